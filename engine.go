@@ -118,12 +118,18 @@ func (p *PreparedDataCache) UncacheStmt(sessId uint32, query string) {
 type Engine struct {
 	Analyzer          *analyzer.Analyzer
 	LS                *sql.LockSubsystem
+	MetadataLocks     *sql.MetadataLockManager
+	GlobalReadLock    *sql.GlobalReadLock
 	ProcessList       sql.ProcessList
 	MemoryManager     *sql.MemoryManager
 	BackgroundThreads *sql.BackgroundThreads
+	EventScheduler    *EventScheduler
 	IsReadOnly        bool
 	IsServerLocked    bool
 	PreparedDataCache *PreparedDataCache
+	// QueryCache keeps the results of read-only queries for the sessions that enable it with query_cache_type.
+	QueryCache        *sql.QueryCache
+	disposeQueryCache sql.DisposeFunc
 	mu                *sync.Mutex
 }
 
@@ -152,16 +158,27 @@ func New(a *analyzer.Analyzer, cfg *Config) *Engine {
 		Fn:   function.NewVersion(cfg.VersionPostfix),
 	})
 	a.Catalog.RegisterFunction(emptyCtx, function.GetLockingFuncs(ls)...)
+	a.Catalog.Instrumentation.LockSubsystem = ls
+	mdl := sql.NewMetadataLockManager()
+	a.Catalog.Instrumentation.MetadataLocks = mdl
+
+	memoryManager := sql.NewMemoryManager(sql.ProcessMemory)
+	queryCache, disposeQueryCache := memoryManager.NewQueryCache()
+	a.Catalog.Instrumentation.QueryCache = queryCache
 
 	return &Engine{
 		Analyzer:          a,
-		MemoryManager:     sql.NewMemoryManager(sql.ProcessMemory),
+		MemoryManager:     memoryManager,
 		ProcessList:       NewProcessList(),
 		LS:                ls,
+		MetadataLocks:     mdl,
+		GlobalReadLock:    a.Catalog.GlobalReadLock,
 		BackgroundThreads: sql.NewBackgroundThreads(),
 		IsReadOnly:        cfg.IsReadOnly,
 		IsServerLocked:    cfg.IsServerLocked,
 		PreparedDataCache: NewPreparedDataCache(),
+		QueryCache:        queryCache,
+		disposeQueryCache: disposeQueryCache,
 		mu:                &sync.Mutex{},
 	}
 }
@@ -220,6 +237,18 @@ func (e *Engine) QueryNodeWithBindings(
 	query string,
 	parsed sql.Node,
 	bindings map[string]sql.Expression,
+) (sql.Schema, sql.RowIter, error) {
+	return e.queryNode(ctx, query, parsed, bindings, nil)
+}
+
+// queryNode executes the statement given like QueryNodeWithBindings does. If analyze is non-nil, it's used to analyze
+// the statement instead of the analyzer's default rules, as is done for the bodies of events.
+func (e *Engine) queryNode(
+	ctx *sql.Context,
+	query string,
+	parsed sql.Node,
+	bindings map[string]sql.Expression,
+	analyze func(ctx *sql.Context) (sql.Node, error),
 ) (sql.Schema, sql.RowIter, error) {
 	var (
 		analyzed sql.Node
@@ -228,12 +257,43 @@ func (e *Engine) QueryNodeWithBindings(
 		err      error
 	)
 
+	// Embedders don't always set the query of the context, which the profile and the optimizer trace record
+	if ctx.Query() == "" {
+		ctx = ctx.WithQuery(query)
+	}
+
+	// Unless the caller already profiles the statement, the profile is finished once the returned iterator is closed
+	ctx, profiler := sql.StartQueryProfile(ctx, "starting")
+	if profiler != nil {
+		defer func() {
+			if err != nil {
+				sql.FinishQueryProfile(ctx, profiler)
+			}
+		}()
+	}
+
+	// SET_VAR hints apply for the rest of the statement, including its parsing, and are undone once its iterator is closed
+	restoreVars := applySetVarHints(ctx, query)
+	if restoreVars != nil {
+		defer func() {
+			if err != nil {
+				restoreVars()
+			}
+		}()
+	}
+
 	if parsed == nil {
+		sql.SetProfileStage(ctx, "parsing")
 		parsed, err = parse.Parse(ctx, query)
 		if err != nil {
 			return nil, nil, err
 		}
 	}
+	switch parsed.(type) {
+	case *plan.ShowProfiles, *plan.ShowProfile:
+		sql.GetQueryProfiler(ctx).Discard()
+	}
+	sql.SetProfileStage(ctx, "analyzing")
 
 	// Before we begin a transaction, we need to know if the database being operated on is not the one
 	// currently selected
@@ -255,11 +315,16 @@ func (e *Engine) QueryNodeWithBindings(
 		return nil, nil, err
 	}
 
-	if p, ok := e.PreparedDataCache.GetCachedStmt(ctx.Session.ID(), query); ok {
-		analyzed, err = e.analyzePreparedQuery(ctx, query, p, bindings)
-	} else {
-		analyzed, err = e.analyzeQuery(ctx, query, parsed, bindings)
+	if analyze == nil {
+		analyze = func(ctx *sql.Context) (sql.Node, error) {
+			if p, ok := e.PreparedDataCache.GetCachedStmt(ctx.Session.ID(), query); ok {
+				return e.analyzePreparedQuery(ctx, query, p, bindings)
+			}
+			return e.analyzeQuery(ctx, query, parsed, bindings)
+		}
 	}
+	generation := e.MetadataLocks.Generation()
+	analyzed, err = analyze(ctx)
 	if err != nil {
 		err2 := clearAutocommitTransaction(ctx)
 		if err2 != nil {
@@ -269,9 +334,94 @@ func (e *Engine) QueryNodeWithBindings(
 		return nil, nil, err
 	}
 
-	useIter2 := false
-	if enableRowIter2 {
-		useIter2 = allNode2(analyzed)
+	// The global read lock taken by FLUSH TABLES WITH READ LOCK keeps statements from writing and committing
+	lockRequests := metadataLockRequests(analyzed)
+	writes := writesOrCommits(parsed, lockRequests)
+	if writes {
+		err = e.GlobalReadLock.BeginWrite(ctx, isCommit(parsed))
+		if err != nil {
+			err2 := clearAutocommitTransaction(ctx)
+			if err2 != nil {
+				err = errors.Wrap(err, "unable to clear autocommit transaction: "+err2.Error())
+			}
+
+			return nil, nil, err
+		}
+	}
+
+	// Metadata locks keep other sessions from changing the definition of the tables of the statement while it runs.
+	// Since they're taken once the statement is analyzed, another session may have changed one of its tables in
+	// between, in which case the statement is analyzed again while it holds its locks.
+	err = e.acquireMetadataLocks(ctx, parsed, lockRequests)
+	for err == nil && e.MetadataLocks.ChangedSince(generation, mdlKeys(lockRequests)...) {
+		generation = e.MetadataLocks.Generation()
+		analyzed, err = analyze(ctx)
+		if err != nil {
+			break
+		}
+		lockRequests = metadataLockRequests(analyzed)
+		if !writes && writesOrCommits(parsed, lockRequests) {
+			err = e.GlobalReadLock.BeginWrite(ctx, isCommit(parsed))
+			if err != nil {
+				break
+			}
+			writes = true
+		}
+		err = e.acquireMetadataLocks(ctx, parsed, lockRequests)
+	}
+	if err != nil {
+		e.releaseMetadataLocks(ctx, parsed)
+		if writes {
+			e.GlobalReadLock.EndWrite(ctx)
+		}
+		err2 := clearAutocommitTransaction(ctx)
+		if err2 != nil {
+			err = errors.Wrap(err, "unable to clear autocommit transaction: "+err2.Error())
+		}
+
+		return nil, nil, err
+	}
+	holdsMetadataLocks := e.MetadataLocks.HasLocks(ctx)
+	holdsLocks := holdsMetadataLocks || writes
+
+	// Cached results of the tables written by the statement are evicted once it's done, while it still holds its locks
+	invalidateCache := e.queryCacheInvalidation(ctx, parsed, analyzed, lockRequests)
+	releaseLocks := func() {
+		if invalidateCache != nil {
+			invalidateCache()
+		}
+		if holdsMetadataLocks {
+			e.releaseMetadataLocks(ctx, parsed)
+		}
+		if writes {
+			e.GlobalReadLock.EndWrite(ctx)
+		}
+	}
+	holdsLocks = holdsLocks || invalidateCache != nil
+
+	// Read-only queries are answered from the query cache when their result is kept there, and kept there otherwise
+	useIter2 := enableRowIter2 && allNode2(analyzed)
+	var cacheRequest *queryCacheRequest
+	if !useIter2 {
+		cacheRequest = e.queryCacheRequest(ctx, query, parsed, analyzed, bindings)
+	}
+	if cacheRequest != nil {
+		if rows, ok := e.QueryCache.Get(cacheRequest.key); ok {
+			analyzed = withQueryCacheResult(analyzed, rows)
+			cacheRequest = nil
+		}
+	}
+
+	sql.SetProfileStage(ctx, "executing")
+
+	// Read-only SELECT statements run under a deadline when they have a maximum execution time
+	ctx, cancelTimeout := withMaxExecutionTime(ctx, query, parsed)
+	if cancelTimeout != nil {
+		defer func() {
+			if err != nil {
+				cancelTimeout()
+			}
+		}()
 	}
 
 	if useIter2 {
@@ -281,6 +431,9 @@ func (e *Engine) QueryNodeWithBindings(
 		iter, err = analyzed.RowIter(ctx, nil)
 	}
 	if err != nil {
+		if holdsLocks {
+			releaseLocks()
+		}
 		err2 := clearAutocommitTransaction(ctx)
 		if err2 != nil {
 			err = errors.Wrap(err, "unable to clear autocommit transaction: "+err2.Error())
@@ -295,6 +448,21 @@ func (e *Engine) QueryNodeWithBindings(
 			iter2:   iter2,
 			isNode2: useIter2,
 		}
+	} else {
+		iter = sql.NewProfilingIter(sql.GetQueryProfiler(ctx), iter, profiler != nil)
+	}
+
+	if cacheRequest != nil {
+		iter = newQueryCacheIter(iter, e.QueryCache, cacheRequest)
+	}
+	if cancelTimeout != nil {
+		iter = newExecutionTimeIter(ctx, cancelTimeout, iter)
+	}
+	if holdsLocks {
+		iter = newStatementLockIter(iter, releaseLocks)
+	}
+	if restoreVars != nil {
+		iter = newSetVarIter(iter, restoreVars)
 	}
 
 	return analyzed.Schema(), iter, nil
@@ -326,11 +494,23 @@ func clearAutocommitTransaction(ctx *sql.Context) error {
 	return nil
 }
 
-// CloseSession deletes session specific prepared statement data
+// CloseSession deletes session specific prepared statement data, releases the metadata locks and global read lock
+// of the session, and drops the session's temporary tables in any database that manages them itself.
 func (e *Engine) CloseSession(ctx *sql.Context) {
 	e.mu.Lock()
 	defer e.mu.Unlock()
 	e.PreparedDataCache.DeleteSessionData(ctx.Session.ID())
+	e.MetadataLocks.ReleaseAll(ctx)
+	e.GlobalReadLock.Unlock(ctx.Session.ID())
+	e.QueryCache.EndTransaction(ctx.Session.ID())
+
+	for _, db := range e.Analyzer.Catalog.Provider.AllDatabases(ctx) {
+		if dropper, ok := db.(sql.TemporaryTableDropper); ok {
+			if err := dropper.DropAllTemporaryTables(ctx); err != nil {
+				ctx.GetLogger().Warnf("unable to drop temporary tables of database %s: %s", db.Name(), err.Error())
+			}
+		}
+	}
 }
 
 // Count number of BindVars in given tree
@@ -604,6 +784,7 @@ func (e *Engine) Close() error {
 	for _, p := range e.ProcessList.Processes() {
 		e.ProcessList.Kill(p.Connection)
 	}
+	e.disposeQueryCache()
 	return e.BackgroundThreads.Shutdown()
 }
 
@@ -24,6 +24,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/dolthub/vitess/go/mysql"
 	"github.com/pmezard/go-difflib/difflib"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -88,6 +89,10 @@ func TestNoDatabaseSelected(t *testing.T) {
 	enginetest.TestNoDatabaseSelected(t, enginetest.NewDefaultMemoryHarness())
 }
 
+func TestConcurrentCallAndDropTable(t *testing.T) {
+	enginetest.TestConcurrentCallAndDropTable(t, enginetest.NewDefaultMemoryHarness())
+}
+
 func TestTracing(t *testing.T) {
 	enginetest.TestTracing(t, enginetest.NewDefaultMemoryHarness())
 }
@@ -172,6 +177,36 @@ func TestRootSpanFinish(t *testing.T) {
 	require.True(t, fakeSpan.finished)
 }
 
+func TestMaxExecutionTime(t *testing.T) {
+	harness := enginetest.NewMemoryHarness("parallel", 2, testNumPartitions, false, nil)
+	harness.Setup(setup.MydbData, setup.MytableData)
+	e, err := harness.NewEngine(t)
+	require.NoError(t, err)
+
+	p := sqle.NewProcessList()
+	sqlCtx := harness.NewContext()
+	sql.WithProcessList(p)(sqlCtx)
+	ctx, fakeSpan := newMockSpan(sqlCtx)
+	sql.WithRootSpan(fakeSpan)(sqlCtx)
+	sqlCtx = sqlCtx.WithContext(ctx)
+
+	query := "SELECT /*+ MAX_EXECUTION_TIME(50) */ i, SLEEP(1) FROM mytable"
+	sqlCtx, err = p.AddProcess(sqlCtx, query)
+	require.NoError(t, err)
+
+	start := time.Now()
+	sch, iter, err := e.Query(sqlCtx, query)
+	require.NoError(t, err)
+	_, err = sql.RowIterToRows(sqlCtx, sch, iter)
+	require.Error(t, err)
+	require.True(t, sql.ErrQueryTimeout.Is(err), "unexpected error %v", err)
+	require.Less(t, time.Since(start), time.Second)
+
+	require.Equal(t, mysql.ERQueryTimeout, sql.CastSQLError(err).Number())
+	require.True(t, fakeSpan.finished)
+	require.Len(t, p.Processes(), 0)
+}
+
 type lockableTable struct {
 	sql.Table
 	readLocks  int
@@ -250,8 +285,8 @@ a (4/5 partitions)
  └─ a-2 (9/? rows)
 
 b (2/6 partitions)
-`, "SELECT foo"},
-		{int64(1), "foo", addr, "foo", "Query", int64(0), "\nfoo (1/2 partitions)\n", "SELECT bar"},
+`, "SELECT foo", uint64(0)},
+		{int64(1), "foo", addr, "foo", "Query", int64(0), "\nfoo (1/2 partitions)\n", "SELECT bar", uint64(0)},
 	}
 
 	require.ElementsMatch(expected, rows)
@@ -343,8 +378,8 @@ func TestLockTables(t *testing.T) {
 	t1 := newLockableTable(memory.NewTable("foo", sql.PrimaryKeySchema{}, nil))
 	t2 := newLockableTable(memory.NewTable("bar", sql.PrimaryKeySchema{}, nil))
 	node := plan.NewLockTables([]*plan.TableLock{
-		{plan.NewResolvedTable(t1, nil, nil), true},
-		{plan.NewResolvedTable(t2, nil, nil), false},
+		{Table: plan.NewResolvedTable(t1, nil, nil), Write: true},
+		{Table: plan.NewResolvedTable(t2, nil, nil)},
 	})
 	node.Catalog = analyzer.NewCatalog(sql.NewDatabaseProvider())
 
@@ -371,8 +406,8 @@ func TestUnlockTables(t *testing.T) {
 	catalog := analyzer.NewCatalog(sql.NewDatabaseProvider(db))
 
 	ctx := sql.NewContext(context.Background()).WithCurrentDB("db").WithCurrentDB("db")
-	catalog.LockTable(ctx, "foo")
-	catalog.LockTable(ctx, "bar")
+	catalog.LockTable(ctx, "db", "foo")
+	catalog.LockTable(ctx, "db", "bar")
 
 	node := plan.NewUnlockTables()
 	node.Catalog = catalog
@@ -683,6 +718,116 @@ func TestTableFunctions(t *testing.T) {
 	}
 }
 
+func TestViewRoutineUsage(t *testing.T) {
+	harness := enginetest.NewMemoryHarness("", 1, testNumPartitions, true, nil)
+	harness.Setup(setup.MydbData)
+
+	databaseProvider := harness.NewDatabaseProvider()
+	testDatabaseProvider := &functionTestProvider{
+		TestProvider: NewTestProvider(&databaseProvider),
+		functions: map[string]sql.Function{
+			"shout": sql.Function1{Name: "shout", Fn: function.NewUpper},
+		},
+	}
+
+	engine := enginetest.NewEngineWithProvider(t, harness, testDatabaseProvider)
+	engine, err := enginetest.RunSetupScripts(harness.NewContext(), engine, setup.MydbData, true)
+	require.NoError(t, err)
+
+	enginetest.TestScriptWithEngine(t, engine, harness, queries.ScriptTest{
+		Name: "information_schema.view_routine_usage lists the functions provided by the integrator",
+		SetUpScript: []string{
+			"CREATE TABLE t (s varchar(20) primary key)",
+			"CREATE VIEW v1 AS SELECT SHOUT(s), lower(s) FROM t",
+			"CREATE VIEW v2 AS SELECT * FROM t WHERE s IN (SELECT shout(s) FROM t)",
+			"CREATE VIEW v3 AS SELECT upper(s) FROM t",
+		},
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "SELECT * FROM information_schema.view_routine_usage ORDER BY table_name",
+				Expected: []sql.Row{
+					{"def", "mydb", "v1", "def", "mydb", "shout"},
+					{"def", "mydb", "v2", "def", "mydb", "shout"},
+				},
+			},
+		},
+	})
+}
+
+// functionTestProvider is a TestProvider that also provides the functions given.
+type functionTestProvider struct {
+	*TestProvider
+	functions map[string]sql.Function
+}
+
+var _ sql.FunctionProvider = (*functionTestProvider)(nil)
+
+func (p *functionTestProvider) Function(ctx *sql.Context, name string) (sql.Function, error) {
+	if fn, ok := p.functions[strings.ToLower(name)]; ok {
+		return fn, nil
+	}
+	return p.TestProvider.Function(ctx, name)
+}
+
+func TestStorageUsage(t *testing.T) {
+	harness := enginetest.NewMemoryHarness("", 1, testNumPartitions, true, nil)
+	harness.Setup(setup.MydbData)
+
+	databaseProvider := harness.NewDatabaseProvider()
+	testDatabaseProvider := &storageUsageTestProvider{
+		TestProvider: NewTestProvider(&databaseProvider),
+		tablespaces: []sql.Tablespace{
+			{Name: "innodb_system", Engine: "InnoDB", Type: "General", ExtentSize: 1048576, AutoextendSize: 0},
+			{Name: "ts1", Engine: "InnoDB", ExtentSize: 1048576, AutoextendSize: 4194304, MaximumSize: 1073741824, Comment: "archive"},
+		},
+		files: []sql.DataFile{
+			{Id: 0, Name: "./ibdata1", Type: "TABLESPACE", TablespaceName: "innodb_system", Engine: "InnoDB", ExtentSize: 1048576, TotalExtents: 12, FreeExtents: 3, InitialSize: 12582912, AutoextendSize: 67108864, DataFree: 3145728, Status: "NORMAL"},
+			{Id: 1, Name: "./ts1.ibd", Type: "TABLESPACE", TablespaceName: "ts1", Engine: "InnoDB", ExtentSize: 1048576, TotalExtents: 1, InitialSize: 1048576, MaximumSize: 1073741824, AutoextendSize: 4194304, Status: "NORMAL"},
+		},
+	}
+
+	engine := enginetest.NewEngineWithProvider(t, harness, testDatabaseProvider)
+	engine, err := enginetest.RunSetupScripts(harness.NewContext(), engine, setup.MydbData, true)
+	require.NoError(t, err)
+
+	enginetest.TestScriptWithEngine(t, engine, harness, queries.ScriptTest{
+		Name: "information_schema.files and information_schema.tablespaces show the storage reported by the provider",
+		Assertions: []queries.ScriptTestAssertion{
+			{
+				Query: "SELECT * FROM information_schema.tablespaces ORDER BY tablespace_name",
+				Expected: []sql.Row{
+					{"innodb_system", "InnoDB", "General", nil, uint64(1048576), uint64(0), nil, nil, nil},
+					{"ts1", "InnoDB", nil, nil, uint64(1048576), uint64(4194304), uint64(1073741824), nil, "archive"},
+				},
+			},
+			{
+				Query: "SELECT file_id, file_name, file_type, tablespace_name, engine, free_extents, total_extents, extent_size, initial_size, maximum_size, autoextend_size, data_free, status FROM information_schema.files ORDER BY file_id",
+				Expected: []sql.Row{
+					{int64(0), "./ibdata1", "TABLESPACE", "innodb_system", "InnoDB", int64(3), int64(12), int64(1048576), int64(12582912), nil, int64(67108864), int64(3145728), "NORMAL"},
+					{int64(1), "./ts1.ibd", "TABLESPACE", "ts1", "InnoDB", int64(0), int64(1), int64(1048576), int64(1048576), int64(1073741824), int64(4194304), int64(0), "NORMAL"},
+				},
+			},
+		},
+	})
+}
+
+// storageUsageTestProvider is a TestProvider that reports the tablespaces and files given.
+type storageUsageTestProvider struct {
+	*TestProvider
+	tablespaces []sql.Tablespace
+	files       []sql.DataFile
+}
+
+var _ sql.StorageUsageProvider = (*storageUsageTestProvider)(nil)
+
+func (p *storageUsageTestProvider) Tablespaces(_ *sql.Context) ([]sql.Tablespace, error) {
+	return p.tablespaces, nil
+}
+
+func (p *storageUsageTestProvider) DataFiles(_ *sql.Context) ([]sql.DataFile, error) {
+	return p.files, nil
+}
+
 func TestExternalProcedures(t *testing.T) {
 	harness := enginetest.NewDefaultMemoryHarness()
 	harness.Setup(setup.MydbData)
@@ -254,7 +254,7 @@ func TestInfoSchema(t *testing.T, h Harness) {
 		ctx, err := p.AddProcess(ctx, "SELECT foo")
 		require.NoError(t, err)
 
-		TestQueryWithContext(t, ctx, e, h, "SELECT * FROM information_schema.processlist", []sql.Row{{uint64(1), "root", "localhost", "NULL", "Query", 0, "processlist(processlist (0/? partitions))", "SELECT foo"}}, nil, nil)
+		TestQueryWithContext(t, ctx, e, h, "SELECT * FROM information_schema.processlist", []sql.Row{{uint64(1), "root", "localhost", "NULL", "Query", 0, "processlist(processlist (0/? partitions))", "SELECT foo", uint64(0)}}, nil, nil)
 	})
 
 	for _, tt := range queries.SkippedInfoSchemaQueries {
@@ -1323,6 +1323,56 @@ func TestUserPrivileges(t *testing.T, harness ClientHarness) {
 	}
 }
 
+// TestSessionRoles tests the roles that are active in a session. Each user keeps the same session for every assertion
+// in a script.
+func TestSessionRoles(t *testing.T, harness ClientHarness) {
+	harness.Setup(setup.MydbData, setup.MytableData)
+	for _, script := range queries.SessionRoleTests {
+		t.Run(script.Name, func(t *testing.T) {
+			engine := mustNewEngine(t, harness)
+			defer engine.Close()
+
+			engine.Analyzer.Catalog.MySQLDb.AddRootAccount()
+			engine.Analyzer.Catalog.MySQLDb.SetPersister(&mysql_db.NoopPersister{})
+			contexts := make(map[sql.Client]*sql.Context)
+			getContext := func(user string, host string) *sql.Context {
+				if user == "" {
+					user = "root"
+				}
+				if host == "" {
+					host = "localhost"
+				}
+				client := sql.Client{User: user, Address: host}
+				if ctx, ok := contexts[client]; ok {
+					return ctx
+				}
+				ctx := NewContextWithClient(harness, client)
+				contexts[client] = ctx
+				return ctx
+			}
+
+			for _, statement := range script.SetUpScript {
+				RunQueryWithContext(t, engine, harness, getContext("", ""), statement)
+			}
+			for _, assertion := range script.Assertions {
+				ctx := getContext(assertion.User, assertion.Host)
+				if assertion.ExpectedErr != nil {
+					t.Run(assertion.Query, func(t *testing.T) {
+						AssertErrWithCtx(t, engine, harness, ctx, assertion.Query, assertion.ExpectedErr)
+					})
+				} else if assertion.ExpectedErrStr != "" {
+					t.Run(assertion.Query, func(t *testing.T) {
+						AssertErrWithCtx(t, engine, harness, ctx, assertion.Query, nil, assertion.ExpectedErrStr)
+					})
+				} else {
+					t.Run(assertion.Query, func(t *testing.T) {
+						TestQueryWithContext(t, ctx, engine, harness, assertion.Query, assertion.Expected, nil, nil)
+					})
+				}
+			}
+		})
+	}
+}
 func TestUserAuthentication(t *testing.T, h Harness) {
 	harness, ok := h.(ClientHarness)
 	if !ok {
@@ -1412,6 +1462,27 @@ func TestComplexIndexQueries(t *testing.T, harness Harness) {
 	}
 }
 
+func TestEvents(t *testing.T, harness Harness) {
+	harness.Setup(setup.MydbData)
+	for _, script := range queries.EventTests {
+		TestScript(t, harness, script)
+	}
+}
+
+func TestTemporaryTables(t *testing.T, harness Harness) {
+	harness.Setup(setup.MydbData)
+	for _, script := range queries.TemporaryTableTests {
+		TestScript(t, harness, script)
+	}
+}
+
+func TestPartitions(t *testing.T, harness Harness) {
+	harness.Setup(setup.MydbData)
+	for _, script := range queries.PartitionTests {
+		TestScript(t, harness, script)
+	}
+}
+
 func TestTriggers(t *testing.T, harness Harness) {
 	harness.Setup(setup.MydbData, setup.FooData)
 	for _, script := range queries.TriggerTests {
@@ -4796,6 +4867,33 @@ func TestConcurrentTransactions(t *testing.T, harness Harness) {
 	require.Len(rows, 1)
 }
 
+// TestConcurrentCallAndDropTable tests that a table written by a procedure can't be dropped by another session while
+// a call of the procedure runs.
+func TestConcurrentCallAndDropTable(t *testing.T, harness Harness) {
+	require := require.New(t)
+	harness.Setup(setup.MydbData)
+	e := mustNewEngine(t, harness)
+	defer e.Close()
+
+	RunQuery(t, e, harness, "CREATE TABLE a (x int primary key)")
+	RunQuery(t, e, harness, "CREATE PROCEDURE p() BEGIN INSERT INTO a VALUES (1); END")
+
+	// The sessions of the harness may share their ID, which metadata locks are taken for
+	clientSessionA := NewContext(harness)
+	clientSessionB := newContextSetup(sql.NewContext(context.Background(), sql.WithSession(
+		sql.NewBaseSessionWithClientServer("address", sql.Client{Address: "localhost", User: "root"}, clientSessionA.ID()+1))))
+	RunQueryWithContext(t, e, harness, clientSessionB, "SET lock_wait_timeout = 1")
+
+	// The call holds its locks until its iterator is closed
+	sch, iter, err := e.Query(clientSessionA, "CALL p()")
+	require.NoError(err)
+	AssertErrWithCtx(t, e, harness, clientSessionB, "DROP TABLE a", sql.ErrLockWaitTimeout)
+
+	_, err = sql.RowIterToRows(clientSessionA, sch, iter)
+	require.NoError(err)
+	RunQueryWithContext(t, e, harness, clientSessionB, "DROP TABLE a")
+}
+
 func TestTransactionScripts(t *testing.T, harness Harness) {
 	for _, script := range queries.TransactionTests {
 		TestTransactionScript(t, harness, script)
@@ -533,6 +533,54 @@ SELECT * FROM xy WHERE (
 			},
 		},
 	},
+	{
+		name: "optimizer hints",
+		setup: []string{
+			"CREATE table xy (x int primary key, y int, index y_idx(y));",
+			"CREATE table uv (u int primary key, v int);",
+			"insert into xy values (1,0), (2,1), (0,2), (3,3);",
+			"insert into uv values (0,1), (1,1), (2,2), (3,2);",
+			"update information_schema.statistics set cardinality = 100 where table_name in ('xy', 'uv');",
+		},
+		tests: []JoinPlanTest{
+			{
+				q:     "select /*+ HASH_JOIN(uv) */ x, u from xy join uv on x = u order by 1",
+				types: []plan.JoinType{plan.JoinTypeHash},
+				exp:   []sql.Row{{0, 0}, {1, 1}, {2, 2}, {3, 3}},
+			},
+			{
+				q:     "select /*+ MERGE_JOIN(uv) */ x, u from xy join uv on x = u order by 1",
+				types: []plan.JoinType{plan.JoinTypeMerge},
+				exp:   []sql.Row{{0, 0}, {1, 1}, {2, 2}, {3, 3}},
+			},
+			{
+				q:     "select /*+ NO_MERGE_JOIN(uv) NO_HASH_JOIN() */ x, u from xy join uv on x = u order by 1",
+				types: []plan.JoinType{plan.JoinTypeLookup},
+				exp:   []sql.Row{{0, 0}, {1, 1}, {2, 2}, {3, 3}},
+			},
+			{
+				q:     "select /*+ NO_JOIN_INDEX(xy) NO_JOIN_INDEX(uv) NO_MERGE_JOIN() */ x, u from xy join uv on x = u order by 1",
+				types: []plan.JoinType{plan.JoinTypeHash},
+				exp:   []sql.Row{{0, 0}, {1, 1}, {2, 2}, {3, 3}},
+			},
+			{
+				q:     "select /*+ JOIN_PREFIX(c) */ 1 from xy a join xy b on a.x+3 = b.x join uv c on a.x+3 = c.u and a.x+3 = b.x",
+				order: []string{"c", "a", "b"},
+			},
+			{
+				q:     "select /*+ JOIN_SUFFIX(a) */ 1 from xy a join xy b on a.x+3 = b.x join uv c on a.x+3 = c.u and a.x+3 = b.x",
+				order: []string{"b", "c", "a"},
+			},
+			{
+				q:     "select /*+ JOIN_FIXED_ORDER() */ 1 from xy c join xy b on c.x+3 = b.x join uv a on c.x+3 = a.u and c.x+3 = b.x",
+				order: []string{"c", "b", "a"},
+			},
+			{
+				q:     "select /*+ JOIN_ORDER(b, a) JOIN_PREFIX(a) */ 1 from xy a join xy b on a.x+3 = b.x",
+				order: []string{"b", "a"},
+			},
+		},
+	},
 }
 
 func TestJoinPlanning(t *testing.T, harness Harness) {
@@ -15,10 +15,13 @@
 package enginetest_test
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"testing"
 
+	"github.com/stretchr/testify/require"
+
 	"github.com/dolthub/go-mysql-server/enginetest"
 	"github.com/dolthub/go-mysql-server/enginetest/queries"
 	"github.com/dolthub/go-mysql-server/enginetest/scriptgen/setup"
@@ -597,6 +600,10 @@ func TestUserPrivileges(t *testing.T) {
 	enginetest.TestUserPrivileges(t, enginetest.NewMemoryHarness("default", 1, testNumPartitions, true, mergableIndexDriver))
 }
 
+func TestSessionRoles(t *testing.T) {
+	enginetest.TestSessionRoles(t, enginetest.NewMemoryHarness("default", 1, testNumPartitions, true, mergableIndexDriver))
+}
+
 func TestUserAuthentication(t *testing.T) {
 	enginetest.TestUserAuthentication(t, enginetest.NewMemoryHarness("default", 1, testNumPartitions, true, mergableIndexDriver))
 }
@@ -610,6 +617,56 @@ func TestComplexIndexQueries(t *testing.T) {
 	enginetest.TestComplexIndexQueries(t, harness)
 }
 
+func TestEvents(t *testing.T) {
+	enginetest.TestEvents(t, enginetest.NewDefaultMemoryHarness())
+}
+
+func TestPartitions(t *testing.T) {
+	enginetest.TestPartitions(t, enginetest.NewDefaultMemoryHarness())
+}
+
+func TestTemporaryTables(t *testing.T) {
+	enginetest.TestTemporaryTables(t, enginetest.NewDefaultMemoryHarness())
+}
+
+// TestTemporaryTableSessions checks that the temporary tables of the memory database are only visible to the session
+// that created them, and that they are dropped when that session is closed.
+func TestTemporaryTableSessions(t *testing.T) {
+	harness := enginetest.NewDefaultMemoryHarness()
+	harness.Setup(setup.MydbData)
+	e, err := harness.NewEngine(t)
+	require.NoError(t, err)
+	defer e.Close()
+
+	newSession := func(id uint32) *sql.Context {
+		session := sql.NewBaseSessionWithClientServer("address", sql.Client{Address: "localhost", User: "root"}, id)
+		ctx := sql.NewContext(context.Background(), sql.WithSession(session))
+		ctx.SetCurrentDatabase("mydb")
+		return ctx
+	}
+	sessionA, sessionB := newSession(10), newSession(11)
+
+	enginetest.RunQueryWithContext(t, e, harness, sessionA, "create table t (a int primary key)")
+	enginetest.RunQueryWithContext(t, e, harness, sessionA, "insert into t values (1)")
+	enginetest.RunQueryWithContext(t, e, harness, sessionA, "create temporary table t (a int primary key)")
+	enginetest.RunQueryWithContext(t, e, harness, sessionA, "insert into t values (2)")
+	enginetest.RunQueryWithContext(t, e, harness, sessionA, "create temporary table tmp (s varchar(10))")
+	enginetest.RunQueryWithContext(t, e, harness, sessionB, "create temporary table tmp (i int)")
+
+	enginetest.TestQueryWithContext(t, sessionA, e, harness, "select * from t", []sql.Row{{2}}, nil, nil)
+	enginetest.TestQueryWithContext(t, sessionB, e, harness, "select * from t", []sql.Row{{1}}, nil, nil)
+	enginetest.RunQueryWithContext(t, e, harness, sessionA, "insert into tmp values ('a')")
+	enginetest.RunQueryWithContext(t, e, harness, sessionB, "insert into tmp values (1)")
+	enginetest.TestQueryWithContext(t, sessionA, e, harness, "select * from tmp", []sql.Row{{"a"}}, nil, nil)
+	enginetest.TestQueryWithContext(t, sessionB, e, harness, "select * from tmp", []sql.Row{{1}}, nil, nil)
+
+	e.CloseSession(sessionA)
+	sessionA = newSession(10)
+	enginetest.TestQueryWithContext(t, sessionA, e, harness, "select * from t", []sql.Row{{1}}, nil, nil)
+	enginetest.AssertErrWithCtx(t, e, harness, sessionA, "select * from tmp", sql.ErrTableNotFound)
+	enginetest.TestQueryWithContext(t, sessionB, e, harness, "select * from tmp", []sql.Row{{1}}, nil, nil)
+}
+
 func TestTriggers(t *testing.T) {
 	enginetest.TestTriggers(t, enginetest.NewDefaultMemoryHarness())
 }
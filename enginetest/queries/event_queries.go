@@ -0,0 +1,231 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queries
+
+import (
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+var EventTests = []ScriptTest{
+	{
+		Name: "create a one-time event",
+		SetUpScript: []string{
+			"create table t (i int primary key)",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "create event e1 on schedule at '2037-01-02 03:04:05' do insert into t values (1)",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "select event_schema, event_name, event_type, execute_at, interval_value, interval_field, starts, ends, status, on_completion, event_definition from information_schema.events",
+				Expected: []sql.Row{{"mydb", "e1", "ONE TIME", time.Date(2037, 1, 2, 3, 4, 5, 0, time.UTC), nil, nil, nil, nil, "ENABLED", "NOT PRESERVE", "insert into t values (1)"}},
+			},
+			{
+				Query:       "create event e1 on schedule at '2037-01-02 03:04:05' do insert into t values (2)",
+				ExpectedErr: sql.ErrEventAlreadyExists,
+			},
+			{
+				Query:           "create event if not exists e1 on schedule at '2037-01-02 03:04:05' do insert into t values (2)",
+				Expected:        []sql.Row{{types.NewOkResult(0)}},
+				ExpectedWarning: 1537,
+			},
+			{
+				Query:    "select event_definition from information_schema.events where event_name = 'e1'",
+				Expected: []sql.Row{{"insert into t values (1)"}},
+			},
+		},
+	},
+	{
+		Name: "create a recurring event",
+		SetUpScript: []string{
+			"create table t (i int primary key)",
+			"create event e2 on schedule every '1:30' hour_minute starts '2037-01-01 00:00:00' ends '2037-02-01 00:00:00' on completion preserve disable comment 'hello' do begin insert into t values (1); insert into t values (2); end",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "select event_name, event_type, execute_at, interval_value, interval_field, starts, ends, status, on_completion, event_comment from information_schema.events",
+				Expected: []sql.Row{{"e2", "RECURRING", nil, "1:30", "HOUR_MINUTE", time.Date(2037, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2037, 2, 1, 0, 0, 0, 0, time.UTC), "DISABLED", "PRESERVE", "hello"}},
+			},
+			{
+				Query:    "select event_definition from information_schema.events",
+				Expected: []sql.Row{{"begin insert into t values (1); insert into t values (2); end"}},
+			},
+		},
+	},
+	{
+		Name: "show events",
+		SetUpScript: []string{
+			"create event e1 on schedule at '2037-01-02 03:04:05' do select 1",
+			"create event e2 on schedule every 1 day starts '2037-01-01 00:00:00' do select 2",
+			"create event other on schedule every 2 week starts '2037-01-01 00:00:00' do select 3",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query: "show events",
+				Expected: []sql.Row{
+					{"mydb", "e1", "root@localhost", "SYSTEM", "ONE TIME", time.Unix(0, 0).UTC(), nil, nil, nil, nil, "ENABLED", int64(0), "utf8mb4", "utf8mb4_0900_bin", "utf8mb4_0900_bin"},
+					{"mydb", "e2", "root@localhost", "SYSTEM", "RECURRING", nil, "1", "DAY", time.Unix(0, 0).UTC(), nil, "ENABLED", int64(0), "utf8mb4", "utf8mb4_0900_bin", "utf8mb4_0900_bin"},
+					{"mydb", "other", "root@localhost", "SYSTEM", "RECURRING", nil, "2", "WEEK", time.Unix(0, 0).UTC(), nil, "ENABLED", int64(0), "utf8mb4", "utf8mb4_0900_bin", "utf8mb4_0900_bin"},
+				},
+			},
+			{
+				Query: "show events like 'e%'",
+				Expected: []sql.Row{
+					{"mydb", "e1", "root@localhost", "SYSTEM", "ONE TIME", time.Unix(0, 0).UTC(), nil, nil, nil, nil, "ENABLED", int64(0), "utf8mb4", "utf8mb4_0900_bin", "utf8mb4_0900_bin"},
+					{"mydb", "e2", "root@localhost", "SYSTEM", "RECURRING", nil, "1", "DAY", time.Unix(0, 0).UTC(), nil, "ENABLED", int64(0), "utf8mb4", "utf8mb4_0900_bin", "utf8mb4_0900_bin"},
+				},
+			},
+			{
+				Query: "show events from mydb where `Type` = 'RECURRING' and Name <> 'e2'",
+				Expected: []sql.Row{
+					{"mydb", "other", "root@localhost", "SYSTEM", "RECURRING", nil, "2", "WEEK", time.Unix(0, 0).UTC(), nil, "ENABLED", int64(0), "utf8mb4", "utf8mb4_0900_bin", "utf8mb4_0900_bin"},
+				},
+			},
+			{
+				Query: "show create event e2",
+				Expected: []sql.Row{
+					{"e2", "STRICT_TRANS_TABLES,NO_ENGINE_SUBSTITUTION,ONLY_FULL_GROUP_BY", "SYSTEM", "CREATE DEFINER = `root`@`localhost` EVENT `e2` ON SCHEDULE EVERY 1 DAY STARTS '2037-01-01 00:00:00' ON COMPLETION NOT PRESERVE ENABLE DO select 2", "utf8mb4", "utf8mb4_0900_bin", "utf8mb4_0900_bin"},
+				},
+			},
+			{
+				Query:       "show create event nope",
+				ExpectedErr: sql.ErrEventDoesNotExist,
+			},
+		},
+	},
+	{
+		Name: "events with schedules in the past",
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:           "create event past1 on schedule at '2000-01-01 00:00:00' do select 1",
+				Expected:        []sql.Row{{types.NewOkResult(0)}},
+				ExpectedWarning: 1588,
+			},
+			{
+				Query:           "create event past2 on schedule at '2000-01-01 00:00:00' on completion preserve do select 1",
+				Expected:        []sql.Row{{types.NewOkResult(0)}},
+				ExpectedWarning: 1544,
+			},
+			{
+				Query:    "select event_name, status from information_schema.events",
+				Expected: []sql.Row{{"past2", "DISABLED"}},
+			},
+			{
+				Query:           "create event past3 on schedule every 1 hour starts '1999-01-01 00:00:00' ends '2000-01-01 00:00:00' on completion preserve do select 1",
+				Expected:        []sql.Row{{types.NewOkResult(0)}},
+				ExpectedWarning: 1544,
+			},
+			{
+				Query:    "select event_name, status from information_schema.events order by 1",
+				Expected: []sql.Row{{"past2", "DISABLED"}, {"past3", "DISABLED"}},
+			},
+		},
+	},
+	{
+		Name: "invalid event schedules",
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:       "create event bad on schedule every 1 hour starts '2037-01-02 00:00:00' ends '2037-01-01 00:00:00' do select 1",
+				ExpectedErr: sql.ErrEventEndsBeforeStarts,
+			},
+			{
+				Query:       "create event bad on schedule every 0 hour do select 1",
+				ExpectedErr: sql.ErrEventIntervalNotPositive,
+			},
+			{
+				Query:       "create event bad on schedule every -5 minute do select 1",
+				ExpectedErr: sql.ErrEventIntervalNotPositive,
+			},
+			{
+				Query:       "create event bad on schedule at '2037-01-01 00:00:00'",
+				ExpectedErr: sql.ErrSyntaxError,
+			},
+			{
+				Query:    "select count(*) from information_schema.events",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+	{
+		Name: "alter event",
+		SetUpScript: []string{
+			"create event e1 on schedule at '2037-01-02 03:04:05' do select 1",
+			"create event e2 on schedule at '2037-01-02 03:04:05' do select 2",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "alter event e1 on schedule every 3 minute starts '2037-01-01 00:00:00' on completion preserve disable comment 'altered' do select 10",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "select event_name, event_type, interval_value, interval_field, starts, status, on_completion, event_comment, event_definition from information_schema.events where event_name = 'e1'",
+				Expected: []sql.Row{{"e1", "RECURRING", "3", "MINUTE", time.Date(2037, 1, 1, 0, 0, 0, 0, time.UTC), "DISABLED", "PRESERVE", "altered", "select 10"}},
+			},
+			{
+				Query:    "alter event e1 rename to e3 enable",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "select event_name, status, event_definition from information_schema.events order by 1",
+				Expected: []sql.Row{{"e2", "ENABLED", "select 2"}, {"e3", "ENABLED", "select 10"}},
+			},
+			{
+				Query:       "alter event e3 rename to e2",
+				ExpectedErr: sql.ErrEventAlreadyExists,
+			},
+			{
+				Query:       "alter event e1 enable",
+				ExpectedErr: sql.ErrEventDoesNotExist,
+			},
+			{
+				Query:       "alter event e2 on schedule at '2000-01-01 00:00:00'",
+				ExpectedErr: sql.ErrEventCannotAlterInThePast,
+			},
+			{
+				Query:       "alter event e2",
+				ExpectedErr: sql.ErrSyntaxError,
+			},
+		},
+	},
+	{
+		Name: "drop event",
+		SetUpScript: []string{
+			"create event e1 on schedule at '2037-01-02 03:04:05' do select 1",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "drop event e1",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:       "drop event e1",
+				ExpectedErr: sql.ErrEventDoesNotExist,
+			},
+			{
+				Query:           "drop event if exists e1",
+				Expected:        []sql.Row{{types.NewOkResult(0)}},
+				ExpectedWarning: 1305,
+			},
+			{
+				Query:    "select count(*) from information_schema.events",
+				Expected: []sql.Row{{0}},
+			},
+		},
+	},
+}
@@ -410,8 +410,8 @@ var InfoSchemaQueries = []QueryTest{
 		Expected: []sql.Row{},
 	},
 	{
-		Query:    "SELECT * FROM information_schema.partitions",
-		Expected: []sql.Row{},
+		Query:    "SELECT table_name, partition_name, partition_method, table_rows, avg_row_length, data_length FROM information_schema.partitions WHERE table_schema = 'mydb' AND table_name = 'mytable'",
+		Expected: []sql.Row{{"mytable", nil, nil, uint64(3), uint64(88), uint64(264)}},
 	},
 	{
 		Query:    `SELECT * FROM information_schema.plugins`,
@@ -447,7 +447,7 @@ var InfoSchemaQueries = []QueryTest{
 	},
 	{
 		Query:    `SELECT * FROM information_schema.view_table_usage`,
-		Expected: []sql.Row{},
+		Expected: []sql.Row{{"def", "mydb", "myview", "def", "mydb", "mytable"}},
 	},
 	{
 		Query:    `SELECT * from information_schema.innodb_buffer_page`,
@@ -616,7 +616,7 @@ FROM INFORMATION_SCHEMA.STATISTICS WHERE TABLE_SCHEMA = 'mydb' AND INDEX_NAME='P
 	},
 	{
 		Query:    "SELECT * FROM information_schema.schemata_extensions",
-		Expected: []sql.Row{{"def", "information_schema", ""}, {"def", "foo", ""}, {"def", "mydb", ""}},
+		Expected: []sql.Row{{"def", "information_schema", ""}, {"def", "foo", ""}, {"def", "mydb", ""}, {"def", "performance_schema", ""}},
 	},
 	{
 		Query:    `SELECT * FROM information_schema.columns_extensions where table_name = 'mytable'`,
@@ -1451,6 +1451,175 @@ from information_schema.routines where routine_schema = 'mydb' and routine_type
 			},
 		},
 	},
+	{
+		Name: "information_schema.optimizer_trace shows the traces of the session's statements",
+		SetUpScript: []string{
+			"CREATE TABLE xy (x int primary key, y int);",
+			"CREATE TABLE uv (u int primary key, v int);",
+			"INSERT INTO xy VALUES (1, 1), (2, 2);",
+			"INSERT INTO uv VALUES (1, 2), (2, 3);",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "SELECT count(*) FROM information_schema.optimizer_trace",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SET optimizer_trace = 'enabled=on'",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "SELECT x, v FROM xy JOIN uv ON x = u ORDER BY x",
+				Expected: []sql.Row{{1, 2}, {2, 3}},
+			},
+			{
+				Query:    "SELECT query, missing_bytes_beyond_max_mem_size, insufficient_privileges FROM information_schema.optimizer_trace",
+				Expected: []sql.Row{{"SELECT x, v FROM xy JOIN uv ON x = u ORDER BY x", int32(0), uint64(0)}},
+			},
+			{
+				Query:    "SELECT json_unquote(json_extract(trace, '$.steps[0].batch.name')) FROM information_schema.optimizer_trace",
+				Expected: []sql.Row{{"pre-analyzer"}},
+			},
+			{
+				Query:    "SELECT trace LIKE '%\"join_planning\"%', trace LIKE '%\"name\": \"resolveTables\"%' FROM information_schema.optimizer_trace",
+				Expected: []sql.Row{{true, true}},
+			},
+			{
+				Query:    "SET optimizer_trace_max_mem_size = 10",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "SELECT x FROM xy WHERE y = 2",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "SELECT length(trace), missing_bytes_beyond_max_mem_size > 0 FROM information_schema.optimizer_trace",
+				Expected: []sql.Row{{10, true}},
+			},
+			{
+				Query:    "SET optimizer_trace_max_mem_size = 1048576, optimizer_trace_offset = -2, optimizer_trace_limit = 2",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "SELECT u FROM uv WHERE v = 3",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "SELECT query FROM information_schema.optimizer_trace",
+				Expected: []sql.Row{{"SET optimizer_trace_max_mem_size = 1048576, optimizer_trace_offset = -2, optimizer_trace_limit = 2"}, {"SELECT u FROM uv WHERE v = 3"}},
+			},
+			{
+				Query:    "SET optimizer_trace = 'enabled=off'",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "SELECT x FROM xy WHERE y = 1",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "SELECT query FROM information_schema.optimizer_trace",
+				Expected: []sql.Row{{"SELECT u FROM uv WHERE v = 3"}, {"SET optimizer_trace = 'enabled=off'"}},
+			},
+		},
+		// the prepared statement harness does not set the text of the query in the context
+		SkipPrepared: true,
+	},
+	{
+		Name: "information_schema.profiling shows the stages of the session's statements",
+		SetUpScript: []string{
+			"CREATE TABLE xy (x int primary key, y int);",
+			"INSERT INTO xy VALUES (1, 1), (2, 2);",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "SELECT count(*) FROM information_schema.profiling",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SET profiling = 1, profiling_history_size = 2",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "SELECT x FROM xy WHERE y = 2",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query: "SELECT query_id, seq, state FROM information_schema.profiling WHERE state IN ('parsing', 'default-rules', 'executing', 'Sending data') ORDER BY seq",
+				Expected: []sql.Row{
+					{int32(1), int32(2), "parsing"},
+					{int32(1), int32(6), "default-rules"},
+					{int32(1), int32(13), "executing"},
+					{int32(1), int32(14), "Sending data"},
+				},
+			},
+			{
+				// SHOW PROFILES and SHOW PROFILE are not profiled, so that the profiles they show are kept
+				Query:            "SHOW PROFILES",
+				SkipResultsCheck: true,
+			},
+			{
+				Query:    "SELECT DISTINCT query_id FROM information_schema.profiling ORDER BY 1",
+				Expected: []sql.Row{{int32(1)}, {int32(2)}},
+			},
+			{
+				Query:    "SELECT 1",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:    "SELECT DISTINCT query_id FROM information_schema.profiling ORDER BY 1",
+				Expected: []sql.Row{{int32(3)}, {int32(4)}},
+			},
+			{
+				Query:    "SET profiling = 0",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "SELECT 2",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				Query:    "SELECT DISTINCT query_id FROM information_schema.profiling ORDER BY 1",
+				Expected: []sql.Row{{int32(5)}, {int32(6)}},
+			},
+		},
+		// prepared statements are parsed when they're prepared, so that their profiles have no parsing stage
+		SkipPrepared: true,
+	},
+	{
+		Name: "information_schema.view_table_usage lists the tables and views referenced by views",
+		SetUpScript: []string{
+			"CREATE TABLE usage_t1 (a int primary key, b int)",
+			"CREATE TABLE usage_t2 (c int primary key)",
+			"CREATE VIEW usage_v1 AS SELECT a, upper(b) FROM usage_t1",
+			"CREATE VIEW usage_v2 AS SELECT * FROM usage_v1 JOIN mydb.usage_t2 ON a = c WHERE a IN (SELECT a FROM usage_t1)",
+			"CREATE VIEW usage_v3 AS WITH usage_t2 AS (SELECT 1 AS c) SELECT * FROM usage_t2, usage_v2",
+			"CREATE VIEW usage_v4 AS SELECT 1",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query: "SELECT * FROM information_schema.view_table_usage WHERE view_name LIKE 'usage%' ORDER BY view_name, table_name",
+				Expected: []sql.Row{
+					{"def", "mydb", "usage_v1", "def", "mydb", "usage_t1"},
+					{"def", "mydb", "usage_v2", "def", "mydb", "usage_t1"},
+					{"def", "mydb", "usage_v2", "def", "mydb", "usage_t2"},
+					{"def", "mydb", "usage_v2", "def", "mydb", "usage_v1"},
+					{"def", "mydb", "usage_v3", "def", "mydb", "usage_v2"},
+				},
+			},
+			{
+				Query:    "SELECT * FROM information_schema.view_routine_usage WHERE table_name LIKE 'usage%'",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "DROP TABLE usage_t2",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "SELECT view_name, table_name FROM information_schema.view_table_usage WHERE view_name = 'usage_v2' ORDER BY table_name",
+				Expected: []sql.Row{{"usage_v2", "usage_t1"}, {"usage_v2", "usage_v1"}},
+			},
+		},
+	},
 }
 
 var SkippedInfoSchemaScripts = []ScriptTest{
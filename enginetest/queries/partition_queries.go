@@ -0,0 +1,387 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queries
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+var PartitionTests = []ScriptTest{
+	{
+		Name: "RANGE partitioning",
+		SetUpScript: []string{
+			"create table t (a int primary key, b varchar(10)) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20), partition p2 values less than maxvalue)",
+			"insert into t values (1, 'one'), (11, 'eleven'), (21, 'twenty-one'), (5, 'five'), (15, 'fifteen')",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "select * from t order by a",
+				Expected: []sql.Row{{1, "one"}, {5, "five"}, {11, "eleven"}, {15, "fifteen"}, {21, "twenty-one"}},
+			},
+			{
+				Query:    "select * from t partition (p0) order by a",
+				Expected: []sql.Row{{1, "one"}, {5, "five"}},
+			},
+			{
+				Query:    "select a from t partition (p1, P2) order by a",
+				Expected: []sql.Row{{11}, {15}, {21}},
+			},
+			{
+				Query:    "select a from t where a >= 12 order by a",
+				Expected: []sql.Row{{15}, {21}},
+			},
+			{
+				Query:    "select a from t where a = 11 or a = 5 order by a",
+				Expected: []sql.Row{{5}, {11}},
+			},
+			{
+				Query:    "select a from t where a in (1, 21) order by a",
+				Expected: []sql.Row{{1}, {21}},
+			},
+			{
+				Query:    "select a from t where a between 4 and 12 order by a",
+				Expected: []sql.Row{{5}, {11}},
+			},
+			{
+				Query:    "select t1.a from t partition (p0) t1 join t partition (p1) t2 on t1.a + 10 = t2.a order by 1",
+				Expected: []sql.Row{{1}, {5}},
+			},
+			{
+				Query:       "select * from t partition (p3)",
+				ExpectedErr: sql.ErrUnknownPartition,
+			},
+			{
+				Query: "show create table t",
+				Expected: []sql.Row{{"t", "CREATE TABLE `t` (\n" +
+					"  `a` int NOT NULL,\n" +
+					"  `b` varchar(10),\n" +
+					"  PRIMARY KEY (`a`)\n" +
+					") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin\n" +
+					"/*!50100 PARTITION BY RANGE (a)\n" +
+					"(PARTITION p0 VALUES LESS THAN (10) ENGINE = InnoDB,\n" +
+					" PARTITION p1 VALUES LESS THAN (20) ENGINE = InnoDB,\n" +
+					" PARTITION p2 VALUES LESS THAN MAXVALUE ENGINE = InnoDB) */"}},
+			},
+		},
+	},
+	{
+		Name: "DML with partition selection",
+		SetUpScript: []string{
+			"create table t (a int primary key, b int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20))",
+			"insert into t values (1, 1), (2, 2), (11, 11), (12, 12)",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:       "insert into t values (20, 20)",
+				ExpectedErr: sql.ErrNoPartitionForValue,
+			},
+			{
+				Query:       "insert into t partition (p0) values (13, 13)",
+				ExpectedErr: sql.ErrRowDoesNotMatchPartitionSet,
+			},
+			{
+				Query:    "insert into t partition (p1) values (13, 13)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "update t partition (p0) set b = b * 10",
+				Expected: []sql.Row{{newUpdateResult(2, 2)}},
+			},
+			{
+				Query:       "update t partition (p0) set a = a + 10 where a = 1",
+				ExpectedErr: sql.ErrRowDoesNotMatchPartitionSet,
+			},
+			{
+				Query:    "delete from t partition (p1)",
+				Expected: []sql.Row{{types.NewOkResult(3)}},
+			},
+			{
+				Query:    "select * from t order by a",
+				Expected: []sql.Row{{1, 10}, {2, 20}},
+			},
+			{
+				Query:    "update t set a = a + 10 where a = 2",
+				Expected: []sql.Row{{newUpdateResult(1, 1)}},
+			},
+			{
+				Query:    "select * from t partition (p1)",
+				Expected: []sql.Row{{12, 20}},
+			},
+		},
+	},
+	{
+		Name: "RANGE COLUMNS and LIST partitioning",
+		SetUpScript: []string{
+			"create table r (a varchar(10), b int) partition by range columns (a, b) (partition p0 values less than ('m', 0), partition p1 values less than (maxvalue, maxvalue))",
+			"insert into r values ('a', 1), ('m', -1), ('m', 1), ('z', 5)",
+			"create table l (a int, b int) partition by list (a % 3) (partition p0 values in (0), partition p1 values in (1, 2))",
+			"insert into l values (1, 1), (2, 2), (3, 3), (6, 6)",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "select * from r partition (p0) order by a, b",
+				Expected: []sql.Row{{"a", 1}, {"m", -1}},
+			},
+			{
+				Query:    "select * from r partition (p1) order by a, b",
+				Expected: []sql.Row{{"m", 1}, {"z", 5}},
+			},
+			{
+				Query:    "select * from r where a = 'm' and b = 1",
+				Expected: []sql.Row{{"m", 1}},
+			},
+			{
+				Query:    "select a from l partition (p0) order by a",
+				Expected: []sql.Row{{3}, {6}},
+			},
+			{
+				Query:    "select a from l partition (p1) order by a",
+				Expected: []sql.Row{{1}, {2}},
+			},
+			{
+				Query:       "create table bad (a int) partition by list (a) (partition p0 values in (1), partition p1 values in (1))",
+				ExpectedErr: sql.ErrMultipleDefinitionInListPartition,
+			},
+			{
+				Query:       "create table bad (a int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (5))",
+				ExpectedErr: sql.ErrRangeNotIncreasing,
+			},
+			{
+				Query:       "create table bad (a int) partition by range (a) (partition p0 values in (1))",
+				ExpectedErr: sql.ErrWrongPartitionValuesType,
+			},
+			{
+				Query:       "create table bad (a int primary key, b int) partition by hash (b)",
+				ExpectedErr: sql.ErrPrimaryKeyNeedsAllPartitionColumns,
+			},
+		},
+	},
+	{
+		Name: "HASH and KEY partitioning",
+		SetUpScript: []string{
+			"create table h (a int primary key, b int) partition by hash (a) partitions 4",
+			"insert into h values (1, 1), (2, 2), (3, 3), (4, 4), (5, 5)",
+			"create table k (a int primary key, b int) partition by key () partitions 2",
+			"insert into k values (1, 1), (2, 2), (3, 3)",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "select a from h partition (p1) order by a",
+				Expected: []sql.Row{{1}, {5}},
+			},
+			{
+				Query:    "select a from h where a = 3",
+				Expected: []sql.Row{{3}},
+			},
+			{
+				Query:    "select count(*) from k partition (p0, p1)",
+				Expected: []sql.Row{{3}},
+			},
+			{
+				Query:    "alter table h coalesce partition 2",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "select a from h partition (p1) order by a",
+				Expected: []sql.Row{{1}, {3}, {5}},
+			},
+			{
+				Query:    "alter table h add partition partitions 1",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "select a from h partition (p2) order by a",
+				Expected: []sql.Row{{2}, {5}},
+			},
+			{
+				Query: "show create table h",
+				Expected: []sql.Row{{"h", "CREATE TABLE `h` (\n" +
+					"  `a` int NOT NULL,\n" +
+					"  `b` int,\n" +
+					"  PRIMARY KEY (`a`)\n" +
+					") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin\n" +
+					"/*!50100 PARTITION BY HASH (a)\n" +
+					"PARTITIONS 3 */"}},
+			},
+			{
+				Query:       "alter table h drop partition p0",
+				ExpectedErr: sql.ErrPartitionActionOnlyOnRangeList,
+			},
+		},
+	},
+	{
+		Name: "HASH partitioning of negative values",
+		SetUpScript: []string{
+			"create table h (a bigint primary key) partition by hash (a) partitions 3",
+			"insert into h values (-9223372036854775808), (-1), (4), (9223372036854775807)",
+			"create table lh (a bigint primary key) partition by linear hash (a) partitions 3",
+			"insert into lh values (-9223372036854775808), (-2), (1)",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "select a from h partition (p2)",
+				Expected: []sql.Row{{-9223372036854775808}},
+			},
+			{
+				Query:    "select a from h partition (p1) order by a",
+				Expected: []sql.Row{{-1}, {4}, {9223372036854775807}},
+			},
+			{
+				Query:    "select a from h where a = -9223372036854775808",
+				Expected: []sql.Row{{-9223372036854775808}},
+			},
+			{
+				Query:    "select a from lh partition (p0)",
+				Expected: []sql.Row{{-9223372036854775808}},
+			},
+			{
+				Query:    "select a from lh partition (p2) order by a",
+				Expected: []sql.Row{{-2}},
+			},
+		},
+	},
+	{
+		Name: "ALTER TABLE partition management",
+		SetUpScript: []string{
+			"create table t (a int, b int) partition by range (a) (partition p0 values less than (10), partition p1 values less than (20))",
+			"insert into t values (1, 1), (11, 11), (15, 15)",
+			"create table plain (a int)",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "alter table t add partition (partition p2 values less than (30))",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "insert into t values (25, 25)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:       "alter table t add partition (partition p3 values less than (25))",
+				ExpectedErr: sql.ErrRangeNotIncreasing,
+			},
+			{
+				Query:       "alter table t add partition (partition p1 values less than (40))",
+				ExpectedErr: sql.ErrDuplicatePartitionName,
+			},
+			{
+				Query:    "alter table t truncate partition p1",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "select a from t order by a",
+				Expected: []sql.Row{{1}, {25}},
+			},
+			{
+				Query:    "alter table t reorganize partition p0 into (partition p0a values less than (5), partition p0b values less than (10))",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "insert into t values (7, 7)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "select a from t partition (p0b)",
+				Expected: []sql.Row{{7}},
+			},
+			{
+				Query:    "alter table t drop partition p0a, p0b",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "select a from t order by a",
+				Expected: []sql.Row{{25}},
+			},
+			{
+				Query:       "alter table t drop column a",
+				ExpectedErr: sql.ErrColumnUsedInPartitioning,
+			},
+			{
+				Query:    "alter table t add column c int first",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "insert into t values (0, 12, 12)",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "select a from t partition (p1)",
+				Expected: []sql.Row{{12}},
+			},
+			{
+				Query:    "alter table t remove partitioning",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:       "select * from t partition (p1)",
+				ExpectedErr: sql.ErrPartitionClauseOnNonpartitioned,
+			},
+			{
+				Query:    "alter table t partition by list (a) (partition p0 values in (12), partition p1 values in (25))",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "select a from t partition (p1)",
+				Expected: []sql.Row{{25}},
+			},
+			{
+				Query:       "alter table t partition by list (a) (partition p0 values in (12))",
+				ExpectedErr: sql.ErrNoPartitionForValue,
+			},
+			{
+				Query:       "alter table plain truncate partition all",
+				ExpectedErr: sql.ErrPartitionManagementOnNonpartitioned,
+			},
+		},
+	},
+	{
+		Name: "information_schema.partitions",
+		SetUpScript: []string{
+			"create table t (a int, b int) partition by range (a) (partition p0 values less than (10) comment 'small', partition p1 values less than maxvalue)",
+			"insert into t values (1, 1), (2, 2), (11, 11)",
+			"create table l (a varchar(10)) partition by list columns (a) (partition p0 values in ('x', 'y'))",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query: "select table_name, partition_name, partition_ordinal_position, partition_method, partition_expression, partition_description, table_rows, partition_comment from information_schema.partitions where table_schema = 'mydb' order by 1, 3",
+				Expected: []sql.Row{
+					{"l", "p0", uint64(1), "LIST COLUMNS", "`a`", "'x','y'", uint64(0), ""},
+					{"t", "p0", uint64(1), "RANGE", "a", "10", uint64(2), "small"},
+					{"t", "p1", uint64(2), "RANGE", "a", "MAXVALUE", uint64(1), ""},
+				},
+			},
+			{
+				Query: "select table_name, partition_name, table_rows, avg_row_length, data_length from information_schema.partitions where table_schema = 'mydb' and table_name = 't' order by 2",
+				Expected: []sql.Row{
+					{"t", "p0", uint64(2), uint64(16), uint64(32)},
+					{"t", "p1", uint64(1), uint64(16), uint64(16)},
+				},
+			},
+			{
+				Query:    "create table n (a int primary key, b varchar(10))",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "insert into n values (1, 'x'), (2, 'y')",
+				Expected: []sql.Row{{types.NewOkResult(2)}},
+			},
+			{
+				Query:    "select table_name, partition_name, partition_ordinal_position, partition_method, table_rows, avg_row_length, data_length from information_schema.partitions where table_schema = 'mydb' and table_name = 'n'",
+				Expected: []sql.Row{{"n", nil, nil, nil, uint64(2), uint64(48), uint64(96)}},
+			},
+		},
+	},
+}
@@ -616,6 +616,295 @@ var UserPrivTests = []UserPrivilegeTest{
 			},
 		},
 	},
+	{
+		Name: "Column-level privileges",
+		SetUpScript: []string{
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY, v1 BIGINT, v2 BIGINT);",
+			"INSERT INTO test VALUES (1, 10, 100), (2, 20, 200);",
+			"CREATE USER tester@localhost;",
+		},
+		Assertions: []UserPrivilegeTestAssertion{
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "GRANT SELECT (pk, v1), INSERT (pk), UPDATE (v1) ON mydb.test TO tester@localhost;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM mysql.columns_priv ORDER BY Column_name;",
+				Expected: []sql.Row{{"localhost", "mydb", "tester", "test", "pk", time.Unix(1, 0).UTC(), uint64(0b11)}, {"localhost", "mydb", "tester", "test", "v1", time.Unix(1, 0).UTC(), uint64(0b101)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM mysql.tables_priv;",
+				Expected: []sql.Row{{"localhost", "mydb", "tester", "test", "", time.Unix(1, 0).UTC(), uint64(0), uint64(0b111)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SHOW GRANTS FOR tester@localhost;",
+				Expected: []sql.Row{{"GRANT USAGE ON *.* TO `tester`@`localhost`"}, {"GRANT SELECT (`pk`, `v1`), INSERT (`pk`), UPDATE (`v1`) ON `mydb`.`test` TO `tester`@`localhost`"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM information_schema.column_privileges WHERE table_name = 'test' ORDER BY column_name, privilege_type;",
+				Expected: []sql.Row{{"'tester'@'localhost'", "def", "mydb", "test", "pk", "INSERT", "NO"}, {"'tester'@'localhost'", "def", "mydb", "test", "pk", "SELECT", "NO"}, {"'tester'@'localhost'", "def", "mydb", "test", "v1", "SELECT", "NO"}, {"'tester'@'localhost'", "def", "mydb", "test", "v1", "UPDATE", "NO"}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT pk, v1 FROM mydb.test ORDER BY pk;",
+				Expected: []sql.Row{{1, 10}, {2, 20}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT pk FROM mydb.test WHERE v1 > 10;",
+				Expected: []sql.Row{{2}},
+			},
+			{
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "SELECT * FROM mydb.test;",
+				ExpectedErr: sql.ErrColumnPrivilegeCheckFailed,
+			},
+			{
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "SELECT pk FROM mydb.test WHERE v2 > 100;",
+				ExpectedErr: sql.ErrColumnPrivilegeCheckFailed,
+			},
+			{
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "SELECT pk FROM mydb.test WHERE pk IN (SELECT v2 FROM mydb.test);",
+				ExpectedErr: sql.ErrColumnPrivilegeCheckFailed,
+			},
+			{
+				User:  "tester",
+				Host:  "localhost",
+				Query: "UPDATE mydb.test SET v1 = v1 + 1 WHERE pk = 1;",
+				Expected: []sql.Row{{types.OkResult{
+					RowsAffected: 1,
+					Info: plan.UpdateInfo{
+						Matched: 1,
+						Updated: 1,
+					},
+				}}},
+			},
+			{
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "UPDATE mydb.test SET v2 = 0 WHERE pk = 1;",
+				ExpectedErr: sql.ErrColumnPrivilegeCheckFailed,
+			},
+			{
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "INSERT INTO mydb.test (pk, v1) VALUES (3, 30);",
+				ExpectedErr: sql.ErrColumnPrivilegeCheckFailed,
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "INSERT INTO mydb.test (pk) VALUES (3);",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "SELECT * FROM mydb.test2;",
+				ExpectedErr: sql.ErrTableAccessDeniedForUser,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "REVOKE SELECT (v1) ON mydb.test FROM tester@localhost;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "SELECT pk, v1 FROM mydb.test;",
+				ExpectedErr: sql.ErrColumnPrivilegeCheckFailed,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SHOW GRANTS FOR tester@localhost;",
+				Expected: []sql.Row{{"GRANT USAGE ON *.* TO `tester`@`localhost`"}, {"GRANT SELECT (`pk`), INSERT (`pk`), UPDATE (`v1`) ON `mydb`.`test` TO `tester`@`localhost`"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "REVOKE SELECT, INSERT, UPDATE ON mydb.test FROM tester@localhost;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM mysql.columns_priv;",
+				Expected: []sql.Row{},
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "GRANT DELETE (v1) ON mydb.test TO tester@localhost;",
+				ExpectedErr: sql.ErrGrantRevokeIllegalPrivilege,
+			},
+		},
+	},
+	{
+		Name: "Routine-level privileges",
+		SetUpScript: []string{
+			"CREATE TABLE test (pk BIGINT PRIMARY KEY);",
+			"INSERT INTO test VALUES (1);",
+			"CREATE PROCEDURE p1() SELECT 1;",
+			"CREATE PROCEDURE p2() SELECT 2;",
+			"CREATE USER tester@localhost;",
+		},
+		Assertions: []UserPrivilegeTestAssertion{
+			{
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "CALL mydb.p1();",
+				ExpectedErr: sql.ErrDatabaseAccessDeniedForUser,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "GRANT EXECUTE ON PROCEDURE mydb.p1 TO tester@localhost;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM mysql.procs_priv;",
+				Expected: []sql.Row{{"localhost", "mydb", "tester", "p1", uint16(2), "", uint64(0b1), time.Unix(1, 0).UTC()}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SHOW GRANTS FOR tester@localhost;",
+				Expected: []sql.Row{{"GRANT USAGE ON *.* TO `tester`@`localhost`"}, {"GRANT EXECUTE ON PROCEDURE `mydb`.`p1` TO `tester`@`localhost`"}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "CALL mydb.p1();",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "CALL mydb.p2();",
+				ExpectedErr: sql.ErrPrivilegeCheckFailed,
+			},
+			{
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "DROP PROCEDURE mydb.p1;",
+				ExpectedErr: sql.ErrPrivilegeCheckFailed,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "REVOKE EXECUTE ON PROCEDURE mydb.p1 FROM tester@localhost;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM mysql.procs_priv;",
+				Expected: []sql.Row{},
+			},
+			{
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "CALL mydb.p1();",
+				ExpectedErr: sql.ErrDatabaseAccessDeniedForUser,
+			},
+		},
+	},
+	{
+		Name: "Account resource limits and password options",
+		SetUpScript: []string{
+			"CREATE USER limited@localhost WITH MAX_QUERIES_PER_HOUR 10 MAX_UPDATES_PER_HOUR 5 MAX_CONNECTIONS_PER_HOUR 3 MAX_USER_CONNECTIONS 2 PASSWORD EXPIRE INTERVAL 90 DAY ACCOUNT LOCK;",
+			"CREATE USER expired@localhost IDENTIFIED BY 'pass' PASSWORD EXPIRE;",
+		},
+		Assertions: []UserPrivilegeTestAssertion{
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "SELECT max_questions, max_updates, max_connections, max_user_connections, password_expired, password_lifetime, account_locked FROM mysql.user WHERE User = 'limited';",
+				Expected: []sql.Row{
+					{uint32(10), uint32(5), uint32(3), uint32(2), uint16(1), uint16(90), uint16(2)},
+				},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "ALTER USER limited@localhost WITH MAX_QUERIES_PER_HOUR 0 PASSWORD EXPIRE NEVER ACCOUNT UNLOCK;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "SELECT max_questions, max_updates, max_connections, max_user_connections, password_expired, password_lifetime, account_locked FROM mysql.user WHERE User = 'limited';",
+				Expected: []sql.Row{
+					{uint32(0), uint32(5), uint32(3), uint32(2), uint16(1), uint16(0), uint16(1)},
+				},
+			},
+			{
+				User:        "root",
+				Host:        "localhost",
+				Query:       "ALTER USER missing@localhost IDENTIFIED BY 'pass';",
+				ExpectedErr: sql.ErrUserAlterFailure,
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "ALTER USER IF EXISTS missing@localhost IDENTIFIED BY 'pass';",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:        "limited",
+				Host:        "localhost",
+				Query:       "ALTER USER expired@localhost ACCOUNT LOCK;",
+				ExpectedErr: sql.ErrPrivilegeCheckFailed,
+			},
+			{
+				User:  "root",
+				Host:  "localhost",
+				Query: "SELECT password_expired FROM mysql.user WHERE User = 'expired';",
+				Expected: []sql.Row{
+					{uint16(2)},
+				},
+			},
+			{
+				User:        "expired",
+				Host:        "localhost",
+				Query:       "SELECT 1;",
+				ExpectedErr: sql.ErrMustResetPassword,
+			},
+			{
+				User:     "expired",
+				Host:     "localhost",
+				Query:    "ALTER USER USER() IDENTIFIED BY 'newpass';",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:     "expired",
+				Host:     "localhost",
+				Query:    "SELECT 1;",
+				Expected: []sql.Row{{1}},
+			},
+		},
+	},
 	{
 		Name: "Basic revoke SELECT privilege",
 		SetUpScript: []string{
@@ -1191,6 +1480,7 @@ var UserPrivTests = []UserPrivilegeTest{
 					{"information_schema"},
 					{"mydb"},
 					{"mysql"},
+					{"performance_schema"},
 				},
 			},
 		},
@@ -1735,6 +2025,245 @@ var UserPrivTests = []UserPrivilegeTest{
 			},
 		},
 	},
+	{
+		Name: "information_schema.view_table_usage shows the views that can be shown only",
+		SetUpScript: []string{
+			"CREATE TABLE vt1 (a int primary key)",
+			"CREATE TABLE vt2 (b int primary key)",
+			"CREATE VIEW dv AS SELECT * FROM vt1",
+			"CREATE SQL SECURITY INVOKER VIEW iv AS SELECT * FROM vt1 JOIN vt2 ON a = b",
+			"CREATE DEFINER = tester@localhost VIEW tv AS SELECT * FROM vt2",
+			"CREATE USER tester@localhost;",
+		},
+		Assertions: []UserPrivilegeTestAssertion{
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT count(*) FROM information_schema.view_table_usage WHERE view_schema = 'mydb';",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "GRANT SHOW VIEW ON mydb.dv TO tester@localhost;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "GRANT SHOW VIEW ON mydb.iv TO tester@localhost;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				// Views are shown to their definer, and the tables referenced by SQL SECURITY INVOKER views are only shown to
+				// the users with privileges on them
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT view_name, table_name FROM information_schema.view_table_usage WHERE view_schema = 'mydb' ORDER BY 1, 2;",
+				Expected: []sql.Row{{"dv", "vt1"}, {"tv", "vt2"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "GRANT SELECT ON mydb.vt2 TO tester@localhost;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT view_name, table_name FROM information_schema.view_table_usage WHERE view_schema = 'mydb' ORDER BY 1, 2;",
+				Expected: []sql.Row{{"dv", "vt1"}, {"iv", "vt2"}, {"tv", "vt2"}},
+			},
+		},
+	},
+}
+
+// SessionRoleTests test the roles that are active in a session. Unlike UserPrivTests, each user keeps the same session
+// for every assertion, so that the roles activated by SET ROLE remain active for the following assertions.
+var SessionRoleTests = []UserPrivilegeTest{
+	{
+		Name: "SET ROLE, SET DEFAULT ROLE and CURRENT_ROLE()",
+		SetUpScript: []string{
+			"CREATE USER tester@localhost;",
+			"CREATE USER other@localhost;",
+			"CREATE ROLE r1, r2, r3;",
+			"GRANT SELECT, INSERT ON mydb.mytable TO r1 WITH GRANT OPTION;",
+			"GRANT SELECT (i) ON mydb.mytable TO r2;",
+			"GRANT r1 TO tester@localhost WITH ADMIN OPTION;",
+			"GRANT r2 TO tester@localhost;",
+			"SET DEFAULT ROLE r1 TO tester@localhost;",
+		},
+		Assertions: []UserPrivilegeTestAssertion{
+			{
+				// Every granted role is active until SET ROLE is used
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT CURRENT_ROLE();",
+				Expected: []sql.Row{{"`r1`@`%`,`r2`@`%`"}},
+			},
+			{
+				User:  "tester",
+				Host:  "localhost",
+				Query: "SELECT * FROM information_schema.applicable_roles ORDER BY role_name;",
+				Expected: []sql.Row{
+					{"tester", "localhost", "tester", "localhost", "r1", "%", "YES", "YES", "NO"},
+					{"tester", "localhost", "tester", "localhost", "r2", "%", "NO", "NO", "NO"},
+				},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT role_name, is_grantable FROM information_schema.administrable_role_authorizations;",
+				Expected: []sql.Row{{"r1", "YES"}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SET ROLE NONE;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT CURRENT_ROLE();",
+				Expected: []sql.Row{{"NONE"}},
+			},
+			{
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "SELECT * FROM mydb.mytable;",
+				ExpectedErr: sql.ErrDatabaseAccessDeniedForUser,
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM information_schema.enabled_roles;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SET ROLE DEFAULT;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT CURRENT_ROLE();",
+				Expected: []sql.Row{{"`r1`@`%`"}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT * FROM information_schema.enabled_roles;",
+				Expected: []sql.Row{{"r1", "%", "YES", "NO"}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT * FROM information_schema.role_table_grants;",
+				Expected: []sql.Row{{"", "", "r1", "%", "def", "mydb", "mytable", "Select,Insert,Grant", "YES"}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM mydb.mytable;",
+				Expected: []sql.Row{{3}},
+			},
+			{
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "SET ROLE r3;",
+				ExpectedErr: sql.ErrRoleNotGranted,
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SET ROLE ALL EXCEPT r1;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT CURRENT_ROLE();",
+				Expected: []sql.Row{{"`r2`@`%`"}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT * FROM information_schema.role_column_grants;",
+				Expected: []sql.Row{{"", "", "r2", "%", "def", "mydb", "mytable", "i", "Select", "NO"}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT COUNT(*) FROM information_schema.role_table_grants;",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SET ROLE ALL;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT CURRENT_ROLE();",
+				Expected: []sql.Row{{"`r1`@`%`,`r2`@`%`"}},
+			},
+			{
+				// Users may set their own default roles
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SET DEFAULT ROLE r2 TO tester@localhost;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT role_name, is_default FROM information_schema.applicable_roles ORDER BY role_name;",
+				Expected: []sql.Row{{"r1", "NO"}, {"r2", "YES"}},
+			},
+			{
+				User:        "tester",
+				Host:        "localhost",
+				Query:       "SET DEFAULT ROLE r3 TO tester@localhost;",
+				ExpectedErr: sql.ErrRoleNotGranted,
+			},
+			{
+				User:        "other",
+				Host:        "localhost",
+				Query:       "SET DEFAULT ROLE NONE TO tester@localhost;",
+				ExpectedErr: sql.ErrPrivilegeCheckFailed,
+			},
+			{
+				User:     "other",
+				Host:     "localhost",
+				Query:    "SELECT CURRENT_ROLE();",
+				Expected: []sql.Row{{"NONE"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "REVOKE r2 FROM tester@localhost;",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				// A revoked role is no longer active, nor a default role
+				User:     "tester",
+				Host:     "localhost",
+				Query:    "SELECT CURRENT_ROLE();",
+				Expected: []sql.Row{{"`r1`@`%`"}},
+			},
+			{
+				User:     "root",
+				Host:     "localhost",
+				Query:    "SELECT * FROM mysql.default_roles;",
+				Expected: []sql.Row{},
+			},
+		},
+	},
 }
 
 // NoopPlaintextPlugin is used to authenticate plaintext user plugins
@@ -1756,7 +2285,7 @@ var ServerAuthTests = []ServerAuthenticationTest{
 				Username:       "root",
 				Password:       "",
 				Query:          "DROP USER xyz;",
-				ExpectedErrStr: "Error 1105: Operation DROP USER failed for 'xyz'@'%'",
+				ExpectedErrStr: "Error 1105 (HY000): Operation DROP USER failed for 'xyz'@'%'",
 			},
 		},
 	},
@@ -2409,7 +2409,7 @@ var ProcedureShowCreate = []ScriptTest{
 var NoDbProcedureTests = []ScriptTestAssertion{
 	{
 		Query:    "SHOW databases;",
-		Expected: []sql.Row{{"information_schema"}, {"mydb"}, {"mysql"}},
+		Expected: []sql.Row{{"information_schema"}, {"mydb"}, {"mysql"}, {"performance_schema"}},
 	},
 	{
 		Query:    "SELECT database();",
@@ -1519,6 +1519,107 @@ inner join pq on true
 			"                 └─ columns: [i]\n" +
 			"",
 	},
+	{
+		Query: `SELECT /*+ NO_INDEX(mytable) */ i FROM mytable WHERE i = 2`,
+		ExpectedPlan: "Filter\n" +
+			" ├─ Eq\n" +
+			" │   ├─ mytable.i:0!null\n" +
+			" │   └─ 2 (tinyint)\n" +
+			" └─ Table\n" +
+			"     ├─ name: mytable\n" +
+			"     └─ columns: [i]\n" +
+			"",
+	},
+	{
+		Query: `SELECT /*+ INDEX(mytable PRIMARY) */ * FROM mytable WHERE i = 2 AND s = 'second row'`,
+		ExpectedPlan: "Filter\n" +
+			" ├─ Eq\n" +
+			" │   ├─ mytable.s:1!null\n" +
+			" │   └─ second row (longtext)\n" +
+			" └─ IndexedTableAccess(mytable)\n" +
+			"     ├─ index: [mytable.i]\n" +
+			"     ├─ static: [{[2, 2]}]\n" +
+			"     └─ columns: [i s]\n" +
+			"",
+	},
+	{
+		Query: `SELECT /*+ INDEX(mytable nope) */ * FROM mytable WHERE i = 2`,
+		ExpectedPlan: "IndexedTableAccess(mytable)\n" +
+			" ├─ index: [mytable.i]\n" +
+			" ├─ static: [{[2, 2]}]\n" +
+			" └─ columns: [i s]\n" +
+			"",
+	},
+	{
+		Query: `SELECT /*+ INDEX(mytable PRIMARY, nope) NO_INDEX(mytable idx_si) */ * FROM mytable WHERE i = 2`,
+		ExpectedPlan: "IndexedTableAccess(mytable)\n" +
+			" ├─ index: [mytable.i]\n" +
+			" ├─ static: [{[2, 2]}]\n" +
+			" └─ columns: [i s]\n" +
+			"",
+	},
+	{
+		Query: `SELECT /*+ NO_ORDER_INDEX(mytable) */ i FROM mytable ORDER BY i`,
+		ExpectedPlan: "Sort(mytable.i:0!null ASC nullsFirst)\n" +
+			" └─ Table\n" +
+			"     ├─ name: mytable\n" +
+			"     └─ columns: [i]\n" +
+			"",
+	},
+	{
+		Query: `SELECT /*+ HASH_JOIN(o) */ mt.i, o.pk FROM mytable mt JOIN one_pk o ON mt.i = o.pk`,
+		ExpectedPlan: "Project\n" +
+			" ├─ columns: [mt.i:1!null, o.pk:0!null]\n" +
+			" └─ HashJoin\n" +
+			"     ├─ Eq\n" +
+			"     │   ├─ mt.i:1!null\n" +
+			"     │   └─ o.pk:0!null\n" +
+			"     ├─ TableAlias(o)\n" +
+			"     │   └─ Table\n" +
+			"     │       ├─ name: one_pk\n" +
+			"     │       └─ columns: [pk]\n" +
+			"     └─ HashLookup\n" +
+			"         ├─ source: TUPLE(o.pk:0!null)\n" +
+			"         ├─ target: TUPLE(mt.i:0!null)\n" +
+			"         └─ CachedResults\n" +
+			"             └─ TableAlias(mt)\n" +
+			"                 └─ Table\n" +
+			"                     ├─ name: mytable\n" +
+			"                     └─ columns: [i]\n" +
+			"",
+	},
+	{
+		Query: `SELECT * FROM xy WHERE x IN (SELECT /*+ NO_SEMIJOIN() */ u FROM uv)`,
+		ExpectedPlan: "Filter\n" +
+			" ├─ InSubquery\n" +
+			" │   ├─ left: xy.x:0!null\n" +
+			" │   └─ right: Subquery\n" +
+			" │       ├─ cacheable: true\n" +
+			" │       └─ Table\n" +
+			" │           ├─ name: uv\n" +
+			" │           └─ columns: [u]\n" +
+			" └─ Table\n" +
+			"     ├─ name: xy\n" +
+			"     └─ columns: [x y]\n" +
+			"",
+	},
+	{
+		Query: `SELECT /*+ NO_SEMIJOIN(@sq) */ * FROM xy WHERE EXISTS (SELECT /*+ QB_NAME(sq) */ 1 FROM uv WHERE u = x)`,
+		ExpectedPlan: "Filter\n" +
+			" ├─ EXISTS Subquery\n" +
+			" │   ├─ cacheable: false\n" +
+			" │   └─ Project\n" +
+			" │       ├─ columns: [1]\n" +
+			" │       └─ Filter\n" +
+			" │           ├─ (uv.u = xy.x)\n" +
+			" │           └─ IndexedTableAccess(uv)\n" +
+			" │               ├─ index: [uv.u]\n" +
+			" │               └─ columns: [u]\n" +
+			" └─ Table\n" +
+			"     ├─ name: xy\n" +
+			"     └─ columns: [x y]\n" +
+			"",
+	},
 	{
 		Query: `SELECT t1.i FROM mytable t1 JOIN mytable t2 on t1.i = t2.i + 1 where t1.i = 2 and t2.i = 1`,
 		ExpectedPlan: "Project\n" +
@@ -17,6 +17,7 @@ package queries
 import (
 	"time"
 
+	"github.com/dolthub/vitess/go/mysql"
 	"gopkg.in/src-d/go-errors.v1"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -2823,6 +2824,227 @@ var ScriptTests = []ScriptTest{
 			},
 		},
 	},
+	{
+		Name: "query_memory_limit fails caches and spills sorts and groupings",
+		SetUpScript: []string{
+			"CREATE TABLE memlimit (pk int primary key, v varchar(20), g int);",
+			"INSERT INTO memlimit VALUES (1, 'one', 1), (2, 'two', 2), (3, 'three', 3), (4, 'four', 1), (5, 'five', 2), (6, 'six', 3), (7, 'seven', 1), (8, 'eight', 2), (9, 'nine', 3), (10, 'ten', 1);",
+			"SET @@query_memory_limit = 200;",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "SELECT @@query_memory_limit, @@connection_memory_limit;",
+				Expected: []sql.Row{{uint64(200), uint64(18446744073709551615)}},
+			},
+			{
+				Query:       "SELECT DISTINCT v FROM memlimit;",
+				ExpectedErr: sql.ErrQueryMemoryLimitExceeded,
+			},
+			{
+				Query: "SELECT v FROM memlimit ORDER BY v DESC;",
+				Expected: []sql.Row{
+					{"two"}, {"three"}, {"ten"}, {"six"}, {"seven"}, {"one"}, {"nine"}, {"four"}, {"five"}, {"eight"},
+				},
+			},
+			{
+				Query:    "SELECT g, COUNT(*), MAX(v) FROM memlimit GROUP BY g ORDER BY g;",
+				Expected: []sql.Row{{1, 4, "ten"}, {2, 3, "two"}, {3, 3, "three"}},
+			},
+			{
+				Query:    "SET @@query_memory_limit = 0;",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "SELECT COUNT(DISTINCT v) FROM memlimit;",
+				Expected: []sql.Row{{10}},
+			},
+		},
+	},
+	{
+		Name: "max_execution_time and MAX_EXECUTION_TIME hint interrupt long-running selects",
+		SetUpScript: []string{
+			"CREATE TABLE slow (i int primary key);",
+			"INSERT INTO slow VALUES (1), (2), (3);",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:       "SELECT /*+ MAX_EXECUTION_TIME(20) */ i, SLEEP(1) FROM slow;",
+				ExpectedErr: sql.ErrQueryTimeout,
+			},
+			{
+				Query:    "SELECT /*+ MAX_EXECUTION_TIME(10000) */ i FROM slow ORDER BY i;",
+				Expected: []sql.Row{{1}, {2}, {3}},
+			},
+			{
+				Query:    "SET @@max_execution_time = 20;",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:       "SELECT i, SLEEP(1) FROM slow ORDER BY i;",
+				ExpectedErr: sql.ErrQueryTimeout,
+			},
+			{
+				Query:    "SELECT /*+ MAX_EXECUTION_TIME(0) */ i, SLEEP(0.05) FROM slow ORDER BY i;",
+				Expected: []sql.Row{{1, 0}, {2, 0}, {3, 0}},
+			},
+			{
+				Query:    "SELECT COUNT(*) FROM slow;",
+				Expected: []sql.Row{{3}},
+			},
+			{
+				Query:    "SET @@max_execution_time = 0;",
+				Expected: []sql.Row{{}},
+			},
+			{
+				Query:    "SELECT i, SLEEP(0.05) FROM slow ORDER BY i;",
+				Expected: []sql.Row{{1, 0}, {2, 0}, {3, 0}},
+			},
+		},
+		// the prepared harness executes an analyzed plan, whose root no longer identifies the statement as a SELECT
+		SkipPrepared: true,
+	},
+	{
+		Name: "optimizer hints",
+		SetUpScript: []string{
+			"CREATE TABLE hint_t (i int primary key, j int, index j_idx(j));",
+			"INSERT INTO hint_t VALUES (1, 10), (2, 20), (3, 30);",
+			"SET @@group_concat_max_len = 1024;",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "SELECT /*+ SET_VAR(group_concat_max_len = 7) */ @@group_concat_max_len, COUNT(*) FROM hint_t;",
+				Expected: []sql.Row{{uint64(7), 3}},
+			},
+			{
+				Query:    "SELECT @@group_concat_max_len;",
+				Expected: []sql.Row{{uint64(1024)}},
+			},
+			{
+				Query:                           "SELECT /*+ SET_VAR(autocommit = 0) */ i FROM hint_t WHERE i = 1;",
+				Expected:                        []sql.Row{{1}},
+				ExpectedWarning:                 sql.ERNotSettableInSetVarHint,
+				ExpectedWarningsCount:           1,
+				ExpectedWarningMessageSubstring: "Variable autocommit cannot be set using SET_VAR hint.",
+			},
+			{
+				Query:                           "SELECT /*+ NO_INDEX(hint_t j_idx) NO_INDEX(hint_t) */ i FROM hint_t WHERE j = 20;",
+				Expected:                        []sql.Row{{2}},
+				ExpectedWarning:                 sql.ERWarnConflictingHint,
+				ExpectedWarningsCount:           1,
+				ExpectedWarningMessageSubstring: "Hint NO_INDEX(hint_t) is ignored as conflicting/duplicated",
+			},
+			{
+				Query:                           "SELECT /*+ NO_SEMIJOIN(@nope) */ i FROM hint_t WHERE i IN (SELECT i FROM hint_t WHERE j > 10) ORDER BY i;",
+				Expected:                        []sql.Row{{2}, {3}},
+				ExpectedWarning:                 sql.ERWarnUnknownQbName,
+				ExpectedWarningsCount:           1,
+				ExpectedWarningMessageSubstring: "Query block name nope is not found for NO_SEMIJOIN hint",
+			},
+			{
+				Query:                           "SELECT /*+ INDEX(nope) */ i FROM hint_t WHERE j = 30;",
+				Expected:                        []sql.Row{{3}},
+				ExpectedWarning:                 sql.ERUnresolvedHintName,
+				ExpectedWarningsCount:           1,
+				ExpectedWarningMessageSubstring: "Unresolved name nope for INDEX hint",
+			},
+			{
+				Query:                           "SELECT /*+ INDEX(hint_t nope_idx) */ i FROM hint_t WHERE i = 2;",
+				Expected:                        []sql.Row{{2}},
+				ExpectedWarning:                 sql.ERUnresolvedHintName,
+				ExpectedWarningsCount:           1,
+				ExpectedWarningMessageSubstring: "Unresolved name hint_t nope_idx for INDEX hint",
+			},
+			{
+				Query:                           "SELECT /*+ NO_INDEX(hint_t nope_idx, j_idx) */ i FROM hint_t WHERE j = 10;",
+				Expected:                        []sql.Row{{1}},
+				ExpectedWarning:                 sql.ERUnresolvedHintName,
+				ExpectedWarningsCount:           1,
+				ExpectedWarningMessageSubstring: "Unresolved name hint_t nope_idx for NO_INDEX hint",
+			},
+			{
+				Query:                           "SELECT /*+ NO_INDEX(hint_t) BOGUS() */ i FROM hint_t WHERE j = 10;",
+				Expected:                        []sql.Row{{1}},
+				ExpectedWarning:                 mysql.ERParseError,
+				ExpectedWarningsCount:           1,
+				ExpectedWarningMessageSubstring: "Optimizer hint syntax error near 'BOGUS()'",
+			},
+			{
+				Query:                           "UPDATE /*+ MAX_EXECUTION_TIME(1000) */ hint_t SET j = j + 1 WHERE i = 3;",
+				Expected:                        []sql.Row{{types.OkResult{RowsAffected: 1, Info: plan.UpdateInfo{Matched: 1, Updated: 1}}}},
+				ExpectedWarning:                 sql.ERWarnUnsupportedMaxExecutionTime,
+				ExpectedWarningsCount:           1,
+				ExpectedWarningMessageSubstring: "MAX_EXECUTION_TIME hint is supported by top-level standalone SELECT statements only",
+			},
+		},
+	},
+	{
+		Name: "LOCK TABLES restricts the session to the tables it locked",
+		SetUpScript: []string{
+			"CREATE TABLE locked_t1 (i int primary key);",
+			"CREATE TABLE locked_t2 (i int primary key);",
+			"INSERT INTO locked_t1 VALUES (1);",
+			"INSERT INTO locked_t2 VALUES (2);",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "LOCK TABLES locked_t1 READ;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "SELECT * FROM locked_t1;",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				Query:       "SELECT * FROM locked_t2;",
+				ExpectedErr: sql.ErrTableNotLocked,
+			},
+			{
+				Query:       "SELECT * FROM locked_t1 WHERE i NOT IN (SELECT i FROM locked_t2);",
+				ExpectedErr: sql.ErrTableNotLocked,
+			},
+			{
+				Query:       "INSERT INTO locked_t1 VALUES (3);",
+				ExpectedErr: sql.ErrTableNotLockedForWrite,
+			},
+			{
+				Query:    "SELECT count(*) FROM information_schema.tables WHERE table_name = 'locked_t2';",
+				Expected: []sql.Row{{1}},
+			},
+			{
+				// Locking tables again releases the tables locked before
+				Query:    "LOCK TABLES locked_t1 WRITE, locked_t2 READ LOCAL;",
+				Expected: []sql.Row{},
+			},
+			{
+				// The locked tables keep their metadata locks until they're unlocked
+				Query: "SELECT object_name, lock_type, lock_duration FROM performance_schema.metadata_locks WHERE object_schema = 'mydb' ORDER BY 1;",
+				Expected: []sql.Row{
+					{"locked_t1", "SHARED_NO_READ_WRITE", "EXPLICIT"},
+					{"locked_t2", "SHARED_READ", "EXPLICIT"},
+				},
+			},
+			{
+				Query:    "INSERT INTO locked_t1 SELECT i FROM locked_t2;",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:       "DELETE FROM locked_t2;",
+				ExpectedErr: sql.ErrTableNotLockedForWrite,
+			},
+			{
+				Query:    "UNLOCK TABLES;",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "SELECT count(*) FROM performance_schema.metadata_locks WHERE object_schema = 'mydb';",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "SELECT * FROM locked_t1 JOIN locked_t2 ON locked_t1.i = locked_t2.i;",
+				Expected: []sql.Row{{2, 2}},
+			},
+		},
+	},
 }
 
 var SpatialScriptTests = []ScriptTest{
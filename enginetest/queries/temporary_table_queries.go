@@ -0,0 +1,175 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package queries
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+var TemporaryTableTests = []ScriptTest{
+	{
+		Name: "basic temporary table",
+		SetUpScript: []string{
+			"create temporary table tmp (a int primary key, b varchar(10))",
+			"insert into tmp values (1, 'one'), (2, 'two')",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "select * from tmp order by a",
+				Expected: []sql.Row{{1, "one"}, {2, "two"}},
+			},
+			{
+				Query:    "update tmp set b = 'uno' where a = 1",
+				Expected: []sql.Row{{newUpdateResult(1, 1)}},
+			},
+			{
+				Query:    "delete from tmp where a = 2",
+				Expected: []sql.Row{{types.NewOkResult(1)}},
+			},
+			{
+				Query:    "select * from tmp",
+				Expected: []sql.Row{{1, "uno"}},
+			},
+			{
+				Query:    "show tables like 't%'",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select table_name from information_schema.tables where table_schema = 'mydb' and table_name = 'tmp'",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:    "select column_name from information_schema.columns where table_schema = 'mydb' and table_name = 'tmp'",
+				Expected: []sql.Row{},
+			},
+			{
+				Query: "show create table tmp",
+				Expected: []sql.Row{{"tmp", "CREATE TEMPORARY TABLE `tmp` (\n" +
+					"  `a` int NOT NULL,\n" +
+					"  `b` varchar(10),\n" +
+					"  PRIMARY KEY (`a`)\n" +
+					") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_bin"}},
+			},
+			{
+				Query:       "create temporary table tmp (x int)",
+				ExpectedErr: sql.ErrTableAlreadyExists,
+			},
+			{
+				Query:    "create temporary table if not exists tmp (x int)",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "drop table tmp",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:       "select * from tmp",
+				ExpectedErr: sql.ErrTableNotFound,
+			},
+		},
+	},
+	{
+		Name: "temporary tables shadow base tables of the same name",
+		SetUpScript: []string{
+			"create table t (a int primary key)",
+			"insert into t values (1), (2), (3)",
+			"create temporary table t (x varchar(10), y varchar(10))",
+			"insert into t values ('temp', 'row')",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "select * from t",
+				Expected: []sql.Row{{"temp", "row"}},
+			},
+			{
+				Query:    "show tables like 't%'",
+				Expected: []sql.Row{{"t"}},
+			},
+			{
+				Query:    "drop table t",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "select * from t order by a",
+				Expected: []sql.Row{{1}, {2}, {3}},
+			},
+		},
+	},
+	{
+		Name: "temporary tables created from other tables",
+		SetUpScript: []string{
+			"create table t (a int primary key, b int)",
+			"insert into t values (1, 10), (2, 20)",
+			"create temporary table t_like like t",
+			"create temporary table t_select as select a, b * 2 as c from t",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "select count(*) from t_like",
+				Expected: []sql.Row{{0}},
+			},
+			{
+				Query:    "insert into t_like select * from t",
+				Expected: []sql.Row{{types.NewOkResult(2)}},
+			},
+			{
+				Query:    "select * from t_like order by a",
+				Expected: []sql.Row{{1, 10}, {2, 20}},
+			},
+			{
+				Query:    "select * from t_select order by a",
+				Expected: []sql.Row{{1, 20}, {2, 40}},
+			},
+			{
+				Query:    "show tables like 't%'",
+				Expected: []sql.Row{{"t"}},
+			},
+		},
+	},
+	{
+		Name: "altering temporary tables",
+		SetUpScript: []string{
+			"create temporary table tmp (a int primary key)",
+			"insert into tmp values (1), (2)",
+		},
+		Assertions: []ScriptTestAssertion{
+			{
+				Query:    "alter table tmp add column b int default 5",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "alter table tmp rename to tmp2",
+				Expected: []sql.Row{{types.NewOkResult(0)}},
+			},
+			{
+				Query:    "select * from tmp2 order by a",
+				Expected: []sql.Row{{1, 5}, {2, 5}},
+			},
+			{
+				Query:       "select * from tmp",
+				ExpectedErr: sql.ErrTableNotFound,
+			},
+			{
+				Query:    "show tables like 't%'",
+				Expected: []sql.Row{},
+			},
+			{
+				Query:       "alter table tmp2 add constraint fk foreign key (a) references tmp2 (a)",
+				ExpectedErr: sql.ErrTemporaryTablesForeignKeySupport,
+			},
+		},
+	},
+}
@@ -0,0 +1,227 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/parse"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+const (
+	// eventSchedulerThreadName is the name of the background thread that runs events.
+	eventSchedulerThreadName = "event_scheduler"
+	// DefaultEventSchedulerPeriod is how often the event scheduler checks for events that are due, unless another
+	// period is given.
+	DefaultEventSchedulerPeriod = time.Second
+)
+
+// EventScheduler runs the events of every database that implements sql.EventDatabase once they are due. Events are
+// only run while the `event_scheduler` system variable is ON.
+type EventScheduler struct {
+	engine *Engine
+	newCtx func() (*sql.Context, error)
+	period time.Duration
+	mu     *sync.Mutex
+}
+
+// InitializeEventScheduler starts the event scheduler on the engine's background threads. |newCtx| is called to create
+// the context that each event is executed in, and a context with a new base session is used if it is nil. The
+// scheduler checks for due events every |period|, or every DefaultEventSchedulerPeriod if it is zero. Returns an error
+// if the `event_scheduler` system variable is DISABLED.
+func (e *Engine) InitializeEventScheduler(newCtx func() (*sql.Context, error), period time.Duration) error {
+	if eventSchedulerStatus() == "DISABLED" {
+		return sql.ErrEventSchedulerDisabled.New()
+	}
+	if newCtx == nil {
+		newCtx = func() (*sql.Context, error) {
+			return sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession())), nil
+		}
+	}
+	if period <= 0 {
+		period = DefaultEventSchedulerPeriod
+	}
+	e.EventScheduler = &EventScheduler{
+		engine: e,
+		newCtx: newCtx,
+		period: period,
+		mu:     &sync.Mutex{},
+	}
+	return e.BackgroundThreads.Add(eventSchedulerThreadName, e.EventScheduler.run)
+}
+
+// eventSchedulerStatus returns the current value of the `event_scheduler` system variable.
+func eventSchedulerStatus() string {
+	_, val, ok := sql.SystemVariables.GetGlobal("event_scheduler")
+	if !ok {
+		return "OFF"
+	}
+	status, _ := val.(string)
+	return strings.ToUpper(status)
+}
+
+// run checks for due events every period until |ctx| is cancelled.
+func (es *EventScheduler) run(ctx context.Context) {
+	ticker := time.NewTicker(es.period)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if eventSchedulerStatus() != "ON" {
+				continue
+			}
+			if err := es.runDueEvents(time.Now().UTC()); err != nil {
+				sql.NewEmptyContext().GetLogger().WithError(err).Error("event scheduler failed to run events")
+			}
+		}
+	}
+}
+
+// runDueEvents runs every enabled event that was due to execute at or before |now|, and then updates or removes the
+// event according to its schedule. Errors from loading or executing a single event are logged, and the remaining
+// events still run.
+func (es *EventScheduler) runDueEvents(now time.Time) error {
+	es.mu.Lock()
+	defer es.mu.Unlock()
+
+	ctx, err := es.newCtx()
+	if err != nil {
+		return err
+	}
+	for _, db := range es.engine.Analyzer.Catalog.Provider.AllDatabases(ctx) {
+		eventDb, ok := db.(sql.EventDatabase)
+		if !ok {
+			continue
+		}
+		events, err := eventDb.GetEvents(ctx)
+		if err != nil {
+			ctx.GetLogger().WithError(err).WithField("database", db.Name()).Error("unable to load events")
+			continue
+		}
+		for _, event := range events {
+			if err = es.runEventIfDue(ctx, eventDb, event, now); err != nil {
+				ctx.GetLogger().WithError(err).WithField("event", event.Name).WithField("database", db.Name()).
+					Error("unable to run event")
+			}
+		}
+	}
+	return nil
+}
+
+// runEventIfDue runs the event given if it was due to execute at or before |now|, and then updates or removes it
+// according to its schedule.
+func (es *EventScheduler) runEventIfDue(ctx *sql.Context, db sql.EventDatabase, event sql.EventDefinition, now time.Time) error {
+	parsedEvent, err := parse.Parse(ctx, event.CreateStatement)
+	if err != nil {
+		return err
+	}
+	details, err := plan.LoadEventDetails(ctx, event, parsedEvent)
+	if err != nil {
+		return err
+	}
+	if details.Status != plan.EventStatus_Enable {
+		return nil
+	}
+	next, ok, err := details.NextExecution(ctx)
+	if err != nil {
+		return err
+	}
+	if ok && next.After(now) {
+		return nil
+	}
+	if ok {
+		es.executeEvent(db, details)
+		details.LastExecuted = now
+		_, ok, err = details.NextExecution(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	return es.updateEvent(ctx, db, details, !ok)
+}
+
+// executeEvent runs the body of the event given in the database given, using the privileges of the event's definer.
+// The body runs like a CALL statement of the engine, so it takes the same locks as other statements and its writes
+// are seen by the query cache. Events wait for the global read lock to be released, as the statements of their bodies
+// would.
+func (es *EventScheduler) executeEvent(db sql.EventDatabase, event plan.EventDetails) {
+	ctx, err := es.newCtx()
+	if err != nil {
+		sql.NewEmptyContext().GetLogger().WithError(err).Errorf("unable to create a context for event %s", event.Name)
+		return
+	}
+	user, host := splitEventDefiner(event.Definer)
+	ctx = ctx.NewCtxWithClient(sql.Client{User: user, Address: host})
+	ctx.SetCurrentDatabase(db.Name())
+	logger := ctx.GetLogger().WithField("event", event.Name).WithField("database", db.Name())
+
+	err = func() error {
+		if err := es.engine.GlobalReadLock.BeginWrite(ctx, false); err != nil {
+			return err
+		}
+		defer es.engine.GlobalReadLock.EndWrite(ctx)
+
+		proc, err := parse.EventBodyProcedure(ctx, event)
+		if err != nil {
+			return err
+		}
+		analyze := func(ctx *sql.Context) (sql.Node, error) {
+			return es.engine.Analyzer.AnalyzeEventBody(ctx, db, proc)
+		}
+		_, iter, err := es.engine.queryNode(ctx, event.Body, plan.NewCall(db, proc.Name, nil, nil), nil, analyze)
+		if err != nil {
+			return err
+		}
+		_, err = sql.RowIterToRows(ctx, nil, iter)
+		return err
+	}()
+	if err != nil {
+		logger.WithError(err).Error("error executing event")
+	}
+}
+
+// updateEvent stores the new execution time of the event given. Completed events are dropped, or are disabled if they
+// are set to be preserved on completion. This changes the definition of the event, so it waits for the global read
+// lock to be released.
+func (es *EventScheduler) updateEvent(ctx *sql.Context, db sql.EventDatabase, event plan.EventDetails, completed bool) error {
+	if err := es.engine.GlobalReadLock.BeginWrite(ctx, false); err != nil {
+		return err
+	}
+	defer es.engine.GlobalReadLock.EndWrite(ctx)
+
+	if completed {
+		if !event.OnCompletionPreserve {
+			return db.DropEvent(ctx, event.Name)
+		}
+		event.Status = plan.EventStatus_Disable
+	}
+	return db.UpdateEvent(ctx, event.Name, event.Definition())
+}
+
+// splitEventDefiner returns the user and host of a definer in the form `user`@`host`.
+func splitEventDefiner(definer string) (string, string) {
+	user, host := definer, ""
+	if i := strings.LastIndex(definer, "@"); i >= 0 {
+		user, host = definer[:i], definer[i+1:]
+	}
+	return strings.Trim(user, "`'\""), strings.Trim(host, "`'\"")
+}
@@ -0,0 +1,143 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+func TestEventSchedulerRunDueEvents(t *testing.T) {
+	require := require.New(t)
+
+	db := memory.NewDatabase("mydb")
+	e := NewDefault(memory.NewDBProvider(db))
+	newCtx := func() (*sql.Context, error) {
+		ctx := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()))
+		ctx.SetCurrentDatabase("mydb")
+		return ctx, nil
+	}
+	es := &EventScheduler{engine: e, newCtx: newCtx, period: time.Second, mu: &sync.Mutex{}}
+
+	query := func(q string) []sql.Row {
+		ctx, err := newCtx()
+		require.NoError(err)
+		_, iter, err := e.Query(ctx, q)
+		require.NoError(err)
+		rows, err := sql.RowIterToRows(ctx, nil, iter)
+		require.NoError(err)
+		return rows
+	}
+
+	query("create table t (i int primary key)")
+	query("create event once on schedule at '2037-01-02 00:00:00' do insert into t values (1)")
+	query("create event recurring on schedule every 1 day starts '2037-01-01 00:00:00' ends '2037-01-03 00:00:00' on completion preserve do insert into t select coalesce(max(i), 0) + 10 from t")
+
+	// Nothing is due yet
+	require.NoError(es.runDueEvents(time.Date(2036, 12, 31, 0, 0, 0, 0, time.UTC)))
+	require.Empty(query("select * from t"))
+
+	require.NoError(es.runDueEvents(time.Date(2037, 1, 1, 0, 0, 0, 0, time.UTC)))
+	require.Equal([]sql.Row{{int32(10)}}, query("select * from t order by i"))
+
+	require.NoError(es.runDueEvents(time.Date(2037, 1, 2, 0, 0, 0, 0, time.UTC)))
+	require.Equal([]sql.Row{{int32(1)}, {int32(10)}, {int32(20)}}, query("select * from t order by i"))
+	require.Equal([]sql.Row{{"recurring", "ENABLED"}}, query("select event_name, status from information_schema.events"))
+
+	// The last execution of the recurring event completes it, and it's disabled rather than dropped
+	require.NoError(es.runDueEvents(time.Date(2037, 1, 3, 0, 0, 0, 0, time.UTC)))
+	require.Equal([]sql.Row{{int32(1)}, {int32(10)}, {int32(20)}, {int32(30)}}, query("select * from t order by i"))
+	require.Equal([]sql.Row{{"recurring", "DISABLED"}}, query("select event_name, status from information_schema.events"))
+
+	// Disabled events aren't run
+	require.NoError(es.runDueEvents(time.Date(2037, 1, 4, 0, 0, 0, 0, time.UTC)))
+	require.Equal([]sql.Row{{int32(1)}, {int32(10)}, {int32(20)}, {int32(30)}}, query("select * from t order by i"))
+}
+
+func TestEventSchedulerSkipsInvalidEvents(t *testing.T) {
+	require := require.New(t)
+
+	db := memory.NewDatabase("mydb")
+	e := NewDefault(memory.NewDBProvider(db))
+	newCtx := func() (*sql.Context, error) {
+		ctx := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()))
+		ctx.SetCurrentDatabase("mydb")
+		return ctx, nil
+	}
+	es := &EventScheduler{engine: e, newCtx: newCtx, period: time.Second, mu: &sync.Mutex{}}
+
+	query := func(q string) []sql.Row {
+		ctx, err := newCtx()
+		require.NoError(err)
+		_, iter, err := e.Query(ctx, q)
+		require.NoError(err)
+		rows, err := sql.RowIterToRows(ctx, nil, iter)
+		require.NoError(err)
+		return rows
+	}
+
+	// An event that can't be parsed doesn't keep the events after it from running
+	ctx, err := newCtx()
+	require.NoError(err)
+	require.NoError(db.SaveEvent(ctx, sql.EventDefinition{Name: "corrupt", CreateStatement: "create event corrupt on schedule"}))
+	query("create table t (i int primary key)")
+	query("create event once on schedule at '2037-01-02 00:00:00' do insert into t values (1)")
+
+	require.NoError(es.runDueEvents(time.Date(2037, 1, 2, 0, 0, 0, 0, time.UTC)))
+	require.Equal([]sql.Row{{int32(1)}}, query("select * from t"))
+}
+
+func TestEventSchedulerWaitsForGlobalReadLock(t *testing.T) {
+	require := require.New(t)
+
+	db := memory.NewDatabase("mydb")
+	e := NewDefault(memory.NewDBProvider(db))
+	newCtx := func() (*sql.Context, error) {
+		ctx := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()))
+		ctx.SetCurrentDatabase("mydb")
+		return ctx, ctx.SetSessionVariable(ctx, "lock_wait_timeout", int64(1))
+	}
+	es := &EventScheduler{engine: e, newCtx: newCtx, period: time.Second, mu: &sync.Mutex{}}
+
+	lockCtx, err := newCtx()
+	require.NoError(err)
+	query := func(q string) []sql.Row {
+		_, iter, err := e.Query(lockCtx, q)
+		require.NoError(err)
+		rows, err := sql.RowIterToRows(lockCtx, nil, iter)
+		require.NoError(err)
+		return rows
+	}
+
+	query("create table t (i int primary key)")
+	query("create event once on schedule at '2037-01-02 00:00:00' do insert into t values (1)")
+
+	// Due events don't write while another session holds the global read lock, and run once it's released
+	query("flush tables with read lock")
+	require.NoError(es.runDueEvents(time.Date(2037, 1, 2, 0, 0, 0, 0, time.UTC)))
+	require.Empty(query("select * from t"))
+	require.Len(query("select * from information_schema.events"), 1)
+	query("unlock tables")
+
+	require.NoError(es.runDueEvents(time.Date(2037, 1, 2, 0, 0, 0, 0, time.UTC)))
+	require.Equal([]sql.Row{{int32(1)}}, query("select * from t"))
+}
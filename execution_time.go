@@ -0,0 +1,144 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+const maxExecutionTimeSysVarName = "max_execution_time"
+
+// maxExecutionTimeHint returns the timeout in milliseconds given by a MAX_EXECUTION_TIME hint in the query given, and
+// whether the query has such a hint. Only the hints that directly follow the SELECT keyword of the statement count.
+func maxExecutionTimeHint(query string) (int64, bool) {
+	keyword, hints := statementHints(query)
+	if keyword != "select" {
+		return 0, false
+	}
+	hints = hints.OfType(sql.HintMaxExecutionTime)
+	if len(hints) == 0 || hints[0].Millis > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(hints[0].Millis), true
+}
+
+// maxExecutionTime returns how long the statement given may run before it is interrupted, or zero if it may run
+// indefinitely. Like MySQL, only read-only SELECT statements are subject to a timeout, and a MAX_EXECUTION_TIME hint
+// takes precedence over the max_execution_time system variable.
+func maxExecutionTime(ctx *sql.Context, query string, parsed sql.Node) time.Duration {
+	if !isReadOnlySelect(parsed) {
+		return 0
+	}
+	ms, ok := maxExecutionTimeHint(query)
+	if !ok {
+		val, err := ctx.GetSessionVariable(ctx, maxExecutionTimeSysVarName)
+		if err != nil {
+			return 0
+		}
+		ms, ok = val.(int64)
+		if !ok {
+			return 0
+		}
+	}
+	if ms <= 0 {
+		return 0
+	}
+	return time.Duration(ms) * time.Millisecond
+}
+
+// isReadOnlySelect returns whether the parsed node given is the root of a SELECT statement. SELECT ... INTO isn't
+// considered read-only, as it assigns variables or writes files.
+func isReadOnlySelect(node sql.Node) bool {
+	switch node.(type) {
+	case *plan.Project, *plan.GroupBy, *plan.Window, *plan.Having, *plan.Distinct,
+		*plan.Sort, *plan.Offset, *plan.Limit, *plan.Union, *plan.With:
+		return true
+	default:
+		return false
+	}
+}
+
+// withMaxExecutionTime returns a context that is canceled once the statement given has run for longer than its
+// maximum execution time, along with the function that releases it. If the statement has no maximum execution time,
+// the context given is returned along with a nil cancel function.
+func withMaxExecutionTime(ctx *sql.Context, query string, parsed sql.Node) (*sql.Context, context.CancelFunc) {
+	timeout := maxExecutionTime(ctx, query, parsed)
+	if timeout == 0 {
+		return ctx, nil
+	}
+	deadlineCtx, cancel := context.WithTimeout(ctx.Context, timeout)
+	return ctx.WithContext(deadlineCtx), cancel
+}
+
+// executionTimeIter runs the iterator of a statement with a maximum execution time under its deadline-bound context,
+// reporting ErrQueryTimeout once the deadline passes, and releases the context when it's closed.
+type executionTimeIter struct {
+	ctx    *sql.Context
+	cancel context.CancelFunc
+	iter   sql.RowIter
+}
+
+var _ sql.RowIterTypeSelector = (*executionTimeIter)(nil)
+var _ sql.RowIter = (*executionTimeIter)(nil)
+var _ sql.RowIter2 = (*executionTimeIter)(nil)
+
+func newExecutionTimeIter(ctx *sql.Context, cancel context.CancelFunc, iter sql.RowIter) *executionTimeIter {
+	return &executionTimeIter{ctx: ctx, cancel: cancel, iter: iter}
+}
+
+// Next implements the sql.RowIter interface.
+func (i *executionTimeIter) Next(ctx *sql.Context) (sql.Row, error) {
+	row, err := i.iter.Next(i.ctx)
+	return row, i.checkTimeout(err)
+}
+
+// Next2 implements the sql.RowIter2 interface.
+func (i *executionTimeIter) Next2(ctx *sql.Context, frame *sql.RowFrame) error {
+	return i.checkTimeout(i.iter.(sql.RowIter2).Next2(i.ctx, frame))
+}
+
+// Close implements the sql.RowIter interface. Closing the wrapped iterator first lets any workers it started, such as
+// the goroutines of an Exchange, observe the deadline and wind down before the context is released.
+func (i *executionTimeIter) Close(ctx *sql.Context) error {
+	err := i.checkTimeout(i.iter.Close(i.ctx))
+	i.cancel()
+	return err
+}
+
+// IsNode2 implements the sql.RowIterTypeSelector interface.
+func (i *executionTimeIter) IsNode2() bool {
+	if selector, ok := i.iter.(sql.RowIterTypeSelector); ok {
+		return selector.IsNode2()
+	}
+	return false
+}
+
+// checkTimeout replaces any error caused by the statement running past its deadline with ErrQueryTimeout.
+func (i *executionTimeIter) checkTimeout(err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+	if errors.Is(i.ctx.Err(), context.DeadlineExceeded) {
+		return sql.ErrQueryTimeout.New()
+	}
+	return err
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMaxExecutionTimeHint(t *testing.T) {
+	tests := []struct {
+		query string
+		ms    int64
+		ok    bool
+	}{
+		{"SELECT /*+ MAX_EXECUTION_TIME(1000) */ * FROM t", 1000, true},
+		{"select/*+ max_execution_time( 5 ) */ 1", 5, true},
+		{"(SELECT /*+ JOIN_ORDER(a, b) MAX_EXECUTION_TIME(20) */ * FROM a JOIN b)", 20, true},
+		{"SELECT /*+ MAX_EXECUTION_TIME(0) */ 1", 0, true},
+		{"SELECT /* MAX_EXECUTION_TIME(1000) */ 1", 0, false},
+		{"SELECT 1 /*+ MAX_EXECUTION_TIME(1000) */", 0, false},
+		{"SELECT /*+ JOIN_ORDER(a, b) */ * FROM a JOIN b", 0, false},
+		{"INSERT /*+ MAX_EXECUTION_TIME(1000) */ INTO t VALUES (1)", 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			ms, ok := maxExecutionTimeHint(tt.query)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.ms, ms)
+		})
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+// writesOrCommits returns whether the statement given, which takes the metadata locks given, is kept from running by
+// the global read lock: it commits, changes the definition of tables or databases, changes accounts or privileges,
+// writes rows, or calls a procedure, whose statements may do any of these.
+func writesOrCommits(parsed sql.Node, requests map[sql.MDLKey]sql.MDLType) bool {
+	if isCommit(parsed) || plan.IsDDLNode(parsed) || changesAccounts(parsed) {
+		return true
+	}
+	if _, ok := parsed.(*plan.Call); ok {
+		return true
+	}
+	for _, typ := range requests {
+		if typ >= sql.MDLSharedWrite {
+			return true
+		}
+	}
+	return false
+}
+
+// isCommit returns whether the statement given commits the transaction of its session.
+func isCommit(parsed sql.Node) bool {
+	_, ok := parsed.(*plan.Commit)
+	return ok
+}
+
+// changesAccounts returns whether the statement given changes user accounts, roles or privileges, which are stored
+// in the mysql database.
+func changesAccounts(parsed sql.Node) bool {
+	switch parsed.(type) {
+	case *plan.CreateUser, *plan.AlterUser, *plan.DropUser, *plan.RenameUser,
+		*plan.CreateRole, *plan.DropRole,
+		*plan.Grant, *plan.GrantRole, *plan.GrantProxy,
+		*plan.Revoke, *plan.RevokeAll, *plan.RevokeRole, *plan.RevokeProxy:
+		return true
+	default:
+		return false
+	}
+}
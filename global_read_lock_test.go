@@ -0,0 +1,127 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+)
+
+// flushingProvider is a database provider that records the FLUSH statements it's notified of.
+type flushingProvider struct {
+	sql.MutableDatabaseProvider
+	flushed []string
+}
+
+var _ sql.TableFlusher = (*flushingProvider)(nil)
+var _ sql.StatusFlusher = (*flushingProvider)(nil)
+var _ sql.LogFlusher = (*flushingProvider)(nil)
+
+func (p *flushingProvider) FlushTables(ctx *sql.Context, tables []sql.DbTable) error {
+	flushed := "tables"
+	for _, t := range tables {
+		flushed += " " + t.String()
+	}
+	p.flushed = append(p.flushed, flushed)
+	return nil
+}
+
+func (p *flushingProvider) FlushStatus(ctx *sql.Context) error {
+	p.flushed = append(p.flushed, "status")
+	return nil
+}
+
+func (p *flushingProvider) FlushLogs(ctx *sql.Context, kind string) error {
+	p.flushed = append(p.flushed, "logs "+kind)
+	return nil
+}
+
+func TestFlush(t *testing.T) {
+	db := memory.NewDatabase("mydb")
+	pro := &flushingProvider{MutableDatabaseProvider: memory.NewDBProvider(db)}
+	e := NewDefault(pro)
+	ctx := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()))
+	ctx.SetCurrentDatabase("mydb")
+	query := func(q string) error {
+		_, iter, err := e.Query(ctx, q)
+		if err != nil {
+			return err
+		}
+		_, err = sql.RowIterToRows(ctx, nil, iter)
+		return err
+	}
+
+	require.NoError(t, query("create table t1 (i int primary key)"))
+	require.NoError(t, query("create table t2 (i int primary key)"))
+	require.NoError(t, query("flush tables"))
+	require.NoError(t, query("flush local tables t1, mydb.T2"))
+	require.NoError(t, query("flush status"))
+	require.NoError(t, query("flush logs"))
+	require.NoError(t, query("flush binary logs"))
+	require.Equal(t, []string{"tables", "tables mydb.t1 mydb.t2", "status", "logs ", "logs binary"}, pro.flushed)
+
+	// Flushing tables with a read lock locks them as LOCK TABLES does, until they're unlocked
+	require.NoError(t, query("flush tables t1 with read lock"))
+	require.NoError(t, query("select * from t1"))
+	require.True(t, sql.ErrTableNotLocked.Is(query("select * from t2")))
+	err := query("insert into t1 values (1)")
+	require.IsType(t, sql.WrappedInsertError{}, err)
+	require.True(t, sql.ErrTableNotLockedForWrite.Is(err.(sql.WrappedInsertError).Cause))
+	require.NoError(t, query("unlock tables"))
+
+	require.NoError(t, query("create procedure p() begin insert into t2 values (2); end"))
+	require.NoError(t, query("flush tables with read lock"))
+	require.True(t, sql.ErrCantUpdateWithReadLock.Is(query("insert into t2 values (1)")))
+	require.True(t, sql.ErrCantUpdateWithReadLock.Is(query("drop table t2")))
+	require.True(t, sql.ErrCantUpdateWithReadLock.Is(query("call p()")))
+	require.NoError(t, query("unlock tables"))
+	require.NoError(t, query("insert into t2 values (1)"))
+	require.NoError(t, query("call p()"))
+}
+
+func TestGlobalReadLockAccounts(t *testing.T) {
+	db := memory.NewDatabase("mydb")
+	e := NewDefault(memory.NewDBProvider(db))
+	e.Analyzer.Catalog.MySQLDb.AddRootAccount()
+	e.Analyzer.Catalog.MySQLDb.SetPersister(&mysql_db.NoopPersister{})
+	ctx := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSessionWithClientServer("address",
+		sql.Client{User: "root", Address: "localhost"}, 1)))
+	ctx.SetCurrentDatabase("mydb")
+	query := func(q string) error {
+		_, iter, err := e.Query(ctx, q)
+		if err != nil {
+			return err
+		}
+		_, err = sql.RowIterToRows(ctx, nil, iter)
+		return err
+	}
+
+	// Accounts and privileges are stored in the mysql database, so they can't be changed while the lock is held
+	require.NoError(t, query("create user u"))
+	require.NoError(t, query("flush tables with read lock"))
+	require.True(t, sql.ErrCantUpdateWithReadLock.Is(query("create user u2")))
+	require.True(t, sql.ErrCantUpdateWithReadLock.Is(query("grant select on mydb.* to u")))
+	require.True(t, sql.ErrCantUpdateWithReadLock.Is(query("revoke select on mydb.* from u")))
+	require.True(t, sql.ErrCantUpdateWithReadLock.Is(query("drop user u")))
+	require.NoError(t, query("unlock tables"))
+	require.NoError(t, query("grant select on mydb.* to u"))
+	require.NoError(t, query("drop user u"))
+}
@@ -15,7 +15,9 @@
 package memory
 
 import (
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/dolthub/go-mysql-server/sql/expression"
 	"github.com/dolthub/go-mysql-server/sql/types"
@@ -41,8 +43,12 @@ var _ sql.TableDropper = (*Database)(nil)
 var _ sql.TableRenamer = (*Database)(nil)
 var _ sql.TriggerDatabase = (*Database)(nil)
 var _ sql.StoredProcedureDatabase = (*Database)(nil)
+var _ sql.EventDatabase = (*Database)(nil)
 var _ sql.ViewDatabase = (*Database)(nil)
 var _ sql.CollatedDatabase = (*Database)(nil)
+var _ sql.TemporaryTableCreator = (*Database)(nil)
+var _ sql.TemporaryTableDatabase = (*Database)(nil)
+var _ sql.TemporaryTableDropper = (*Database)(nil)
 
 // BaseDatabase is an in-memory database that can't store views, only for testing the engine
 type BaseDatabase struct {
@@ -51,8 +57,13 @@ type BaseDatabase struct {
 	fkColl            *ForeignKeyCollection
 	triggers          []sql.TriggerDefinition
 	storedProcedures  []sql.StoredProcedureDetails
+	events            []sql.EventDefinition
 	primaryKeyIndexes bool
 	collation         sql.CollationID
+
+	// Temporary tables, keyed by the ID of the session that created them
+	tempTablesMu sync.Mutex
+	tempTables   map[uint32]map[string]*Table
 }
 
 var _ MemoryDatabase = (*Database)(nil)
@@ -69,9 +80,10 @@ func NewDatabase(name string) *Database {
 // NewViewlessDatabase creates a new database that doesn't persist views. Used only for testing. Use NewDatabase.
 func NewViewlessDatabase(name string) *BaseDatabase {
 	return &BaseDatabase{
-		name:   name,
-		tables: map[string]sql.Table{},
-		fkColl: newForeignKeyCollection(),
+		name:       name,
+		tables:     map[string]sql.Table{},
+		fkColl:     newForeignKeyCollection(),
+		tempTables: map[uint32]map[string]*Table{},
 	}
 }
 
@@ -90,7 +102,12 @@ func (d *BaseDatabase) Tables() map[string]sql.Table {
 	return d.tables
 }
 
+// GetTableInsensitive returns the table with the name given, ignoring case. Temporary tables of the context's session
+// take precedence over persisted tables with the same name.
 func (d *BaseDatabase) GetTableInsensitive(ctx *sql.Context, tblName string) (sql.Table, bool, error) {
+	if tbl, ok := d.getTemporaryTable(ctx, tblName); ok {
+		return tbl, true, nil
+	}
 	tbl, ok := sql.GetTableInsensitive(tblName, d.tables)
 	return tbl, ok, nil
 }
@@ -161,6 +178,9 @@ func (db *HistoryDatabase) AddTableAsOf(name string, t sql.Table, asOf interface
 
 // AddTable adds a new table to the database.
 func (d *BaseDatabase) AddTable(name string, t sql.Table) {
+	if table, ok := t.(*Table); ok {
+		table.database = d.name
+	}
 	d.tables[name] = t
 }
 
@@ -172,6 +192,7 @@ func (d *BaseDatabase) CreateTable(ctx *sql.Context, name string, schema sql.Pri
 	}
 
 	table := NewTableWithCollation(name, schema, d.fkColl, collation)
+	table.database = d.name
 	if d.primaryKeyIndexes {
 		table.EnablePrimaryKeyIndexes()
 	}
@@ -187,6 +208,7 @@ func (d *BaseDatabase) CreateIndexedTable(ctx *sql.Context, name string, sch sql
 	}
 
 	table := NewTableWithCollation(name, sch, d.fkColl, collation)
+	table.database = d.name
 	if d.primaryKeyIndexes {
 		table.EnablePrimaryKeyIndexes()
 	}
@@ -202,8 +224,13 @@ func (d *BaseDatabase) CreateIndexedTable(ctx *sql.Context, name string, sch sql
 	return nil
 }
 
-// DropTable drops the table with the given name
+// DropTable drops the table with the given name. A temporary table of the context's session with that name is dropped
+// in preference to a persisted one.
 func (d *BaseDatabase) DropTable(ctx *sql.Context, name string) error {
+	if d.dropTemporaryTable(ctx, name) {
+		return nil
+	}
+
 	_, ok := d.tables[name]
 	if !ok {
 		return sql.ErrTableNotFound.New(name)
@@ -214,6 +241,10 @@ func (d *BaseDatabase) DropTable(ctx *sql.Context, name string) error {
 }
 
 func (d *BaseDatabase) RenameTable(ctx *sql.Context, oldName, newName string) error {
+	if renamed, err := d.renameTemporaryTable(ctx, oldName, newName); renamed || err != nil {
+		return err
+	}
+
 	tbl, ok := d.tables[oldName]
 	if !ok {
 		// Should be impossible (engine already checks this condition)
@@ -225,7 +256,15 @@ func (d *BaseDatabase) RenameTable(ctx *sql.Context, oldName, newName string) er
 		return sql.ErrTableAlreadyExists.New(newName)
 	}
 
-	memTbl := tbl.(*Table)
+	renameTable(tbl.(*Table), newName)
+	d.tables[newName] = tbl
+	delete(d.tables, oldName)
+
+	return nil
+}
+
+// renameTable sets the name of the table given, along with the source of its columns and index expressions.
+func renameTable(memTbl *Table, newName string) {
 	memTbl.name = newName
 	for _, col := range memTbl.schema.Schema {
 		col.Source = newName
@@ -237,12 +276,105 @@ func (d *BaseDatabase) RenameTable(ctx *sql.Context, oldName, newName string) er
 			memIndex.Exprs[i] = expression.NewGetFieldWithTable(i, getField.Type(), newName, getField.Name(), getField.IsNullable())
 		}
 	}
-	d.tables[newName] = tbl
-	delete(d.tables, oldName)
+}
 
+// CreateTemporaryTable implements the sql.TemporaryTableCreator interface. The table is only visible to the session
+// of the context given.
+func (d *BaseDatabase) CreateTemporaryTable(ctx *sql.Context, name string, schema sql.PrimaryKeySchema, collation sql.CollationID) error {
+	d.tempTablesMu.Lock()
+	defer d.tempTablesMu.Unlock()
+
+	sessionTables := d.tempTables[ctx.Session.ID()]
+	if _, ok := sessionTables[strings.ToLower(name)]; ok {
+		return sql.ErrTableAlreadyExists.New(name)
+	}
+
+	table := NewTableWithCollation(name, schema, d.fkColl, collation)
+	table.temporary = true
+	if d.primaryKeyIndexes {
+		table.EnablePrimaryKeyIndexes()
+	}
+
+	if sessionTables == nil {
+		sessionTables = make(map[string]*Table)
+		d.tempTables[ctx.Session.ID()] = sessionTables
+	}
+	sessionTables[strings.ToLower(name)] = table
+	return nil
+}
+
+// GetAllTemporaryTables implements the sql.TemporaryTableDatabase interface.
+func (d *BaseDatabase) GetAllTemporaryTables(ctx *sql.Context) ([]sql.Table, error) {
+	d.tempTablesMu.Lock()
+	defer d.tempTablesMu.Unlock()
+
+	sessionTables := d.tempTables[ctx.Session.ID()]
+	tables := make([]sql.Table, 0, len(sessionTables))
+	for _, table := range sessionTables {
+		tables = append(tables, table)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].Name() < tables[j].Name()
+	})
+
+	return tables, nil
+}
+
+// DropAllTemporaryTables implements the sql.TemporaryTableDropper interface.
+func (d *BaseDatabase) DropAllTemporaryTables(ctx *sql.Context) error {
+	d.tempTablesMu.Lock()
+	defer d.tempTablesMu.Unlock()
+
+	delete(d.tempTables, ctx.Session.ID())
 	return nil
 }
 
+func (d *BaseDatabase) getTemporaryTable(ctx *sql.Context, name string) (*Table, bool) {
+	d.tempTablesMu.Lock()
+	defer d.tempTablesMu.Unlock()
+
+	table, ok := d.tempTables[ctx.Session.ID()][strings.ToLower(name)]
+	return table, ok
+}
+
+func (d *BaseDatabase) dropTemporaryTable(ctx *sql.Context, name string) bool {
+	d.tempTablesMu.Lock()
+	defer d.tempTablesMu.Unlock()
+
+	sessionTables := d.tempTables[ctx.Session.ID()]
+	if _, ok := sessionTables[strings.ToLower(name)]; !ok {
+		return false
+	}
+
+	delete(sessionTables, strings.ToLower(name))
+	if len(sessionTables) == 0 {
+		delete(d.tempTables, ctx.Session.ID())
+	}
+	return true
+}
+
+// renameTemporaryTable renames the temporary table of the context's session with the old name given, returning
+// whether there was one to rename.
+func (d *BaseDatabase) renameTemporaryTable(ctx *sql.Context, oldName, newName string) (bool, error) {
+	d.tempTablesMu.Lock()
+	defer d.tempTablesMu.Unlock()
+
+	sessionTables := d.tempTables[ctx.Session.ID()]
+	table, ok := sessionTables[strings.ToLower(oldName)]
+	if !ok {
+		return false, nil
+	}
+
+	if _, ok := sessionTables[strings.ToLower(newName)]; ok {
+		return true, sql.ErrTableAlreadyExists.New(newName)
+	}
+
+	renameTable(table, newName)
+	delete(sessionTables, strings.ToLower(oldName))
+	sessionTables[strings.ToLower(newName)] = table
+	return true, nil
+}
+
 func (d *BaseDatabase) GetTriggers(ctx *sql.Context) ([]sql.TriggerDefinition, error) {
 	var triggers []sql.TriggerDefinition
 	for _, def := range d.triggers {
@@ -320,6 +452,67 @@ func (d *BaseDatabase) DropStoredProcedure(ctx *sql.Context, name string) error
 	return nil
 }
 
+// GetEvent implements sql.EventDatabase
+func (d *BaseDatabase) GetEvent(ctx *sql.Context, name string) (sql.EventDefinition, bool, error) {
+	name = strings.ToLower(name)
+	for _, ed := range d.events {
+		if name == strings.ToLower(ed.Name) {
+			return ed, true, nil
+		}
+	}
+	return sql.EventDefinition{}, false, nil
+}
+
+// GetEvents implements sql.EventDatabase
+func (d *BaseDatabase) GetEvents(ctx *sql.Context) ([]sql.EventDefinition, error) {
+	var eds []sql.EventDefinition
+	for _, ed := range d.events {
+		eds = append(eds, ed)
+	}
+	return eds, nil
+}
+
+// SaveEvent implements sql.EventDatabase
+func (d *BaseDatabase) SaveEvent(ctx *sql.Context, ed sql.EventDefinition) error {
+	loweredName := strings.ToLower(ed.Name)
+	for _, existingEd := range d.events {
+		if strings.ToLower(existingEd.Name) == loweredName {
+			return sql.ErrEventAlreadyExists.New(ed.Name)
+		}
+	}
+	d.events = append(d.events, ed)
+	return nil
+}
+
+// DropEvent implements sql.EventDatabase
+func (d *BaseDatabase) DropEvent(ctx *sql.Context, name string) error {
+	loweredName := strings.ToLower(name)
+	found := false
+	for i, ed := range d.events {
+		if strings.ToLower(ed.Name) == loweredName {
+			d.events = append(d.events[:i], d.events[i+1:]...)
+			found = true
+			break
+		}
+	}
+	if !found {
+		return sql.ErrEventDoesNotExist.New(name)
+	}
+	return nil
+}
+
+// UpdateEvent implements sql.EventDatabase
+func (d *BaseDatabase) UpdateEvent(ctx *sql.Context, originalName string, ed sql.EventDefinition) error {
+	loweredName := strings.ToLower(originalName)
+	for i, existingEd := range d.events {
+		if strings.ToLower(existingEd.Name) == loweredName {
+			d.events[i] = ed
+			return nil
+		}
+	}
+	return sql.ErrEventDoesNotExist.New(originalName)
+}
+
 // GetCollation implements sql.CollatedDatabase.
 func (d *BaseDatabase) GetCollation(ctx *sql.Context) sql.CollationID {
 	return d.collation
@@ -45,6 +45,7 @@ type Table struct {
 	checks           []sql.CheckDefinition
 	collation        sql.CollationID
 	pkIndexesEnabled bool
+	temporary        bool
 
 	// pushdown info
 	filters         []sql.Expression // currently unused, filter pushdown is significantly broken right now
@@ -59,6 +60,12 @@ type Table struct {
 	// Insert bookkeeping
 	insertPartIdx int
 
+	// User-defined partitioning. When partitioned, each partition of the scheme is stored under its name.
+	partitioning       *sql.PartitionScheme
+	selectedPartitions []string
+	// selectedFrom is the table that this table's partitions were selected from, which receives all edits
+	selectedFrom *Table
+
 	// Indexed lookups
 	lookup sql.DriverIndexLookup
 
@@ -67,6 +74,12 @@ type Table struct {
 	autoColIdx int
 
 	tableStats *sql.TableStatistics
+
+	// LOCK TABLES locks, shared by every copy of the table
+	locks *tableLocks
+	// database is the name of the database the table was added to, whose cached query results are evicted once the
+	// table is written
+	database string
 }
 
 var _ sql.Table = (*Table)(nil)
@@ -88,6 +101,9 @@ var _ sql.StatisticsTable = (*Table)(nil)
 var _ sql.ProjectedTable = (*Table)(nil)
 var _ sql.PrimaryKeyAlterableTable = (*Table)(nil)
 var _ sql.PrimaryKeyTable = (*Table)(nil)
+var _ sql.PartitionAlterableTable = (*Table)(nil)
+var _ sql.TemporaryTable = (*Table)(nil)
+var _ sql.ReadLocalLockable = (*Table)(nil)
 
 // NewTable creates a new Table with the given name and schema. Assigns the default collation, therefore if a different
 // collation is desired, please use NewTableWithCollation.
@@ -140,6 +156,7 @@ func NewPartitionedTableWithCollation(name string, schema sql.PrimaryKeySchema,
 		partitionKeys: keys,
 		autoIncVal:    autoIncVal,
 		autoColIdx:    autoIncIdx,
+		locks:         newTableLocks(),
 	}
 }
 
@@ -161,6 +178,11 @@ func (t *Table) Collation() sql.CollationID {
 	return t.collation
 }
 
+// IsTemporary implements the sql.TemporaryTable interface.
+func (t *Table) IsTemporary() bool {
+	return t.temporary
+}
+
 func (t *Table) GetPartition(key string) []sql.Row {
 	rows, ok := t.partitions[string(key)]
 	if ok {
@@ -170,10 +192,15 @@ func (t *Table) GetPartition(key string) []sql.Row {
 	return nil
 }
 
-// Partitions implements the sql.Table interface.
+// Partitions implements the sql.Table interface. LOCK TABLES locks are checked once the partitions are iterated, rather
+// than for each partition, so that a scan isn't kept from reading its remaining partitions by a lock taken after it
+// started.
 func (t *Table) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
+	if err := t.locks.checkRead(ctx); err != nil {
+		return nil, err
+	}
 	var keys [][]byte
-	for _, k := range t.partitionKeys {
+	for _, k := range t.scannedPartitionKeys() {
 		if rows, ok := t.partitions[string(k)]; ok && len(rows) > 0 {
 			keys = append(keys, k)
 		}
@@ -245,7 +272,20 @@ type spatialRangePartition struct {
 
 // PartitionCount implements the sql.PartitionCounter interface.
 func (t *Table) PartitionCount(ctx *sql.Context) (int64, error) {
-	return int64(len(t.partitions)), nil
+	return int64(len(t.scannedPartitionKeys())), nil
+}
+
+// scannedPartitionKeys returns the keys of the partitions that are scanned, which are only the selected partitions if
+// any were selected.
+func (t *Table) scannedPartitionKeys() [][]byte {
+	if t.selectedPartitions == nil {
+		return t.partitionKeys
+	}
+	keys := make([][]byte, len(t.selectedPartitions))
+	for i, name := range t.selectedPartitions {
+		keys[i] = []byte(name)
+	}
+	return keys
 }
 
 // PartitionRows implements the sql.PartitionRows interface.
@@ -623,6 +663,12 @@ func (t *Table) AutoIncrementSetter(*sql.Context) sql.AutoIncrementSetter {
 }
 
 func (t *Table) newTableEditor() *tableEditor {
+	if t.selectedFrom != nil {
+		editor := t.selectedFrom.newTableEditor()
+		editor.selectedPartitions = t.selectedPartitions
+		return editor
+	}
+
 	var uniqIdxCols [][]int
 	var prefixLengths [][]uint16
 	for _, idx := range t.indexes {
@@ -653,6 +699,9 @@ func (t *Table) newTableEditor() *tableEditor {
 }
 
 func (t *Table) Truncate(ctx *sql.Context) (int, error) {
+	if err := t.locks.checkWrite(ctx, t.name, false); err != nil {
+		return 0, err
+	}
 	count := 0
 	for key := range t.partitions {
 		count += len(t.partitions[key])
@@ -751,6 +800,7 @@ func (t *Table) GetNextAutoIncrementValue(ctx *sql.Context, insertVal interface{
 
 func (t *Table) AddColumn(ctx *sql.Context, column *sql.Column, order *sql.ColumnOrder) error {
 	newColIdx := t.addColumnToSchema(ctx, column, order)
+	t.rebindPartitioning()
 	return t.insertValueInRows(ctx, newColIdx, column.Default)
 }
 
@@ -866,6 +916,10 @@ func (t *Table) insertValueInRows(ctx *sql.Context, idx int, colDefault *sql.Col
 }
 
 func (t *Table) DropColumn(ctx *sql.Context, columnName string) error {
+	if t.partitioning != nil && t.partitioning.UsesColumn(columnName) {
+		return sql.ErrColumnUsedInPartitioning.New(columnName)
+	}
+
 	droppedCol := t.dropColumnFromSchema(ctx, columnName)
 	for k, p := range t.partitions {
 		newP := make([]sql.Row, len(p))
@@ -877,6 +931,7 @@ func (t *Table) DropColumn(ctx *sql.Context, columnName string) error {
 		}
 		t.partitions[k] = newP
 	}
+	t.rebindPartitioning()
 	return nil
 }
 
@@ -908,6 +963,10 @@ func (t *Table) dropColumnFromSchema(ctx *sql.Context, columnName string) int {
 }
 
 func (t *Table) ModifyColumn(ctx *sql.Context, columnName string, column *sql.Column, order *sql.ColumnOrder) error {
+	if t.partitioning != nil && !strings.EqualFold(columnName, column.Name) && t.partitioning.UsesColumn(columnName) {
+		return sql.ErrColumnUsedInPartitioning.New(columnName)
+	}
+
 	oldIdx := -1
 	newIdx := 0
 	for i, col := range t.schema.Schema {
@@ -992,6 +1051,7 @@ func (t *Table) ModifyColumn(ctx *sql.Context, columnName string, column *sql.Co
 		}
 	}
 
+	t.rebindPartitioning()
 	return nil
 }
 
@@ -1344,6 +1404,132 @@ func (t *Table) GetForeignKeyEditor(ctx *sql.Context) sql.ForeignKeyEditor {
 	return t.newTableEditor()
 }
 
+// PartitionScheme implements sql.PartitionedTable
+func (t *Table) PartitionScheme(ctx *sql.Context) (*sql.PartitionScheme, error) {
+	return t.partitioning, nil
+}
+
+// WithSelectedPartitions implements sql.PartitionedTable
+func (t *Table) WithSelectedPartitions(names []string) (sql.Table, error) {
+	if t.partitioning == nil {
+		return nil, sql.ErrPartitionClauseOnNonpartitioned.New()
+	}
+
+	selected := make([]string, 0, len(names))
+	for _, name := range names {
+		idx := t.partitioning.PartitionIndex(name)
+		if idx < 0 {
+			return nil, sql.ErrUnknownPartition.New(name, t.name)
+		}
+		selected = append(selected, t.partitioning.Definitions[idx].Name)
+	}
+
+	nt := *t
+	nt.selectedPartitions = selected
+	if t.selectedFrom == nil {
+		nt.selectedFrom = t
+	}
+	return &nt, nil
+}
+
+// SelectedPartitions implements sql.PartitionedTable
+func (t *Table) SelectedPartitions() []string {
+	return t.selectedPartitions
+}
+
+// SetPartitionScheme implements sql.PartitionAlterableTable
+func (t *Table) SetPartitionScheme(ctx *sql.Context, scheme *sql.PartitionScheme) error {
+	var keys [][]byte
+	partitions := make(map[string][]sql.Row)
+	if scheme == nil {
+		keys = append(keys, []byte("0"))
+		partitions["0"] = []sql.Row{}
+	} else {
+		for _, def := range scheme.Definitions {
+			keys = append(keys, []byte(def.Name))
+			partitions[def.Name] = []sql.Row{}
+		}
+	}
+
+	for _, k := range t.partitionKeys {
+		for _, row := range t.partitions[string(k)] {
+			key := "0"
+			if scheme != nil {
+				i, err := scheme.PartitionForRow(ctx, t.schema.Schema, row)
+				if err != nil {
+					return err
+				}
+				key = scheme.Definitions[i].Name
+			}
+			partitions[key] = append(partitions[key], row)
+		}
+	}
+
+	t.partitioning = scheme
+	t.partitions = partitions
+	t.partitionKeys = keys
+	t.insertPartIdx = 0
+	t.sortRows()
+	return nil
+}
+
+// TruncatePartitions implements sql.PartitionAlterableTable
+func (t *Table) TruncatePartitions(ctx *sql.Context, names []string) (int, error) {
+	if err := t.locks.checkWrite(ctx, t.name, false); err != nil {
+		return 0, err
+	}
+	if t.partitioning == nil {
+		return 0, sql.ErrPartitionManagementOnNonpartitioned.New()
+	}
+
+	count := 0
+	for _, name := range names {
+		idx := t.partitioning.PartitionIndex(name)
+		if idx < 0 {
+			return 0, sql.ErrUnknownPartition.New(name, t.name)
+		}
+		key := t.partitioning.Definitions[idx].Name
+		count += len(t.partitions[key])
+		t.partitions[key] = []sql.Row{}
+	}
+	return count, nil
+}
+
+// partitionKeyForRow returns the key of the partition that a new row is stored in. Rows of tables without user-defined
+// partitioning are distributed among the partitions in turn.
+func (t *Table) partitionKeyForRow(ctx *sql.Context, row sql.Row) (string, error) {
+	if t.partitioning == nil {
+		key := string(t.partitionKeys[t.insertPartIdx])
+		t.insertPartIdx++
+		if t.insertPartIdx == len(t.partitionKeys) {
+			t.insertPartIdx = 0
+		}
+		return key, nil
+	}
+
+	i, err := t.partitioning.PartitionForRow(ctx, t.schema.Schema, row)
+	if err != nil {
+		return "", err
+	}
+	return t.partitioning.Definitions[i].Name, nil
+}
+
+// rebindPartitioning updates the column indexes of the partitioning expression after the schema changes.
+func (t *Table) rebindPartitioning() {
+	if t.partitioning == nil || t.partitioning.Expression == nil {
+		return
+	}
+	expr, _, _ := transform.Expr(t.partitioning.Expression, func(e sql.Expression) (sql.Expression, transform.TreeIdentity, error) {
+		if gf, ok := e.(*expression.GetField); ok {
+			return gf.WithIndex(t.schema.Schema.IndexOfColName(gf.Name())), transform.NewTree, nil
+		}
+		return e, transform.SameTree, nil
+	})
+	scheme := t.partitioning.Copy()
+	scheme.Expression = expr
+	t.partitioning = scheme
+}
+
 // GetChecks implements sql.CheckTable
 func (t *Table) GetChecks(_ *sql.Context) ([]sql.CheckDefinition, error) {
 	return t.checks, nil
@@ -1652,6 +1838,19 @@ func (t *Table) sortRows() {
 		return false
 	}
 
+	// Rows can't move between user-defined partitions, so each of them is sorted on its own
+	if t.partitioning != nil {
+		for _, k := range t.partitionKeys {
+			p := t.partitions[string(k)]
+			idx := make([]partidx, len(p))
+			for i := range p {
+				idx[i] = partidx{string(k), i}
+			}
+			sort.Sort(partitionssort{t.partitions, idx, less})
+		}
+		return
+	}
+
 	var idx []partidx
 	for _, k := range t.partitionKeys {
 		p := t.partitions[string(k)]
@@ -1714,6 +1913,12 @@ func copyschema(sch sql.Schema) sql.Schema {
 
 func newTable(t *Table, newSch sql.PrimaryKeySchema) (*Table, error) {
 	newTable := NewPartitionedTableWithCollation(t.name, newSch, t.fkColl, len(t.partitions), t.collation)
+	newTable.temporary = t.temporary
+	if t.partitioning != nil {
+		if err := newTable.SetPartitionScheme(sql.NewEmptyContext(), t.partitioning); err != nil {
+			return nil, err
+		}
+	}
 	for _, partition := range t.partitions {
 		for _, partitionRow := range partition {
 			err := newTable.Insert(sql.NewEmptyContext(), partitionRow)
@@ -33,6 +33,14 @@ type tableEditor struct {
 	uniqueIdxCols [][]int
 	prefixLengths [][]uint16
 	fkTable       *Table
+	// selectedPartitions are the only partitions that new rows may belong to, if not nil
+	selectedPartitions []string
+	// insertChecked and writeChecked are whether the LOCK TABLES locks of the table were checked for inserts, and for
+	// any write, during the current statement.
+	insertChecked bool
+	writeChecked  bool
+	// written is whether rows were written during the current statement
+	written bool
 }
 
 var _ sql.Table = (*tableEditor)(nil)
@@ -79,6 +87,7 @@ func (t *tableEditor) Close(ctx *sql.Context) error {
 }
 
 func (t *tableEditor) StatementBegin(ctx *sql.Context) {
+	t.insertChecked, t.writeChecked = false, false
 	t.initialInsert = t.table.insertPartIdx
 	t.initialAutoIncVal = t.table.autoIncVal
 	t.initialPartitions = make(map[string][]sql.Row)
@@ -96,6 +105,8 @@ func (t *tableEditor) DiscardChanges(ctx *sql.Context, errorEncountered error) e
 	t.table.autoIncVal = t.initialAutoIncVal
 	t.table.partitions = t.initialPartitions
 	t.ea.Clear()
+	t.insertChecked, t.writeChecked = false, false
+	t.invalidateQueryCaches()
 	return nil
 }
 
@@ -105,6 +116,8 @@ func (t *tableEditor) StatementComplete(ctx *sql.Context) error {
 		return nil
 	}
 	t.ea.Clear()
+	t.insertChecked, t.writeChecked = false, false
+	t.invalidateQueryCaches()
 	t.initialInsert = t.table.insertPartIdx
 	t.initialAutoIncVal = t.table.autoIncVal
 	t.initialPartitions = make(map[string][]sql.Row)
@@ -118,12 +131,45 @@ func (t *tableEditor) StatementComplete(ctx *sql.Context) error {
 	return nil
 }
 
+// invalidateQueryCaches evicts the cached query results that read the table once rows written during the statement
+// are applied or discarded, since other sessions may have read them in the meantime.
+func (t *tableEditor) invalidateQueryCaches() {
+	if t.written {
+		t.written = false
+		sql.InvalidateQueryCaches(t.table.database, t.table.name)
+	}
+}
+
+// checkLocks checks the LOCK TABLES locks of the table for a write, once per statement, so that a lock taken while
+// the statement runs doesn't fail it after some of its rows were written. Checking for any write covers inserts.
+func (t *tableEditor) checkLocks(ctx *sql.Context, insert bool) error {
+	if t.writeChecked || (insert && t.insertChecked) {
+		return nil
+	}
+	if err := t.table.locks.checkWrite(ctx, t.table.name, insert); err != nil {
+		return err
+	}
+	if insert {
+		t.insertChecked = true
+	} else {
+		t.writeChecked = true
+	}
+	return nil
+}
+
 // Insert a new row into the table.
 func (t *tableEditor) Insert(ctx *sql.Context, row sql.Row) error {
+	if err := t.checkLocks(ctx, true); err != nil {
+		return err
+	}
+	t.written = true
 	if err := checkRow(t.table.schema.Schema, row); err != nil {
 		return err
 	}
 	t.table.verifyRowTypes(row)
+	if err := t.checkPartition(ctx, row); err != nil {
+		return err
+	}
 
 	partitionRow, added, err := t.ea.Get(row)
 	if err != nil {
@@ -181,6 +227,10 @@ func (t *tableEditor) Insert(ctx *sql.Context, row sql.Row) error {
 
 // Delete the given row from the table.
 func (t *tableEditor) Delete(ctx *sql.Context, row sql.Row) error {
+	if err := t.checkLocks(ctx, false); err != nil {
+		return err
+	}
+	t.written = true
 	if err := checkRow(t.table.Schema(), row); err != nil {
 		return err
 	}
@@ -196,6 +246,10 @@ func (t *tableEditor) Delete(ctx *sql.Context, row sql.Row) error {
 
 // Update the given row from the table.
 func (t *tableEditor) Update(ctx *sql.Context, oldRow sql.Row, newRow sql.Row) error {
+	if err := t.checkLocks(ctx, false); err != nil {
+		return err
+	}
+	t.written = true
 	if err := checkRow(t.table.Schema(), oldRow); err != nil {
 		return err
 	}
@@ -204,6 +258,9 @@ func (t *tableEditor) Update(ctx *sql.Context, oldRow sql.Row, newRow sql.Row) e
 	}
 	t.table.verifyRowTypes(oldRow)
 	t.table.verifyRowTypes(newRow)
+	if err := t.checkPartition(ctx, newRow); err != nil {
+		return err
+	}
 
 	err := t.ea.Delete(oldRow)
 	if err != nil {
@@ -306,6 +363,27 @@ func (t *tableEditor) IndexedAccess(i sql.IndexLookup) sql.IndexedTable {
 	}
 }
 
+// checkPartition returns an error if the row given doesn't belong to any partition of the table, or to any of the
+// selected partitions.
+func (t *tableEditor) checkPartition(ctx *sql.Context, row sql.Row) error {
+	if t.table.partitioning == nil {
+		return nil
+	}
+	i, err := t.table.partitioning.PartitionForRow(ctx, t.table.schema.Schema, row)
+	if err != nil {
+		return err
+	}
+	if t.selectedPartitions == nil {
+		return nil
+	}
+	for _, name := range t.selectedPartitions {
+		if name == t.table.partitioning.Definitions[i].Name {
+			return nil
+		}
+	}
+	return sql.ErrRowDoesNotMatchPartitionSet.New()
+}
+
 func (t *tableEditor) pkColumnIndexes() []int {
 	var pkColIdxes []int
 	for _, column := range t.table.schema.Schema {
@@ -564,10 +642,9 @@ func (pke *pkTableEditAccumulator) deleteHelper(ctx *sql.Context, table *Table,
 
 // insertHelper inserts the given row into the given table.
 func (pke *pkTableEditAccumulator) insertHelper(ctx *sql.Context, table *Table, row sql.Row) error {
-	key := string(table.partitionKeys[table.insertPartIdx])
-	table.insertPartIdx++
-	if table.insertPartIdx == len(table.partitionKeys) {
-		table.insertPartIdx = 0
+	key, err := table.partitionKeyForRow(ctx, row)
+	if err != nil {
+		return err
 	}
 
 	pkColIdxes := pke.pkColumnIndexes()
@@ -728,10 +805,9 @@ func (k *keylessTableEditAccumulator) deleteHelper(ctx *sql.Context, table *Tabl
 
 // insertHelper inserts into a keyless table.
 func (k *keylessTableEditAccumulator) insertHelper(ctx *sql.Context, table *Table, row sql.Row) error {
-	key := string(table.partitionKeys[table.insertPartIdx])
-	table.insertPartIdx++
-	if table.insertPartIdx == len(table.partitionKeys) {
-		table.insertPartIdx = 0
+	key, err := table.partitionKeyForRow(ctx, row)
+	if err != nil {
+		return err
 	}
 
 	table.partitions[key] = append(table.partitions[key], row)
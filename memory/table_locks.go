@@ -0,0 +1,175 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// lockMode is the mode of a LOCK TABLES lock.
+type lockMode int
+
+const (
+	// lockRead lets other sessions read the table, but not write it.
+	lockRead lockMode = iota
+	// lockReadLocal lets other sessions read the table and insert rows into it, but not update or delete them.
+	lockReadLocal
+	// lockWrite keeps other sessions from reading and writing the table.
+	lockWrite
+)
+
+// tableLocks are the LOCK TABLES locks held on a table. They're shared by every copy of the table, so that the locks
+// taken on the table stored in its database apply to the projected and filtered copies that statements use.
+type tableLocks struct {
+	mu   sync.Mutex
+	held map[uint32]lockMode
+	// released is made by the first session that waits for a lock to be released, and closed once one is.
+	released chan struct{}
+}
+
+func newTableLocks() *tableLocks {
+	return &tableLocks{held: make(map[uint32]lockMode)}
+}
+
+// lock takes a lock of the mode given for the session of the context, replacing the one it already holds. It waits
+// for other sessions to release their conflicting locks.
+func (l *tableLocks) lock(ctx *sql.Context, mode lockMode) error {
+	err := l.wait(ctx, func(other lockMode) bool {
+		return mode == lockWrite || other == lockWrite
+	})
+	if err != nil {
+		return err
+	}
+	defer l.mu.Unlock()
+	l.held[ctx.ID()] = mode
+	return nil
+}
+
+// unlock releases the lock held by the session given, if any.
+func (l *tableLocks) unlock(id uint32) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.held[id]; ok {
+		delete(l.held, id)
+		if l.released != nil {
+			close(l.released)
+			l.released = nil
+		}
+	}
+}
+
+// checkRead waits until the session of the context may read the table.
+func (l *tableLocks) checkRead(ctx *sql.Context) error {
+	if l == nil {
+		return nil
+	}
+	err := l.wait(ctx, func(other lockMode) bool {
+		return other == lockWrite
+	})
+	if err != nil {
+		return err
+	}
+	l.mu.Unlock()
+	return nil
+}
+
+// checkWrite waits until the session of the context may write the table named, or returns an error if it only
+// locked the table for reads. Inserts aren't kept waiting by READ LOCAL locks.
+func (l *tableLocks) checkWrite(ctx *sql.Context, name string, insert bool) error {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	mode, ok := l.held[ctx.ID()]
+	l.mu.Unlock()
+	if ok && mode != lockWrite {
+		return sql.ErrTableNotLockedForWrite.New(name)
+	}
+
+	err := l.wait(ctx, func(other lockMode) bool {
+		return other != lockReadLocal || !insert
+	})
+	if err != nil {
+		return err
+	}
+	l.mu.Unlock()
+	return nil
+}
+
+// wait waits until no other session holds a lock that conflicts, as given by the function given, or until the
+// lock_wait_timeout of the session passes. When it returns without an error, the mutex of the locks is held.
+func (l *tableLocks) wait(ctx *sql.Context, conflicts func(other lockMode) bool) error {
+	var timer <-chan time.Time
+	var waiting bool
+	for {
+		l.mu.Lock()
+		blocked := false
+		for id, mode := range l.held {
+			if id != ctx.ID() && conflicts(mode) {
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			return nil
+		}
+		if l.released == nil {
+			l.released = make(chan struct{})
+		}
+		released := l.released
+		l.mu.Unlock()
+
+		if !waiting {
+			waiting = true
+			if timeout := sql.LockWaitTimeout(ctx); timeout >= 0 {
+				t := time.NewTimer(timeout)
+				defer t.Stop()
+				timer = t.C
+			}
+		}
+		select {
+		case <-released:
+		case <-timer:
+			return sql.ErrLockWaitTimeout.New()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Lock implements the sql.Lockable interface.
+func (t *Table) Lock(ctx *sql.Context, write bool) error {
+	if write {
+		return t.locks.lock(ctx, lockWrite)
+	}
+	return t.locks.lock(ctx, lockRead)
+}
+
+// LockReadLocal implements the sql.ReadLocalLockable interface.
+func (t *Table) LockReadLocal(ctx *sql.Context) error {
+	return t.locks.lock(ctx, lockReadLocal)
+}
+
+// Unlock implements the sql.Lockable interface.
+func (t *Table) Unlock(ctx *sql.Context, id uint32) error {
+	t.locks.unlock(id)
+	return nil
+}
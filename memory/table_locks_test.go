@@ -0,0 +1,142 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package memory
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+func TestTableLocks(t *testing.T) {
+	newCtx := func() *sql.Context {
+		return sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()))
+	}
+	read := func(ctx *sql.Context, table *Table) error {
+		_, err := table.Partitions(ctx)
+		return err
+	}
+	// blocked checks that the function given waits for a lock, until its context is canceled
+	blocked := func(t *testing.T, ctx *sql.Context, f func(ctx *sql.Context) error) {
+		subCtx, cancel := ctx.NewSubContext()
+		go func() {
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+		}()
+		require.ErrorIs(t, f(subCtx), context.Canceled)
+	}
+
+	setup := func() (*Table, *sql.Context, *sql.Context) {
+		table := NewTable("t", sql.NewPrimaryKeySchema(sql.Schema{{Name: "i", Type: types.Int64, Source: "t", PrimaryKey: true}}), nil)
+		ctx := newCtx()
+		require.NoError(t, table.Insert(ctx, sql.NewRow(int64(1))))
+		return table, ctx, newCtx()
+	}
+
+	t.Run("read", func(t *testing.T) {
+		table, owner, other := setup()
+		require.NoError(t, table.Lock(owner, false))
+
+		// The locking session can only read the table
+		require.NoError(t, read(owner, table))
+		require.True(t, sql.ErrTableNotLockedForWrite.Is(table.Insert(owner, sql.NewRow(int64(2)))))
+
+		// Other sessions can read it, but they wait to write it
+		require.NoError(t, read(other, table))
+		require.NoError(t, table.Lock(other, false))
+		require.NoError(t, table.Unlock(other, other.ID()))
+		blocked(t, other, func(ctx *sql.Context) error {
+			return table.Insert(ctx, sql.NewRow(int64(2)))
+		})
+		blocked(t, other, func(ctx *sql.Context) error {
+			return table.Lock(ctx, true)
+		})
+
+		require.NoError(t, table.Unlock(owner, owner.ID()))
+		require.NoError(t, table.Insert(other, sql.NewRow(int64(2))))
+	})
+
+	t.Run("read local", func(t *testing.T) {
+		table, owner, other := setup()
+		require.NoError(t, table.LockReadLocal(owner))
+
+		// Other sessions can insert rows, but not delete them
+		require.NoError(t, table.Insert(other, sql.NewRow(int64(2))))
+		blocked(t, other, func(ctx *sql.Context) error {
+			return table.newTableEditor().Delete(ctx, sql.NewRow(int64(1)))
+		})
+		blocked(t, other, func(ctx *sql.Context) error {
+			_, err := table.Truncate(ctx)
+			return err
+		})
+		require.NoError(t, table.Unlock(owner, owner.ID()))
+	})
+
+	t.Run("write", func(t *testing.T) {
+		table, owner, other := setup()
+		require.NoError(t, table.Lock(owner, true))
+
+		// The locking session can read and write the table, while other sessions can't use it at all
+		require.NoError(t, read(owner, table))
+		require.NoError(t, table.Insert(owner, sql.NewRow(int64(2))))
+		blocked(t, other, func(ctx *sql.Context) error {
+			return read(ctx, table)
+		})
+		blocked(t, other, func(ctx *sql.Context) error {
+			return table.Lock(ctx, false)
+		})
+
+		// A waiting session proceeds once the lock is released
+		done := make(chan error)
+		go func() {
+			done <- read(other, table)
+		}()
+		time.Sleep(10 * time.Millisecond)
+		require.NoError(t, table.Unlock(owner, owner.ID()))
+		require.NoError(t, <-done)
+	})
+
+	t.Run("locked during a statement", func(t *testing.T) {
+		table, owner, other := setup()
+
+		// Locks are checked once per statement, so one that's taken while it runs doesn't fail it partway
+		editor := table.newTableEditor()
+		editor.StatementBegin(other)
+		require.NoError(t, editor.Delete(other, sql.NewRow(int64(1))))
+		require.NoError(t, table.Lock(owner, false))
+		require.NoError(t, editor.Insert(other, sql.NewRow(int64(2))))
+		require.NoError(t, editor.Update(other, sql.NewRow(int64(2)), sql.NewRow(int64(3))))
+		require.NoError(t, editor.StatementComplete(other))
+
+		// But the next statement waits for it
+		editor.StatementBegin(other)
+		blocked(t, other, func(ctx *sql.Context) error {
+			return editor.Insert(ctx, sql.NewRow(int64(4)))
+		})
+		require.NoError(t, table.Unlock(owner, owner.ID()))
+	})
+
+	t.Run("lock wait timeout", func(t *testing.T) {
+		table, owner, other := setup()
+		require.NoError(t, table.Lock(owner, true))
+		require.NoError(t, other.SetSessionVariable(other, "lock_wait_timeout", int64(1)))
+		require.True(t, sql.ErrLockWaitTimeout.Is(read(other, table)))
+	})
+}
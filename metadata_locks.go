@@ -0,0 +1,280 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+)
+
+// metadataLockRequests returns the metadata locks the analyzed statement given needs on each table it uses. Tables
+// that are read get a shared read lock, tables that are written get a shared write lock, and tables whose definition
+// is changed, created or dropped get an exclusive lock. The statements of the procedures called get the locks they
+// need as well, since they run as part of the CALL statement.
+func metadataLockRequests(node sql.Node) map[sql.MDLKey]sql.MDLType {
+	requests := make(map[sql.MDLKey]sql.MDLType)
+	add := func(database, table string, typ sql.MDLType) {
+		if database == "" || table == "" || isSystemSchema(database) {
+			return
+		}
+		key := sql.NewMDLKey(database, table)
+		if held, ok := requests[key]; !ok || typ > held {
+			requests[key] = typ
+		}
+	}
+	addTable := func(rt *plan.ResolvedTable, typ sql.MDLType) {
+		if rt.Database != nil {
+			add(rt.Database.Name(), rt.Name(), typ)
+		}
+	}
+
+	var visit func(node sql.Node)
+	var walk func(node sql.Node, typ sql.MDLType)
+	called := make(map[*plan.Procedure]bool)
+	walk = func(node sql.Node, typ sql.MDLType) {
+		transform.Inspect(node, func(n sql.Node) bool {
+			if ex, ok := n.(sql.Expressioner); ok {
+				for _, e := range ex.Expressions() {
+					sql.Inspect(e, func(e sql.Expression) bool {
+						if sq, ok := e.(*plan.Subquery); ok && sq.Query != nil {
+							walk(sq.Query, sql.MDLSharedRead)
+						}
+						return true
+					})
+				}
+			}
+			switch n := n.(type) {
+			case *plan.ResolvedTable:
+				addTable(n, typ)
+			case *plan.IndexedTableAccess:
+				addTable(n.ResolvedTable, typ)
+			case *plan.InsertInto:
+				walk(n.Destination, sql.MDLSharedWrite)
+				walk(n.Source, sql.MDLSharedRead)
+				return false
+			case *plan.Update, *plan.DeleteFrom:
+				for _, child := range n.Children() {
+					walk(child, sql.MDLSharedWrite)
+				}
+				return false
+			case *plan.Call:
+				// The body of a procedure isn't a child of its call, and procedures may call themselves
+				if n.Procedure != nil && !called[n.Procedure] {
+					called[n.Procedure] = true
+					walk(n.Procedure.Body, sql.MDLSharedRead)
+				}
+				return false
+			case *plan.TableCopier:
+				visit(n)
+				return false
+			}
+			// Procedure bodies may change the definition of tables too
+			if changesTableDefinition(n) {
+				visit(n)
+				return false
+			}
+			return true
+		})
+	}
+
+	visit = func(node sql.Node) {
+		switch n := node.(type) {
+		case *plan.QueryProcess:
+			visit(n.Child())
+		case *plan.TransactionCommittingNode:
+			visit(n.Child())
+		case *plan.Block:
+			for _, child := range n.Children() {
+				visit(child)
+			}
+		case *plan.CreateTable:
+			if n.Database() != nil {
+				add(n.Database().Name(), n.Name(), sql.MDLExclusive)
+			}
+			for _, child := range n.Children() {
+				walk(child, sql.MDLSharedRead)
+			}
+		case *plan.TableCopier:
+			visit(n.Destination())
+			walk(n.Source(), sql.MDLSharedRead)
+		case *plan.CreateView:
+			if n.Database() != nil {
+				add(n.Database().Name(), n.Name, sql.MDLExclusive)
+			}
+			walk(n.Child, sql.MDLSharedRead)
+		case *plan.RenameTable:
+			if n.Database() != nil {
+				for _, name := range append(n.OldNames(), n.NewNames()...) {
+					add(n.Database().Name(), name, sql.MDLExclusive)
+				}
+			}
+		default:
+			if !changesTableDefinition(node) {
+				walk(node, sql.MDLSharedRead)
+				return
+			}
+			// The tables a DDL statement changes are its direct children, while any other table it uses is only read
+			for _, child := range node.Children() {
+				if rt, ok := child.(*plan.ResolvedTable); ok {
+					addTable(rt, sql.MDLExclusive)
+				} else if prt, ok := child.(*plan.ProcedureResolvedTable); ok {
+					addTable(prt.ResolvedTable, sql.MDLExclusive)
+				} else {
+					walk(child, sql.MDLSharedRead)
+				}
+			}
+		}
+	}
+	visit(node)
+	return requests
+}
+
+// changesTableDefinition returns whether the node given is a DDL statement that changes or drops the tables that are
+// its children.
+func changesTableDefinition(node sql.Node) bool {
+	switch node.(type) {
+	case *plan.AlterAutoIncrement, *plan.AlterDefaultSet, *plan.AlterDefaultDrop:
+		return true
+	default:
+		return plan.IsDDLNode(node)
+	}
+}
+
+// isSystemSchema returns whether the database given is one of the system schemas, whose tables can't be changed and
+// so aren't locked.
+func isSystemSchema(database string) bool {
+	return strings.EqualFold(database, sql.InformationSchemaDatabaseName) ||
+		strings.EqualFold(database, sql.PerformanceSchemaDatabaseName)
+}
+
+// acquireMetadataLocks takes the metadata locks given, as returned by metadataLockRequests for the statement given, in
+// the order of their tables so that statements locking the same tables can't deadlock each other. Exclusive locks are
+// first taken as upgradable locks, which let other sessions keep using the table, and then upgraded once every table
+// is locked. Locks are held until the statement is done, or until its transaction ends if it runs in an explicit
+// transaction.
+func (e *Engine) acquireMetadataLocks(ctx *sql.Context, parsed sql.Node, requests map[sql.MDLKey]sql.MDLType) error {
+	if len(requests) == 0 {
+		return nil
+	}
+	keys := mdlKeys(requests)
+	duration := sql.MDLStatement
+	if !plan.IsDDLNode(parsed) && inExplicitTransaction(ctx) {
+		duration = sql.MDLTransaction
+	}
+	timeout := sql.LockWaitTimeout(ctx)
+
+	for _, key := range keys {
+		typ := requests[key]
+		if typ == sql.MDLExclusive {
+			typ = sql.MDLSharedUpgradable
+		}
+		if err := e.MetadataLocks.Acquire(ctx, key, typ, duration, timeout); err != nil {
+			return err
+		}
+	}
+	for _, key := range keys {
+		if requests[key] != sql.MDLExclusive {
+			continue
+		}
+		if err := e.MetadataLocks.Acquire(ctx, key, sql.MDLExclusive, duration, timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mdlKeys returns the tables of the metadata lock requests given, sorted by database and table.
+func mdlKeys(requests map[sql.MDLKey]sql.MDLType) []sql.MDLKey {
+	keys := make([]sql.MDLKey, 0, len(requests))
+	for key := range requests {
+		keys = append(keys, key)
+	}
+	sql.SortMDLKeys(keys)
+	return keys
+}
+
+// releaseMetadataLocks releases the metadata locks of the statement given once it's done, along with the locks of its
+// transaction if the statement ended it.
+func (e *Engine) releaseMetadataLocks(ctx *sql.Context, parsed sql.Node) {
+	if endsTransaction(parsed) || !inExplicitTransaction(ctx) {
+		e.MetadataLocks.ReleaseAll(ctx)
+	} else {
+		e.MetadataLocks.ReleaseStatementLocks(ctx)
+	}
+}
+
+// endsTransaction returns whether the statement given ends the transaction of its session, either explicitly or
+// through an implicit commit.
+func endsTransaction(parsed sql.Node) bool {
+	switch parsed.(type) {
+	case *plan.Commit, *plan.Rollback, *plan.StartTransaction:
+		return true
+	default:
+		return plan.IsDDLNode(parsed)
+	}
+}
+
+// inExplicitTransaction returns whether the session of the context runs in a transaction that outlives its current
+// statement, because it was started explicitly or autocommit is off.
+func inExplicitTransaction(ctx *sql.Context) bool {
+	if ctx.GetIgnoreAutoCommit() {
+		return true
+	}
+	autocommit, err := plan.IsSessionAutocommit(ctx)
+	return err == nil && !autocommit
+}
+
+// statementLockIter releases the metadata locks of a statement, and its hold on the global read lock, once its
+// iterator is closed.
+type statementLockIter struct {
+	iter    sql.RowIter
+	release func()
+}
+
+var _ sql.RowIterTypeSelector = (*statementLockIter)(nil)
+var _ sql.RowIter = (*statementLockIter)(nil)
+var _ sql.RowIter2 = (*statementLockIter)(nil)
+
+func newStatementLockIter(iter sql.RowIter, release func()) *statementLockIter {
+	return &statementLockIter{iter: iter, release: release}
+}
+
+// Next implements the sql.RowIter interface.
+func (i *statementLockIter) Next(ctx *sql.Context) (sql.Row, error) {
+	return i.iter.Next(ctx)
+}
+
+// Next2 implements the sql.RowIter2 interface.
+func (i *statementLockIter) Next2(ctx *sql.Context, frame *sql.RowFrame) error {
+	return i.iter.(sql.RowIter2).Next2(ctx, frame)
+}
+
+// Close implements the sql.RowIter interface.
+func (i *statementLockIter) Close(ctx *sql.Context) error {
+	err := i.iter.Close(ctx)
+	i.release()
+	return err
+}
+
+// IsNode2 implements the sql.RowIterTypeSelector interface.
+func (i *statementLockIter) IsNode2() bool {
+	if selector, ok := i.iter.(sql.RowIterTypeSelector); ok {
+		return selector.IsNode2()
+	}
+	return false
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/parse"
+)
+
+func TestMetadataLockRequests(t *testing.T) {
+	db := memory.NewDatabase("mydb")
+	e := NewDefault(memory.NewDBProvider(db))
+	ctx := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()))
+	ctx.SetCurrentDatabase("mydb")
+	for _, q := range []string{
+		"create table t1 (i int primary key, j int)",
+		"create table t2 (i int primary key, j int)",
+		"create view v as select * from t2",
+		"create procedure p() begin insert into t1 select * from t2; end",
+		"create procedure q() begin call p(); drop table t2; end",
+	} {
+		_, iter, err := e.Query(ctx, q)
+		require.NoError(t, err)
+		_, err = sql.RowIterToRows(ctx, nil, iter)
+		require.NoError(t, err)
+	}
+
+	t1, t2, t3 := sql.NewMDLKey("mydb", "t1"), sql.NewMDLKey("mydb", "t2"), sql.NewMDLKey("mydb", "t3")
+	tests := []struct {
+		query    string
+		expected map[sql.MDLKey]sql.MDLType
+	}{
+		{"select 1", map[sql.MDLKey]sql.MDLType{}},
+		{"select * from information_schema.tables", map[sql.MDLKey]sql.MDLType{}},
+		{"select * from t1 where i = 1", map[sql.MDLKey]sql.MDLType{t1: sql.MDLSharedRead}},
+		{"select * from t1 where j in (select j from T2)", map[sql.MDLKey]sql.MDLType{t1: sql.MDLSharedRead, t2: sql.MDLSharedRead}},
+		{"select * from v", map[sql.MDLKey]sql.MDLType{t2: sql.MDLSharedRead}},
+		{"insert into t1 select * from t2", map[sql.MDLKey]sql.MDLType{t1: sql.MDLSharedWrite, t2: sql.MDLSharedRead}},
+		{"update t1 set j = 1 where i = 2", map[sql.MDLKey]sql.MDLType{t1: sql.MDLSharedWrite}},
+		{"delete from t1 where i in (select i from t2)", map[sql.MDLKey]sql.MDLType{t1: sql.MDLSharedWrite, t2: sql.MDLSharedRead}},
+		{"alter table t1 add column k int", map[sql.MDLKey]sql.MDLType{t1: sql.MDLExclusive}},
+		{"create index idx on t1 (j)", map[sql.MDLKey]sql.MDLType{t1: sql.MDLExclusive}},
+		{"drop table t1, t2", map[sql.MDLKey]sql.MDLType{t1: sql.MDLExclusive, t2: sql.MDLExclusive}},
+		{"truncate t1", map[sql.MDLKey]sql.MDLType{t1: sql.MDLExclusive}},
+		{"create table t3 as select * from t1", map[sql.MDLKey]sql.MDLType{t3: sql.MDLExclusive, t1: sql.MDLSharedRead}},
+		{"rename table t1 to t3", map[sql.MDLKey]sql.MDLType{t1: sql.MDLExclusive, t3: sql.MDLExclusive}},
+		{"call p()", map[sql.MDLKey]sql.MDLType{t1: sql.MDLSharedWrite, t2: sql.MDLSharedRead}},
+		{"call q()", map[sql.MDLKey]sql.MDLType{t1: sql.MDLSharedWrite, t2: sql.MDLExclusive}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			parsed, err := parse.Parse(ctx, tt.query)
+			require.NoError(t, err)
+			analyzed, err := e.analyzeQuery(ctx, tt.query, parsed, nil)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, metadataLockRequests(analyzed))
+		})
+	}
+}
+
+func TestMetadataLocksReanalyzeChangedTables(t *testing.T) {
+	db := memory.NewDatabase("mydb")
+	e := NewDefault(memory.NewDBProvider(db))
+	newCtx := func() *sql.Context {
+		ctx := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()))
+		ctx.SetCurrentDatabase("mydb")
+		return ctx
+	}
+	query := func(ctx *sql.Context, q string) (sql.Schema, error) {
+		sch, iter, err := e.Query(ctx, q)
+		if err != nil {
+			return nil, err
+		}
+		_, err = sql.RowIterToRows(ctx, nil, iter)
+		return sch, err
+	}
+	ctx1, ctx2 := newCtx(), newCtx()
+	_, err := query(ctx1, "create table t1 (i int primary key, j int)")
+	require.NoError(t, err)
+
+	// The second session holds an exclusive lock on the table, as an ALTER TABLE does while it runs, so the SELECT of
+	// the first session is analyzed and then waits for its lock
+	require.NoError(t, e.MetadataLocks.Acquire(ctx2, sql.NewMDLKey("mydb", "t1"), sql.MDLExclusive, sql.MDLStatement, time.Minute))
+	done := make(chan sql.Schema)
+	go func() {
+		sch, err := query(ctx1, "select * from t1")
+		require.NoError(t, err)
+		done <- sch
+	}()
+	require.Eventually(t, func() bool {
+		return len(e.MetadataLocks.Locks()) == 2
+	}, 5*time.Second, time.Millisecond)
+
+	// Once the table is changed and its lock released, the SELECT is analyzed again and sees the new column
+	_, err = query(ctx2, "alter table t1 add column k int")
+	require.NoError(t, err)
+	select {
+	case sch := <-done:
+		require.Len(t, sch, 3)
+	case <-time.After(5 * time.Second):
+		require.Fail(t, "SELECT wasn't run once the table was unlocked")
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/dolthub/vitess/go/mysql"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// statementHintsRegex matches the optimizer hint comment that directly follows the first keyword of a statement,
+// which is the only place MySQL honors the hints that apply to the whole statement, such as SET_VAR.
+var statementHintsRegex = regexp.MustCompile(`(?is)^[\s(]*(select|insert|replace|update|delete)\s*(/\*\+.*?\*/)`)
+
+// statementHints returns the lower-cased keyword the statement given starts with, along with the optimizer hints that
+// follow it. Syntax errors in the hints are reported by the parser, so they're ignored here.
+func statementHints(query string) (string, sql.OptimizerHints) {
+	match := statementHintsRegex.FindStringSubmatch(query)
+	if match == nil {
+		return "", nil
+	}
+	hints, _ := sql.ParseOptimizerHints(match[2])
+	return strings.ToLower(match[1]), hints
+}
+
+// applySetVarHints sets the session variables named by the SET_VAR hints of the statement given for the duration of
+// the statement, and returns a function that restores their previous values, or nil if no variable was set. Hints
+// naming a variable that can't be set this way are ignored with a warning, as is a MAX_EXECUTION_TIME hint on a
+// statement other than a SELECT.
+func applySetVarHints(ctx *sql.Context, query string) func() {
+	keyword, hints := statementHints(query)
+	if keyword != "select" && len(hints.OfType(sql.HintMaxExecutionTime)) > 0 {
+		ctx.Warn(sql.ERWarnUnsupportedMaxExecutionTime, "MAX_EXECUTION_TIME hint is supported by top-level standalone SELECT statements only")
+	}
+
+	var restores []func()
+	seen := make(map[string]struct{})
+	for _, h := range hints.OfType(sql.HintSetVar) {
+		name := strings.ToLower(h.Name)
+		if _, ok := seen[name]; ok {
+			continue
+		}
+		seen[name] = struct{}{}
+
+		sysVar, _, ok := sql.SystemVariables.GetGlobal(name)
+		if !ok || !sysVar.SetVarHintApplies {
+			ctx.Warn(sql.ERNotSettableInSetVarHint, "Variable %s cannot be set using SET_VAR hint.", h.Name)
+			continue
+		}
+		prev, err := ctx.GetSessionVariable(ctx, name)
+		if err != nil {
+			ctx.Warn(sql.ERNotSettableInSetVarHint, "Variable %s cannot be set using SET_VAR hint.", h.Name)
+			continue
+		}
+		if err = ctx.SetSessionVariable(ctx, name, h.Value); err != nil {
+			ctx.Warn(mysql.ERWrongValueForVar, "Variable '%s' can't be set to the value of '%v'", h.Name, h.Value)
+			continue
+		}
+		restores = append(restores, func() {
+			_ = ctx.SetSessionVariable(ctx, name, prev)
+		})
+	}
+
+	if len(restores) == 0 {
+		return nil
+	}
+	return func() {
+		for i := len(restores) - 1; i >= 0; i-- {
+			restores[i]()
+		}
+	}
+}
+
+// setVarIter restores the session variables set by the SET_VAR hints of a statement once its iterator is closed.
+type setVarIter struct {
+	iter    sql.RowIter
+	restore func()
+}
+
+var _ sql.RowIterTypeSelector = (*setVarIter)(nil)
+var _ sql.RowIter = (*setVarIter)(nil)
+var _ sql.RowIter2 = (*setVarIter)(nil)
+
+func newSetVarIter(iter sql.RowIter, restore func()) *setVarIter {
+	return &setVarIter{iter: iter, restore: restore}
+}
+
+// Next implements the sql.RowIter interface.
+func (i *setVarIter) Next(ctx *sql.Context) (sql.Row, error) {
+	return i.iter.Next(ctx)
+}
+
+// Next2 implements the sql.RowIter2 interface.
+func (i *setVarIter) Next2(ctx *sql.Context, frame *sql.RowFrame) error {
+	return i.iter.(sql.RowIter2).Next2(ctx, frame)
+}
+
+// Close implements the sql.RowIter interface.
+func (i *setVarIter) Close(ctx *sql.Context) error {
+	err := i.iter.Close(ctx)
+	i.restore()
+	return err
+}
+
+// IsNode2 implements the sql.RowIterTypeSelector interface.
+func (i *setVarIter) IsNode2() bool {
+	if selector, ok := i.iter.(sql.RowIterTypeSelector); ok {
+		return selector.IsNode2()
+	}
+	return false
+}
@@ -77,8 +77,10 @@ func (pl *ProcessList) AddProcess(
 		Query:      query,
 		Progress:   make(map[string]sql.TableProgress),
 		User:       ctx.Session.Client().User,
+		Host:       ctx.Session.Client().Address,
 		StartedAt:  time.Now(),
 		Kill:       cancel,
+		Memory:     ctx.Memory.Budget(),
 	}
 
 	return ctx, nil
@@ -206,6 +208,16 @@ func (pl *ProcessList) RemovePartitionProgress(pid uint64, tableName, partitionN
 	delete(tablePg.PartitionsProgress, partitionName)
 }
 
+// UpdateState sets what the process with the given pid is waiting for, or clears it if the state is empty.
+func (pl *ProcessList) UpdateState(pid uint64, state string) {
+	pl.mu.Lock()
+	defer pl.mu.Unlock()
+
+	if p, ok := pl.procs[pid]; ok {
+		p.State = state
+	}
+}
+
 // Kill terminates all queries for a given connection id.
 func (pl *ProcessList) Kill(connID uint32) {
 	pl.mu.Lock()
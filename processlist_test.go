@@ -47,8 +47,10 @@ func TestProcessList(t *testing.T) {
 			"b": {sql.Progress{Name: "b", Done: 0, Total: 6}, map[string]sql.PartitionProgress{}},
 		},
 		User:      "foo",
+		Host:      "127.0.0.1:34567",
 		Query:     "SELECT foo",
 		StartedAt: p.procs[ctx.Pid()].StartedAt,
+		Memory:    ctx.Memory.Budget(),
 	}
 	require.NotNil(p.procs[ctx.Pid()].Kill)
 	p.procs[ctx.Pid()].Kill = nil
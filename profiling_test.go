@@ -0,0 +1,52 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// TestQueryRecordedWithoutContextQuery checks that the profiles and optimizer traces of queries run by embedders
+// record the query, which their contexts don't carry.
+func TestQueryRecordedWithoutContextQuery(t *testing.T) {
+	db := memory.NewDatabase("mydb")
+	e := NewDefault(memory.NewDBProvider(db))
+	defer e.Close()
+	ctx := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()))
+	ctx.SetCurrentDatabase("mydb")
+	query := func(q string) []sql.Row {
+		_, iter, err := e.Query(ctx, q)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(ctx, nil, iter)
+		require.NoError(t, err)
+		return rows
+	}
+
+	query("create table t (i int primary key)")
+	query("set profiling = 1, optimizer_trace = 'enabled=on'")
+	query("select * from t where i = 1")
+
+	require.Equal(t, []sql.Row{{"select * from t where i = 1"}}, query("select query from information_schema.optimizer_trace"))
+	profiles := query("show profiles")
+	require.Len(t, profiles, 2)
+	require.Equal(t, "select * from t where i = 1", profiles[0][2])
+	require.Equal(t, "select query from information_schema.optimizer_trace", profiles[1][2])
+}
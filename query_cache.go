@@ -0,0 +1,492 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/expression/function"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// queryCacheVariables are the session variables that the result of a query may depend on without naming them, which
+// are part of the key of its result in the query cache.
+var queryCacheVariables = []string{
+	"character_set_client",
+	"character_set_connection",
+	"character_set_results",
+	"collation_connection",
+	"default_week_format",
+	"div_precision_increment",
+	"group_concat_max_len",
+	"lc_time_names",
+	"max_sort_length",
+	"sql_mode",
+	"sql_select_limit",
+	"time_zone",
+}
+
+// queryCacheModifier is the SQL_CACHE or SQL_NO_CACHE modifier of a SELECT statement, which overrides the
+// query_cache_type of the session for the statement.
+type queryCacheModifier byte
+
+const (
+	noQueryCacheModifier queryCacheModifier = iota
+	sqlCache
+	sqlNoCache
+)
+
+// queryCacheRequest is a query whose result is kept in the query cache once it's run.
+type queryCacheRequest struct {
+	key    string
+	tables []sql.MDLKey
+	// version is the version of the cache before the query started.
+	version uint64
+	// limit is the number of bytes the results kept by the cache may use.
+	limit uint64
+}
+
+// queryCacheRequest returns the request to keep the result of the analyzed statement given in the query cache, or nil
+// if it can't be kept. Like MySQL, only SELECT statements whose result only depends on the tables they read, and on
+// the session variables of queryCacheVariables, are cached. Queries run in an explicit transaction aren't cached
+// either, as they may see writes that other sessions can't, or miss those that other sessions can.
+func (e *Engine) queryCacheRequest(ctx *sql.Context, query string, parsed, analyzed sql.Node, bindings map[string]sql.Expression) *queryCacheRequest {
+	if !isReadOnlySelect(parsed) {
+		return nil
+	}
+	cacheType, err := ctx.GetSessionVariable(ctx, sql.QueryCacheTypeSysVarName)
+	if err != nil || cacheType == "OFF" {
+		return nil
+	}
+	_, limit, ok := sql.SystemVariables.GetGlobal(sql.QueryCacheSizeSysVarName)
+	if !ok {
+		return nil
+	}
+	size, err := types.Uint64.Convert(limit)
+	if err != nil || size.(uint64) == 0 {
+		return nil
+	}
+
+	normalized, modifier, ok := normalizeQuery(query)
+	if !ok || modifier == sqlNoCache || cacheType == "DEMAND" && modifier != sqlCache || inExplicitTransaction(ctx) {
+		e.QueryCache.NotCached()
+		return nil
+	}
+	tables, ok := queryCacheTables(analyzed)
+	if !ok {
+		e.QueryCache.NotCached()
+		return nil
+	}
+	key, ok := queryCacheKey(ctx, normalized, bindings)
+	if !ok {
+		e.QueryCache.NotCached()
+		return nil
+	}
+	return &queryCacheRequest{key: key, tables: tables, version: e.QueryCache.Version(), limit: size.(uint64)}
+}
+
+// queryCacheInvalidation returns the function that evicts the results read from the tables written by the analyzed
+// statement given from the query cache, once it's done. Every result is evicted once tables or views are created,
+// changed or dropped, once tables are flushed, or once an external procedure, whose writes can't be known, is called. If the session is in an explicit transaction, the results are
+// evicted again once it's committed or rolled back. Returns nil if the statement doesn't change the results of any query.
+func (e *Engine) queryCacheInvalidation(ctx *sql.Context, parsed, analyzed sql.Node, lockRequests map[sql.MDLKey]sql.MDLType) func() {
+	clear, ends := plan.IsDDLNode(parsed), false
+	switch parsed.(type) {
+	case *plan.FlushTables:
+		clear = true
+	case *plan.Call:
+		clear = callsExternalProcedure(analyzed)
+	case *plan.Commit, *plan.Rollback:
+		ends = true
+	}
+	for _, typ := range lockRequests {
+		if typ == sql.MDLExclusive {
+			clear = true
+		}
+	}
+	tables := writtenTables(analyzed, lockRequests)
+	id := ctx.Session.ID()
+	if !clear && len(tables) == 0 && !e.QueryCache.HasPendingWrites(id) {
+		return nil
+	}
+
+	return func() {
+		inTransaction := inExplicitTransaction(ctx)
+		if clear {
+			e.QueryCache.Clear()
+		} else {
+			e.QueryCache.InvalidateTables(id, tables, inTransaction)
+		}
+		if ends || !inTransaction {
+			e.QueryCache.EndTransaction(id)
+		}
+	}
+}
+
+// callsExternalProcedure returns whether the analyzed statement given calls an external procedure, either itself or
+// through the procedures it calls.
+func callsExternalProcedure(node sql.Node) bool {
+	var external bool
+	inspectCalledProcedures(node, func(n sql.Node) bool {
+		if call, ok := n.(*plan.Call); ok && call.Procedure != nil && call.Procedure.IsExternal() {
+			external = true
+		}
+		return !external
+	})
+	return external
+}
+
+// inspectCalledProcedures inspects the analyzed statement given like transform.Inspect, along with the bodies of the
+// procedures it calls, which aren't children of their calls.
+func inspectCalledProcedures(node sql.Node, f func(sql.Node) bool) {
+	called := make(map[*plan.Procedure]bool)
+	var inspect func(node sql.Node)
+	inspect = func(node sql.Node) {
+		transform.Inspect(node, func(n sql.Node) bool {
+			if !f(n) {
+				return false
+			}
+			if call, ok := n.(*plan.Call); ok && call.Procedure != nil && !called[call.Procedure] {
+				called[call.Procedure] = true
+				inspect(call.Procedure.Body)
+			}
+			return true
+		})
+	}
+	inspect(node)
+}
+
+// normalizeQuery returns the text of the query given without its comments and with its keywords upper-cased and its
+// tokens separated by single spaces, so that queries that only differ in these respects share their cached results.
+// Unlike the digest of a query, its literals are kept. The SQL_CACHE or SQL_NO_CACHE modifier of a SELECT statement
+// is returned separately. Returns false if the query can't be tokenized.
+func normalizeQuery(query string) (string, queryCacheModifier, bool) {
+	tokenizer := sqlparser.NewStringTokenizer(query)
+	tokenizer.SkipSpecialComments = true
+	modifier := noQueryCacheModifier
+	var tokens []string
+	for {
+		typ, val := tokenizer.Scan()
+		if typ == 0 || typ == ';' {
+			break
+		}
+		switch typ {
+		case sqlparser.COMMENT:
+			continue
+		case sqlparser.LEX_ERROR:
+			return "", modifier, false
+		case sqlparser.SQL_CACHE, sqlparser.SQL_NO_CACHE:
+			if isSelectKeyword(tokens) {
+				modifier = sqlCache
+				if typ == sqlparser.SQL_NO_CACHE {
+					modifier = sqlNoCache
+				}
+				continue
+			}
+			tokens = append(tokens, strings.ToUpper(string(val)))
+		case sqlparser.STRING:
+			tokens = append(tokens, strconv.Quote(string(val)))
+		case sqlparser.ID:
+			tokens = append(tokens, "`"+strings.ReplaceAll(string(val), "`", "``")+"`")
+		default:
+			if val == nil {
+				// Operators have no value, so their text is taken from the query
+				start, end := tokenizer.OldPosition-1, tokenizer.Position-1
+				if start < 0 {
+					start = 0
+				}
+				if end > len(query) || end < start {
+					end = len(query)
+				}
+				tokens = append(tokens, strings.TrimSpace(query[start:end]))
+			} else {
+				tokens = append(tokens, strings.ToUpper(string(val)))
+			}
+		}
+	}
+	return strings.Join(tokens, " "), modifier, true
+}
+
+// isSelectKeyword returns whether the tokens given are the SELECT keyword that starts a statement, which may be
+// preceded by parentheses.
+func isSelectKeyword(tokens []string) bool {
+	if len(tokens) == 0 || tokens[len(tokens)-1] != "SELECT" {
+		return false
+	}
+	for _, t := range tokens[:len(tokens)-1] {
+		if t != "(" {
+			return false
+		}
+	}
+	return true
+}
+
+// queryCacheKey returns the key of the result of the query with the normalized text given in the query cache, which
+// also holds the values of the bindings given, the current database and the session variables of
+// queryCacheVariables. Returns false if a binding isn't a literal.
+func queryCacheKey(ctx *sql.Context, normalized string, bindings map[string]sql.Expression) (string, bool) {
+	var sb strings.Builder
+	sb.WriteString(normalized)
+	fmt.Fprintf(&sb, "\x00%s", ctx.GetCurrentDatabase())
+	for _, name := range queryCacheVariables {
+		if val, err := ctx.GetSessionVariable(ctx, name); err == nil {
+			fmt.Fprintf(&sb, "\x00%s=%v", name, val)
+		}
+	}
+
+	names := make([]string, 0, len(bindings))
+	for name := range bindings {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		lit, ok := bindings[name].(*expression.Literal)
+		if !ok {
+			return "", false
+		}
+		fmt.Fprintf(&sb, "\x00:%s=%s %#v", name, lit.Type(), lit.Value())
+	}
+	return sb.String(), true
+}
+
+// queryCacheTables returns the tables read by the analyzed query given, and whether its result may be cached: it
+// doesn't read the tables of system databases or temporary tables, it doesn't use variables or functions whose result
+// may change between runs, and it doesn't count the rows it would return without a LIMIT.
+func queryCacheTables(node sql.Node) ([]sql.MDLKey, bool) {
+	cacheable := true
+	seen := make(map[sql.MDLKey]struct{})
+	addTable := func(rt *plan.ResolvedTable) {
+		if plan.IsDualTable(rt.Table) {
+			return
+		}
+		if rt.Database == nil || isSystemSchema(rt.Database.Name()) ||
+			strings.EqualFold(rt.Database.Name(), "mysql") || isTemporaryTable(rt.Table) {
+			cacheable = false
+			return
+		}
+		seen[sql.NewMDLKey(rt.Database.Name(), rt.Name())] = struct{}{}
+	}
+
+	var walk func(node sql.Node)
+	walk = func(node sql.Node) {
+		transform.Inspect(node, func(n sql.Node) bool {
+			if ex, ok := n.(sql.Expressioner); ok {
+				for _, e := range ex.Expressions() {
+					sql.Inspect(e, func(e sql.Expression) bool {
+						switch e := e.(type) {
+						case *plan.Subquery:
+							if e.Query != nil {
+								walk(e.Query)
+							}
+						case *expression.UserVar, *expression.SystemVar, *expression.ProcedureParam, *function.Sleep,
+							*function.GetLock, *function.IsUsedLock, *function.IsFreeLock, function.ReleaseAllLocks,
+							*function.ReleaseLock:
+							cacheable = false
+						case sql.NonDeterministicExpression:
+							if e.IsNonDeterministic() {
+								cacheable = false
+							}
+						}
+						return cacheable
+					})
+				}
+			}
+			switch n := n.(type) {
+			case *plan.ResolvedTable:
+				addTable(n)
+			case *plan.IndexedTableAccess:
+				addTable(n.ResolvedTable)
+			case sql.TableFunction:
+				cacheable = false
+			case *plan.Limit:
+				cacheable = cacheable && !n.CalcFoundRows
+			case *plan.TopN:
+				cacheable = cacheable && !n.CalcFoundRows
+			}
+			return cacheable
+		})
+	}
+	walk(node)
+	if !cacheable {
+		return nil, false
+	}
+
+	tables := make([]sql.MDLKey, 0, len(seen))
+	for t := range seen {
+		tables = append(tables, t)
+	}
+	return tables, true
+}
+
+// isTemporaryTable returns whether the table given, or the table it wraps, is a temporary table.
+func isTemporaryTable(table sql.Table) bool {
+	for {
+		if tt, ok := table.(sql.TemporaryTable); ok && tt.IsTemporary() {
+			return true
+		}
+		wrapper, ok := table.(sql.TableWrapper)
+		if !ok {
+			return false
+		}
+		table = wrapper.Underlying()
+	}
+}
+
+// writtenTables returns the tables written by the analyzed statement given, which takes the metadata locks given.
+// Along with the tables it writes itself, these are the tables written by the foreign key editors that cascade its
+// changes, including those of the statements of the procedures it calls.
+func writtenTables(node sql.Node, lockRequests map[sql.MDLKey]sql.MDLType) []sql.MDLKey {
+	seen := make(map[sql.MDLKey]struct{})
+	for key, typ := range lockRequests {
+		if typ >= sql.MDLSharedWrite {
+			seen[key] = struct{}{}
+		}
+	}
+
+	visited := make(map[*plan.ForeignKeyEditor]struct{})
+	var addCascades func(editor *plan.ForeignKeyEditor)
+	addCascades = func(editor *plan.ForeignKeyEditor) {
+		if _, ok := visited[editor]; ok || editor == nil {
+			return
+		}
+		visited[editor] = struct{}{}
+		for _, action := range editor.RefActions {
+			seen[sql.NewMDLKey(action.ForeignKey.Database, action.ForeignKey.Table)] = struct{}{}
+			addCascades(action.Editor)
+		}
+	}
+	inspectCalledProcedures(node, func(n sql.Node) bool {
+		if handler, ok := n.(*plan.ForeignKeyHandler); ok {
+			addCascades(handler.Editor)
+		}
+		return true
+	})
+
+	tables := make([]sql.MDLKey, 0, len(seen))
+	for t := range seen {
+		tables = append(tables, t)
+	}
+	return tables
+}
+
+// queryCacheResult is the plan of a query answered from the query cache, which returns the rows kept for it.
+type queryCacheResult struct {
+	schema sql.Schema
+	rows   []sql.Row
+}
+
+var _ sql.Node = (*queryCacheResult)(nil)
+
+// withQueryCacheResult returns the analyzed query given with its plan replaced by the rows kept for it in the query
+// cache. The process of the query is still tracked.
+func withQueryCacheResult(analyzed sql.Node, rows []sql.Row) sql.Node {
+	result := &queryCacheResult{schema: analyzed.Schema(), rows: rows}
+	if qp, ok := analyzed.(*plan.QueryProcess); ok {
+		return plan.NewQueryProcess(result, qp.Notify)
+	}
+	return result
+}
+
+// Resolved implements the sql.Node interface.
+func (r *queryCacheResult) Resolved() bool {
+	return true
+}
+
+// String implements the sql.Node interface.
+func (r *queryCacheResult) String() string {
+	return "QueryCacheResult"
+}
+
+// Schema implements the sql.Node interface.
+func (r *queryCacheResult) Schema() sql.Schema {
+	return r.schema
+}
+
+// Children implements the sql.Node interface.
+func (r *queryCacheResult) Children() []sql.Node {
+	return nil
+}
+
+// RowIter implements the sql.Node interface.
+func (r *queryCacheResult) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	return sql.RowsToRowIter(r.rows...), nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (r *queryCacheResult) WithChildren(children ...sql.Node) (sql.Node, error) {
+	return plan.NillaryWithChildren(r, children...)
+}
+
+// CheckPrivileges implements the sql.Node interface. The privileges of the query were checked when it was analyzed.
+func (r *queryCacheResult) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return true
+}
+
+// queryCacheIter keeps the result of a query in the query cache once it's been read to the end.
+type queryCacheIter struct {
+	iter     sql.RowIter
+	cache    *sql.QueryCache
+	request  *queryCacheRequest
+	rows     []sql.Row
+	size     uint64
+	complete bool
+}
+
+var _ sql.RowIter = (*queryCacheIter)(nil)
+
+func newQueryCacheIter(iter sql.RowIter, cache *sql.QueryCache, request *queryCacheRequest) *queryCacheIter {
+	return &queryCacheIter{iter: iter, cache: cache, request: request}
+}
+
+// Next implements the sql.RowIter interface. Once the result is larger than the cache, it's no longer collected.
+func (i *queryCacheIter) Next(ctx *sql.Context) (sql.Row, error) {
+	row, err := i.iter.Next(ctx)
+	if i.request == nil {
+		return row, err
+	}
+	if err == io.EOF {
+		i.complete = true
+	} else if err != nil {
+		i.request = nil
+	} else {
+		i.size += sql.RowMemorySize(row)
+		if i.size > i.request.limit {
+			i.cache.NotCached()
+			i.request, i.rows = nil, nil
+		} else {
+			i.rows = append(i.rows, row.Copy())
+		}
+	}
+	return row, err
+}
+
+// Close implements the sql.RowIter interface.
+func (i *queryCacheIter) Close(ctx *sql.Context) error {
+	err := i.iter.Close(ctx)
+	if i.request != nil && i.complete && err == nil {
+		i.cache.Put(i.request.key, i.request.tables, i.request.version, i.rows, i.request.limit)
+	}
+	return err
+}
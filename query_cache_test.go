@@ -0,0 +1,191 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sqle
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+func TestQueryCache(t *testing.T) {
+	db := memory.NewDatabase("mydb")
+	e := NewDefault(memory.NewDBProvider(db))
+	defer e.Close()
+	newCtx := func() *sql.Context {
+		ctx := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()))
+		ctx.SetCurrentDatabase("mydb")
+		return ctx
+	}
+	query := func(ctx *sql.Context, q string) []sql.Row {
+		_, iter, err := e.Query(ctx, q)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(ctx, nil, iter)
+		require.NoError(t, err)
+		return rows
+	}
+	stats := func() map[string]uint64 {
+		return e.QueryCache.StatusVariables()
+	}
+
+	ctx := newCtx()
+	query(ctx, "create table t (i int primary key, j int)")
+	query(ctx, "create table u (i int primary key)")
+	query(ctx, "insert into t values (1, 1), (2, 2)")
+	query(ctx, "insert into u values (1)")
+
+	// The cache is off by default
+	query(ctx, "select * from t")
+	require.Equal(t, uint64(0), stats()["Qcache_misses"])
+
+	query(ctx, "set query_cache_type = ON")
+	require.Equal(t, []sql.Row{{int32(1), int32(1)}, {int32(2), int32(2)}}, query(ctx, "select * from t order by i"))
+	require.Equal(t, uint64(1), stats()["Qcache_misses"])
+	require.Equal(t, uint64(1), stats()["Qcache_inserts"])
+
+	// Queries that only differ in case, spacing and comments share their results
+	require.Equal(t, []sql.Row{{int32(1), int32(1)}, {int32(2), int32(2)}}, query(ctx, "SELECT *  FROM t /* dashboard */ ORDER BY i"))
+	require.Equal(t, uint64(1), stats()["Qcache_hits"])
+	query(ctx, "select i from u")
+	require.Equal(t, uint64(2), stats()["Qcache_queries_in_cache"])
+
+	// Writes evict the results of the tables they write, for every session
+	other := newCtx()
+	query(other, "insert into t values (3, 3)")
+	require.Equal(t, uint64(1), stats()["Qcache_queries_in_cache"])
+	require.Equal(t, []sql.Row{{int32(1), int32(1)}, {int32(2), int32(2)}, {int32(3), int32(3)}}, query(ctx, "select * from t order by i"))
+	require.Equal(t, uint64(1), stats()["Qcache_hits"])
+	query(ctx, "select i from u")
+	require.Equal(t, uint64(2), stats()["Qcache_hits"])
+
+	// The result depends on the session variables of the query
+	query(other, "set query_cache_type = ON")
+	query(other, "set sql_select_limit = 1")
+	require.Equal(t, []sql.Row{{int32(1), int32(1)}}, query(other, "select * from t order by i"))
+	require.Equal(t, uint64(2), stats()["Qcache_hits"])
+
+	// SQL_NO_CACHE and nondeterministic functions keep a query from being cached
+	notCached := stats()["Qcache_not_cached"]
+	query(ctx, "select sql_no_cache * from t order by i")
+	query(ctx, "select i, rand() from u")
+	require.Equal(t, notCached+2, stats()["Qcache_not_cached"])
+	require.Equal(t, uint64(2), stats()["Qcache_hits"])
+
+	// Only SQL_CACHE queries are cached on demand
+	query(ctx, "set query_cache_type = DEMAND")
+	query(ctx, "select j from t where i = 1")
+	query(ctx, "select j from t where i = 1")
+	require.Equal(t, uint64(2), stats()["Qcache_hits"])
+	query(ctx, "select sql_cache j from t where i = 2")
+	query(ctx, "select sql_cache j from t where i = 2")
+	require.Equal(t, uint64(3), stats()["Qcache_hits"])
+
+	// Writes in an explicit transaction evict results again once the transaction ends
+	query(ctx, "set query_cache_type = ON")
+	query(ctx, "set autocommit = 0")
+	query(ctx, "update u set i = 2")
+	query(ctx, "select i from u")
+	require.True(t, e.QueryCache.HasPendingWrites(ctx.Session.ID()))
+	query(ctx, "commit")
+	require.False(t, e.QueryCache.HasPendingWrites(ctx.Session.ID()))
+	query(ctx, "set autocommit = 1")
+	require.Equal(t, []sql.Row{{int32(2)}}, query(ctx, "select i from u"))
+
+	// DDL evicts every result
+	require.NotZero(t, stats()["Qcache_queries_in_cache"])
+	query(ctx, "alter table u add column k int")
+	require.Equal(t, uint64(0), stats()["Qcache_queries_in_cache"])
+
+	rows := query(ctx, "show status like 'Qcache_hits'")
+	require.Equal(t, []sql.Row{{"Qcache_hits", stats()["Qcache_hits"]}}, rows)
+}
+
+func TestQueryCacheWritesOutsideStatements(t *testing.T) {
+	db := memory.NewDatabase("mydb")
+	e := NewDefault(memory.NewDBProvider(db))
+	defer e.Close()
+	newCtx := func() (*sql.Context, error) {
+		ctx := sql.NewContext(context.Background(), sql.WithSession(sql.NewBaseSession()))
+		ctx.SetCurrentDatabase("mydb")
+		return ctx, nil
+	}
+	ctx, err := newCtx()
+	require.NoError(t, err)
+	query := func(q string) []sql.Row {
+		_, iter, err := e.Query(ctx, q)
+		require.NoError(t, err)
+		rows, err := sql.RowIterToRows(ctx, nil, iter)
+		require.NoError(t, err)
+		return rows
+	}
+	cached := func() uint64 {
+		return e.QueryCache.StatusVariables()["Qcache_queries_in_cache"]
+	}
+
+	query("create table t (i int primary key)")
+	query("create table u (i int primary key)")
+	query("create procedure p() begin insert into t select coalesce(max(i), 0) + 1 from t; end")
+	query("create event once on schedule at '2037-01-02 00:00:00' do insert into t values (10)")
+	query("set query_cache_type = ON")
+	query("select * from u")
+
+	// The statements of a procedure evict the results of the tables they write, but not those of other tables
+	require.Empty(t, query("select * from t"))
+	query("call p()")
+	require.Equal(t, uint64(1), cached())
+	require.Equal(t, []sql.Row{{int32(1)}}, query("select * from t"))
+
+	// So do the statements of events
+	es := &EventScheduler{engine: e, newCtx: newCtx, period: time.Second, mu: &sync.Mutex{}}
+	require.NoError(t, es.runDueEvents(time.Date(2037, 1, 2, 0, 0, 0, 0, time.UTC)))
+	require.Equal(t, uint64(1), cached())
+	require.Equal(t, []sql.Row{{int32(1)}, {int32(10)}}, query("select * from t order by i"))
+
+	// And rows written through the editors of a table, without a statement of the engine
+	table, ok, err := db.GetTableInsensitive(ctx, "t")
+	require.NoError(t, err)
+	require.True(t, ok)
+	require.NoError(t, table.(*memory.Table).Insert(ctx, sql.NewRow(int32(20))))
+	require.Equal(t, uint64(1), cached())
+	require.Equal(t, []sql.Row{{int32(1)}, {int32(10)}, {int32(20)}}, query("select * from t order by i"))
+}
+
+func TestNormalizeQuery(t *testing.T) {
+	tests := []struct {
+		query      string
+		normalized string
+		modifier   queryCacheModifier
+	}{
+		{"select * from t", "SELECT * FROM `t`", noQueryCacheModifier},
+		{"SELECT  *\n FROM `t` -- comment", "SELECT * FROM `t`", noQueryCacheModifier},
+		{"select sql_cache a from t where b = 'x'", "SELECT `a` FROM `t` WHERE `b` = \"x\"", sqlCache},
+		{"(select /* hint */ sql_no_cache a from t where b <= 1.5)", "( SELECT `a` FROM `t` WHERE `b` <= 1.5 )", sqlNoCache},
+		{"select a from t where b = 'X';", "SELECT `a` FROM `t` WHERE `b` = \"X\"", noQueryCacheModifier},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			normalized, modifier, ok := normalizeQuery(tt.query)
+			require.True(t, ok)
+			require.Equal(t, tt.normalized, normalized)
+			require.Equal(t, tt.modifier, modifier)
+		})
+	}
+}
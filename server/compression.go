@@ -0,0 +1,452 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/klauspost/compress/zstd"
+)
+
+// The compressed client/server protocol wraps the regular protocol's packets in compressed packets. Each compressed
+// packet has a 7 byte header: the 3 byte length of its payload, a 1 byte sequence number and the 3 byte length of the
+// payload once uncompressed, which is 0 when the payload was sent as is. A compressed packet may hold any number of
+// regular packets, or only part of one. Compression is negotiated during the handshake with the CLIENT_COMPRESS (zlib)
+// and CLIENT_ZSTD_COMPRESSION_ALGORITHM (zstd) capability flags, and begins once the client has authenticated.
+//
+// The vitess connection that the server is built on has no support for compression, so it's implemented here by
+// wrapping the net.Conn given to vitess: compressedConn advertises the capabilities in the server's handshake packet,
+// reads the client's choice from its handshake response, and after the OK packet that completes authentication it
+// compresses everything vitess writes and decompresses everything it reads.
+
+const (
+	CompressionAlgorithmZlib         = "zlib"
+	CompressionAlgorithmZstd         = "zstd"
+	CompressionAlgorithmUncompressed = "uncompressed"
+
+	// capabilityClientCompress is CLIENT_COMPRESS, which vitess doesn't define.
+	capabilityClientCompress = 1 << 5
+	// capabilityClientZstdCompressionAlgorithm is CLIENT_ZSTD_COMPRESSION_ALGORITHM, which vitess doesn't define.
+	capabilityClientZstdCompressionAlgorithm = 1 << 26
+
+	compressedPacketHeaderLength = 7
+	maxCompressedPayloadLength   = 1<<24 - 1
+	// payloads shorter than this are sent uncompressed, as MySQL does
+	minCompressLength = 50
+	// defaultZstdCompressionLevel is the level used when neither the server nor the client specify one
+	defaultZstdCompressionLevel = 3
+)
+
+// compressionConfig is the server's protocol compression configuration.
+type compressionConfig struct {
+	zlib      bool
+	zstd      bool
+	zstdLevel int
+}
+
+// newCompressionConfig returns the compression configuration for the server config given, or nil if the server
+// doesn't offer compression.
+func newCompressionConfig(cfg Config) (*compressionConfig, error) {
+	if cfg.ZstdCompressionLevel < 0 || cfg.ZstdCompressionLevel > 22 {
+		return nil, fmt.Errorf("invalid zstd compression level %d, must be between 1 and 22", cfg.ZstdCompressionLevel)
+	}
+
+	cc := &compressionConfig{zstdLevel: cfg.ZstdCompressionLevel}
+	for _, algorithm := range cfg.CompressionAlgorithms {
+		switch strings.ToLower(strings.TrimSpace(algorithm)) {
+		case CompressionAlgorithmZlib:
+			cc.zlib = true
+		case CompressionAlgorithmZstd:
+			cc.zstd = true
+		case CompressionAlgorithmUncompressed, "":
+		default:
+			return nil, fmt.Errorf("unknown protocol compression algorithm: %s", algorithm)
+		}
+	}
+
+	// Compression would have to happen inside of TLS, which is terminated by vitess
+	if (!cc.zlib && !cc.zstd) || cfg.TLSConfig != nil {
+		return nil, nil
+	}
+	return cc, nil
+}
+
+// algorithms returns the algorithms offered to clients, as the value of @@protocol_compression_algorithms. A nil
+// configuration offers no compression.
+func (cc *compressionConfig) algorithms() string {
+	var algorithms []string
+	if cc != nil && cc.zlib {
+		algorithms = append(algorithms, CompressionAlgorithmZlib)
+	}
+	if cc != nil && cc.zstd {
+		algorithms = append(algorithms, CompressionAlgorithmZstd)
+	}
+	return strings.Join(append(algorithms, CompressionAlgorithmUncompressed), ",")
+}
+
+// capabilities returns the capability flags to advertise to clients.
+func (cc *compressionConfig) capabilities() uint32 {
+	var capabilities uint32
+	if cc.zlib {
+		capabilities |= capabilityClientCompress
+	}
+	if cc.zstd {
+		capabilities |= capabilityClientZstdCompressionAlgorithm
+	}
+	return capabilities
+}
+
+// compressedConn is a net.Conn that negotiates protocol compression with the client during the handshake, and then
+// compresses and decompresses the packets written and read by the server.
+type compressedConn struct {
+	net.Conn
+	cfg    *compressionConfig
+	reader *bufio.Reader
+
+	// mu guards the handshake state, which is shared by reads and writes
+	mu sync.Mutex
+	// negotiating is true until the handshake is over
+	negotiating bool
+	// serverPackets is the number of server packets written during the handshake
+	serverPackets int
+	// pendingWrite holds written bytes of the handshake that don't yet make a complete packet
+	pendingWrite []byte
+	// clientHandshake accumulates the client's handshake response, until it has been read in full
+	clientHandshake     []byte
+	clientHandshakeRead bool
+	// algorithm is the compression algorithm the client chose, if any
+	algorithm string
+	zstdLevel int
+	// compressing is set once authentication completes, if the client chose to use compression
+	compressing bool
+
+	// sequence is the sequence number of the next compressed packet to write
+	sequence     uint8
+	decompressed []byte
+	zlibWriter   *zlib.Writer
+	zstdEncoder  *zstd.Encoder
+	zstdDecoder  *zstd.Decoder
+}
+
+var _ net.Conn = (*compressedConn)(nil)
+
+func newCompressedConn(conn net.Conn, cfg *compressionConfig) *compressedConn {
+	return &compressedConn{
+		Conn:        conn,
+		cfg:         cfg,
+		reader:      bufio.NewReaderSize(conn, mysql.DefaultConnBufferSize),
+		negotiating: true,
+	}
+}
+
+// Read implements net.Conn.
+func (c *compressedConn) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	compressing := c.compressing
+	negotiating := c.negotiating && !c.clientHandshakeRead
+	c.mu.Unlock()
+
+	if compressing {
+		return c.readCompressed(p)
+	}
+
+	n, err := c.reader.Read(p)
+	if negotiating && n > 0 {
+		c.readClientHandshake(p[:n])
+	}
+	return n, err
+}
+
+// Write implements net.Conn.
+func (c *compressedConn) Write(p []byte) (int, error) {
+	c.mu.Lock()
+	if c.compressing {
+		c.mu.Unlock()
+		if err := c.writeCompressed(p); err != nil {
+			return 0, err
+		}
+		return len(p), nil
+	}
+	if !c.negotiating {
+		c.mu.Unlock()
+		return c.Conn.Write(p)
+	}
+	defer c.mu.Unlock()
+
+	c.pendingWrite = append(c.pendingWrite, p...)
+	for c.negotiating && len(c.pendingWrite) >= 4 {
+		length := int(uint32(c.pendingWrite[0]) | uint32(c.pendingWrite[1])<<8 | uint32(c.pendingWrite[2])<<16)
+		if len(c.pendingWrite) < 4+length {
+			break
+		}
+
+		packet := c.pendingWrite[:4+length]
+		c.pendingWrite = c.pendingWrite[4+length:]
+		if err := c.writeHandshakePacket(packet); err != nil {
+			return 0, err
+		}
+	}
+
+	if !c.negotiating && len(c.pendingWrite) > 0 {
+		// Anything written after the packet that ended the handshake is compressed if the client asked for it
+		rest := c.pendingWrite
+		c.pendingWrite = nil
+		var err error
+		if c.compressing {
+			err = c.writeCompressed(rest)
+		} else {
+			_, err = c.Conn.Write(rest)
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// writeHandshakePacket writes a complete packet of the server during the handshake, adding the compression
+// capabilities to the server's handshake packet and starting compression after authentication completes.
+func (c *compressedConn) writeHandshakePacket(packet []byte) error {
+	payload := packet[4:]
+	c.serverPackets++
+	if c.serverPackets == 1 {
+		addHandshakeCapabilities(payload, c.cfg.capabilities())
+	} else if len(payload) > 0 && (payload[0] == mysql.OKPacket || payload[0] == mysql.ErrPacket) {
+		// The OK packet completes authentication, and an error packet means that the connection is about to be closed
+		c.negotiating = false
+		if _, err := c.Conn.Write(packet); err != nil {
+			return err
+		}
+		c.compressing = payload[0] == mysql.OKPacket && c.algorithm != ""
+		return nil
+	}
+
+	_, err := c.Conn.Write(packet)
+	return err
+}
+
+// readClientHandshake accumulates the bytes of the client's handshake response given, and records the client's choice
+// of compression algorithm once it's complete.
+func (c *compressedConn) readClientHandshake(p []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.clientHandshake = append(c.clientHandshake, p...)
+	if len(c.clientHandshake) < 4 {
+		return
+	}
+	length := int(uint32(c.clientHandshake[0]) | uint32(c.clientHandshake[1])<<8 | uint32(c.clientHandshake[2])<<16)
+	if len(c.clientHandshake) < 4+length {
+		return
+	}
+
+	payload := c.clientHandshake[4 : 4+length]
+	c.clientHandshake = nil
+	c.clientHandshakeRead = true
+	if len(payload) < 4 {
+		return
+	}
+
+	flags := binary.LittleEndian.Uint32(payload)
+	if flags&mysql.CapabilityClientProtocol41 == 0 {
+		return
+	}
+	if flags&capabilityClientCompress != 0 && c.cfg.zlib {
+		c.algorithm = CompressionAlgorithmZlib
+	} else if flags&capabilityClientZstdCompressionAlgorithm != 0 && c.cfg.zstd {
+		c.algorithm = CompressionAlgorithmZstd
+		// The level requested by the client is the last byte of its handshake response
+		c.zstdLevel = int(payload[len(payload)-1])
+		if c.cfg.zstdLevel != 0 {
+			c.zstdLevel = c.cfg.zstdLevel
+		} else if c.zstdLevel < 1 || c.zstdLevel > 22 {
+			c.zstdLevel = defaultZstdCompressionLevel
+		}
+	}
+}
+
+// addHandshakeCapabilities sets the capability flags given in the payload of a server's handshake packet.
+func addHandshakeCapabilities(payload []byte, capabilities uint32) {
+	// protocol version, then the null terminated server version
+	end := bytes.IndexByte(payload[1:], 0)
+	if end < 0 {
+		return
+	}
+	// connection id, first part of the auth plugin data and a filler
+	pos := 1 + end + 1 + 4 + 8 + 1
+	if len(payload) < pos+7 {
+		return
+	}
+
+	lower := binary.LittleEndian.Uint16(payload[pos:])
+	binary.LittleEndian.PutUint16(payload[pos:], lower|uint16(capabilities))
+	// the upper part of the flags follows the character set and the status flags
+	upper := binary.LittleEndian.Uint16(payload[pos+5:])
+	binary.LittleEndian.PutUint16(payload[pos+5:], upper|uint16(capabilities>>16))
+}
+
+// readCompressed reads decompressed data into the buffer given, reading the next compressed packet if needed.
+func (c *compressedConn) readCompressed(p []byte) (int, error) {
+	for len(c.decompressed) == 0 {
+		if err := c.readCompressedPacket(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, c.decompressed)
+	c.decompressed = c.decompressed[n:]
+	return n, nil
+}
+
+func (c *compressedConn) readCompressedPacket() error {
+	var header [compressedPacketHeaderLength]byte
+	if _, err := io.ReadFull(c.reader, header[:]); err != nil {
+		return err
+	}
+
+	length := int(uint32(header[0]) | uint32(header[1])<<8 | uint32(header[2])<<16)
+	uncompressedLength := int(uint32(header[4]) | uint32(header[5])<<8 | uint32(header[6])<<16)
+	// Replies continue the client's sequence
+	c.sequence = header[3] + 1
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return err
+	}
+
+	if uncompressedLength == 0 {
+		c.decompressed = payload
+		return nil
+	}
+
+	decompressed, err := c.decompress(payload, uncompressedLength)
+	if err != nil {
+		return err
+	}
+	if len(decompressed) != uncompressedLength {
+		return fmt.Errorf("invalid compressed packet: expected %d bytes once uncompressed, got %d", uncompressedLength, len(decompressed))
+	}
+	c.decompressed = decompressed
+	return nil
+}
+
+// decompress decompresses the payload of a compressed packet. The payload may not decompress to more than the
+// uncompressed length given, so that a small packet can't make the server allocate more than a packet can hold.
+func (c *compressedConn) decompress(payload []byte, uncompressedLength int) ([]byte, error) {
+	if c.algorithm == CompressionAlgorithmZstd {
+		if c.zstdDecoder == nil {
+			decoder, err := zstd.NewReader(nil,
+				zstd.WithDecoderConcurrency(1),
+				zstd.WithDecoderMaxMemory(maxCompressedPayloadLength))
+			if err != nil {
+				return nil, err
+			}
+			c.zstdDecoder = decoder
+		}
+		decompressed, err := c.zstdDecoder.DecodeAll(payload, make([]byte, 0, uncompressedLength))
+		if err != nil {
+			return nil, err
+		}
+		if len(decompressed) > uncompressedLength {
+			return nil, fmt.Errorf("invalid compressed packet: expected %d bytes once uncompressed, got %d", uncompressedLength, len(decompressed))
+		}
+		return decompressed, nil
+	}
+
+	r, err := zlib.NewReader(bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	decompressed := bytes.NewBuffer(make([]byte, 0, uncompressedLength))
+	if _, err := decompressed.ReadFrom(io.LimitReader(r, int64(uncompressedLength)+1)); err != nil {
+		return nil, err
+	}
+	if decompressed.Len() > uncompressedLength {
+		return nil, fmt.Errorf("invalid compressed packet: expected %d bytes once uncompressed, got more", uncompressedLength)
+	}
+	return decompressed.Bytes(), nil
+}
+
+// writeCompressed writes the data given in as many compressed packets as needed.
+func (c *compressedConn) writeCompressed(p []byte) error {
+	for len(p) > 0 {
+		chunk := p[:min(len(p), maxCompressedPayloadLength)]
+		p = p[len(chunk):]
+
+		payload, uncompressedLength := chunk, 0
+		if len(chunk) >= minCompressLength {
+			compressed, err := c.compress(chunk)
+			if err != nil {
+				return err
+			}
+			// Data that doesn't compress is sent as is
+			if len(compressed) < len(chunk) {
+				payload, uncompressedLength = compressed, len(chunk)
+			}
+		}
+
+		packet := make([]byte, compressedPacketHeaderLength, compressedPacketHeaderLength+len(payload))
+		packet[0], packet[1], packet[2] = byte(len(payload)), byte(len(payload)>>8), byte(len(payload)>>16)
+		packet[3] = c.sequence
+		packet[4], packet[5], packet[6] = byte(uncompressedLength), byte(uncompressedLength>>8), byte(uncompressedLength>>16)
+		packet = append(packet, payload...)
+		c.sequence++
+
+		if _, err := c.Conn.Write(packet); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *compressedConn) compress(data []byte) ([]byte, error) {
+	if c.algorithm == CompressionAlgorithmZstd {
+		if c.zstdEncoder == nil {
+			encoder, err := zstd.NewWriter(nil,
+				zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(c.zstdLevel)),
+				zstd.WithEncoderConcurrency(1))
+			if err != nil {
+				return nil, err
+			}
+			c.zstdEncoder = encoder
+		}
+		return c.zstdEncoder.EncodeAll(data, nil), nil
+	}
+
+	var buf bytes.Buffer
+	if c.zlibWriter == nil {
+		c.zlibWriter = zlib.NewWriter(&buf)
+	} else {
+		c.zlibWriter.Reset(&buf)
+	}
+	if _, err := c.zlibWriter.Write(data); err != nil {
+		return nil, err
+	}
+	if err := c.zlibWriter.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,341 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"crypto/tls"
+	gosql "database/sql"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dolthub/vitess/go/mysql"
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/require"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// countingConn counts the bytes read from the server by a client.
+type countingConn struct {
+	net.Conn
+	read *int64
+}
+
+func (c countingConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	atomic.AddInt64(c.read, int64(n))
+	return n, err
+}
+
+var bytesReadFromServer int64
+
+func init() {
+	gomysql.RegisterDialContext("countingtcp", func(ctx context.Context, addr string) (net.Conn, error) {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		return countingConn{Conn: conn, read: &bytesReadFromServer}, nil
+	})
+}
+
+func newCompressionTestServer(t *testing.T, algorithms ...string) *Server {
+	db := memory.NewDatabase("mydb")
+	pro := memory.NewDBProvider(db)
+	e := sqle.NewDefault(pro)
+
+	ctx := sql.NewEmptyContext()
+	ctx.SetCurrentDatabase("mydb")
+	for _, query := range []string{
+		"create table t (id int primary key, s varchar(600))",
+		"insert into t with recursive n(i) as (select 1 union all select i + 1 from n where i < 500) select i, repeat('compressible ', 40) from n",
+	} {
+		_, iter, err := e.Query(ctx, query)
+		require.NoError(t, err)
+		_, err = sql.RowIterToRows(ctx, nil, iter)
+		require.NoError(t, err)
+	}
+	e.Analyzer.Catalog.MySQLDb.AddRootAccount()
+
+	s, err := NewDefaultServer(Config{
+		Protocol:              "tcp",
+		Address:               "localhost:0",
+		CompressionAlgorithms: algorithms,
+	}, e)
+	require.NoError(t, err)
+	go s.Start()
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+	return s
+}
+
+// queryWithClient runs a query with a go-sql-driver client, returning the number of rows and the number of bytes read
+// from the server.
+func queryWithClient(t *testing.T, s *Server, dsnParams string, query string) (int, int64) {
+	atomic.StoreInt64(&bytesReadFromServer, 0)
+	db, err := gosql.Open("mysql", fmt.Sprintf("root:@countingtcp(%s)/mydb?%s", s.Listener.Addr().String(), dsnParams))
+	require.NoError(t, err)
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	rows, err := db.Query(query)
+	require.NoError(t, err)
+	count := 0
+	for rows.Next() {
+		var id int
+		var str string
+		require.NoError(t, rows.Scan(&id, &str))
+		require.Equal(t, strings.Repeat("compressible ", 40), str)
+		count++
+	}
+	require.NoError(t, rows.Err())
+	require.NoError(t, rows.Close())
+
+	// a second statement checks that the compressed sequence carries on correctly
+	var sum int
+	require.NoError(t, db.QueryRow("select sum(id) from t where id <= 10").Scan(&sum))
+	require.Equal(t, 55, sum)
+
+	return count, atomic.LoadInt64(&bytesReadFromServer)
+}
+
+func TestCompressedProtocol(t *testing.T) {
+	s := newCompressionTestServer(t, CompressionAlgorithmZlib, CompressionAlgorithmZstd, CompressionAlgorithmUncompressed)
+
+	count, uncompressedBytes := queryWithClient(t, s, "compress=false", "select * from t order by id")
+	require.Equal(t, 500, count)
+
+	count, compressedBytes := queryWithClient(t, s, "compress=true", "select * from t order by id")
+	require.Equal(t, 500, count)
+	require.Less(t, compressedBytes*4, uncompressedBytes)
+
+	// Small results are sent uncompressed inside of compressed packets
+	count, _ = queryWithClient(t, s, "compress=true", "select * from t where id = 1")
+	require.Equal(t, 1, count)
+
+	require.Equal(t, "zlib,zstd,uncompressed", compressionAlgorithmsVariable(t, s))
+}
+
+// compressionAlgorithmsVariable returns the value of @@protocol_compression_algorithms reported by the server.
+func compressionAlgorithmsVariable(t *testing.T, s *Server) string {
+	db, err := gosql.Open("mysql", fmt.Sprintf("root:@tcp(%s)/mydb", s.Listener.Addr().String()))
+	require.NoError(t, err)
+	defer db.Close()
+
+	var algorithms string
+	require.NoError(t, db.QueryRow("select @@global.protocol_compression_algorithms").Scan(&algorithms))
+	_, err = db.Exec("set global protocol_compression_algorithms = 'zlib'")
+	require.Error(t, err)
+	return algorithms
+}
+
+func TestCompressedProtocolDisabled(t *testing.T) {
+	s := newCompressionTestServer(t)
+
+	count, uncompressedBytes := queryWithClient(t, s, "compress=false", "select * from t order by id")
+	require.Equal(t, 500, count)
+
+	// The client falls back to the uncompressed protocol when the server doesn't offer compression
+	count, bytesRead := queryWithClient(t, s, "compress=true", "select * from t order by id")
+	require.Equal(t, 500, count)
+	require.Equal(t, uncompressedBytes, bytesRead)
+
+	require.Equal(t, "uncompressed", compressionAlgorithmsVariable(t, s))
+}
+
+func TestNewCompressionConfig(t *testing.T) {
+	cc, err := newCompressionConfig(Config{})
+	require.NoError(t, err)
+	require.Nil(t, cc)
+
+	cc, err = newCompressionConfig(Config{CompressionAlgorithms: []string{"uncompressed"}})
+	require.NoError(t, err)
+	require.Nil(t, cc)
+
+	cc, err = newCompressionConfig(Config{CompressionAlgorithms: []string{"zlib", "ZSTD"}, ZstdCompressionLevel: 7})
+	require.NoError(t, err)
+	require.Equal(t, &compressionConfig{zlib: true, zstd: true, zstdLevel: 7}, cc)
+	require.Equal(t, "zlib,zstd,uncompressed", cc.algorithms())
+
+	cc, err = newCompressionConfig(Config{CompressionAlgorithms: []string{"zstd"}})
+	require.NoError(t, err)
+	require.Equal(t, "zstd,uncompressed", cc.algorithms())
+
+	cc, err = newCompressionConfig(Config{CompressionAlgorithms: []string{"zlib"}, TLSConfig: &tls.Config{}})
+	require.NoError(t, err)
+	require.Nil(t, cc)
+	require.Equal(t, "uncompressed", cc.algorithms())
+
+	_, err = newCompressionConfig(Config{CompressionAlgorithms: []string{"lz4"}})
+	require.Error(t, err)
+
+	_, err = newCompressionConfig(Config{CompressionAlgorithms: []string{"zstd"}, ZstdCompressionLevel: 23})
+	require.Error(t, err)
+}
+
+// TestZstdCompressedConn runs the handshake and a command over a compressedConn with a fake client that uses zstd,
+// which go-sql-driver doesn't support.
+func TestZstdCompressedConn(t *testing.T) {
+	serverSide, clientSide := net.Pipe()
+	conn := newCompressedConn(serverSide, &compressionConfig{zlib: true, zstd: true})
+	defer conn.Close()
+	defer clientSide.Close()
+
+	writePacket := func(w io.Writer, seq byte, payload []byte) {
+		header := []byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), seq}
+		_, err := w.Write(append(header, payload...))
+		require.NoError(t, err)
+	}
+	readPacket := func(r io.Reader) []byte {
+		header := make([]byte, 4)
+		_, err := io.ReadFull(r, header)
+		require.NoError(t, err)
+		payload := make([]byte, int(header[0])|int(header[1])<<8|int(header[2])<<16)
+		_, err = io.ReadFull(r, payload)
+		require.NoError(t, err)
+		return payload
+	}
+
+	// The server's handshake packet, as written by vitess in two parts
+	handshake := []byte{10}
+	handshake = append(handshake, "8.0.31\x00"...)
+	handshake = append(handshake, 1, 0, 0, 0)
+	handshake = append(handshake, "saltsalt"...)
+	handshake = append(handshake, 0)
+	handshake = binary.LittleEndian.AppendUint16(handshake, uint16(mysql.CapabilityClientProtocol41))
+	handshake = append(handshake, 255, 2, 0)
+	handshake = binary.LittleEndian.AppendUint16(handshake, 0)
+	handshake = append(handshake, 21, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0)
+	go func() {
+		header := []byte{byte(len(handshake)), 0, 0, 0}
+		_, err := conn.Write(header)
+		require.NoError(t, err)
+		_, err = conn.Write(handshake)
+		require.NoError(t, err)
+	}()
+
+	received := readPacket(clientSide)
+	pos := 1 + len("8.0.31\x00") + 4 + 8 + 1
+	capabilities := uint32(binary.LittleEndian.Uint16(received[pos:])) | uint32(binary.LittleEndian.Uint16(received[pos+5:]))<<16
+	require.NotZero(t, capabilities&capabilityClientCompress)
+	require.NotZero(t, capabilities&capabilityClientZstdCompressionAlgorithm)
+
+	// The client's handshake response asks for zstd at level 5, in its last byte
+	response := binary.LittleEndian.AppendUint32(nil, mysql.CapabilityClientProtocol41|capabilityClientZstdCompressionAlgorithm)
+	response = append(response, "more handshake response\x00"...)
+	response = append(response, 5)
+	go writePacket(clientSide, 1, response)
+	require.Equal(t, response, readPacket(conn))
+
+	// The OK packet is sent uncompressed, along with the start of the first compressed packet
+	result := []byte(strings.Repeat("a compressible result ", 100))
+	go func() {
+		var data []byte
+		data = append(data, 7, 0, 0, 2, mysql.OKPacket, 0, 0, 2, 0, 0, 0)
+		data = append(data, result...)
+		_, err := conn.Write(data)
+		require.NoError(t, err)
+	}()
+	require.Equal(t, []byte{mysql.OKPacket, 0, 0, 2, 0, 0, 0}, readPacket(clientSide))
+
+	header := make([]byte, compressedPacketHeaderLength)
+	_, err := io.ReadFull(clientSide, header)
+	require.NoError(t, err)
+	length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	require.Equal(t, byte(0), header[3])
+	require.Equal(t, len(result), int(header[4])|int(header[5])<<8|int(header[6])<<16)
+	require.Less(t, length, len(result))
+	compressed := make([]byte, length)
+	_, err = io.ReadFull(clientSide, compressed)
+	require.NoError(t, err)
+	decoder, err := zstd.NewReader(nil)
+	require.NoError(t, err)
+	defer decoder.Close()
+	decompressed, err := decoder.DecodeAll(compressed, nil)
+	require.NoError(t, err)
+	require.Equal(t, result, decompressed)
+
+	// A compressed command from the client
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	command := append([]byte{byte(len(result) + 1), byte((len(result) + 1) >> 8), 0, 0, 3}, result...)
+	payload := encoder.EncodeAll(command, nil)
+	go func() {
+		header := []byte{byte(len(payload)), byte(len(payload) >> 8), byte(len(payload) >> 16), 0,
+			byte(len(command)), byte(len(command) >> 8), byte(len(command) >> 16)}
+		_, err := clientSide.Write(append(header, payload...))
+		require.NoError(t, err)
+	}()
+	require.Equal(t, command[4:], readPacket(conn))
+
+	// Short replies are sent uncompressed, continuing the client's sequence
+	go func() {
+		_, err := conn.Write([]byte{1, 0, 0, 1, 0})
+		require.NoError(t, err)
+	}()
+	_, err = io.ReadFull(clientSide, header)
+	require.NoError(t, err)
+	require.Equal(t, []byte{5, 0, 0, 1, 0, 0, 0}, header)
+	reply := make([]byte, 5)
+	_, err = io.ReadFull(clientSide, reply)
+	require.NoError(t, err)
+	require.Equal(t, []byte{1, 0, 0, 1, 0}, reply)
+}
+
+func TestDecompressIsBounded(t *testing.T) {
+	serverSide, clientSide := net.Pipe()
+	defer clientSide.Close()
+	conn := newCompressedConn(serverSide, &compressionConfig{zlib: true, zstd: true})
+	defer conn.Close()
+
+	data := make([]byte, 1<<20)
+	var zlibPayload bytes.Buffer
+	w := zlib.NewWriter(&zlibPayload)
+	_, err := w.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	encoder, err := zstd.NewWriter(nil)
+	require.NoError(t, err)
+	zstdPayload := encoder.EncodeAll(data, nil)
+
+	for algorithm, payload := range map[string][]byte{
+		CompressionAlgorithmZlib: zlibPayload.Bytes(),
+		CompressionAlgorithmZstd: zstdPayload,
+	} {
+		t.Run(algorithm, func(t *testing.T) {
+			conn.algorithm = algorithm
+			decompressed, err := conn.decompress(payload, len(data))
+			require.NoError(t, err)
+			require.Equal(t, data, decompressed)
+
+			// A payload that decompresses to more than its declared length is rejected
+			_, err = conn.decompress(payload, 100)
+			require.Error(t, err)
+		})
+	}
+}
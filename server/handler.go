@@ -37,7 +37,10 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/analyzer"
 	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
 	"github.com/dolthub/go-mysql-server/sql/parse"
+	"github.com/dolthub/go-mysql-server/sql/performance_schema"
+	"github.com/dolthub/go-mysql-server/sql/plan"
 	"github.com/dolthub/go-mysql-server/sql/types"
 )
 
@@ -92,11 +95,20 @@ func (h *Handler) NewConnection(c *mysql.Conn) {
 		h.sel.ClientConnected()
 	}
 
+	h.e.Analyzer.Catalog.Instrumentation.ThreadConnected(c.ConnectionID)
+
 	c.DisableClientMultiStatements = h.disableMultiStmts
 	logrus.WithField(sql.ConnectionIdLogField, c.ConnectionID).WithField("DisableClientMultiStatements", c.DisableClientMultiStatements).Infof("NewConnection")
 }
 
 func (h *Handler) ComInitDB(c *mysql.Conn, schemaName string) error {
+	// This is called once the connection has authenticated, so the account's connection limits are checked here. Later
+	// calls for the same connection are not counted again.
+	if connUser, ok := c.UserData.(mysql_db.MysqlConnectionUser); ok {
+		if err := h.e.Analyzer.Catalog.MySQLDb.AccountConnected(c.ConnectionID, connUser.User, connUser.Host); err != nil {
+			return sql.CastSQLError(err)
+		}
+	}
 	return h.sm.SetDB(c, schemaName)
 }
 
@@ -156,6 +168,8 @@ func (h *Handler) ConnectionClosed(c *mysql.Conn) {
 	}
 
 	h.sm.CloseConn(c)
+	h.e.Analyzer.Catalog.MySQLDb.AccountDisconnected(c.ConnectionID)
+	h.e.Analyzer.Catalog.Instrumentation.ThreadDisconnected(c.ConnectionID)
 
 	// If connection was closed, kill its associated queries.
 	ctx.ProcessList.Kill(c.ConnectionID)
@@ -306,6 +320,13 @@ func (h *Handler) doQuery(
 		return "", err
 	}
 
+	// The statement is profiled from here, so that its parsing is included in its profile
+	ctx, profiler := sql.StartQueryProfile(ctx, "starting")
+	defer func() {
+		sql.FinishQueryProfile(ctx, profiler)
+	}()
+	sql.SetProfileStage(ctx, "parsing")
+
 	var remainder string
 	var parsed sql.Node
 	if mode == MultiStmtModeOn {
@@ -319,6 +340,17 @@ func (h *Handler) doQuery(
 	ctx = ctx.WithQuery(query)
 	more := remainder != ""
 
+	instrumentation := h.e.Analyzer.Catalog.Instrumentation
+	event := instrumentation.StatementStarted(ctx, query)
+	var rowsSent, rowsAffected uint64
+	defer func() {
+		instrumentation.StatementFinished(ctx, event, performance_schema.StatementResult{
+			RowsSent:     rowsSent,
+			RowsAffected: rowsAffected,
+			Err:          err,
+		})
+	}()
+
 	queryStr := string(queryLoggingRegex.ReplaceAll([]byte(query), []byte(" ")))
 	if h.maxLoggedQueryLen > 0 && len(queryStr) > h.maxLoggedQueryLen {
 		queryStr = queryStr[:h.maxLoggedQueryLen] + "..."
@@ -341,6 +373,12 @@ func (h *Handler) doQuery(
 		return "", err
 	}
 
+	client := ctx.Session.Client()
+	err = h.e.Analyzer.Catalog.MySQLDb.AccountQueried(client.User, client.Address, isUpdateStatement(parsed))
+	if err != nil {
+		return remainder, err
+	}
+
 	ctx.GetLogger().Tracef("beginning execution")
 
 	var sqlBindings map[string]sql.Expression
@@ -498,6 +536,7 @@ func (h *Handler) doQuery(
 					ctx.GetLogger().Tracef("spooling result row %s", outputRow)
 					r.Rows = append(r.Rows, outputRow)
 					r.RowsAffected++
+					rowsSent++
 				case <-timer.C:
 					if h.readTimeout != 0 {
 						// Cancel and return so Vitess can call the CloseConnection callback
@@ -518,6 +557,7 @@ func (h *Handler) doQuery(
 							panic("Got OkResult mixed with RowResult")
 						}
 						r = resultFromOkResult(row[0].(types.OkResult))
+						rowsAffected = r.RowsAffected
 						continue
 					}
 
@@ -529,6 +569,7 @@ func (h *Handler) doQuery(
 					ctx.GetLogger().Tracef("spooling result row %s", outputRow)
 					r.Rows = append(r.Rows, outputRow)
 					r.RowsAffected++
+					rowsSent++
 				case <-timer.C:
 					if h.readTimeout != 0 {
 						// Cancel and return so Vitess can call the CloseConnection callback
@@ -615,6 +656,22 @@ func isSessionAutocommit(ctx *sql.Context) (bool, error) {
 	return types.ConvertToBool(autoCommitSessionVar)
 }
 
+// isUpdateStatement returns whether the given statement counts against an account's MAX_UPDATES_PER_HOUR limit, which
+// covers any statement that modifies tables, databases or accounts.
+func isUpdateStatement(node sql.Node) bool {
+	if plan.IsDDLNode(node) {
+		return true
+	}
+	switch node.(type) {
+	case *plan.InsertInto, *plan.Update, *plan.DeleteFrom, *plan.LoadData,
+		*plan.CreateUser, *plan.AlterUser, *plan.DropUser, *plan.RenameUser,
+		*plan.CreateRole, *plan.DropRole, *plan.Grant, *plan.GrantRole, *plan.Revoke, *plan.RevokeRole:
+		return true
+	default:
+		return false
+	}
+}
+
 // Call doQuery and cast known errors to SQLError
 func (h *Handler) errorWrappedDoQuery(
 	c *mysql.Conn,
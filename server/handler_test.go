@@ -36,6 +36,7 @@ import (
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/analyzer"
 	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
 	"github.com/dolthub/go-mysql-server/sql/types"
 )
 
@@ -990,6 +991,549 @@ func TestHandlerFoundRowsCapabilities(t *testing.T) {
 	}
 }
 
+func TestHandlerAccountLimits(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+	mysqlDb := e.Analyzer.Catalog.MySQLDb
+	mysqlDb.AddSuperUser("tester", "localhost", "")
+	user := mysqlDb.GetUser("tester", "localhost", false)
+	user.MaxUserConnections = 1
+	user.MaxQuestions = 2
+	user.MaxUpdates = 1
+
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			DefaultSessionBuilder,
+			sql.NoopTracer,
+			func(ctx *sql.Context, db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			sqle.NewProcessList(),
+			"foo",
+		),
+		0,
+		false,
+		0,
+		nil,
+	)
+	noop := func(res *sqltypes.Result, more bool) error {
+		return nil
+	}
+
+	conn1 := newConn(1)
+	conn1.UserData = mysql_db.MysqlConnectionUser{User: "tester", Host: "localhost"}
+	handler.NewConnection(conn1)
+	require.NoError(handler.ComInitDB(conn1, "test"))
+
+	conn2 := newConn(2)
+	conn2.UserData = mysql_db.MysqlConnectionUser{User: "tester", Host: "localhost"}
+	handler.NewConnection(conn2)
+	err := handler.ComInitDB(conn2, "test")
+	require.Error(err)
+	require.Equal(mysql.ERTooManyUserConnections, err.(*mysql.SQLError).Number())
+
+	require.NoError(handler.ComQuery(conn1, "INSERT INTO test VALUES (20000)", noop))
+	err = handler.ComQuery(conn1, "INSERT INTO test VALUES (20001)", noop)
+	require.Error(err)
+	require.Equal(mysql.ERUserLimitReached, err.(*mysql.SQLError).Number())
+	require.NoError(handler.ComQuery(conn1, "SELECT 1", noop))
+	err = handler.ComQuery(conn1, "SELECT 1", noop)
+	require.Error(err)
+	require.Equal(mysql.ERUserLimitReached, err.(*mysql.SQLError).Number())
+
+	// Closing a connection frees it for another
+	handler.ConnectionClosed(conn1)
+	require.NoError(handler.ComInitDB(conn2, "test"))
+}
+
+func TestHandlerPerformanceSchema(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			testSessionBuilder,
+			sql.NoopTracer,
+			func(ctx *sql.Context, db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			sqle.NewProcessList(),
+			"foo",
+		),
+		0,
+		false,
+		0,
+		nil,
+	)
+	noop := func(res *sqltypes.Result, more bool) error {
+		return nil
+	}
+	queryRows := func(c *mysql.Conn, query string) [][]string {
+		var rows [][]string
+		err := handler.ComQuery(c, query, func(res *sqltypes.Result, more bool) error {
+			for _, row := range res.Rows {
+				strs := make([]string, len(row))
+				for i, val := range row {
+					strs[i] = val.ToString()
+				}
+				rows = append(rows, strs)
+			}
+			return nil
+		})
+		require.NoError(err)
+		return rows
+	}
+
+	conn1 := newConn(1)
+	conn1.User = "tester"
+	handler.NewConnection(conn1)
+	require.NoError(handler.ComInitDB(conn1, "test"))
+	conn2 := newConn(2)
+	conn2.User = "tester"
+	handler.NewConnection(conn2)
+	require.NoError(handler.ComInitDB(conn2, "test"))
+
+	require.NoError(handler.ComQuery(conn1, "SELECT * FROM test WHERE c1 + 0 < 5", noop))
+	require.NoError(handler.ComQuery(conn1, "INSERT INTO test VALUES (2000), (2001)", noop))
+	require.NoError(handler.ComQuery(conn1, "select * from test where c1 + 0 < 3", noop))
+	require.NoError(handler.ComQuery(conn1, "SELECT GET_LOCK('perf_lock', 0)", noop))
+	require.Error(handler.ComQuery(conn1, "SELECT * FROM nonexistent", noop))
+	require.NoError(handler.ComQuery(conn2, "SELECT 1", noop))
+
+	require.Equal([][]string{
+		{"statement/sql/select", "SELECT * FROM test WHERE c1 + 0 < 5", "SELECT * FROM `test` WHERE `c1` + ? < ?", "5", "0", "1010", "0", "0"},
+		{"statement/sql/insert", "INSERT INTO test VALUES (2000), (2001)", "INSERT INTO `test` VALUES (...) /* , ... */", "0", "2", "0", "0", "0"},
+		{"statement/sql/select", "select * from test where c1 + 0 < 3", "SELECT * FROM `test` WHERE `c1` + ? < ?", "3", "0", "1012", "0", "0"},
+		{"statement/sql/select", "SELECT GET_LOCK('perf_lock', 0)", "SELECT `GET_LOCK` ( ? , ? )", "1", "0", "0", "0", "0"},
+		{"statement/sql/select", "SELECT * FROM nonexistent", "SELECT * FROM `nonexistent`", "0", "0", "0", "1", "1146"},
+	}, queryRows(conn1, "SELECT event_name, sql_text, digest_text, rows_sent, rows_affected, rows_examined, errors, mysql_errno "+
+		"FROM performance_schema.events_statements_history WHERE thread_id = 1 ORDER BY event_id"))
+
+	require.Equal([][]string{
+		{"SELECT sql_text, end_event_id IS NULL FROM performance_schema.events_statements_current WHERE thread_id = 1", "1"},
+	}, queryRows(conn1, "SELECT sql_text, end_event_id IS NULL FROM performance_schema.events_statements_current WHERE thread_id = 1"))
+
+	require.Equal([][]string{
+		{"test", "SELECT * FROM `test` WHERE `c1` + ? < ?", "2", "8", "2022"},
+	}, queryRows(conn1, "SELECT schema_name, digest_text, count_star, sum_rows_sent, sum_rows_examined "+
+		"FROM performance_schema.events_statements_summary_by_digest WHERE digest_text LIKE 'SELECT * FROM `test`%'"))
+
+	require.Equal([][]string{
+		{"2024", "2022", "2", "2", "0", "0"},
+	}, queryRows(conn1, "SELECT count_star, count_fetch, count_write, count_insert, count_update, count_delete "+
+		"FROM performance_schema.table_io_waits_summary_by_table WHERE object_schema = 'test' AND object_name = 'test'"))
+
+	require.Equal([][]string{
+		{"USER LEVEL LOCK", "perf_lock", "EXCLUSIVE", "1"},
+	}, queryRows(conn2, "SELECT object_type, object_name, lock_type, owner_thread_id FROM performance_schema.metadata_locks"))
+
+	require.Equal([][]string{
+		{"1", "tester", "127.0.0.1", "test", "Sleep"},
+		{"2", "tester", "127.0.0.1", "test", "Query"},
+	}, queryRows(conn2, "SELECT thread_id, processlist_user, processlist_host, processlist_db, processlist_command "+
+		"FROM performance_schema.threads ORDER BY thread_id"))
+
+	require.Equal([][]string{
+		{"Com_insert", "1"},
+		{"Com_select", "11"},
+		{"Connections", "2"},
+		{"Threads_connected", "2"},
+	}, queryRows(conn2, "SELECT variable_name, variable_value FROM performance_schema.global_status "+
+		"WHERE variable_name IN ('Com_insert', 'Com_select', 'Connections', 'Threads_connected') ORDER BY 1"))
+
+	require.Equal([][]string{
+		{"autocommit", "ON"},
+	}, queryRows(conn2, "SELECT variable_name, variable_value FROM performance_schema.session_variables WHERE variable_name = 'autocommit'"))
+
+	handler.ConnectionClosed(conn1)
+	require.Equal([][]string{
+		{"2"},
+	}, queryRows(conn2, "SELECT thread_id FROM performance_schema.threads"))
+	require.Empty(queryRows(conn2, "SELECT * FROM performance_schema.metadata_locks"))
+}
+
+func TestHandlerMetadataLocks(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			testSessionBuilder,
+			sql.NoopTracer,
+			func(ctx *sql.Context, db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			sqle.NewProcessList(),
+			"foo",
+		),
+		0,
+		false,
+		0,
+		nil,
+	)
+	noop := func(res *sqltypes.Result, more bool) error {
+		return nil
+	}
+	queryRows := func(c *mysql.Conn, query string) [][]string {
+		var rows [][]string
+		err := handler.ComQuery(c, query, func(res *sqltypes.Result, more bool) error {
+			for _, row := range res.Rows {
+				strs := make([]string, len(row))
+				for i, val := range row {
+					strs[i] = val.ToString()
+				}
+				rows = append(rows, strs)
+			}
+			return nil
+		})
+		require.NoError(err)
+		return rows
+	}
+	locksQuery := "SELECT object_type, object_schema, object_name, lock_type, lock_duration, lock_status, owner_thread_id " +
+		"FROM performance_schema.metadata_locks"
+
+	conn1 := newConn(1)
+	handler.NewConnection(conn1)
+	require.NoError(handler.ComInitDB(conn1, "test"))
+	conn2 := newConn(2)
+	handler.NewConnection(conn2)
+	require.NoError(handler.ComInitDB(conn2, "test"))
+
+	// Without a transaction, locks are released once the statement is done
+	require.NoError(handler.ComQuery(conn1, "SELECT * FROM test", noop))
+	require.Empty(queryRows(conn2, locksQuery))
+
+	// Within one, they are held until it ends, and keep other sessions from changing the table
+	require.NoError(handler.ComQuery(conn1, "SET autocommit = 0", noop))
+	require.NoError(handler.ComQuery(conn1, "SELECT * FROM test", noop))
+	require.Equal([][]string{
+		{"TABLE", "test", "test", "SHARED_READ", "TRANSACTION", "GRANTED", "1"},
+	}, queryRows(conn2, locksQuery))
+
+	require.NoError(handler.ComQuery(conn2, "SET lock_wait_timeout = 1", noop))
+	err := handler.ComQuery(conn2, "ALTER TABLE test ADD COLUMN c2 int", noop)
+	require.Error(err)
+	require.Equal(mysql.ERLockWaitTimeout, err.(*mysql.SQLError).Number())
+	require.Equal([][]string{
+		{"TABLE", "test", "test", "SHARED_READ", "TRANSACTION", "GRANTED", "1"},
+	}, queryRows(conn2, locksQuery))
+
+	// Rows can still be written, as that doesn't change the definition of the table
+	require.NoError(handler.ComQuery(conn2, "INSERT INTO test VALUES (2000)", noop))
+
+	require.NoError(handler.ComQuery(conn1, "COMMIT", noop))
+	require.Empty(queryRows(conn2, locksQuery))
+	require.NoError(handler.ComQuery(conn2, "ALTER TABLE test ADD COLUMN c2 int", noop))
+
+	// Closing the connection releases the locks of its transaction
+	require.NoError(handler.ComQuery(conn1, "SELECT * FROM test", noop))
+	require.Len(queryRows(conn2, locksQuery), 1)
+	handler.ConnectionClosed(conn1)
+	require.Empty(queryRows(conn2, locksQuery))
+}
+
+func TestHandlerLockTables(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			testSessionBuilder,
+			sql.NoopTracer,
+			func(ctx *sql.Context, db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			sqle.NewProcessList(),
+			"foo",
+		),
+		0,
+		false,
+		0,
+		nil,
+	)
+	noop := func(res *sqltypes.Result, more bool) error {
+		return nil
+	}
+	requireErrorCode := func(code int, err error) {
+		require.Error(err)
+		require.Equal(code, err.(*mysql.SQLError).Number())
+	}
+
+	conn1 := newConn(1)
+	handler.NewConnection(conn1)
+	require.NoError(handler.ComInitDB(conn1, "test"))
+	conn2 := newConn(2)
+	handler.NewConnection(conn2)
+	require.NoError(handler.ComInitDB(conn2, "test"))
+	require.NoError(handler.ComQuery(conn2, "SET lock_wait_timeout = 1", noop))
+
+	// A WRITE lock keeps other sessions from reading the table
+	require.NoError(handler.ComQuery(conn1, "LOCK TABLES test WRITE", noop))
+	requireErrorCode(mysql.ERLockWaitTimeout, handler.ComQuery(conn2, "SELECT * FROM test", noop))
+	require.NoError(handler.ComQuery(conn1, "UNLOCK TABLES", noop))
+	require.NoError(handler.ComQuery(conn2, "SELECT * FROM test", noop))
+
+	// A READ lock only keeps them from writing it, until it's released
+	require.NoError(handler.ComQuery(conn1, "LOCK TABLES test READ", noop))
+	require.NoError(handler.ComQuery(conn2, "SELECT * FROM test", noop))
+	requireErrorCode(mysql.ERLockWaitTimeout, handler.ComQuery(conn2, "INSERT INTO test VALUES (2000)", noop))
+	requireErrorCode(mysql.ERTableNotLockedForWrite, handler.ComQuery(conn1, "INSERT INTO test VALUES (2000)", noop))
+
+	done := make(chan error)
+	go func() {
+		done <- handler.ComQuery(conn2, "INSERT INTO test VALUES (2001)", noop)
+	}()
+	time.Sleep(50 * time.Millisecond)
+	require.NoError(handler.ComQuery(conn1, "UNLOCK TABLES", noop))
+	require.NoError(<-done)
+
+	// Sessions wait for the metadata locks of the tables locked for writes, so that the session that locked them can
+	// still change their definition instead of deadlocking with the sessions waiting for them
+	require.NoError(handler.ComQuery(conn2, "SET lock_wait_timeout = 10", noop))
+	require.NoError(handler.ComQuery(conn1, "LOCK TABLES test WRITE", noop))
+	var columns int
+	go func() {
+		done <- handler.ComQuery(conn2, "SELECT * FROM test", func(res *sqltypes.Result, more bool) error {
+			if len(res.Fields) > 0 {
+				columns = len(res.Fields)
+			}
+			return nil
+		})
+	}()
+	require.Eventually(func() bool {
+		for _, lock := range e.MetadataLocks.Locks() {
+			if !lock.Granted && lock.Owner == conn2.ConnectionID {
+				return true
+			}
+		}
+		return false
+	}, time.Second, time.Millisecond)
+	require.NoError(handler.ComQuery(conn1, "ALTER TABLE test ADD COLUMN c2 int", noop))
+	require.NoError(handler.ComQuery(conn1, "UNLOCK TABLES", noop))
+	require.NoError(<-done)
+	require.Equal(2, columns)
+
+	// Closing the connection releases its locks
+	require.NoError(handler.ComQuery(conn1, "LOCK TABLES test READ", noop))
+	handler.ConnectionClosed(conn1)
+	require.NoError(handler.ComQuery(conn2, "INSERT INTO test (c1) VALUES (2002)", noop))
+}
+
+func TestHandlerFlushTablesWithReadLock(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			testSessionBuilder,
+			sql.NoopTracer,
+			func(ctx *sql.Context, db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			sqle.NewProcessList(),
+			"foo",
+		),
+		0,
+		false,
+		0,
+		nil,
+	)
+	noop := func(res *sqltypes.Result, more bool) error {
+		return nil
+	}
+	requireErrorCode := func(code int, err error) {
+		require.Error(err)
+		require.Equal(code, err.(*mysql.SQLError).Number())
+	}
+
+	conn1 := newConn(1)
+	handler.NewConnection(conn1)
+	require.NoError(handler.ComInitDB(conn1, "test"))
+	conn2 := newConn(2)
+	handler.NewConnection(conn2)
+	require.NoError(handler.ComInitDB(conn2, "test"))
+	require.NoError(handler.ComQuery(conn1, "SET lock_wait_timeout = 1", noop))
+	require.NoError(handler.ComQuery(conn2, "SET lock_wait_timeout = 1", noop))
+
+	// Every session can read while the lock is held, but only other sessions wait to write
+	require.NoError(handler.ComQuery(conn1, "FLUSH TABLES WITH READ LOCK", noop))
+	require.NoError(handler.ComQuery(conn2, "SELECT * FROM test", noop))
+	require.NoError(handler.ComQuery(conn1, "SELECT * FROM test", noop))
+	requireErrorCode(mysql.ERCantUpdateWithReadLock, handler.ComQuery(conn1, "INSERT INTO test VALUES (2000)", noop))
+	requireErrorCode(mysql.ERLockWaitTimeout, handler.ComQuery(conn2, "INSERT INTO test VALUES (2000)", noop))
+	requireErrorCode(mysql.ERLockWaitTimeout, handler.ComQuery(conn2, "CREATE TABLE t2 (i int primary key)", noop))
+	requireErrorCode(mysql.ERLockWaitTimeout, handler.ComQuery(conn2, "LOCK TABLES test WRITE", noop))
+	requireErrorCode(mysql.ERCantUpdateWithReadLock, handler.ComQuery(conn1, "LOCK TABLES test WRITE", noop))
+
+	// A waiting write shows what it waits for in the processlist, and proceeds once the lock is released
+	require.NoError(handler.ComQuery(conn2, "SET lock_wait_timeout = 10", noop))
+	done := make(chan error)
+	go func() {
+		done <- handler.ComQuery(conn2, "INSERT INTO test VALUES (2001)", noop)
+	}()
+	require.Eventually(func() bool {
+		var state string
+		err := handler.ComQuery(conn1, "SELECT state FROM information_schema.processlist WHERE id = 2", func(res *sqltypes.Result, more bool) error {
+			if len(res.Rows) == 1 {
+				state = res.Rows[0][0].ToString()
+			}
+			return nil
+		})
+		return err == nil && state == "Waiting for global read lock"
+	}, 5*time.Second, 10*time.Millisecond)
+	require.NoError(handler.ComQuery(conn1, "UNLOCK TABLES", noop))
+	require.NoError(<-done)
+	require.NoError(handler.ComQuery(conn2, "SET lock_wait_timeout = 1", noop))
+
+	// Tables locked for writes keep the lock from being taken until they're unlocked
+	require.NoError(handler.ComQuery(conn2, "LOCK TABLES test WRITE", noop))
+	requireErrorCode(mysql.ERLockWaitTimeout, handler.ComQuery(conn1, "FLUSH TABLES WITH READ LOCK", noop))
+	requireErrorCode(mysql.ERLockOrActiveTransaction, handler.ComQuery(conn2, "FLUSH TABLES WITH READ LOCK", noop))
+	require.NoError(handler.ComQuery(conn2, "UNLOCK TABLES", noop))
+	require.NoError(handler.ComQuery(conn1, "FLUSH TABLES WITH READ LOCK", noop))
+
+	// Closing the connection releases the lock
+	handler.ConnectionClosed(conn1)
+	require.NoError(handler.ComQuery(conn2, "INSERT INTO test (c1) VALUES (2002)", noop))
+}
+
+func TestHandlerUserLockDeadlock(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			testSessionBuilder,
+			sql.NoopTracer,
+			func(ctx *sql.Context, db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			sqle.NewProcessList(),
+			"foo",
+		),
+		0,
+		false,
+		0,
+		nil,
+	)
+	noop := func(res *sqltypes.Result, more bool) error {
+		return nil
+	}
+	queryRows := func(c *mysql.Conn, query string) [][]string {
+		var rows [][]string
+		err := handler.ComQuery(c, query, func(res *sqltypes.Result, more bool) error {
+			for _, row := range res.Rows {
+				strs := make([]string, len(row))
+				for i, val := range row {
+					strs[i] = val.ToString()
+				}
+				rows = append(rows, strs)
+			}
+			return nil
+		})
+		require.NoError(err)
+		return rows
+	}
+	const userLocks = "SELECT object_name, lock_status, owner_thread_id FROM performance_schema.metadata_locks " +
+		"WHERE object_type = 'USER LEVEL LOCK' ORDER BY object_name, lock_status"
+
+	conn1 := newConn(1)
+	handler.NewConnection(conn1)
+	require.NoError(handler.ComInitDB(conn1, "test"))
+	conn2 := newConn(2)
+	handler.NewConnection(conn2)
+	require.NoError(handler.ComInitDB(conn2, "test"))
+
+	require.Equal([][]string{{"1"}}, queryRows(conn1, "SELECT GET_LOCK('lock_a', 0)"))
+	require.Equal([][]string{{"1"}}, queryRows(conn2, "SELECT GET_LOCK('lock_b', 0)"))
+
+	// A session waiting for a lock shows as a pending owner of it
+	done := make(chan error)
+	go func() {
+		done <- handler.ComQuery(conn2, "SELECT GET_LOCK('lock_a', 10)", noop)
+	}()
+	require.Eventually(func() bool {
+		return len(queryRows(conn1, userLocks)) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+	require.Equal([][]string{
+		{"lock_a", "GRANTED", "1"},
+		{"lock_a", "PENDING", "2"},
+		{"lock_b", "GRANTED", "2"},
+	}, queryRows(conn1, userLocks))
+
+	// Waiting for a lock of the waiting session is a deadlock, which is reported at once
+	err := handler.ComQuery(conn1, "SELECT GET_LOCK('lock_b', 10)", noop)
+	require.Error(err)
+	require.Equal(3058, err.(*mysql.SQLError).Number())
+
+	require.Equal([][]string{{"1"}}, queryRows(conn1, "SELECT RELEASE_LOCK('lock_a')"))
+	require.NoError(<-done)
+	require.Equal([][]string{{"2"}}, queryRows(conn1, "SELECT IS_USED_LOCK('lock_a')"))
+	require.Equal([][]string{{"2"}}, queryRows(conn2, "SELECT RELEASE_ALL_LOCKS()"))
+	require.Empty(queryRows(conn1, userLocks))
+}
+
+func TestHandlerProfiling(t *testing.T) {
+	require := require.New(t)
+	e := setupMemDB(require)
+	handler := NewHandler(
+		e,
+		NewSessionManager(
+			testSessionBuilder,
+			sql.NoopTracer,
+			func(ctx *sql.Context, db string) bool { return db == "test" },
+			sql.NewMemoryManager(nil),
+			sqle.NewProcessList(),
+			"foo",
+		),
+		0,
+		false,
+		0,
+		nil,
+	)
+	noop := func(res *sqltypes.Result, more bool) error {
+		return nil
+	}
+	queryColumn := func(c *mysql.Conn, query string, column int) []string {
+		var values []string
+		err := handler.ComQuery(c, query, func(res *sqltypes.Result, more bool) error {
+			for _, row := range res.Rows {
+				values = append(values, row[column].ToString())
+			}
+			return nil
+		})
+		require.NoError(err)
+		return values
+	}
+
+	conn := newConn(1)
+	handler.NewConnection(conn)
+	require.NoError(handler.ComInitDB(conn, "test"))
+
+	require.NoError(handler.ComQuery(conn, "SELECT * FROM test WHERE c1 < 3", noop))
+	require.Empty(queryColumn(conn, "SHOW PROFILES", 0))
+
+	require.NoError(handler.ComQuery(conn, "SET profiling = 1", noop))
+	require.NoError(handler.ComQuery(conn, "SELECT * FROM test WHERE c1 < 3", noop))
+	require.Error(handler.ComQuery(conn, "SELECT * FROM nonexistent", noop))
+
+	require.Equal([]string{"1", "2"}, queryColumn(conn, "SHOW PROFILES", 0))
+	require.Equal([]string{"SELECT * FROM test WHERE c1 < 3", "SELECT * FROM nonexistent"}, queryColumn(conn, "SHOW PROFILES", 2))
+	require.Equal([]string{
+		"starting",
+		"parsing",
+		"analyzing",
+		"pre-analyzer",
+		"once-before",
+		"default-rules",
+		"once-after",
+		"post-analyzer",
+		"pre-validation",
+		"validation",
+		"post-validation",
+		"after-all",
+		"executing",
+		"Sending data",
+		"cleaning up",
+	}, queryColumn(conn, "SHOW PROFILE CPU FOR QUERY 1", 0))
+	require.Equal([]string{"starting", "parsing", "analyzing", "pre-analyzer", "once-before"}, queryColumn(conn, "SHOW PROFILE", 0))
+}
+
 func setupMemDB(require *require.Assertions) *sqle.Engine {
 	db := memory.NewDatabase("test")
 	pro := memory.NewDBProvider(db)
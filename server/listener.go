@@ -46,6 +46,10 @@ type Listener struct {
 	// channel to close both listener
 	shutdown chan struct{}
 	once     *sync.Once
+	// compression is the protocol compression offered on accepted connections, or nil if compression is disabled
+	compression *compressionConfig
+	// proxyProtocol holds the sources trusted to send a PROXY protocol header, or nil if the PROXY protocol is disabled
+	proxyProtocol *proxyProtocolConfig
 }
 
 // NewListener creates a new Listener.
@@ -128,7 +132,17 @@ func (l *Listener) Accept() (net.Conn, error) {
 	if !ok {
 		return nil, net.ErrClosed
 	}
-	return cr.conn, cr.err
+	if cr.err != nil {
+		return cr.conn, cr.err
+	}
+	conn := cr.conn
+	if l.proxyProtocol != nil && l.proxyProtocol.trusts(conn.RemoteAddr()) {
+		conn = newProxyConn(conn)
+	}
+	if l.compression != nil {
+		conn = newCompressedConn(conn, l.compression)
+	}
+	return conn, nil
 }
 
 func (l *Listener) Close() error {
@@ -0,0 +1,279 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// The PROXY protocol lets a proxy or load balancer in front of the server pass along the address of the client that
+// connected to it, in a header that it sends before any other data on the connection. Both the human-readable version
+// 1 and the binary version 2 of the header are supported.
+// See https://www.haproxy.org/download/2.8/doc/proxy-protocol.txt
+
+var ErrProxyProtocolHeader = errors.New("invalid PROXY protocol header")
+
+const (
+	// proxyProtocolV1MaxLength is the maximum length of a version 1 header, including the trailing CRLF
+	proxyProtocolV1MaxLength = 107
+	// proxyProtocolV2HeaderLength is the length of the fixed part of a version 2 header
+	proxyProtocolV2HeaderLength = 16
+
+	proxyProtocolV2CommandLocal = 0x0
+	proxyProtocolV2CommandProxy = 0x1
+
+	proxyProtocolV2FamilyTCP4 = 0x11
+	proxyProtocolV2FamilyTCP6 = 0x21
+)
+
+var (
+	proxyProtocolV1Signature = []byte("PROXY ")
+	proxyProtocolV2Signature = []byte("\r\n\r\n\x00\r\nQUIT\n")
+)
+
+// proxyProtocolConfig holds the sources that are trusted to send a PROXY protocol header.
+type proxyProtocolConfig struct {
+	trustedSources []*net.IPNet
+}
+
+// newProxyProtocolConfig returns the PROXY protocol configuration for the server config given, or nil if no trusted
+// sources are configured.
+func newProxyProtocolConfig(cfg Config) (*proxyProtocolConfig, error) {
+	if len(cfg.ProxyProtocolTrustedSources) == 0 {
+		return nil, nil
+	}
+
+	pc := &proxyProtocolConfig{}
+	for _, source := range cfg.ProxyProtocolTrustedSources {
+		source = strings.TrimSpace(source)
+		if strings.Contains(source, "/") {
+			_, ipNet, err := net.ParseCIDR(source)
+			if err != nil {
+				return nil, fmt.Errorf("invalid PROXY protocol trusted source '%s': %w", source, err)
+			}
+			pc.trustedSources = append(pc.trustedSources, ipNet)
+			continue
+		}
+
+		ip := net.ParseIP(source)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid PROXY protocol trusted source '%s'", source)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			ip = ip4
+		}
+		pc.trustedSources = append(pc.trustedSources, &net.IPNet{IP: ip, Mask: net.CIDRMask(len(ip)*8, len(ip)*8)})
+	}
+	return pc, nil
+}
+
+// trusts returns whether a connection from the address given must begin with a PROXY protocol header.
+func (pc *proxyProtocolConfig) trusts(addr net.Addr) bool {
+	tcpAddr, ok := addr.(*net.TCPAddr)
+	if !ok {
+		return false
+	}
+	for _, ipNet := range pc.trustedSources {
+		if ipNet.Contains(tcpAddr.IP) {
+			return true
+		}
+	}
+	return false
+}
+
+// proxyConn is a connection from a trusted proxy, which reports the addresses sent in the PROXY protocol header that
+// begins the connection as its remote and local addresses. The header is read on the first call to Read, RemoteAddr
+// or LocalAddr, so that the listener doesn't wait on it when accepting connections.
+type proxyConn struct {
+	net.Conn
+	reader *bufio.Reader
+
+	once       sync.Once
+	err        error
+	remoteAddr net.Addr
+	localAddr  net.Addr
+}
+
+var _ net.Conn = (*proxyConn)(nil)
+
+func newProxyConn(conn net.Conn) *proxyConn {
+	return &proxyConn{
+		Conn:   conn,
+		reader: bufio.NewReader(conn),
+	}
+}
+
+// Read implements net.Conn.
+func (c *proxyConn) Read(b []byte) (int, error) {
+	c.once.Do(c.readHeader)
+	if c.err != nil {
+		return 0, c.err
+	}
+	// The header is usually the only thing buffered, since clients wait for the server's handshake before writing
+	if c.reader.Buffered() > 0 {
+		return c.reader.Read(b)
+	}
+	return c.Conn.Read(b)
+}
+
+// RemoteAddr implements net.Conn.
+func (c *proxyConn) RemoteAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.remoteAddr != nil {
+		return c.remoteAddr
+	}
+	return c.Conn.RemoteAddr()
+}
+
+// LocalAddr implements net.Conn.
+func (c *proxyConn) LocalAddr() net.Addr {
+	c.once.Do(c.readHeader)
+	if c.localAddr != nil {
+		return c.localAddr
+	}
+	return c.Conn.LocalAddr()
+}
+
+// readHeader reads and parses the PROXY protocol header at the beginning of the connection.
+func (c *proxyConn) readHeader() {
+	signature, err := c.reader.Peek(len(proxyProtocolV1Signature))
+	if err != nil {
+		c.err = err
+		return
+	}
+	if bytes.Equal(signature, proxyProtocolV1Signature) {
+		c.err = c.readV1Header()
+		return
+	}
+
+	signature, err = c.reader.Peek(len(proxyProtocolV2Signature))
+	if err != nil {
+		c.err = err
+		return
+	}
+	if bytes.Equal(signature, proxyProtocolV2Signature) {
+		c.err = c.readV2Header()
+		return
+	}
+
+	c.err = fmt.Errorf("%w: connection from trusted source %s has no header", ErrProxyProtocolHeader, c.Conn.RemoteAddr())
+}
+
+// readV1Header reads a version 1 header, such as "PROXY TCP4 192.168.0.1 192.168.0.11 56324 3306\r\n".
+func (c *proxyConn) readV1Header() error {
+	var line []byte
+	for !bytes.HasSuffix(line, []byte("\r\n")) {
+		b, err := c.reader.ReadByte()
+		if err != nil {
+			return err
+		}
+		line = append(line, b)
+		if len(line) > proxyProtocolV1MaxLength {
+			return fmt.Errorf("%w: header is too long", ErrProxyProtocolHeader)
+		}
+	}
+
+	fields := strings.Split(string(line[:len(line)-2]), " ")
+	if len(fields) >= 2 && fields[1] == "UNKNOWN" {
+		// The proxy couldn't determine the client's address, so the connection's own addresses are used
+		return nil
+	}
+	if len(fields) != 6 || (fields[1] != "TCP4" && fields[1] != "TCP6") {
+		return fmt.Errorf("%w: %q", ErrProxyProtocolHeader, line)
+	}
+
+	remoteAddr, err := parseProxyProtocolV1Address(fields[2], fields[4])
+	if err != nil {
+		return err
+	}
+	localAddr, err := parseProxyProtocolV1Address(fields[3], fields[5])
+	if err != nil {
+		return err
+	}
+	c.remoteAddr, c.localAddr = remoteAddr, localAddr
+	return nil
+}
+
+func parseProxyProtocolV1Address(host, port string) (*net.TCPAddr, error) {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return nil, fmt.Errorf("%w: invalid address '%s'", ErrProxyProtocolHeader, host)
+	}
+	p, err := strconv.ParseUint(port, 10, 16)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid port '%s'", ErrProxyProtocolHeader, port)
+	}
+	return &net.TCPAddr{IP: ip, Port: int(p)}, nil
+}
+
+// readV2Header reads a binary version 2 header.
+func (c *proxyConn) readV2Header() error {
+	header := make([]byte, proxyProtocolV2HeaderLength)
+	if _, err := io.ReadFull(c.reader, header); err != nil {
+		return err
+	}
+	versionCommand, family := header[12], header[13]
+	if versionCommand>>4 != 2 {
+		return fmt.Errorf("%w: unsupported version %d", ErrProxyProtocolHeader, versionCommand>>4)
+	}
+
+	// The addresses are followed by optional TLVs, which aren't used, but must be read all the same
+	data := make([]byte, binary.BigEndian.Uint16(header[14:]))
+	if _, err := io.ReadFull(c.reader, data); err != nil {
+		return err
+	}
+
+	switch versionCommand & 0xf {
+	case proxyProtocolV2CommandLocal:
+		// A connection that the proxy made on its own behalf, such as a health check
+		return nil
+	case proxyProtocolV2CommandProxy:
+	default:
+		return fmt.Errorf("%w: unsupported command %d", ErrProxyProtocolHeader, versionCommand&0xf)
+	}
+
+	var ipLength int
+	switch family {
+	case proxyProtocolV2FamilyTCP4:
+		ipLength = net.IPv4len
+	case proxyProtocolV2FamilyTCP6:
+		ipLength = net.IPv6len
+	default:
+		// Other address families can't be represented as a TCP address, so the connection's own addresses are used
+		return nil
+	}
+	if len(data) < 2*ipLength+4 {
+		return fmt.Errorf("%w: address block is too short", ErrProxyProtocolHeader)
+	}
+
+	c.remoteAddr = &net.TCPAddr{
+		IP:   net.IP(data[:ipLength]),
+		Port: int(binary.BigEndian.Uint16(data[2*ipLength:])),
+	}
+	c.localAddr = &net.TCPAddr{
+		IP:   net.IP(data[ipLength : 2*ipLength]),
+		Port: int(binary.BigEndian.Uint16(data[2*ipLength+2:])),
+	}
+	return nil
+}
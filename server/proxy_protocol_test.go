@@ -0,0 +1,237 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package server
+
+import (
+	"context"
+	gosql "database/sql"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+
+	gomysql "github.com/go-sql-driver/mysql"
+	"github.com/stretchr/testify/require"
+
+	sqle "github.com/dolthub/go-mysql-server"
+	"github.com/dolthub/go-mysql-server/memory"
+)
+
+func init() {
+	// proxiedtcp connects as if through a proxy, for a client at 10.1.2.3:45678
+	gomysql.RegisterDialContext("proxiedtcp", func(ctx context.Context, addr string) (net.Conn, error) {
+		var d net.Dialer
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		if err != nil {
+			return nil, err
+		}
+		_, err = conn.Write([]byte(fmt.Sprintf("PROXY TCP4 10.1.2.3 127.0.0.1 45678 %d\r\n", conn.RemoteAddr().(*net.TCPAddr).Port)))
+		if err != nil {
+			conn.Close()
+			return nil, err
+		}
+		return conn, nil
+	})
+}
+
+func newProxyProtocolTestServer(t *testing.T, trustedSources ...string) *Server {
+	db := memory.NewDatabase("mydb")
+	pro := memory.NewDBProvider(db)
+	e := sqle.NewDefault(pro)
+
+	e.Analyzer.Catalog.MySQLDb.AddRootAccount()
+	// This account can only connect from the address of the client behind the proxy
+	e.Analyzer.Catalog.MySQLDb.AddSuperUser("proxied", "10.1.2.3", "")
+
+	s, err := NewDefaultServer(Config{
+		Protocol:                    "tcp",
+		Address:                     "127.0.0.1:0",
+		ProxyProtocolTrustedSources: trustedSources,
+	}, e)
+	require.NoError(t, err)
+	go s.Start()
+	t.Cleanup(func() {
+		require.NoError(t, s.Close())
+	})
+	return s
+}
+
+func TestProxyProtocol(t *testing.T) {
+	s := newProxyProtocolTestServer(t, "127.0.0.0/8")
+
+	db, err := gosql.Open("mysql", fmt.Sprintf("proxied:@proxiedtcp(%s)/", s.Listener.Addr().String()))
+	require.NoError(t, err)
+	defer db.Close()
+
+	var user string
+	require.NoError(t, db.QueryRow("select current_user()").Scan(&user))
+	require.Equal(t, "proxied@10.1.2.3", user)
+
+	// Connections from a trusted source without a header are refused
+	db, err = gosql.Open("mysql", fmt.Sprintf("root:@tcp(%s)/", s.Listener.Addr().String()))
+	require.NoError(t, err)
+	defer db.Close()
+	require.Error(t, db.Ping())
+}
+
+func TestProxyProtocolUntrustedSource(t *testing.T) {
+	s := newProxyProtocolTestServer(t, "10.0.0.0/8")
+
+	// The header isn't read from untrusted sources, so it's taken as a malformed handshake response
+	db, err := gosql.Open("mysql", fmt.Sprintf("proxied:@proxiedtcp(%s)/", s.Listener.Addr().String()))
+	require.NoError(t, err)
+	defer db.Close()
+	require.Error(t, db.Ping())
+
+	db, err = gosql.Open("mysql", fmt.Sprintf("root:@tcp(%s)/", s.Listener.Addr().String()))
+	require.NoError(t, err)
+	defer db.Close()
+	require.NoError(t, db.Ping())
+}
+
+func TestNewProxyProtocolConfig(t *testing.T) {
+	pc, err := newProxyProtocolConfig(Config{})
+	require.NoError(t, err)
+	require.Nil(t, pc)
+
+	pc, err = newProxyProtocolConfig(Config{ProxyProtocolTrustedSources: []string{"10.0.0.0/8", "192.168.1.7", "fd00::/8"}})
+	require.NoError(t, err)
+	require.True(t, pc.trusts(&net.TCPAddr{IP: net.ParseIP("10.20.30.40")}))
+	require.True(t, pc.trusts(&net.TCPAddr{IP: net.ParseIP("192.168.1.7")}))
+	require.False(t, pc.trusts(&net.TCPAddr{IP: net.ParseIP("192.168.1.8")}))
+	require.True(t, pc.trusts(&net.TCPAddr{IP: net.ParseIP("fd12::1")}))
+	require.False(t, pc.trusts(&net.TCPAddr{IP: net.ParseIP("::1")}))
+	require.False(t, pc.trusts(&net.UnixAddr{Name: "/tmp/mysql.sock", Net: "unix"}))
+
+	_, err = newProxyProtocolConfig(Config{ProxyProtocolTrustedSources: []string{"10.0.0.0/33"}})
+	require.Error(t, err)
+	_, err = newProxyProtocolConfig(Config{ProxyProtocolTrustedSources: []string{"proxy.example.com"}})
+	require.Error(t, err)
+}
+
+func TestProxyConnHeaders(t *testing.T) {
+	v2Header := func(command, family byte, addresses []byte, tlvs []byte) []byte {
+		header := append([]byte{}, proxyProtocolV2Signature...)
+		header = append(header, 0x20|command, family)
+		header = binary.BigEndian.AppendUint16(header, uint16(len(addresses)+len(tlvs)))
+		header = append(header, addresses...)
+		return append(header, tlvs...)
+	}
+	v4Addresses := []byte{10, 1, 2, 3, 192, 168, 0, 1}
+	v4Addresses = binary.BigEndian.AppendUint16(v4Addresses, 45678)
+	v4Addresses = binary.BigEndian.AppendUint16(v4Addresses, 3306)
+	v6Addresses := append(append([]byte{}, net.ParseIP("2001:db8::1")...), net.ParseIP("2001:db8::2")...)
+	v6Addresses = binary.BigEndian.AppendUint16(v6Addresses, 45678)
+	v6Addresses = binary.BigEndian.AppendUint16(v6Addresses, 3306)
+
+	tests := []struct {
+		name   string
+		header []byte
+		remote string
+		local  string
+		err    bool
+	}{
+		{
+			name:   "v1 TCP4",
+			header: []byte("PROXY TCP4 10.1.2.3 192.168.0.1 45678 3306\r\n"),
+			remote: "10.1.2.3:45678",
+			local:  "192.168.0.1:3306",
+		},
+		{
+			name:   "v1 TCP6",
+			header: []byte("PROXY TCP6 2001:db8::1 2001:db8::2 45678 3306\r\n"),
+			remote: "[2001:db8::1]:45678",
+			local:  "[2001:db8::2]:3306",
+		},
+		{
+			name:   "v1 UNKNOWN",
+			header: []byte("PROXY UNKNOWN\r\n"),
+			remote: "pipe",
+			local:  "pipe",
+		},
+		{
+			name:   "v1 malformed",
+			header: []byte("PROXY TCP4 10.1.2.3\r\n"),
+			err:    true,
+		},
+		{
+			name:   "v1 bad port",
+			header: []byte("PROXY TCP4 10.1.2.3 192.168.0.1 456789 3306\r\n"),
+			err:    true,
+		},
+		{
+			name:   "v2 TCP4",
+			header: v2Header(proxyProtocolV2CommandProxy, proxyProtocolV2FamilyTCP4, v4Addresses, nil),
+			remote: "10.1.2.3:45678",
+			local:  "192.168.0.1:3306",
+		},
+		{
+			name:   "v2 TCP4 with TLVs",
+			header: v2Header(proxyProtocolV2CommandProxy, proxyProtocolV2FamilyTCP4, v4Addresses, []byte{0x04, 0x00, 0x01, 0xff}),
+			remote: "10.1.2.3:45678",
+			local:  "192.168.0.1:3306",
+		},
+		{
+			name:   "v2 TCP6",
+			header: v2Header(proxyProtocolV2CommandProxy, proxyProtocolV2FamilyTCP6, v6Addresses, nil),
+			remote: "[2001:db8::1]:45678",
+			local:  "[2001:db8::2]:3306",
+		},
+		{
+			name:   "v2 LOCAL",
+			header: v2Header(proxyProtocolV2CommandLocal, 0, nil, nil),
+			remote: "pipe",
+			local:  "pipe",
+		},
+		{
+			name:   "v2 short addresses",
+			header: v2Header(proxyProtocolV2CommandProxy, proxyProtocolV2FamilyTCP6, v4Addresses, nil),
+			err:    true,
+		},
+		{
+			name:   "no header",
+			header: []byte{0x20, 0, 0, 1, 0x85, 0xa6, 0xff, 0x01, 0, 0, 0, 0, 0x21},
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			serverSide, clientSide := net.Pipe()
+			defer clientSide.Close()
+			conn := newProxyConn(serverSide)
+			defer conn.Close()
+
+			go func() {
+				// The header is followed by the client's data, which must be read after it
+				clientSide.Write(append(test.header, "data"...))
+			}()
+
+			if test.err {
+				_, err := conn.Read(make([]byte, 4))
+				require.ErrorIs(t, err, ErrProxyProtocolHeader)
+				return
+			}
+
+			require.Equal(t, test.remote, conn.RemoteAddr().String())
+			require.Equal(t, test.local, conn.LocalAddr().String())
+			data := make([]byte, 4)
+			_, err := io.ReadFull(conn, data)
+			require.NoError(t, err)
+			require.Equal(t, "data", string(data))
+		})
+	}
+}
@@ -101,6 +101,24 @@ func newServerFromHandler(cfg Config, e *sqle.Engine, sm *SessionManager, handle
 		}
 	}
 
+	l.compression, err = newCompressionConfig(cfg)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	err = sql.SystemVariables.AssignValues(map[string]interface{}{
+		"protocol_compression_algorithms": l.compression.algorithms(),
+	})
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	l.proxyProtocol, err = newProxyProtocolConfig(cfg)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+
 	listenerCfg := mysql.ListenerConfig{
 		Listener:                 l,
 		AuthServer:               e.Analyzer.Catalog.MySQLDb,
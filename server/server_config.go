@@ -70,6 +70,20 @@ type Config struct {
 	// MaxLoggedQueryLen sets the length at which queries written to the logs are truncated.  A value of 0 will
 	// result in no truncation. A value less than 0 will result in the queries being omitted from the logs completely
 	MaxLoggedQueryLen int
+	// CompressionAlgorithms are the algorithms of the compressed protocol that the server offers to clients: any of
+	// "zlib" and "zstd", as well as "uncompressed", which is always allowed. If empty, which is the default, compression
+	// is disabled: embedders opt in by setting it, and NewConfig leaves it as is. Compression isn't supported over TLS,
+	// so it's also disabled when TLSConfig is set. The algorithms in use are reported by the read-only
+	// @@protocol_compression_algorithms.
+	CompressionAlgorithms []string
+	// ZstdCompressionLevel is the level, from 1 to 22, at which the server compresses data sent to clients using zstd.
+	// If 0, the level requested by each client is used.
+	ZstdCompressionLevel int
+	// ProxyProtocolTrustedSources are the addresses of proxies and load balancers, as IPs or CIDR ranges, that are
+	// trusted to send a PROXY protocol header with the address of the client they are forwarding. Every connection from
+	// one of these addresses must begin with a version 1 or version 2 header, and the client address it contains is used
+	// in place of the proxy's for authentication and in the session. If empty, the PROXY protocol is not accepted.
+	ProxyProtocolTrustedSources []string
 }
 
 func (c Config) NewConfig() (Config, error) {
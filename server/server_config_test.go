@@ -88,3 +88,15 @@ func TestConfigWithDefaults(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigCompressionIsOptIn(t *testing.T) {
+	variables.InitSystemVariables()
+
+	serverConf, err := Config{}.NewConfig()
+	assert.NoError(t, err)
+	assert.Empty(t, serverConf.CompressionAlgorithms)
+
+	serverConf, err = Config{CompressionAlgorithms: []string{"zstd"}}.NewConfig()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"zstd"}, serverConf.CompressionAlgorithms)
+}
@@ -608,6 +608,12 @@ func (a *Analyzer) analyzeWithSelector(ctx *sql.Context, n sql.Node, scope *Scop
 		allSame = transform.SameTree
 		err     error
 	)
+	ctx, trace := startOptimizerTrace(ctx)
+	if trace != nil {
+		defer func() {
+			finishOptimizerTrace(ctx, trace, n)
+		}()
+	}
 	a.Log("starting analysis of node of type: %T", n)
 	for _, batch := range a.Batches {
 		if batchSelector(batch.Desc) {
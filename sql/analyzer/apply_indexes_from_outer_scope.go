@@ -152,6 +152,7 @@ func getOuterScopeIndexes(
 				return false
 			}
 			defer indexAnalyzer.releaseUsedIndexes()
+			indexAnalyzer.restrictToHints(extractQueryHints(node.Child), indexUseJoin)
 
 			indexes, exprsByTable, err = getSubqueryIndexes(ctx, a, node.Expression, scope, indexAnalyzer, tableAliases)
 			if err != nil {
@@ -73,10 +73,22 @@ func assignCatalog(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, sel
 		case *plan.LockTables:
 			nc := *node
 			nc.Catalog = a.Catalog
+			nc.GlobalReadLock = a.Catalog.GlobalReadLock
+			nc.MetadataLocks = a.Catalog.Instrumentation.MetadataLocks
 			return &nc, transform.NewTree, nil
 		case *plan.UnlockTables:
 			nc := *node
 			nc.Catalog = a.Catalog
+			nc.GlobalReadLock = a.Catalog.GlobalReadLock
+			return &nc, transform.NewTree, nil
+		case *plan.FlushTables:
+			nc := *node
+			nc.Catalog = a.Catalog
+			nc.GlobalReadLock = a.Catalog.GlobalReadLock
+			return &nc, transform.NewTree, nil
+		case *plan.ShowStatus:
+			nc := *node
+			nc.QueryCache = a.Catalog.Instrumentation.QueryCache
 			return &nc, transform.NewTree, nil
 		case *plan.ResolvedTable:
 			ct, ok := node.Table.(sql.CatalogTable)
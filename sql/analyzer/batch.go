@@ -61,8 +61,13 @@ func (b *Batch) EvalWithSelector(ctx *sql.Context, a *Analyzer, n sql.Node, scop
 		return n, transform.SameTree, nil
 	}
 	prev := n
+	bt := traceBatch(ctx, b, scope)
+	// The batches of nested scopes, such as subqueries, are profiled as part of the enclosing batch
+	if scope == nil {
+		sql.SetProfileStage(ctx, b.Desc)
+	}
 	a.PushDebugContext("0")
-	cur, _, err := b.evalOnce(ctx, a, n, scope, sel)
+	cur, _, err := b.evalOnce(ctx, a, n, scope, sel, bt)
 	a.PopDebugContext()
 	if err != nil {
 		return cur, transform.SameTree, err
@@ -83,7 +88,7 @@ func (b *Batch) EvalWithSelector(ctx *sql.Context, a *Analyzer, n sql.Node, scop
 
 		prev = cur
 		a.PushDebugContext(strconv.Itoa(i))
-		cur, _, err = b.evalOnce(ctx, a, cur, scope, sel)
+		cur, _, err = b.evalOnce(ctx, a, cur, scope, sel, bt)
 		a.PopDebugContext()
 		if err != nil {
 			return cur, transform.SameTree, err
@@ -102,14 +107,17 @@ func (b *Batch) EvalWithSelector(ctx *sql.Context, a *Analyzer, n sql.Node, scop
 
 // evalOnce returns the result of evaluating a batch of rules on the node given. In the result of an error, the result
 // of the last successful transformation is returned along with the error. If no transformation was successful, the
-// input node is returned as-is.
-func (b *Batch) evalOnce(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, sel RuleSelector) (sql.Node, transform.TreeIdentity, error) {
+// input node is returned as-is. The rules that change the node are recorded in |bt|, if the statement is being traced.
+func (b *Batch) evalOnce(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, sel RuleSelector, bt *batchTrace) (sql.Node, transform.TreeIdentity, error) {
 	var (
 		same    = transform.SameTree
 		allSame = transform.SameTree
 		next    sql.Node
 		prev    = n
 	)
+	if bt != nil {
+		bt.Iterations++
+	}
 	for _, rule := range b.Rules {
 		if !sel(rule.Id) {
 			a.Log("Skipping rule %s", rule.Id)
@@ -125,6 +133,7 @@ func (b *Batch) evalOnce(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope
 			// We should only do this if the result has changed, but some rules currently misbehave and falsely report nothing
 			// changed
 			a.LogDiff(prev, next)
+			bt.addRule(rule.Id, prev, next)
 		}
 		a.PopDebugContext()
 		if err != nil {
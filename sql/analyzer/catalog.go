@@ -24,11 +24,17 @@ import (
 	"github.com/dolthub/go-mysql-server/sql/expression/function"
 	"github.com/dolthub/go-mysql-server/sql/information_schema"
 	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+	"github.com/dolthub/go-mysql-server/sql/performance_schema"
 )
 
 type Catalog struct {
-	MySQLDb    *mysql_db.MySQLDb
-	InfoSchema sql.Database
+	MySQLDb           *mysql_db.MySQLDb
+	InfoSchema        sql.Database
+	PerformanceSchema sql.Database
+	// Instrumentation collects the events that are exposed by the performance_schema database.
+	Instrumentation *performance_schema.Instrumentation
+	// GlobalReadLock is the lock taken by FLUSH TABLES WITH READ LOCK.
+	GlobalReadLock *sql.GlobalReadLock
 
 	Provider         sql.DatabaseProvider
 	builtInFunctions function.Registry
@@ -38,8 +44,10 @@ type Catalog struct {
 
 var _ sql.Catalog = (*Catalog)(nil)
 var _ sql.FunctionProvider = (*Catalog)(nil)
+var _ sql.ExternalFunctionProvider = (*Catalog)(nil)
 var _ sql.TableFunctionProvider = (*Catalog)(nil)
 var _ sql.ExternalStoredProcedureProvider = (*Catalog)(nil)
+var _ sql.StorageUsageProvider = (*Catalog)(nil)
 
 type tableLocks map[string]struct{}
 
@@ -49,12 +57,16 @@ type sessionLocks map[uint32]dbLocks
 
 // NewCatalog returns a new empty Catalog with the given provider
 func NewCatalog(provider sql.DatabaseProvider) *Catalog {
+	instrumentation := performance_schema.NewInstrumentation()
 	return &Catalog{
-		MySQLDb:          mysql_db.CreateEmptyMySQLDb(),
-		InfoSchema:       information_schema.NewInformationSchemaDatabase(),
-		Provider:         provider,
-		builtInFunctions: function.NewRegistry(),
-		locks:            make(sessionLocks),
+		MySQLDb:           mysql_db.CreateEmptyMySQLDb(),
+		InfoSchema:        information_schema.NewInformationSchemaDatabase(),
+		PerformanceSchema: performance_schema.NewPerformanceSchemaDatabase(instrumentation),
+		Instrumentation:   instrumentation,
+		GlobalReadLock:    sql.NewGlobalReadLock(),
+		Provider:          provider,
+		builtInFunctions:  function.NewRegistry(),
+		locks:             make(sessionLocks),
 	}
 }
 
@@ -66,6 +78,9 @@ func NewDatabaseProvider(dbs ...sql.Database) sql.DatabaseProvider {
 func (c *Catalog) AllDatabases(ctx *sql.Context) []sql.Database {
 	var dbs []sql.Database
 	dbs = append(dbs, c.InfoSchema)
+	if c.canAccessPerformanceSchema(ctx) {
+		dbs = append(dbs, c.PerformanceSchema)
+	}
 
 	if c.MySQLDb.Enabled {
 		dbs = append(dbs, mysql_db.NewPrivilegedDatabaseProvider(c.MySQLDb, c.Provider).AllDatabases(ctx)...)
@@ -120,6 +135,8 @@ func (c *Catalog) HasDB(ctx *sql.Context, db string) bool {
 	db = strings.ToLower(db)
 	if db == "information_schema" {
 		return true
+	} else if db == sql.PerformanceSchemaDatabaseName {
+		return c.canAccessPerformanceSchema(ctx)
 	} else if c.MySQLDb.Enabled {
 		return mysql_db.NewPrivilegedDatabaseProvider(c.MySQLDb, c.Provider).HasDatabase(ctx, db)
 	} else {
@@ -131,6 +148,13 @@ func (c *Catalog) HasDB(ctx *sql.Context, db string) bool {
 func (c *Catalog) Database(ctx *sql.Context, db string) (sql.Database, error) {
 	if strings.ToLower(db) == "information_schema" {
 		return c.InfoSchema, nil
+	} else if strings.ToLower(db) == sql.PerformanceSchemaDatabaseName {
+		if !c.canAccessPerformanceSchema(ctx) {
+			client := ctx.Session.Client()
+			user := mysql_db.User{User: client.User, Host: client.Address}
+			return nil, sql.ErrDatabaseAccessDeniedForUser.New(user.UserHostToString("'"), db)
+		}
+		return c.PerformanceSchema, nil
 	} else if c.MySQLDb.Enabled {
 		return mysql_db.NewPrivilegedDatabaseProvider(c.MySQLDb, c.Provider).Database(ctx, db)
 	} else {
@@ -138,11 +162,20 @@ func (c *Catalog) Database(ctx *sql.Context, db string) (sql.Database, error) {
 	}
 }
 
-// LockTable adds a lock for the given table and session client. It is assumed
-// the database is the current database in use.
-func (c *Catalog) LockTable(ctx *sql.Context, table string) {
+// canAccessPerformanceSchema returns whether the current user may access the performance_schema database, which
+// requires either a global privilege or a privilege on the database itself when the grant tables are in use.
+func (c *Catalog) canAccessPerformanceSchema(ctx *sql.Context) bool {
+	if !c.MySQLDb.Enabled {
+		return true
+	}
+	privSet := c.MySQLDb.UserActivePrivilegeSet(ctx)
+	return privSet.Count() > 0 || privSet.Database(sql.PerformanceSchemaDatabaseName).HasPrivileges()
+}
+
+// LockTable adds a lock for the given table and session client.
+func (c *Catalog) LockTable(ctx *sql.Context, database, table string) {
 	id := ctx.ID()
-	db := ctx.GetCurrentDatabase()
+	database = strings.ToLower(database)
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
@@ -151,18 +184,38 @@ func (c *Catalog) LockTable(ctx *sql.Context, table string) {
 		c.locks[id] = make(dbLocks)
 	}
 
-	if _, ok := c.locks[id][db]; !ok {
-		c.locks[id][db] = make(tableLocks)
+	if _, ok := c.locks[id][database]; !ok {
+		c.locks[id][database] = make(tableLocks)
 	}
 
-	c.locks[id][db][table] = struct{}{}
+	c.locks[id][database][strings.ToLower(table)] = struct{}{}
+}
+
+// hasTableLocks returns whether the session client given holds any table lock.
+func (c *Catalog) hasTableLocks(id uint32) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return len(c.locks[id]) > 0
+}
+
+// isTableLocked returns whether the session client given holds a lock on the table given.
+func (c *Catalog) isTableLocked(id uint32, database, table string) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.locks[id][strings.ToLower(database)][strings.ToLower(table)]
+	return ok
 }
 
 // UnlockTables unlocks all tables for which the given session client has a
-// lock.
+// lock. Once its tables are unlocked, the global read lock can be taken again,
+// and other sessions can take the metadata locks of the tables.
 func (c *Catalog) UnlockTables(ctx *sql.Context, id uint32) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
+	defer c.GlobalReadLock.EndTableWrites(id)
+	if c.Instrumentation.MetadataLocks != nil {
+		defer c.Instrumentation.MetadataLocks.ReleaseExplicitLocks(id)
+	}
 
 	var errors []string
 	for db, tables := range c.locks[id] {
@@ -265,6 +318,15 @@ func (c *Catalog) Function(ctx *sql.Context, name string) (sql.Function, error)
 	return c.builtInFunctions.Function(ctx, name)
 }
 
+// ExternalFunction implements sql.ExternalFunctionProvider. It only returns the functions of the catalog's provider,
+// and never the built-in functions.
+func (c *Catalog) ExternalFunction(ctx *sql.Context, name string) (sql.Function, error) {
+	if fp, ok := c.Provider.(sql.FunctionProvider); ok {
+		return fp.Function(ctx, name)
+	}
+	return nil, sql.ErrFunctionNotFound.New(name)
+}
+
 // ExternalStoredProcedure implements sql.ExternalStoredProcedureProvider
 func (c *Catalog) ExternalStoredProcedure(ctx *sql.Context, name string, numOfParams int) (*sql.ExternalStoredProcedureDetails, error) {
 	if espp, ok := c.Provider.(sql.ExternalStoredProcedureProvider); ok {
@@ -293,6 +355,22 @@ func (c *Catalog) ExternalStoredProcedures(ctx *sql.Context, name string) ([]sql
 	return nil, nil
 }
 
+// Tablespaces implements sql.StorageUsageProvider
+func (c *Catalog) Tablespaces(ctx *sql.Context) ([]sql.Tablespace, error) {
+	if sup, ok := c.Provider.(sql.StorageUsageProvider); ok {
+		return sup.Tablespaces(ctx)
+	}
+	return nil, nil
+}
+
+// DataFiles implements sql.StorageUsageProvider
+func (c *Catalog) DataFiles(ctx *sql.Context) ([]sql.DataFile, error) {
+	if sup, ok := c.Provider.(sql.StorageUsageProvider); ok {
+		return sup.DataFiles(ctx)
+	}
+	return nil, nil
+}
+
 // TableFunction implements the TableFunctionProvider interface
 func (c *Catalog) TableFunction(ctx *sql.Context, name string) (sql.TableFunction, error) {
 	if fp, ok := c.Provider.(sql.TableFunctionProvider); ok {
@@ -338,3 +416,9 @@ func suggestSimilarTablesAsOf(db sql.VersionedDatabase, ctx *sql.Context, tableN
 	similar := similartext.Find(tableNames, tableName)
 	return sql.ErrTableNotFound.New(tableName + similar)
 }
+
+// GrantTables returns the grant tables, without checking the privileges of the current user. This is used by the
+// information_schema tables that describe the current user's own roles.
+func (c *Catalog) GrantTables() *mysql_db.MySQLDb {
+	return c.MySQLDb
+}
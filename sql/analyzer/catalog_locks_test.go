@@ -28,15 +28,12 @@ func TestCatalogLockTable(t *testing.T) {
 	c := NewCatalog(NewDatabaseProvider())
 
 	ctx1 := sql.NewContext(context.Background())
-	ctx1.SetCurrentDatabase("db1")
 	ctx2 := sql.NewContext(context.Background())
-	ctx2.SetCurrentDatabase("db1")
 
-	c.LockTable(ctx1, "foo")
-	c.LockTable(ctx2, "bar")
-	c.LockTable(ctx1, "baz")
-	ctx1.SetCurrentDatabase("db2")
-	c.LockTable(ctx1, "qux")
+	c.LockTable(ctx1, "db1", "foo")
+	c.LockTable(ctx2, "db1", "bar")
+	c.LockTable(ctx1, "DB1", "Baz")
+	c.LockTable(ctx1, "db2", "qux")
 
 	expected := sessionLocks{
 		ctx1.ID(): dbLocks{
@@ -56,4 +53,10 @@ func TestCatalogLockTable(t *testing.T) {
 	}
 
 	require.Equal(expected, c.locks)
+
+	require.True(c.hasTableLocks(ctx1.ID()))
+	require.True(c.isTableLocked(ctx1.ID(), "db1", "BAZ"))
+	require.False(c.isTableLocked(ctx1.ID(), "db1", "bar"))
+	require.False(c.isTableLocked(ctx1.ID(), "db2", "foo"))
+	require.False(c.hasTableLocks(ctx2.ID() + 1))
 }
@@ -32,5 +32,13 @@ func resolveDescribeQuery(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scop
 		return nil, transform.SameTree, err
 	}
 
+	if d.Format == "json" {
+		stats, err := a.Catalog.Statistics(ctx)
+		if err != nil {
+			return nil, transform.SameTree, err
+		}
+		d = d.WithStats(stats)
+	}
+
 	return d.WithQuery(StripPassthroughNodes(q)), transform.NewTree, nil
 }
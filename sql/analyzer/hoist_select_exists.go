@@ -71,10 +71,12 @@ func hoistExistSubqueries(scope *Scope, a *Analyzer, filter *plan.Filter, scopeL
 		var s *hoistSubquery
 		switch e := f.(type) {
 		case *plan.ExistsSubquery:
-			joinType = plan.JoinTypeSemi
-			s = decorrelateOuterCols(e.Query, scopeLen)
+			if semiJoinAllowed(e.Query.Query) {
+				joinType = plan.JoinTypeSemi
+				s = decorrelateOuterCols(e.Query, scopeLen)
+			}
 		case *expression.Not:
-			if esq, ok := e.Child.(*plan.ExistsSubquery); ok {
+			if esq, ok := e.Child.(*plan.ExistsSubquery); ok && semiJoinAllowed(esq.Query.Query) {
 				joinType = plan.JoinTypeAnti
 				s = decorrelateOuterCols(esq.Query, scopeLen)
 			}
@@ -110,6 +110,16 @@ func newIndexAnalyzerForNode(ctx *sql.Context, n sql.Node) (*indexAnalyzer, erro
 	}, nil
 }
 
+// restrictToHints removes the indexes that the optimizer hints given don't allow to be used as given.
+func (r *indexAnalyzer) restrictToHints(hints sql.OptimizerHints, use indexHintUse) {
+	if len(hints) == 0 {
+		return
+	}
+	for table, idxes := range r.indexesByTable {
+		r.indexesByTable[table] = indexesAllowed(hints, use, table, idxes)
+	}
+}
+
 // IndexesByTable returns all indexes on the table named. The table must be present in the node used to create the
 // analyzer.
 func (r *indexAnalyzer) IndexesByTable(ctx *sql.Context, db, table string) []sql.Index {
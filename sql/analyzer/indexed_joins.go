@@ -16,7 +16,6 @@ package analyzer
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 
 	"github.com/dolthub/vitess/go/vt/sqlparser"
@@ -163,6 +162,7 @@ func replanJoin(ctx *sql.Context, n *plan.JoinNode, a *Analyzer, scope *Scope) (
 
 	j := newJoinOrderBuilder(m)
 	j.reorderJoin(n)
+	m.WithHints(extractQueryHints(n))
 
 	err = convertSemiToInnerJoin(m)
 	if err != nil {
@@ -189,7 +189,8 @@ func replanJoin(ctx *sql.Context, n *plan.JoinNode, a *Analyzer, scope *Scope) (
 		a.Log(m.String())
 	}
 
-	if hint := extractJoinHint(n); !hint.IsEmpty() {
+	hint := m.joinOrderHint()
+	if !hint.IsEmpty() {
 		// this should probably happen earlier, but the root is not
 		// populated before reordering
 		m.WithJoinOrder(hint)
@@ -199,7 +200,16 @@ func replanJoin(ctx *sql.Context, n *plan.JoinNode, a *Analyzer, scope *Scope) (
 	if err != nil {
 		return nil, err
 	}
-	return m.bestRootPlan()
+	traceJoinPlanning(ctx, m, hint)
+	ret, err := m.bestRootPlan()
+	if err != nil {
+		return nil, err
+	}
+	// Index selection after join planning needs the hints of the query block
+	if cn, ok := ret.(sql.CommentedNode); ok && n.Comment() != "" {
+		ret = cn.WithComment(n.Comment())
+	}
+	return ret, nil
 }
 
 // addLookupJoins prefixes memo join group expressions with indexed join
@@ -241,6 +251,7 @@ func addLookupJoins(m *Memo) error {
 		if err != nil {
 			return err
 		}
+		indexes = indexesAllowed(m.hints, indexUseJoin, attrSource, indexes)
 
 		if or, ok := join.filter[0].(*expression.Or); ok && len(join.filter) == 1 {
 			// Special case disjoint filter. The execution plan will perform an index
@@ -376,6 +387,7 @@ func addRightSemiJoins(m *Memo) error {
 		if err != nil {
 			return err
 		}
+		indexes = indexesAllowed(m.hints, indexUseJoin, attrSource, indexes)
 
 		// check that the right side is unique on the join keys
 		conds := collectJoinConds(attrSource, semi.filter...)
@@ -704,12 +716,14 @@ func addMergeJoins(m *Memo) error {
 		} else if lAttrSource == "" {
 			return nil
 		}
+		lIndexes = indexesAllowed(m.hints, indexUseJoin, lAttrSource, lIndexes)
 		rAttrSource, rIndexes, err := lookupCandidates(m.ctx, join.right.first, aliases)
 		if err != nil {
 			return err
 		} else if rAttrSource == "" {
 			return nil
 		}
+		rIndexes = indexesAllowed(m.hints, indexUseJoin, rAttrSource, rIndexes)
 
 		for i, f := range join.filter {
 			var l, r sql.Expression
@@ -863,52 +877,7 @@ func attrsRefSingleTableCol(e sql.Expression) (tableCol, bool) {
 	return tc, !invalid && tc.table != ""
 }
 
-func extractJoinHint(n *plan.JoinNode) JoinOrderHint {
-	if n.Comment() != "" {
-		return parseJoinHint(n.Comment())
-	}
-	return EmptyJoinOrder
-}
-
-var hintRegex = regexp.MustCompile("(\\s*[a-z_]+\\([^\\(]+\\)\\s*)+")
-
-// TODO: this is pretty nasty. Should be done in the parser instead.
-func parseJoinHint(comment string) JoinOrderHint {
-	comment = strings.TrimPrefix(comment, "/*+")
-	comment = strings.TrimSuffix(comment, "*/")
-	comment = strings.ToLower(strings.TrimSpace(comment))
-
-	hints := hintRegex.FindAll([]byte(comment), -1)
-
-	for _, hint := range hints {
-		hintStr := strings.TrimSpace(string(hint))
-		if strings.HasPrefix(string(hintStr), "join_order(") {
-			var tables []string
-			var table strings.Builder
-			for _, b := range hintStr[len("join_order("):] {
-				switch b {
-				case ',', ')':
-					tables = append(tables, strings.TrimSpace(table.String()))
-					table = strings.Builder{}
-				default:
-					table.WriteRune(b)
-				}
-			}
-
-			return JoinOrderHint{
-				tables: tables,
-			}
-		}
-	}
-
-	return EmptyJoinOrder
-}
-
-type QueryHint interface {
-	fmt.Stringer
-	HintType() string
-}
-
+// JoinOrderHint is the order of the tables of a join asked for by an optimizer hint, by name or alias.
 type JoinOrderHint struct {
 	tables []string
 }
@@ -917,11 +886,6 @@ var EmptyJoinOrder = JoinOrderHint{}
 
 func (j JoinOrderHint) String() string {
 	return "JOIN_ORDER(" + strings.Join(j.tables, ",") + ")"
-
-}
-
-func (j JoinOrderHint) HintType() string {
-	return "JOIN_ORDER"
 }
 
 func (j JoinOrderHint) IsEmpty() bool {
@@ -0,0 +1,90 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/parse"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+)
+
+// loadEvents loads any events that are required for a plan node to operate properly (except for event execution,
+// which is handled by the event scheduler).
+func loadEvents(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, sel RuleSelector) (sql.Node, transform.TreeIdentity, error) {
+	span, ctx := ctx.Span("loadEvents")
+	defer span.End()
+
+	return transform.Node(n, func(n sql.Node) (sql.Node, transform.TreeIdentity, error) {
+		switch node := n.(type) {
+		case *plan.ShowEvents:
+			loadedEvents, err := loadEventsFromDb(ctx, node.Database())
+			if err != nil {
+				return nil, transform.SameTree, err
+			}
+			if loadedEvents == nil {
+				loadedEvents = make([]plan.EventDetails, 0)
+			}
+			return node.WithEvents(loadedEvents), transform.NewTree, nil
+		case *plan.AlterEvent:
+			eventDb, ok := node.Database().(sql.EventDatabase)
+			if !ok {
+				return nil, transform.SameTree, sql.ErrEventsNotSupported.New(node.Database().Name())
+			}
+			event, exists, err := eventDb.GetEvent(ctx, node.EventName)
+			if err != nil {
+				return nil, transform.SameTree, err
+			} else if !exists {
+				return nil, transform.SameTree, sql.ErrEventDoesNotExist.New(node.EventName)
+			}
+			details, err := loadEventDetails(ctx, event)
+			if err != nil {
+				return nil, transform.SameTree, err
+			}
+			return node.WithEvent(details), transform.NewTree, nil
+		default:
+			return node, transform.SameTree, nil
+		}
+	})
+}
+
+func loadEventsFromDb(ctx *sql.Context, db sql.Database) ([]plan.EventDetails, error) {
+	var loadedEvents []plan.EventDetails
+	if eventDb, ok := db.(sql.EventDatabase); ok {
+		events, err := eventDb.GetEvents(ctx)
+		if err != nil {
+			if sql.ErrEventsNotSupported.Is(err) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		for _, event := range events {
+			details, err := loadEventDetails(ctx, event)
+			if err != nil {
+				return nil, err
+			}
+			loadedEvents = append(loadedEvents, details)
+		}
+	}
+	return loadedEvents, nil
+}
+
+func loadEventDetails(ctx *sql.Context, event sql.EventDefinition) (plan.EventDetails, error) {
+	parsedEvent, err := parse.Parse(ctx, event.CreateStatement)
+	if err != nil {
+		return plan.EventDetails{}, err
+	}
+	return plan.LoadEventDetails(ctx, event, parsedEvent)
+}
@@ -0,0 +1,89 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+)
+
+// validateLockedTables returns an error if the session holds table locks taken with LOCK TABLES, and the statement
+// uses a table that isn't one of them, or takes read locks with FLUSH TABLES. Like MySQL, temporary tables and the
+// tables of the system schemas can be used without being locked. Tables used by triggers and foreign keys aren't
+// checked.
+func validateLockedTables(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, sel RuleSelector) (sql.Node, transform.TreeIdentity, error) {
+	// Subqueries are checked along with the statement that contains them
+	if scope != nil || !a.Catalog.hasTableLocks(ctx.ID()) {
+		return n, transform.SameTree, nil
+	}
+	switch n := n.(type) {
+	case *plan.LockTables, *plan.UnlockTables:
+		return n, transform.SameTree, nil
+	case *plan.FlushTables:
+		if n.ReadLock {
+			return nil, transform.SameTree, sql.ErrLockOrActiveTransaction.New()
+		}
+	}
+
+	var err error
+	checkTable := func(rt *plan.ResolvedTable) {
+		if err != nil || rt.Database == nil || isUnlockedTable(rt) {
+			return
+		}
+		if !a.Catalog.isTableLocked(ctx.ID(), rt.Database.Name(), rt.Name()) {
+			err = sql.ErrTableNotLocked.New(rt.Name())
+		}
+	}
+
+	var check func(node sql.Node)
+	check = func(node sql.Node) {
+		transform.Inspect(node, func(node sql.Node) bool {
+			switch node := node.(type) {
+			case *plan.ResolvedTable:
+				checkTable(node)
+			case *plan.IndexedTableAccess:
+				checkTable(node.ResolvedTable)
+			case *plan.InsertInto:
+				check(node.Source)
+			}
+			return err == nil
+		})
+		transform.InspectExpressions(node, func(e sql.Expression) bool {
+			if sq, ok := e.(*plan.Subquery); ok && sq.Query != nil {
+				check(sq.Query)
+			}
+			return err == nil
+		})
+	}
+	check(n)
+
+	if err != nil {
+		return nil, transform.SameTree, err
+	}
+	return n, transform.SameTree, nil
+}
+
+// isUnlockedTable returns whether the table given can be used by a session without being locked by it.
+func isUnlockedTable(rt *plan.ResolvedTable) bool {
+	db := rt.Database.Name()
+	if db == "" || strings.EqualFold(db, sql.InformationSchemaDatabaseName) || strings.EqualFold(db, sql.PerformanceSchemaDatabaseName) {
+		return true
+	}
+	tt, ok := rt.Table.(sql.TemporaryTable)
+	return ok && tt.IsTemporary()
+}
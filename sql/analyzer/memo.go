@@ -38,12 +38,16 @@ type Memo struct {
 	root *exprGroup
 
 	orderHint *joinOrderDeps
-	c         Coster
-	s         Carder
-	statsRw   sql.StatsReadWriter
-	ctx       *sql.Context
-	scope     *Scope
-	scopeLen  int
+	// hints are the optimizer hints of the query block being planned
+	hints sql.OptimizerHints
+	// joinMethodHints are the hints that ask for or against a physical join method
+	joinMethodHints []joinMethodHint
+	c               Coster
+	s               Carder
+	statsRw         sql.StatsReadWriter
+	ctx             *sql.Context
+	scope           *Scope
+	scopeLen        int
 
 	tableProps *tableProps
 }
@@ -110,6 +114,7 @@ func (m *Memo) optimizeMemoGroup(grp *exprGroup) error {
 			return err
 		}
 		cost += relCost
+		n.setCost(cost)
 		m.updateBest(grp, n, cost)
 		n = n.next()
 	}
@@ -188,8 +193,169 @@ func (m *Memo) WithJoinOrder(hint JoinOrderHint) {
 	}
 }
 
+// WithHints sets the optimizer hints of the query block being planned. Hints that name a table that isn't part of
+// the join are ignored with a warning.
+func (m *Memo) WithHints(hints sql.OptimizerHints) {
+	m.hints = hints
+	m.joinMethodHints = nil
+	for _, h := range hints.OfType(sql.HintHashJoin, sql.HintNoHashJoin, sql.HintMergeJoin, sql.HintNoMergeJoin) {
+		if tables, ok := m.hintTables(h); ok {
+			m.joinMethodHints = append(m.joinMethodHints, joinMethodHint{typ: h.Type, tables: tables})
+		}
+	}
+}
+
+// joinOrderHint returns the join order asked for by the JOIN_ORDER, JOIN_PREFIX, JOIN_SUFFIX or JOIN_FIXED_ORDER
+// hint of the query block being planned, if any. Prefix and suffix hints are completed with the tables they don't
+// name in the order of the FROM clause.
+func (m *Memo) joinOrderHint() JoinOrderHint {
+	hints := m.hints.OfType(sql.HintJoinOrder, sql.HintJoinPrefix, sql.HintJoinSuffix, sql.HintJoinFixedOrder)
+	if len(hints) == 0 {
+		return EmptyJoinOrder
+	}
+	h := hints[0]
+	if _, ok := m.hintTables(h); !ok {
+		return EmptyJoinOrder
+	}
+	tables := make([]string, len(h.Tables))
+	for i, t := range h.Tables {
+		tables[i] = strings.ToLower(t.Name)
+	}
+	switch h.Type {
+	case sql.HintJoinPrefix:
+		tables = append(tables, m.tablesExcept(tables)...)
+	case sql.HintJoinSuffix:
+		tables = append(m.tablesExcept(tables), tables...)
+	case sql.HintJoinFixedOrder:
+		tables = m.tablesExcept(nil)
+	}
+	return JoinOrderHint{tables: tables}
+}
+
+// hintTables returns the ids of the tables named by the hint given, warning and returning false if any of them isn't
+// part of the join being planned.
+func (m *Memo) hintTables(h *sql.OptimizerHint) (sql.FastIntSet, bool) {
+	var tables sql.FastIntSet
+	for _, t := range h.Tables {
+		id, ok := m.tableProps.getId(strings.ToLower(t.Name))
+		if !ok {
+			m.ctx.Warn(sql.ERUnresolvedHintName, "Unresolved name %s for %s hint", t.String(), h.Type)
+			return tables, false
+		}
+		tables.Add(int(tableIdForSource(id)))
+	}
+	return tables, true
+}
+
+// tablesExcept returns the names of the tables of the join being planned in the order they were memoized, which is
+// the order of the FROM clause, leaving out those given.
+func (m *Memo) tablesExcept(except []string) []string {
+	var ret []string
+	for id := GroupId(1); id <= GroupId(m.cnt); id++ {
+		name, ok := m.tableProps.getTable(id)
+		if !ok {
+			continue
+		}
+		found := false
+		for _, e := range except {
+			if e == name {
+				found = true
+				break
+			}
+		}
+		if !found {
+			ret = append(ret, name)
+		}
+	}
+	return ret
+}
+
+// joinMethodHint is a HASH_JOIN, NO_HASH_JOIN, MERGE_JOIN or NO_MERGE_JOIN hint, along with the ids of the tables
+// it names.
+type joinMethodHint struct {
+	typ    sql.OptimizerHintType
+	tables sql.FastIntSet
+}
+
+// appliesTo returns whether the hint applies to the join given. A hint that names no tables applies to every join.
+// Otherwise it applies to the join that brings its tables together: either one side of the join is exactly its tables,
+// or its tables are split across the two sides.
+func (h joinMethodHint) appliesTo(join *joinBase) bool {
+	if h.tables.Empty() {
+		return true
+	}
+	left, right := join.left.relProps.OutputTables(), join.right.relProps.OutputTables()
+	if !h.tables.SubsetOf(left.Union(right)) {
+		return false
+	}
+	if h.tables.Equals(left) || h.tables.Equals(right) {
+		return true
+	}
+	return !h.tables.SubsetOf(left) && !h.tables.SubsetOf(right)
+}
+
+// obeysJoinMethodHints returns whether the plan given uses the join methods that the hints of the memo ask for.
+func (m *Memo) obeysJoinMethodHints(n relExpr) bool {
+	switch n := n.(type) {
+	case joinRel:
+		_, isHash := n.(*hashJoin)
+		_, isMerge := n.(*mergeJoin)
+		for _, h := range m.joinMethodHints {
+			if !h.appliesTo(n.joinPrivate()) {
+				continue
+			}
+			switch h.typ {
+			case sql.HintHashJoin:
+				if !isHash {
+					return false
+				}
+			case sql.HintNoHashJoin:
+				if isHash {
+					return false
+				}
+			case sql.HintMergeJoin:
+				if !isMerge {
+					return false
+				}
+			case sql.HintNoMergeJoin:
+				if isMerge {
+					return false
+				}
+			}
+		}
+		return true
+	case *project:
+		return m.obeysJoinMethodHints(n.child.best)
+	case *distinct:
+		return m.obeysJoinMethodHints(n.child.best)
+	default:
+		return true
+	}
+}
+
 func (m *Memo) String() string {
 	exprs := make([]string, m.cnt)
+	for i, g := range m.exprGroups() {
+		if g != nil {
+			exprs[i] = g.String()
+		}
+	}
+	b := strings.Builder{}
+	b.WriteString("memo:\n")
+	beg := "├──"
+	for i, g := range exprs {
+		if i == len(exprs)-1 {
+			beg = "└──"
+		}
+		b.WriteString(fmt.Sprintf("%s G%d: %s\n", beg, i+1, g))
+	}
+	return b.String()
+}
+
+// exprGroups returns the expression groups that are reachable from the
+// root, indexed by their id minus one. Unreachable groups are nil.
+func (m *Memo) exprGroups() []*exprGroup {
+	exprGroups := make([]*exprGroup, m.cnt)
 	groups := make([]*exprGroup, 0)
 	if m.root != nil {
 		r := m.root.first
@@ -202,24 +368,15 @@ func (m *Memo) String() string {
 	for len(groups) > 0 {
 		newGroups := make([]*exprGroup, 0)
 		for _, g := range groups {
-			if exprs[int(g.id)-1] != "" {
+			if exprGroups[int(g.id)-1] != nil {
 				continue
 			}
-			exprs[int(g.id)-1] = g.String()
+			exprGroups[int(g.id)-1] = g
 			newGroups = append(newGroups, g.children()...)
 		}
 		groups = newGroups
 	}
-	b := strings.Builder{}
-	b.WriteString("memo:\n")
-	beg := "├──"
-	for i, g := range exprs {
-		if i == len(exprs)-1 {
-			beg = "└──"
-		}
-		b.WriteString(fmt.Sprintf("%s G%d: %s\n", beg, i+1, g))
-	}
-	return b.String()
+	return exprGroups
 }
 
 // relProps are relational attributes shared by all plans in an expression
@@ -427,6 +584,16 @@ func (e *exprGroup) children() []*exprGroup {
 }
 
 func (e *exprGroup) updateBest(n relExpr, grpCost float64) {
+	if e.best != nil && len(e.m.joinMethodHints) > 0 {
+		// a plan that uses the join methods asked for by hints beats one that doesn't, regardless of cost
+		if bestObeys, nObeys := e.m.obeysJoinMethodHints(e.best), e.m.obeysJoinMethodHints(n); bestObeys != nObeys {
+			if nObeys {
+				e.best = n
+				e.cost = grpCost
+			}
+			return
+		}
+	}
 	if e.best == nil || grpCost <= e.cost || (!e.obeysOpHint(e.best) && e.obeysOpHint(n)) {
 		e.best = n
 		e.cost = grpCost
@@ -472,6 +639,8 @@ type relExpr interface {
 	setNext(relExpr)
 	children() []*exprGroup
 	setGroup(g *exprGroup)
+	cost() float64
+	setCost(c float64)
 }
 
 type relBase struct {
@@ -515,6 +684,10 @@ func (r *relBase) setNext(rel relExpr) {
 	r.n = rel
 }
 
+func (r *relBase) cost() float64 {
+	return r.c
+}
+
 func (r *relBase) setCost(c float64) {
 	r.c = c
 }
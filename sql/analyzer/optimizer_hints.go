@@ -0,0 +1,251 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+)
+
+// extractQueryHints returns the optimizer hints of the query block that the node given is the FROM clause of, or a
+// part of. The parser attaches the hints of each query block as a comment on the node of its FROM clause, so they're
+// found on the first commented node below the node given. Derived tables and subqueries are other query blocks, and
+// aren't searched.
+func extractQueryHints(n sql.Node) sql.OptimizerHints {
+	var comment string
+	transform.Inspect(n, func(n sql.Node) bool {
+		if comment != "" {
+			return false
+		}
+		switch n := n.(type) {
+		case sql.CommentedNode:
+			if n.Comment() != "" {
+				comment = n.Comment()
+				return false
+			}
+		case *plan.SubqueryAlias:
+			return false
+		}
+		_, opaque := n.(sql.OpaqueNode)
+		return !opaque
+	})
+	if comment == "" {
+		return nil
+	}
+	// Syntax errors were reported by the parser, and the hints before them are kept in the comment
+	hints, _ := sql.ParseOptimizerHints(comment)
+	return hints
+}
+
+// resolveIndexHints checks the indexes named by the index hints of each query block against the indexes of the tables
+// they name, warning about those that don't exist. The names of missing indexes are removed from their hints, and the
+// hints that name no existing index are dropped, so that they don't prevent the use of the table's other indexes.
+func resolveIndexHints(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, sel RuleSelector) (sql.Node, transform.TreeIdentity, error) {
+	return transform.Node(n, func(n sql.Node) (sql.Node, transform.TreeIdentity, error) {
+		commented, ok := n.(sql.CommentedNode)
+		if !ok || commented.Comment() == "" {
+			return n, transform.SameTree, nil
+		}
+		hints, _ := sql.ParseOptimizerHints(commented.Comment())
+		var tables map[string][]sql.Index
+		var resolved sql.OptimizerHints
+		changed := false
+		for _, h := range hints {
+			if len(h.Indexes) == 0 {
+				resolved = append(resolved, h)
+				continue
+			}
+			if tables == nil {
+				tables = queryBlockIndexes(ctx, n)
+			}
+			indexes, ok := tables[strings.ToLower(h.Table().Name)]
+			if !ok {
+				resolved = append(resolved, h)
+				continue
+			}
+			var names []string
+			for _, name := range h.Indexes {
+				if hasIndexNamed(indexes, name) {
+					names = append(names, name)
+				} else {
+					ctx.Warn(sql.ERUnresolvedHintName, "Unresolved name %s %s for %s hint", h.Table().String(), name, h.Type)
+				}
+			}
+			if len(names) != len(h.Indexes) {
+				changed = true
+				h.Indexes = names
+			}
+			if len(names) > 0 {
+				resolved = append(resolved, h)
+			}
+		}
+		if !changed {
+			return n, transform.SameTree, nil
+		}
+		return commented.WithComment(resolved.String()), transform.NewTree, nil
+	})
+}
+
+// queryBlockIndexes returns the indexes of the tables of the query block whose FROM clause is the node given, by the
+// lower-cased names the tables are referred to by. Derived tables are other query blocks, and aren't included.
+func queryBlockIndexes(ctx *sql.Context, n sql.Node) map[string][]sql.Index {
+	tables := make(map[string][]sql.Index)
+	transform.Inspect(n, func(n sql.Node) bool {
+		var name string
+		var rt *plan.ResolvedTable
+		switch n := n.(type) {
+		case *plan.TableAlias:
+			rt, _ = n.Child.(*plan.ResolvedTable)
+			name = n.Name()
+		case *plan.ResolvedTable:
+			rt, name = n, n.Name()
+		case *plan.SubqueryAlias:
+			return false
+		}
+		if rt != nil {
+			if _, ok := tables[strings.ToLower(name)]; !ok {
+				tables[strings.ToLower(name)] = tableIndexes(ctx, rt.Table)
+			}
+			return false
+		}
+		_, opaque := n.(sql.OpaqueNode)
+		return !opaque
+	})
+	return tables
+}
+
+// tableIndexes returns the indexes of the table given, or nil if it has none.
+func tableIndexes(ctx *sql.Context, table sql.Table) []sql.Index {
+	for {
+		wrapper, ok := table.(sql.TableWrapper)
+		if !ok {
+			break
+		}
+		table = wrapper.Underlying()
+	}
+	addressable, ok := table.(sql.IndexAddressable)
+	if !ok {
+		return nil
+	}
+	indexes, err := addressable.GetIndexes(ctx)
+	if err != nil {
+		return nil
+	}
+	return indexes
+}
+
+func hasIndexNamed(indexes []sql.Index, name string) bool {
+	for _, idx := range indexes {
+		if strings.EqualFold(idx.ID(), name) {
+			return true
+		}
+	}
+	return false
+}
+
+// semiJoinAllowed returns whether the subquery given may be converted into a semi or anti join, which its NO_SEMIJOIN
+// hint prevents.
+func semiJoinAllowed(subquery sql.Node) bool {
+	return len(extractQueryHints(subquery).OfType(sql.HintNoSemiJoin)) == 0
+}
+
+// indexHintUse is a way the optimizer considers using an index, which decides the index hints that apply to it.
+type indexHintUse uint8
+
+const (
+	// indexUseRange is an index lookup on the filters of a table
+	indexUseRange indexHintUse = iota
+	// indexUseJoin is an index lookup of a lookup join, or an index scan of a merge join
+	indexUseJoin
+	// indexUseOrder is an index scan that provides the order of a sort
+	indexUseOrder
+)
+
+// hintTypes returns the hints that restrict the indexes that may be used as given, and those that prevent indexes
+// from being used as given.
+func (u indexHintUse) hintTypes() (allow, deny []sql.OptimizerHintType) {
+	switch u {
+	case indexUseRange:
+		return []sql.OptimizerHintType{sql.HintIndex, sql.HintJoinIndex},
+			[]sql.OptimizerHintType{sql.HintNoIndex, sql.HintNoJoinIndex, sql.HintNoRangeOptimization}
+	case indexUseJoin:
+		return []sql.OptimizerHintType{sql.HintIndex, sql.HintJoinIndex},
+			[]sql.OptimizerHintType{sql.HintNoIndex, sql.HintNoJoinIndex}
+	case indexUseOrder:
+		return []sql.OptimizerHintType{sql.HintIndex, sql.HintOrderIndex},
+			[]sql.OptimizerHintType{sql.HintNoIndex, sql.HintNoOrderIndex}
+	default:
+		return nil, nil
+	}
+}
+
+// indexAllowed returns whether the hints given allow the index given of the table named, by its alias if it has one,
+// to be used as given. A NO_INDEX-style hint prevents the use of the indexes it names, and an INDEX-style hint
+// prevents the use of any index it doesn't name. A hint that names no indexes applies to all indexes of its table.
+func indexAllowed(hints sql.OptimizerHints, use indexHintUse, table string, index sql.Index) bool {
+	allow, deny := use.hintTypes()
+	restricted, allowed := false, false
+	for _, h := range hints {
+		if !strings.EqualFold(h.Table().Name, table) {
+			continue
+		}
+		if hasHintType(deny, h.Type) && hintNamesIndex(h, index) {
+			return false
+		}
+		if hasHintType(allow, h.Type) {
+			restricted = true
+			allowed = allowed || hintNamesIndex(h, index)
+		}
+	}
+	return !restricted || allowed
+}
+
+// indexesAllowed returns the indexes of those given that the hints given allow to be used as given.
+func indexesAllowed(hints sql.OptimizerHints, use indexHintUse, table string, indexes []sql.Index) []sql.Index {
+	if len(hints) == 0 {
+		return indexes
+	}
+	var ret []sql.Index
+	for _, idx := range indexes {
+		if indexAllowed(hints, use, table, idx) {
+			ret = append(ret, idx)
+		}
+	}
+	return ret
+}
+
+func hintNamesIndex(h *sql.OptimizerHint, index sql.Index) bool {
+	if len(h.Indexes) == 0 {
+		return true
+	}
+	for _, name := range h.Indexes {
+		if strings.EqualFold(name, index.ID()) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasHintType(types []sql.OptimizerHintType, t sql.OptimizerHintType) bool {
+	for _, tt := range types {
+		if tt == t {
+			return true
+		}
+	}
+	return false
+}
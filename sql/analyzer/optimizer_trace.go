@@ -0,0 +1,246 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/information_schema"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+)
+
+// optimizerTraceKey is the key of the optimizerTrace of the statement being analyzed in the context.
+type optimizerTraceKey struct{}
+
+// optimizerTrace records the batches and rules that transformed a statement's plan, along with the memo groups that
+// were considered while planning its joins. It is recorded when the optimizer_trace system variable is set to
+// "enabled=on", and shown as JSON in information_schema.OPTIMIZER_TRACE.
+type optimizerTrace struct {
+	Steps []*optimizerTraceStep `json:"steps"`
+}
+
+// optimizerTraceStep is a single step of an optimizerTrace. Exactly one of its fields is set.
+type optimizerTraceStep struct {
+	Batch        *batchTrace        `json:"batch,omitempty"`
+	JoinPlanning *joinPlanningTrace `json:"join_planning,omitempty"`
+}
+
+// batchTrace records the evaluation of a Batch. Only the rules that changed the plan are recorded.
+type batchTrace struct {
+	Name       string       `json:"name"`
+	Depth      int          `json:"depth"`
+	Iterations int          `json:"iterations"`
+	Rules      []*ruleTrace `json:"rules"`
+}
+
+// ruleTrace records a rule that changed the plan, along with the plan before and after the rule was applied.
+type ruleTrace struct {
+	Name      string `json:"name"`
+	Iteration int    `json:"iteration"`
+	Before    string `json:"before"`
+	After     string `json:"after"`
+}
+
+// joinPlanningTrace records the memo groups of a join tree, along with the plans in each group and their costs.
+type joinPlanningTrace struct {
+	Depth     int               `json:"depth"`
+	JoinOrder []string          `json:"join_order_hint,omitempty"`
+	Groups    []*memoGroupTrace `json:"memo_groups"`
+}
+
+// memoGroupTrace records a memo group, and the plan that was chosen for it.
+type memoGroupTrace struct {
+	Id          GroupId          `json:"id"`
+	Plans       []*memoPlanTrace `json:"plans"`
+	Best        string           `json:"best"`
+	Cost        float64          `json:"cost"`
+	Cardinality float64          `json:"cardinality"`
+}
+
+// memoPlanTrace records a plan of a memo group, and its cost including the cost of its children.
+type memoPlanTrace struct {
+	Plan string  `json:"plan"`
+	Cost float64 `json:"cost"`
+}
+
+// getOptimizerTrace returns the optimizerTrace of the statement being analyzed, or nil if it is not being traced.
+func getOptimizerTrace(ctx *sql.Context) *optimizerTrace {
+	if ctx == nil || ctx.Context == nil {
+		return nil
+	}
+	trace, _ := ctx.Value(optimizerTraceKey{}).(*optimizerTrace)
+	return trace
+}
+
+// startOptimizerTrace starts tracing the analysis of a statement if the session enabled the optimizer trace, and no
+// trace was already started for the statement. Returns the context to use for the analysis, along with the new trace,
+// or nil if no trace was started.
+func startOptimizerTrace(ctx *sql.Context) (*sql.Context, *optimizerTrace) {
+	if ctx == nil || ctx.Session == nil || getOptimizerTrace(ctx) != nil {
+		return ctx, nil
+	}
+	if enabled, _, err := sql.OptimizerTraceFlags(ctx); err != nil || !enabled {
+		return ctx, nil
+	}
+	trace := &optimizerTrace{Steps: make([]*optimizerTraceStep, 0)}
+	return ctx.WithContext(context.WithValue(ctx.Context, optimizerTraceKey{}, trace)), trace
+}
+
+// finishOptimizerTrace stores the given trace of the analysis of the node given in the session, unless the node reads
+// the traces themselves.
+func finishOptimizerTrace(ctx *sql.Context, trace *optimizerTrace, n sql.Node) {
+	if trace == nil || readsOptimizerTrace(n) {
+		return
+	}
+	_, oneLine, err := sql.OptimizerTraceFlags(ctx)
+	if err != nil {
+		return
+	}
+	offset, limit, err := sql.OptimizerTraceWindow(ctx)
+	if err != nil {
+		return
+	}
+	maxMemSize, err := sql.OptimizerTraceMaxMemSize(ctx)
+	if err != nil {
+		return
+	}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if !oneLine {
+		enc.SetIndent("", "  ")
+	}
+	if err = enc.Encode(trace); err != nil {
+		return
+	}
+	text := strings.TrimSuffix(buf.String(), "\n")
+	missingBytes := 0
+	if maxMemSize >= 0 && int64(len(text)) > maxMemSize {
+		missingBytes = len(text) - int(maxMemSize)
+		text = text[:maxMemSize]
+	}
+
+	traces := sql.AppendOptimizerTrace(ctx.Session.GetOptimizerTraces(), sql.OptimizerTrace{
+		Query:                        ctx.Query(),
+		Trace:                        text,
+		MissingBytesBeyondMaxMemSize: missingBytes,
+	}, offset, limit)
+	ctx.Session.SetOptimizerTraces(traces)
+}
+
+// readsOptimizerTrace returns whether the node given reads information_schema.OPTIMIZER_TRACE, in which case it is not
+// traced, so that it doesn't replace the traces that it reads.
+func readsOptimizerTrace(n sql.Node) bool {
+	if n == nil {
+		return false
+	}
+	found := false
+	transform.Inspect(n, func(n sql.Node) bool {
+		if found {
+			return false
+		}
+		if rt, ok := n.(*plan.ResolvedTable); ok && rt.Database != nil &&
+			strings.EqualFold(rt.Database.Name(), sql.InformationSchemaDatabaseName) &&
+			strings.EqualFold(rt.Name(), information_schema.OptimizerTraceTableName) {
+			found = true
+			return false
+		}
+		return true
+	})
+	if !found {
+		transform.InspectExpressions(n, func(e sql.Expression) bool {
+			if sq, ok := e.(*plan.Subquery); ok && !found {
+				found = readsOptimizerTrace(sq.Query)
+			}
+			return !found
+		})
+	}
+	return found
+}
+
+// traceBatch starts recording the evaluation of the batch given, if the statement is being traced. Returns nil
+// otherwise.
+func traceBatch(ctx *sql.Context, b *Batch, scope *Scope) *batchTrace {
+	trace := getOptimizerTrace(ctx)
+	if trace == nil {
+		return nil
+	}
+	bt := &batchTrace{
+		Name:  b.Desc,
+		Depth: scope.RecursionDepth(),
+		Rules: make([]*ruleTrace, 0),
+	}
+	trace.Steps = append(trace.Steps, &optimizerTraceStep{Batch: bt})
+	return bt
+}
+
+// addRule records that the rule given changed the plan from |prev| to |next|. Rules that report a change without
+// changing the plan's description are not recorded.
+func (bt *batchTrace) addRule(id RuleId, prev, next sql.Node) {
+	if bt == nil || next == nil {
+		return
+	}
+	before, after := sql.DebugString(prev), sql.DebugString(next)
+	if before == after {
+		return
+	}
+	bt.Rules = append(bt.Rules, &ruleTrace{
+		Name:      id.String(),
+		Iteration: bt.Iterations,
+		Before:    before,
+		After:     after,
+	})
+}
+
+// traceJoinPlanning records the memo groups of the optimized memo given, if the statement is being traced.
+func traceJoinPlanning(ctx *sql.Context, m *Memo, hint JoinOrderHint) {
+	trace := getOptimizerTrace(ctx)
+	if trace == nil {
+		return
+	}
+	jt := &joinPlanningTrace{
+		Depth:  m.scope.RecursionDepth(),
+		Groups: make([]*memoGroupTrace, 0),
+	}
+	if m.orderHint != nil {
+		jt.JoinOrder = hint.tables
+	}
+	for _, grp := range m.exprGroups() {
+		if grp == nil || grp.best == nil {
+			continue
+		}
+		gt := &memoGroupTrace{
+			Id:          grp.id,
+			Plans:       make([]*memoPlanTrace, 0),
+			Best:        formatRelExpr(grp.best),
+			Cost:        grp.cost,
+			Cardinality: grp.relProps.card,
+		}
+		for n := grp.first; n != nil; n = n.next() {
+			gt.Plans = append(gt.Plans, &memoPlanTrace{
+				Plan: formatRelExpr(n),
+				Cost: n.cost(),
+			})
+		}
+		jt.Groups = append(jt.Groups, gt)
+	}
+	trace.Steps = append(trace.Steps, &optimizerTraceStep{JoinPlanning: jt})
+}
@@ -21,6 +21,8 @@ import (
 	"github.com/go-kit/kit/metrics/discard"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/expression/function/aggregation"
 	"github.com/dolthub/go-mysql-server/sql/plan"
 	"github.com/dolthub/go-mysql-server/sql/transform"
 )
@@ -42,6 +44,11 @@ var (
 	ParallelQueryCounter = discard.NewCounter()
 
 	SingleThreadFeatureFlag = false
+
+	// ParallelOperatorMinRows is the number of rows a partitioned table needs before the aggregations and sorts over
+	// an exchange of it are split across the workers of the exchange. Smaller tables are aggregated and sorted by a
+	// single thread.
+	ParallelOperatorMinRows uint64 = 10000
 )
 
 func shouldParallelize(node sql.Node, scope *Scope) bool {
@@ -89,7 +96,126 @@ func parallelize(ctx *sql.Context, a *Analyzer, node sql.Node, scope *Scope, sel
 		return node, transform.SameTree, nil
 	}
 
-	return transform.Node(node, removeRedundantExchanges)
+	node, _, err = transform.Node(node, removeRedundantExchanges)
+	if err != nil {
+		return nil, transform.SameTree, err
+	}
+
+	node, _, err = transform.Node(node, func(node sql.Node) (sql.Node, transform.TreeIdentity, error) {
+		return parallelizeOperator(ctx, node)
+	})
+	return node, transform.NewTree, err
+}
+
+// parallelizeOperator moves the work of a GroupBy or Sort node over an exchange into the workers of the exchange,
+// when the exchange reads a table large enough and with enough partitions for it to pay off. Each worker computes
+// partial aggregations of its rows, which a GroupBy over the exchange combines, or sorts its rows, which the exchange
+// merges.
+func parallelizeOperator(ctx *sql.Context, node sql.Node) (sql.Node, transform.TreeIdentity, error) {
+	switch n := node.(type) {
+	case *plan.GroupBy:
+		exchange, ok := n.Child.(*plan.Exchange)
+		if !ok || len(exchange.SortFields) > 0 || !shouldParallelizeOperator(ctx, exchange) {
+			return node, transform.SameTree, nil
+		}
+		return splitGroupBy(n, exchange)
+	case *plan.Sort:
+		exchange, ok := n.Child.(*plan.Exchange)
+		if !ok || len(exchange.SortFields) > 0 || containsAnySubquery(n.Expressions()...) || !shouldParallelizeOperator(ctx, exchange) {
+			return node, transform.SameTree, nil
+		}
+		sort := plan.NewSort(n.SortFields, exchange.Child)
+		return plan.NewOrderedExchange(exchange.Parallelism, n.SortFields, sort), transform.NewTree, nil
+	default:
+		return node, transform.SameTree, nil
+	}
+}
+
+// shouldParallelizeOperator returns whether the table the exchange given reads has at least two partitions and
+// ParallelOperatorMinRows rows. Tables that don't report their partition count and row count are left alone.
+func shouldParallelizeOperator(ctx *sql.Context, exchange *plan.Exchange) bool {
+	var table sql.Table
+	transform.Inspect(exchange.Child, func(node sql.Node) bool {
+		if t, ok := node.(sql.Table); ok && table == nil {
+			table = t
+		}
+		return table == nil
+	})
+	if rt, ok := table.(*plan.ResolvedTable); ok {
+		table = rt.Table
+	}
+	for {
+		w, ok := table.(sql.TableWrapper)
+		if !ok {
+			break
+		}
+		table = w.Underlying()
+	}
+
+	pc, ok := table.(sql.PartitionCounter)
+	if !ok {
+		return false
+	}
+	partitions, err := pc.PartitionCount(ctx)
+	if err != nil || partitions < 2 {
+		return false
+	}
+	st, ok := table.(sql.StatisticsTable)
+	if !ok {
+		return false
+	}
+	rows, err := st.RowCount(ctx)
+	return err == nil && rows >= ParallelOperatorMinRows
+}
+
+// splitGroupBy splits the GroupBy given into a GroupBy computing partial aggregations, run by every worker of the
+// exchange given, and a GroupBy combining the partial aggregations of the workers. The GroupBy is left as is if any
+// of its aggregations can't be split.
+func splitGroupBy(groupBy *plan.GroupBy, exchange *plan.Exchange) (sql.Node, transform.TreeIdentity, error) {
+	if containsAnySubquery(groupBy.Expressions()...) {
+		return groupBy, transform.SameTree, nil
+	}
+
+	// The partial GroupBy returns the partial aggregations of every aggregation, or the value of every other selected
+	// expression, followed by the grouping keys. The final GroupBy refers to those by their index.
+	var partialSelected []sql.Expression
+	finalSelected := make([]sql.Expression, len(groupBy.SelectedExprs))
+	schema := groupBy.Schema()
+	for i, e := range groupBy.SelectedExprs {
+		agg, ok := e.(sql.Aggregation)
+		if !ok {
+			if containsAggregation(e) {
+				return groupBy, transform.SameTree, nil
+			}
+			finalSelected[i] = expression.NewGetFieldWithTable(len(partialSelected), e.Type(), schema[i].Source, schema[i].Name, e.IsNullable())
+			partialSelected = append(partialSelected, e)
+			continue
+		}
+
+		parts, ok := aggregation.Decompose(agg)
+		if !ok {
+			return groupBy, transform.SameTree, nil
+		}
+		partials := make([]sql.Expression, len(parts))
+		for j, part := range parts {
+			partials[j] = expression.NewGetField(len(partialSelected), part.Type(), part.String(), true)
+			partialSelected = append(partialSelected, part)
+		}
+		finalSelected[i] = aggregation.NewFinal(agg, partials...)
+	}
+
+	finalGrouping := make([]sql.Expression, len(groupBy.GroupByExprs))
+	for i, e := range groupBy.GroupByExprs {
+		finalGrouping[i] = expression.NewGetField(len(partialSelected), e.Type(), e.String(), e.IsNullable())
+		partialSelected = append(partialSelected, e)
+	}
+
+	partial := plan.NewGroupBy(partialSelected, groupBy.GroupByExprs, exchange.Child)
+	final := plan.NewGroupBy(finalSelected, finalGrouping, plan.NewExchange(exchange.Parallelism, partial))
+	if !final.Schema().Equals(schema) {
+		return groupBy, transform.SameTree, nil
+	}
+	return final, transform.NewTree, nil
 }
 
 // removeRedundantExchanges removes all the exchanges except for the topmost
@@ -200,3 +326,13 @@ func isParallelizable(node sql.Node) bool {
 
 	return parallelizable && tableSeen && lastWasTable
 }
+
+// containsAnySubquery returns whether any of the expressions given contains a subquery.
+func containsAnySubquery(exprs ...sql.Expression) bool {
+	for _, e := range exprs {
+		if containsSubquery(e) {
+			return true
+		}
+	}
+	return false
+}
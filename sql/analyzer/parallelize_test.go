@@ -22,6 +22,7 @@ import (
 	"github.com/dolthub/go-mysql-server/memory"
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/expression/function/aggregation"
 	"github.com/dolthub/go-mysql-server/sql/expression/function/aggregation/window"
 	"github.com/dolthub/go-mysql-server/sql/plan"
 	"github.com/dolthub/go-mysql-server/sql/transform"
@@ -73,6 +74,135 @@ func TestParallelize(t *testing.T) {
 	require.Equal(expected, result)
 }
 
+func TestParallelizeOperators(t *testing.T) {
+	defer func(minRows uint64) {
+		ParallelOperatorMinRows = minRows
+	}(ParallelOperatorMinRows)
+	ParallelOperatorMinRows = 10
+
+	ctx := sql.NewEmptyContext()
+	table := memory.NewPartitionedTable("t", sql.NewPrimaryKeySchema(sql.Schema{
+		{Name: "i", Type: types.Int64, Source: "t"},
+		{Name: "s", Type: types.Text, Source: "t"},
+	}), nil, 4)
+	for i := int64(0); i < 20; i++ {
+		require.NoError(t, table.Insert(ctx, sql.NewRow(i, []string{"a", "b", "c"}[i%3])))
+	}
+	rule := getRuleFrom(OnceAfterAll, parallelizeId)
+
+	i := expression.NewGetFieldWithTable(0, types.Int64, "t", "i", false)
+	s := expression.NewGetFieldWithTable(1, types.Text, "t", "s", false)
+	sortFields := sql.SortFields{{Column: s, Order: sql.Ascending}, {Column: i, Order: sql.Descending}}
+
+	testCases := []struct {
+		name     string
+		node     sql.Node
+		minRows  uint64
+		expected string
+	}{
+		{
+			name: "aggregations are split",
+			node: plan.NewGroupBy(
+				[]sql.Expression{
+					expression.NewAlias("x", s),
+					aggregation.NewSum(i),
+					aggregation.NewCount(expression.NewStar()),
+					aggregation.NewAvg(i),
+					aggregation.NewMin(i),
+					aggregation.NewBitXor(i),
+				},
+				[]sql.Expression{s},
+				plan.NewResolvedTable(table, nil, nil),
+			),
+			expected: "GroupBy\n" +
+				" ├─ select: x:0!null, FINAL SUM(SUM(t.i):1), FINAL COUNT(COUNT(*):2), FINAL AVG(SUM(t.i):3, COUNT(t.i):4), FINAL MIN(MIN(t.i):5), FINAL BITXOR(BITXOR(t.i):6)\n" +
+				" ├─ group: t.s:7!null\n" +
+				" └─ Exchange(parallelism=2)\n" +
+				"     └─ GroupBy\n" +
+				"         ├─ select: t.s:1!null as x, SUM(t.i:0!null), COUNT(*), SUM(t.i:0!null), COUNT(t.i:0!null), MIN(t.i:0!null), BITXOR(t.i:0!null), t.s:1!null\n" +
+				"         ├─ group: t.s:1!null\n" +
+				"         └─ Table\n" +
+				"             ├─ name: t\n" +
+				"             └─ columns: [i s]\n",
+		},
+		{
+			name: "aggregations without grouping are split",
+			node: plan.NewGroupBy(
+				[]sql.Expression{aggregation.NewMax(i), aggregation.NewBitOr(i)},
+				nil,
+				plan.NewResolvedTable(table, nil, nil),
+			),
+			expected: "GroupBy\n" +
+				" ├─ select: FINAL MAX(MAX(t.i):0), FINAL BITOR(BITOR(t.i):1)\n" +
+				" ├─ group: \n" +
+				" └─ Exchange(parallelism=2)\n" +
+				"     └─ GroupBy\n" +
+				"         ├─ select: MAX(t.i:0!null), BITOR(t.i:0!null)\n" +
+				"         ├─ group: \n" +
+				"         └─ Table\n" +
+				"             ├─ name: t\n" +
+				"             └─ columns: [i s]\n",
+		},
+		{
+			name: "distinct aggregations are not split",
+			node: plan.NewGroupBy(
+				[]sql.Expression{aggregation.NewSum(i), aggregation.NewCountDistinct(s)},
+				nil,
+				plan.NewResolvedTable(table, nil, nil),
+			),
+			expected: "GroupBy\n" +
+				" ├─ select: SUM(t.i:0!null), COUNTDISTINCT([t.s])\n" +
+				" ├─ group: \n" +
+				" └─ Exchange(parallelism=2)\n" +
+				"     └─ Table\n" +
+				"         ├─ name: t\n" +
+				"         └─ columns: [i s]\n",
+		},
+		{
+			name: "sorts are split",
+			node: plan.NewSort(sortFields, plan.NewResolvedTable(table, nil, nil)),
+			expected: "Exchange(parallelism=2, order: t.s:1!null ASC nullsFirst, t.i:0!null DESC nullsFirst)\n" +
+				" └─ Sort(t.s:1!null ASC nullsFirst, t.i:0!null DESC nullsFirst)\n" +
+				"     └─ Table\n" +
+				"         ├─ name: t\n" +
+				"         └─ columns: [i s]\n",
+		},
+		{
+			name:    "small tables are not split",
+			node:    plan.NewSort(sortFields, plan.NewResolvedTable(table, nil, nil)),
+			minRows: 100,
+			expected: "Sort(t.s:1!null ASC nullsFirst, t.i:0!null DESC nullsFirst)\n" +
+				" └─ Exchange(parallelism=2)\n" +
+				"     └─ Table\n" +
+				"         ├─ name: t\n" +
+				"         └─ columns: [i s]\n",
+		},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			ParallelOperatorMinRows = 10
+			if tt.minRows > 0 {
+				ParallelOperatorMinRows = tt.minRows
+			}
+			result, _, err := rule.Apply(ctx, &Analyzer{Parallelism: 2}, tt.node, nil, DefaultRuleSelector)
+			require.NoError(t, err)
+			require.Equal(t, tt.expected, sql.DebugString(result))
+			require.Equal(t, tt.node.Schema(), result.Schema())
+
+			expected, err := sql.NodeToRows(ctx, tt.node)
+			require.NoError(t, err)
+			rows, err := sql.NodeToRows(ctx, result)
+			require.NoError(t, err)
+			if _, ok := tt.node.(*plan.Sort); ok {
+				require.Equal(t, expected, rows)
+			} else {
+				require.ElementsMatch(t, expected, rows)
+			}
+		})
+	}
+}
+
 func TestParallelizeCreateIndex(t *testing.T) {
 	require := require.New(t)
 	table := memory.NewTable("t", sql.PrimaryKeySchema{}, nil)
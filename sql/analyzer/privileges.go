@@ -15,8 +15,11 @@
 package analyzer
 
 import (
+	"strings"
+
 	"github.com/dolthub/vitess/go/mysql"
 
+	"github.com/dolthub/go-mysql-server/sql/expression"
 	"github.com/dolthub/go-mysql-server/sql/transform"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -29,8 +32,9 @@ import (
 func validatePrivileges(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, sel RuleSelector) (sql.Node, transform.TreeIdentity, error) {
 	mysqlDb := a.Catalog.MySQLDb
 	switch n.(type) {
-	case *plan.CreateUser, *plan.DropUser, *plan.RenameUser, *plan.CreateRole, *plan.DropRole,
-		*plan.Grant, *plan.GrantRole, *plan.GrantProxy, *plan.Revoke, *plan.RevokeRole, *plan.RevokeAll, *plan.RevokeProxy:
+	case *plan.CreateUser, *plan.AlterUser, *plan.DropUser, *plan.RenameUser, *plan.CreateRole, *plan.DropRole,
+		*plan.Grant, *plan.GrantRole, *plan.GrantProxy, *plan.Revoke, *plan.RevokeRole, *plan.RevokeAll, *plan.RevokeProxy,
+		*plan.SetDefaultRole:
 		mysqlDb.Enabled = true
 	}
 	if !mysqlDb.Enabled {
@@ -42,6 +46,12 @@ func validatePrivileges(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope,
 	if user == nil {
 		return nil, transform.SameTree, mysql.NewSQLError(mysql.ERAccessDeniedError, mysql.SSAccessDeniedError, "Access denied for user '%v'", ctx.Session.Client().User)
 	}
+	if mysqlDb.IsPasswordExpired(user) && !allowedWithExpiredPassword(ctx, n) {
+		return nil, transform.SameTree, sql.ErrMustResetPassword.New()
+	}
+	// Computing the privilege set also refreshes the session's active roles, which are read by CURRENT_ROLE() and the
+	// role tables in information_schema
+	mysqlDb.UserActivePrivilegeSet(ctx)
 	if plan.IsDualTable(getTable(n)) {
 		return n, transform.SameTree, nil
 	}
@@ -53,3 +63,155 @@ func validatePrivileges(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope,
 	}
 	return n, transform.SameTree, nil
 }
+
+// allowedWithExpiredPassword returns whether the given statement may be run by a user whose password has expired. Such
+// users are restricted to resetting their own password, along with setting variables as clients commonly do when
+// connecting.
+func allowedWithExpiredPassword(ctx *sql.Context, n sql.Node) bool {
+	switch n := n.(type) {
+	case *plan.AlterUser:
+		return n.OnlyChangesOwnPassword(ctx)
+	case *plan.Set:
+		return true
+	default:
+		return false
+	}
+}
+
+// validateColumnPrivileges verifies that the calling user has privileges on every column that the given statement
+// reads or writes, for the tables where the user only holds column-level privileges. validatePrivileges accepts a
+// column-level privilege on any column as access to the table, as the columns used are not yet known when it runs.
+// This runs once the columns have been resolved, which includes those expanded from a star expression.
+func validateColumnPrivileges(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, sel RuleSelector) (sql.Node, transform.TreeIdentity, error) {
+	mysqlDb := a.Catalog.MySQLDb
+	if !mysqlDb.Enabled {
+		return n, transform.SameTree, nil
+	}
+
+	privSet := mysqlDb.UserActivePrivilegeSet(ctx)
+	checker := &columnPrivilegeChecker{ctx: ctx, privSet: privSet}
+	checker.checkNode(n, nil)
+	for _, op := range checker.operations {
+		if !mysqlDb.UserHasPrivileges(ctx, op) {
+			client := ctx.Session.Client()
+			user := mysqlDb.GetUser(client.User, client.Address, false)
+			if user == nil {
+				return nil, transform.SameTree, mysql.NewSQLError(mysql.ERAccessDeniedError, mysql.SSAccessDeniedError, "Access denied for user '%v'", client.User)
+			}
+			return nil, transform.SameTree, sql.ErrColumnPrivilegeCheckFailed.New(
+				strings.ToUpper(op.Privileges[0].String()), user.UserHostToString("'"), op.Column, op.Table)
+		}
+	}
+	return n, transform.SameTree, nil
+}
+
+// columnPrivilegeTable is a table that may be referenced by the columns in a statement.
+type columnPrivilegeTable struct {
+	database string
+	table    string
+}
+
+// columnPrivilegeChecker gathers the column-level operations performed by a statement.
+type columnPrivilegeChecker struct {
+	ctx        *sql.Context
+	privSet    sql.PrivilegeSet
+	operations []sql.PrivilegedOperation
+}
+
+// checkNode gathers the operations of the given node, which forms a scope along with any outer scopes that its
+// subqueries may reference. Subquery aliases are their own scope, and shadow any table with the same name.
+func (c *columnPrivilegeChecker) checkNode(n sql.Node, outerTables map[string]*columnPrivilegeTable) {
+	tables := make(map[string]*columnPrivilegeTable, len(outerTables))
+	for name, tbl := range outerTables {
+		tables[name] = tbl
+	}
+	transform.Inspect(n, func(n sql.Node) bool {
+		switch n := n.(type) {
+		case *plan.ResolvedTable:
+			tables[strings.ToLower(n.Name())] = newColumnPrivilegeTable(n)
+		case *plan.TableAlias:
+			if rt, ok := n.Child.(*plan.ResolvedTable); ok {
+				tables[strings.ToLower(n.Name())] = newColumnPrivilegeTable(rt)
+			} else {
+				tables[strings.ToLower(n.Name())] = nil
+			}
+			return false
+		case *plan.SubqueryAlias:
+			tables[strings.ToLower(n.Name())] = nil
+			c.checkNode(n.Child, nil)
+			return false
+		}
+		return true
+	})
+
+	transform.Inspect(n, func(n sql.Node) bool {
+		switch n := n.(type) {
+		case *plan.SubqueryAlias:
+			return false
+		case *plan.InsertInto:
+			// The source is analyzed on its own, and the checks may use any column of the destination
+			if rt := getResolvedTable(n.Destination); rt != nil {
+				for _, colName := range n.ColumnNames {
+					c.addOperation(newColumnPrivilegeTable(rt), colName, sql.PrivilegeType_Insert)
+				}
+			}
+			for _, e := range n.OnDupExprs {
+				c.checkExpression(e, tables)
+			}
+			return true
+		case *plan.Update:
+			// The checks may use any column of the table being updated
+			return true
+		case sql.Expressioner:
+			for _, e := range n.Expressions() {
+				c.checkExpression(e, tables)
+			}
+		}
+		return true
+	})
+}
+
+// checkExpression gathers the operations of the given expression. Columns that are assigned are updated, while all
+// other columns are read.
+func (c *columnPrivilegeChecker) checkExpression(e sql.Expression, tables map[string]*columnPrivilegeTable) {
+	sql.Inspect(e, func(e sql.Expression) bool {
+		switch e := e.(type) {
+		case *expression.SetField:
+			if gf, ok := e.Left.(*expression.GetField); ok {
+				c.addOperation(tables[strings.ToLower(gf.Table())], gf.Name(), sql.PrivilegeType_Update)
+			}
+			c.checkExpression(e.Right, tables)
+			return false
+		case *expression.GetField:
+			c.addOperation(tables[strings.ToLower(e.Table())], e.Name(), sql.PrivilegeType_Select)
+		case *plan.Subquery:
+			c.checkNode(e.Query, tables)
+			return false
+		case *sql.ColumnDefaultValue:
+			return false
+		}
+		return true
+	})
+}
+
+// addOperation adds an operation on the given column, unless the column does not belong to a table, or the user has
+// no column-level privileges on the table, in which case validatePrivileges has already checked the table.
+func (c *columnPrivilegeChecker) addOperation(tbl *columnPrivilegeTable, colName string, priv sql.PrivilegeType) {
+	if tbl == nil {
+		return
+	}
+	if len(c.privSet.Database(tbl.database).Table(tbl.table).GetColumns()) == 0 {
+		return
+	}
+	c.operations = append(c.operations, sql.NewPrivilegedOperation(tbl.database, tbl.table, colName, priv))
+}
+
+func newColumnPrivilegeTable(rt *plan.ResolvedTable) *columnPrivilegeTable {
+	if rt.Database == nil || strings.ToLower(rt.Database.Name()) == sql.InformationSchemaDatabaseName {
+		return nil
+	}
+	return &columnPrivilegeTable{
+		database: rt.Database.Name(),
+		table:    rt.Name(),
+	}
+}
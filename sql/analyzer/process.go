@@ -16,10 +16,12 @@ package analyzer
 
 import (
 	"os"
+	"strings"
 
 	"github.com/dolthub/go-mysql-server/sql/transform"
 
 	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/performance_schema"
 	"github.com/dolthub/go-mysql-server/sql/plan"
 )
 
@@ -88,6 +90,15 @@ func trackProcess(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, sel R
 					processList.UpdatePartitionProgress(ctx.Pid(), name, partitionName, 1)
 				}
 			}
+			if countRead := tableReadCounter(ctx, a, n); countRead != nil {
+				onProgress := onRowNext
+				onRowNext = func(partitionName string) {
+					countRead()
+					if onProgress != nil {
+						onProgress(partitionName)
+					}
+				}
+			}
 
 			var t sql.Table
 			switch table := n.Table.(type) {
@@ -107,6 +118,8 @@ func trackProcess(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, sel R
 		return nil, transform.SameTree, err
 	}
 
+	recordStatementWrites(ctx, a, n)
+
 	// Don't wrap CreateIndex in a QueryProcess, as it is a CreateIndexProcess.
 	// CreateIndex will take care of marking the process as done on its own.
 	if _, ok := n.(*plan.CreateIndex); ok {
@@ -141,3 +154,47 @@ func trackProcess(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, sel R
 		}
 	}), transform.NewTree, nil
 }
+
+// tableReadCounter returns a function that reports a row read from the given table to the performance schema
+// instrumentation, or nil if the table is not instrumented. The dual table and the tables of the system schemas are not
+// instrumented.
+func tableReadCounter(ctx *sql.Context, a *Analyzer, rt *plan.ResolvedTable) func() {
+	if a.Catalog.Instrumentation == nil || rt.Database == nil || plan.IsDualTable(rt.Table) {
+		return nil
+	}
+	switch strings.ToLower(rt.Database.Name()) {
+	case sql.InformationSchemaDatabaseName, sql.PerformanceSchemaDatabaseName:
+		return nil
+	}
+	return a.Catalog.Instrumentation.TableReadCounter(ctx, rt.Database.Name(), rt.Name())
+}
+
+// recordStatementWrites reports the table that is written by the given INSERT, UPDATE or DELETE to the performance
+// schema instrumentation, which counts the affected rows for that table once the statement has finished.
+func recordStatementWrites(ctx *sql.Context, a *Analyzer, n sql.Node) {
+	if a.Catalog.Instrumentation == nil {
+		return
+	}
+	var target sql.Node
+	var op performance_schema.TableOp
+	transform.Inspect(n, func(n sql.Node) bool {
+		if target != nil {
+			return false
+		}
+		switch n := n.(type) {
+		case *plan.InsertInto:
+			target, op = n.Destination, performance_schema.TableOpInsert
+		case *plan.Update:
+			target, op = n.Child, performance_schema.TableOpUpdate
+		case *plan.DeleteFrom:
+			target, op = n.Child, performance_schema.TableOpDelete
+		}
+		return target == nil
+	})
+	if target == nil {
+		return
+	}
+	if rt := getResolvedTable(target); rt != nil && rt.Database != nil {
+		a.Catalog.Instrumentation.StatementWrites(ctx, rt.Database.Name(), rt.Name(), op)
+	}
+}
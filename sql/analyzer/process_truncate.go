@@ -71,6 +71,11 @@ func deleteToTruncate(ctx *sql.Context, a *Analyzer, deletePlan *plan.DeleteFrom
 	}
 	tblName := strings.ToLower(tbl.Name())
 
+	// deleting from selected partitions must leave the other partitions alone
+	if pt, ok := tbl.Table.(sql.PartitionedTable); ok && pt.SelectedPartitions() != nil {
+		return deletePlan, transform.SameTree, nil
+	}
+
 	// auto_increment behaves differently for TRUNCATE and DELETE
 	for _, col := range tbl.Schema() {
 		if col.AutoIncrement {
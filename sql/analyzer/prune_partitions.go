@@ -0,0 +1,441 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// prunePartitions restricts the scans of tables with user-defined partitioning to the partitions that can hold rows
+// matching the filters above them.
+func prunePartitions(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, sel RuleSelector) (sql.Node, transform.TreeIdentity, error) {
+	span, ctx := ctx.Span("prune_partitions")
+	defer span.End()
+
+	if !n.Resolved() {
+		return n, transform.SameTree, nil
+	}
+
+	// Selecting partitions also restricts the rows that can be written to the table, which would make it impossible for
+	// an UPDATE to move a row to another partition.
+	var hasUpdate bool
+	transform.Inspect(n, func(n sql.Node) bool {
+		if _, ok := n.(*plan.Update); ok {
+			hasUpdate = true
+		}
+		return !hasUpdate
+	})
+	if hasUpdate {
+		return n, transform.SameTree, nil
+	}
+
+	return transform.Node(n, func(n sql.Node) (sql.Node, transform.TreeIdentity, error) {
+		filter, ok := n.(*plan.Filter)
+		if !ok {
+			return n, transform.SameTree, nil
+		}
+		child, same, err := prunePartitionsBelow(ctx, filter.Child, filter.Expression)
+		if err != nil || same {
+			return n, transform.SameTree, err
+		}
+		return plan.NewFilter(filter.Expression, child), transform.NewTree, nil
+	})
+}
+
+// prunePartitionsBelow prunes the partitions of the tables whose rows are filtered by the filter given, which is
+// directly above the node given. The filter is applied to the rows of the tables through joins, since only the
+// comparisons that reject NULL values are used for pruning.
+func prunePartitionsBelow(ctx *sql.Context, n sql.Node, filter sql.Expression) (sql.Node, transform.TreeIdentity, error) {
+	switch n := n.(type) {
+	case *plan.ResolvedTable:
+		return prunePartitionsOfTable(ctx, n, n.Name(), filter)
+	case *plan.TableAlias:
+		rt, ok := n.Child.(*plan.ResolvedTable)
+		if !ok {
+			return n, transform.SameTree, nil
+		}
+		newRt, same, err := prunePartitionsOfTable(ctx, rt, n.Name(), filter)
+		if err != nil || same {
+			return n, transform.SameTree, err
+		}
+		alias, err := n.WithChildren(newRt)
+		if err != nil {
+			return nil, transform.SameTree, err
+		}
+		return alias, transform.NewTree, nil
+	case *plan.JoinNode:
+		// The rows of only one side of a semi or anti join are returned
+		if n.JoinType().IsRightPartial() {
+			return n, transform.SameTree, nil
+		}
+		left, sameL, err := prunePartitionsBelow(ctx, n.Left(), filter)
+		if err != nil {
+			return nil, transform.SameTree, err
+		}
+		right, sameR := n.Right(), transform.SameTree
+		if !n.JoinType().IsPartial() {
+			right, sameR, err = prunePartitionsBelow(ctx, n.Right(), filter)
+			if err != nil {
+				return nil, transform.SameTree, err
+			}
+		}
+		if sameL && sameR {
+			return n, transform.SameTree, nil
+		}
+		newJoin, err := n.WithChildren(left, right)
+		if err != nil {
+			return nil, transform.SameTree, err
+		}
+		return newJoin, transform.NewTree, nil
+	default:
+		return n, transform.SameTree, nil
+	}
+}
+
+// prunePartitionsOfTable returns the table given restricted to the partitions that can hold rows matching the filter
+// given. |tableName| is the name that the filter uses for the table.
+func prunePartitionsOfTable(ctx *sql.Context, rt *plan.ResolvedTable, tableName string, filter sql.Expression) (sql.Node, transform.TreeIdentity, error) {
+	pt, ok := rt.Table.(sql.PartitionedTable)
+	if !ok {
+		return rt, transform.SameTree, nil
+	}
+	scheme, err := pt.PartitionScheme(ctx)
+	if err != nil {
+		return nil, transform.SameTree, err
+	}
+	if scheme == nil || len(scheme.ColumnNames()) == 0 {
+		return rt, transform.SameTree, nil
+	}
+
+	// The partitioning refers to the columns of the full schema, which a projected table doesn't return
+	sch := rt.Schema()
+	if pkt, ok := rt.Table.(sql.PrimaryKeyTable); ok {
+		sch = pkt.PrimaryKeySchema().Schema
+	}
+
+	p := &partitionPruner{
+		ctx:       ctx,
+		scheme:    scheme,
+		sch:       sch,
+		tableName: tableName,
+	}
+	matches, ok := p.partitionsForFilter(filter)
+	if !ok {
+		return rt, transform.SameTree, nil
+	}
+
+	var names []string
+	for i, def := range scheme.Definitions {
+		if matches[i] && partitionIsSelected(pt.SelectedPartitions(), def.Name) {
+			names = append(names, def.Name)
+		}
+	}
+	if selected := pt.SelectedPartitions(); selected != nil && len(names) == len(selected) {
+		return rt, transform.SameTree, nil
+	} else if selected == nil && len(names) == len(scheme.Definitions) {
+		return rt, transform.SameTree, nil
+	}
+
+	if names == nil {
+		names = []string{}
+	}
+	table, err := pt.WithSelectedPartitions(names)
+	if err != nil {
+		return nil, transform.SameTree, err
+	}
+	newRt, err := rt.WithTable(table)
+	if err != nil {
+		return nil, transform.SameTree, err
+	}
+	return newRt, transform.NewTree, nil
+}
+
+// partitionIsSelected returns whether the partition named is one of the partitions selected, or whether there is no
+// selection.
+func partitionIsSelected(selected []string, name string) bool {
+	if selected == nil {
+		return true
+	}
+	for _, s := range selected {
+		if strings.EqualFold(s, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// partitionPruner finds the partitions of a table that can hold rows matching a filter.
+type partitionPruner struct {
+	ctx       *sql.Context
+	scheme    *sql.PartitionScheme
+	sch       sql.Schema
+	tableName string
+}
+
+// partitionsForFilter returns which partitions can hold rows matching the filter given, or false if it can't tell.
+func (p *partitionPruner) partitionsForFilter(filter sql.Expression) ([]bool, bool) {
+	conjuncts := expression.SplitConjunction(filter)
+
+	// Equality on every partitioning column determines the one partition that can hold matching rows
+	matches, ok := p.partitionsForEqualities(conjuncts)
+	for _, conjunct := range conjuncts {
+		conjunctMatches, conjunctOk := p.partitionsForPredicate(conjunct)
+		if !conjunctOk {
+			continue
+		}
+		if !ok {
+			matches, ok = conjunctMatches, true
+			continue
+		}
+		for i := range matches {
+			matches[i] = matches[i] && conjunctMatches[i]
+		}
+	}
+	return matches, ok
+}
+
+// partitionsForEqualities returns which partitions can hold rows matching the conjuncts given, if they include an
+// equality on every partitioning column.
+func (p *partitionPruner) partitionsForEqualities(conjuncts []sql.Expression) ([]bool, bool) {
+	cols := p.scheme.ColumnNames()
+	row := make(sql.Row, len(p.sch))
+	bound := make(map[int]bool)
+	for _, conjunct := range conjuncts {
+		eq, ok := conjunct.(*expression.Equals)
+		if !ok {
+			continue
+		}
+		idx, val, ok := p.columnAndValue(eq.Left(), eq.Right())
+		if !ok {
+			continue
+		}
+		row[idx] = val
+		bound[idx] = true
+	}
+	for _, col := range cols {
+		if !bound[p.sch.IndexOfColName(col)] {
+			return nil, false
+		}
+	}
+	return p.partitionsForRows(row)
+}
+
+// partitionsForPredicate returns which partitions can hold rows matching the predicate given, or false if it can't
+// tell.
+func (p *partitionPruner) partitionsForPredicate(e sql.Expression) ([]bool, bool) {
+	switch e := e.(type) {
+	case *expression.Or:
+		left, ok := p.partitionsForPredicate(e.Left)
+		if !ok {
+			return nil, false
+		}
+		right, ok := p.partitionsForPredicate(e.Right)
+		if !ok {
+			return nil, false
+		}
+		for i := range left {
+			left[i] = left[i] || right[i]
+		}
+		return left, true
+	case *expression.And:
+		return p.partitionsForFilter(e)
+	case *expression.Equals:
+		if len(p.scheme.ColumnNames()) != 1 {
+			return nil, false
+		}
+		return p.partitionsForEqualities([]sql.Expression{e})
+	case *expression.InTuple:
+		if len(p.scheme.ColumnNames()) != 1 {
+			return nil, false
+		}
+		tuple, ok := e.Right().(expression.Tuple)
+		if !ok {
+			return nil, false
+		}
+		var rows []sql.Row
+		for _, val := range tuple {
+			idx, v, ok := p.columnAndValue(e.Left(), val)
+			if !ok {
+				return nil, false
+			}
+			row := make(sql.Row, len(p.sch))
+			row[idx] = v
+			rows = append(rows, row)
+		}
+		return p.partitionsForRows(rows...)
+	case *expression.Between:
+		lower, ok := p.partitionsForRange(e.Val, e.Lower, true, true)
+		if !ok {
+			return nil, false
+		}
+		upper, ok := p.partitionsForRange(e.Val, e.Upper, false, true)
+		if !ok {
+			return nil, false
+		}
+		for i := range lower {
+			lower[i] = lower[i] && upper[i]
+		}
+		return lower, true
+	case *expression.GreaterThan:
+		return p.partitionsForComparison(e.Left(), e.Right(), true, false)
+	case *expression.GreaterThanOrEqual:
+		return p.partitionsForComparison(e.Left(), e.Right(), true, true)
+	case *expression.LessThan:
+		return p.partitionsForComparison(e.Left(), e.Right(), false, false)
+	case *expression.LessThanOrEqual:
+		return p.partitionsForComparison(e.Left(), e.Right(), false, true)
+	default:
+		return nil, false
+	}
+}
+
+// partitionsForComparison returns which partitions can hold rows for which |left| is greater than |right|, or less
+// than it when |greater| is false.
+func (p *partitionPruner) partitionsForComparison(left, right sql.Expression, greater, inclusive bool) ([]bool, bool) {
+	if _, ok := right.(*expression.GetField); ok {
+		left, right = right, left
+		greater = !greater
+	}
+	return p.partitionsForRange(left, right, greater, inclusive)
+}
+
+// partitionsForRange returns which partitions can hold rows whose column |col| is above the value |bound|, or below it
+// when |lower| is false. Only RANGE partitioning by a single column and RANGE COLUMNS partitioning are pruned by
+// ranges, using the first partitioning column.
+func (p *partitionPruner) partitionsForRange(col, bound sql.Expression, lower, inclusive bool) ([]bool, bool) {
+	if !p.scheme.Method.IsRange() {
+		return nil, false
+	}
+	if p.scheme.Method == sql.PartitionMethod_Range {
+		if _, ok := p.scheme.Expression.(*expression.GetField); !ok {
+			return nil, false
+		}
+		if !types.IsInteger(bound.Type()) {
+			return nil, false
+		}
+	}
+	gf, ok := col.(*expression.GetField)
+	if !ok || !strings.EqualFold(gf.Name(), p.scheme.ColumnNames()[0]) || !strings.EqualFold(gf.Table(), p.tableName) {
+		return nil, false
+	}
+	val, ok := p.evalConstant(bound)
+	if !ok || val == nil {
+		return nil, false
+	}
+
+	colType := p.sch[p.sch.IndexOfColName(gf.Name())].Type
+	if p.scheme.Method == sql.PartitionMethod_Range {
+		converted, err := types.Int64.Convert(val)
+		if err != nil {
+			return nil, false
+		}
+		val, colType = converted, types.Int64
+	}
+
+	// With more than one column, the first column of a row in a RANGE COLUMNS partition can be equal to its bound
+	upperInclusive := len(p.scheme.ColumnNames()) > 1
+	matches := make([]bool, len(p.scheme.Definitions))
+	for i, def := range p.scheme.Definitions {
+		partitionUpper := def.LessThan[0]
+		var partitionLower interface{}
+		if i > 0 {
+			partitionLower = p.scheme.Definitions[i-1].LessThan[0]
+		}
+
+		matches[i] = true
+		if lower && partitionUpper != sql.PartitionMaxValue {
+			cmp, err := colType.Compare(val, partitionUpper)
+			if err != nil {
+				return nil, false
+			}
+			matches[i] = cmp < 0 || (cmp == 0 && inclusive && upperInclusive)
+		} else if !lower && partitionLower != nil && partitionLower != sql.PartitionMaxValue {
+			cmp, err := colType.Compare(val, partitionLower)
+			if err != nil {
+				return nil, false
+			}
+			matches[i] = cmp > 0 || (cmp == 0 && inclusive)
+		}
+	}
+	return matches, true
+}
+
+// partitionsForRows returns which partitions hold the rows given.
+func (p *partitionPruner) partitionsForRows(rows ...sql.Row) ([]bool, bool) {
+	matches := make([]bool, len(p.scheme.Definitions))
+	for _, row := range rows {
+		i, err := p.scheme.PartitionForRow(p.ctx, p.sch, row)
+		if sql.ErrNoPartitionForValue.Is(err) {
+			continue
+		} else if err != nil {
+			return nil, false
+		}
+		matches[i] = true
+	}
+	return matches, true
+}
+
+// columnAndValue returns the index of the partitioning column and the constant value compared by the operands given,
+// converted to the column's type.
+func (p *partitionPruner) columnAndValue(left, right sql.Expression) (int, interface{}, bool) {
+	gf, ok := left.(*expression.GetField)
+	if !ok {
+		gf, ok = right.(*expression.GetField)
+		right = left
+	}
+	if !ok || !strings.EqualFold(gf.Table(), p.tableName) || !p.scheme.UsesColumn(gf.Name()) {
+		return -1, nil, false
+	}
+	idx := p.sch.IndexOfColName(gf.Name())
+	if idx < 0 {
+		return -1, nil, false
+	}
+	// Hashes of strings don't respect collations, so values that compare equal may belong to different partitions
+	if p.scheme.Method.IsHash() && types.IsText(p.sch[idx].Type) {
+		return -1, nil, false
+	}
+
+	val, ok := p.evalConstant(right)
+	if !ok {
+		return -1, nil, false
+	}
+	// A comparison with NULL never matches any rows
+	if val == nil {
+		return -1, nil, false
+	}
+	converted, err := p.sch[idx].Type.Convert(val)
+	if err != nil {
+		return -1, nil, false
+	}
+	return idx, converted, true
+}
+
+// evalConstant evaluates the constant expression given.
+func (p *partitionPruner) evalConstant(e sql.Expression) (interface{}, bool) {
+	if !isEvaluable(e) {
+		return nil, false
+	}
+	val, err := e.Eval(p.ctx, nil)
+	if err != nil {
+		return nil, false
+	}
+	return val, true
+}
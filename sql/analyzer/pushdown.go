@@ -755,6 +755,7 @@ func getIndexesByTable(ctx *sql.Context, a *Analyzer, node sql.Node, scope *Scop
 			return false
 		}
 		defer indexAnalyzer.releaseUsedIndexes()
+		indexAnalyzer.restrictToHints(extractQueryHints(filter.Child), indexUseRange)
 
 		var result indexLookupsByTable
 		filterExpression := convertIsNullForIndexes(ctx, filter.Expression)
@@ -852,7 +853,7 @@ func replacePkSort(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, sel
 				break
 			}
 		}
-		if pkIndex == nil {
+		if pkIndex == nil || !indexAllowed(extractQueryHints(rs), indexUseOrder, rs.Name(), pkIndex) {
 			return s, transform.SameTree, nil
 		}
 
@@ -44,6 +44,15 @@ func resolveDatabases(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, s
 				}
 			}
 
+			// Users may always change their own password and manage their own roles, even without any access to the
+			// mysql database, so these statements are given the grant tables directly. Their privileges are checked by
+			// validatePrivileges instead.
+			switch n.(type) {
+			case *plan.AlterUser, *plan.SetRole, *plan.SetDefaultRole:
+				n, err := d.WithDatabase(a.Catalog.MySQLDb)
+				return n, transform.NewTree, err
+			}
+
 			// Only search the catalog if we have a database to resolve.
 			if dbName != "" {
 				db, err := a.Catalog.Database(ctx, dbName)
@@ -54,6 +54,9 @@ func resolveTables(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, sel
 			if sql.ErrTableNotFound.Is(err) && ignore {
 				return p, transform.SameTree, nil
 			}
+			if cn, ok := r.(sql.CommentedNode); ok && p.Comment() != "" {
+				r = cn.WithComment(p.Comment())
+			}
 			return r, transform.NewTree, err
 		case *plan.InsertInto:
 			if with, ok := p.Source.(*plan.With); ok {
@@ -145,6 +148,13 @@ func resolveTable(ctx *sql.Context, t sql.UnresolvedTable, a *Analyzer) (sql.Nod
 		return nil, err
 	}
 
+	if ut, ok := t.(*plan.UnresolvedTable); ok && ut.Partitions() != nil {
+		rt, err = selectPartitions(rt, ut.Partitions())
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	resolvedTableNode := plan.NewResolvedTable(rt, database, nil)
 
 	a.Log("table resolved: %s", t.Name())
@@ -154,6 +164,15 @@ func resolveTable(ctx *sql.Context, t sql.UnresolvedTable, a *Analyzer) (sql.Nod
 	return resolvedTableNode, nil
 }
 
+// selectPartitions returns the table given restricted to the partitions named in a PARTITION clause.
+func selectPartitions(t sql.Table, names []string) (sql.Table, error) {
+	pt, ok := t.(sql.PartitionedTable)
+	if !ok {
+		return nil, sql.ErrPartitionClauseOnNonpartitioned.New()
+	}
+	return pt.WithSelectedPartitions(names)
+}
+
 // setTargetSchemas fills in the target schema for any nodes in the tree that operate on a table node but also want to
 // store supplementary schema information. This is useful for lazy resolution of column default values.
 func setTargetSchemas(ctx *sql.Context, a *Analyzer, n sql.Node, scope *Scope, sel RuleSelector) (sql.Node, transform.TreeIdentity, error) {
@@ -305,6 +324,16 @@ func transferProjections(ctx *sql.Context, from, to *plan.ResolvedTable) *plan.R
 		changed = true
 	}
 
+	if pt, ok := fromTable.(sql.PartitionedTable); ok && pt.SelectedPartitions() != nil {
+		if _, ok := toTable.(sql.PartitionedTable); ok {
+			selected, err := toTable.(sql.PartitionedTable).WithSelectedPartitions(pt.SelectedPartitions())
+			if err == nil {
+				toTable = selected
+				changed = true
+			}
+		}
+	}
+
 	if !changed {
 		return to
 	}
@@ -51,6 +51,7 @@ const (
 	setInsertColumnsId                           // setInsertColumns
 	validateJoinComplexityId                     // validateJoinComplexity
 	applyBinlogReplicaControllerId               // applyBinlogReplicaController
+	resolveIndexHintsId                          // resolveIndexHints
 
 	// default
 	resolveNaturalJoinsId          // resolveNaturalJoins
@@ -78,9 +79,12 @@ const (
 	optimizeDistinctId             // optimizeDistinct
 
 	// after default
+	validateColumnPrivilegesId   // validateColumnPrivileges
+	validateLockedTablesId       // validateLockedTables
 	finalizeSubqueriesId         // finalizeSubqueries
 	finalizeUnionsId             // finalizeUnions
 	loadTriggersId               // loadTriggers
+	loadEventsId                 // loadEvents
 	processTruncateId            // processTruncate
 	resolveAlterColumnId         // resolveAlterColumn
 	resolveGeneratorsId          // resolveGenerators
@@ -90,6 +94,7 @@ const (
 	hoistSelectExistsId          // hoistSelectExists
 	optimizeJoinsId              // optimizeJoins
 	concatFiltersId              // concatFilters
+	prunePartitionsId            // prunePartitions
 	pushdownFiltersId            // pushdownFilters
 	subqueryIndexesId            // subqueryIndexes
 	pruneTablesId                // pruneTables
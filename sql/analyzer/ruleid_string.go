@@ -53,86 +53,92 @@ func _() {
 	_ = x[setInsertColumnsId-42]
 	_ = x[validateJoinComplexityId-43]
 	_ = x[applyBinlogReplicaControllerId-44]
-	_ = x[resolveNaturalJoinsId-45]
-	_ = x[resolveOrderbyLiteralsId-46]
-	_ = x[resolveFunctionsId-47]
-	_ = x[flattenTableAliasesId-48]
-	_ = x[pushdownSortId-49]
-	_ = x[pushdownGroupbyAliasesId-50]
-	_ = x[pushdownSubqueryAliasFiltersId-51]
-	_ = x[qualifyColumnsId-52]
-	_ = x[resolveColumnsId-53]
-	_ = x[validateCheckConstraintId-54]
-	_ = x[resolveBarewordSetVariablesId-55]
-	_ = x[replaceCountStarId-56]
-	_ = x[expandStarsId-57]
-	_ = x[transposeRightJoinsId-58]
-	_ = x[resolveHavingId-59]
-	_ = x[mergeUnionSchemasId-60]
-	_ = x[flattenAggregationExprsId-61]
-	_ = x[reorderProjectionId-62]
-	_ = x[resolveSubqueryExprsId-63]
-	_ = x[replaceCrossJoinsId-64]
-	_ = x[moveJoinCondsToFilterId-65]
-	_ = x[evalFilterId-66]
-	_ = x[optimizeDistinctId-67]
-	_ = x[finalizeSubqueriesId-68]
-	_ = x[finalizeUnionsId-69]
-	_ = x[loadTriggersId-70]
-	_ = x[processTruncateId-71]
-	_ = x[resolveAlterColumnId-72]
-	_ = x[resolveGeneratorsId-73]
-	_ = x[removeUnnecessaryConvertsId-74]
-	_ = x[pruneColumnsId-75]
-	_ = x[stripTableNameInDefaultsId-76]
-	_ = x[hoistSelectExistsId-77]
-	_ = x[optimizeJoinsId-78]
-	_ = x[concatFiltersId-79]
-	_ = x[pushdownFiltersId-80]
-	_ = x[subqueryIndexesId-81]
-	_ = x[pruneTablesId-82]
-	_ = x[setJoinScopeLenId-83]
-	_ = x[eraseProjectionId-84]
-	_ = x[replaceSortPkId-85]
-	_ = x[insertTopNId-86]
-	_ = x[applyHashInId-87]
-	_ = x[resolveInsertRowsId-88]
-	_ = x[resolvePreparedInsertId-89]
-	_ = x[applyTriggersId-90]
-	_ = x[applyProceduresId-91]
-	_ = x[assignRoutinesId-92]
-	_ = x[modifyUpdateExprsForJoinId-93]
-	_ = x[applyRowUpdateAccumulatorsId-94]
-	_ = x[wrapWithRollbackId-95]
-	_ = x[applyFKsId-96]
-	_ = x[validateResolvedId-97]
-	_ = x[validateOrderById-98]
-	_ = x[validateGroupById-99]
-	_ = x[validateSchemaSourceId-100]
-	_ = x[validateIndexCreationId-101]
-	_ = x[validateOperandsId-102]
-	_ = x[validateCaseResultTypesId-103]
-	_ = x[validateIntervalUsageId-104]
-	_ = x[validateExplodeUsageId-105]
-	_ = x[validateSubqueryColumnsId-106]
-	_ = x[validateUnionSchemasMatchId-107]
-	_ = x[validateAggregationsId-108]
-	_ = x[normalizeSelectSingleRelId-109]
-	_ = x[cacheSubqueryResultsId-110]
-	_ = x[cacheSubqueryAliasesInJoinsId-111]
-	_ = x[AutocommitId-112]
-	_ = x[TrackProcessId-113]
-	_ = x[parallelizeId-114]
-	_ = x[clearWarningsId-115]
+	_ = x[resolveIndexHintsId-45]
+	_ = x[resolveNaturalJoinsId-46]
+	_ = x[resolveOrderbyLiteralsId-47]
+	_ = x[resolveFunctionsId-48]
+	_ = x[flattenTableAliasesId-49]
+	_ = x[pushdownSortId-50]
+	_ = x[pushdownGroupbyAliasesId-51]
+	_ = x[pushdownSubqueryAliasFiltersId-52]
+	_ = x[qualifyColumnsId-53]
+	_ = x[resolveColumnsId-54]
+	_ = x[validateCheckConstraintId-55]
+	_ = x[resolveBarewordSetVariablesId-56]
+	_ = x[replaceCountStarId-57]
+	_ = x[expandStarsId-58]
+	_ = x[transposeRightJoinsId-59]
+	_ = x[resolveHavingId-60]
+	_ = x[mergeUnionSchemasId-61]
+	_ = x[flattenAggregationExprsId-62]
+	_ = x[reorderProjectionId-63]
+	_ = x[resolveSubqueryExprsId-64]
+	_ = x[replaceCrossJoinsId-65]
+	_ = x[moveJoinCondsToFilterId-66]
+	_ = x[evalFilterId-67]
+	_ = x[optimizeDistinctId-68]
+	_ = x[validateColumnPrivilegesId-69]
+	_ = x[validateLockedTablesId-70]
+	_ = x[finalizeSubqueriesId-71]
+	_ = x[finalizeUnionsId-72]
+	_ = x[loadTriggersId-73]
+	_ = x[loadEventsId-74]
+	_ = x[processTruncateId-75]
+	_ = x[resolveAlterColumnId-76]
+	_ = x[resolveGeneratorsId-77]
+	_ = x[removeUnnecessaryConvertsId-78]
+	_ = x[pruneColumnsId-79]
+	_ = x[stripTableNameInDefaultsId-80]
+	_ = x[hoistSelectExistsId-81]
+	_ = x[optimizeJoinsId-82]
+	_ = x[concatFiltersId-83]
+	_ = x[prunePartitionsId-84]
+	_ = x[pushdownFiltersId-85]
+	_ = x[subqueryIndexesId-86]
+	_ = x[pruneTablesId-87]
+	_ = x[setJoinScopeLenId-88]
+	_ = x[eraseProjectionId-89]
+	_ = x[replaceSortPkId-90]
+	_ = x[insertTopNId-91]
+	_ = x[applyHashInId-92]
+	_ = x[resolveInsertRowsId-93]
+	_ = x[resolvePreparedInsertId-94]
+	_ = x[applyTriggersId-95]
+	_ = x[applyProceduresId-96]
+	_ = x[assignRoutinesId-97]
+	_ = x[modifyUpdateExprsForJoinId-98]
+	_ = x[applyRowUpdateAccumulatorsId-99]
+	_ = x[wrapWithRollbackId-100]
+	_ = x[applyFKsId-101]
+	_ = x[validateResolvedId-102]
+	_ = x[validateOrderById-103]
+	_ = x[validateGroupById-104]
+	_ = x[validateSchemaSourceId-105]
+	_ = x[validateIndexCreationId-106]
+	_ = x[validateOperandsId-107]
+	_ = x[validateCaseResultTypesId-108]
+	_ = x[validateIntervalUsageId-109]
+	_ = x[validateExplodeUsageId-110]
+	_ = x[validateSubqueryColumnsId-111]
+	_ = x[validateUnionSchemasMatchId-112]
+	_ = x[validateAggregationsId-113]
+	_ = x[normalizeSelectSingleRelId-114]
+	_ = x[cacheSubqueryResultsId-115]
+	_ = x[cacheSubqueryAliasesInJoinsId-116]
+	_ = x[AutocommitId-117]
+	_ = x[TrackProcessId-118]
+	_ = x[parallelizeId-119]
+	_ = x[clearWarningsId-120]
 }
 
-const _RuleId_name = "applyDefaultSelectLimitvalidateOffsetAndLimitvalidateCreateTablevalidateExprSemresolveVariablesresolveNamedWindowsresolveSetVariablesresolveViewsliftCtesresolveCtesliftRecursiveCtesresolveDatabasesresolveTablesloadStoredProceduresvalidateDropTablessetTargetSchemasresolveCreateLikeparseColumnDefaultsresolveDropConstraintvalidateDropConstraintloadCheckConstraintsassignCatalogresolveAnalyzeTablesresolveCreateSelectresolveSubqueriessetViewTargetSchemaresolveUnionsresolveDescribeQuerycheckUniqueTableNamesresolveTableFunctionsresolveDeclarationsresolveColumnDefaultsvalidateColumnDefaultsvalidateCreateTriggervalidateCreateProcedureloadInfoSchemavalidateReadOnlyDatabasevalidateReadOnlyTransactionvalidateDatabaseSetvalidatePrivilegesreresolveTablestransformJoinApplysetInsertColumnsvalidateJoinComplexityapplyBinlogReplicaControllerresolveNaturalJoinsresolveOrderbyLiteralsresolveFunctionsflattenTableAliasespushdownSortpushdownGroupbyAliasespushdownSubqueryAliasFiltersqualifyColumnsresolveColumnsvalidateCheckConstraintresolveBarewordSetVariablesreplaceCountStarexpandStarstransposeRightJoinsresolveHavingmergeUnionSchemasflattenAggregationExprsreorderProjectionresolveSubqueryExprsreplaceCrossJoinsmoveJoinCondsToFilterevalFilteroptimizeDistinctfinalizeSubqueriesfinalizeUnionsloadTriggersprocessTruncateresolveAlterColumnresolveGeneratorsremoveUnnecessaryConvertspruneColumnsstripTableNamesFromColumnDefaultshoistSelectExistsoptimizeJoinsconcatFilterspushdownFilterssubqueryIndexespruneTablessetJoinScopeLeneraseProjectionreplaceSortPkinsertTopNapplyHashInresolveInsertRowsresolvePreparedInsertapplyTriggersapplyProceduresassignRoutinesmodifyUpdateExprsForJoinapplyRowUpdateAccumulatorsrollback triggersapplyFKsvalidateResolvedvalidateOrderByvalidateGroupByvalidateSchemaSourcevalidateIndexCreationvalidateOperandsvalidateCaseResultTypesvalidateIntervalUsagevalidateExplodeUsagevalidateSubqueryColumnsvalidateUnionSchemasMatchvalidateAggregationsnormalizeSelectSingleRelcacheSubqueryResultscacheSubqueryAliasesInJoinsaddAutocommitNodetrackProcessparallelizeclearWarnings"
+const _RuleId_name = "applyDefaultSelectLimitvalidateOffsetAndLimitvalidateCreateTablevalidateExprSemresolveVariablesresolveNamedWindowsresolveSetVariablesresolveViewsliftCtesresolveCtesliftRecursiveCtesresolveDatabasesresolveTablesloadStoredProceduresvalidateDropTablessetTargetSchemasresolveCreateLikeparseColumnDefaultsresolveDropConstraintvalidateDropConstraintloadCheckConstraintsassignCatalogresolveAnalyzeTablesresolveCreateSelectresolveSubqueriessetViewTargetSchemaresolveUnionsresolveDescribeQuerycheckUniqueTableNamesresolveTableFunctionsresolveDeclarationsresolveColumnDefaultsvalidateColumnDefaultsvalidateCreateTriggervalidateCreateProcedureloadInfoSchemavalidateReadOnlyDatabasevalidateReadOnlyTransactionvalidateDatabaseSetvalidatePrivilegesreresolveTablestransformJoinApplysetInsertColumnsvalidateJoinComplexityapplyBinlogReplicaControllerresolveIndexHintsresolveNaturalJoinsresolveOrderbyLiteralsresolveFunctionsflattenTableAliasespushdownSortpushdownGroupbyAliasespushdownSubqueryAliasFiltersqualifyColumnsresolveColumnsvalidateCheckConstraintresolveBarewordSetVariablesreplaceCountStarexpandStarstransposeRightJoinsresolveHavingmergeUnionSchemasflattenAggregationExprsreorderProjectionresolveSubqueryExprsreplaceCrossJoinsmoveJoinCondsToFilterevalFilteroptimizeDistinctvalidateColumnPrivilegesvalidateLockedTablesfinalizeSubqueriesfinalizeUnionsloadTriggersloadEventsprocessTruncateresolveAlterColumnresolveGeneratorsremoveUnnecessaryConvertspruneColumnsstripTableNamesFromColumnDefaultshoistSelectExistsoptimizeJoinsconcatFiltersprunePartitionspushdownFilterssubqueryIndexespruneTablessetJoinScopeLeneraseProjectionreplaceSortPkinsertTopNapplyHashInresolveInsertRowsresolvePreparedInsertapplyTriggersapplyProceduresassignRoutinesmodifyUpdateExprsForJoinapplyRowUpdateAccumulatorsrollback triggersapplyFKsvalidateResolvedvalidateOrderByvalidateGroupByvalidateSchemaSourcevalidateIndexCreationvalidateOperandsvalidateCaseResultTypesvalidateIntervalUsagevalidateExplodeUsagevalidateSubqueryColumnsvalidateUnionSchemasMatchvalidateAggregationsnormalizeSelectSingleRelcacheSubqueryResultscacheSubqueryAliasesInJoinsaddAutocommitNodetrackProcessparallelizeclearWarnings"
 
-var _RuleId_index = [...]uint16{0, 23, 45, 64, 79, 95, 114, 133, 145, 153, 164, 181, 197, 210, 230, 248, 264, 281, 300, 321, 343, 363, 376, 396, 415, 432, 451, 464, 484, 505, 526, 545, 566, 588, 609, 632, 646, 670, 697, 716, 734, 749, 767, 783, 805, 833, 852, 874, 890, 909, 921, 943, 971, 985, 999, 1022, 1049, 1065, 1076, 1095, 1108, 1125, 1148, 1165, 1185, 1202, 1223, 1233, 1249, 1267, 1281, 1293, 1308, 1326, 1343, 1368, 1380, 1413, 1430, 1443, 1456, 1471, 1486, 1497, 1512, 1527, 1540, 1550, 1561, 1578, 1599, 1612, 1627, 1641, 1665, 1691, 1708, 1716, 1732, 1747, 1762, 1782, 1803, 1819, 1842, 1863, 1883, 1906, 1931, 1951, 1975, 1995, 2022, 2039, 2051, 2062, 2075}
+var _RuleId_index = [...]uint16{0, 23, 45, 64, 79, 95, 114, 133, 145, 153, 164, 181, 197, 210, 230, 248, 264, 281, 300, 321, 343, 363, 376, 396, 415, 432, 451, 464, 484, 505, 526, 545, 566, 588, 609, 632, 646, 670, 697, 716, 734, 749, 767, 783, 805, 833, 850, 869, 891, 907, 926, 938, 960, 988, 1002, 1016, 1039, 1066, 1082, 1093, 1112, 1125, 1142, 1165, 1182, 1202, 1219, 1240, 1250, 1266, 1290, 1310, 1328, 1342, 1354, 1364, 1379, 1397, 1414, 1439, 1451, 1484, 1501, 1514, 1527, 1542, 1557, 1572, 1583, 1598, 1613, 1626, 1636, 1647, 1664, 1685, 1698, 1713, 1727, 1751, 1777, 1794, 1802, 1818, 1833, 1848, 1868, 1889, 1905, 1928, 1949, 1969, 1992, 2017, 2037, 2061, 2081, 2108, 2125, 2137, 2148, 2161}
 
 func (i RuleId) String() string {
-	if i < 0 || i >= RuleId(len(_RuleId_index)-1) {
+	idx := int(i) - 0
+	if i < 0 || idx >= len(_RuleId_index)-1 {
 		return "RuleId(" + strconv.FormatInt(int64(i), 10) + ")"
 	}
-	return _RuleId_name[_RuleId_index[i]:_RuleId_index[i+1]]
+	return _RuleId_name[_RuleId_index[idx]:_RuleId_index[idx+1]]
 }
@@ -58,6 +58,7 @@ var OnceBeforeDefault = []Rule{
 	{resolveDeclarationsId, resolveDeclarations},
 	{validateCreateTriggerId, validateCreateTrigger},
 	{loadInfoSchemaId, loadInfoSchema},
+	{resolveIndexHintsId, resolveIndexHints},
 	{resolveColumnDefaultsId, resolveColumnDefaults},
 	{validateColumnDefaultsId, validateColumnDefaults},
 	{validateReadOnlyDatabaseId, validateReadOnlyDatabase},
@@ -97,14 +98,18 @@ var DefaultRules = []Rule{
 // OnceAfterDefault contains the rules to be applied just once after the
 // DefaultRules.
 var OnceAfterDefault = []Rule{
+	{validateColumnPrivilegesId, validateColumnPrivileges}, // Columns must be resolved, and expanded from stars
+	{validateLockedTablesId, validateLockedTables},         // Subqueries must be resolved, before triggers and foreign keys are applied
 	{transformJoinApplyId, transformJoinApply},
 	{hoistSelectExistsId, hoistSelectExists},
 	{finalizeUnionsId, finalizeUnions},
 	{loadTriggersId, loadTriggers},
+	{loadEventsId, loadEvents},
 	{processTruncateId, processTruncate},
 	{removeUnnecessaryConvertsId, removeUnnecessaryConverts},
 	{stripTableNameInDefaultsId, stripTableNamesFromColumnDefaults},
 	{optimizeJoinsId, constructJoinPlan},
+	{prunePartitionsId, prunePartitions},
 	{pushdownFiltersId, pushdownFilters},
 	{pruneColumnsId, pruneColumns},
 	{finalizeSubqueriesId, finalizeSubqueries},
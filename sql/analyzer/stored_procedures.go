@@ -514,3 +514,23 @@ func applyProceduresCall(ctx *sql.Context, a *Analyzer, call *plan.Call, scope *
 	call = call.WithProcedure(procedure)
 	return call, transform.NewTree, nil
 }
+
+// AnalyzeEventBody analyzes the body of an event, which is given as a stored procedure in the database of the event,
+// returning a node that executes the body and commits the result. The body is analyzed using the client of the given
+// context, which should be the event's definer.
+func (a *Analyzer) AnalyzeEventBody(ctx *sql.Context, db sql.Database, proc *plan.Procedure) (sql.Node, error) {
+	call := plan.NewCall(db, proc.Name, nil, nil)
+	scope, err := loadStoredProcedures(ctx, a, call, nil, DefaultRuleSelector)
+	if err != nil {
+		return nil, err
+	}
+	analyzedProc, err := analyzeCreateProcedure(ctx, a, &plan.CreateProcedure{Procedure: proc}, scope, DefaultRuleSelector)
+	if err != nil {
+		return nil, err
+	}
+	analyzed, _, err := applyProceduresCall(ctx, a, call.WithProcedure(analyzedProc), scope, DefaultRuleSelector)
+	if err != nil {
+		return nil, err
+	}
+	return plan.NewTransactionCommittingNode(analyzed), nil
+}
@@ -54,6 +54,13 @@ type BaseSession struct {
 	// privilege set if our counter doesn't equal the database's counter.
 	privSetCounter uint64
 	privilegeSet   PrivilegeSet
+	activeRoles    []ActiveRole
+	explicitRoles  bool
+
+	optimizerTraces []OptimizerTrace
+	queryProfiles   []QueryProfile
+
+	memoryBudget *MemoryBudget
 }
 
 func (s *BaseSession) GetLogger() *logrus.Entry {
@@ -439,6 +446,50 @@ func (s *BaseSession) SetPrivilegeSet(newPs PrivilegeSet, counter uint64) {
 	s.privilegeSet = newPs
 }
 
+// GetActiveRoles implements the Session interface.
+func (s *BaseSession) GetActiveRoles() ([]ActiveRole, bool) {
+	return s.activeRoles, s.explicitRoles
+}
+
+// SetActiveRoles implements the Session interface.
+func (s *BaseSession) SetActiveRoles(roles []ActiveRole, explicit bool) {
+	s.activeRoles = roles
+	s.explicitRoles = explicit
+}
+
+// GetOptimizerTraces implements the Session interface.
+func (s *BaseSession) GetOptimizerTraces() []OptimizerTrace {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.optimizerTraces
+}
+
+// SetOptimizerTraces implements the Session interface.
+func (s *BaseSession) SetOptimizerTraces(traces []OptimizerTrace) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.optimizerTraces = traces
+}
+
+// GetQueryProfiles implements the Session interface.
+func (s *BaseSession) GetQueryProfiles() []QueryProfile {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.queryProfiles
+}
+
+// SetQueryProfiles implements the Session interface.
+func (s *BaseSession) SetQueryProfiles(profiles []QueryProfile) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.queryProfiles = profiles
+}
+
+// GetMemoryBudget implements the Session interface.
+func (s *BaseSession) GetMemoryBudget() *MemoryBudget {
+	return s.memoryBudget
+}
+
 // NewBaseSessionWithClientServer creates a new session with data.
 func NewBaseSessionWithClientServer(server string, client Client, id uint32) *BaseSession {
 	// TODO: if system variable "activate_all_roles_on_login" if set, activate all roles
@@ -460,6 +511,7 @@ func NewBaseSessionWithClientServer(server string, client Client, id uint32) *Ba
 		locks:          make(map[string]bool),
 		lastQueryInfo:  defaultLastQueryInfo(),
 		privSetCounter: 0,
+		memoryBudget:   NewSessionMemoryBudget(),
 	}
 }
 
@@ -482,5 +534,6 @@ func NewBaseSession() *BaseSession {
 		locks:          make(map[string]bool),
 		lastQueryInfo:  defaultLastQueryInfo(),
 		privSetCounter: 0,
+		memoryBudget:   NewSessionMemoryBudget(),
 	}
 }
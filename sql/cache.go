@@ -83,29 +83,46 @@ func (l *lruCache) Dispose() {
 type rowsCache struct {
 	memory   Freeable
 	reporter Reporter
+	budget   *MemoryBudget
+	charged  uint64
 	rows     []Row
 	rows2    []Row2
 }
 
-func newRowsCache(memory Freeable, r Reporter) *rowsCache {
-	return &rowsCache{memory: memory, reporter: r}
+func newRowsCache(memory Freeable, r Reporter, budget *MemoryBudget) *rowsCache {
+	return &rowsCache{memory: memory, reporter: r, budget: budget}
 }
 
 func (c *rowsCache) Add(row Row) error {
 	if !releaseMemoryIfNeeded(c.reporter, c.memory.Free) {
 		return ErrNoMemoryAvailable.New()
 	}
+	if err := c.charge(RowMemorySize(row)); err != nil {
+		return err
+	}
 
 	c.rows = append(c.rows, row)
 	return nil
 }
 
+// charge charges the size given to the memory budget of the cache.
+func (c *rowsCache) charge(size uint64) error {
+	if err := c.budget.Charge(size); err != nil {
+		return err
+	}
+	c.charged += size
+	return nil
+}
+
 func (c *rowsCache) Get() []Row { return c.rows }
 
 func (c *rowsCache) Add2(row2 Row2) error {
 	if !releaseMemoryIfNeeded(c.reporter, c.memory.Free) {
 		return ErrNoMemoryAvailable.New()
 	}
+	if err := c.charge(row2MemorySize(row2)); err != nil {
+		return err
+	}
 
 	c.rows2 = append(c.rows2, row2)
 	return nil
@@ -118,6 +135,19 @@ func (c *rowsCache) Get2() []Row2 {
 func (c *rowsCache) Dispose() {
 	c.memory = nil
 	c.rows = nil
+	c.rows2 = nil
+	c.budget.Release(c.charged)
+	c.charged = 0
+}
+
+// row2MemorySize returns an estimate of the memory used by the row given, like RowMemorySize.
+func row2MemorySize(row Row2) uint64 {
+	const valueSize = 32
+	size := uint64(len(row)) * valueSize
+	for _, v := range row {
+		size += uint64(len(v.Val))
+	}
+	return size
 }
 
 // mapCache is a simple in-memory implementation of a cache
@@ -151,6 +181,8 @@ func NewMapCache() mapCache {
 type historyCache struct {
 	memory   Freeable
 	reporter Reporter
+	budget   *MemoryBudget
+	charged  uint64
 	cache    map[uint64]interface{}
 }
 
@@ -158,14 +190,21 @@ func (h *historyCache) Size() int {
 	return len(h.cache)
 }
 
-func newHistoryCache(memory Freeable, r Reporter) *historyCache {
-	return &historyCache{memory, r, make(map[uint64]interface{})}
+func newHistoryCache(memory Freeable, r Reporter, budget *MemoryBudget) *historyCache {
+	return &historyCache{memory: memory, reporter: r, budget: budget, cache: make(map[uint64]interface{})}
 }
 
 func (h *historyCache) Put(k uint64, v interface{}) error {
 	if !releaseMemoryIfNeeded(h.reporter, h.memory.Free) {
 		return ErrNoMemoryAvailable.New()
 	}
+	if _, ok := h.cache[k]; !ok {
+		size := cacheEntryMemorySize(v)
+		if err := h.budget.Charge(size); err != nil {
+			return err
+		}
+		h.charged += size
+	}
 	h.cache[k] = v
 	return nil
 }
@@ -181,6 +220,32 @@ func (h *historyCache) Get(k uint64) (interface{}, error) {
 func (h *historyCache) Dispose() {
 	h.memory = nil
 	h.cache = nil
+	h.budget.Release(h.charged)
+	h.charged = 0
+}
+
+// cacheEntryMemorySize returns an estimate of the memory used by an entry of a key-value cache holding the value given.
+// Values of types it doesn't know about are counted as a single value.
+func cacheEntryMemorySize(v interface{}) uint64 {
+	const entrySize = 32
+	switch v := v.(type) {
+	case Row:
+		return entrySize + RowMemorySize(v)
+	case []Row:
+		size := uint64(entrySize)
+		for _, row := range v {
+			size += RowMemorySize(row)
+		}
+		return size
+	case []AggregationBuffer:
+		return entrySize + uint64(len(v))*entrySize
+	case string:
+		return entrySize + uint64(len(v))
+	case []byte:
+		return entrySize + uint64(len(v))
+	default:
+		return entrySize
+	}
 }
 
 // releasesMemoryIfNeeded releases memory if needed using the following steps
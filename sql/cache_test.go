@@ -15,6 +15,7 @@
 package sql
 
 import (
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -85,7 +86,7 @@ func TestHistoryCache(t *testing.T) {
 	t.Run("basic methods", func(t *testing.T) {
 		require := require.New(t)
 
-		cache := newHistoryCache(mockMemory{}, fixedReporter(5, 50))
+		cache := newHistoryCache(mockMemory{}, fixedReporter(5, 50), nil)
 
 		require.NoError(cache.Put(1, "foo"))
 		v, err := cache.Get(1)
@@ -104,7 +105,7 @@ func TestHistoryCache(t *testing.T) {
 
 	t.Run("no memory available", func(t *testing.T) {
 		require := require.New(t)
-		cache := newHistoryCache(mockMemory{}, fixedReporter(51, 50))
+		cache := newHistoryCache(mockMemory{}, fixedReporter(51, 50), nil)
 
 		err := cache.Put(1, "foo")
 		require.Error(err)
@@ -124,6 +125,7 @@ func TestHistoryCache(t *testing.T) {
 				}
 				return 51
 			}, 50},
+			nil,
 		)
 		require.NoError(cache.Put(1, "foo"))
 		v, err := cache.Get(1)
@@ -131,13 +133,33 @@ func TestHistoryCache(t *testing.T) {
 		require.Equal("foo", v)
 		require.True(freed)
 	})
+
+	t.Run("memory budget", func(t *testing.T) {
+		require := require.New(t)
+		session := NewSessionMemoryBudget()
+		session.SetLimit(40)
+		budget := NewQueryMemoryBudget(math.MaxUint64, session)
+		cache := newHistoryCache(mockMemory{}, fixedReporter(5, 50), budget)
+
+		require.NoError(cache.Put(1, "foo"))
+		require.NoError(cache.Put(1, "bar"))
+		require.Equal(uint64(35), session.Used())
+
+		err := cache.Put(2, "baz")
+		require.True(ErrConnectionMemoryLimitExceeded.Is(err))
+		require.Equal(uint64(35), budget.Used())
+
+		cache.Dispose()
+		require.Zero(budget.Used())
+		require.Zero(session.Used())
+	})
 }
 
 func TestRowsCache(t *testing.T) {
 	t.Run("basic methods", func(t *testing.T) {
 		require := require.New(t)
 
-		cache := newRowsCache(mockMemory{}, fixedReporter(5, 50))
+		cache := newRowsCache(mockMemory{}, fixedReporter(5, 50), nil)
 
 		require.NoError(cache.Add(Row{1}))
 		require.Len(cache.Get(), 1)
@@ -150,7 +172,7 @@ func TestRowsCache(t *testing.T) {
 
 	t.Run("no memory available", func(t *testing.T) {
 		require := require.New(t)
-		cache := newRowsCache(mockMemory{}, fixedReporter(51, 50))
+		cache := newRowsCache(mockMemory{}, fixedReporter(51, 50), nil)
 
 		err := cache.Add(Row{1, "foo"})
 		require.Error(err)
@@ -170,9 +192,30 @@ func TestRowsCache(t *testing.T) {
 				}
 				return 51
 			}, 50},
+			nil,
 		)
 		require.NoError(cache.Add(Row{1, "foo"}))
 		require.Len(cache.Get(), 1)
 		require.True(freed)
 	})
+
+	t.Run("memory budget", func(t *testing.T) {
+		require := require.New(t)
+		session := NewSessionMemoryBudget()
+		budget := NewQueryMemoryBudget(40, session)
+		cache := newRowsCache(mockMemory{}, fixedReporter(5, 50), budget)
+
+		require.NoError(cache.Add(Row{1, "foo"}))
+		require.Equal(uint64(35), budget.Used())
+		require.Equal(uint64(35), session.Used())
+
+		err := cache.Add(Row{2, "bar"})
+		require.True(ErrQueryMemoryLimitExceeded.Is(err))
+		require.Len(cache.Get(), 1)
+		require.Equal(uint64(35), budget.Used())
+
+		cache.Dispose()
+		require.Zero(budget.Used())
+		require.Zero(session.Used())
+	})
 }
@@ -48,8 +48,8 @@ type Catalog interface {
 	// Integrators with custom functions should typically use the FunctionProvider interface to register their functions.
 	RegisterFunction(ctx *Context, fns ...Function)
 
-	// LockTable locks the table named
-	LockTable(ctx *Context, table string)
+	// LockTable records that the session of the context locked the table named in the database named
+	LockTable(ctx *Context, database, table string)
 
 	// UnlockTables unlocks all tables locked by the session id given
 	UnlockTables(ctx *Context, id uint32) error
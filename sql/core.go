@@ -111,7 +111,8 @@ type UnaryNode interface {
 	Child() Node
 }
 
-// CommentedNode allows comments to be set and retrieved on it. Used primarily for join hint comments.
+// CommentedNode allows comments to be set and retrieved on it. Used primarily for the optimizer hint comments of query
+// blocks, which the parser attaches to the node of their FROM clause.
 type CommentedNode interface {
 	Node
 	WithComment(string) Node
@@ -217,6 +218,14 @@ type Lockable interface {
 	Unlock(ctx *Context, id uint32) error
 }
 
+// ReadLocalLockable is a Lockable table that supports READ LOCAL locks, which are read locks that still let other
+// session clients insert rows into the table.
+type ReadLocalLockable interface {
+	Lockable
+	// LockReadLocal locks the table for reads, except for inserts of other session clients.
+	LockReadLocal(ctx *Context) error
+}
+
 // EvaluateCondition evaluates a condition, which is an expression whose value
 // will be nil or coerced boolean.
 func EvaluateCondition(ctx *Context, cond Expression, row Row) (interface{}, error) {
@@ -0,0 +1,29 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux && !darwin
+// +build !linux,!darwin
+
+package sql
+
+import "time"
+
+// HasProcessCPUTime is set on the platforms where the CPU time of the stages of profiled statements is recorded.
+const HasProcessCPUTime = false
+
+// processCPUTime returns the user and system CPU time used by the server process so far. It is not supported on this
+// platform.
+func processCPUTime() (user time.Duration, system time.Duration, ok bool) {
+	return 0, 0, false
+}
@@ -22,6 +22,8 @@ import (
 const (
 	// InformationSchemaDatabaseName is the name of the information schema database.
 	InformationSchemaDatabaseName = "information_schema"
+	// PerformanceSchemaDatabaseName is the name of the performance schema database.
+	PerformanceSchemaDatabaseName = "performance_schema"
 )
 
 // DatabaseProvider is the fundamental interface to integrate with the engine. It provides access to all databases in
@@ -61,6 +63,25 @@ type TableFunctionProvider interface {
 	TableFunction(ctx *Context, name string) (TableFunction, error)
 }
 
+// TableFlusher is a DatabaseProvider that acts on FLUSH TABLES statements, such as by writing out the changes it
+// buffers and closing the files it holds open.
+type TableFlusher interface {
+	// FlushTables flushes the tables given, or every table if none are given.
+	FlushTables(ctx *Context, tables []DbTable) error
+}
+
+// StatusFlusher is a DatabaseProvider that acts on FLUSH STATUS statements by resetting the status counters it keeps.
+type StatusFlusher interface {
+	// FlushStatus resets the status counters of the session of the context.
+	FlushStatus(ctx *Context) error
+}
+
+// LogFlusher is a DatabaseProvider that acts on FLUSH LOGS statements, such as by closing and reopening its log files.
+type LogFlusher interface {
+	// FlushLogs flushes the logs of the kind given, such as "binary" or "slow", or every log if the kind is empty.
+	FlushLogs(ctx *Context, kind string) error
+}
+
 // Database represents the database. Its primary job is to provide access to all tables.
 type Database interface {
 	Nameable
@@ -191,6 +212,41 @@ type TriggerDefinition struct {
 	CreatedAt time.Time
 }
 
+// EventDatabase is a Database that supports creating and storing events. The engine handles all parsing, scheduling and
+// execution logic for events. Integrators are not expected to parse or understand the event definitions, but must
+// store and return them when asked.
+type EventDatabase interface {
+	Database
+	// GetEvent returns the EventDefinition with the given name, or false if it doesn't exist. Names are
+	// case-insensitive.
+	GetEvent(ctx *Context, name string) (EventDefinition, bool, error)
+	// GetEvents returns all EventDefinitions for the database.
+	GetEvents(ctx *Context) ([]EventDefinition, error)
+	// SaveEvent stores the given EventDefinition. If an event with the same name already exists, must return
+	// ErrEventAlreadyExists.
+	SaveEvent(ctx *Context, definition EventDefinition) error
+	// DropEvent removes the event with the given name. Returns ErrEventDoesNotExist if the event was not found.
+	DropEvent(ctx *Context, name string) error
+	// UpdateEvent replaces the event named |originalName| with the given EventDefinition, which may have a different
+	// name. Returns ErrEventDoesNotExist if the original event was not found.
+	UpdateEvent(ctx *Context, originalName string, definition EventDefinition) error
+}
+
+// EventDefinition defines an event. Integrators are not expected to parse or understand the event definitions, but must
+// store and return them when asked.
+type EventDefinition struct {
+	// The name of this event. Event names in a database are unique.
+	Name string
+	// The text of the statement to create this event. All timestamps in the schedule are stored as literals.
+	CreateStatement string
+	// The time that the event was created.
+	CreatedAt time.Time
+	// The time that the event was last altered.
+	LastAltered time.Time
+	// The time that the event was last executed, or the zero time if it has never been executed.
+	LastExecuted time.Time
+}
+
 // TemporaryTableDatabase is a database that can query the session (which manages the temporary table state) to
 // retrieve the name of all temporary tables.
 type TemporaryTableDatabase interface {
@@ -198,6 +254,13 @@ type TemporaryTableDatabase interface {
 	GetAllTemporaryTables(ctx *Context) ([]Table, error)
 }
 
+// TemporaryTableDropper is a database that manages the temporary tables of every session itself, rather than leaving
+// them to the session. The engine calls it when a session is closed so that the session's temporary tables are dropped.
+type TemporaryTableDropper interface {
+	// DropAllTemporaryTables drops every temporary table created by the session of the context given.
+	DropAllTemporaryTables(ctx *Context) error
+}
+
 // TableCopierDatabase is a database that can copy a source table's data (without preserving indexed, fks, etc.) into
 // another destination table.
 type TableCopierDatabase interface {
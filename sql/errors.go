@@ -166,6 +166,31 @@ var (
 	// ErrTriggerCannotBeDropped is returned when dropping a trigger would cause another trigger to reference a non-existent trigger.
 	ErrTriggerCannotBeDropped = errors.NewKind(`trigger "%s" cannot be dropped as it is referenced by trigger "%s"`)
 
+	// ErrEventsNotSupported is returned when attempting to create an event on a database that doesn't support them.
+	ErrEventsNotSupported = errors.NewKind(`database "%s" doesn't support events`)
+
+	// ErrEventAlreadyExists is returned when an event with the same name already exists.
+	ErrEventAlreadyExists = errors.NewKind(`Event '%s' already exists`)
+
+	// ErrEventDoesNotExist is returned when an event does not exist.
+	ErrEventDoesNotExist = errors.NewKind(`Unknown event '%s'`)
+
+	// ErrEventCreateStatementInvalid is returned when an EventDatabase returns a CREATE EVENT statement that is invalid.
+	ErrEventCreateStatementInvalid = errors.NewKind(`Invalid CREATE EVENT statement: %s`)
+
+	// ErrEventEndsBeforeStarts is returned when an event's ENDS time is before its STARTS time.
+	ErrEventEndsBeforeStarts = errors.NewKind(`ENDS is either invalid or before STARTS`)
+
+	// ErrEventIntervalNotPositive is returned when an event's EVERY interval is not a positive amount of time.
+	ErrEventIntervalNotPositive = errors.NewKind(`INTERVAL is either not positive or too big`)
+
+	// ErrEventCannotAlterInThePast is returned when an event that would be dropped on completion is altered to only
+	// execute at a time that has passed.
+	ErrEventCannotAlterInThePast = errors.NewKind(`Event execution time is in the past and ON COMPLETION NOT PRESERVE is set. The event was not changed. Specify a time in the future.`)
+
+	// ErrEventSchedulerDisabled is returned when the event scheduler is started after being disabled at startup.
+	ErrEventSchedulerDisabled = errors.NewKind(`The event scheduler is disabled`)
+
 	// ErrStoredProceduresNotSupported is returned when attempting to create a stored procedure on a database that doesn't support them.
 	ErrStoredProceduresNotSupported = errors.NewKind(`database "%s" doesn't support stored procedures`)
 
@@ -363,6 +388,80 @@ var (
 	// ErrPartitionNotFound is thrown when a partition key on a table is not found
 	ErrPartitionNotFound = errors.NewKind("partition not found %q")
 
+	// ErrPartitioningNotSupported is returned when creating a partitioned table on a database or table that doesn't
+	// support user-defined partitioning.
+	ErrPartitioningNotSupported = errors.NewKind("table %s does not support partitioning")
+
+	// ErrNoPartitionForValue is returned when a row does not belong to any of a table's partitions.
+	ErrNoPartitionForValue = errors.NewKind("Table has no partition for value %v")
+
+	// ErrUnknownPartition is returned when a partition is referenced that a table doesn't have.
+	ErrUnknownPartition = errors.NewKind("Unknown partition '%s' in table '%s'")
+
+	// ErrPartitionClauseOnNonpartitioned is returned when selecting partitions of a table that isn't partitioned.
+	ErrPartitionClauseOnNonpartitioned = errors.NewKind("PARTITION () clause on non partitioned table")
+
+	// ErrRowDoesNotMatchPartitionSet is returned when a row is written to a partition that wasn't selected.
+	ErrRowDoesNotMatchPartitionSet = errors.NewKind("Found a row not matching the given partition set")
+
+	// ErrPartitionManagementOnNonpartitioned is returned when altering the partitions of a table that isn't partitioned.
+	ErrPartitionManagementOnNonpartitioned = errors.NewKind("Partition management on a not partitioned table is not possible")
+
+	// ErrDuplicatePartitionName is returned when two partitions of a table have the same name.
+	ErrDuplicatePartitionName = errors.NewKind("Duplicate partition name %s")
+
+	// ErrRangeNotIncreasing is returned when the bounds of RANGE partitions are not strictly increasing.
+	ErrRangeNotIncreasing = errors.NewKind("VALUES LESS THAN value must be strictly increasing for each partition")
+
+	// ErrMultipleDefinitionInListPartition is returned when a value appears in more than one LIST partition.
+	ErrMultipleDefinitionInListPartition = errors.NewKind("Multiple definition of same constant in list partitioning")
+
+	// ErrWrongPartitionValuesType is returned when a partition definition doesn't match the partitioning method.
+	ErrWrongPartitionValuesType = errors.NewKind("Only %s PARTITIONING can use VALUES %s in partition definition")
+
+	// ErrPartitionsMustBeDefined is returned when a RANGE or LIST partitioned table has no partition definitions.
+	ErrPartitionsMustBeDefined = errors.NewKind("For %s partitions each partition must be defined")
+
+	// ErrWrongPartitionFunctionType is returned when a partitioning expression does not evaluate to an integer.
+	ErrWrongPartitionFunctionType = errors.NewKind("The PARTITION function returns the wrong type")
+
+	// ErrPartitionColumnListMismatch is returned when a partition's values don't match the partitioning columns.
+	ErrPartitionColumnListMismatch = errors.NewKind("Inconsistency in usage of column lists for partitioning")
+
+	// ErrDropLastPartition is returned when dropping every partition of a table.
+	ErrDropLastPartition = errors.NewKind("Cannot remove all partitions, use DROP TABLE instead")
+
+	// ErrPartitionActionOnlyOnRangeList is returned when dropping partitions of a HASH or KEY partitioned table.
+	ErrPartitionActionOnlyOnRangeList = errors.NewKind("%s PARTITION can only be used on RANGE/LIST partitions")
+
+	// ErrCoalesceOnlyOnHashPartition is returned when coalescing partitions of a RANGE or LIST partitioned table.
+	ErrCoalesceOnlyOnHashPartition = errors.NewKind("COALESCE PARTITION can only be used on HASH/KEY partitions")
+
+	// ErrPartitionRequiresValues is returned when a RANGE or LIST partition is defined without its values.
+	ErrPartitionRequiresValues = errors.NewKind("Syntax error: %s PARTITIONING requires definition of VALUES %s for each partition")
+
+	// ErrMaxValueNotLast is returned when a RANGE partition other than the last one is bounded by MAXVALUE.
+	ErrMaxValueNotLast = errors.NewKind("MAXVALUE can only be used in last partition definition")
+
+	// ErrNullInValuesLessThan is returned when a bound of a RANGE partition is NULL.
+	ErrNullInValuesLessThan = errors.NewKind("Not allowed to use NULL value in VALUES LESS THAN")
+
+	// ErrWrongPartitionCount is returned when the number of partitions defined doesn't match the PARTITIONS clause.
+	ErrWrongPartitionCount = errors.NewKind("Wrong number of partitions defined, mismatch with previous setting")
+
+	// ErrPartitionValueNotInt is returned when a value of a RANGE or LIST partition is not an integer.
+	ErrPartitionValueNotInt = errors.NewKind("VALUES value for partition '%s' must have type INT")
+
+	// ErrPartitionFieldNotFound is returned when a partitioning column doesn't exist in the table.
+	ErrPartitionFieldNotFound = errors.NewKind("Field in list of fields for partition function not found in table")
+
+	// ErrPrimaryKeyNeedsAllPartitionColumns is returned when a table's primary key doesn't include every column used by
+	// its partitioning.
+	ErrPrimaryKeyNeedsAllPartitionColumns = errors.NewKind("A PRIMARY KEY must include all columns in the table's partitioning function")
+
+	// ErrColumnUsedInPartitioning is returned when dropping or renaming a column used by a table's partitioning.
+	ErrColumnUsedInPartitioning = errors.NewKind("Column '%s' has a partitioning function dependency and cannot be dropped or renamed.")
+
 	// ErrInsertIntoNonNullableProvidedNull is called when a null value is inserted into a non-nullable column
 	ErrInsertIntoNonNullableProvidedNull = errors.NewKind("column name '%v' is non-nullable but attempted to set a value of null")
 
@@ -599,6 +698,9 @@ var (
 	// ErrUserCreationFailure is returned when attempting to create a user and it fails for any reason.
 	ErrUserCreationFailure = errors.NewKind("Operation CREATE USER failed for %s")
 
+	// ErrUserAlterFailure is returned when attempting to alter a user and it fails for any reason.
+	ErrUserAlterFailure = errors.NewKind("Operation ALTER USER failed for %s")
+
 	// ErrRoleCreationFailure is returned when attempting to create a role and it fails for any reason.
 	ErrRoleCreationFailure = errors.NewKind("Operation CREATE ROLE failed for %s")
 
@@ -619,6 +721,20 @@ var (
 	// ErrPrivilegeCheckFailed is returned when a user does not have the correct privileges to perform an operation.
 	ErrPrivilegeCheckFailed = errors.NewKind("command denied to user %s")
 
+	// ErrColumnPrivilegeCheckFailed is returned when a user has privileges on some of a table's columns, but not on a
+	// column that the operation uses.
+	ErrColumnPrivilegeCheckFailed = errors.NewKind("%s command denied to user %s for column '%s' in table '%s'")
+
+	// ErrMustResetPassword is returned when a user with an expired password runs any statement other than one that
+	// resets their password.
+	ErrMustResetPassword = errors.NewKind("You must reset your password using ALTER USER statement before executing this statement.")
+
+	// ErrUserLimitReached is returned when an account has used all of one of its hourly resources.
+	ErrUserLimitReached = errors.NewKind("User '%s' has exceeded the '%s' resource (current value: %d)")
+
+	// ErrTooManyUserConnections is returned when an account already has its maximum number of simultaneous connections.
+	ErrTooManyUserConnections = errors.NewKind("User %s already has more than 'max_user_connections' active connections")
+
 	// ErrGrantUserDoesNotExist is returned when a user does not exist when attempting to grant them privileges.
 	ErrGrantUserDoesNotExist = errors.NewKind("You are not allowed to create a user with GRANT")
 
@@ -628,6 +744,10 @@ var (
 	// ErrGrantRevokeRoleDoesNotExist is returned when a user or role does not exist when attempting to grant or revoke roles.
 	ErrGrantRevokeRoleDoesNotExist = errors.NewKind("Unknown authorization ID %s")
 
+	// ErrRoleNotGranted is returned when activating a role, or setting a default role, that has not been granted to the
+	// user.
+	ErrRoleNotGranted = errors.NewKind("%s is not granted to %s")
+
 	// ErrShowGrantsUserDoesNotExist is returned when a user does not exist when attempting to show their grants.
 	ErrShowGrantsUserDoesNotExist = errors.NewKind("There is no such grant defined for user '%s' on host '%s'")
 
@@ -762,6 +882,22 @@ var (
 
 	// ErrDroppedJoinFilters is returned when we removed filters from a join, but failed to re-insert them
 	ErrDroppedJoinFilters = errors.NewKind("dropped filters from join, but failed to re-insert them")
+
+	// ErrQueryTimeout is returned when a SELECT statement runs longer than max_execution_time or its
+	// MAX_EXECUTION_TIME hint allows.
+	ErrQueryTimeout = errors.NewKind("Query execution was interrupted, maximum statement execution time exceeded")
+
+	// ErrTableNotLocked is returned when a session that holds table locks uses a table it didn't lock.
+	ErrTableNotLocked = errors.NewKind("Table '%s' was not locked with LOCK TABLES")
+
+	// ErrTableNotLockedForWrite is returned when a session writes to a table it only locked for reads.
+	ErrTableNotLockedForWrite = errors.NewKind("Table '%s' was locked with a READ lock and can't be updated")
+
+	// ErrCantUpdateWithReadLock is returned when a session writes while it holds the global read lock.
+	ErrCantUpdateWithReadLock = errors.NewKind("Can't execute the query because you have a conflicting read lock")
+
+	// ErrLockOrActiveTransaction is returned when a session takes the global read lock while it holds table locks.
+	ErrLockOrActiveTransaction = errors.NewKind("Can't execute the given command because you have active locked tables or an active transaction")
 )
 
 // CastSQLError returns a *mysql.SQLError with the error code and in some cases, also a SQL state, populated for the
@@ -828,6 +964,88 @@ func CastSQLError(err error) *mysql.SQLError {
 		code = 1553 // TODO: Needs to be added to vitess
 	case ErrInvalidValue.Is(err):
 		code = mysql.ERTruncatedWrongValueForField
+	case ErrEventAlreadyExists.Is(err):
+		code = 1537 // TODO: Needs to be added to vitess
+	case ErrEventDoesNotExist.Is(err):
+		code = 1539 // TODO: Needs to be added to vitess
+	case ErrEventEndsBeforeStarts.Is(err):
+		code = 1543 // TODO: Needs to be added to vitess
+	case ErrEventIntervalNotPositive.Is(err):
+		code = 1542 // TODO: Needs to be added to vitess
+	case ErrEventCannotAlterInThePast.Is(err):
+		code = 1589 // TODO: Needs to be added to vitess
+	case ErrNoPartitionForValue.Is(err):
+		code = 1526 // TODO: Needs to be added to vitess
+	case ErrUnknownPartition.Is(err):
+		code = 1735 // TODO: Needs to be added to vitess
+	case ErrPartitionClauseOnNonpartitioned.Is(err):
+		code = 1747 // TODO: Needs to be added to vitess
+	case ErrRowDoesNotMatchPartitionSet.Is(err):
+		code = 1748 // TODO: Needs to be added to vitess
+	case ErrPartitionManagementOnNonpartitioned.Is(err):
+		code = 1505 // TODO: Needs to be added to vitess
+	case ErrDuplicatePartitionName.Is(err):
+		code = 1517 // TODO: Needs to be added to vitess
+	case ErrRangeNotIncreasing.Is(err):
+		code = 1493 // TODO: Needs to be added to vitess
+	case ErrMultipleDefinitionInListPartition.Is(err):
+		code = 1495 // TODO: Needs to be added to vitess
+	case ErrWrongPartitionValuesType.Is(err):
+		code = 1480 // TODO: Needs to be added to vitess
+	case ErrPartitionsMustBeDefined.Is(err):
+		code = 1492 // TODO: Needs to be added to vitess
+	case ErrWrongPartitionFunctionType.Is(err):
+		code = 1490 // TODO: Needs to be added to vitess
+	case ErrPartitionColumnListMismatch.Is(err):
+		code = 1653 // TODO: Needs to be added to vitess
+	case ErrDropLastPartition.Is(err):
+		code = 1508 // TODO: Needs to be added to vitess
+	case ErrPartitionActionOnlyOnRangeList.Is(err):
+		code = 1512 // TODO: Needs to be added to vitess
+	case ErrCoalesceOnlyOnHashPartition.Is(err):
+		code = 1509 // TODO: Needs to be added to vitess
+	case ErrPartitionRequiresValues.Is(err):
+		code = 1479 // TODO: Needs to be added to vitess
+	case ErrMaxValueNotLast.Is(err):
+		code = 1481 // TODO: Needs to be added to vitess
+	case ErrNullInValuesLessThan.Is(err):
+		code = 1566 // TODO: Needs to be added to vitess
+	case ErrWrongPartitionCount.Is(err):
+		code = 1630 // TODO: Needs to be added to vitess
+	case ErrPartitionValueNotInt.Is(err):
+		code = 1697 // TODO: Needs to be added to vitess
+	case ErrPartitionFieldNotFound.Is(err):
+		code = 1488 // TODO: Needs to be added to vitess
+	case ErrPrimaryKeyNeedsAllPartitionColumns.Is(err):
+		code = 1503 // TODO: Needs to be added to vitess
+	case ErrColumnUsedInPartitioning.Is(err):
+		code = 3855 // TODO: Needs to be added to vitess
+	case ErrColumnPrivilegeCheckFailed.Is(err):
+		code = 1143 // TODO: Needs to be added to vitess
+	case ErrUserAlterFailure.Is(err):
+		code = 1396 // TODO: Needs to be added to vitess
+	case ErrMustResetPassword.Is(err):
+		code = 1820 // TODO: Needs to be added to vitess
+	case ErrUserLimitReached.Is(err):
+		code = mysql.ERUserLimitReached
+	case ErrTooManyUserConnections.Is(err):
+		code = mysql.ERTooManyUserConnections
+	case ErrRoleNotGranted.Is(err):
+		code = 3530 // TODO: Needs to be added to vitess
+	case ErrQueryTimeout.Is(err):
+		code = mysql.ERQueryTimeout
+	case ErrTableNotLocked.Is(err):
+		code = mysql.ERTableNotLocked
+	case ErrTableNotLockedForWrite.Is(err):
+		code = mysql.ERTableNotLockedForWrite
+	case ErrCantUpdateWithReadLock.Is(err):
+		code = mysql.ERCantUpdateWithReadLock
+	case ErrLockOrActiveTransaction.Is(err):
+		code = mysql.ERLockOrActiveTransaction
+	case ErrLockWaitTimeout.Is(err):
+		code = mysql.ERLockWaitTimeout
+	case ErrUserLockDeadlock.Is(err):
+		code = 3058 // TODO: Needs to be added to vitess
 	case ErrLockDeadlock.Is(err):
 		// ER_LOCK_DEADLOCK signals that the transaction was rolled back
 		// due to a deadlock between concurrent transactions.
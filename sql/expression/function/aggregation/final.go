@@ -0,0 +1,228 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+)
+
+// Decompose splits the aggregation given into partial aggregations, which can be computed separately over parts of
+// the rows, such as by each worker of an Exchange. A Final aggregation combines the results of the partial
+// aggregations of every part into the result of the aggregation given. Returns false if the aggregation can't be
+// split this way, as is the case of DISTINCT aggregations and aggregations that depend on the order of the rows.
+func Decompose(agg sql.Aggregation) ([]sql.Aggregation, bool) {
+	if agg.Window() != nil {
+		return nil, false
+	}
+	for _, child := range agg.Children() {
+		if hasDistinct(child) {
+			return nil, false
+		}
+	}
+
+	switch agg := agg.(type) {
+	case *Count, *Sum, *Min, *Max, *BitAnd, *BitOr, *BitXor:
+		return []sql.Aggregation{agg}, true
+	case *Avg:
+		return []sql.Aggregation{NewSum(agg.Child), NewCount(agg.Child)}, true
+	default:
+		return nil, false
+	}
+}
+
+func hasDistinct(e sql.Expression) bool {
+	var found bool
+	sql.Inspect(e, func(e sql.Expression) bool {
+		if _, ok := e.(*expression.DistinctExpression); ok {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
+
+// Final is the aggregation combining the results of the partial aggregations that Decompose split an aggregation
+// into. Its children are the results of the partial aggregations, in the order Decompose returned them. It has the
+// name, type and result of the aggregation it completes.
+type Final struct {
+	Agg      sql.Aggregation
+	Partials []sql.Expression
+}
+
+var _ sql.Aggregation = (*Final)(nil)
+
+// NewFinal returns the aggregation combining the partial results given of the aggregation given.
+func NewFinal(agg sql.Aggregation, partials ...sql.Expression) *Final {
+	return &Final{Agg: agg, Partials: partials}
+}
+
+// Resolved implements the sql.Expression interface.
+func (f *Final) Resolved() bool {
+	return expression.ExpressionsResolved(f.Partials...)
+}
+
+// String implements the sql.Expression interface. It's the string of the aggregation it completes, which names the
+// column of its result.
+func (f *Final) String() string {
+	return f.Agg.String()
+}
+
+func (f *Final) DebugString() string {
+	partials := make([]string, len(f.Partials))
+	for i, p := range f.Partials {
+		partials[i] = sql.DebugString(p)
+	}
+	return fmt.Sprintf("FINAL %s(%s)", strings.ToUpper(f.functionName()), strings.Join(partials, ", "))
+}
+
+func (f *Final) functionName() string {
+	if fn, ok := f.Agg.(sql.FunctionExpression); ok {
+		return fn.FunctionName()
+	}
+	return f.Agg.String()
+}
+
+// Type implements the sql.Expression interface.
+func (f *Final) Type() sql.Type {
+	return f.Agg.Type()
+}
+
+// IsNullable implements the sql.Expression interface.
+func (f *Final) IsNullable() bool {
+	return f.Agg.IsNullable()
+}
+
+// Children implements the sql.Expression interface.
+func (f *Final) Children() []sql.Expression {
+	return f.Partials
+}
+
+// WithChildren implements the sql.Expression interface.
+func (f *Final) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	if len(children) != len(f.Partials) {
+		return nil, sql.ErrInvalidChildrenNumber.New(f, len(children), len(f.Partials))
+	}
+	return NewFinal(f.Agg, children...), nil
+}
+
+// Eval implements the sql.Expression interface.
+func (f *Final) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	return nil, ErrEvalUnsupportedOnAggregation.New(f.functionName())
+}
+
+// NewBuffer implements the sql.Aggregation interface.
+func (f *Final) NewBuffer() (sql.AggregationBuffer, error) {
+	switch f.Agg.(type) {
+	case *Count:
+		return &countMergeBuffer{expr: f.Partials[0]}, nil
+	case *Sum:
+		return NewSumBuffer(f.Partials[0]), nil
+	case *Min:
+		return NewMinBuffer(f.Partials[0]), nil
+	case *Max:
+		return NewMaxBuffer(f.Partials[0]), nil
+	case *BitAnd:
+		return NewBitAndBuffer(f.Partials[0]), nil
+	case *BitOr:
+		return NewBitOrBuffer(f.Partials[0]), nil
+	case *BitXor:
+		return NewBitXorBuffer(f.Partials[0]), nil
+	case *Avg:
+		return &avgMergeBuffer{avgBuffer: NewAvgBuffer(f.Partials[0]), count: f.Partials[1]}, nil
+	default:
+		return nil, fmt.Errorf("aggregation %s can't be computed from partial aggregations", f.Agg)
+	}
+}
+
+// NewWindowFunction implements the sql.WindowAdaptableExpression interface. A final aggregation only exists in a
+// GroupBy, so it has no window.
+func (f *Final) NewWindowFunction() (sql.WindowFunction, error) {
+	return nil, fmt.Errorf("aggregation %s can't be used as a window function", f)
+}
+
+// WithWindow implements the sql.Aggregation interface.
+func (f *Final) WithWindow(window *sql.WindowDefinition) (sql.Aggregation, error) {
+	if window != nil {
+		return nil, fmt.Errorf("aggregation %s can't be used as a window function", f)
+	}
+	return f, nil
+}
+
+// Window implements the sql.Aggregation interface.
+func (f *Final) Window() *sql.WindowDefinition {
+	return nil
+}
+
+// countMergeBuffer adds up partial counts.
+type countMergeBuffer struct {
+	cnt  int64
+	expr sql.Expression
+}
+
+// Update implements the AggregationBuffer interface.
+func (c *countMergeBuffer) Update(ctx *sql.Context, row sql.Row) error {
+	v, err := c.expr.Eval(ctx, row)
+	if err != nil {
+		return err
+	}
+	if v != nil {
+		c.cnt += v.(int64)
+	}
+	return nil
+}
+
+// Eval implements the AggregationBuffer interface.
+func (c *countMergeBuffer) Eval(ctx *sql.Context) (interface{}, error) {
+	return c.cnt, nil
+}
+
+// Dispose implements the Disposable interface.
+func (c *countMergeBuffer) Dispose() {
+	expression.Dispose(c.expr)
+}
+
+// avgMergeBuffer adds up partial sums and partial counts, reading the sums with the expression of its avgBuffer.
+type avgMergeBuffer struct {
+	*avgBuffer
+	count sql.Expression
+}
+
+// Update implements the AggregationBuffer interface.
+func (a *avgMergeBuffer) Update(ctx *sql.Context, row sql.Row) error {
+	sum, err := a.expr.Eval(ctx, row)
+	if err != nil {
+		return err
+	}
+	count, err := a.count.Eval(ctx, row)
+	if err != nil {
+		return err
+	}
+	if sum == nil || count == nil {
+		return nil
+	}
+	a.sum.PerformSum(sum)
+	a.rows += count.(int64)
+	return nil
+}
+
+// Dispose implements the Disposable interface.
+func (a *avgMergeBuffer) Dispose() {
+	a.avgBuffer.Dispose()
+	expression.Dispose(a.count)
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package aggregation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+func TestDecompose(t *testing.T) {
+	col := expression.NewGetField(0, types.Int64, "col1", true)
+
+	testCases := []struct {
+		agg      sql.Aggregation
+		expected []string
+	}{
+		{NewCount(col), []string{"COUNT(col1)"}},
+		{NewSum(col), []string{"SUM(col1)"}},
+		{NewMin(col), []string{"MIN(col1)"}},
+		{NewMax(col), []string{"MAX(col1)"}},
+		{NewBitAnd(col), []string{"BITAND(col1)"}},
+		{NewBitOr(col), []string{"BITOR(col1)"}},
+		{NewBitXor(col), []string{"BITXOR(col1)"}},
+		{NewAvg(col), []string{"SUM(col1)", "COUNT(col1)"}},
+		{NewSum(expression.NewDistinctExpression(col)), nil},
+		{NewCountDistinct(col), nil},
+		{NewAnyValue(col), nil},
+		{NewFirst(col), nil},
+		{NewFinal(NewSum(col), col), nil},
+	}
+
+	for _, tt := range testCases {
+		t.Run(tt.agg.String(), func(t *testing.T) {
+			parts, ok := Decompose(tt.agg)
+			if tt.expected == nil {
+				require.False(t, ok)
+				return
+			}
+			require.True(t, ok)
+			var names []string
+			for _, p := range parts {
+				names = append(names, p.String())
+			}
+			require.Equal(t, tt.expected, names)
+		})
+	}
+}
+
+func TestFinal(t *testing.T) {
+	col := expression.NewGetField(0, types.Int64, "col1", true)
+	runs := [][]sql.Row{
+		{{int64(1)}, {int64(6)}, {nil}},
+		{},
+		{{int64(3)}, {int64(12)}},
+		{{nil}, {int64(-4)}},
+	}
+	var rows []sql.Row
+	for _, run := range runs {
+		rows = append(rows, run...)
+	}
+
+	testCases := []sql.Aggregation{
+		NewCount(col),
+		NewCount(expression.NewStar()),
+		NewSum(col),
+		NewMin(col),
+		NewMax(col),
+		NewAvg(col),
+		NewBitAnd(col),
+		NewBitOr(col),
+		NewBitXor(col),
+	}
+
+	for _, agg := range testCases {
+		t.Run(agg.String(), func(t *testing.T) {
+			ctx := sql.NewEmptyContext()
+			parts, ok := Decompose(agg)
+			require.True(t, ok)
+
+			// Every run is aggregated separately, and the final aggregation combines the results of the runs
+			var partials []sql.Expression
+			for i, p := range parts {
+				partials = append(partials, expression.NewGetField(i, p.Type(), p.String(), true))
+			}
+			final := NewFinal(agg, partials...)
+			require.Equal(t, agg.String(), final.String())
+			require.Equal(t, agg.Type(), final.Type())
+
+			buf, err := final.NewBuffer()
+			require.NoError(t, err)
+			for _, run := range runs {
+				var partialRow sql.Row
+				for _, p := range parts {
+					partialRow = append(partialRow, aggregate(t, p, run...))
+				}
+				require.NoError(t, buf.Update(ctx, partialRow))
+			}
+
+			require.Equal(t, aggregate(t, agg, rows...), evalBuffer(t, buf))
+		})
+	}
+}
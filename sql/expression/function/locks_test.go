@@ -96,6 +96,27 @@ func TestLockIsUsed(t *testing.T) {
 	tf.Test(t, user1, nil)
 }
 
+func TestLockIsUsedMultipleOwners(t *testing.T) {
+	ls := sql.NewLockSubsystem()
+	isUsed := NewIsUsedLock(ls)
+	tf := NewTestFactory(isUsed)
+
+	user0 := sql.NewEmptyContext()
+	user1 := sql.NewEmptyContext()
+	require.NoError(t, ls.Lock(user0, "lock0", 0))
+	require.NoError(t, ls.Lock(user0, "lock1", 0))
+	require.NoError(t, ls.Lock(user1, "lock2", 0))
+	require.NoError(t, ls.Lock(user1, "lock2", 0))
+
+	// A lock acquired more than once is used until it's released as many times
+	require.NoError(t, ls.Unlock(user1, "lock2"))
+
+	tf.AddSucceeding(user0.ID(), "lock0")
+	tf.AddSucceeding(user0.ID(), "lock1")
+	tf.AddSucceeding(user1.ID(), "lock2")
+	tf.Test(t, user0, nil)
+}
+
 func TestReleaseLock(t *testing.T) {
 	ls := sql.NewLockSubsystem()
 	releaseLock := NewReleaseLock(ls)
@@ -140,18 +161,16 @@ func TestReleaseAllLocks(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, 3, count)
 
-	state, owner := ls.GetLockState("lock0")
-	assert.Equal(t, sql.LockInUse, state)
-	assert.Equal(t, user0.ID(), owner)
-	ls.GetLockState("lock1")
-	assert.Equal(t, sql.LockInUse, state)
-	assert.Equal(t, user0.ID(), owner)
-	ls.GetLockState("lock2")
-	assert.Equal(t, sql.LockInUse, state)
-	assert.Equal(t, user0.ID(), owner)
+	for _, name := range []string{"lock0", "lock1", "lock2"} {
+		state, owner := ls.GetLockState(name)
+		assert.Equal(t, sql.LockInUse, state)
+		assert.Equal(t, user0.ID(), owner)
+	}
 
-	_, err = releaseAllLocksForLS(ls)(user0, nil)
+	// Each acquisition of a lock counts as one of the locks released
+	released, err := releaseAllLocksForLS(ls)(user0, nil)
 	require.NoError(t, err)
+	assert.Equal(t, 5, released)
 
 	count = 0
 	err = user0.IterLocks(func(name string) error {
@@ -159,17 +178,40 @@ func TestReleaseAllLocks(t *testing.T) {
 		return nil
 	})
 	require.NoError(t, err)
-	assert.Equal(t, 3, count)
+	assert.Equal(t, 0, count)
+
+	for _, name := range []string{"lock0", "lock1", "lock2"} {
+		state, owner := ls.GetLockState(name)
+		assert.Equal(t, sql.LockFree, state)
+		assert.Equal(t, uint32(0), owner)
+	}
+
+	released, err = releaseAllLocksForLS(ls)(user0, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 0, released)
+}
+
+func TestReleaseAllLocksOfOtherSessions(t *testing.T) {
+	ls := sql.NewLockSubsystem()
+	isUsed := NewIsUsedLock(ls)
 
-	state, owner = ls.GetLockState("lock0")
-	assert.Equal(t, sql.LockFree, state)
-	assert.Equal(t, uint32(0), owner)
-	ls.GetLockState("lock1")
-	assert.Equal(t, sql.LockFree, state)
-	assert.Equal(t, uint32(0), owner)
-	ls.GetLockState("lock2")
-	assert.Equal(t, sql.LockFree, state)
-	assert.Equal(t, uint32(0), owner)
+	user0 := sql.NewEmptyContext()
+	user1 := sql.NewEmptyContext()
+	require.NoError(t, ls.Lock(user0, "lock0", 0))
+	require.NoError(t, ls.Lock(user0, "lock1", 0))
+	require.NoError(t, ls.Lock(user1, "lock2", 0))
+	require.NoError(t, ls.Lock(user1, "lock2", 0))
+
+	// Only the locks of the releasing session are released
+	released, err := releaseAllLocksForLS(ls)(user1, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, released)
+
+	tf := NewTestFactory(isUsed)
+	tf.AddSucceeding(user0.ID(), "lock0")
+	tf.AddSucceeding(user0.ID(), "lock1")
+	tf.AddSucceeding(nil, "lock2")
+	tf.Test(t, user1, nil)
 }
 
 // releaseAllLocksForLS returns the logic to execute when the sql function release_all_locks is executed
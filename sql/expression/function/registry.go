@@ -61,6 +61,7 @@ var BuiltIns = []sql.Function{
 	sql.Function1{Name: "crc32", Fn: NewCrc32},
 	sql.NewFunction0("curdate", NewCurrDate),
 	sql.NewFunction0("current_date", NewCurrentDate),
+	sql.NewFunction0("current_role", NewCurrentRole),
 	sql.NewFunction0("current_time", NewCurrentTime),
 	sql.FunctionN{Name: "current_timestamp", Fn: NewCurrTimestamp},
 	sql.NewFunction0("current_user", NewCurrentUser),
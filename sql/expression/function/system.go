@@ -15,6 +15,10 @@
 package function
 
 import (
+	"fmt"
+	"sort"
+	"strings"
+
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/types"
 )
@@ -103,3 +107,44 @@ func (c User) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
 func (c User) WithChildren(children ...sql.Expression) (sql.Expression, error) {
 	return NoArgFuncWithChildren(c, children)
 }
+
+// CurrentRole is the CURRENT_ROLE() function, which returns the roles that are active in the session.
+type CurrentRole struct {
+	NoArgFunc
+}
+
+var _ sql.FunctionExpression = CurrentRole{}
+
+func NewCurrentRole() sql.Expression {
+	return CurrentRole{
+		NoArgFunc: NoArgFunc{"current_role", types.LongText},
+	}
+}
+
+func (c CurrentRole) IsNonDeterministic() bool {
+	return true
+}
+
+// Description implements sql.FunctionExpression
+func (c CurrentRole) Description() string {
+	return "returns the active roles for the current session."
+}
+
+// Eval implements sql.Expression
+func (c CurrentRole) Eval(ctx *sql.Context, row sql.Row) (interface{}, error) {
+	activeRoles, _ := ctx.Session.GetActiveRoles()
+	if len(activeRoles) == 0 {
+		return "NONE", nil
+	}
+	roles := make([]string, len(activeRoles))
+	for i, role := range activeRoles {
+		roles[i] = fmt.Sprintf("`%s`@`%s`", role.Name, role.Host)
+	}
+	sort.Strings(roles)
+	return strings.Join(roles, ","), nil
+}
+
+// WithChildren implements sql.Expression
+func (c CurrentRole) WithChildren(children ...sql.Expression) (sql.Expression, error) {
+	return NoArgFuncWithChildren(c, children)
+}
@@ -31,6 +31,15 @@ type FunctionProvider interface {
 	Function(ctx *Context, name string) (Function, error)
 }
 
+// ExternalFunctionProvider is an interface that allows the functions provided by the integrator to be told apart from
+// the built-in functions, such as when listing the routines that a view uses. It's implemented by the analyzer's
+// Catalog.
+type ExternalFunctionProvider interface {
+	// ExternalFunction returns the function provided by the integrator with the name given, case-insensitive, or
+	// ErrFunctionNotFound if there isn't one.
+	ExternalFunction(ctx *Context, name string) (Function, error)
+}
+
 type CreateFunc0Args func() Expression
 type CreateFunc1Args func(e1 Expression) Expression
 type CreateFunc2Args func(e1, e2 Expression) Expression
@@ -0,0 +1,226 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// waitingForGlobalReadLock is the state of processes waiting for the global read lock to be released, or taken.
+	waitingForGlobalReadLock = "Waiting for global read lock"
+	// waitingForCommitLock is the state of processes waiting to commit while the global read lock is held.
+	waitingForCommitLock = "Waiting for commit lock"
+)
+
+// GlobalReadLock is the lock taken by FLUSH TABLES WITH READ LOCK, which lets every session read but keeps them from
+// writing and committing until it's released, as is needed to take consistent backups. Any number of sessions may
+// hold it at once. It's only granted once other sessions are done with the statements they're writing with, and
+// have unlocked the tables they locked for writes with LOCK TABLES.
+type GlobalReadLock struct {
+	mu sync.Mutex
+	// holders are the sessions that hold the lock.
+	holders map[uint32]struct{}
+	// waiting are the sessions that wait to take the lock. Sessions that start writing wait for them as well, so that
+	// a steady stream of writes can't keep the lock from being taken.
+	waiting map[uint32]struct{}
+	// writes is the number of statements each session runs that write or commit.
+	writes map[uint32]int
+	// tableWriters are the sessions that hold tables locked for writes with LOCK TABLES.
+	tableWriters map[uint32]struct{}
+	// changed is closed, and replaced, whenever sessions release the lock, stop waiting for it, or stop writing.
+	changed chan struct{}
+}
+
+// NewGlobalReadLock returns a new GlobalReadLock that no session holds.
+func NewGlobalReadLock() *GlobalReadLock {
+	return &GlobalReadLock{
+		holders:      make(map[uint32]struct{}),
+		waiting:      make(map[uint32]struct{}),
+		writes:       make(map[uint32]int),
+		tableWriters: make(map[uint32]struct{}),
+		changed:      make(chan struct{}),
+	}
+}
+
+// Lock takes the lock for the session of the context, waiting up to its lock_wait_timeout for other sessions to
+// finish writing.
+func (l *GlobalReadLock) Lock(ctx *Context) error {
+	id := ctx.ID()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.holders[id]; ok {
+		return nil
+	}
+
+	l.waiting[id] = struct{}{}
+	err := l.wait(ctx, waitingForGlobalReadLock, func() bool {
+		for other, n := range l.writes {
+			if other != id && n > 0 {
+				return true
+			}
+		}
+		for other := range l.tableWriters {
+			if other != id {
+				return true
+			}
+		}
+		return false
+	})
+	delete(l.waiting, id)
+	if err != nil {
+		l.notify()
+		return err
+	}
+	l.holders[id] = struct{}{}
+	return nil
+}
+
+// Unlock releases the lock held by the session given, if any.
+func (l *GlobalReadLock) Unlock(id uint32) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.holders[id]; ok {
+		delete(l.holders, id)
+		l.notify()
+	}
+}
+
+// BeginWrite waits up to the lock_wait_timeout of the session of the context for other sessions to release the
+// lock, and then keeps it from being taken until EndWrite is called. A commit is allowed while the session holds the
+// lock itself, but any other write returns ErrCantUpdateWithReadLock.
+func (l *GlobalReadLock) BeginWrite(ctx *Context, commit bool) error {
+	state := waitingForGlobalReadLock
+	if commit {
+		state = waitingForCommitLock
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.waitToWrite(ctx, state, commit); err != nil {
+		return err
+	}
+	l.writes[ctx.ID()]++
+	return nil
+}
+
+// EndWrite ends a write started with BeginWrite.
+func (l *GlobalReadLock) EndWrite(ctx *Context) {
+	id := ctx.ID()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.writes[id] <= 1 {
+		delete(l.writes, id)
+	} else {
+		l.writes[id]--
+	}
+	l.notify()
+}
+
+// BeginTableWrites waits up to the lock_wait_timeout of the session of the context for other sessions to release the
+// lock, and then keeps it from being taken until EndTableWrites is called, as is done for the tables a session locks
+// for writes with LOCK TABLES. Returns ErrCantUpdateWithReadLock if the session holds the lock itself.
+func (l *GlobalReadLock) BeginTableWrites(ctx *Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.waitToWrite(ctx, waitingForGlobalReadLock, false); err != nil {
+		return err
+	}
+	l.tableWriters[ctx.ID()] = struct{}{}
+	return nil
+}
+
+// EndTableWrites ends the table writes of the session given, once it unlocks its tables.
+func (l *GlobalReadLock) EndTableWrites(id uint32) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.tableWriters[id]; ok {
+		delete(l.tableWriters, id)
+		l.notify()
+	}
+}
+
+// waitToWrite waits until the session of the context may write. It must be called with the mutex held.
+func (l *GlobalReadLock) waitToWrite(ctx *Context, state string, commit bool) error {
+	id := ctx.ID()
+	if _, ok := l.holders[id]; ok {
+		if commit {
+			return nil
+		}
+		return ErrCantUpdateWithReadLock.New()
+	}
+	// A session that is already writing keeps the lock from being taken, so it doesn't wait for sessions waiting to
+	// take it, which would wait for it in turn.
+	_, writesTables := l.tableWriters[id]
+	alreadyWriting := writesTables || l.writes[id] > 0
+	return l.wait(ctx, state, func() bool {
+		if len(l.holders) > 0 {
+			return true
+		}
+		if alreadyWriting {
+			return false
+		}
+		for other := range l.waiting {
+			if other != id {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// wait waits until the function given returns false, which is called with the mutex held, or until the
+// lock_wait_timeout of the session of the context passes. The process of the context shows the state given while it
+// waits. It must be called with the mutex held, which is held again once it returns.
+func (l *GlobalReadLock) wait(ctx *Context, state string, blocked func() bool) error {
+	if !blocked() {
+		return nil
+	}
+	ctx.ProcessList.UpdateState(ctx.Pid(), state)
+	defer ctx.ProcessList.UpdateState(ctx.Pid(), "")
+
+	var timer <-chan time.Time
+	if timeout := LockWaitTimeout(ctx); timeout >= 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		timer = t.C
+	}
+	for blocked() {
+		changed := l.changed
+		l.mu.Unlock()
+
+		var err error
+		select {
+		case <-changed:
+		case <-timer:
+			err = ErrLockWaitTimeout.New()
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+
+		l.mu.Lock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// notify wakes up every waiting session, so that it checks whether it may proceed. It must be called with the mutex
+// held.
+func (l *GlobalReadLock) notify() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
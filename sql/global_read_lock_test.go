@@ -0,0 +1,102 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobalReadLock(t *testing.T) {
+	// blocked checks that the function given waits for the lock, until its context times out
+	blocked := func(t *testing.T, ctx *Context, f func(ctx *Context) error) {
+		subCtx, cancel := context.WithTimeout(ctx, testLockTimeout)
+		defer cancel()
+		require.ErrorIs(t, f(ctx.WithContext(subCtx)), context.DeadlineExceeded)
+	}
+	write := func(l *GlobalReadLock) func(ctx *Context) error {
+		return func(ctx *Context) error {
+			return l.BeginWrite(ctx, false)
+		}
+	}
+
+	t.Run("held", func(t *testing.T) {
+		l := NewGlobalReadLock()
+		holder, other := NewEmptyContext(), NewEmptyContext()
+		require.NoError(t, l.Lock(holder))
+		require.NoError(t, l.Lock(holder))
+
+		// The holder can commit, but not write
+		require.True(t, ErrCantUpdateWithReadLock.Is(l.BeginWrite(holder, false)))
+		require.True(t, ErrCantUpdateWithReadLock.Is(l.BeginTableWrites(holder)))
+		require.NoError(t, l.BeginWrite(holder, true))
+		l.EndWrite(holder)
+
+		// Other sessions wait to write and commit, but can take the lock as well
+		blocked(t, other, write(l))
+		blocked(t, other, func(ctx *Context) error {
+			return l.BeginWrite(ctx, true)
+		})
+		blocked(t, other, l.BeginTableWrites)
+		require.NoError(t, l.Lock(other))
+		l.Unlock(other.ID())
+
+		// A waiting session proceeds once the lock is released
+		done := make(chan error)
+		go func() {
+			done <- l.BeginWrite(other, false)
+		}()
+		time.Sleep(10 * time.Millisecond)
+		l.Unlock(holder.ID())
+		require.NoError(t, <-done)
+		l.EndWrite(other)
+	})
+
+	t.Run("writes", func(t *testing.T) {
+		l := NewGlobalReadLock()
+		writer, locker, other := NewEmptyContext(), NewEmptyContext(), NewEmptyContext()
+		require.NoError(t, l.BeginWrite(writer, false))
+		blocked(t, locker, l.Lock)
+
+		done := make(chan error)
+		go func() {
+			done <- l.Lock(locker)
+		}()
+		time.Sleep(10 * time.Millisecond)
+
+		// New writes wait for the waiting session, except for those of sessions that are already writing
+		blocked(t, other, write(l))
+		require.NoError(t, l.BeginWrite(writer, false))
+		l.EndWrite(writer)
+		l.EndWrite(writer)
+		require.NoError(t, <-done)
+		blocked(t, writer, write(l))
+	})
+
+	t.Run("table writes", func(t *testing.T) {
+		l := NewGlobalReadLock()
+		writer, locker := NewEmptyContext(), NewEmptyContext()
+		require.NoError(t, l.BeginTableWrites(writer))
+		require.NoError(t, l.BeginWrite(writer, false))
+		l.EndWrite(writer)
+		blocked(t, locker, l.Lock)
+
+		l.EndTableWrites(writer.ID())
+		require.NoError(t, l.Lock(locker))
+	})
+}
@@ -0,0 +1,557 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/dolthub/vitess/go/mysql"
+)
+
+// OptimizerHintType is the name of an optimizer hint, such as JOIN_ORDER or NO_INDEX.
+type OptimizerHintType string
+
+const (
+	HintJoinOrder           OptimizerHintType = "JOIN_ORDER"
+	HintJoinPrefix          OptimizerHintType = "JOIN_PREFIX"
+	HintJoinSuffix          OptimizerHintType = "JOIN_SUFFIX"
+	HintJoinFixedOrder      OptimizerHintType = "JOIN_FIXED_ORDER"
+	HintHashJoin            OptimizerHintType = "HASH_JOIN"
+	HintNoHashJoin          OptimizerHintType = "NO_HASH_JOIN"
+	HintMergeJoin           OptimizerHintType = "MERGE_JOIN"
+	HintNoMergeJoin         OptimizerHintType = "NO_MERGE_JOIN"
+	HintIndex               OptimizerHintType = "INDEX"
+	HintNoIndex             OptimizerHintType = "NO_INDEX"
+	HintJoinIndex           OptimizerHintType = "JOIN_INDEX"
+	HintNoJoinIndex         OptimizerHintType = "NO_JOIN_INDEX"
+	HintOrderIndex          OptimizerHintType = "ORDER_INDEX"
+	HintNoOrderIndex        OptimizerHintType = "NO_ORDER_INDEX"
+	HintNoRangeOptimization OptimizerHintType = "NO_RANGE_OPTIMIZATION"
+	HintSemiJoin            OptimizerHintType = "SEMIJOIN"
+	HintNoSemiJoin          OptimizerHintType = "NO_SEMIJOIN"
+	HintSetVar              OptimizerHintType = "SET_VAR"
+	HintQbName              OptimizerHintType = "QB_NAME"
+	HintMaxExecutionTime    OptimizerHintType = "MAX_EXECUTION_TIME"
+)
+
+// Warning codes for optimizer hints that can't be applied. Hints never cause a statement to fail.
+const (
+	ERWarnUnsupportedMaxExecutionTime = 3125
+	ERWarnConflictingHint             = 3126
+	ERWarnUnknownQbName               = 3127
+	ERUnresolvedHintName              = 3128
+	ERNotSettableInSetVarHint         = 4537
+)
+
+type hintArgs uint8
+
+const (
+	// hintArgsTables takes a list of tables, which may be empty
+	hintArgsTables hintArgs = iota
+	// hintArgsIndexes takes a table followed by a list of its indexes, which may be empty
+	hintArgsIndexes
+	// hintArgsStrategies takes a list of semi join strategies
+	hintArgsStrategies
+	// hintArgsSetVar takes a variable assignment
+	hintArgsSetVar
+	// hintArgsName takes a single name
+	hintArgsName
+	// hintArgsMillis takes a number of milliseconds
+	hintArgsMillis
+)
+
+var optimizerHintArgs = map[OptimizerHintType]hintArgs{
+	HintJoinOrder:           hintArgsTables,
+	HintJoinPrefix:          hintArgsTables,
+	HintJoinSuffix:          hintArgsTables,
+	HintJoinFixedOrder:      hintArgsTables,
+	HintHashJoin:            hintArgsTables,
+	HintNoHashJoin:          hintArgsTables,
+	HintMergeJoin:           hintArgsTables,
+	HintNoMergeJoin:         hintArgsTables,
+	HintIndex:               hintArgsIndexes,
+	HintNoIndex:             hintArgsIndexes,
+	HintJoinIndex:           hintArgsIndexes,
+	HintNoJoinIndex:         hintArgsIndexes,
+	HintOrderIndex:          hintArgsIndexes,
+	HintNoOrderIndex:        hintArgsIndexes,
+	HintNoRangeOptimization: hintArgsIndexes,
+	HintSemiJoin:            hintArgsStrategies,
+	HintNoSemiJoin:          hintArgsStrategies,
+	HintSetVar:              hintArgsSetVar,
+	HintQbName:              hintArgsName,
+	HintMaxExecutionTime:    hintArgsMillis,
+}
+
+// semiJoinStrategies are the strategies MySQL accepts in SEMIJOIN and NO_SEMIJOIN hints.
+var semiJoinStrategies = map[string]struct{}{
+	"DUPSWEEDOUT":     {},
+	"FIRSTMATCH":      {},
+	"LOOSESCAN":       {},
+	"MATERIALIZATION": {},
+}
+
+// HintTable is a table named by an optimizer hint, optionally qualified by the query block it belongs to, as in
+// t1@qb1.
+type HintTable struct {
+	Name       string
+	QueryBlock string
+}
+
+func (t HintTable) String() string {
+	if t.QueryBlock != "" {
+		return t.Name + "@" + t.QueryBlock
+	}
+	return t.Name
+}
+
+// OptimizerHint is a single hint from an optimizer hint comment, such as /*+ JOIN_ORDER(t1, t2) */.
+type OptimizerHint struct {
+	Type OptimizerHintType
+	// QueryBlock is the query block named by a leading @name argument. Empty for the query block the comment
+	// belongs to.
+	QueryBlock string
+	// Tables are the tables named by join and index hints
+	Tables []HintTable
+	// Indexes are the indexes named by index hints. Empty for all indexes of the table.
+	Indexes []string
+	// Strategies are the strategies named by SEMIJOIN and NO_SEMIJOIN
+	Strategies []string
+	// Name is the query block name of QB_NAME, or the variable name of SET_VAR
+	Name string
+	// Value is the value of SET_VAR, one of int64, float64 or string
+	Value interface{}
+	// Millis is the timeout of MAX_EXECUTION_TIME
+	Millis uint64
+}
+
+// Table returns the single table of an index hint.
+func (h *OptimizerHint) Table() HintTable {
+	if len(h.Tables) == 0 {
+		return HintTable{}
+	}
+	return h.Tables[0]
+}
+
+// TargetsQueryBlock returns whether the hint applies to the query block named, given that it was found in that query
+// block or moved there by name.
+func (h *OptimizerHint) TargetsQueryBlock(name string) bool {
+	return h.QueryBlock == "" || strings.EqualFold(h.QueryBlock, name)
+}
+
+// IsStatementLevel returns whether the hint applies to the whole statement rather than to a query block.
+func (h *OptimizerHint) IsStatementLevel() bool {
+	return h.Type == HintSetVar || h.Type == HintMaxExecutionTime
+}
+
+func (h *OptimizerHint) String() string {
+	var args []string
+	switch optimizerHintArgs[h.Type] {
+	case hintArgsTables:
+		for _, t := range h.Tables {
+			args = append(args, t.String())
+		}
+		args = []string{strings.Join(args, ", ")}
+	case hintArgsIndexes:
+		args = []string{h.Table().String()}
+		if len(h.Indexes) > 0 {
+			args = append(args, strings.Join(h.Indexes, ", "))
+		}
+	case hintArgsStrategies:
+		args = []string{strings.Join(h.Strategies, ", ")}
+	case hintArgsSetVar:
+		var value string
+		switch v := h.Value.(type) {
+		case string:
+			value = "'" + strings.ReplaceAll(v, "'", "''") + "'"
+		default:
+			value = fmt.Sprint(v)
+		}
+		args = []string{h.Name + " = " + value}
+	case hintArgsName:
+		args = []string{h.Name}
+	case hintArgsMillis:
+		args = []string{strconv.FormatUint(h.Millis, 10)}
+	}
+	if h.QueryBlock != "" {
+		args = append([]string{"@" + h.QueryBlock}, args...)
+	}
+	return string(h.Type) + "(" + strings.TrimSpace(strings.Join(args, " ")) + ")"
+}
+
+// conflictKeys returns the keys identifying what the hint decides. Two hints of a query block that share a key
+// conflict, and only the first of them is applied.
+func (h *OptimizerHint) conflictKeys() []string {
+	table := strings.ToLower(h.Table().Name)
+	switch h.Type {
+	case HintJoinOrder, HintJoinPrefix, HintJoinSuffix, HintJoinFixedOrder:
+		return []string{"join_order"}
+	case HintSemiJoin, HintNoSemiJoin:
+		return []string{"semijoin"}
+	case HintQbName:
+		return []string{"qb_name"}
+	case HintMaxExecutionTime:
+		return []string{"max_execution_time"}
+	case HintSetVar:
+		return []string{"set_var:" + strings.ToLower(h.Name)}
+	case HintHashJoin, HintNoHashJoin, HintMergeJoin, HintNoMergeJoin:
+		var keys []string
+		for _, t := range h.Tables {
+			keys = append(keys, string(h.Type)+":"+strings.ToLower(t.Name))
+		}
+		if len(keys) == 0 {
+			keys = append(keys, string(h.Type))
+		}
+		return keys
+	case HintIndex, HintNoIndex:
+		return []string{"join_index:" + table, "order_index:" + table}
+	case HintJoinIndex, HintNoJoinIndex:
+		return []string{"join_index:" + table}
+	case HintOrderIndex, HintNoOrderIndex:
+		return []string{"order_index:" + table}
+	case HintNoRangeOptimization:
+		return []string{"range:" + table}
+	default:
+		return nil
+	}
+}
+
+// OptimizerHints are the hints of an optimizer hint comment, in the order they were written.
+type OptimizerHints []*OptimizerHint
+
+// OfType returns the hints of the types given.
+func (hs OptimizerHints) OfType(types ...OptimizerHintType) OptimizerHints {
+	var ret OptimizerHints
+	for _, h := range hs {
+		for _, t := range types {
+			if h.Type == t {
+				ret = append(ret, h)
+				break
+			}
+		}
+	}
+	return ret
+}
+
+// QueryBlockName returns the name given to the query block by a QB_NAME hint, if any.
+func (hs OptimizerHints) QueryBlockName() string {
+	for _, h := range hs {
+		if h.Type == HintQbName && h.QueryBlock == "" {
+			return h.Name
+		}
+	}
+	return ""
+}
+
+// String returns the hints as an optimizer hint comment, or the empty string if there are none.
+func (hs OptimizerHints) String() string {
+	if len(hs) == 0 {
+		return ""
+	}
+	strs := make([]string, len(hs))
+	for i, h := range hs {
+		strs[i] = h.String()
+	}
+	return "/*+ " + strings.Join(strs, " ") + " */"
+}
+
+// WithoutConflicts returns the hints without those that conflict with or duplicate an earlier hint, warning about
+// each hint it removes.
+func (hs OptimizerHints) WithoutConflicts(ctx *Context) OptimizerHints {
+	var ret OptimizerHints
+	seen := make(map[string]struct{})
+	for _, h := range hs {
+		keys := h.conflictKeys()
+		conflicting := false
+		for _, k := range keys {
+			if _, ok := seen[k]; ok {
+				conflicting = true
+				break
+			}
+		}
+		if conflicting {
+			ctx.Warn(ERWarnConflictingHint, "Hint %s is ignored as conflicting/duplicated", h.String())
+			continue
+		}
+		for _, k := range keys {
+			seen[k] = struct{}{}
+		}
+		ret = append(ret, h)
+	}
+	return ret
+}
+
+// IsOptimizerHintComment returns whether the comment given is an optimizer hint comment, which starts with /*+.
+func IsOptimizerHintComment(comment string) bool {
+	return strings.HasPrefix(strings.TrimSpace(comment), "/*+")
+}
+
+// ParseOptimizerHints parses the hints of an optimizer hint comment such as /*+ JOIN_ORDER(t1, t2) NO_INDEX(t2) */.
+// Like MySQL, a syntax error doesn't fail the statement: the hints before it are returned along with a warning to be
+// reported to the client, and the rest of the comment is ignored.
+func ParseOptimizerHints(comment string) (OptimizerHints, *Warning) {
+	comment = strings.TrimSpace(comment)
+	comment = strings.TrimPrefix(comment, "/*+")
+	comment = strings.TrimSuffix(comment, "*/")
+	p := &hintParser{text: comment}
+
+	var hints OptimizerHints
+	for {
+		p.skipSpace()
+		if p.done() {
+			return hints, nil
+		}
+		hint, err := p.hint()
+		if err != nil {
+			return hints, &Warning{
+				Level:   "Warning",
+				Code:    mysql.ERParseError,
+				Message: fmt.Sprintf("Optimizer hint syntax error near '%s'", strings.TrimSpace(p.text[p.start:])),
+			}
+		}
+		hints = append(hints, hint)
+	}
+}
+
+// hintParser is a recursive descent parser over the text of an optimizer hint comment.
+type hintParser struct {
+	text string
+	pos  int
+	// start is the position of the hint being parsed
+	start int
+}
+
+type hintSyntaxError struct{}
+
+func (hintSyntaxError) Error() string {
+	return "optimizer hint syntax error"
+}
+
+func (p *hintParser) done() bool {
+	return p.pos >= len(p.text)
+}
+
+func (p *hintParser) skipSpace() {
+	for !p.done() && unicode.IsSpace(rune(p.text[p.pos])) {
+		p.pos++
+	}
+}
+
+// peek returns the next character after any whitespace, or 0 at the end of the text.
+func (p *hintParser) peek() byte {
+	p.skipSpace()
+	if p.done() {
+		return 0
+	}
+	return p.text[p.pos]
+}
+
+func (p *hintParser) expect(c byte) error {
+	if p.peek() != c {
+		return hintSyntaxError{}
+	}
+	p.pos++
+	return nil
+}
+
+func isHintIdentChar(c byte) bool {
+	return c == '_' || c == '$' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80
+}
+
+// ident parses a plain or backquoted identifier.
+func (p *hintParser) ident() (string, error) {
+	c := p.peek()
+	if c == '`' {
+		end := strings.IndexByte(p.text[p.pos+1:], '`')
+		if end < 0 {
+			return "", hintSyntaxError{}
+		}
+		ident := p.text[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+		return ident, nil
+	}
+	start := p.pos
+	for !p.done() && isHintIdentChar(p.text[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		return "", hintSyntaxError{}
+	}
+	return p.text[start:p.pos], nil
+}
+
+// queryBlock parses an optional @name query block reference.
+func (p *hintParser) queryBlock() (string, error) {
+	if p.peek() != '@' {
+		return "", nil
+	}
+	p.pos++
+	return p.ident()
+}
+
+func (p *hintParser) table() (HintTable, error) {
+	name, err := p.ident()
+	if err != nil {
+		return HintTable{}, err
+	}
+	qb, err := p.queryBlock()
+	if err != nil {
+		return HintTable{}, err
+	}
+	return HintTable{Name: name, QueryBlock: qb}, nil
+}
+
+func (p *hintParser) hint() (*OptimizerHint, error) {
+	p.start = p.pos
+	name, err := p.ident()
+	if err != nil {
+		return nil, err
+	}
+	h := &OptimizerHint{Type: OptimizerHintType(strings.ToUpper(name))}
+	args, ok := optimizerHintArgs[h.Type]
+	if !ok {
+		return nil, hintSyntaxError{}
+	}
+	if err = p.expect('('); err != nil {
+		return nil, err
+	}
+
+	if args != hintArgsSetVar && args != hintArgsName && args != hintArgsMillis {
+		if h.QueryBlock, err = p.queryBlock(); err != nil {
+			return nil, err
+		}
+	}
+
+	switch args {
+	case hintArgsTables:
+		for p.peek() != ')' {
+			if len(h.Tables) > 0 {
+				if err = p.expect(','); err != nil {
+					return nil, err
+				}
+			}
+			t, err := p.table()
+			if err != nil {
+				return nil, err
+			}
+			h.Tables = append(h.Tables, t)
+		}
+	case hintArgsIndexes:
+		t, err := p.table()
+		if err != nil {
+			return nil, err
+		}
+		h.Tables = []HintTable{t}
+		for p.peek() != ')' {
+			if len(h.Indexes) > 0 {
+				if err = p.expect(','); err != nil {
+					return nil, err
+				}
+			}
+			idx, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			h.Indexes = append(h.Indexes, idx)
+		}
+	case hintArgsStrategies:
+		for p.peek() != ')' {
+			if len(h.Strategies) > 0 {
+				if err = p.expect(','); err != nil {
+					return nil, err
+				}
+			}
+			strategy, err := p.ident()
+			if err != nil {
+				return nil, err
+			}
+			strategy = strings.ToUpper(strategy)
+			if _, ok := semiJoinStrategies[strategy]; !ok {
+				return nil, hintSyntaxError{}
+			}
+			h.Strategies = append(h.Strategies, strategy)
+		}
+	case hintArgsSetVar:
+		if h.Name, err = p.ident(); err != nil {
+			return nil, err
+		}
+		if err = p.expect('='); err != nil {
+			return nil, err
+		}
+		if h.Value, err = p.value(); err != nil {
+			return nil, err
+		}
+	case hintArgsName:
+		if h.Name, err = p.ident(); err != nil {
+			return nil, err
+		}
+	case hintArgsMillis:
+		p.skipSpace()
+		start := p.pos
+		for !p.done() && p.text[p.pos] >= '0' && p.text[p.pos] <= '9' {
+			p.pos++
+		}
+		if h.Millis, err = strconv.ParseUint(p.text[start:p.pos], 10, 64); err != nil {
+			return nil, hintSyntaxError{}
+		}
+	}
+
+	if err = p.expect(')'); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// value parses the value of a SET_VAR hint: a number, a quoted string or a bare word such as ON.
+func (p *hintParser) value() (interface{}, error) {
+	c := p.peek()
+	if c == '\'' || c == '"' {
+		var sb strings.Builder
+		p.pos++
+		for !p.done() {
+			if p.text[p.pos] == c {
+				if p.pos+1 < len(p.text) && p.text[p.pos+1] == c {
+					sb.WriteByte(c)
+					p.pos += 2
+					continue
+				}
+				p.pos++
+				return sb.String(), nil
+			}
+			sb.WriteByte(p.text[p.pos])
+			p.pos++
+		}
+		return nil, hintSyntaxError{}
+	}
+
+	start := p.pos
+	for !p.done() && (isHintIdentChar(p.text[p.pos]) || p.text[p.pos] == '.' || p.text[p.pos] == '-' || p.text[p.pos] == '+') {
+		p.pos++
+	}
+	word := p.text[start:p.pos]
+	if word == "" {
+		return nil, hintSyntaxError{}
+	}
+	if i, err := strconv.ParseInt(word, 10, 64); err == nil {
+		return i, nil
+	}
+	if f, err := strconv.ParseFloat(word, 64); err == nil {
+		return f, nil
+	}
+	return word, nil
+}
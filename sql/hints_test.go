@@ -0,0 +1,134 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptimizerHints(t *testing.T) {
+	tests := []struct {
+		comment  string
+		expected OptimizerHints
+		str      string
+		warning  string
+	}{
+		{
+			comment: "/*+ JOIN_ORDER(a,b) */",
+			expected: OptimizerHints{
+				{Type: HintJoinOrder, Tables: []HintTable{{Name: "a"}, {Name: "b"}}},
+			},
+			str: "/*+ JOIN_ORDER(a, b) */",
+		},
+		{
+			comment: "/*+ join_prefix(@qb1 t1@qb1) hash_join() NO_MERGE_JOIN(t2, t3) */",
+			expected: OptimizerHints{
+				{Type: HintJoinPrefix, QueryBlock: "qb1", Tables: []HintTable{{Name: "t1", QueryBlock: "qb1"}}},
+				{Type: HintHashJoin},
+				{Type: HintNoMergeJoin, Tables: []HintTable{{Name: "t2"}, {Name: "t3"}}},
+			},
+			str: "/*+ JOIN_PREFIX(@qb1 t1@qb1) HASH_JOIN() NO_MERGE_JOIN(t2, t3) */",
+		},
+		{
+			comment: "/*+ NO_INDEX(t idx1, idx2) INDEX(`u` PRIMARY) NO_RANGE_OPTIMIZATION(t) */",
+			expected: OptimizerHints{
+				{Type: HintNoIndex, Tables: []HintTable{{Name: "t"}}, Indexes: []string{"idx1", "idx2"}},
+				{Type: HintIndex, Tables: []HintTable{{Name: "u"}}, Indexes: []string{"PRIMARY"}},
+				{Type: HintNoRangeOptimization, Tables: []HintTable{{Name: "t"}}},
+			},
+			str: "/*+ NO_INDEX(t idx1, idx2) INDEX(u PRIMARY) NO_RANGE_OPTIMIZATION(t) */",
+		},
+		{
+			comment: "/*+ QB_NAME(sq) SEMIJOIN(@sq firstmatch, LOOSESCAN) NO_SEMIJOIN() */",
+			expected: OptimizerHints{
+				{Type: HintQbName, Name: "sq"},
+				{Type: HintSemiJoin, QueryBlock: "sq", Strategies: []string{"FIRSTMATCH", "LOOSESCAN"}},
+				{Type: HintNoSemiJoin},
+			},
+			str: "/*+ QB_NAME(sq) SEMIJOIN(@sq FIRSTMATCH, LOOSESCAN) NO_SEMIJOIN() */",
+		},
+		{
+			comment: "/*+ SET_VAR(sort_buffer_size = 16384) SET_VAR(sql_mode='ANSI''') SET_VAR(autocommit=ON) MAX_EXECUTION_TIME(100) */",
+			expected: OptimizerHints{
+				{Type: HintSetVar, Name: "sort_buffer_size", Value: int64(16384)},
+				{Type: HintSetVar, Name: "sql_mode", Value: "ANSI'"},
+				{Type: HintSetVar, Name: "autocommit", Value: "ON"},
+				{Type: HintMaxExecutionTime, Millis: 100},
+			},
+			str: "/*+ SET_VAR(sort_buffer_size = 16384) SET_VAR(sql_mode = 'ANSI''') SET_VAR(autocommit = 'ON') MAX_EXECUTION_TIME(100) */",
+		},
+		{
+			comment: "/*+ HASH_JOIN(t1) BOGUS(t2) NO_INDEX(t3) */",
+			expected: OptimizerHints{
+				{Type: HintHashJoin, Tables: []HintTable{{Name: "t1"}}},
+			},
+			str:     "/*+ HASH_JOIN(t1) */",
+			warning: "Optimizer hint syntax error near 'BOGUS(t2) NO_INDEX(t3)'",
+		},
+		{
+			comment: "/*+ SEMIJOIN(NOPE) */",
+			str:     "",
+			warning: "Optimizer hint syntax error near 'SEMIJOIN(NOPE)'",
+		},
+		{
+			comment: "/*+ JOIN_ORDER(a, */",
+			str:     "",
+			warning: "Optimizer hint syntax error near 'JOIN_ORDER(a,'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.comment, func(t *testing.T) {
+			hints, warning := ParseOptimizerHints(tt.comment)
+			require.Equal(t, tt.expected, hints)
+			require.Equal(t, tt.str, hints.String())
+			if tt.warning == "" {
+				require.Nil(t, warning)
+				// The canonical form parses back to the same hints
+				reparsed, warning := ParseOptimizerHints(hints.String())
+				require.Nil(t, warning)
+				require.Equal(t, hints, reparsed)
+			} else {
+				require.NotNil(t, warning)
+				require.Equal(t, mysql.ERParseError, warning.Code)
+				require.Equal(t, tt.warning, warning.Message)
+			}
+		})
+	}
+}
+
+func TestOptimizerHintsWithoutConflicts(t *testing.T) {
+	ctx := NewEmptyContext()
+	hints, warning := ParseOptimizerHints("/*+ JOIN_ORDER(a, b) JOIN_PREFIX(b) NO_INDEX(a) JOIN_INDEX(a) ORDER_INDEX(b) HASH_JOIN(a) NO_HASH_JOIN(b) HASH_JOIN(a) */")
+	require.Nil(t, warning)
+
+	hints = hints.WithoutConflicts(ctx)
+	require.Equal(t, "/*+ JOIN_ORDER(a, b) NO_INDEX(a) ORDER_INDEX(b) HASH_JOIN(a) NO_HASH_JOIN(b) */", hints.String())
+
+	var messages []string
+	for _, w := range ctx.Warnings() {
+		require.Equal(t, ERWarnConflictingHint, w.Code)
+		messages = append(messages, w.Message)
+	}
+	// Warnings are listed newest first
+	require.Equal(t, []string{
+		"Hint HASH_JOIN(a) is ignored as conflicting/duplicated",
+		"Hint JOIN_INDEX(a) is ignored as conflicting/duplicated",
+		"Hint JOIN_PREFIX(b) is ignored as conflicting/duplicated",
+	}, messages)
+}
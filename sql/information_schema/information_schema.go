@@ -459,15 +459,16 @@ var processListSchema = Schema{
 	{Name: "TIME", Type: types.Int32, Default: nil, Nullable: false, Source: ProcessListTableName},
 	{Name: "STATE", Type: types.MustCreateString(sqltypes.VarChar, 64, Collation_Information_Schema_Default), Default: nil, Nullable: true, Source: ProcessListTableName},
 	{Name: "INFO", Type: types.MustCreateString(sqltypes.VarChar, 65535, Collation_Information_Schema_Default), Default: nil, Nullable: true, Source: ProcessListTableName},
+	{Name: "MEMORY_USED", Type: types.Uint64, Default: nil, Nullable: false, Source: ProcessListTableName},
 }
 
 var profilingSchema = Schema{
 	{Name: "QUERY_ID", Type: types.Int32, Default: nil, Nullable: false, Source: ProfilingTableName},
 	{Name: "SEQ", Type: types.Int32, Default: nil, Nullable: false, Source: ProfilingTableName},
 	{Name: "STATE", Type: types.MustCreateString(sqltypes.VarChar, 30, Collation_Information_Schema_Default), Default: nil, Nullable: false, Source: ProfilingTableName},
-	{Name: "DURATION", Type: types.MustCreateDecimalType(types.DecimalTypeMaxPrecision, 0), Default: nil, Nullable: false, Source: ProfilingTableName},
-	{Name: "CPU_USER", Type: types.MustCreateDecimalType(types.DecimalTypeMaxPrecision, 0), Default: nil, Nullable: true, Source: ProfilingTableName},
-	{Name: "CPU_SYSTEM", Type: types.MustCreateDecimalType(types.DecimalTypeMaxPrecision, 0), Default: nil, Nullable: true, Source: ProfilingTableName},
+	{Name: "DURATION", Type: plan.ProfileDurationType, Default: nil, Nullable: false, Source: ProfilingTableName},
+	{Name: "CPU_USER", Type: plan.ProfileDurationType, Default: nil, Nullable: true, Source: ProfilingTableName},
+	{Name: "CPU_SYSTEM", Type: plan.ProfileDurationType, Default: nil, Nullable: true, Source: ProfilingTableName},
 	{Name: "CONTEXT_VOLUNTARY", Type: types.Int32, Default: nil, Nullable: true, Source: ProfilingTableName},
 	{Name: "CONTEXT_INVOLUNTARY", Type: types.Int32, Default: nil, Nullable: true, Source: ProfilingTableName},
 	{Name: "BLOCK_OPS_IN", Type: types.Int32, Default: nil, Nullable: true, Source: ProfilingTableName},
@@ -768,8 +769,8 @@ var viewRoutineUsageSchema = Schema{
 	{Name: "TABLE_SCHEMA", Type: types.MustCreateString(sqltypes.VarChar, 64, Collation_Information_Schema_Default), Default: nil, Nullable: true, Source: ViewRoutineUsageTableName},
 	{Name: "TABLE_NAME", Type: types.MustCreateString(sqltypes.VarChar, 64, Collation_Information_Schema_Default), Default: nil, Nullable: true, Source: ViewRoutineUsageTableName},
 	{Name: "SPECIFIC_CATALOG", Type: types.MustCreateString(sqltypes.VarChar, 64, Collation_Information_Schema_Default), Default: nil, Nullable: true, Source: ViewRoutineUsageTableName},
-	{Name: "SPECIFIC_CATALOG", Type: types.MustCreateString(sqltypes.VarChar, 64, Collation_Information_Schema_Default), Default: nil, Nullable: true, Source: ViewRoutineUsageTableName},
-	{Name: "SPECIFIC_TABLE", Type: types.MustCreateString(sqltypes.VarChar, 64, Collation_Information_Schema_Default), Default: nil, Nullable: false, Source: ViewRoutineUsageTableName},
+	{Name: "SPECIFIC_SCHEMA", Type: types.MustCreateString(sqltypes.VarChar, 64, Collation_Information_Schema_Default), Default: nil, Nullable: true, Source: ViewRoutineUsageTableName},
+	{Name: "SPECIFIC_NAME", Type: types.MustCreateString(sqltypes.VarChar, 64, Collation_Information_Schema_Default), Default: nil, Nullable: false, Source: ViewRoutineUsageTableName},
 }
 
 var viewTableUsageSchema = Schema{
@@ -979,6 +980,105 @@ func enginesRowIter(ctx *Context, cat Catalog) (RowIter, error) {
 	return RowsToRowIter(rows...), nil
 }
 
+// eventsRowIter implements the sql.RowIter for the information_schema.EVENTS table.
+func eventsRowIter(ctx *Context, c Catalog) (RowIter, error) {
+	var rows []Row
+	characterSetClient, err := ctx.GetSessionVariable(ctx, "character_set_client")
+	if err != nil {
+		return nil, err
+	}
+	collationConnection, err := ctx.GetSessionVariable(ctx, "collation_connection")
+	if err != nil {
+		return nil, err
+	}
+	sysVal, err := ctx.Session.GetSessionVariable(ctx, "sql_mode")
+	if err != nil {
+		return nil, err
+	}
+	sqlMode, sok := sysVal.(string)
+	if !sok {
+		return nil, ErrSystemVariableCodeFail.New("sql_mode", sysVal)
+	}
+	// The privilege set is only loaded when the grant tables are enabled, in which case every event is visible
+	privSet, _ := ctx.GetPrivilegeSet()
+	hasGlobalEventPriv := privSet == nil || privSet.Has(PrivilegeType_Event)
+	for _, db := range c.AllDatabases(ctx) {
+		eventDb, ok := db.(EventDatabase)
+		if !ok {
+			continue
+		}
+		// To see information about a database's events, you must have the EVENT privilege for the database.
+		if !hasGlobalEventPriv && !privSet.Database(db.Name()).Has(PrivilegeType_Event) {
+			continue
+		}
+		events, err := eventDb.GetEvents(ctx)
+		if err != nil {
+			if ErrEventsNotSupported.Is(err) {
+				continue
+			}
+			return nil, err
+		}
+		dbCollation := plan.GetDatabaseCollation(ctx, db)
+		for _, event := range events {
+			parsedEvent, err := parse.Parse(ctx, event.CreateStatement)
+			if err != nil {
+				return nil, err
+			}
+			details, err := plan.LoadEventDetails(ctx, event, parsedEvent)
+			if err != nil {
+				return nil, err
+			}
+
+			var executeAt, intervalValue, intervalField, starts, ends, lastExecuted interface{}
+			if details.HasExecuteAt {
+				executeAt = details.ExecuteAt
+			} else {
+				intervalValue = details.ExecuteEvery.Value
+				intervalField = details.ExecuteEvery.Unit
+				starts = details.Starts
+				if details.HasEnds {
+					ends = details.Ends
+				}
+			}
+			if !details.LastExecuted.IsZero() {
+				lastExecuted = details.LastExecuted
+			}
+			onCompletion := "NOT PRESERVE"
+			if details.OnCompletionPreserve {
+				onCompletion = "PRESERVE"
+			}
+
+			rows = append(rows, Row{
+				"def",                                    // event_catalog
+				eventDb.Name(),                           // event_schema
+				details.Name,                             // event_name
+				removeBackticks(details.Definer),         // definer
+				"SYSTEM",                                 // time_zone
+				"SQL",                                    // event_body
+				details.Body,                             // event_definition
+				details.EventType(),                      // event_type
+				executeAt,                                // execute_at
+				intervalValue,                            // interval_value
+				intervalField,                            // interval_field
+				sqlMode,                                  // sql_mode
+				starts,                                   // starts
+				ends,                                     // ends
+				details.Status.InformationSchemaString(), // status
+				onCompletion,                             // on_completion
+				details.CreatedAt,                        // created
+				details.LastAltered,                      // last_altered
+				lastExecuted,                             // last_executed
+				details.Comment,                          // event_comment
+				uint32(0),                                // originator
+				characterSetClient,                       // character_set_client
+				collationConnection,                      // collation_connection
+				dbCollation.String(),                     // database_collation
+			})
+		}
+	}
+	return RowsToRowIter(rows...), nil
+}
+
 // keyColumnUsageRowIter implements the sql.RowIter for the information_schema.KEY_COLUMN_USAGE table.
 func keyColumnUsageRowIter(ctx *Context, c Catalog) (RowIter, error) {
 	var rows []Row
@@ -1059,6 +1159,61 @@ func keywordsRowIter(ctx *Context, cat Catalog) (RowIter, error) {
 	return RowsToRowIter(rows...), nil
 }
 
+// optimizerTraceRowIter implements the sql.RowIter for the information_schema.OPTIMIZER_TRACE table.
+func optimizerTraceRowIter(ctx *Context, c Catalog) (RowIter, error) {
+	offset, limit, err := OptimizerTraceWindow(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var rows []Row
+	for _, trace := range VisibleOptimizerTraces(ctx.Session.GetOptimizerTraces(), offset, limit) {
+		missingBytes := int32(trace.MissingBytesBeyondMaxMemSize)
+		rows = append(rows, Row{
+			trace.Query,  // query
+			trace.Trace,  // trace
+			missingBytes, // missing_bytes_beyond_max_mem_size
+			uint64(0),    // insufficient_privileges
+		})
+	}
+
+	return RowsToRowIter(rows...), nil
+}
+
+// profilingRowIter implements the sql.RowIter for the information_schema.PROFILING table.
+func profilingRowIter(ctx *Context, c Catalog) (RowIter, error) {
+	var rows []Row
+	for _, profile := range ctx.Session.GetQueryProfiles() {
+		for i, stage := range profile.Stages {
+			var cpuUser, cpuSystem interface{}
+			if HasProcessCPUTime {
+				cpuUser, cpuSystem = plan.ProfileDuration(stage.CPUUser), plan.ProfileDuration(stage.CPUSystem)
+			}
+			rows = append(rows, Row{
+				int32(profile.QueryId),               // query_id
+				int32(i + 1),                         // seq
+				stage.State,                          // state
+				plan.ProfileDuration(stage.Duration), // duration
+				cpuUser,                              // cpu_user
+				cpuSystem,                            // cpu_system
+				nil,                                  // context_voluntary
+				nil,                                  // context_involuntary
+				nil,                                  // block_ops_in
+				nil,                                  // block_ops_out
+				nil,                                  // messages_sent
+				nil,                                  // messages_received
+				nil,                                  // page_faults_major
+				nil,                                  // page_faults_minor
+				nil,                                  // swaps
+				nil,                                  // source_function
+				nil,                                  // source_file
+				nil,                                  // source_line
+			})
+		}
+	}
+
+	return RowsToRowIter(rows...), nil
+}
+
 // processListRowIter implements the sql.RowIter for the information_schema.PROCESSLIST table.
 func processListRowIter(ctx *Context, c Catalog) (RowIter, error) {
 	processes := ctx.ProcessList.Processes()
@@ -1074,19 +1229,22 @@ func processListRowIter(ctx *Context, c Catalog) (RowIter, error) {
 		for name, progress := range proc.Progress {
 			status = append(status, fmt.Sprintf("%s(%s)", name, progress))
 		}
-		if len(status) == 0 {
+		if proc.State != "" {
+			status = []string{proc.State}
+		} else if len(status) == 0 {
 			status = []string{"running"}
 		}
 		sort.Strings(status)
 		rows[i] = Row{
-			uint64(proc.Connection),      // id
-			proc.User,                    // user
-			ctx.Session.Client().Address, // host
-			db,                           // db
-			"Query",                      // command
-			int32(proc.Seconds()),        // time
-			strings.Join(status, ", "),   // state
-			proc.Query,                   // info
+			uint64(proc.Connection),    // id
+			proc.User,                  // user
+			proc.Host,                  // host
+			db,                         // db
+			"Query",                    // command
+			int32(proc.Seconds()),      // time
+			strings.Join(status, ", "), // state
+			proc.Query,                 // info
+			proc.MemoryUsed(),          // memory_used
 		}
 	}
 
@@ -1580,6 +1738,65 @@ func tableConstraintsExtensionsRowIter(ctx *Context, c Catalog) (RowIter, error)
 	return RowsToRowIter(rows...), nil
 }
 
+// columnPrivilegesRowIter implements the sql.RowIter for the information_schema.COLUMN_PRIVILEGES table.
+func columnPrivilegesRowIter(ctx *Context, c Catalog) (RowIter, error) {
+	var rows []Row
+	privSet, _ := ctx.GetPrivilegeSet()
+	if privSet.Has(PrivilegeType_Select) || privSet.Database("mysql").Has(PrivilegeType_Select) {
+		var users = make(map[*mysql_db.User]struct{})
+		db, err := c.Database(ctx, "mysql")
+		if err != nil {
+			return nil, err
+		}
+
+		mysqlDb, ok := db.(*mysql_db.MySQLDb)
+		if !ok {
+			return nil, ErrDatabaseNotFound.New("mysql")
+		}
+		colsPriv, _, err := mysqlDb.GetTableInsensitive(ctx, "columns_priv")
+		if err != nil {
+			return nil, err
+		}
+		ri, err := colsPriv.PartitionRows(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		for {
+			r, rerr := ri.Next(ctx)
+			if rerr == io.EOF {
+				break
+			}
+			// mysql.columns_priv table will have 'Host', 'Db', 'User' as first 3 columns in string format.
+			users[mysqlDb.GetUser(r[2].(string), r[0].(string), false)] = struct{}{}
+		}
+
+		for user := range users {
+			grantee := user.UserHostToString("'")
+			for _, privSetDb := range user.PrivilegeSet.GetDatabases() {
+				dbName := privSetDb.Name()
+				for _, privSetTbl := range privSetDb.GetTables() {
+					rows = append(rows, getColumnPrivsRowsFromPrivTblSet(privSetTbl, grantee, dbName)...)
+				}
+			}
+		}
+	} else {
+		// If current client does not have SELECT privilege on 'mysql' db, only available column privileges are
+		// their current column privileges.
+		currClient := ctx.Session.Client()
+		grantee := fmt.Sprintf("'%s'@'%s'", currClient.User, currClient.Address)
+		dbs := c.AllDatabases(ctx)
+		for _, db := range dbs {
+			dbName := db.Name()
+			privSetDb := privSet.Database(dbName)
+			for _, privSetTbl := range privSetDb.GetTables() {
+				rows = append(rows, getColumnPrivsRowsFromPrivTblSet(privSetTbl, grantee, dbName)...)
+			}
+		}
+	}
+
+	return RowsToRowIter(rows...), nil
+}
+
 // tablePrivilegesRowIter implements the sql.RowIter for the information_schema.TABLE_PRIVILEGES table.
 func tablePrivilegesRowIter(ctx *Context, c Catalog) (RowIter, error) {
 	var rows []Row
@@ -2077,6 +2294,289 @@ func viewsRowIter(ctx *Context, catalog Catalog) (RowIter, error) {
 }
 
 // emptyRowIter implements the sql.RowIter for empty table.
+// partitionsRowIter implements the sql.RowIter for the information_schema.PARTITIONS table.
+func partitionsRowIter(ctx *Context, c Catalog) (RowIter, error) {
+	var rows []Row
+	y2k, _ := types.Timestamp.Convert("2000-01-01 00:00:00")
+	for _, db := range c.AllDatabases(ctx) {
+		if db.Name() == InformationSchemaDatabaseName {
+			continue
+		}
+		err := DBTableIter(ctx, db, func(t Table) (cont bool, err error) {
+			var scheme *PartitionScheme
+			pt, ok := t.(PartitionedTable)
+			if ok {
+				scheme, err = pt.PartitionScheme(ctx)
+				if err != nil {
+					return false, err
+				}
+			}
+
+			tableRows, dataLength, err := tableDataLength(ctx, t)
+			if err != nil {
+				return false, err
+			}
+			var avgRowLength uint64
+			if tableRows > 0 {
+				avgRowLength = dataLength / tableRows
+			}
+
+			// A table that isn't partitioned has a single row, which describes the whole table
+			if scheme == nil {
+				rows = append(rows, Row{
+					"def",        // table_catalog
+					db.Name(),    // table_schema
+					t.Name(),     // table_name
+					nil,          // partition_name
+					nil,          // subpartition_name
+					nil,          // partition_ordinal_position
+					nil,          // subpartition_ordinal_position
+					nil,          // partition_method
+					nil,          // subpartition_method
+					nil,          // partition_expression
+					nil,          // subpartition_expression
+					nil,          // partition_description
+					tableRows,    // table_rows
+					avgRowLength, // avg_row_length
+					dataLength,   // data_length
+					nil,          // max_data_length
+					uint64(0),    // index_length
+					uint64(0),    // data_free
+					y2k,          // create_time
+					nil,          // update_time
+					nil,          // check_time
+					nil,          // checksum
+					"",           // partition_comment
+					nil,          // nodegroup
+					nil,          // tablespace_name
+				})
+				return true, nil
+			}
+
+			partitionExpression := scheme.ExpressionString
+			if scheme.Method.UsesColumns() {
+				quoted := make([]string, len(scheme.Columns))
+				for i, col := range scheme.Columns {
+					quoted[i] = fmt.Sprintf("`%s`", col)
+				}
+				partitionExpression = strings.Join(quoted, ",")
+			}
+
+			for i, def := range scheme.Definitions {
+				var description interface{}
+				if !scheme.Method.IsHash() {
+					description, err = scheme.ValuesDescription(ctx, t.Schema(), i)
+					if err != nil {
+						return false, err
+					}
+				}
+				partitionRows, err := countPartitionRows(ctx, pt, def.Name)
+				if err != nil {
+					return false, err
+				}
+
+				// The length of the data of a partition is estimated from the average length of the rows of the table
+				rows = append(rows, Row{
+					"def",                        // table_catalog
+					db.Name(),                    // table_schema
+					t.Name(),                     // table_name
+					def.Name,                     // partition_name
+					nil,                          // subpartition_name
+					uint32(i + 1),                // partition_ordinal_position
+					nil,                          // subpartition_ordinal_position
+					string(scheme.Method),        // partition_method
+					nil,                          // subpartition_method
+					partitionExpression,          // partition_expression
+					nil,                          // subpartition_expression
+					description,                  // partition_description
+					partitionRows,                // table_rows
+					avgRowLength,                 // avg_row_length
+					avgRowLength * partitionRows, // data_length
+					nil,                          // max_data_length
+					uint64(0),                    // index_length
+					uint64(0),                    // data_free
+					y2k,                          // create_time
+					nil,                          // update_time
+					nil,                          // check_time
+					nil,                          // checksum
+					def.Comment,                  // partition_comment
+					"default",                    // nodegroup
+					nil,                          // tablespace_name
+				})
+			}
+			return true, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return RowsToRowIter(rows...), nil
+}
+
+// tableDataLength returns the number of rows of the table given and the length of its data, which are only known for
+// the tables that implement StatisticsTable.
+func tableDataLength(ctx *Context, t Table) (rowCount uint64, dataLength uint64, err error) {
+	st, ok := t.(StatisticsTable)
+	if !ok {
+		return 0, 0, nil
+	}
+	rowCount, err = st.RowCount(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	dataLength, err = st.DataLength(ctx)
+	if err != nil {
+		return 0, 0, err
+	}
+	return rowCount, dataLength, nil
+}
+
+// filesRowIter implements the sql.RowIter for the information_schema.FILES table. Files are reported by the catalog
+// when its database provider implements StorageUsageProvider, and are only shown to the users with the PROCESS
+// privilege.
+func filesRowIter(ctx *Context, c Catalog) (RowIter, error) {
+	var rows []Row
+	sup, ok := c.(StorageUsageProvider)
+	if !ok {
+		return RowsToRowIter(rows...), nil
+	}
+	privSet, _ := ctx.GetPrivilegeSet()
+	if privSet == nil || !privSet.Has(PrivilegeType_Process) {
+		return RowsToRowIter(rows...), nil
+	}
+
+	files, err := sup.DataFiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range files {
+		var maximumSize interface{}
+		if file.MaximumSize > 0 {
+			maximumSize = file.MaximumSize
+		}
+		rows = append(rows, Row{
+			file.Id,             // file_id
+			file.Name,           // file_name
+			file.Type,           // file_type
+			file.TablespaceName, // tablespace_name
+			"",                  // table_catalog
+			nil,                 // table_schema
+			nil,                 // table_name
+			nil,                 // logfile_group_name
+			nil,                 // logfile_group_number
+			file.Engine,         // engine
+			nil,                 // fulltext_keys
+			nil,                 // deleted_rows
+			nil,                 // update_count
+			file.FreeExtents,    // free_extents
+			file.TotalExtents,   // total_extents
+			file.ExtentSize,     // extent_size
+			file.InitialSize,    // initial_size
+			maximumSize,         // maximum_size
+			file.AutoextendSize, // autoextend_size
+			nil,                 // creation_time
+			nil,                 // last_update_time
+			nil,                 // last_access_time
+			nil,                 // recover_time
+			nil,                 // transaction_counter
+			nil,                 // version
+			nil,                 // row_format
+			nil,                 // table_rows
+			nil,                 // avg_row_length
+			nil,                 // data_length
+			nil,                 // max_data_length
+			nil,                 // index_length
+			file.DataFree,       // data_free
+			nil,                 // create_time
+			nil,                 // update_time
+			nil,                 // check_time
+			nil,                 // checksum
+			file.Status,         // status
+			nil,                 // extra
+		})
+	}
+	return RowsToRowIter(rows...), nil
+}
+
+// tablespacesRowIter implements the sql.RowIter for the information_schema.TABLESPACES table. Tablespaces are
+// reported by the catalog when its database provider implements StorageUsageProvider.
+func tablespacesRowIter(ctx *Context, c Catalog) (RowIter, error) {
+	var rows []Row
+	sup, ok := c.(StorageUsageProvider)
+	if !ok {
+		return RowsToRowIter(rows...), nil
+	}
+
+	tablespaces, err := sup.Tablespaces(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, tablespace := range tablespaces {
+		var tablespaceType, maximumSize, comment interface{}
+		if tablespace.Type != "" {
+			tablespaceType = tablespace.Type
+		}
+		if tablespace.MaximumSize > 0 {
+			maximumSize = tablespace.MaximumSize
+		}
+		if tablespace.Comment != "" {
+			comment = tablespace.Comment
+		}
+		rows = append(rows, Row{
+			tablespace.Name,           // tablespace_name
+			tablespace.Engine,         // engine
+			tablespaceType,            // tablespace_type
+			nil,                       // logfile_group_name
+			tablespace.ExtentSize,     // extent_size
+			tablespace.AutoextendSize, // autoextend_size
+			maximumSize,               // maximum_size
+			nil,                       // nodegroup_id
+			comment,                   // tablespace_comment
+		})
+	}
+	return RowsToRowIter(rows...), nil
+}
+
+// countPartitionRows returns the number of rows in the user-defined partition of the table given.
+func countPartitionRows(ctx *Context, t PartitionedTable, name string) (uint64, error) {
+	selected, err := t.WithSelectedPartitions([]string{name})
+	if err != nil {
+		return 0, err
+	}
+	partitions, err := selected.Partitions(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer partitions.Close(ctx)
+
+	var count uint64
+	for {
+		partition, err := partitions.Next(ctx)
+		if err == io.EOF {
+			return count, nil
+		} else if err != nil {
+			return 0, err
+		}
+		iter, err := selected.PartitionRows(ctx, partition)
+		if err != nil {
+			return 0, err
+		}
+		for {
+			_, err := iter.Next(ctx)
+			if err == io.EOF {
+				break
+			} else if err != nil {
+				iter.Close(ctx)
+				return 0, err
+			}
+			count++
+		}
+		if err := iter.Close(ctx); err != nil {
+			return 0, err
+		}
+	}
+}
+
 func emptyRowIter(ctx *Context, c Catalog) (RowIter, error) {
 	return RowsToRowIter(), nil
 }
@@ -2095,12 +2595,12 @@ func NewInformationSchemaDatabase() Database {
 			AdministrableRoleAuthorizationsTableName: &informationSchemaTable{
 				name:   AdministrableRoleAuthorizationsTableName,
 				schema: administrableRoleAuthorizationsSchema,
-				reader: emptyRowIter,
+				reader: administrableRoleAuthorizationsRowIter,
 			},
 			ApplicableRolesTableName: &informationSchemaTable{
 				name:   ApplicableRolesTableName,
 				schema: applicableRolesSchema,
-				reader: emptyRowIter,
+				reader: applicableRolesRowIter,
 			},
 			CharacterSetsTableName: &informationSchemaTable{
 				name:   CharacterSetsTableName,
@@ -2125,7 +2625,7 @@ func NewInformationSchemaDatabase() Database {
 			ColumnPrivilegesTableName: &informationSchemaTable{
 				name:   ColumnPrivilegesTableName,
 				schema: columnPrivilegesSchema,
-				reader: emptyRowIter,
+				reader: columnPrivilegesRowIter,
 			},
 			ColumnStatisticsTableName: &informationSchemaTable{
 				name:   ColumnStatisticsTableName,
@@ -2145,7 +2645,7 @@ func NewInformationSchemaDatabase() Database {
 			EnabledRolesTablesName: &informationSchemaTable{
 				name:   EnabledRolesTablesName,
 				schema: enabledRolesSchema,
-				reader: emptyRowIter,
+				reader: enabledRolesRowIter,
 			},
 			EnginesTableName: &informationSchemaTable{
 				name:   EnginesTableName,
@@ -2155,12 +2655,12 @@ func NewInformationSchemaDatabase() Database {
 			EventsTableName: &informationSchemaTable{
 				name:   EventsTableName,
 				schema: eventsSchema,
-				reader: emptyRowIter,
+				reader: eventsRowIter,
 			},
 			FilesTableName: &informationSchemaTable{
 				name:   FilesTableName,
 				schema: filesSchema,
-				reader: emptyRowIter,
+				reader: filesRowIter,
 			},
 			KeyColumnUsageTableName: &informationSchemaTable{
 				name:   KeyColumnUsageTableName,
@@ -2175,7 +2675,7 @@ func NewInformationSchemaDatabase() Database {
 			OptimizerTraceTableName: &informationSchemaTable{
 				name:   OptimizerTraceTableName,
 				schema: optimizerTraceSchema,
-				reader: emptyRowIter,
+				reader: optimizerTraceRowIter,
 			},
 			ParametersTableName: &routineTable{
 				name:    ParametersTableName,
@@ -2185,7 +2685,7 @@ func NewInformationSchemaDatabase() Database {
 			PartitionsTableName: &informationSchemaTable{
 				name:   PartitionsTableName,
 				schema: partitionsSchema,
-				reader: emptyRowIter,
+				reader: partitionsRowIter,
 			},
 			PluginsTableName: &informationSchemaTable{
 				name:   PluginsTableName,
@@ -2200,7 +2700,7 @@ func NewInformationSchemaDatabase() Database {
 			ProfilingTableName: &informationSchemaTable{
 				name:   ProfilingTableName,
 				schema: profilingSchema,
-				reader: emptyRowIter,
+				reader: profilingRowIter,
 			},
 			ReferentialConstraintsTableName: &informationSchemaTable{
 				name:   ReferentialConstraintsTableName,
@@ -2215,17 +2715,17 @@ func NewInformationSchemaDatabase() Database {
 			RoleColumnGrantsTableName: &informationSchemaTable{
 				name:   RoleColumnGrantsTableName,
 				schema: roleColumnGrantsSchema,
-				reader: emptyRowIter,
+				reader: roleColumnGrantsRowIter,
 			},
 			RoleRoutineGrantsTableName: &informationSchemaTable{
 				name:   RoleRoutineGrantsTableName,
 				schema: roleRoutineGrantsSchema,
-				reader: emptyRowIter,
+				reader: roleRoutineGrantsRowIter,
 			},
 			RoleTableGrantsTableName: &informationSchemaTable{
 				name:   RoleTableGrantsTableName,
 				schema: roleTableGrantsSchema,
-				reader: emptyRowIter,
+				reader: roleTableGrantsRowIter,
 			},
 			RoutinesTableName: &routineTable{
 				name:    RoutinesTableName,
@@ -2290,7 +2790,7 @@ func NewInformationSchemaDatabase() Database {
 			TablespacesTableName: &informationSchemaTable{
 				name:   TablespacesTableName,
 				schema: tablespacesSchema,
-				reader: emptyRowIter,
+				reader: tablespacesRowIter,
 			},
 			TablespacesExtensionsTableName: &informationSchemaTable{
 				name:   TablespacesExtensionsTableName,
@@ -2315,12 +2815,12 @@ func NewInformationSchemaDatabase() Database {
 			ViewRoutineUsageTableName: &informationSchemaTable{
 				name:   ViewRoutineUsageTableName,
 				schema: viewRoutineUsageSchema,
-				reader: emptyRowIter,
+				reader: viewRoutineUsageRowIter,
 			},
 			ViewTableUsageTableName: &informationSchemaTable{
 				name:   ViewTableUsageTableName,
 				schema: viewTableUsageSchema,
-				reader: emptyRowIter,
+				reader: viewTableUsageRowIter,
 			},
 			ViewsTableName: &informationSchemaTable{
 				name:   ViewsTableName,
@@ -2856,6 +3356,29 @@ func getSchemaPrivsRowsFromPrivDbSet(privSetDb PrivilegeSetDatabase, grantee str
 }
 
 // getTablePrivsRowsFromPrivTblSet returns TABLE_PRIVILEGES rows using given Table privilege set and grantee and database name strings.
+// getColumnPrivsRowsFromPrivTblSet returns the COLUMN_PRIVILEGES rows for every column of the given table.
+func getColumnPrivsRowsFromPrivTblSet(privSetTbl PrivilegeSetTable, grantee, dbName string) []Row {
+	var rows []Row
+	isGrantable := "NO"
+	if privSetTbl.Has(PrivilegeType_GrantOption) {
+		isGrantable = "YES"
+	}
+	for _, privSetCol := range privSetTbl.GetColumns() {
+		for _, privType := range privSetCol.ToSlice() {
+			rows = append(rows, Row{
+				grantee,           // grantee
+				"def",             // table_catalog
+				dbName,            // table_schema
+				privSetTbl.Name(), // table_name
+				privSetCol.Name(), // column_name
+				privType.String(), // privilege_type
+				isGrantable,       // is_grantable
+			})
+		}
+	}
+	return rows
+}
+
 func getTablePrivsRowsFromPrivTblSet(privSetTbl PrivilegeSetTable, grantee, dbName string) []Row {
 	var rows []Row
 	hasGrantOpt := privSetTbl.Has(PrivilegeType_GrantOption)
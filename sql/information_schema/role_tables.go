@@ -0,0 +1,283 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package information_schema
+
+import (
+	"sort"
+	"strings"
+
+	. "github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// grantTablesCatalog is implemented by catalogs that are able to return the grant tables without checking the
+// privileges of the current user. The role tables only describe the current user's own roles, which every user is
+// allowed to see, even when they have no access to the mysql database.
+type grantTablesCatalog interface {
+	GrantTables() *mysql_db.MySQLDb
+}
+
+// roleTablesUser returns the grant tables along with the current user. Returns a nil user if the grant tables are not
+// in use, or the current user does not exist.
+func roleTablesUser(ctx *Context, c Catalog) (*mysql_db.MySQLDb, *mysql_db.User, error) {
+	var mysqlDb *mysql_db.MySQLDb
+	if gtc, ok := c.(grantTablesCatalog); ok {
+		mysqlDb = gtc.GrantTables()
+	} else {
+		db, err := c.Database(ctx, "mysql")
+		if err != nil {
+			return nil, nil, err
+		}
+		var ok bool
+		if mysqlDb, ok = db.(*mysql_db.MySQLDb); !ok {
+			return nil, nil, ErrDatabaseNotFound.New("mysql")
+		}
+	}
+	if mysqlDb == nil || !mysqlDb.Enabled {
+		return mysqlDb, nil, nil
+	}
+	client := ctx.Session.Client()
+	return mysqlDb, mysqlDb.GetUser(client.User, client.Address, false), nil
+}
+
+// applicableRolesRowIter implements the sql.RowIter for the information_schema.APPLICABLE_ROLES table.
+func applicableRolesRowIter(ctx *Context, c Catalog) (RowIter, error) {
+	rows, err := applicableRoleRows(ctx, c, false)
+	if err != nil {
+		return nil, err
+	}
+	return RowsToRowIter(rows...), nil
+}
+
+// administrableRoleAuthorizationsRowIter implements the sql.RowIter for the
+// information_schema.ADMINISTRABLE_ROLE_AUTHORIZATIONS table.
+func administrableRoleAuthorizationsRowIter(ctx *Context, c Catalog) (RowIter, error) {
+	rows, err := applicableRoleRows(ctx, c, true)
+	if err != nil {
+		return nil, err
+	}
+	return RowsToRowIter(rows...), nil
+}
+
+// applicableRoleRows returns the rows for the roles that have been granted to the current user. When grantableOnly is
+// true, only the roles that were granted WITH ADMIN OPTION are returned.
+func applicableRoleRows(ctx *Context, c Catalog, grantableOnly bool) ([]Row, error) {
+	mysqlDb, user, err := roleTablesUser(ctx, c)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	var rows []Row
+	for _, roleEdge := range mysqlDb.GrantedRoles(user) {
+		if grantableOnly && !roleEdge.WithAdminOption {
+			continue
+		}
+		rows = append(rows, Row{
+			user.User,                         // user
+			user.Host,                         // host
+			user.User,                         // grantee
+			user.Host,                         // grantee_host
+			roleEdge.FromUser,                 // role_name
+			roleEdge.FromHost,                 // role_host
+			yesOrNo(roleEdge.WithAdminOption), // is_grantable
+			yesOrNo(mysqlDb.IsDefaultRole(user, roleEdge.FromUser, roleEdge.FromHost)),   // is_default
+			yesOrNo(mysql_db.IsMandatoryRole(ctx, roleEdge.FromUser, roleEdge.FromHost)), // is_mandatory
+		})
+	}
+	return rows, nil
+}
+
+// enabledRolesRowIter implements the sql.RowIter for the information_schema.ENABLED_ROLES table.
+func enabledRolesRowIter(ctx *Context, c Catalog) (RowIter, error) {
+	mysqlDb, user, err := roleTablesUser(ctx, c)
+	if err != nil || user == nil {
+		return RowsToRowIter(), err
+	}
+	var rows []Row
+	for _, role := range enabledRoles(ctx) {
+		rows = append(rows, Row{
+			role.Name, // role_name
+			role.Host, // role_host
+			yesOrNo(mysqlDb.IsDefaultRole(user, role.Name, role.Host)),   // is_default
+			yesOrNo(mysql_db.IsMandatoryRole(ctx, role.Name, role.Host)), // is_mandatory
+		})
+	}
+	return RowsToRowIter(rows...), nil
+}
+
+// roleTableGrantsRowIter implements the sql.RowIter for the information_schema.ROLE_TABLE_GRANTS table.
+func roleTableGrantsRowIter(ctx *Context, c Catalog) (RowIter, error) {
+	roles, err := enabledRoleUsers(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	var rows []Row
+	for _, role := range roles {
+		for _, privSetDb := range role.PrivilegeSet.GetDatabases() {
+			for _, privSetTbl := range privSetDb.GetTables() {
+				privileges, ok := privilegeSetString(roleTableGrantsSchema[7].Type, privSetTbl.ToSlice())
+				if !ok {
+					continue
+				}
+				rows = append(rows, Row{
+					"",                // grantor
+					"",                // grantor_host
+					role.User,         // grantee
+					role.Host,         // grantee_host
+					"def",             // table_catalog
+					privSetDb.Name(),  // table_schema
+					privSetTbl.Name(), // table_name
+					privileges,        // privilege_type
+					yesOrNo(privSetTbl.Has(PrivilegeType_GrantOption)), // is_grantable
+				})
+			}
+		}
+	}
+	return RowsToRowIter(rows...), nil
+}
+
+// roleColumnGrantsRowIter implements the sql.RowIter for the information_schema.ROLE_COLUMN_GRANTS table.
+func roleColumnGrantsRowIter(ctx *Context, c Catalog) (RowIter, error) {
+	roles, err := enabledRoleUsers(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	var rows []Row
+	for _, role := range roles {
+		for _, privSetDb := range role.PrivilegeSet.GetDatabases() {
+			for _, privSetTbl := range privSetDb.GetTables() {
+				for _, privSetCol := range privSetTbl.GetColumns() {
+					privileges, ok := privilegeSetString(roleColumnGrantsSchema[8].Type, privSetCol.ToSlice())
+					if !ok {
+						continue
+					}
+					rows = append(rows, Row{
+						"",                // grantor
+						"",                // grantor_host
+						role.User,         // grantee
+						role.Host,         // grantee_host
+						"def",             // table_catalog
+						privSetDb.Name(),  // table_schema
+						privSetTbl.Name(), // table_name
+						privSetCol.Name(), // column_name
+						privileges,        // privilege_type
+						yesOrNo(privSetTbl.Has(PrivilegeType_GrantOption)), // is_grantable
+					})
+				}
+			}
+		}
+	}
+	return RowsToRowIter(rows...), nil
+}
+
+// roleRoutineGrantsRowIter implements the sql.RowIter for the information_schema.ROLE_ROUTINE_GRANTS table.
+func roleRoutineGrantsRowIter(ctx *Context, c Catalog) (RowIter, error) {
+	roles, err := enabledRoleUsers(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	var rows []Row
+	for _, role := range roles {
+		for _, privSetDb := range role.PrivilegeSet.GetDatabases() {
+			for _, privSetRoutine := range privSetDb.GetRoutines() {
+				privileges, ok := privilegeSetString(roleRoutineGrantsSchema[10].Type, privSetRoutine.ToSlice())
+				if !ok {
+					continue
+				}
+				rows = append(rows, Row{
+					"",                    // grantor
+					"",                    // grantor_host
+					role.User,             // grantee
+					role.Host,             // grantee_host
+					"def",                 // specific_catalog
+					privSetDb.Name(),      // specific_schema
+					privSetRoutine.Name(), // specific_name
+					"def",                 // routine_catalog
+					privSetDb.Name(),      // routine_schema
+					privSetRoutine.Name(), // routine_name
+					privileges,            // privilege_type
+					yesOrNo(privSetRoutine.Has(PrivilegeType_GrantOption)), // is_grantable
+				})
+			}
+		}
+	}
+	return RowsToRowIter(rows...), nil
+}
+
+// enabledRoles returns the roles that are active in the session, sorted by name and host.
+func enabledRoles(ctx *Context) []ActiveRole {
+	activeRoles, _ := ctx.Session.GetActiveRoles()
+	roles := make([]ActiveRole, len(activeRoles))
+	copy(roles, activeRoles)
+	sort.Slice(roles, func(i, j int) bool {
+		if roles[i].Name == roles[j].Name {
+			return roles[i].Host < roles[j].Host
+		}
+		return roles[i].Name < roles[j].Name
+	})
+	return roles
+}
+
+// enabledRoleUsers returns the roles that are active in the session, for the current user.
+func enabledRoleUsers(ctx *Context, c Catalog) ([]*mysql_db.User, error) {
+	mysqlDb, user, err := roleTablesUser(ctx, c)
+	if err != nil || user == nil {
+		return nil, err
+	}
+	var roles []*mysql_db.User
+	for _, activeRole := range enabledRoles(ctx) {
+		if role := mysqlDb.GetUser(activeRole.Name, activeRole.Host, true); role != nil {
+			roles = append(roles, role)
+		}
+	}
+	return roles, nil
+}
+
+// privilegeSetString returns the given privileges as a value of the given set type, which names each privilege as
+// the grant tables do. Privileges that are not part of the set are ignored. Returns false if none of the privileges
+// are part of the set.
+func privilegeSetString(typ Type, privileges []PrivilegeType) (string, bool) {
+	setType := typ.(types.SetType)
+	var names []string
+	for _, privilege := range privileges {
+		name := privilege.String()
+		if privilege == PrivilegeType_GrantOption {
+			name = "Grant"
+		}
+		if _, err := setType.Convert(name); err == nil {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "", false
+	}
+	bits, err := setType.Convert(strings.Join(names, ","))
+	if err != nil {
+		return "", false
+	}
+	str, err := setType.BitsToString(bits.(uint64))
+	if err != nil {
+		return "", false
+	}
+	return str, true
+}
+
+// yesOrNo returns "YES" when the given value is true, and "NO" otherwise.
+func yesOrNo(val bool) string {
+	if val {
+		return "YES"
+	}
+	return "NO"
+}
@@ -0,0 +1,305 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package information_schema
+
+import (
+	"strings"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	. "github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+	"github.com/dolthub/go-mysql-server/sql/parse"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/transform"
+)
+
+// viewUsage is the set of tables, views and functions referenced by the definition of a view.
+type viewUsage struct {
+	dbName   string
+	viewName string
+	// invoker is set for the views with SQL SECURITY INVOKER, whose referenced tables are only shown to the users with
+	// privileges on them.
+	invoker   bool
+	tables    []viewTableReference
+	functions []string
+}
+
+// viewTableReference is a table or view referenced by the definition of a view.
+type viewTableReference struct {
+	dbName    string
+	tableName string
+}
+
+// viewTableUsageRowIter implements the sql.RowIter for the information_schema.VIEW_TABLE_USAGE table.
+func viewTableUsageRowIter(ctx *Context, c Catalog) (RowIter, error) {
+	usages, err := viewUsages(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	privSet, _ := ctx.GetPrivilegeSet()
+
+	var rows []Row
+	for _, usage := range usages {
+		for _, table := range usage.tables {
+			if usage.invoker && !hasTablePrivileges(privSet, table.dbName, table.tableName) {
+				continue
+			}
+			rows = append(rows, Row{
+				"def",           // view_catalog
+				usage.dbName,    // view_schema
+				usage.viewName,  // view_name
+				"def",           // table_catalog
+				table.dbName,    // table_schema
+				table.tableName, // table_name
+			})
+		}
+	}
+
+	return RowsToRowIter(rows...), nil
+}
+
+// viewRoutineUsageRowIter implements the sql.RowIter for the information_schema.VIEW_ROUTINE_USAGE table. Only the
+// functions provided by the integrator are listed, as built-in functions aren't routines. Like external stored
+// procedures in the ROUTINES table, they're listed as belonging to the schema of the view.
+func viewRoutineUsageRowIter(ctx *Context, c Catalog) (RowIter, error) {
+	usages, err := viewUsages(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []Row
+	for _, usage := range usages {
+		for _, function := range usage.functions {
+			rows = append(rows, Row{
+				"def",          // table_catalog
+				usage.dbName,   // table_schema
+				usage.viewName, // table_name
+				"def",          // specific_catalog
+				usage.dbName,   // specific_schema
+				function,       // specific_name
+			})
+		}
+	}
+
+	return RowsToRowIter(rows...), nil
+}
+
+// viewUsages returns the objects referenced by the definition of every view visible to the current user, which are
+// the views that the user may show, as well as the ones they defined.
+func viewUsages(ctx *Context, c Catalog) ([]viewUsage, error) {
+	privSet, _ := ctx.GetPrivilegeSet()
+	if privSet == nil {
+		return nil, nil
+	}
+
+	databases := c.AllDatabases(ctx)
+	// The referenced tables are looked up in the databases themselves, rather than in their privileged wrappers, as the
+	// tables referenced by views with SQL SECURITY DEFINER are listed even when the user has no privileges on them.
+	unwrapped := make(map[string]Database)
+	viewNames := make(map[string]map[string]string)
+	for _, db := range databases {
+		if privilegedDatabase, ok := db.(mysql_db.PrivilegedDatabase); ok {
+			unwrapped[strings.ToLower(db.Name())] = privilegedDatabase.Unwrap()
+		} else {
+			unwrapped[strings.ToLower(db.Name())] = db
+		}
+		views, err := viewsInDatabase(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		names := make(map[string]string)
+		for _, view := range views {
+			names[strings.ToLower(view.Name)] = view.Name
+		}
+		viewNames[strings.ToLower(db.Name())] = names
+	}
+
+	hasGlobalShowViewPriv := privSet.Has(PrivilegeType_ShowView)
+	var usages []viewUsage
+	for _, db := range databases {
+		dbName := db.Name()
+		privDbSet := privSet.Database(dbName)
+		hasDbShowViewPriv := privDbSet.Has(PrivilegeType_ShowView)
+
+		views, err := viewsInDatabase(ctx, db)
+		if err != nil {
+			return nil, err
+		}
+		for _, view := range views {
+			parsedView, err := parse.Parse(ctx, view.CreateViewStatement)
+			if err != nil {
+				return nil, err
+			}
+			viewPlan, ok := parsedView.(*plan.CreateView)
+			if !ok {
+				return nil, ErrTriggerCreateStatementInvalid.New(view.CreateViewStatement)
+			}
+
+			if !hasGlobalShowViewPriv && !hasDbShowViewPriv && !privDbSet.Table(view.Name).Has(PrivilegeType_ShowView) &&
+				!isViewDefiner(ctx, view.CreateViewStatement) {
+				continue
+			}
+
+			usage := viewUsage{
+				dbName:   dbName,
+				viewName: view.Name,
+				invoker:  strings.EqualFold(viewPlan.Security, "INVOKER"),
+			}
+			refs := newViewReferences()
+			refs.collect(viewPlan.Child)
+
+			seenTables := make(map[viewTableReference]struct{})
+			for _, table := range refs.tables {
+				tableDbName := table.dbName
+				if tableDbName == "" {
+					if _, ok := refs.ctes[strings.ToLower(table.tableName)]; ok {
+						continue
+					}
+					tableDbName = dbName
+				}
+				// Only the tables and views that exist are listed, under the names they were created with
+				tableName, ok := viewNames[strings.ToLower(tableDbName)][strings.ToLower(table.tableName)]
+				if !ok {
+					tableDb, ok := unwrapped[strings.ToLower(tableDbName)]
+					if !ok {
+						continue
+					}
+					t, ok, err := tableDb.GetTableInsensitive(ctx, table.tableName)
+					if err != nil {
+						return nil, err
+					} else if !ok {
+						continue
+					}
+					tableDbName, tableName = tableDb.Name(), t.Name()
+				}
+				ref := viewTableReference{dbName: tableDbName, tableName: tableName}
+				if _, ok := seenTables[ref]; ok {
+					continue
+				}
+				seenTables[ref] = struct{}{}
+				usage.tables = append(usage.tables, ref)
+			}
+
+			if fp, ok := c.(ExternalFunctionProvider); ok {
+				for _, name := range refs.functionNames {
+					if fn, err := fp.ExternalFunction(ctx, name); err == nil {
+						usage.functions = append(usage.functions, fn.FunctionName())
+					}
+				}
+			}
+
+			usages = append(usages, usage)
+		}
+	}
+
+	return usages, nil
+}
+
+// isViewDefiner returns whether the current user is the definer of the view created by the statement given. The
+// definer is only known when the statement has a DEFINER clause, as the parser otherwise defaults it to the current
+// user.
+func isViewDefiner(ctx *Context, createViewStatement string) bool {
+	stmt, err := sqlparser.Parse(createViewStatement)
+	if err != nil {
+		return false
+	}
+	ddl, ok := stmt.(*sqlparser.DDL)
+	if !ok || ddl.ViewSpec == nil {
+		return false
+	}
+	user, host, ok := strings.Cut(ddl.ViewSpec.Definer, "@")
+	if !ok {
+		return false
+	}
+	client := ctx.Session.Client()
+	user, host = removeBackticks(user), removeBackticks(host)
+	return user == client.User && (host == client.Address || host == "%")
+}
+
+// hasTablePrivileges returns whether the privilege set given has any privilege on the table given.
+func hasTablePrivileges(privSet PrivilegeSet, dbName, tableName string) bool {
+	if privSet == nil {
+		return false
+	}
+	if privSet.Count() > 0 {
+		return true
+	}
+	privDbSet := privSet.Database(dbName)
+	return privDbSet.Count() > 0 || privDbSet.Table(tableName).HasPrivileges()
+}
+
+// viewReferences collects the names of the tables and functions referenced by a view definition, in the order in
+// which they appear.
+type viewReferences struct {
+	tables        []viewTableReference
+	functionNames []string
+	functions     map[string]struct{}
+	// ctes are the names of the common table expressions of the definition. Unqualified references to these names are
+	// not references to tables. Scoping is not taken into account, so a table named like a CTE of another part of the
+	// definition isn't reported.
+	ctes map[string]struct{}
+}
+
+func newViewReferences() *viewReferences {
+	return &viewReferences{
+		functions: make(map[string]struct{}),
+		ctes:      make(map[string]struct{}),
+	}
+}
+
+// collect adds the tables and functions referenced by the node given, including the ones in subqueries.
+func (r *viewReferences) collect(node Node) {
+	transform.Inspect(node, func(n Node) bool {
+		switch n := n.(type) {
+		case *plan.UnresolvedTable:
+			r.addTable(n.Database(), n.Name())
+		case *plan.With:
+			for _, cte := range n.CTEs {
+				r.ctes[strings.ToLower(cte.Subquery.Name())] = struct{}{}
+				r.collect(cte.Subquery)
+			}
+		}
+
+		if ne, ok := n.(Expressioner); ok {
+			for _, e := range ne.Expressions() {
+				transform.InspectExpr(e, func(e Expression) bool {
+					switch e := e.(type) {
+					case *plan.Subquery:
+						r.collect(e.Query)
+					case *expression.UnresolvedFunction:
+						r.addFunction(e.Name())
+					}
+					return false
+				})
+			}
+		}
+		return true
+	})
+}
+
+func (r *viewReferences) addTable(dbName, tableName string) {
+	r.tables = append(r.tables, viewTableReference{dbName: dbName, tableName: tableName})
+}
+
+func (r *viewReferences) addFunction(name string) {
+	key := strings.ToLower(name)
+	if _, ok := r.functions[key]; ok {
+		return
+	}
+	r.functions[key] = struct{}{}
+	r.functionNames = append(r.functionNames, name)
+}
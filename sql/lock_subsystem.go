@@ -15,10 +15,9 @@
 package sql
 
 import (
+	"sort"
 	"sync"
-	"sync/atomic"
 	"time"
-	"unsafe"
 
 	"gopkg.in/src-d/go-errors.v1"
 )
@@ -32,141 +31,192 @@ var ErrLockDoesNotExist = errors.NewKind("Lock '%s' does not exist.")
 // ErrLockNotOwned is the kind of error returned when attempting an operation against a lock that the given context does not own.
 var ErrLockNotOwned = errors.NewKind("Operation '%s' failed as the lock '%s' has a different owner.")
 
+// ErrUserLockDeadlock is the kind of error returned when waiting for a named lock would never end, as its owner waits
+// (possibly through other sessions) for a lock that the waiting session owns.
+var ErrUserLockDeadlock = errors.NewKind("Deadlock found when trying to get user-level lock; try rolling back transaction/releasing locks and restarting lock acquisition.")
+
+// waitingForUserLock is the state of processes waiting for a named lock.
+const waitingForUserLock = "User lock"
+
 type ownedLock struct {
-	Owner int64
+	Owner uint32
 	Count int64
 }
 
-// LockSubsystem manages reentrant named locks
+// LockSubsystem manages reentrant named locks. Sessions waiting for a lock form a wait-for graph with the sessions
+// owning them, which is checked for cycles whenever a session starts waiting, so that a deadlock between sessions is
+// reported rather than waited out.
 type LockSubsystem struct {
-	lockLock *sync.RWMutex
-	locks    map[string]**ownedLock
+	mu    sync.Mutex
+	locks map[string]*ownedLock
+	// waiting is the name of the lock that each waiting session waits for. A session waits for one lock at a time.
+	waiting map[uint32]string
+	// changed is closed, and replaced, whenever a lock is released.
+	changed chan struct{}
 }
 
 // NewLockSubsystem creates a LockSubsystem object
 func NewLockSubsystem() *LockSubsystem {
-	return &LockSubsystem{&sync.RWMutex{}, make(map[string]**ownedLock)}
+	return &LockSubsystem{
+		locks:   make(map[string]*ownedLock),
+		waiting: make(map[uint32]string),
+		changed: make(chan struct{}),
+	}
 }
 
-func (ls *LockSubsystem) getNamedLock(name string) **ownedLock {
-	ls.lockLock.RLock()
-	defer ls.lockLock.RUnlock()
+// Lock attempts to acquire a lock with a given name for the Id associated with the given ctx.Session within the given
+// timeout. A negative timeout waits until the lock is acquired. Returns ErrUserLockDeadlock if the owner of the lock
+// waits for a lock the session owns.
+func (ls *LockSubsystem) Lock(ctx *Context, name string, timeout time.Duration) error {
+	userId := ctx.Session.ID()
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
 
-	return ls.locks[name]
+	nl, ok := ls.locks[name]
+	if !ok {
+		nl = &ownedLock{}
+		ls.locks[name] = nl
+	}
+	if nl.Owner == userId {
+		nl.Count++
+		return nil
+	}
+
+	if nl.Owner != 0 {
+		if err := ls.wait(ctx, name, nl, timeout); err != nil {
+			return err
+		}
+	}
+	nl.Owner, nl.Count = userId, 1
+	return ctx.Session.AddLock(name)
 }
 
-func (ls *LockSubsystem) createLock(name string) **ownedLock {
-	ls.lockLock.Lock()
-	defer ls.lockLock.Unlock()
+// wait waits until the lock given, which is owned by another session, is released. It must be called with the mutex
+// held, which is held again once it returns.
+func (ls *LockSubsystem) wait(ctx *Context, name string, nl *ownedLock, timeout time.Duration) error {
+	userId := ctx.Session.ID()
+	if timeout == 0 {
+		return ErrLockTimeout.New(name)
+	}
+	// Only a session that starts waiting can close a cycle: one that is granted a lock others wait for waits for
+	// nothing itself.
+	if ls.deadlocked(userId, nl.Owner) {
+		return ErrUserLockDeadlock.New()
+	}
 
-	nl, ok := ls.locks[name]
+	ls.waiting[userId] = name
+	defer delete(ls.waiting, userId)
+	ctx.ProcessList.UpdateState(ctx.Pid(), waitingForUserLock)
+	defer ctx.ProcessList.UpdateState(ctx.Pid(), "")
 
-	if !ok {
-		newLock := &ownedLock{}
-		ls.locks[name] = &newLock
-		nl = &newLock
+	var timer <-chan time.Time
+	if timeout > 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		timer = t.C
 	}
+	for nl.Owner != 0 {
+		changed := ls.changed
+		ls.mu.Unlock()
+
+		var err error
+		select {
+		case <-changed:
+		case <-timer:
+			err = ErrLockTimeout.New(name)
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
 
-	return nl
+		ls.mu.Lock()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// Lock attempts to acquire a lock with a given name for the Id associated with the given ctx.Session within the given
-// timeout
-func (ls *LockSubsystem) Lock(ctx *Context, name string, timeout time.Duration) error {
-	nl := ls.getNamedLock(name)
-
-	if nl == nil {
-		nl = ls.createLock(name)
-	}
-
-	userId := int64(ctx.Session.ID())
-	for i, start := 0, time.Now(); i == 0 || timeout < 0 || time.Since(start) < timeout; i++ {
-		dest := (*unsafe.Pointer)(unsafe.Pointer(nl))
-		curr := atomic.LoadPointer(dest)
-		currLock := *(*ownedLock)(curr)
-
-		if currLock.Owner == 0 {
-			newVal := &ownedLock{userId, 1}
-			if atomic.CompareAndSwapPointer(dest, curr, unsafe.Pointer(newVal)) {
-				return ctx.Session.AddLock(name)
-			}
-		} else if currLock.Owner == userId {
-			newVal := &ownedLock{userId, currLock.Count + 1}
-			if atomic.CompareAndSwapPointer(dest, curr, unsafe.Pointer(newVal)) {
-				return nil
-			}
+// deadlocked returns whether the session given would never stop waiting for a lock owned by the owner given, as the
+// owner waits for a lock the session owns, or for a lock whose owner does in turn. It must be called with the mutex
+// held.
+func (ls *LockSubsystem) deadlocked(waiter, owner uint32) bool {
+	seen := make(map[uint32]struct{})
+	for owner != 0 {
+		if owner == waiter {
+			return true
+		}
+		if _, ok := seen[owner]; ok {
+			return false
 		}
+		seen[owner] = struct{}{}
 
-		time.Sleep(100 * time.Microsecond)
+		name, ok := ls.waiting[owner]
+		if !ok {
+			return false
+		}
+		owner = ls.locks[name].Owner
 	}
-
-	return ErrLockTimeout.New(name)
+	return false
 }
 
 // Unlock releases a lock with a given name for the ID associated with the given ctx.Session
 func (ls *LockSubsystem) Unlock(ctx *Context, name string) error {
-	nl := ls.getNamedLock(name)
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
 
-	if nl == nil {
+	nl, ok := ls.locks[name]
+	if !ok {
 		return ErrLockDoesNotExist.New(name)
 	}
+	if nl.Owner != ctx.Session.ID() {
+		return ErrLockNotOwned.New("unlock", name)
+	}
 
-	userId := int64(ctx.Session.ID())
-	for {
-		dest := (*unsafe.Pointer)(unsafe.Pointer(nl))
-		curr := atomic.LoadPointer(dest)
-		currLock := *(*ownedLock)(curr)
-
-		if currLock.Owner != userId {
-			return ErrLockNotOwned.New("unlock", name)
-		}
-
-		newVal := &ownedLock{}
-		if currLock.Count > 1 {
-			newVal = &ownedLock{userId, currLock.Count - 1}
-		}
-
-		if atomic.CompareAndSwapPointer(dest, curr, unsafe.Pointer(newVal)) {
-			if newVal.Count == 0 {
-				return ctx.Session.DelLock(name)
-			}
-
-			return nil
-		}
+	nl.Count--
+	if nl.Count > 0 {
+		return nil
 	}
+	nl.Owner = 0
+	ls.notify()
+	return ctx.Session.DelLock(name)
 }
 
 // ReleaseAll releases all locks the ID associated with the given ctx.Session, and returns the number of locks that were
-// succeessfully released.
+// succeessfully released. A lock acquired several times counts once for each time.
 func (ls *LockSubsystem) ReleaseAll(ctx *Context) (int, error) {
-	releaseCount := 0
+	var names []string
 	_ = ctx.Session.IterLocks(func(name string) error {
-		nl := ls.getNamedLock(name)
-
-		if nl != nil {
-			userId := ctx.Session.ID()
-			for {
-				dest := (*unsafe.Pointer)(unsafe.Pointer(nl))
-				curr := atomic.LoadPointer(dest)
-				currLock := *(*ownedLock)(curr)
-
-				if currLock.Owner != int64(userId) {
-					break
-				}
-
-				if atomic.CompareAndSwapPointer(dest, curr, unsafe.Pointer(&ownedLock{})) {
-					releaseCount++
-					break
-				}
-			}
-		}
-
+		names = append(names, name)
 		return nil
 	})
 
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	releaseCount := 0
+	userId := ctx.Session.ID()
+	for _, name := range names {
+		if nl, ok := ls.locks[name]; ok && nl.Owner == userId {
+			releaseCount += int(nl.Count)
+			nl.Owner, nl.Count = 0, 0
+		}
+		if err := ctx.Session.DelLock(name); err != nil {
+			return releaseCount, err
+		}
+	}
+	if releaseCount > 0 {
+		ls.notify()
+	}
 	return releaseCount, nil
 }
 
+// notify wakes up every waiting session, so that it checks whether the lock it waits for was released. It must be
+// called with the mutex held.
+func (ls *LockSubsystem) notify() {
+	close(ls.changed)
+	ls.changed = make(chan struct{})
+}
+
 // LockState represents the different states a lock can be in
 type LockState int
 
@@ -181,19 +231,63 @@ const (
 
 // GetLockState returns the LockState and owner ID for a lock with a given name.
 func (ls *LockSubsystem) GetLockState(name string) (state LockState, owner uint32) {
-	nl := ls.getNamedLock(name)
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
 
-	if nl == nil {
+	nl, ok := ls.locks[name]
+	if !ok {
 		return LockDoesNotExist, 0
 	}
+	if nl.Owner == 0 {
+		return LockFree, 0
+	}
+	return LockInUse, nl.Owner
+}
+
+// HeldLock is a named lock that is currently held, along with its owner and the number of times it was acquired.
+type HeldLock struct {
+	Name  string
+	Owner uint32
+	Count int64
+}
 
-	dest := (*unsafe.Pointer)(unsafe.Pointer(nl))
-	curr := atomic.LoadPointer(dest)
-	currLock := *(*ownedLock)(curr)
+// HeldLocks returns every named lock that is currently held, sorted by name.
+func (ls *LockSubsystem) HeldLocks() []HeldLock {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
 
-	if currLock.Owner == 0 {
-		return LockFree, 0
-	} else {
-		return LockInUse, uint32(currLock.Owner)
+	var held []HeldLock
+	for name, nl := range ls.locks {
+		if nl.Owner != 0 {
+			held = append(held, HeldLock{Name: name, Owner: nl.Owner, Count: nl.Count})
+		}
+	}
+	sort.Slice(held, func(i, j int) bool {
+		return held[i].Name < held[j].Name
+	})
+	return held
+}
+
+// WaitingLock is a named lock that a session waits to acquire.
+type WaitingLock struct {
+	Name   string
+	Waiter uint32
+}
+
+// WaitingLocks returns every named lock that sessions wait for, sorted by name and then by waiting session.
+func (ls *LockSubsystem) WaitingLocks() []WaitingLock {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	var waiting []WaitingLock
+	for waiter, name := range ls.waiting {
+		waiting = append(waiting, WaitingLock{Name: name, Waiter: waiter})
 	}
+	sort.Slice(waiting, func(i, j int) bool {
+		if waiting[i].Name != waiting[j].Name {
+			return waiting[i].Name < waiting[j].Name
+		}
+		return waiting[i].Waiter < waiting[j].Waiter
+	})
+	return waiting
 }
@@ -187,3 +187,96 @@ func TestGetLockState(t *testing.T) {
 	assert.Equal(t, LockFree, state)
 	assert.Equal(t, uint32(0), owner)
 }
+
+func TestHeldLocks(t *testing.T) {
+	user1 := NewEmptyContext()
+	user2 := NewEmptyContext()
+	ls := NewLockSubsystem()
+
+	assert.Empty(t, ls.HeldLocks())
+
+	assert.NoError(t, ls.Lock(user1, "b_lock", 0))
+	assert.NoError(t, ls.Lock(user1, "b_lock", 0))
+	assert.NoError(t, ls.Lock(user2, "a_lock", 0))
+	assert.Equal(t, []HeldLock{
+		{Name: "a_lock", Owner: user2.Session.ID(), Count: 1},
+		{Name: "b_lock", Owner: user1.Session.ID(), Count: 2},
+	}, ls.HeldLocks())
+
+	assert.NoError(t, ls.Unlock(user2, "a_lock"))
+	assert.Equal(t, []HeldLock{
+		{Name: "b_lock", Owner: user1.Session.ID(), Count: 2},
+	}, ls.HeldLocks())
+}
+
+func TestDeadlock(t *testing.T) {
+	user1 := NewEmptyContext()
+	user2 := NewEmptyContext()
+	user3 := NewEmptyContext()
+	ls := NewLockSubsystem()
+
+	assert.NoError(t, ls.Lock(user1, "a_lock", 0))
+	assert.NoError(t, ls.Lock(user2, "b_lock", 0))
+	assert.NoError(t, ls.Lock(user3, "c_lock", 0))
+
+	// user2 waits for user1, and user3 for user2
+	waitFor := func(ctx *Context, name string) chan error {
+		done := make(chan error)
+		go func() {
+			done <- ls.Lock(ctx, name, -1)
+		}()
+		return done
+	}
+	done2 := waitFor(user2, "a_lock")
+	done3 := waitFor(user3, "b_lock")
+	for len(ls.WaitingLocks()) < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	assert.Equal(t, []WaitingLock{
+		{Name: "a_lock", Waiter: user2.Session.ID()},
+		{Name: "b_lock", Waiter: user3.Session.ID()},
+	}, ls.WaitingLocks())
+
+	// user1 waiting for user3 would close the cycle, however long it's willing to wait
+	assert.True(t, ErrUserLockDeadlock.Is(ls.Lock(user1, "c_lock", -1)))
+	assert.True(t, ErrUserLockDeadlock.Is(ls.Lock(user1, "b_lock", time.Hour)))
+	assert.True(t, ErrLockTimeout.Is(ls.Lock(user1, "c_lock", 0)))
+	assert.Nil(t, getLockDiffs(user1, "a_lock"))
+
+	// Releasing a lock lets the sessions waiting for it proceed in turn
+	assert.NoError(t, ls.Unlock(user1, "a_lock"))
+	assert.NoError(t, <-done2)
+	_, err := ls.ReleaseAll(user2)
+	assert.NoError(t, err)
+	assert.NoError(t, <-done3)
+	assert.Empty(t, ls.WaitingLocks())
+	assert.Equal(t, []HeldLock{
+		{Name: "b_lock", Owner: user3.Session.ID(), Count: 1},
+		{Name: "c_lock", Owner: user3.Session.ID(), Count: 1},
+	}, ls.HeldLocks())
+}
+
+func TestReleaseAll(t *testing.T) {
+	user1 := NewEmptyContext()
+	user2 := NewEmptyContext()
+	ls := NewLockSubsystem()
+
+	assert.NoError(t, ls.Lock(user1, "a_lock", 0))
+	assert.NoError(t, ls.Lock(user1, "a_lock", 0))
+	assert.NoError(t, ls.Lock(user1, "b_lock", 0))
+	assert.NoError(t, ls.Lock(user2, "c_lock", 0))
+
+	// Every acquisition of every lock of the session is released
+	count, err := ls.ReleaseAll(user1)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, count)
+	assert.Nil(t, getLockDiffs(user1))
+	assert.Nil(t, getLockDiffs(user2, "c_lock"))
+	assert.Equal(t, []HeldLock{
+		{Name: "c_lock", Owner: user2.Session.ID(), Count: 1},
+	}, ls.HeldLocks())
+
+	count, err = ls.ReleaseAll(user1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, count)
+}
@@ -136,8 +136,16 @@ func HasAvailableMemory(r Reporter) bool {
 
 // MemoryManager is in charge of keeping track and managing all the components that operate
 // in memory. There should only be one instance of a memory manager running at the
-// same time in each process.
+// same time in each process. Each query uses a view of it scoped to the query's memory
+// budget, which the caches created through that view are charged to.
 type MemoryManager struct {
+	*memoryCaches
+	// budget is charged by the caches created through this manager, or nil if they aren't accounted for.
+	budget *MemoryBudget
+}
+
+// memoryCaches holds the caches of a memory manager, shared by its views scoped to a memory budget.
+type memoryCaches struct {
 	mu       sync.RWMutex
 	reporter Reporter
 	caches   map[uint64]Disposable
@@ -152,11 +160,25 @@ func NewMemoryManager(r Reporter) *MemoryManager {
 	}
 
 	return &MemoryManager{
-		reporter: r,
-		caches:   make(map[uint64]Disposable),
+		memoryCaches: &memoryCaches{
+			reporter: r,
+			caches:   make(map[uint64]Disposable),
+		},
 	}
 }
 
+// WithBudget returns a view of the manager whose caches are charged to the memory budget given. The view shares its
+// caches with this manager.
+func (m *MemoryManager) WithBudget(budget *MemoryBudget) *MemoryManager {
+	return &MemoryManager{memoryCaches: m.memoryCaches, budget: budget}
+}
+
+// Budget returns the memory budget charged by the caches created through this manager, which is nil if they aren't
+// accounted for.
+func (m *MemoryManager) Budget() *MemoryBudget {
+	return m.budget
+}
+
 // HasAvailable reports whether the memory manager has any available memory.
 func (m *MemoryManager) HasAvailable() bool {
 	return HasAvailableMemory(m.reporter)
@@ -179,7 +201,7 @@ func (m *MemoryManager) NewLRUCache(size uint) (KeyValueCache, DisposeFunc) {
 // NewHistoryCache returns an empty history cache and a function to dispose it when it's
 // no longer needed.
 func (m *MemoryManager) NewHistoryCache() (KeyValueCache, DisposeFunc) {
-	c := newHistoryCache(m, m.reporter)
+	c := newHistoryCache(m, m.reporter, m.budget)
 	pos := m.addCache(c)
 	return c, func() {
 		c.Dispose()
@@ -190,7 +212,7 @@ func (m *MemoryManager) NewHistoryCache() (KeyValueCache, DisposeFunc) {
 // NewRowsCache returns an empty rows cache and a function to dispose it when it's
 // no longer needed.
 func (m *MemoryManager) NewRowsCache() (RowsCache, DisposeFunc) {
-	c := newRowsCache(m, m.reporter)
+	c := newRowsCache(m, m.reporter, m.budget)
 	pos := m.addCache(c)
 	return c, func() {
 		c.Dispose()
@@ -201,9 +223,22 @@ func (m *MemoryManager) NewRowsCache() (RowsCache, DisposeFunc) {
 // NewRowsCache returns an empty rows cache and a function to dispose it when it's
 // no longer needed.
 func (m *MemoryManager) NewRows2Cache() (Rows2Cache, DisposeFunc) {
-	c := newRowsCache(m, m.reporter)
+	c := newRowsCache(m, m.reporter, m.budget)
+	pos := m.addCache(c)
+	return c, func() {
+		c.Dispose()
+		m.removeCache(pos)
+	}
+}
+
+// NewQueryCache returns an empty query result cache and a function to dispose it when it's no longer needed. The
+// cache is emptied whenever the manager frees its caches.
+func (m *MemoryManager) NewQueryCache() (*QueryCache, DisposeFunc) {
+	c := newQueryCache(m, m.reporter)
 	pos := m.addCache(c)
+	registerQueryCache(c)
 	return c, func() {
+		unregisterQueryCache(c)
 		c.Dispose()
 		m.removeCache(pos)
 	}
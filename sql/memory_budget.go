@@ -0,0 +1,136 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"math"
+	"sync/atomic"
+
+	errors "gopkg.in/src-d/go-errors.v1"
+)
+
+const (
+	// QueryMemoryLimitSysVarName is the system variable holding the largest number of bytes that the caches and buffers
+	// of a single query may use.
+	QueryMemoryLimitSysVarName = "query_memory_limit"
+	// ConnectionMemoryLimitSysVarName is the system variable holding the largest number of bytes that the caches and
+	// buffers of the queries of a session may use at once.
+	ConnectionMemoryLimitSysVarName = "connection_memory_limit"
+)
+
+// ErrQueryMemoryLimitExceeded is returned when a query needs more memory than query_memory_limit allows.
+var ErrQueryMemoryLimitExceeded = errors.NewKind("query memory limit of %d bytes exceeded: %d bytes needed; raise query_memory_limit to run this query")
+
+// ErrConnectionMemoryLimitExceeded is returned when the queries of a session need more memory than
+// connection_memory_limit allows.
+var ErrConnectionMemoryLimitExceeded = errors.NewKind("connection memory limit of %d bytes exceeded: %d bytes needed; raise connection_memory_limit to run this query")
+
+// IsMemoryUnavailable returns whether the error given is returned because there is no memory available for a cache,
+// either for the process or under the limits of the query and its session. The components that can write their data
+// to disk do so on these errors.
+func IsMemoryUnavailable(err error) bool {
+	return ErrNoMemoryAvailable.Is(err) || ErrQueryMemoryLimitExceeded.Is(err) || ErrConnectionMemoryLimitExceeded.Is(err)
+}
+
+// MemoryBudget accounts for the memory used by the caches and buffers of a query or of a session, and enforces a limit
+// on it. The budget of a query is charged along with the budget of its session. All methods may be called on a nil
+// budget, which accounts for nothing.
+type MemoryBudget struct {
+	limit    uint64
+	used     uint64
+	parent   *MemoryBudget
+	exceeded *errors.Kind
+}
+
+// NewQueryMemoryBudget returns a budget for a query with the limit given, charged along with the budget of its session.
+func NewQueryMemoryBudget(limit uint64, session *MemoryBudget) *MemoryBudget {
+	return &MemoryBudget{limit: limit, parent: session, exceeded: ErrQueryMemoryLimitExceeded}
+}
+
+// NewSessionMemoryBudget returns a budget for a session, which is unlimited until a limit is set.
+func NewSessionMemoryBudget() *MemoryBudget {
+	return &MemoryBudget{limit: math.MaxUint64, exceeded: ErrConnectionMemoryLimitExceeded}
+}
+
+// Limit returns the largest number of bytes that may be charged to the budget.
+func (b *MemoryBudget) Limit() uint64 {
+	if b == nil {
+		return math.MaxUint64
+	}
+	return atomic.LoadUint64(&b.limit)
+}
+
+// SetLimit sets the largest number of bytes that may be charged to the budget. It applies to the charges made
+// afterwards.
+func (b *MemoryBudget) SetLimit(limit uint64) {
+	if b != nil {
+		atomic.StoreUint64(&b.limit, limit)
+	}
+}
+
+// Used returns the number of bytes charged to the budget.
+func (b *MemoryBudget) Used() uint64 {
+	if b == nil {
+		return 0
+	}
+	return atomic.LoadUint64(&b.used)
+}
+
+// Charge charges the number of bytes given to the budget, and to the budget of its session. If that exceeds the limit
+// of either budget, nothing is charged and an error is returned.
+func (b *MemoryBudget) Charge(size uint64) error {
+	if b == nil || size == 0 {
+		return nil
+	}
+	used := atomic.AddUint64(&b.used, size)
+	if limit := b.Limit(); used > limit {
+		b.release(size)
+		return b.exceeded.New(limit, used)
+	}
+	if err := b.parent.Charge(size); err != nil {
+		b.release(size)
+		return err
+	}
+	return nil
+}
+
+// Release gives back the number of bytes given, which were charged to the budget before.
+func (b *MemoryBudget) Release(size uint64) {
+	if b == nil || size == 0 {
+		return
+	}
+	b.release(size)
+	b.parent.Release(size)
+}
+
+func (b *MemoryBudget) release(size uint64) {
+	atomic.AddUint64(&b.used, ^(size - 1))
+}
+
+// RowMemorySize returns an estimate of the memory used by the row given, counting the size of each value's interface
+// along with the contents of strings and byte slices.
+func RowMemorySize(row Row) uint64 {
+	const valueSize = 16
+	size := uint64(len(row)) * valueSize
+	for _, v := range row {
+		switch v := v.(type) {
+		case string:
+			size += uint64(len(v))
+		case []byte:
+			size += uint64(len(v))
+		}
+	}
+	return size
+}
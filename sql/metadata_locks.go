@@ -0,0 +1,469 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/src-d/go-errors.v1"
+)
+
+// ErrLockWaitTimeout is returned when a metadata lock isn't granted within the lock_wait_timeout of the session.
+var ErrLockWaitTimeout = errors.NewKind("Lock wait timeout exceeded; try restarting transaction")
+
+// LockWaitTimeout returns how long a statement of the session of the context waits for the metadata and table locks
+// it needs, as given by the lock_wait_timeout system variable, or a negative duration if it waits indefinitely.
+func LockWaitTimeout(ctx *Context) time.Duration {
+	val, err := ctx.GetSessionVariable(ctx, "lock_wait_timeout")
+	if err != nil {
+		return -1
+	}
+	seconds, ok := val.(int64)
+	if !ok {
+		return -1
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// MDLType is the mode of a metadata lock, which determines the locks it's compatible with.
+type MDLType int
+
+const (
+	// MDLSharedRead is taken by statements that read a table. It only conflicts with exclusive locks.
+	MDLSharedRead MDLType = iota
+	// MDLSharedWrite is taken by statements that change the rows of a table. It only conflicts with exclusive locks.
+	MDLSharedWrite
+	// MDLSharedUpgradable is taken by statements that change the definition of a table, before they upgrade it to an
+	// exclusive lock. It lets other sessions read and write the table, but not take another upgradable lock on it.
+	MDLSharedUpgradable
+	// MDLSharedNoReadWrite is taken by LOCK TABLES ... WRITE. It keeps other sessions from using the table, while the
+	// session that holds it may still upgrade it to an exclusive lock.
+	MDLSharedNoReadWrite
+	// MDLExclusive is taken by statements that change the definition of a table. It conflicts with every other lock.
+	MDLExclusive
+)
+
+// String returns the name of the lock type, as listed in performance_schema.metadata_locks.
+func (t MDLType) String() string {
+	switch t {
+	case MDLSharedRead:
+		return "SHARED_READ"
+	case MDLSharedWrite:
+		return "SHARED_WRITE"
+	case MDLSharedUpgradable:
+		return "SHARED_UPGRADABLE"
+	case MDLSharedNoReadWrite:
+		return "SHARED_NO_READ_WRITE"
+	case MDLExclusive:
+		return "EXCLUSIVE"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// compatible returns whether a lock of this type can be granted while another session holds a lock of the type given.
+func (t MDLType) compatible(other MDLType) bool {
+	switch {
+	case t >= MDLSharedNoReadWrite || other >= MDLSharedNoReadWrite:
+		return false
+	case t == MDLSharedUpgradable && other == MDLSharedUpgradable:
+		return false
+	default:
+		return true
+	}
+}
+
+// MDLDuration is how long a metadata lock is held.
+type MDLDuration int
+
+const (
+	// MDLStatement locks are released once the statement that took them is done.
+	MDLStatement MDLDuration = iota
+	// MDLTransaction locks are released once the transaction of the statement that took them ends.
+	MDLTransaction
+	// MDLExplicit locks are taken by LOCK TABLES, and are held until the session unlocks its tables.
+	MDLExplicit
+)
+
+// String returns the name of the duration, as listed in performance_schema.metadata_locks.
+func (d MDLDuration) String() string {
+	switch d {
+	case MDLTransaction:
+		return "TRANSACTION"
+	case MDLExplicit:
+		return "EXPLICIT"
+	default:
+		return "STATEMENT"
+	}
+}
+
+// MDLKey identifies the table a metadata lock is taken on. Names are case-insensitive.
+type MDLKey struct {
+	Database string
+	Table    string
+}
+
+// NewMDLKey returns the key of the metadata locks on the table given.
+func NewMDLKey(database, table string) MDLKey {
+	return MDLKey{Database: strings.ToLower(database), Table: strings.ToLower(table)}
+}
+
+// SortMDLKeys sorts the keys given by database and table, which is the order in which statements take the locks of
+// their tables so that statements locking the same tables can't deadlock each other.
+func SortMDLKeys(keys []MDLKey) {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Database != keys[j].Database {
+			return keys[i].Database < keys[j].Database
+		}
+		return keys[i].Table < keys[j].Table
+	})
+}
+
+// MetadataLock is a metadata lock held or waited for by a session.
+type MetadataLock struct {
+	Key      MDLKey
+	Type     MDLType
+	Duration MDLDuration
+	// Owner is the ID of the session.
+	Owner uint32
+	// Granted is false while the session waits for the lock.
+	Granted bool
+}
+
+// mdlTicket is a metadata lock granted to a session. A session gets a ticket for each lock it takes, so that upgraded
+// locks and locks of a shorter duration can be released on their own.
+type mdlTicket struct {
+	owner    uint32
+	typ      MDLType
+	duration MDLDuration
+}
+
+// mdlRequest is a metadata lock a session waits for.
+type mdlRequest struct {
+	owner    uint32
+	key      MDLKey
+	typ      MDLType
+	duration MDLDuration
+}
+
+// mdlLocks are the locks granted and waited for on a table. Requests are granted in order, except that a session that
+// already holds a lock on the table doesn't queue behind other sessions.
+type mdlLocks struct {
+	granted []*mdlTicket
+	pending []*mdlRequest
+}
+
+// MetadataLockManager manages the metadata locks that keep sessions from changing the definition of tables while
+// other sessions use them. A session waits for the locks it requests until they are compatible with the locks of
+// other sessions, the lock_wait_timeout of the session passes, or it would deadlock with other waiting sessions.
+type MetadataLockManager struct {
+	mu    sync.Mutex
+	locks map[MDLKey]*mdlLocks
+	// waiting is the request each waiting session waits for.
+	waiting map[uint32]*mdlRequest
+	// changed is closed, and replaced, whenever locks are released or requests stop waiting.
+	changed chan struct{}
+	// generation is incremented whenever an exclusive lock is released, and releasedAt is the generation at which an
+	// exclusive lock on each table was last released.
+	generation uint64
+	releasedAt map[MDLKey]uint64
+}
+
+// NewMetadataLockManager returns a new MetadataLockManager without any locks.
+func NewMetadataLockManager() *MetadataLockManager {
+	return &MetadataLockManager{
+		locks:      make(map[MDLKey]*mdlLocks),
+		waiting:    make(map[uint32]*mdlRequest),
+		changed:    make(chan struct{}),
+		releasedAt: make(map[MDLKey]uint64),
+	}
+}
+
+// Acquire takes a metadata lock of the type and duration given on the table given for the session of the context,
+// waiting up to the timeout given for other sessions to release conflicting locks. Requesting a stronger lock than
+// the session already holds upgrades it. Returns ErrLockWaitTimeout if the timeout passes, and ErrLockDeadlock if
+// waiting would deadlock.
+func (m *MetadataLockManager) Acquire(ctx *Context, key MDLKey, typ MDLType, duration MDLDuration, timeout time.Duration) error {
+	req := &mdlRequest{owner: ctx.Session.ID(), key: key, typ: typ, duration: duration}
+
+	m.mu.Lock()
+	locks, ok := m.locks[key]
+	if !ok {
+		locks = &mdlLocks{}
+		m.locks[key] = locks
+	}
+	if locks.holds(req) {
+		m.mu.Unlock()
+		return nil
+	}
+	if len(m.blockers(req)) == 0 {
+		locks.grant(req)
+		m.mu.Unlock()
+		return nil
+	}
+	locks.pending = append(locks.pending, req)
+	m.waiting[req.owner] = req
+
+	var timer <-chan time.Time
+	if timeout >= 0 {
+		t := time.NewTimer(timeout)
+		defer t.Stop()
+		timer = t.C
+	}
+	for {
+		if len(m.blockers(req)) == 0 {
+			m.stopWaiting(req)
+			locks.grant(req)
+			m.mu.Unlock()
+			return nil
+		}
+		if m.deadlocks(req) {
+			m.stopWaiting(req)
+			m.dropUnused(key)
+			m.mu.Unlock()
+			return ErrLockDeadlock.New("Deadlock found when trying to get lock")
+		}
+		changed := m.changed
+		m.mu.Unlock()
+
+		var err error
+		select {
+		case <-changed:
+		case <-timer:
+			err = ErrLockWaitTimeout.New()
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+
+		m.mu.Lock()
+		if err != nil {
+			m.stopWaiting(req)
+			m.dropUnused(key)
+			m.mu.Unlock()
+			return err
+		}
+	}
+}
+
+// ReleaseStatementLocks releases the statement locks of the session of the context.
+func (m *MetadataLockManager) ReleaseStatementLocks(ctx *Context) {
+	m.release(ctx.Session.ID(), func(t *mdlTicket) bool {
+		return t.duration == MDLStatement
+	})
+}
+
+// ReleaseAll releases the statement and transaction locks of the session of the context, as is done once its
+// transaction ends. The locks taken by LOCK TABLES are kept until ReleaseExplicitLocks.
+func (m *MetadataLockManager) ReleaseAll(ctx *Context) {
+	m.release(ctx.Session.ID(), func(t *mdlTicket) bool {
+		return t.duration != MDLExplicit
+	})
+}
+
+// ReleaseExplicitLocks releases the locks taken by LOCK TABLES for the session given, once it unlocks its tables.
+func (m *MetadataLockManager) ReleaseExplicitLocks(owner uint32) {
+	m.release(owner, func(t *mdlTicket) bool {
+		return t.duration == MDLExplicit
+	})
+}
+
+// HasLocks returns whether the session of the context holds any lock.
+func (m *MetadataLockManager) HasLocks(ctx *Context) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	owner := ctx.Session.ID()
+	for _, locks := range m.locks {
+		for _, t := range locks.granted {
+			if t.owner == owner {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Generation returns the number of exclusive locks released so far. Statements that are analyzed before they take
+// their locks get the generation first, and then check with ChangedSince whether the definition of their tables may
+// have changed before they were locked.
+func (m *MetadataLockManager) Generation() uint64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.generation
+}
+
+// ChangedSince returns whether an exclusive lock on any of the tables given was released after the generation given,
+// as returned by Generation.
+func (m *MetadataLockManager) ChangedSince(generation uint64, keys ...MDLKey) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, key := range keys {
+		if m.releasedAt[key] > generation {
+			return true
+		}
+	}
+	return false
+}
+
+// Locks returns the locks held and waited for by every session, sorted by table and session.
+func (m *MetadataLockManager) Locks() []MetadataLock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var all []MetadataLock
+	for key, locks := range m.locks {
+		for _, t := range locks.granted {
+			all = append(all, MetadataLock{Key: key, Type: t.typ, Duration: t.duration, Owner: t.owner, Granted: true})
+		}
+		for _, r := range locks.pending {
+			all = append(all, MetadataLock{Key: key, Type: r.typ, Duration: r.duration, Owner: r.owner})
+		}
+	}
+	sort.SliceStable(all, func(i, j int) bool {
+		if all[i].Key != all[j].Key {
+			if all[i].Key.Database != all[j].Key.Database {
+				return all[i].Key.Database < all[j].Key.Database
+			}
+			return all[i].Key.Table < all[j].Key.Table
+		}
+		if all[i].Owner != all[j].Owner {
+			return all[i].Owner < all[j].Owner
+		}
+		return all[i].Granted && !all[j].Granted
+	})
+	return all
+}
+
+// release removes the tickets of the owner given that match the predicate given.
+func (m *MetadataLockManager) release(owner uint32, matches func(*mdlTicket) bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var released bool
+	for key, locks := range m.locks {
+		granted := locks.granted[:0]
+		for _, t := range locks.granted {
+			if t.owner == owner && matches(t) {
+				released = true
+				if t.typ == MDLExclusive {
+					m.generation++
+					m.releasedAt[key] = m.generation
+				}
+				continue
+			}
+			granted = append(granted, t)
+		}
+		locks.granted = granted
+		m.dropUnused(key)
+	}
+	if released {
+		m.notify()
+	}
+}
+
+// blockers returns the sessions the request given waits for: those holding conflicting locks on its table and, unless
+// the session already holds a lock on the table, those with conflicting requests queued ahead of it.
+func (m *MetadataLockManager) blockers(req *mdlRequest) []uint32 {
+	locks := m.locks[req.key]
+	var blockers []uint32
+	var holdsLock bool
+	for _, t := range locks.granted {
+		if t.owner == req.owner {
+			holdsLock = true
+		} else if !req.typ.compatible(t.typ) {
+			blockers = append(blockers, t.owner)
+		}
+	}
+	if holdsLock {
+		return blockers
+	}
+	for _, r := range locks.pending {
+		if r == req {
+			break
+		}
+		if r.owner != req.owner && !req.typ.compatible(r.typ) {
+			blockers = append(blockers, r.owner)
+		}
+	}
+	return blockers
+}
+
+// deadlocks returns whether the session of the request given waits, directly or through other waiting sessions, for
+// itself.
+func (m *MetadataLockManager) deadlocks(req *mdlRequest) bool {
+	visited := make(map[uint32]bool)
+	var waitsFor func(r *mdlRequest) bool
+	waitsFor = func(r *mdlRequest) bool {
+		for _, owner := range m.blockers(r) {
+			if owner == req.owner {
+				return true
+			}
+			if visited[owner] {
+				continue
+			}
+			visited[owner] = true
+			if next, ok := m.waiting[owner]; ok && waitsFor(next) {
+				return true
+			}
+		}
+		return false
+	}
+	return waitsFor(req)
+}
+
+// stopWaiting removes the request given from the queue of its table.
+func (m *MetadataLockManager) stopWaiting(req *mdlRequest) {
+	delete(m.waiting, req.owner)
+	locks := m.locks[req.key]
+	for i, r := range locks.pending {
+		if r == req {
+			locks.pending = append(locks.pending[:i], locks.pending[i+1:]...)
+			break
+		}
+	}
+	m.notify()
+}
+
+// dropUnused forgets the table given once no session holds or waits for a lock on it.
+func (m *MetadataLockManager) dropUnused(key MDLKey) {
+	if locks, ok := m.locks[key]; ok && len(locks.granted) == 0 && len(locks.pending) == 0 {
+		delete(m.locks, key)
+	}
+}
+
+// notify wakes up every waiting session, so that it checks whether its lock can be granted.
+func (m *MetadataLockManager) notify() {
+	close(m.changed)
+	m.changed = make(chan struct{})
+}
+
+// holds returns whether the session of the request given already holds a lock at least as strong, and as long, as the
+// one requested.
+func (l *mdlLocks) holds(req *mdlRequest) bool {
+	for _, t := range l.granted {
+		if t.owner == req.owner && t.typ >= req.typ && t.duration >= req.duration {
+			return true
+		}
+	}
+	return false
+}
+
+// grant gives the session of the request given a ticket for its lock.
+func (l *mdlLocks) grant(req *mdlRequest) {
+	l.granted = append(l.granted, &mdlTicket{owner: req.owner, typ: req.typ, duration: req.duration})
+}
@@ -0,0 +1,199 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testLockTimeout = 50 * time.Millisecond
+
+func TestMDLCompatibility(t *testing.T) {
+	testCases := []struct {
+		held      MDLType
+		requested MDLType
+		granted   bool
+	}{
+		{MDLSharedRead, MDLSharedRead, true},
+		{MDLSharedRead, MDLSharedWrite, true},
+		{MDLSharedRead, MDLSharedUpgradable, true},
+		{MDLSharedRead, MDLExclusive, false},
+		{MDLSharedWrite, MDLSharedUpgradable, true},
+		{MDLSharedWrite, MDLExclusive, false},
+		{MDLSharedUpgradable, MDLSharedRead, true},
+		{MDLSharedUpgradable, MDLSharedWrite, true},
+		{MDLSharedUpgradable, MDLSharedUpgradable, false},
+		{MDLSharedUpgradable, MDLSharedNoReadWrite, false},
+		{MDLSharedNoReadWrite, MDLSharedRead, false},
+		{MDLSharedNoReadWrite, MDLSharedWrite, false},
+		{MDLSharedNoReadWrite, MDLSharedUpgradable, false},
+		{MDLSharedNoReadWrite, MDLSharedNoReadWrite, false},
+		{MDLSharedRead, MDLSharedNoReadWrite, false},
+		{MDLExclusive, MDLSharedRead, false},
+		{MDLExclusive, MDLSharedWrite, false},
+	}
+
+	key := NewMDLKey("mydb", "t")
+	for _, tt := range testCases {
+		t.Run(tt.held.String()+"/"+tt.requested.String(), func(t *testing.T) {
+			m := NewMetadataLockManager()
+			ctx1, ctx2 := NewEmptyContext(), NewEmptyContext()
+			require.NoError(t, m.Acquire(ctx1, key, tt.held, MDLStatement, testLockTimeout))
+			err := m.Acquire(ctx2, key, tt.requested, MDLStatement, testLockTimeout)
+			if tt.granted {
+				require.NoError(t, err)
+			} else {
+				require.True(t, ErrLockWaitTimeout.Is(err), "unexpected error %v", err)
+				// The request that timed out isn't left behind
+				require.Len(t, m.Locks(), 1)
+			}
+		})
+	}
+}
+
+func TestMDLUpgrade(t *testing.T) {
+	m := NewMetadataLockManager()
+	ctx1, ctx2 := NewEmptyContext(), NewEmptyContext()
+	key := NewMDLKey("MyDB", "T")
+
+	require.NoError(t, m.Acquire(ctx1, key, MDLSharedRead, MDLTransaction, testLockTimeout))
+	require.NoError(t, m.Acquire(ctx1, key, MDLSharedRead, MDLStatement, testLockTimeout))
+	require.Len(t, m.Locks(), 1)
+
+	require.NoError(t, m.Acquire(ctx1, key, MDLSharedUpgradable, MDLStatement, testLockTimeout))
+	require.NoError(t, m.Acquire(ctx1, key, MDLExclusive, MDLStatement, testLockTimeout))
+	require.Equal(t, []MetadataLock{
+		{Key: MDLKey{"mydb", "t"}, Type: MDLSharedRead, Duration: MDLTransaction, Owner: ctx1.ID(), Granted: true},
+		{Key: MDLKey{"mydb", "t"}, Type: MDLSharedUpgradable, Duration: MDLStatement, Owner: ctx1.ID(), Granted: true},
+		{Key: MDLKey{"mydb", "t"}, Type: MDLExclusive, Duration: MDLStatement, Owner: ctx1.ID(), Granted: true},
+	}, m.Locks())
+	require.Error(t, m.Acquire(ctx2, key, MDLSharedRead, MDLStatement, testLockTimeout))
+
+	// Once the statement is done, only the lock of the transaction is left
+	m.ReleaseStatementLocks(ctx1)
+	require.Equal(t, []MetadataLock{
+		{Key: MDLKey{"mydb", "t"}, Type: MDLSharedRead, Duration: MDLTransaction, Owner: ctx1.ID(), Granted: true},
+	}, m.Locks())
+	require.True(t, m.HasLocks(ctx1))
+	require.NoError(t, m.Acquire(ctx2, key, MDLSharedRead, MDLStatement, testLockTimeout))
+
+	m.ReleaseAll(ctx1)
+	m.ReleaseAll(ctx2)
+	require.False(t, m.HasLocks(ctx1))
+	require.Empty(t, m.Locks())
+}
+
+func TestMDLExplicitLocks(t *testing.T) {
+	m := NewMetadataLockManager()
+	ctx1, ctx2 := NewEmptyContext(), NewEmptyContext()
+	key := NewMDLKey("mydb", "t")
+
+	require.NoError(t, m.Acquire(ctx1, key, MDLSharedNoReadWrite, MDLExplicit, testLockTimeout))
+	// The session that locked the table keeps using it, and may change its definition
+	require.NoError(t, m.Acquire(ctx1, key, MDLSharedWrite, MDLTransaction, testLockTimeout))
+	require.NoError(t, m.Acquire(ctx1, key, MDLExclusive, MDLStatement, testLockTimeout))
+	require.True(t, ErrLockWaitTimeout.Is(m.Acquire(ctx2, key, MDLSharedRead, MDLStatement, testLockTimeout)))
+
+	// The end of a transaction doesn't unlock the table
+	m.ReleaseAll(ctx1)
+	require.Equal(t, []MetadataLock{
+		{Key: key, Type: MDLSharedNoReadWrite, Duration: MDLExplicit, Owner: ctx1.ID(), Granted: true},
+	}, m.Locks())
+	require.True(t, ErrLockWaitTimeout.Is(m.Acquire(ctx2, key, MDLSharedRead, MDLStatement, testLockTimeout)))
+
+	m.ReleaseExplicitLocks(ctx1.ID())
+	require.Empty(t, m.Locks())
+	require.NoError(t, m.Acquire(ctx2, key, MDLSharedRead, MDLStatement, testLockTimeout))
+}
+
+func TestMDLWait(t *testing.T) {
+	m := NewMetadataLockManager()
+	ctx1, ctx2, ctx3 := NewEmptyContext(), NewEmptyContext(), NewEmptyContext()
+	key := NewMDLKey("mydb", "t")
+
+	require.NoError(t, m.Acquire(ctx1, key, MDLSharedRead, MDLTransaction, testLockTimeout))
+
+	done := make(chan error)
+	go func() {
+		done <- m.Acquire(ctx2, key, MDLExclusive, MDLStatement, time.Minute)
+	}()
+	require.Eventually(t, func() bool {
+		return len(m.Locks()) == 2
+	}, time.Second, time.Millisecond)
+	require.Equal(t, MetadataLock{Key: key, Type: MDLExclusive, Duration: MDLStatement, Owner: ctx2.ID()}, m.Locks()[1])
+
+	// Sessions without a lock on the table queue behind the pending exclusive lock
+	require.True(t, ErrLockWaitTimeout.Is(m.Acquire(ctx3, key, MDLSharedRead, MDLStatement, testLockTimeout)))
+	// But the session holding a lock on it doesn't, or it would deadlock
+	require.NoError(t, m.Acquire(ctx1, key, MDLSharedWrite, MDLTransaction, testLockTimeout))
+
+	m.ReleaseAll(ctx1)
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(time.Second):
+		require.Fail(t, "exclusive lock wasn't granted once the shared lock was released")
+	}
+	require.Equal(t, []MetadataLock{
+		{Key: key, Type: MDLExclusive, Duration: MDLStatement, Owner: ctx2.ID(), Granted: true},
+	}, m.Locks())
+}
+
+func TestMDLDeadlock(t *testing.T) {
+	m := NewMetadataLockManager()
+	ctx1, ctx2 := NewEmptyContext(), NewEmptyContext()
+	t1, t2 := NewMDLKey("mydb", "t1"), NewMDLKey("mydb", "t2")
+
+	require.NoError(t, m.Acquire(ctx1, t1, MDLSharedRead, MDLTransaction, testLockTimeout))
+	require.NoError(t, m.Acquire(ctx2, t2, MDLSharedRead, MDLTransaction, testLockTimeout))
+
+	done := make(chan error)
+	go func() {
+		done <- m.Acquire(ctx1, t2, MDLExclusive, MDLStatement, time.Minute)
+	}()
+	require.Eventually(t, func() bool {
+		return len(m.Locks()) == 3
+	}, time.Second, time.Millisecond)
+
+	// The session that closes the cycle gets the error, while the other one keeps waiting
+	err := m.Acquire(ctx2, t1, MDLExclusive, MDLStatement, time.Minute)
+	require.True(t, ErrLockDeadlock.Is(err), "unexpected error %v", err)
+
+	m.ReleaseAll(ctx2)
+	require.NoError(t, <-done)
+}
+
+func TestMDLChangedSince(t *testing.T) {
+	m := NewMetadataLockManager()
+	ctx1, ctx2 := NewEmptyContext(), NewEmptyContext()
+	t1, t2 := NewMDLKey("mydb", "t1"), NewMDLKey("mydb", "t2")
+
+	generation := m.Generation()
+	require.NoError(t, m.Acquire(ctx1, t1, MDLSharedWrite, MDLStatement, testLockTimeout))
+	m.ReleaseAll(ctx1)
+	require.False(t, m.ChangedSince(generation, t1, t2))
+
+	// Releasing an exclusive lock marks its table as changed, but not the other tables
+	require.NoError(t, m.Acquire(ctx2, t1, MDLExclusive, MDLStatement, testLockTimeout))
+	require.False(t, m.ChangedSince(generation, t1))
+	m.ReleaseStatementLocks(ctx2)
+	require.True(t, m.ChangedSince(generation, t1))
+	require.True(t, m.ChangedSince(generation, t2, t1))
+	require.False(t, m.ChangedSince(generation, t2))
+	require.False(t, m.ChangedSince(m.Generation(), t1, t2))
+}
@@ -0,0 +1,275 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql_db
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/dolthub/vitess/go/mysql"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// accountResourceHour is the length of the window that the hourly account limits are counted over.
+const accountResourceHour = time.Hour
+
+// These are the error codes returned while authenticating an account that has been locked.
+const (
+	erAccountHasBeenLocked                      = 3118 // TODO: Needs to be added to vitess
+	erUserAccessDeniedForUserAccountBlockedByPW = 3955 // TODO: Needs to be added to vitess
+)
+
+// accountResources tracks the resources that each account has used, which are enforced against the account's limits.
+// None of this is persisted, which matches MySQL, as the counts are reset whenever the server restarts.
+type accountResources struct {
+	mu          sync.Mutex
+	accounts    map[UserPrimaryKey]*accountUsage
+	connections map[uint32]UserPrimaryKey
+	now         func() time.Time
+}
+
+// accountUsage is the resource usage of a single account.
+type accountUsage struct {
+	hourStart       time.Time
+	queries         uint32
+	updates         uint32
+	connections     uint32
+	userConnections uint32
+
+	failedLogins uint32
+	// lockedUntil is the time that a temporary lock from too many failed logins expires. A zero time means that the
+	// account is not locked, while lockedIndefinitely represents an unbounded lock.
+	lockedUntil        time.Time
+	lockedIndefinitely bool
+}
+
+func newAccountResources() *accountResources {
+	return &accountResources{
+		accounts:    make(map[UserPrimaryKey]*accountUsage),
+		connections: make(map[uint32]UserPrimaryKey),
+		now:         time.Now,
+	}
+}
+
+// usage returns the usage for the given account, resetting the hourly counts once the hour has elapsed. The mutex must
+// be held by the caller.
+func (r *accountResources) usage(key UserPrimaryKey) *accountUsage {
+	usage, ok := r.accounts[key]
+	if !ok {
+		usage = &accountUsage{hourStart: r.now()}
+		r.accounts[key] = usage
+	}
+	if now := r.now(); now.Sub(usage.hourStart) >= accountResourceHour {
+		usage.hourStart = now
+		usage.queries = 0
+		usage.updates = 0
+		usage.connections = 0
+	}
+	return usage
+}
+
+// AccountConnected records a new connection for the account that the given user and host authenticated as, returning
+// an error if the account has reached its connection limits. Calling this multiple times for the same connection
+// only records the connection once.
+func (db *MySQLDb) AccountConnected(connID uint32, user string, host string) error {
+	if !db.Enabled {
+		return nil
+	}
+	userEntry := db.GetUser(user, host, false)
+	if userEntry == nil {
+		return nil
+	}
+	key := UserPrimaryKey{Host: userEntry.Host, User: userEntry.User}
+
+	r := db.resources
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.connections[connID]; ok {
+		return nil
+	}
+	usage := r.usage(key)
+	maxUserConnections := uint64(userEntry.MaxUserConnections)
+	if maxUserConnections == 0 && sql.SystemVariables != nil {
+		// The global limit only applies to accounts that do not have their own limit
+		if _, val, ok := sql.SystemVariables.GetGlobal("max_user_connections"); ok {
+			if globalMax, ok := val.(int64); ok && globalMax > 0 {
+				maxUserConnections = uint64(globalMax)
+			}
+		}
+	}
+	if maxUserConnections > 0 && uint64(usage.userConnections) >= maxUserConnections {
+		return sql.ErrTooManyUserConnections.New(userEntry.User)
+	}
+	if userEntry.MaxConnections > 0 && usage.connections >= userEntry.MaxConnections {
+		return sql.ErrUserLimitReached.New(userEntry.User, "max_connections_per_hour", userEntry.MaxConnections)
+	}
+	usage.connections++
+	usage.userConnections++
+	r.connections[connID] = key
+	return nil
+}
+
+// AccountDisconnected records that the given connection has closed. Connections that were never recorded by
+// AccountConnected are ignored.
+func (db *MySQLDb) AccountDisconnected(connID uint32) {
+	r := db.resources
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	key, ok := r.connections[connID]
+	if !ok {
+		return
+	}
+	delete(r.connections, connID)
+	if usage, ok := r.accounts[key]; ok && usage.userConnections > 0 {
+		usage.userConnections--
+	}
+}
+
+// AccountQueried records a statement run by the account that the given user and host authenticated as, returning an
+// error if the account has reached its hourly query limits. Statements that modify data count as both a query and an
+// update.
+func (db *MySQLDb) AccountQueried(user string, host string, isUpdate bool) error {
+	if !db.Enabled {
+		return nil
+	}
+	userEntry := db.GetUser(user, host, false)
+	if userEntry == nil || (userEntry.MaxQuestions == 0 && userEntry.MaxUpdates == 0) {
+		return nil
+	}
+	key := UserPrimaryKey{Host: userEntry.Host, User: userEntry.User}
+
+	r := db.resources
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usage := r.usage(key)
+	if userEntry.MaxQuestions > 0 && usage.queries >= userEntry.MaxQuestions {
+		return sql.ErrUserLimitReached.New(userEntry.User, "max_questions", userEntry.MaxQuestions)
+	}
+	if isUpdate && userEntry.MaxUpdates > 0 && usage.updates >= userEntry.MaxUpdates {
+		return sql.ErrUserLimitReached.New(userEntry.User, "max_updates", userEntry.MaxUpdates)
+	}
+	usage.queries++
+	if isUpdate {
+		usage.updates++
+	}
+	return nil
+}
+
+// ResetFailedLogins clears the consecutive failed login count for the given account, along with any temporary lock
+// that resulted from it.
+func (db *MySQLDb) ResetFailedLogins(user string, host string) {
+	r := db.resources
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if usage, ok := r.accounts[UserPrimaryKey{Host: host, User: user}]; ok {
+		usage.failedLogins = 0
+		usage.lockedUntil = time.Time{}
+		usage.lockedIndefinitely = false
+	}
+}
+
+// checkAccountLogin returns an error if the given account may not log in, due to the account being locked, either
+// explicitly or temporarily after too many failed logins.
+func (db *MySQLDb) checkAccountLogin(userEntry *User) error {
+	if userEntry.Locked {
+		return mysql.NewSQLError(erAccountHasBeenLocked, mysql.SSAccessDeniedError,
+			"Access denied for user '%v'@'%v'. Account is locked.", userEntry.User, userEntry.Host)
+	}
+	r := db.resources
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usage, ok := r.accounts[UserPrimaryKey{Host: userEntry.Host, User: userEntry.User}]
+	if !ok {
+		return nil
+	}
+	if usage.lockedIndefinitely || (!usage.lockedUntil.IsZero() && r.now().Before(usage.lockedUntil)) {
+		return accountBlockedError(userEntry, usage, r.now())
+	}
+	return nil
+}
+
+// accountLoginFailed records a failed login for the given account, temporarily locking the account once it has
+// reached its limit of consecutive failed logins. Returns the error that should be given to the client.
+func (db *MySQLDb) accountLoginFailed(userEntry *User, loginErr error) error {
+	if userEntry.FailedLoginAttempts == 0 || userEntry.PasswordLockTime == 0 {
+		return loginErr
+	}
+	r := db.resources
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usage := r.usage(UserPrimaryKey{Host: userEntry.Host, User: userEntry.User})
+	// A temporary lock that has expired starts the count over
+	if !usage.lockedUntil.IsZero() && !r.now().Before(usage.lockedUntil) {
+		usage.failedLogins = 0
+		usage.lockedUntil = time.Time{}
+	}
+	usage.failedLogins++
+	if usage.failedLogins < userEntry.FailedLoginAttempts {
+		return loginErr
+	}
+	if userEntry.PasswordLockTime < 0 {
+		usage.lockedIndefinitely = true
+	} else {
+		usage.lockedUntil = r.now().Add(time.Duration(userEntry.PasswordLockTime) * 24 * time.Hour)
+	}
+	return accountBlockedError(userEntry, usage, r.now())
+}
+
+// accountLoginSucceeded resets the consecutive failed login count for the given account.
+func (db *MySQLDb) accountLoginSucceeded(userEntry *User) {
+	r := db.resources
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if usage, ok := r.accounts[UserPrimaryKey{Host: userEntry.Host, User: userEntry.User}]; ok {
+		usage.failedLogins = 0
+		usage.lockedUntil = time.Time{}
+	}
+}
+
+// accountBlockedError returns the error for an account that has been temporarily locked after too many failed logins.
+func accountBlockedError(userEntry *User, usage *accountUsage, now time.Time) error {
+	lockDays := "unlimited"
+	remainingDays := "unlimited"
+	if !usage.lockedIndefinitely {
+		lockDays = fmt.Sprintf("%d", userEntry.PasswordLockTime)
+		remaining := usage.lockedUntil.Sub(now)
+		remainingDays = fmt.Sprintf("%d", (remaining+24*time.Hour-1)/(24*time.Hour))
+	}
+	return mysql.NewSQLError(erUserAccessDeniedForUserAccountBlockedByPW, mysql.SSAccessDeniedError,
+		"Access denied for user '%v'@'%v'. Account is blocked for %s day(s) (%s day(s) remaining) due to %d consecutive failed logins.",
+		userEntry.User, userEntry.Host, lockDays, remainingDays, usage.failedLogins)
+}
+
+// IsPasswordExpired returns whether the given user's password has expired, either explicitly or because the password's
+// lifetime has elapsed. Users with an expired password may only reset their password.
+func (db *MySQLDb) IsPasswordExpired(userEntry *User) bool {
+	if userEntry.PasswordExpired {
+		return true
+	}
+	var lifetime int64
+	if userEntry.PasswordLifetime != nil {
+		lifetime = int64(*userEntry.PasswordLifetime)
+	} else if sql.SystemVariables != nil {
+		if _, val, ok := sql.SystemVariables.GetGlobal("default_password_lifetime"); ok {
+			lifetime, _ = val.(int64)
+		}
+	}
+	if lifetime <= 0 || userEntry.PasswordLastChanged.IsZero() {
+		return false
+	}
+	return db.resources.now().After(userEntry.PasswordLastChanged.Add(time.Duration(lifetime) * 24 * time.Hour))
+}
@@ -0,0 +1,133 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql_db
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	"github.com/dolthub/vitess/go/mysql"
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+func newAccountResourcesTestDb(t *testing.T) (*MySQLDb, *User, *time.Time) {
+	db := CreateEmptyMySQLDb()
+	db.AddSuperUser("tester", "localhost", "")
+	user := db.GetUser("tester", "localhost", false)
+	require.NotNil(t, user)
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	db.resources.now = func() time.Time { return now }
+	return db, user, &now
+}
+
+func TestAccountConnectionLimits(t *testing.T) {
+	db, user, now := newAccountResourcesTestDb(t)
+	user.MaxUserConnections = 2
+	user.MaxConnections = 3
+
+	require.NoError(t, db.AccountConnected(1, "tester", "localhost"))
+	// The same connection is only counted once
+	require.NoError(t, db.AccountConnected(1, "tester", "localhost"))
+	require.NoError(t, db.AccountConnected(2, "tester", "localhost"))
+	err := db.AccountConnected(3, "tester", "localhost")
+	require.True(t, sql.ErrTooManyUserConnections.Is(err))
+
+	db.AccountDisconnected(1)
+	require.NoError(t, db.AccountConnected(3, "tester", "localhost"))
+	db.AccountDisconnected(2)
+	err = db.AccountConnected(4, "tester", "localhost")
+	require.True(t, sql.ErrUserLimitReached.Is(err))
+
+	// The hourly count starts over once the hour has elapsed
+	*now = now.Add(time.Hour)
+	require.NoError(t, db.AccountConnected(4, "tester", "localhost"))
+}
+
+func TestAccountQueryLimits(t *testing.T) {
+	db, user, now := newAccountResourcesTestDb(t)
+	user.MaxQuestions = 3
+	user.MaxUpdates = 1
+
+	require.NoError(t, db.AccountQueried("tester", "localhost", true))
+	err := db.AccountQueried("tester", "localhost", true)
+	require.True(t, sql.ErrUserLimitReached.Is(err))
+	require.NoError(t, db.AccountQueried("tester", "localhost", false))
+	require.NoError(t, db.AccountQueried("tester", "localhost", false))
+	err = db.AccountQueried("tester", "localhost", false)
+	require.True(t, sql.ErrUserLimitReached.Is(err))
+
+	*now = now.Add(time.Hour)
+	require.NoError(t, db.AccountQueried("tester", "localhost", true))
+}
+
+func TestAccountFailedLoginLocking(t *testing.T) {
+	db, user, now := newAccountResourcesTestDb(t)
+	user.FailedLoginAttempts = 2
+	user.PasswordLockTime = 1
+	addr := &net.UnixAddr{Net: "unix"}
+	salt, err := db.Salt()
+	require.NoError(t, err)
+	requireLoginErrorCode := func(expectedCode int, authResponse []byte) {
+		_, err := db.ValidateHash(salt, "tester", authResponse, addr)
+		require.Error(t, err)
+		sqlErr, ok := err.(*mysql.SQLError)
+		require.True(t, ok)
+		require.Equal(t, expectedCode, sqlErr.Number())
+	}
+
+	// The account has no password, so giving one fails
+	requireLoginErrorCode(mysql.ERAccessDeniedError, []byte("wrong"))
+	requireLoginErrorCode(erUserAccessDeniedForUserAccountBlockedByPW, []byte("wrong"))
+	// The account stays blocked even when the correct password is given
+	requireLoginErrorCode(erUserAccessDeniedForUserAccountBlockedByPW, nil)
+
+	*now = now.Add(24 * time.Hour)
+	_, err = db.ValidateHash(salt, "tester", nil, addr)
+	require.NoError(t, err)
+
+	// An unbounded lock lasts until the failed logins are reset
+	user.PasswordLockTime = -1
+	requireLoginErrorCode(mysql.ERAccessDeniedError, []byte("wrong"))
+	requireLoginErrorCode(erUserAccessDeniedForUserAccountBlockedByPW, []byte("wrong"))
+	*now = now.Add(365 * 24 * time.Hour)
+	requireLoginErrorCode(erUserAccessDeniedForUserAccountBlockedByPW, nil)
+	db.ResetFailedLogins("tester", "localhost")
+	_, err = db.ValidateHash(salt, "tester", nil, addr)
+	require.NoError(t, err)
+
+	user.Locked = true
+	requireLoginErrorCode(erAccountHasBeenLocked, nil)
+}
+
+func TestPasswordExpiration(t *testing.T) {
+	db, user, now := newAccountResourcesTestDb(t)
+	user.PasswordLastChanged = *now
+	require.False(t, db.IsPasswordExpired(user))
+
+	lifetime := uint16(10)
+	user.PasswordLifetime = &lifetime
+	*now = now.Add(9 * 24 * time.Hour)
+	require.False(t, db.IsPasswordExpired(user))
+	*now = now.Add(2 * 24 * time.Hour)
+	require.True(t, db.IsPasswordExpired(user))
+
+	lifetime = 0
+	require.False(t, db.IsPasswordExpired(user))
+	user.PasswordExpired = true
+	require.True(t, db.IsPasswordExpired(user))
+}
@@ -0,0 +1,269 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql_db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/in_mem_table"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+const columnsPrivTblName = "columns_priv"
+
+var (
+	errColumnsPrivEntry = fmt.Errorf("the converter for the `columns_priv` table was given an unknown entry")
+	errColumnsPrivRow   = fmt.Errorf("the converter for the `columns_priv` table was given a row belonging to an unknown schema")
+
+	columnsPrivTblSchema sql.Schema
+)
+
+// ColumnsPrivConverter handles the conversion between a stored *User entry and the faux "columns_priv" Grant Table.
+type ColumnsPrivConverter struct{}
+
+var _ in_mem_table.DataEditorConverter = ColumnsPrivConverter{}
+
+// RowToKey implements the interface in_mem_table.DataEditorConverter.
+func (conv ColumnsPrivConverter) RowToKey(ctx *sql.Context, row sql.Row) (in_mem_table.Key, error) {
+	if len(row) != len(columnsPrivTblSchema) {
+		return nil, errColumnsPrivRow
+	}
+	host, ok := row[columnsPrivTblColIndex_Host].(string)
+	if !ok {
+		return nil, errColumnsPrivRow
+	}
+	user, ok := row[columnsPrivTblColIndex_User].(string)
+	if !ok {
+		return nil, errColumnsPrivRow
+	}
+	return UserPrimaryKey{
+		Host: host,
+		User: user,
+	}, nil
+}
+
+// AddRowToEntry implements the interface in_mem_table.DataEditorConverter.
+func (conv ColumnsPrivConverter) AddRowToEntry(ctx *sql.Context, row sql.Row, entry in_mem_table.Entry) (in_mem_table.Entry, error) {
+	if len(row) != len(columnsPrivTblSchema) {
+		return nil, errColumnsPrivRow
+	}
+	user, ok := entry.(*User)
+	if !ok {
+		return nil, errColumnsPrivEntry
+	}
+	user = user.Copy(ctx).(*User)
+
+	dbName, ok := row[columnsPrivTblColIndex_Db].(string)
+	if !ok {
+		return nil, errColumnsPrivRow
+	}
+	tblName, ok := row[columnsPrivTblColIndex_Table_name].(string)
+	if !ok {
+		return nil, errColumnsPrivRow
+	}
+	colName, ok := row[columnsPrivTblColIndex_Column_name].(string)
+	if !ok {
+		return nil, errColumnsPrivRow
+	}
+	columnPrivs, ok := row[columnsPrivTblColIndex_Column_priv].(uint64)
+	if !ok {
+		return nil, errColumnsPrivRow
+	}
+	columnPrivStrs, err := columnsPrivTblSchema[columnsPrivTblColIndex_Column_priv].Type.(sql.SetType).BitsToString(columnPrivs)
+	if err != nil {
+		return nil, err
+	}
+	privs, err := columnPrivsFromSetString(columnPrivStrs)
+	if err != nil {
+		return nil, errColumnsPrivRow
+	}
+	user.PrivilegeSet.AddColumn(dbName, tblName, colName, privs...)
+	return user, nil
+}
+
+// RemoveRowFromEntry implements the interface in_mem_table.DataEditorConverter.
+func (conv ColumnsPrivConverter) RemoveRowFromEntry(ctx *sql.Context, row sql.Row, entry in_mem_table.Entry) (in_mem_table.Entry, error) {
+	if len(row) != len(columnsPrivTblSchema) {
+		return nil, errColumnsPrivRow
+	}
+	user, ok := entry.(*User)
+	if !ok {
+		return nil, errColumnsPrivEntry
+	}
+	user = user.Copy(ctx).(*User)
+
+	db, ok := row[columnsPrivTblColIndex_Db].(string)
+	if !ok {
+		return nil, errColumnsPrivRow
+	}
+	tbl, ok := row[columnsPrivTblColIndex_Table_name].(string)
+	if !ok {
+		return nil, errColumnsPrivRow
+	}
+	col, ok := row[columnsPrivTblColIndex_Column_name].(string)
+	if !ok {
+		return nil, errColumnsPrivRow
+	}
+	user.PrivilegeSet.ClearColumn(db, tbl, col)
+	return user, nil
+}
+
+// EntryToRows implements the interface in_mem_table.DataEditorConverter.
+func (conv ColumnsPrivConverter) EntryToRows(ctx *sql.Context, entry in_mem_table.Entry) ([]sql.Row, error) {
+	user, ok := entry.(*User)
+	if !ok {
+		return nil, errColumnsPrivEntry
+	}
+
+	var rows []sql.Row
+	for _, dbSet := range user.PrivilegeSet.GetDatabases() {
+		for _, tblSet := range dbSet.GetTables() {
+			for _, colSet := range tblSet.GetColumns() {
+				row := make(sql.Row, len(columnsPrivTblSchema))
+				var err error
+				for i, col := range columnsPrivTblSchema {
+					row[i], err = col.Default.Eval(ctx, nil)
+					if err != nil {
+						return nil, err // Should never happen, schema is static
+					}
+				}
+				row[columnsPrivTblColIndex_User] = user.User
+				row[columnsPrivTblColIndex_Host] = user.Host
+				row[columnsPrivTblColIndex_Db] = dbSet.Name()
+				row[columnsPrivTblColIndex_Table_name] = tblSet.Name()
+				row[columnsPrivTblColIndex_Column_name] = colSet.Name()
+
+				formattedSet, err := columnsPrivTblSchema[columnsPrivTblColIndex_Column_priv].Type.Convert(columnPrivsToSetString(colSet.ToSlice()))
+				if err != nil {
+					return nil, err
+				}
+				row[columnsPrivTblColIndex_Column_priv] = formattedSet.(uint64)
+				rows = append(rows, row)
+			}
+		}
+	}
+
+	return rows, nil
+}
+
+// columnPrivsFromSetString returns the privileges represented by the given value of a column privilege set, which is
+// used by both the "columns_priv" and "tables_priv" Grant Tables.
+func columnPrivsFromSetString(setStr string) ([]sql.PrivilegeType, error) {
+	var privs []sql.PrivilegeType
+	for _, val := range strings.Split(setStr, ",") {
+		switch val {
+		case "Select":
+			privs = append(privs, sql.PrivilegeType_Select)
+		case "Insert":
+			privs = append(privs, sql.PrivilegeType_Insert)
+		case "Update":
+			privs = append(privs, sql.PrivilegeType_Update)
+		case "References":
+			privs = append(privs, sql.PrivilegeType_References)
+		case "":
+		default:
+			return nil, fmt.Errorf("unknown column privilege: %s", val)
+		}
+	}
+	return privs, nil
+}
+
+// columnPrivsToSetString returns the value of a column privilege set for the given privileges. Privileges that cannot
+// be granted on columns are skipped.
+func columnPrivsToSetString(privs []sql.PrivilegeType) string {
+	var privStrs []string
+	for _, priv := range privs {
+		switch priv {
+		case sql.PrivilegeType_Select:
+			privStrs = append(privStrs, "Select")
+		case sql.PrivilegeType_Insert:
+			privStrs = append(privStrs, "Insert")
+		case sql.PrivilegeType_Update:
+			privStrs = append(privStrs, "Update")
+		case sql.PrivilegeType_References:
+			privStrs = append(privStrs, "References")
+		}
+	}
+	return strings.Join(privStrs, ",")
+}
+
+// init creates the schema for the "columns_priv" Grant Table.
+func init() {
+	// Types
+	char32_utf8_bin := types.MustCreateString(sqltypes.Char, 32, sql.Collation_utf8_bin)
+	char64_utf8_bin := types.MustCreateString(sqltypes.Char, 64, sql.Collation_utf8_bin)
+	char64_utf8_general_ci := types.MustCreateString(sqltypes.Char, 64, sql.Collation_utf8_general_ci)
+	char255_ascii_general_ci := types.MustCreateString(sqltypes.Char, 255, sql.Collation_ascii_general_ci)
+	set_ColumnPrivs_utf8_general_ci := types.MustCreateSetType([]string{"Select", "Insert", "Update", "References"}, sql.Collation_utf8_general_ci)
+
+	// Column Templates
+	char32_utf8_bin_not_null_default_empty := &sql.Column{
+		Type:     char32_utf8_bin,
+		Default:  mustDefault(expression.NewLiteral("", char32_utf8_bin), char32_utf8_bin, true, false),
+		Nullable: false,
+	}
+	char64_utf8_bin_not_null_default_empty := &sql.Column{
+		Type:     char64_utf8_bin,
+		Default:  mustDefault(expression.NewLiteral("", char64_utf8_bin), char64_utf8_bin, true, false),
+		Nullable: false,
+	}
+	char64_utf8_general_ci_not_null_default_empty := &sql.Column{
+		Type:     char64_utf8_general_ci,
+		Default:  mustDefault(expression.NewLiteral("", char64_utf8_general_ci), char64_utf8_general_ci, true, false),
+		Nullable: false,
+	}
+	char255_ascii_general_ci_not_null_default_empty := &sql.Column{
+		Type:     char255_ascii_general_ci,
+		Default:  mustDefault(expression.NewLiteral("", char255_ascii_general_ci), char255_ascii_general_ci, true, false),
+		Nullable: false,
+	}
+	set_ColumnPrivs_utf8_general_ci_not_null_default_empty := &sql.Column{
+		Type:     set_ColumnPrivs_utf8_general_ci,
+		Default:  mustDefault(expression.NewLiteral("", set_ColumnPrivs_utf8_general_ci), set_ColumnPrivs_utf8_general_ci, true, false),
+		Nullable: false,
+	}
+	timestamp_not_null_default_epoch := &sql.Column{
+		Type:     types.Timestamp,
+		Default:  mustDefault(expression.NewLiteral(time.Unix(1, 0).UTC(), types.Timestamp), types.Timestamp, true, false),
+		Nullable: false,
+	}
+
+	columnsPrivTblSchema = sql.Schema{
+		columnTemplate("Host", columnsPrivTblName, true, char255_ascii_general_ci_not_null_default_empty),
+		columnTemplate("Db", columnsPrivTblName, true, char64_utf8_bin_not_null_default_empty),
+		columnTemplate("User", columnsPrivTblName, true, char32_utf8_bin_not_null_default_empty),
+		columnTemplate("Table_name", columnsPrivTblName, true, char64_utf8_bin_not_null_default_empty),
+		columnTemplate("Column_name", columnsPrivTblName, true, char64_utf8_general_ci_not_null_default_empty),
+		columnTemplate("Timestamp", columnsPrivTblName, false, timestamp_not_null_default_epoch),
+		columnTemplate("Column_priv", columnsPrivTblName, false, set_ColumnPrivs_utf8_general_ci_not_null_default_empty),
+	}
+}
+
+// These represent the column indexes of the "columns_priv" Grant Table.
+const (
+	columnsPrivTblColIndex_Host int = iota
+	columnsPrivTblColIndex_Db
+	columnsPrivTblColIndex_User
+	columnsPrivTblColIndex_Table_name
+	columnsPrivTblColIndex_Column_name
+	columnsPrivTblColIndex_Timestamp
+	columnsPrivTblColIndex_Column_priv
+)
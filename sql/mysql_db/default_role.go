@@ -0,0 +1,94 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql_db
+
+import (
+	"encoding/json"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/in_mem_table"
+)
+
+// DefaultRole represents a user's default role from the default_roles Grant Table. Default roles are the roles that
+// are activated by SET ROLE DEFAULT.
+type DefaultRole struct {
+	Host            string
+	User            string
+	DefaultRoleHost string
+	DefaultRoleUser string
+}
+
+var _ in_mem_table.Entry = (*DefaultRole)(nil)
+
+// NewFromRow implements the interface in_mem_table.Entry.
+func (r *DefaultRole) NewFromRow(ctx *sql.Context, row sql.Row) (in_mem_table.Entry, error) {
+	if err := defaultRolesTblSchema.CheckRow(row); err != nil {
+		return nil, err
+	}
+	return &DefaultRole{
+		Host:            row[defaultRolesTblColIndex_HOST].(string),
+		User:            row[defaultRolesTblColIndex_USER].(string),
+		DefaultRoleHost: row[defaultRolesTblColIndex_DEFAULT_ROLE_HOST].(string),
+		DefaultRoleUser: row[defaultRolesTblColIndex_DEFAULT_ROLE_USER].(string),
+	}, nil
+}
+
+// UpdateFromRow implements the interface in_mem_table.Entry.
+func (r *DefaultRole) UpdateFromRow(ctx *sql.Context, row sql.Row) (in_mem_table.Entry, error) {
+	return r.NewFromRow(ctx, row)
+}
+
+// ToRow implements the interface in_mem_table.Entry.
+func (r *DefaultRole) ToRow(ctx *sql.Context) sql.Row {
+	row := make(sql.Row, len(defaultRolesTblSchema))
+	row[defaultRolesTblColIndex_HOST] = r.Host
+	row[defaultRolesTblColIndex_USER] = r.User
+	row[defaultRolesTblColIndex_DEFAULT_ROLE_HOST] = r.DefaultRoleHost
+	row[defaultRolesTblColIndex_DEFAULT_ROLE_USER] = r.DefaultRoleUser
+	return row
+}
+
+// Equals implements the interface in_mem_table.Entry.
+func (r *DefaultRole) Equals(ctx *sql.Context, otherEntry in_mem_table.Entry) bool {
+	otherDefaultRole, ok := otherEntry.(*DefaultRole)
+	if !ok {
+		return false
+	}
+	return *r == *otherDefaultRole
+}
+
+// Copy implements the interface in_mem_table.Entry.
+func (r *DefaultRole) Copy(ctx *sql.Context) in_mem_table.Entry {
+	rr := *r
+	return &rr
+}
+
+// FromJson implements the interface in_mem_table.Entry.
+func (r *DefaultRole) FromJson(ctx *sql.Context, jsonStr string) (in_mem_table.Entry, error) {
+	newDefaultRole := &DefaultRole{}
+	if err := json.Unmarshal([]byte(jsonStr), newDefaultRole); err != nil {
+		return nil, err
+	}
+	return newDefaultRole, nil
+}
+
+// ToJson implements the interface in_mem_table.Entry.
+func (r *DefaultRole) ToJson(ctx *sql.Context) (string, error) {
+	jsonData, err := json.Marshal(*r)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonData), nil
+}
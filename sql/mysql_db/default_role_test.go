@@ -0,0 +1,81 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql_db
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// This test enforces not only that DefaultRole round trips, but that the output is as expected.
+func TestDefaultRoleJson(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+	testDefaultRole := &DefaultRole{
+		Host:            "localhost",
+		User:            "some_user",
+		DefaultRoleHost: "%",
+		DefaultRoleUser: "some_role",
+	}
+	jsonStr, err := testDefaultRole.ToJson(ctx)
+	require.NoError(t, err)
+	require.Equal(t, `{"Host":"localhost","User":"some_user","DefaultRoleHost":"%","DefaultRoleUser":"some_role"}`, jsonStr)
+	newDefaultRole, err := (&DefaultRole{}).FromJson(ctx, jsonStr)
+	require.NoError(t, err)
+	require.True(t, testDefaultRole.Equals(ctx, newDefaultRole))
+}
+
+type bufferPersister struct {
+	data []byte
+}
+
+func (p *bufferPersister) Persist(ctx *sql.Context, data []byte) error {
+	p.data = data
+	return nil
+}
+
+func TestDefaultRolesPersistence(t *testing.T) {
+	ctx := sql.NewEmptyContext()
+	db := CreateEmptyMySQLDb()
+	persister := &bufferPersister{}
+	db.SetPersister(persister)
+	user := &User{User: "tester", Host: "localhost", PrivilegeSet: NewPrivilegeSet()}
+	role := &User{User: "some_role", Host: "%", PrivilegeSet: NewPrivilegeSet(), IsRole: true}
+	require.NoError(t, db.UserTable().Data().Put(ctx, user))
+	require.NoError(t, db.UserTable().Data().Put(ctx, role))
+	require.NoError(t, db.RoleEdgesTable().Data().Put(ctx, &RoleEdge{
+		FromHost:        role.Host,
+		FromUser:        role.User,
+		ToHost:          user.Host,
+		ToUser:          user.User,
+		WithAdminOption: true,
+	}))
+	require.NoError(t, db.SetDefaultRoles(ctx, user, []*User{role}))
+	require.NoError(t, db.Persist(ctx))
+
+	loadedDb := CreateEmptyMySQLDb()
+	require.NoError(t, loadedDb.LoadData(ctx, persister.data))
+	loadedUser := loadedDb.GetUser("tester", "localhost", false)
+	require.NotNil(t, loadedUser)
+	require.True(t, loadedDb.IsDefaultRole(loadedUser, "some_role", "%"))
+	roleEdges := loadedDb.GrantedRoles(loadedUser)
+	require.Len(t, roleEdges, 1)
+	require.True(t, roleEdges[0].WithAdminOption)
+
+	require.NoError(t, loadedDb.RemoveDefaultRoles(ctx, role))
+	require.False(t, loadedDb.IsDefaultRole(loadedUser, "some_role", "%"))
+}
@@ -0,0 +1,167 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql_db
+
+import (
+	"fmt"
+
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/in_mem_table"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+const defaultRolesTblName = "default_roles"
+
+var (
+	errDefaultRolePkEntry = fmt.Errorf("the primary key for the `default_roles` table was given an unknown entry")
+	errDefaultRolePkRow   = fmt.Errorf("the primary key for the `default_roles` table was given a row belonging to an unknown schema")
+	errDefaultRoleUkEntry = fmt.Errorf("the `user` secondary key for the `default_roles` table was given an unknown entry")
+	errDefaultRoleUkRow   = fmt.Errorf("the `user` secondary key for the `default_roles` table was given a row belonging to an unknown schema")
+
+	defaultRolesTblSchema sql.Schema
+)
+
+// DefaultRolesPrimaryKey is a key that represents the primary key for the "default_roles" Grant Table.
+type DefaultRolesPrimaryKey struct {
+	Host            string
+	User            string
+	DefaultRoleHost string
+	DefaultRoleUser string
+}
+
+// DefaultRolesUserKey is a secondary key that represents the user columns on the "default_roles" Grant Table.
+type DefaultRolesUserKey struct {
+	Host string
+	User string
+}
+
+var _ in_mem_table.Key = DefaultRolesPrimaryKey{}
+var _ in_mem_table.Key = DefaultRolesUserKey{}
+
+// KeyFromEntry implements the interface in_mem_table.Key.
+func (k DefaultRolesPrimaryKey) KeyFromEntry(ctx *sql.Context, entry in_mem_table.Entry) (in_mem_table.Key, error) {
+	defaultRole, ok := entry.(*DefaultRole)
+	if !ok {
+		return nil, errDefaultRolePkEntry
+	}
+	return DefaultRolesPrimaryKey{
+		Host:            defaultRole.Host,
+		User:            defaultRole.User,
+		DefaultRoleHost: defaultRole.DefaultRoleHost,
+		DefaultRoleUser: defaultRole.DefaultRoleUser,
+	}, nil
+}
+
+// KeyFromRow implements the interface in_mem_table.Key.
+func (k DefaultRolesPrimaryKey) KeyFromRow(ctx *sql.Context, row sql.Row) (in_mem_table.Key, error) {
+	if len(row) != len(defaultRolesTblSchema) {
+		return k, errDefaultRolePkRow
+	}
+	host, ok := row[defaultRolesTblColIndex_HOST].(string)
+	if !ok {
+		return k, errDefaultRolePkRow
+	}
+	user, ok := row[defaultRolesTblColIndex_USER].(string)
+	if !ok {
+		return k, errDefaultRolePkRow
+	}
+	defaultRoleHost, ok := row[defaultRolesTblColIndex_DEFAULT_ROLE_HOST].(string)
+	if !ok {
+		return k, errDefaultRolePkRow
+	}
+	defaultRoleUser, ok := row[defaultRolesTblColIndex_DEFAULT_ROLE_USER].(string)
+	if !ok {
+		return k, errDefaultRolePkRow
+	}
+	return DefaultRolesPrimaryKey{
+		Host:            host,
+		User:            user,
+		DefaultRoleHost: defaultRoleHost,
+		DefaultRoleUser: defaultRoleUser,
+	}, nil
+}
+
+// KeyFromEntry implements the interface in_mem_table.Key.
+func (k DefaultRolesUserKey) KeyFromEntry(ctx *sql.Context, entry in_mem_table.Entry) (in_mem_table.Key, error) {
+	defaultRole, ok := entry.(*DefaultRole)
+	if !ok {
+		return nil, errDefaultRoleUkEntry
+	}
+	return DefaultRolesUserKey{
+		Host: defaultRole.Host,
+		User: defaultRole.User,
+	}, nil
+}
+
+// KeyFromRow implements the interface in_mem_table.Key.
+func (k DefaultRolesUserKey) KeyFromRow(ctx *sql.Context, row sql.Row) (in_mem_table.Key, error) {
+	if len(row) != len(defaultRolesTblSchema) {
+		return k, errDefaultRoleUkRow
+	}
+	host, ok := row[defaultRolesTblColIndex_HOST].(string)
+	if !ok {
+		return k, errDefaultRoleUkRow
+	}
+	user, ok := row[defaultRolesTblColIndex_USER].(string)
+	if !ok {
+		return k, errDefaultRoleUkRow
+	}
+	return DefaultRolesUserKey{
+		Host: host,
+		User: user,
+	}, nil
+}
+
+// init creates the schema for the "default_roles" Grant Table.
+func init() {
+	// Types
+	char32_utf8_bin := types.MustCreateString(sqltypes.Char, 32, sql.Collation_utf8_bin)
+	char255_ascii_general_ci := types.MustCreateString(sqltypes.Char, 255, sql.Collation_ascii_general_ci)
+
+	// Column Templates
+	char32_utf8_bin_not_null_default_empty := &sql.Column{
+		Type:     char32_utf8_bin,
+		Default:  mustDefault(expression.NewLiteral("", char32_utf8_bin), char32_utf8_bin, true, false),
+		Nullable: false,
+	}
+	char255_ascii_general_ci_not_null_default_empty := &sql.Column{
+		Type:     char255_ascii_general_ci,
+		Default:  mustDefault(expression.NewLiteral("", char255_ascii_general_ci), char255_ascii_general_ci, true, false),
+		Nullable: false,
+	}
+	char255_ascii_general_ci_not_null_default_percent := &sql.Column{
+		Type:     char255_ascii_general_ci,
+		Default:  mustDefault(expression.NewLiteral("%", char255_ascii_general_ci), char255_ascii_general_ci, true, false),
+		Nullable: false,
+	}
+
+	defaultRolesTblSchema = sql.Schema{
+		columnTemplate("HOST", defaultRolesTblName, true, char255_ascii_general_ci_not_null_default_empty),
+		columnTemplate("USER", defaultRolesTblName, true, char32_utf8_bin_not_null_default_empty),
+		columnTemplate("DEFAULT_ROLE_HOST", defaultRolesTblName, true, char255_ascii_general_ci_not_null_default_percent),
+		columnTemplate("DEFAULT_ROLE_USER", defaultRolesTblName, true, char32_utf8_bin_not_null_default_empty),
+	}
+}
+
+// These represent the column indexes of the "default_roles" Grant Table.
+const (
+	defaultRolesTblColIndex_HOST int = iota
+	defaultRolesTblColIndex_USER
+	defaultRolesTblColIndex_DEFAULT_ROLE_HOST
+	defaultRolesTblColIndex_DEFAULT_ROLE_USER
+)
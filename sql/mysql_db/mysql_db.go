@@ -56,20 +56,21 @@ type MySQLDb struct {
 
 	user                *mysqlTable
 	role_edges          *mysqlTable
+	default_roles       *mysqlTable
 	replica_source_info *mysqlTable
 
 	db            *mysqlTableShim
 	tables_priv   *mysqlTableShim
+	columns_priv  *mysqlTableShim
+	procs_priv    *mysqlTableShim
 	global_grants *mysqlTableShim
 	//TODO: add the rest of these tables
-	//columns_priv     *mysqlTable
-	//procs_priv       *mysqlTable
 	//proxies_priv     *mysqlTable
-	//default_roles    *mysqlTable
 	//password_history *mysqlTable
 
 	persister MySQLDbPersistence
 	plugins   map[string]PlaintextAuthPlugin
+	resources *accountResources
 
 	updateCounter uint64
 }
@@ -98,6 +99,14 @@ func CreateEmptyMySQLDb() *MySQLDb {
 		RoleEdgesFromKey{},
 		RoleEdgesToKey{},
 	)
+	mysqlDb.default_roles = newMySQLTable(
+		defaultRolesTblName,
+		defaultRolesTblSchema,
+		mysqlDb,
+		&DefaultRole{},
+		DefaultRolesPrimaryKey{},
+		DefaultRolesUserKey{},
+	)
 	mysqlDb.replica_source_info = newMySQLTable(
 		replicaSourceInfoTblName,
 		replicaSourceInfoTblSchema,
@@ -109,8 +118,12 @@ func CreateEmptyMySQLDb() *MySQLDb {
 	// mysqlTable shims
 	mysqlDb.db = newMySQLTableShim(dbTblName, dbTblSchema, mysqlDb.user, DbConverter{})
 	mysqlDb.tables_priv = newMySQLTableShim(tablesPrivTblName, tablesPrivTblSchema, mysqlDb.user, TablesPrivConverter{})
+	mysqlDb.columns_priv = newMySQLTableShim(columnsPrivTblName, columnsPrivTblSchema, mysqlDb.user, ColumnsPrivConverter{})
+	mysqlDb.procs_priv = newMySQLTableShim(procsPrivTblName, procsPrivTblSchema, mysqlDb.user, ProcsPrivConverter{})
 	mysqlDb.global_grants = newMySQLTableShim(globalGrantsTblName, globalGrantsTblSchema, mysqlDb.user, GlobalGrantsConverter{})
 
+	mysqlDb.resources = newAccountResources()
+
 	// Start the counter at 1, all new sessions will start at zero so this forces an update for any new session
 	mysqlDb.updateCounter = 1
 
@@ -199,6 +212,18 @@ func (db *MySQLDb) LoadData(ctx *sql.Context, buf []byte) (err error) {
 		}
 	}
 
+	// Fill in the DefaultRoles table
+	for i := 0; i < serialMySQLDb.DefaultRolesLength(); i++ {
+		serialDefaultRole := new(serial.DefaultRole)
+		if !serialMySQLDb.DefaultRoles(serialDefaultRole, i) {
+			continue
+		}
+		defaultRole := LoadDefaultRole(serialDefaultRole)
+		if err := db.default_roles.data.Put(ctx, defaultRole); err != nil {
+			return err
+		}
+	}
+
 	// Fill in the ReplicaSourceInfo table
 	for i := 0; i < serialMySQLDb.ReplicaSourceInfoLength(); i++ {
 		serialReplicaSourceInfo := new(serial.ReplicaSourceInfo)
@@ -317,20 +342,18 @@ func (db *MySQLDb) UserActivePrivilegeSet(ctx *sql.Context) PrivilegeSet {
 	}
 
 	privSet := user.PrivilegeSet.Copy()
-	roleEdgeEntries := db.role_edges.data.Get(RoleEdgesToKey{
-		ToHost: user.Host,
-		ToUser: user.User,
-	})
-	//TODO: filter the active roles using the context, rather than using every granted roles
 	//TODO: System variable "activate_all_roles_on_login", if set, will set all roles as active upon logging in
-	for _, roleEdgeEntry := range roleEdgeEntries {
-		roleEdge := roleEdgeEntry.(*RoleEdge)
+	roleEdges, explicit := db.activeRoleEdges(ctx, user)
+	activeRoles := make([]sql.ActiveRole, 0, len(roleEdges))
+	for _, roleEdge := range roleEdges {
 		role := db.GetUser(roleEdge.FromUser, roleEdge.FromHost, true)
 		if role != nil {
 			privSet.UnionWith(role.PrivilegeSet)
+			activeRoles = append(activeRoles, sql.ActiveRole{Name: role.User, Host: role.Host})
 		}
 	}
 
+	ctx.Session.SetActiveRoles(activeRoles, explicit)
 	ctx.Session.SetPrivilegeSet(privSet, db.updateCounter)
 	return privSet
 }
@@ -357,10 +380,24 @@ func (db *MySQLDb) UserHasPrivileges(ctx *sql.Context, operations ...sql.Privile
 			if dbSet.Has(operationPriv) {
 				continue
 			}
+			if operation.Routine != "" {
+				if !dbSet.Routine(operation.Routine, operation.IsProcedure).Has(operationPriv) {
+					return false
+				}
+				continue
+			}
 			tblSet := dbSet.Table(operation.Table)
 			if tblSet.Has(operationPriv) {
 				continue
 			}
+			if operation.Column == "" && operation.Table != "" {
+				// A privilege on any of the table's columns grants access to the table, while the columns themselves
+				// are checked once they're known
+				if !tableHasColumnPrivilege(tblSet, operationPriv) {
+					return false
+				}
+				continue
+			}
 			colSet := tblSet.Column(operation.Column)
 			if !colSet.Has(operationPriv) {
 				return false
@@ -370,6 +407,16 @@ func (db *MySQLDb) UserHasPrivileges(ctx *sql.Context, operations ...sql.Privile
 	return true
 }
 
+// tableHasColumnPrivilege returns whether any column of the given table has the given privilege.
+func tableHasColumnPrivilege(tblSet sql.PrivilegeSetTable, priv sql.PrivilegeType) bool {
+	for _, colSet := range tblSet.GetColumns() {
+		if colSet.Has(priv) {
+			return true
+		}
+	}
+	return false
+}
+
 // Name implements the interface sql.Database.
 func (db *MySQLDb) Name() string {
 	return "mysql"
@@ -382,10 +429,16 @@ func (db *MySQLDb) GetTableInsensitive(_ *sql.Context, tblName string) (sql.Tabl
 		return db.user, true, nil
 	case roleEdgesTblName:
 		return db.role_edges, true, nil
+	case defaultRolesTblName:
+		return db.default_roles, true, nil
 	case dbTblName:
 		return db.db, true, nil
 	case tablesPrivTblName:
 		return db.tables_priv, true, nil
+	case columnsPrivTblName:
+		return db.columns_priv, true, nil
+	case procsPrivTblName:
+		return db.procs_priv, true, nil
 	case replicaSourceInfoTblName:
 		return db.replica_source_info, true, nil
 	default:
@@ -399,7 +452,10 @@ func (db *MySQLDb) GetTableNames(ctx *sql.Context) ([]string, error) {
 		userTblName,
 		dbTblName,
 		tablesPrivTblName,
+		columnsPrivTblName,
+		procsPrivTblName,
 		roleEdgesTblName,
+		defaultRolesTblName,
 		replicaSourceInfoTblName,
 	}, nil
 }
@@ -455,17 +511,21 @@ func (db *MySQLDb) ValidateHash(salt []byte, user string, authResponse []byte, a
 	}
 
 	userEntry := db.GetUser(user, host, false)
-	if userEntry == nil || userEntry.Locked {
+	if userEntry == nil {
 		return nil, mysql.NewSQLError(mysql.ERAccessDeniedError, mysql.SSAccessDeniedError, "Access denied for user '%v'", user)
 	}
+	if err = db.checkAccountLogin(userEntry); err != nil {
+		return nil, err
+	}
 	if len(userEntry.Password) > 0 {
 		if !validateMysqlNativePassword(authResponse, salt, userEntry.Password) {
-			return nil, mysql.NewSQLError(mysql.ERAccessDeniedError, mysql.SSAccessDeniedError, "Access denied for user '%v'", user)
+			return nil, db.accountLoginFailed(userEntry, mysql.NewSQLError(mysql.ERAccessDeniedError, mysql.SSAccessDeniedError, "Access denied for user '%v'", user))
 		}
 	} else if len(authResponse) > 0 { // password is nil or empty, therefore no password is set
 		// a password was given and the account has no password set, therefore access is denied
-		return nil, mysql.NewSQLError(mysql.ERAccessDeniedError, mysql.SSAccessDeniedError, "Access denied for user '%v'", user)
+		return nil, db.accountLoginFailed(userEntry, mysql.NewSQLError(mysql.ERAccessDeniedError, mysql.SSAccessDeniedError, "Access denied for user '%v'", user))
 	}
+	db.accountLoginSucceeded(userEntry)
 
 	return MysqlConnectionUser{User: userEntry.User, Host: userEntry.Host}, nil
 }
@@ -488,6 +548,12 @@ func (db *MySQLDb) Negotiate(c *mysql.Conn, user string, addr net.Addr) (mysql.G
 		return connUser, nil
 	}
 	userEntry := db.GetUser(user, host, false)
+	if userEntry == nil {
+		return nil, mysql.NewSQLError(mysql.ERAccessDeniedError, mysql.SSAccessDeniedError, "Access denied for user '%v'", user)
+	}
+	if err = db.checkAccountLogin(userEntry); err != nil {
+		return nil, err
+	}
 
 	if userEntry.Plugin != "" {
 		authplugin, ok := db.plugins[userEntry.Plugin]
@@ -503,8 +569,9 @@ func (db *MySQLDb) Negotiate(c *mysql.Conn, user string, addr net.Addr) (mysql.G
 			return nil, mysql.NewSQLError(mysql.ERAccessDeniedError, mysql.SSAccessDeniedError, "Access denied for user '%v': %v", user, err)
 		}
 		if !authed {
-			return nil, mysql.NewSQLError(mysql.ERAccessDeniedError, mysql.SSAccessDeniedError, "Access denied for user '%v'", user)
+			return nil, db.accountLoginFailed(userEntry, mysql.NewSQLError(mysql.ERAccessDeniedError, mysql.SSAccessDeniedError, "Access denied for user '%v'", user))
 		}
+		db.accountLoginSucceeded(userEntry)
 		return connUser, nil
 	}
 	return nil, fmt.Errorf(`the only user login interface currently supported is "mysql_native_password"`)
@@ -549,6 +616,25 @@ func (db *MySQLDb) Persist(ctx *sql.Context) error {
 		return roles[i].FromHost < roles[j].FromHost
 	})
 
+	// Extract all default role entries from table, and sort
+	defaultRoleEntries := db.default_roles.data.ToSlice(ctx)
+	defaultRoles := make([]*DefaultRole, len(defaultRoleEntries))
+	for i, defaultRoleEntry := range defaultRoleEntries {
+		defaultRoles[i] = defaultRoleEntry.(*DefaultRole)
+	}
+	sort.Slice(defaultRoles, func(i, j int) bool {
+		if defaultRoles[i].Host == defaultRoles[j].Host {
+			if defaultRoles[i].User == defaultRoles[j].User {
+				if defaultRoles[i].DefaultRoleHost == defaultRoles[j].DefaultRoleHost {
+					return defaultRoles[i].DefaultRoleUser < defaultRoles[j].DefaultRoleUser
+				}
+				return defaultRoles[i].DefaultRoleHost < defaultRoles[j].DefaultRoleHost
+			}
+			return defaultRoles[i].User < defaultRoles[j].User
+		}
+		return defaultRoles[i].Host < defaultRoles[j].Host
+	})
+
 	// Extract all replica source info entries from table, and sort
 	replicaSourceInfoEntries := db.replica_source_info.data.ToSlice(ctx)
 	replicaSourceInfos := make([]*ReplicaSourceInfo, len(replicaSourceInfoEntries))
@@ -572,12 +658,14 @@ func (db *MySQLDb) Persist(ctx *sql.Context) error {
 	user := serializeUser(b, users)
 	roleEdge := serializeRoleEdge(b, roles)
 	replicaSourceInfo := serializeReplicaSourceInfo(b, replicaSourceInfos)
+	defaultRole := serializeDefaultRole(b, defaultRoles)
 
 	// Write MySQL DB
 	serial.MySQLDbStart(b)
 	serial.MySQLDbAddUser(b, user)
 	serial.MySQLDbAddRoleEdges(b, roleEdge)
 	serial.MySQLDbAddReplicaSourceInfo(b, replicaSourceInfo)
+	serial.MySQLDbAddDefaultRoles(b, defaultRole)
 	mysqlDbOffset := serial.MySQLDbEnd(b)
 
 	// Finish writing
@@ -597,6 +685,11 @@ func (db *MySQLDb) RoleEdgesTable() *mysqlTable {
 	return db.role_edges
 }
 
+// DefaultRolesTable returns the "default_roles" table.
+func (db *MySQLDb) DefaultRolesTable() *mysqlTable {
+	return db.default_roles
+}
+
 // ReplicaSourceInfoTable returns the "slave_master_info" table.
 func (db *MySQLDb) ReplicaSourceInfoTable() *mysqlTable {
 	return db.replica_source_info
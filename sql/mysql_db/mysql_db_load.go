@@ -15,6 +15,7 @@
 package mysql_db
 
 import (
+	"strings"
 	"time"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -46,7 +47,7 @@ func loadTable(serialTable *serial.PrivilegeSetTable) *PrivilegeSetTable {
 			continue
 		}
 		column := loadColumn(serialColumn)
-		columns[column.Name()] = *column
+		columns[strings.ToLower(column.Name())] = *column
 	}
 
 	return &PrivilegeSetTable{
@@ -56,6 +57,14 @@ func loadTable(serialTable *serial.PrivilegeSetTable) *PrivilegeSetTable {
 	}
 }
 
+func loadRoutine(serialRoutine *serial.PrivilegeSetRoutine) *PrivilegeSetRoutine {
+	return &PrivilegeSetRoutine{
+		name:        string(serialRoutine.Name()),
+		isProcedure: serialRoutine.IsProc(),
+		privs:       loadPrivilegeTypes(serialRoutine.PrivsLength(), serialRoutine.Privs),
+	}
+}
+
 func loadDatabase(serialDatabase *serial.PrivilegeSetDatabase) *PrivilegeSetDatabase {
 	tables := make(map[string]PrivilegeSetTable, serialDatabase.TablesLength())
 	for i := 0; i < serialDatabase.TablesLength(); i++ {
@@ -64,13 +73,24 @@ func loadDatabase(serialDatabase *serial.PrivilegeSetDatabase) *PrivilegeSetData
 			continue
 		}
 		table := loadTable(serialTable)
-		tables[table.Name()] = *table
+		tables[strings.ToLower(table.Name())] = *table
+	}
+
+	routines := make(map[privilegeSetRoutineKey]PrivilegeSetRoutine, serialDatabase.RoutinesLength())
+	for i := 0; i < serialDatabase.RoutinesLength(); i++ {
+		serialRoutine := new(serial.PrivilegeSetRoutine)
+		if !serialDatabase.Routines(serialRoutine, i) {
+			continue
+		}
+		routine := loadRoutine(serialRoutine)
+		routines[privilegeSetRoutineKey{strings.ToLower(routine.Name()), routine.IsProcedure()}] = *routine
 	}
 
 	return &PrivilegeSetDatabase{
-		name:   string(serialDatabase.Name()),
-		privs:  loadPrivilegeTypes(serialDatabase.PrivsLength(), serialDatabase.Privs),
-		tables: tables,
+		name:     string(serialDatabase.Name()),
+		privs:    loadPrivilegeTypes(serialDatabase.PrivsLength(), serialDatabase.Privs),
+		tables:   tables,
+		routines: routines,
 	}
 }
 
@@ -82,7 +102,7 @@ func loadPrivilegeSet(serialPrivilegeSet *serial.PrivilegeSet) *PrivilegeSet {
 			continue
 		}
 		database := loadDatabase(serialDatabase)
-		databases[database.Name()] = *database
+		databases[strings.ToLower(database.Name())] = *database
 	}
 
 	globalDynamic := make(map[string]bool)
@@ -106,6 +126,11 @@ func LoadUser(serialUser *serial.User) *User {
 	if attributesBuf != nil {
 		attributes = &attributesVal
 	}
+	var passwordLifetime *uint16
+	if val := serialUser.PasswordLifetime(); val >= 0 {
+		lifetime := uint16(val)
+		passwordLifetime = &lifetime
+	}
 
 	return &User{
 		User:                string(serialUser.User()),
@@ -117,6 +142,14 @@ func LoadUser(serialUser *serial.User) *User {
 		Locked:              serialUser.Locked(),
 		Attributes:          attributes,
 		Identity:            string(serialUser.Identity()),
+		MaxQuestions:        serialUser.MaxQuestions(),
+		MaxUpdates:          serialUser.MaxUpdates(),
+		MaxConnections:      serialUser.MaxConnections(),
+		MaxUserConnections:  serialUser.MaxUserConnections(),
+		PasswordExpired:     serialUser.PasswordExpired(),
+		PasswordLifetime:    passwordLifetime,
+		FailedLoginAttempts: serialUser.FailedLoginAttempts(),
+		PasswordLockTime:    serialUser.PasswordLockTime(),
 	}
 }
 
@@ -126,6 +159,17 @@ func LoadRoleEdge(serialRoleEdge *serial.RoleEdge) *RoleEdge {
 		FromUser: string(serialRoleEdge.FromUser()),
 		ToHost:   string(serialRoleEdge.ToHost()),
 		ToUser:   string(serialRoleEdge.ToUser()),
+
+		WithAdminOption: serialRoleEdge.WithAdminOption(),
+	}
+}
+
+func LoadDefaultRole(serialDefaultRole *serial.DefaultRole) *DefaultRole {
+	return &DefaultRole{
+		Host:            string(serialDefaultRole.Host()),
+		User:            string(serialDefaultRole.User()),
+		DefaultRoleHost: string(serialDefaultRole.DefaultRoleHost()),
+		DefaultRoleUser: string(serialDefaultRole.DefaultRoleUser()),
 	}
 }
 
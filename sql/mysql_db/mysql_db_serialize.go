@@ -22,7 +22,7 @@ import (
 )
 
 // serializePrivilegeTypes writes the given PrivilegeTypes into the flatbuffer Builder using the given flatbuffer start function, and returns the offset
-// This helper function is used by PrivilegeSetColumn, PrivilegeSetTable, and PrivilegeSetDatabase
+// This helper function is used by PrivilegeSetColumn, PrivilegeSetTable, PrivilegeSetRoutine, and PrivilegeSetDatabase
 func serializePrivilegeTypes(b *flatbuffers.Builder, StartPTVector func(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT, pts []sql.PrivilegeType) flatbuffers.UOffsetT {
 	// Order doesn't matter since it's a set of indexes
 	StartPTVector(b, len(pts))
@@ -97,6 +97,23 @@ func serializeTables(b *flatbuffers.Builder, tables []PrivilegeSetTable) flatbuf
 	return serializeVectorOffsets(b, serial.PrivilegeSetDatabaseStartTablesVector, offsets)
 }
 
+func serializeRoutines(b *flatbuffers.Builder, routines []PrivilegeSetRoutine) flatbuffers.UOffsetT {
+	// Write routine variables, and save offsets
+	offsets := make([]flatbuffers.UOffsetT, len(routines))
+	for i, routine := range routines {
+		name := b.CreateString(routine.Name())
+		privs := serializePrivilegeTypes(b, serial.PrivilegeSetRoutineStartPrivsVector, routine.ToSlice())
+
+		serial.PrivilegeSetRoutineStart(b)
+		serial.PrivilegeSetRoutineAddName(b, name)
+		serial.PrivilegeSetRoutineAddIsProc(b, routine.IsProcedure())
+		serial.PrivilegeSetRoutineAddPrivs(b, privs)
+		offsets[len(offsets)-i-1] = serial.PrivilegeSetRoutineEnd(b) // reverse order
+	}
+	// Write routine offsets (order already reversed)
+	return serializeVectorOffsets(b, serial.PrivilegeSetDatabaseStartRoutinesVector, offsets)
+}
+
 // serializeDatabases writes the given Privilege Set Databases into the flatbuffer Builder, and returns the offset
 func serializeDatabases(b *flatbuffers.Builder, databases []PrivilegeSetDatabase) flatbuffers.UOffsetT {
 	// Write database variables, and save offsets
@@ -105,11 +122,13 @@ func serializeDatabases(b *flatbuffers.Builder, databases []PrivilegeSetDatabase
 		name := b.CreateString(database.Name())
 		privs := serializePrivilegeTypes(b, serial.PrivilegeSetDatabaseStartPrivsVector, database.ToSlice())
 		tables := serializeTables(b, database.getTables())
+		routines := serializeRoutines(b, database.getRoutines())
 
 		serial.PrivilegeSetDatabaseStart(b)
 		serial.PrivilegeSetDatabaseAddName(b, name)
 		serial.PrivilegeSetDatabaseAddPrivs(b, privs)
 		serial.PrivilegeSetDatabaseAddTables(b, tables)
+		serial.PrivilegeSetDatabaseAddRoutines(b, routines)
 		offsets[len(offsets)-i-1] = serial.PrivilegeSetDatabaseEnd(b)
 	}
 
@@ -164,6 +183,16 @@ func serializeUser(b *flatbuffers.Builder, users []*User) flatbuffers.UOffsetT {
 		serial.UserAddLocked(b, user.Locked)
 		serial.UserAddAttributes(b, attributes)
 		serial.UserAddIdentity(b, identity)
+		serial.UserAddMaxQuestions(b, user.MaxQuestions)
+		serial.UserAddMaxUpdates(b, user.MaxUpdates)
+		serial.UserAddMaxConnections(b, user.MaxConnections)
+		serial.UserAddMaxUserConnections(b, user.MaxUserConnections)
+		serial.UserAddPasswordExpired(b, user.PasswordExpired)
+		if user.PasswordLifetime != nil {
+			serial.UserAddPasswordLifetime(b, int32(*user.PasswordLifetime))
+		}
+		serial.UserAddFailedLoginAttempts(b, user.FailedLoginAttempts)
+		serial.UserAddPasswordLockTime(b, user.PasswordLockTime)
 
 		offsets[len(users)-i-1] = serial.UserEnd(b) // reverse order
 	}
@@ -201,6 +230,32 @@ func serializeRoleEdge(b *flatbuffers.Builder, roleEdges []*RoleEdge) flatbuffer
 	return serializeVectorOffsets(b, serial.MySQLDbStartRoleEdgesVector, offsets)
 }
 
+func serializeDefaultRole(b *flatbuffers.Builder, defaultRoles []*DefaultRole) flatbuffers.UOffsetT {
+	offsets := make([]flatbuffers.UOffsetT, len(defaultRoles))
+	for i, defaultRole := range defaultRoles {
+		// Serialize each of the member vars in DefaultRole and save their offsets
+		host := b.CreateString(defaultRole.Host)
+		user := b.CreateString(defaultRole.User)
+		defaultRoleHost := b.CreateString(defaultRole.DefaultRoleHost)
+		defaultRoleUser := b.CreateString(defaultRole.DefaultRoleUser)
+
+		// Start DefaultRole
+		serial.DefaultRoleStart(b)
+
+		// Write their offsets to flatbuffer builder
+		serial.DefaultRoleAddHost(b, host)
+		serial.DefaultRoleAddUser(b, user)
+		serial.DefaultRoleAddDefaultRoleHost(b, defaultRoleHost)
+		serial.DefaultRoleAddDefaultRoleUser(b, defaultRoleUser)
+
+		// End DefaultRole
+		offsets[len(defaultRoles)-i-1] = serial.DefaultRoleEnd(b) // reverse order
+	}
+
+	// Write default_roles vector (already in reversed order)
+	return serializeVectorOffsets(b, serial.MySQLDbStartDefaultRolesVector, offsets)
+}
+
 func serializeReplicaSourceInfo(b *flatbuffers.Builder, replicaSourceInfos []*ReplicaSourceInfo) flatbuffers.UOffsetT {
 	offsets := make([]flatbuffers.UOffsetT, len(replicaSourceInfos))
 
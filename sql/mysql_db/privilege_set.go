@@ -119,6 +119,14 @@ func (ps PrivilegeSet) AddColumn(dbName string, tblName string, colName string,
 	}
 }
 
+// AddRoutine adds the given routine privilege(s).
+func (ps PrivilegeSet) AddRoutine(dbName string, routineName string, isProcedure bool, privileges ...sql.PrivilegeType) {
+	routineSet := ps.getUseableDb(dbName).getUseableRoutine(routineName, isProcedure)
+	for _, priv := range privileges {
+		routineSet.privs[priv] = struct{}{}
+	}
+}
+
 // RemoveGlobalStatic removes the given global static privilege(s).
 func (ps PrivilegeSet) RemoveGlobalStatic(privileges ...sql.PrivilegeType) {
 	for _, priv := range privileges {
@@ -166,6 +174,17 @@ func (ps PrivilegeSet) RemoveColumn(dbName string, tblName string, colName strin
 	}
 }
 
+// RemoveRoutine removes the given routine privilege(s).
+func (ps PrivilegeSet) RemoveRoutine(dbName string, routineName string, isProcedure bool, privileges ...sql.PrivilegeType) {
+	// We don't use the getUseable functions since we don't want to create new maps if they don't already exist
+	routineSet := ps.Database(dbName).Routine(routineName, isProcedure).(PrivilegeSetRoutine)
+	if len(routineSet.privs) > 0 {
+		for _, priv := range privileges {
+			delete(routineSet.privs, priv)
+		}
+	}
+}
+
 // Has returns whether the given global static privilege(s) exists.
 func (ps PrivilegeSet) Has(privileges ...sql.PrivilegeType) bool {
 	for _, priv := range privileges {
@@ -230,6 +249,7 @@ func (ps PrivilegeSet) GetDatabases() []sql.PrivilegeSetDatabase {
 			i++
 		}
 	}
+	dbSets = dbSets[:i]
 	sort.Slice(dbSets, func(i, j int) bool {
 		return dbSets[i].Name() < dbSets[j].Name()
 	})
@@ -248,6 +268,7 @@ func (ps PrivilegeSet) getDatabases() []PrivilegeSetDatabase {
 			i++
 		}
 	}
+	dbSets = dbSets[:i]
 	sort.Slice(dbSets, func(i, j int) bool {
 		return dbSets[i].name < dbSets[j].name
 	})
@@ -289,6 +310,11 @@ func (ps PrivilegeSet) ClearColumn(dbName string, tblName string, colName string
 	ps.getUseableDb(dbName).getUseableTbl(tblName).getUseableCol(colName).clear()
 }
 
+// ClearRoutine removes all privileges for the given routine.
+func (ps PrivilegeSet) ClearRoutine(dbName string, routineName string, isProcedure bool) {
+	ps.getUseableDb(dbName).getUseableRoutine(routineName, isProcedure).clear()
+}
+
 // ClearAll removes all privileges.
 func (ps *PrivilegeSet) ClearAll() {
 	ps.globalStatic = make(map[sql.PrivilegeType]struct{})
@@ -364,9 +390,10 @@ func (ps PrivilegeSet) getUseableDb(dbName string) PrivilegeSetDatabase {
 	dbSet, ok := ps.databases[lowerDbName]
 	if !ok {
 		dbSet = PrivilegeSetDatabase{
-			name:   dbName,
-			privs:  make(map[sql.PrivilegeType]struct{}),
-			tables: make(map[string]PrivilegeSetTable),
+			name:     dbName,
+			privs:    make(map[sql.PrivilegeType]struct{}),
+			tables:   make(map[string]PrivilegeSetTable),
+			routines: make(map[privilegeSetRoutineKey]PrivilegeSetRoutine),
 		}
 		ps.databases[lowerDbName] = dbSet
 	}
@@ -375,9 +402,17 @@ func (ps PrivilegeSet) getUseableDb(dbName string) PrivilegeSetDatabase {
 
 // PrivilegeSetDatabase is a set containing database-level privileges.
 type PrivilegeSetDatabase struct {
-	name   string
-	privs  map[sql.PrivilegeType]struct{}
-	tables map[string]PrivilegeSetTable
+	name     string
+	privs    map[sql.PrivilegeType]struct{}
+	tables   map[string]PrivilegeSetTable
+	routines map[privilegeSetRoutineKey]PrivilegeSetRoutine
+}
+
+// privilegeSetRoutineKey is the key for routines within a database. Procedures and functions have separate namespaces,
+// so the routine type is a part of the key.
+type privilegeSetRoutineKey struct {
+	name        string
+	isProcedure bool
 }
 
 var _ sql.PrivilegeSetDatabase = PrivilegeSetDatabase{}
@@ -397,8 +432,8 @@ func (ps PrivilegeSetDatabase) Has(privileges ...sql.PrivilegeType) bool {
 	return true
 }
 
-// HasPrivileges returns whether this database has either database-level privileges, or privileges on a table or column
-// contained within this database.
+// HasPrivileges returns whether this database has either database-level privileges, or privileges on a table, column,
+// or routine contained within this database.
 func (ps PrivilegeSetDatabase) HasPrivileges() bool {
 	if len(ps.privs) > 0 {
 		return true
@@ -408,6 +443,11 @@ func (ps PrivilegeSetDatabase) HasPrivileges() bool {
 			return true
 		}
 	}
+	for _, routineSet := range ps.routines {
+		if routineSet.Count() > 0 {
+			return true
+		}
+	}
 	return false
 }
 
@@ -437,6 +477,7 @@ func (ps PrivilegeSetDatabase) GetTables() []sql.PrivilegeSetTable {
 			i++
 		}
 	}
+	tblSets = tblSets[:i]
 	sort.Slice(tblSets, func(i, j int) bool {
 		return tblSets[i].Name() < tblSets[j].Name()
 	})
@@ -455,19 +496,64 @@ func (ps PrivilegeSetDatabase) getTables() []PrivilegeSetTable {
 			i++
 		}
 	}
+	tblSets = tblSets[:i]
 	sort.Slice(tblSets, func(i, j int) bool {
 		return tblSets[i].name < tblSets[j].name
 	})
 	return tblSets
 }
 
+// Routine returns the set of privileges for the given routine. Returns an empty set if the routine does not exist.
+func (ps PrivilegeSetDatabase) Routine(routineName string, isProcedure bool) sql.PrivilegeSetRoutine {
+	routineSet, ok := ps.routines[privilegeSetRoutineKey{strings.ToLower(routineName), isProcedure}]
+	if ok {
+		return routineSet
+	}
+	return PrivilegeSetRoutine{name: routineName, isProcedure: isProcedure}
+}
+
+// GetRoutines returns all routines.
+func (ps PrivilegeSetDatabase) GetRoutines() []sql.PrivilegeSetRoutine {
+	routineSets := ps.getRoutines()
+	sqlRoutineSets := make([]sql.PrivilegeSetRoutine, len(routineSets))
+	for i, routineSet := range routineSets {
+		sqlRoutineSets[i] = routineSet
+	}
+	return sqlRoutineSets
+}
+
+// getRoutines returns all routines of the native type, with procedures sorted before functions.
+func (ps PrivilegeSetDatabase) getRoutines() []PrivilegeSetRoutine {
+	routineSets := make([]PrivilegeSetRoutine, 0, len(ps.routines))
+	for _, routineSet := range ps.routines {
+		// Only return routines that have privileges. Otherwise, there is no difference between the returned routine
+		// and the zero-value for any routine.
+		if routineSet.Count() > 0 {
+			routineSets = append(routineSets, routineSet)
+		}
+	}
+	sort.Slice(routineSets, func(i, j int) bool {
+		if routineSets[i].isProcedure != routineSets[j].isProcedure {
+			return routineSets[i].isProcedure
+		}
+		return routineSets[i].name < routineSets[j].name
+	})
+	return routineSets
+}
+
 // Equals returns whether the given set of privileges is equivalent to the calling set.
 func (ps PrivilegeSetDatabase) Equals(otherPsd sql.PrivilegeSetDatabase) bool {
 	otherPs := otherPsd.(PrivilegeSetDatabase)
 	if len(ps.privs) != len(otherPs.privs) ||
-		len(ps.tables) != len(otherPs.tables) {
+		len(ps.tables) != len(otherPs.tables) ||
+		len(ps.routines) != len(otherPs.routines) {
 		return false
 	}
+	for key, routineSet := range ps.routines {
+		if !routineSet.Equals(otherPs.routines[key]) {
+			return false
+		}
+	}
 	for priv := range ps.privs {
 		if _, ok := otherPs.privs[priv]; !ok {
 			return false
@@ -510,6 +596,21 @@ func (ps PrivilegeSetDatabase) getUseableTbl(tblName string) PrivilegeSetTable {
 	return tblSet
 }
 
+// getUseableRoutine is used internally to either retrieve an existing routine, or create a new one that is returned.
+func (ps PrivilegeSetDatabase) getUseableRoutine(routineName string, isProcedure bool) PrivilegeSetRoutine {
+	key := privilegeSetRoutineKey{strings.ToLower(routineName), isProcedure}
+	routineSet, ok := ps.routines[key]
+	if !ok {
+		routineSet = PrivilegeSetRoutine{
+			name:        routineName,
+			isProcedure: isProcedure,
+			privs:       make(map[sql.PrivilegeType]struct{}),
+		}
+		ps.routines[key] = routineSet
+	}
+	return routineSet
+}
+
 // unionWith merges the given set of privileges to the calling set of privileges.
 func (ps PrivilegeSetDatabase) unionWith(otherPs PrivilegeSetDatabase) {
 	for priv := range otherPs.privs {
@@ -518,6 +619,9 @@ func (ps PrivilegeSetDatabase) unionWith(otherPs PrivilegeSetDatabase) {
 	for _, otherTblSet := range otherPs.tables {
 		ps.getUseableTbl(otherTblSet.name).unionWith(otherTblSet)
 	}
+	for _, otherRoutineSet := range otherPs.routines {
+		ps.getUseableRoutine(otherRoutineSet.name, otherRoutineSet.isProcedure).unionWith(otherRoutineSet)
+	}
 }
 
 // clear removes all database privileges.
@@ -591,6 +695,7 @@ func (ps PrivilegeSetTable) GetColumns() []sql.PrivilegeSetColumn {
 			i++
 		}
 	}
+	colSets = colSets[:i]
 	sort.Slice(colSets, func(i, j int) bool {
 		return colSets[i].Name() < colSets[j].Name()
 	})
@@ -609,6 +714,7 @@ func (ps PrivilegeSetTable) getColumns() []PrivilegeSetColumn {
 			i++
 		}
 	}
+	colSets = colSets[:i]
 	sort.Slice(colSets, func(i, j int) bool {
 		return colSets[i].name < colSets[j].name
 	})
@@ -749,3 +855,79 @@ func (ps PrivilegeSetColumn) clear() {
 		delete(ps.privs, priv)
 	}
 }
+
+// PrivilegeSetRoutine is a set containing routine privileges.
+type PrivilegeSetRoutine struct {
+	name        string
+	isProcedure bool
+	privs       map[sql.PrivilegeType]struct{}
+}
+
+var _ sql.PrivilegeSetRoutine = PrivilegeSetRoutine{}
+
+// Name returns the name of the routine that this privilege set belongs to.
+func (ps PrivilegeSetRoutine) Name() string {
+	return ps.name
+}
+
+// IsProcedure returns whether the routine is a procedure, rather than a function.
+func (ps PrivilegeSetRoutine) IsProcedure() bool {
+	return ps.isProcedure
+}
+
+// Has returns whether the given routine privilege(s) exists.
+func (ps PrivilegeSetRoutine) Has(privileges ...sql.PrivilegeType) bool {
+	for _, priv := range privileges {
+		if _, ok := ps.privs[priv]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Count returns the number of routine privileges.
+func (ps PrivilegeSetRoutine) Count() int {
+	return len(ps.privs)
+}
+
+// Equals returns whether the given set of privileges is equivalent to the calling set.
+func (ps PrivilegeSetRoutine) Equals(otherPsr sql.PrivilegeSetRoutine) bool {
+	otherPs := otherPsr.(PrivilegeSetRoutine)
+	if ps.isProcedure != otherPs.isProcedure || len(ps.privs) != len(otherPs.privs) {
+		return false
+	}
+	for priv := range ps.privs {
+		if _, ok := otherPs.privs[priv]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// ToSlice returns all of the routine privileges contained as a sorted slice.
+func (ps PrivilegeSetRoutine) ToSlice() []sql.PrivilegeType {
+	privs := make([]sql.PrivilegeType, len(ps.privs))
+	i := 0
+	for priv := range ps.privs {
+		privs[i] = priv
+		i++
+	}
+	sort.Slice(privs, func(i, j int) bool {
+		return privs[i] < privs[j]
+	})
+	return privs
+}
+
+// unionWith merges the given set of privileges to the calling set of privileges.
+func (ps PrivilegeSetRoutine) unionWith(otherPs PrivilegeSetRoutine) {
+	for priv := range otherPs.privs {
+		ps.privs[priv] = struct{}{}
+	}
+}
+
+// clear removes all routine privileges.
+func (ps PrivilegeSetRoutine) clear() {
+	for priv := range ps.privs {
+		delete(ps.privs, priv)
+	}
+}
@@ -0,0 +1,271 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql_db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/in_mem_table"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+const procsPrivTblName = "procs_priv"
+
+var (
+	errProcsPrivEntry = fmt.Errorf("the converter for the `procs_priv` table was given an unknown entry")
+	errProcsPrivRow   = fmt.Errorf("the converter for the `procs_priv` table was given a row belonging to an unknown schema")
+
+	procsPrivTblSchema sql.Schema
+)
+
+// These are the values of the "Routine_type" enum in the "procs_priv" Grant Table.
+const (
+	procsPrivRoutineType_Function  uint16 = 1
+	procsPrivRoutineType_Procedure uint16 = 2
+)
+
+// ProcsPrivConverter handles the conversion between a stored *User entry and the faux "procs_priv" Grant Table.
+type ProcsPrivConverter struct{}
+
+var _ in_mem_table.DataEditorConverter = ProcsPrivConverter{}
+
+// RowToKey implements the interface in_mem_table.DataEditorConverter.
+func (conv ProcsPrivConverter) RowToKey(ctx *sql.Context, row sql.Row) (in_mem_table.Key, error) {
+	if len(row) != len(procsPrivTblSchema) {
+		return nil, errProcsPrivRow
+	}
+	host, ok := row[procsPrivTblColIndex_Host].(string)
+	if !ok {
+		return nil, errProcsPrivRow
+	}
+	user, ok := row[procsPrivTblColIndex_User].(string)
+	if !ok {
+		return nil, errProcsPrivRow
+	}
+	return UserPrimaryKey{
+		Host: host,
+		User: user,
+	}, nil
+}
+
+// AddRowToEntry implements the interface in_mem_table.DataEditorConverter.
+func (conv ProcsPrivConverter) AddRowToEntry(ctx *sql.Context, row sql.Row, entry in_mem_table.Entry) (in_mem_table.Entry, error) {
+	if len(row) != len(procsPrivTblSchema) {
+		return nil, errProcsPrivRow
+	}
+	user, ok := entry.(*User)
+	if !ok {
+		return nil, errProcsPrivEntry
+	}
+	user = user.Copy(ctx).(*User)
+
+	dbName, ok := row[procsPrivTblColIndex_Db].(string)
+	if !ok {
+		return nil, errProcsPrivRow
+	}
+	routineName, ok := row[procsPrivTblColIndex_Routine_name].(string)
+	if !ok {
+		return nil, errProcsPrivRow
+	}
+	routineType, ok := row[procsPrivTblColIndex_Routine_type].(uint16)
+	if !ok {
+		return nil, errProcsPrivRow
+	}
+	procPrivs, ok := row[procsPrivTblColIndex_Proc_priv].(uint64)
+	if !ok {
+		return nil, errProcsPrivRow
+	}
+	procPrivStrs, err := procsPrivTblSchema[procsPrivTblColIndex_Proc_priv].Type.(sql.SetType).BitsToString(procPrivs)
+	if err != nil {
+		return nil, err
+	}
+	var privs []sql.PrivilegeType
+	for _, val := range strings.Split(procPrivStrs, ",") {
+		switch val {
+		case "Execute":
+			privs = append(privs, sql.PrivilegeType_Execute)
+		case "Alter Routine":
+			privs = append(privs, sql.PrivilegeType_AlterRoutine)
+		case "Grant":
+			privs = append(privs, sql.PrivilegeType_GrantOption)
+		case "":
+		default:
+			return nil, errProcsPrivRow
+		}
+	}
+	user.PrivilegeSet.AddRoutine(dbName, routineName, routineType == procsPrivRoutineType_Procedure, privs...)
+	return user, nil
+}
+
+// RemoveRowFromEntry implements the interface in_mem_table.DataEditorConverter.
+func (conv ProcsPrivConverter) RemoveRowFromEntry(ctx *sql.Context, row sql.Row, entry in_mem_table.Entry) (in_mem_table.Entry, error) {
+	if len(row) != len(procsPrivTblSchema) {
+		return nil, errProcsPrivRow
+	}
+	user, ok := entry.(*User)
+	if !ok {
+		return nil, errProcsPrivEntry
+	}
+	user = user.Copy(ctx).(*User)
+
+	db, ok := row[procsPrivTblColIndex_Db].(string)
+	if !ok {
+		return nil, errProcsPrivRow
+	}
+	routineName, ok := row[procsPrivTblColIndex_Routine_name].(string)
+	if !ok {
+		return nil, errProcsPrivRow
+	}
+	routineType, ok := row[procsPrivTblColIndex_Routine_type].(uint16)
+	if !ok {
+		return nil, errProcsPrivRow
+	}
+	user.PrivilegeSet.ClearRoutine(db, routineName, routineType == procsPrivRoutineType_Procedure)
+	return user, nil
+}
+
+// EntryToRows implements the interface in_mem_table.DataEditorConverter.
+func (conv ProcsPrivConverter) EntryToRows(ctx *sql.Context, entry in_mem_table.Entry) ([]sql.Row, error) {
+	user, ok := entry.(*User)
+	if !ok {
+		return nil, errProcsPrivEntry
+	}
+
+	var rows []sql.Row
+	for _, dbSet := range user.PrivilegeSet.GetDatabases() {
+		for _, routineSet := range dbSet.GetRoutines() {
+			row := make(sql.Row, len(procsPrivTblSchema))
+			var err error
+			for i, col := range procsPrivTblSchema {
+				row[i], err = col.Default.Eval(ctx, nil)
+				if err != nil {
+					return nil, err // Should never happen, schema is static
+				}
+			}
+			row[procsPrivTblColIndex_User] = user.User
+			row[procsPrivTblColIndex_Host] = user.Host
+			row[procsPrivTblColIndex_Db] = dbSet.Name()
+			row[procsPrivTblColIndex_Routine_name] = routineSet.Name()
+			if routineSet.IsProcedure() {
+				row[procsPrivTblColIndex_Routine_type] = procsPrivRoutineType_Procedure
+			} else {
+				row[procsPrivTblColIndex_Routine_type] = procsPrivRoutineType_Function
+			}
+
+			var privs []string
+			for _, priv := range routineSet.ToSlice() {
+				switch priv {
+				case sql.PrivilegeType_Execute:
+					privs = append(privs, "Execute")
+				case sql.PrivilegeType_AlterRoutine:
+					privs = append(privs, "Alter Routine")
+				case sql.PrivilegeType_GrantOption:
+					privs = append(privs, "Grant")
+				}
+			}
+			formattedSet, err := procsPrivTblSchema[procsPrivTblColIndex_Proc_priv].Type.Convert(strings.Join(privs, ","))
+			if err != nil {
+				return nil, err
+			}
+			row[procsPrivTblColIndex_Proc_priv] = formattedSet.(uint64)
+			rows = append(rows, row)
+		}
+	}
+
+	return rows, nil
+}
+
+// init creates the schema for the "procs_priv" Grant Table.
+func init() {
+	// Types
+	char32_utf8_bin := types.MustCreateString(sqltypes.Char, 32, sql.Collation_utf8_bin)
+	char64_utf8_bin := types.MustCreateString(sqltypes.Char, 64, sql.Collation_utf8_bin)
+	char64_utf8_general_ci := types.MustCreateString(sqltypes.Char, 64, sql.Collation_utf8_general_ci)
+	char255_ascii_general_ci := types.MustCreateString(sqltypes.Char, 255, sql.Collation_ascii_general_ci)
+	enum_RoutineType_utf8_bin := types.MustCreateEnumType([]string{"FUNCTION", "PROCEDURE"}, sql.Collation_utf8_bin)
+	set_ProcPrivs_utf8_general_ci := types.MustCreateSetType([]string{"Execute", "Alter Routine", "Grant"}, sql.Collation_utf8_general_ci)
+	varchar288_utf8_bin := types.MustCreateString(sqltypes.VarChar, 288, sql.Collation_utf8_bin)
+
+	// Column Templates
+	char32_utf8_bin_not_null_default_empty := &sql.Column{
+		Type:     char32_utf8_bin,
+		Default:  mustDefault(expression.NewLiteral("", char32_utf8_bin), char32_utf8_bin, true, false),
+		Nullable: false,
+	}
+	char64_utf8_bin_not_null_default_empty := &sql.Column{
+		Type:     char64_utf8_bin,
+		Default:  mustDefault(expression.NewLiteral("", char64_utf8_bin), char64_utf8_bin, true, false),
+		Nullable: false,
+	}
+	char64_utf8_general_ci_not_null_default_empty := &sql.Column{
+		Type:     char64_utf8_general_ci,
+		Default:  mustDefault(expression.NewLiteral("", char64_utf8_general_ci), char64_utf8_general_ci, true, false),
+		Nullable: false,
+	}
+	char255_ascii_general_ci_not_null_default_empty := &sql.Column{
+		Type:     char255_ascii_general_ci,
+		Default:  mustDefault(expression.NewLiteral("", char255_ascii_general_ci), char255_ascii_general_ci, true, false),
+		Nullable: false,
+	}
+	enum_RoutineType_utf8_bin_not_null_default_FUNCTION := &sql.Column{
+		Type:     enum_RoutineType_utf8_bin,
+		Default:  mustDefault(expression.NewLiteral("FUNCTION", enum_RoutineType_utf8_bin), enum_RoutineType_utf8_bin, true, false),
+		Nullable: false,
+	}
+	set_ProcPrivs_utf8_general_ci_not_null_default_empty := &sql.Column{
+		Type:     set_ProcPrivs_utf8_general_ci,
+		Default:  mustDefault(expression.NewLiteral("", set_ProcPrivs_utf8_general_ci), set_ProcPrivs_utf8_general_ci, true, false),
+		Nullable: false,
+	}
+	timestamp_not_null_default_epoch := &sql.Column{
+		Type:     types.Timestamp,
+		Default:  mustDefault(expression.NewLiteral(time.Unix(1, 0).UTC(), types.Timestamp), types.Timestamp, true, false),
+		Nullable: false,
+	}
+	varchar288_utf8_bin_not_null_default_empty := &sql.Column{
+		Type:     varchar288_utf8_bin,
+		Default:  mustDefault(expression.NewLiteral("", varchar288_utf8_bin), varchar288_utf8_bin, true, false),
+		Nullable: false,
+	}
+
+	procsPrivTblSchema = sql.Schema{
+		columnTemplate("Host", procsPrivTblName, true, char255_ascii_general_ci_not_null_default_empty),
+		columnTemplate("Db", procsPrivTblName, true, char64_utf8_bin_not_null_default_empty),
+		columnTemplate("User", procsPrivTblName, true, char32_utf8_bin_not_null_default_empty),
+		columnTemplate("Routine_name", procsPrivTblName, true, char64_utf8_general_ci_not_null_default_empty),
+		columnTemplate("Routine_type", procsPrivTblName, true, enum_RoutineType_utf8_bin_not_null_default_FUNCTION),
+		columnTemplate("Grantor", procsPrivTblName, false, varchar288_utf8_bin_not_null_default_empty),
+		columnTemplate("Proc_priv", procsPrivTblName, false, set_ProcPrivs_utf8_general_ci_not_null_default_empty),
+		columnTemplate("Timestamp", procsPrivTblName, false, timestamp_not_null_default_epoch),
+	}
+}
+
+// These represent the column indexes of the "procs_priv" Grant Table.
+const (
+	procsPrivTblColIndex_Host int = iota
+	procsPrivTblColIndex_Db
+	procsPrivTblColIndex_User
+	procsPrivTblColIndex_Routine_name
+	procsPrivTblColIndex_Routine_type
+	procsPrivTblColIndex_Grantor
+	procsPrivTblColIndex_Proc_priv
+	procsPrivTblColIndex_Timestamp
+)
@@ -0,0 +1,60 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql_db
+
+import "testing"
+
+func TestProcsPrivTableSchema(t *testing.T) {
+	// Each column has a constant index that it expects to match, therefore if a column's position is updated and the
+	// variable referencing it hasn't also been updated, this will throw a panic.
+	for i, col := range procsPrivTblSchema {
+		switch col.Name {
+		case "Host":
+			if procsPrivTblColIndex_Host != i {
+				t.FailNow()
+			}
+		case "Db":
+			if procsPrivTblColIndex_Db != i {
+				t.FailNow()
+			}
+		case "User":
+			if procsPrivTblColIndex_User != i {
+				t.FailNow()
+			}
+		case "Routine_name":
+			if procsPrivTblColIndex_Routine_name != i {
+				t.FailNow()
+			}
+		case "Routine_type":
+			if procsPrivTblColIndex_Routine_type != i {
+				t.FailNow()
+			}
+		case "Grantor":
+			if procsPrivTblColIndex_Grantor != i {
+				t.FailNow()
+			}
+		case "Proc_priv":
+			if procsPrivTblColIndex_Proc_priv != i {
+				t.FailNow()
+			}
+		case "Timestamp":
+			if procsPrivTblColIndex_Timestamp != i {
+				t.FailNow()
+			}
+		default:
+			t.Errorf(`col "%s" does not have a constant`, col.Name)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package mysql_db
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// GrantedRoles returns the role edges for every role that has been granted to the given user, sorted by role.
+func (db *MySQLDb) GrantedRoles(user *User) []*RoleEdge {
+	roleEdgeEntries := db.role_edges.data.Get(RoleEdgesToKey{
+		ToHost: user.Host,
+		ToUser: user.User,
+	})
+	roleEdges := make([]*RoleEdge, len(roleEdgeEntries))
+	for i, roleEdgeEntry := range roleEdgeEntries {
+		roleEdges[i] = roleEdgeEntry.(*RoleEdge)
+	}
+	sort.Slice(roleEdges, func(i, j int) bool {
+		if roleEdges[i].FromUser == roleEdges[j].FromUser {
+			return roleEdges[i].FromHost < roleEdges[j].FromHost
+		}
+		return roleEdges[i].FromUser < roleEdges[j].FromUser
+	})
+	return roleEdges
+}
+
+// DefaultRoles returns the default roles of the given user, which are activated by SET ROLE DEFAULT.
+func (db *MySQLDb) DefaultRoles(user *User) []*DefaultRole {
+	defaultRoleEntries := db.default_roles.data.Get(DefaultRolesUserKey{
+		Host: user.Host,
+		User: user.User,
+	})
+	defaultRoles := make([]*DefaultRole, len(defaultRoleEntries))
+	for i, defaultRoleEntry := range defaultRoleEntries {
+		defaultRoles[i] = defaultRoleEntry.(*DefaultRole)
+	}
+	return defaultRoles
+}
+
+// IsDefaultRole returns whether the given role is one of the default roles of the given user.
+func (db *MySQLDb) IsDefaultRole(user *User, roleUser string, roleHost string) bool {
+	return len(db.default_roles.data.Get(DefaultRolesPrimaryKey{
+		Host:            user.Host,
+		User:            user.User,
+		DefaultRoleHost: roleHost,
+		DefaultRoleUser: roleUser,
+	})) > 0
+}
+
+// SetDefaultRoles replaces the default roles of the given user with the given roles. This does not persist the
+// change, which is left to the caller.
+func (db *MySQLDb) SetDefaultRoles(ctx *sql.Context, user *User, roles []*User) error {
+	err := db.default_roles.data.Remove(ctx, DefaultRolesUserKey{
+		Host: user.Host,
+		User: user.User,
+	}, nil)
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		err = db.default_roles.data.Put(ctx, &DefaultRole{
+			Host:            user.Host,
+			User:            user.User,
+			DefaultRoleHost: role.Host,
+			DefaultRoleUser: role.User,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RemoveDefaultRoles removes every default role entry that either belongs to, or references, the given user or role.
+// This does not persist the change, which is left to the caller.
+func (db *MySQLDb) RemoveDefaultRoles(ctx *sql.Context, user *User) error {
+	err := db.default_roles.data.Remove(ctx, DefaultRolesUserKey{
+		Host: user.Host,
+		User: user.User,
+	}, nil)
+	if err != nil {
+		return err
+	}
+	for _, entry := range db.default_roles.data.ToSlice(ctx) {
+		defaultRole := entry.(*DefaultRole)
+		if defaultRole.DefaultRoleHost != user.Host || defaultRole.DefaultRoleUser != user.User {
+			continue
+		}
+		if err = db.default_roles.data.Remove(ctx, nil, defaultRole); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// activeRoleEdges returns the role edges of the given user that are active in the session. Until SET ROLE has been
+// used in the session, every granted role is active. Afterwards, only the roles that were set and are still granted
+// remain active.
+func (db *MySQLDb) activeRoleEdges(ctx *sql.Context, user *User) ([]*RoleEdge, bool) {
+	sessionRoles, explicit := ctx.Session.GetActiveRoles()
+	grantedRoles := db.GrantedRoles(user)
+	if !explicit {
+		return grantedRoles, false
+	}
+	var activeRoles []*RoleEdge
+	for _, roleEdge := range grantedRoles {
+		for _, sessionRole := range sessionRoles {
+			if roleEdge.FromUser == sessionRole.Name && roleEdge.FromHost == sessionRole.Host {
+				activeRoles = append(activeRoles, roleEdge)
+				break
+			}
+		}
+	}
+	return activeRoles, true
+}
+
+// IsMandatoryRole returns whether the given role is named by the "mandatory_roles" system variable.
+func IsMandatoryRole(ctx *sql.Context, roleUser string, roleHost string) bool {
+	val, err := ctx.GetSessionVariable(ctx, "mandatory_roles")
+	if err != nil {
+		return false
+	}
+	mandatoryRoles, ok := val.(string)
+	if !ok {
+		return false
+	}
+	for _, mandatoryRole := range strings.Split(mandatoryRoles, ",") {
+		name, host := strings.TrimSpace(mandatoryRole), "%"
+		if idx := strings.LastIndex(name, "@"); idx >= 0 {
+			name, host = name[:idx], name[idx+1:]
+		}
+		if strings.Trim(name, "`'\"") == roleUser && strings.Trim(host, "`'\"") == roleHost {
+			return true
+		}
+	}
+	return false
+}
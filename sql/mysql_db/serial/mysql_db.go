@@ -200,6 +200,98 @@ func PrivilegeSetTableEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
 
+type PrivilegeSetRoutine struct {
+	_tab flatbuffers.Table
+}
+
+func GetRootAsPrivilegeSetRoutine(buf []byte, offset flatbuffers.UOffsetT) *PrivilegeSetRoutine {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	x := &PrivilegeSetRoutine{}
+	x.Init(buf, n+offset)
+	return x
+}
+
+func GetSizePrefixedRootAsPrivilegeSetRoutine(buf []byte, offset flatbuffers.UOffsetT) *PrivilegeSetRoutine {
+	n := flatbuffers.GetUOffsetT(buf[offset+flatbuffers.SizeUint32:])
+	x := &PrivilegeSetRoutine{}
+	x.Init(buf, n+offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func (rcv *PrivilegeSetRoutine) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *PrivilegeSetRoutine) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *PrivilegeSetRoutine) Name() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *PrivilegeSetRoutine) IsProc() bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.GetBool(o + rcv._tab.Pos)
+	}
+	return false
+}
+
+func (rcv *PrivilegeSetRoutine) MutateIsProc(n bool) bool {
+	return rcv._tab.MutateBoolSlot(6, n)
+}
+
+func (rcv *PrivilegeSetRoutine) Privs(j int) int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.GetInt32(a + flatbuffers.UOffsetT(j*4))
+	}
+	return 0
+}
+
+func (rcv *PrivilegeSetRoutine) PrivsLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
+func (rcv *PrivilegeSetRoutine) MutatePrivs(j int, n int32) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		a := rcv._tab.Vector(o)
+		return rcv._tab.MutateInt32(a+flatbuffers.UOffsetT(j*4), n)
+	}
+	return false
+}
+
+func PrivilegeSetRoutineStart(builder *flatbuffers.Builder) {
+	builder.StartObject(3)
+}
+func PrivilegeSetRoutineAddName(builder *flatbuffers.Builder, name flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(name), 0)
+}
+func PrivilegeSetRoutineAddIsProc(builder *flatbuffers.Builder, isProc bool) {
+	builder.PrependBoolSlot(1, isProc, false)
+}
+func PrivilegeSetRoutineAddPrivs(builder *flatbuffers.Builder, privs flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(2, flatbuffers.UOffsetT(privs), 0)
+}
+func PrivilegeSetRoutineStartPrivsVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(4, numElems, 4)
+}
+func PrivilegeSetRoutineEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
+
 type PrivilegeSetDatabase struct {
 	_tab flatbuffers.Table
 }
@@ -281,8 +373,28 @@ func (rcv *PrivilegeSetDatabase) TablesLength() int {
 	return 0
 }
 
+func (rcv *PrivilegeSetDatabase) Routines(obj *PrivilegeSetRoutine, j int) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		return true
+	}
+	return false
+}
+
+func (rcv *PrivilegeSetDatabase) RoutinesLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
 func PrivilegeSetDatabaseStart(builder *flatbuffers.Builder) {
-	builder.StartObject(3)
+	builder.StartObject(4)
 }
 func PrivilegeSetDatabaseAddName(builder *flatbuffers.Builder, name flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(name), 0)
@@ -299,6 +411,12 @@ func PrivilegeSetDatabaseAddTables(builder *flatbuffers.Builder, tables flatbuff
 func PrivilegeSetDatabaseStartTablesVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
 	return builder.StartVector(4, numElems, 4)
 }
+func PrivilegeSetDatabaseAddRoutines(builder *flatbuffers.Builder, routines flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(3, flatbuffers.UOffsetT(routines), 0)
+}
+func PrivilegeSetDatabaseStartRoutinesVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(4, numElems, 4)
+}
 func PrivilegeSetDatabaseEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -562,8 +680,104 @@ func (rcv *User) Identity() []byte {
 	return nil
 }
 
+func (rcv *User) MaxQuestions() uint32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(22))
+	if o != 0 {
+		return rcv._tab.GetUint32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *User) MutateMaxQuestions(n uint32) bool {
+	return rcv._tab.MutateUint32Slot(22, n)
+}
+
+func (rcv *User) MaxUpdates() uint32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(24))
+	if o != 0 {
+		return rcv._tab.GetUint32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *User) MutateMaxUpdates(n uint32) bool {
+	return rcv._tab.MutateUint32Slot(24, n)
+}
+
+func (rcv *User) MaxConnections() uint32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(26))
+	if o != 0 {
+		return rcv._tab.GetUint32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *User) MutateMaxConnections(n uint32) bool {
+	return rcv._tab.MutateUint32Slot(26, n)
+}
+
+func (rcv *User) MaxUserConnections() uint32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(28))
+	if o != 0 {
+		return rcv._tab.GetUint32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *User) MutateMaxUserConnections(n uint32) bool {
+	return rcv._tab.MutateUint32Slot(28, n)
+}
+
+func (rcv *User) PasswordExpired() bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(30))
+	if o != 0 {
+		return rcv._tab.GetBool(o + rcv._tab.Pos)
+	}
+	return false
+}
+
+func (rcv *User) MutatePasswordExpired(n bool) bool {
+	return rcv._tab.MutateBoolSlot(30, n)
+}
+
+func (rcv *User) PasswordLifetime() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(32))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return -1
+}
+
+func (rcv *User) MutatePasswordLifetime(n int32) bool {
+	return rcv._tab.MutateInt32Slot(32, n)
+}
+
+func (rcv *User) FailedLoginAttempts() uint32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(34))
+	if o != 0 {
+		return rcv._tab.GetUint32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *User) MutateFailedLoginAttempts(n uint32) bool {
+	return rcv._tab.MutateUint32Slot(34, n)
+}
+
+func (rcv *User) PasswordLockTime() int32 {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(36))
+	if o != 0 {
+		return rcv._tab.GetInt32(o + rcv._tab.Pos)
+	}
+	return 0
+}
+
+func (rcv *User) MutatePasswordLockTime(n int32) bool {
+	return rcv._tab.MutateInt32Slot(36, n)
+}
+
 func UserStart(builder *flatbuffers.Builder) {
-	builder.StartObject(9)
+	builder.StartObject(17)
 }
 func UserAddUser(builder *flatbuffers.Builder, user flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(user), 0)
@@ -592,6 +806,30 @@ func UserAddAttributes(builder *flatbuffers.Builder, attributes flatbuffers.UOff
 func UserAddIdentity(builder *flatbuffers.Builder, identity flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(8, flatbuffers.UOffsetT(identity), 0)
 }
+func UserAddMaxQuestions(builder *flatbuffers.Builder, maxQuestions uint32) {
+	builder.PrependUint32Slot(9, maxQuestions, 0)
+}
+func UserAddMaxUpdates(builder *flatbuffers.Builder, maxUpdates uint32) {
+	builder.PrependUint32Slot(10, maxUpdates, 0)
+}
+func UserAddMaxConnections(builder *flatbuffers.Builder, maxConnections uint32) {
+	builder.PrependUint32Slot(11, maxConnections, 0)
+}
+func UserAddMaxUserConnections(builder *flatbuffers.Builder, maxUserConnections uint32) {
+	builder.PrependUint32Slot(12, maxUserConnections, 0)
+}
+func UserAddPasswordExpired(builder *flatbuffers.Builder, passwordExpired bool) {
+	builder.PrependBoolSlot(13, passwordExpired, false)
+}
+func UserAddPasswordLifetime(builder *flatbuffers.Builder, passwordLifetime int32) {
+	builder.PrependInt32Slot(14, passwordLifetime, -1)
+}
+func UserAddFailedLoginAttempts(builder *flatbuffers.Builder, failedLoginAttempts uint32) {
+	builder.PrependUint32Slot(15, failedLoginAttempts, 0)
+}
+func UserAddPasswordLockTime(builder *flatbuffers.Builder, passwordLockTime int32) {
+	builder.PrependInt32Slot(16, passwordLockTime, 0)
+}
 func UserEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
@@ -689,6 +927,84 @@ func RoleEdgeEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
 
+type DefaultRole struct {
+	_tab flatbuffers.Table
+}
+
+func GetRootAsDefaultRole(buf []byte, offset flatbuffers.UOffsetT) *DefaultRole {
+	n := flatbuffers.GetUOffsetT(buf[offset:])
+	x := &DefaultRole{}
+	x.Init(buf, n+offset)
+	return x
+}
+
+func GetSizePrefixedRootAsDefaultRole(buf []byte, offset flatbuffers.UOffsetT) *DefaultRole {
+	n := flatbuffers.GetUOffsetT(buf[offset+flatbuffers.SizeUint32:])
+	x := &DefaultRole{}
+	x.Init(buf, n+offset+flatbuffers.SizeUint32)
+	return x
+}
+
+func (rcv *DefaultRole) Init(buf []byte, i flatbuffers.UOffsetT) {
+	rcv._tab.Bytes = buf
+	rcv._tab.Pos = i
+}
+
+func (rcv *DefaultRole) Table() flatbuffers.Table {
+	return rcv._tab
+}
+
+func (rcv *DefaultRole) Host() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(4))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *DefaultRole) User() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(6))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *DefaultRole) DefaultRoleHost() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(8))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func (rcv *DefaultRole) DefaultRoleUser() []byte {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.ByteVector(o + rcv._tab.Pos)
+	}
+	return nil
+}
+
+func DefaultRoleStart(builder *flatbuffers.Builder) {
+	builder.StartObject(4)
+}
+func DefaultRoleAddHost(builder *flatbuffers.Builder, host flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(host), 0)
+}
+func DefaultRoleAddUser(builder *flatbuffers.Builder, user flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(1, flatbuffers.UOffsetT(user), 0)
+}
+func DefaultRoleAddDefaultRoleHost(builder *flatbuffers.Builder, defaultRoleHost flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(2, flatbuffers.UOffsetT(defaultRoleHost), 0)
+}
+func DefaultRoleAddDefaultRoleUser(builder *flatbuffers.Builder, defaultRoleUser flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(3, flatbuffers.UOffsetT(defaultRoleUser), 0)
+}
+func DefaultRoleEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
+	return builder.EndObject()
+}
+
 type ReplicaSourceInfo struct {
 	_tab flatbuffers.Table
 }
@@ -899,8 +1215,28 @@ func (rcv *MySQLDb) ReplicaSourceInfoLength() int {
 	return 0
 }
 
+func (rcv *MySQLDb) DefaultRoles(obj *DefaultRole, j int) bool {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		x := rcv._tab.Vector(o)
+		x += flatbuffers.UOffsetT(j) * 4
+		x = rcv._tab.Indirect(x)
+		obj.Init(rcv._tab.Bytes, x)
+		return true
+	}
+	return false
+}
+
+func (rcv *MySQLDb) DefaultRolesLength() int {
+	o := flatbuffers.UOffsetT(rcv._tab.Offset(10))
+	if o != 0 {
+		return rcv._tab.VectorLen(o)
+	}
+	return 0
+}
+
 func MySQLDbStart(builder *flatbuffers.Builder) {
-	builder.StartObject(3)
+	builder.StartObject(4)
 }
 func MySQLDbAddUser(builder *flatbuffers.Builder, user flatbuffers.UOffsetT) {
 	builder.PrependUOffsetTSlot(0, flatbuffers.UOffsetT(user), 0)
@@ -920,6 +1256,12 @@ func MySQLDbAddReplicaSourceInfo(builder *flatbuffers.Builder, replicaSourceInfo
 func MySQLDbStartReplicaSourceInfoVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
 	return builder.StartVector(4, numElems, 4)
 }
+func MySQLDbAddDefaultRoles(builder *flatbuffers.Builder, defaultRoles flatbuffers.UOffsetT) {
+	builder.PrependUOffsetTSlot(3, flatbuffers.UOffsetT(defaultRoles), 0)
+}
+func MySQLDbStartDefaultRolesVector(builder *flatbuffers.Builder, numElems int) flatbuffers.UOffsetT {
+	return builder.StartVector(4, numElems, 4)
+}
 func MySQLDbEnd(builder *flatbuffers.Builder) flatbuffers.UOffsetT {
 	return builder.EndObject()
 }
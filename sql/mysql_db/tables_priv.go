@@ -16,6 +16,7 @@ package mysql_db
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -158,7 +159,14 @@ func (conv TablesPrivConverter) EntryToRows(ctx *sql.Context, entry in_mem_table
 	var rows []sql.Row
 	for _, dbSet := range user.PrivilegeSet.GetDatabases() {
 		for _, tblSet := range dbSet.GetTables() {
-			if tblSet.Count() == 0 {
+			// Column_priv holds every privilege that has been granted on at least one of the table's columns
+			columnPrivSet := make(map[sql.PrivilegeType]struct{})
+			for _, colSet := range tblSet.GetColumns() {
+				for _, priv := range colSet.ToSlice() {
+					columnPrivSet[priv] = struct{}{}
+				}
+			}
+			if tblSet.Count() == 0 && len(columnPrivSet) == 0 {
 				continue
 			}
 			row := make(sql.Row, len(tablesPrivTblSchema))
@@ -210,6 +218,19 @@ func (conv TablesPrivConverter) EntryToRows(ctx *sql.Context, entry in_mem_table
 				return nil, err
 			}
 			row[tablesPrivTblColIndex_Table_priv] = formattedSet.(uint64)
+
+			var columnPrivs []sql.PrivilegeType
+			for priv := range columnPrivSet {
+				columnPrivs = append(columnPrivs, priv)
+			}
+			sort.Slice(columnPrivs, func(i, j int) bool {
+				return columnPrivs[i] < columnPrivs[j]
+			})
+			formattedSet, err = tablesPrivTblSchema[tablesPrivTblColIndex_Column_priv].Type.Convert(columnPrivsToSetString(columnPrivs))
+			if err != nil {
+				return nil, err
+			}
+			row[tablesPrivTblColIndex_Column_priv] = formattedSet.(uint64)
 			rows = append(rows, row)
 		}
 	}
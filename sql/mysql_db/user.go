@@ -36,6 +36,20 @@ type User struct {
 	Attributes          *string
 	Identity            string
 	IsSuperUser         bool
+	MaxQuestions        uint32
+	MaxUpdates          uint32
+	MaxConnections      uint32
+	MaxUserConnections  uint32
+	PasswordExpired     bool
+	// PasswordLifetime is the number of days that a password is valid for. A nil value uses the global
+	// default_password_lifetime, while 0 means that the password never expires.
+	PasswordLifetime *uint16
+	// FailedLoginAttempts is the number of consecutive failed logins that will temporarily lock the account, with 0
+	// disabling the lock.
+	FailedLoginAttempts uint32
+	// PasswordLockTime is the number of days that an account is locked after too many failed logins, with -1
+	// representing an unbounded lock time.
+	PasswordLockTime int32
 	//TODO: add the remaining fields
 
 	// IsRole is an additional field that states whether the User represents a role or user. In MySQL this must be a
@@ -59,6 +73,10 @@ func (u *User) NewFromRow(ctx *sql.Context, row sql.Row) (in_mem_table.Entry, er
 	if val, ok := row[userTblColIndex_password_last_changed].(time.Time); ok {
 		passwordLastChanged = val
 	}
+	var passwordLifetime *uint16
+	if val, ok := row[userTblColIndex_password_lifetime].(uint16); ok {
+		passwordLifetime = &val
+	}
 	return &User{
 		User:                row[userTblColIndex_User].(string),
 		Host:                row[userTblColIndex_Host].(string),
@@ -69,6 +87,12 @@ func (u *User) NewFromRow(ctx *sql.Context, row sql.Row) (in_mem_table.Entry, er
 		Locked:              row[userTblColIndex_account_locked].(uint16) == 2,
 		Attributes:          attributes,
 		Identity:            row[userTblColIndex_identity].(string),
+		MaxQuestions:        row[userTblColIndex_max_questions].(uint32),
+		MaxUpdates:          row[userTblColIndex_max_updates].(uint32),
+		MaxConnections:      row[userTblColIndex_max_connections].(uint32),
+		MaxUserConnections:  row[userTblColIndex_max_user_connections].(uint32),
+		PasswordExpired:     row[userTblColIndex_password_expired].(uint16) == 2,
+		PasswordLifetime:    passwordLifetime,
 		IsRole:              false,
 	}, nil
 }
@@ -79,8 +103,12 @@ func (u *User) UpdateFromRow(ctx *sql.Context, row sql.Row) (in_mem_table.Entry,
 	if err != nil {
 		return nil, err
 	}
-	updatedEntry.(*User).IsRole = u.IsRole
-	return updatedEntry, nil
+	updatedUser := updatedEntry.(*User)
+	updatedUser.IsRole = u.IsRole
+	// The password locking options do not have their own columns, so they're retained from the existing entry
+	updatedUser.FailedLoginAttempts = u.FailedLoginAttempts
+	updatedUser.PasswordLockTime = u.PasswordLockTime
+	return updatedUser, nil
 }
 
 // ToRow implements the interface in_mem_table.Entry.
@@ -100,6 +128,16 @@ func (u *User) ToRow(ctx *sql.Context) sql.Row {
 	row[userTblColIndex_authentication_string] = u.Password
 	row[userTblColIndex_password_last_changed] = u.PasswordLastChanged
 	row[userTblColIndex_identity] = u.Identity
+	row[userTblColIndex_max_questions] = u.MaxQuestions
+	row[userTblColIndex_max_updates] = u.MaxUpdates
+	row[userTblColIndex_max_connections] = u.MaxConnections
+	row[userTblColIndex_max_user_connections] = u.MaxUserConnections
+	if u.PasswordExpired {
+		row[userTblColIndex_password_expired] = uint16(2)
+	}
+	if u.PasswordLifetime != nil {
+		row[userTblColIndex_password_lifetime] = *u.PasswordLifetime
+	}
 	if u.Locked {
 		row[userTblColIndex_account_locked] = uint16(2)
 	}
@@ -125,6 +163,16 @@ func (u *User) Equals(ctx *sql.Context, otherEntry in_mem_table.Entry) bool {
 		u.Identity != otherUser.Identity ||
 		!u.PasswordLastChanged.Equal(otherUser.PasswordLastChanged) ||
 		u.Locked != otherUser.Locked ||
+		u.MaxQuestions != otherUser.MaxQuestions ||
+		u.MaxUpdates != otherUser.MaxUpdates ||
+		u.MaxConnections != otherUser.MaxConnections ||
+		u.MaxUserConnections != otherUser.MaxUserConnections ||
+		u.PasswordExpired != otherUser.PasswordExpired ||
+		u.PasswordLifetime == nil && otherUser.PasswordLifetime != nil ||
+		u.PasswordLifetime != nil && otherUser.PasswordLifetime == nil ||
+		(u.PasswordLifetime != nil && *u.PasswordLifetime != *otherUser.PasswordLifetime) ||
+		u.FailedLoginAttempts != otherUser.FailedLoginAttempts ||
+		u.PasswordLockTime != otherUser.PasswordLockTime ||
 		!u.PrivilegeSet.Equals(otherUser.PrivilegeSet) ||
 		u.Attributes == nil && otherUser.Attributes != nil ||
 		u.Attributes != nil && otherUser.Attributes == nil ||
@@ -0,0 +1,139 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import "strings"
+
+const (
+	optimizerTraceSysVarName           = "optimizer_trace"
+	optimizerTraceOffsetSysVarName     = "optimizer_trace_offset"
+	optimizerTraceLimitSysVarName      = "optimizer_trace_limit"
+	optimizerTraceMaxMemSizeSysVarName = "optimizer_trace_max_mem_size"
+)
+
+// OptimizerTrace is the trace of the analysis of a single statement, as shown in information_schema.OPTIMIZER_TRACE.
+type OptimizerTrace struct {
+	// Query is the text of the traced statement.
+	Query string
+	// Trace is the JSON document describing the analysis of the statement.
+	Trace string
+	// MissingBytesBeyondMaxMemSize is the number of bytes that were cut from the end of the trace, as it was larger
+	// than the optimizer_trace_max_mem_size system variable.
+	MissingBytesBeyondMaxMemSize int
+}
+
+// AppendOptimizerTrace appends the given trace to the traces of a session, and returns the traces that are kept
+// according to the given values of the optimizer_trace_offset and optimizer_trace_limit system variables. A negative
+// offset keeps the traces of the most recent statements, while a positive offset keeps the traces of the first
+// statements that were traced, ignoring the ones that follow.
+func AppendOptimizerTrace(traces []OptimizerTrace, trace OptimizerTrace, offset, limit int64) []OptimizerTrace {
+	if offset < 0 {
+		traces = append(traces, trace)
+		if int64(len(traces)) > -offset {
+			traces = traces[int64(len(traces))+offset:]
+		}
+		return traces
+	}
+	if limit > 0 && int64(len(traces)) < offset+limit {
+		traces = append(traces, trace)
+	}
+	return traces
+}
+
+// VisibleOptimizerTraces returns the traces that are shown in information_schema.OPTIMIZER_TRACE, out of the traces
+// that were kept by AppendOptimizerTrace for the same offset and limit.
+func VisibleOptimizerTraces(traces []OptimizerTrace, offset, limit int64) []OptimizerTrace {
+	if limit <= 0 {
+		return nil
+	}
+	if offset > 0 {
+		if offset >= int64(len(traces)) {
+			return nil
+		}
+		traces = traces[offset:]
+	}
+	if int64(len(traces)) > limit {
+		traces = traces[:limit]
+	}
+	return traces
+}
+
+// OptimizerTraceFlags returns whether the optimizer_trace system variable enables tracing for the session, and whether
+// the traces should be written on a single line. The variable holds a comma-separated list of flags, such as
+// "enabled=on,one_line=off".
+func OptimizerTraceFlags(ctx *Context) (enabled bool, oneLine bool, err error) {
+	val, err := ctx.GetSessionVariable(ctx, optimizerTraceSysVarName)
+	if err != nil {
+		return false, false, err
+	}
+	str, _ := val.(string)
+	for _, flag := range strings.Split(str, ",") {
+		name, value, ok := strings.Cut(strings.TrimSpace(flag), "=")
+		if !ok {
+			continue
+		}
+		on := strings.EqualFold(strings.TrimSpace(value), "on")
+		switch strings.ToLower(strings.TrimSpace(name)) {
+		case "enabled":
+			enabled = on
+		case "one_line":
+			oneLine = on
+		}
+	}
+	return enabled, oneLine, nil
+}
+
+// OptimizerTraceWindow returns the values of the optimizer_trace_offset and optimizer_trace_limit system variables,
+// which determine the statements whose traces are kept.
+func OptimizerTraceWindow(ctx *Context) (offset int64, limit int64, err error) {
+	offset, err = intSessionVariable(ctx, optimizerTraceOffsetSysVarName, -1)
+	if err != nil {
+		return 0, 0, err
+	}
+	limit, err = intSessionVariable(ctx, optimizerTraceLimitSysVarName, 1)
+	if err != nil {
+		return 0, 0, err
+	}
+	return offset, limit, nil
+}
+
+// OptimizerTraceMaxMemSize returns the value of the optimizer_trace_max_mem_size system variable, which is the largest
+// size of a trace that is kept.
+func OptimizerTraceMaxMemSize(ctx *Context) (int64, error) {
+	return intSessionVariable(ctx, optimizerTraceMaxMemSizeSysVarName, 1048576)
+}
+
+// intSessionVariable returns the value of the integer or boolean system variable given, or the default given if the
+// variable has an unexpected type.
+func intSessionVariable(ctx *Context, name string, def int64) (int64, error) {
+	val, err := ctx.GetSessionVariable(ctx, name)
+	if err != nil {
+		return 0, err
+	}
+	switch val := val.(type) {
+	case int64:
+		return val, nil
+	case int:
+		return int64(val), nil
+	case int32:
+		return int64(val), nil
+	case int8:
+		return int64(val), nil
+	case uint64:
+		return int64(val), nil
+	default:
+		return def, nil
+	}
+}
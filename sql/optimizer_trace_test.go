@@ -0,0 +1,58 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptimizerTraceWindow(t *testing.T) {
+	queries := func(traces []OptimizerTrace) []string {
+		var res []string
+		for _, trace := range traces {
+			res = append(res, trace.Query)
+		}
+		return res
+	}
+	appendAll := func(offset, limit int64, queries ...string) []OptimizerTrace {
+		var traces []OptimizerTrace
+		for _, q := range queries {
+			traces = AppendOptimizerTrace(traces, OptimizerTrace{Query: q}, offset, limit)
+		}
+		return traces
+	}
+
+	tests := []struct {
+		name    string
+		offset  int64
+		limit   int64
+		kept    []string
+		visible []string
+	}{
+		{name: "default keeps the last statement", offset: -1, limit: 1, kept: []string{"q4"}, visible: []string{"q4"}},
+		{name: "negative offset keeps the last statements", offset: -3, limit: 2, kept: []string{"q2", "q3", "q4"}, visible: []string{"q2", "q3"}},
+		{name: "positive offset keeps the first statements", offset: 1, limit: 2, kept: []string{"q1", "q2", "q3"}, visible: []string{"q2", "q3"}},
+		{name: "zero limit shows nothing", offset: 0, limit: 0, kept: nil, visible: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			traces := appendAll(tt.offset, tt.limit, "q1", "q2", "q3", "q4")
+			require.Equal(t, tt.kept, queries(traces))
+			require.Equal(t, tt.visible, queries(VisibleOptimizerTraces(traces, tt.offset, tt.limit)))
+		})
+	}
+}
@@ -0,0 +1,380 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// eventIntervalUnits are the units that may be used in the EVERY clause of an event's schedule.
+var eventIntervalUnits = []string{
+	"YEAR", "QUARTER", "MONTH", "DAY", "HOUR", "MINUTE", "WEEK", "SECOND", "YEAR_MONTH", "DAY_HOUR",
+	"DAY_MINUTE", "DAY_SECOND", "HOUR_MINUTE", "HOUR_SECOND", "MINUTE_SECOND",
+}
+
+// eventBodyProcedureName is the name of the procedure used to parse the body of an event, which may be a BEGIN...END
+// block that is otherwise only valid inside of a stored routine.
+const eventBodyProcedureName = "__event_body__"
+
+// parseEventStatement parses the CREATE EVENT, ALTER EVENT, DROP EVENT, SHOW EVENTS and SHOW CREATE EVENT statements.
+func parseEventStatement(ctx *sql.Context, s *statementScanner) (sql.Node, bool, error) {
+	switch {
+	case s.acceptKeywords("create"):
+		definer, ok := parseEventDefiner(s)
+		if !ok {
+			return nil, false, nil
+		}
+		node, err := parseCreateEvent(ctx, s, definer)
+		return node, true, err
+	case s.acceptKeywords("alter"):
+		definer, ok := parseEventDefiner(s)
+		if !ok {
+			return nil, false, nil
+		}
+		node, err := parseAlterEvent(ctx, s, definer)
+		return node, true, err
+	case s.acceptKeywords("drop", "event"):
+		node, err := parseDropEvent(ctx, s)
+		return node, true, err
+	case s.acceptKeywords("show", "events"):
+		node, err := parseShowEvents(ctx, s)
+		return node, true, err
+	case s.acceptKeywords("show", "create", "event"):
+		dbName, name, err := s.qualifiedIdentifier()
+		if err != nil {
+			return nil, true, err
+		}
+		if !s.atEnd() {
+			return nil, true, s.syntaxError()
+		}
+		return plan.NewShowCreateEvent(sql.UnresolvedDatabase(dbName), name), true, nil
+	default:
+		return nil, false, nil
+	}
+}
+
+// parseEventDefiner parses the optional DEFINER clause and the EVENT keyword of a CREATE EVENT or ALTER EVENT
+// statement. Returns false if the statement is not for an event.
+func parseEventDefiner(s *statementScanner) (string, bool) {
+	definer := ""
+	if s.acceptKeywords("definer") {
+		if !s.acceptPunct("=") {
+			return "", false
+		}
+		var err error
+		if definer, err = s.accountName(); err != nil {
+			return "", false
+		}
+	}
+	return definer, s.acceptKeywords("event")
+}
+
+func parseCreateEvent(ctx *sql.Context, s *statementScanner, definer string) (sql.Node, error) {
+	ifNotExists := s.acceptKeywords("if", "not", "exists")
+	dbName, name, err := s.qualifiedIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	if err = s.expectKeywords("on", "schedule"); err != nil {
+		return nil, err
+	}
+	sched, err := parseEventSchedule(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	onCompletionPreserve := false
+	if s.acceptKeywords("on", "completion") {
+		onCompletionPreserve = !s.acceptKeywords("not")
+		if err = s.expectKeywords("preserve"); err != nil {
+			return nil, err
+		}
+	}
+	status := plan.EventStatus_Enable
+	if st, ok := parseEventStatus(s); ok {
+		status = st
+	}
+	comment := ""
+	if s.acceptKeywords("comment") {
+		if comment, err = s.stringLiteral(); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.expectKeywords("do"); err != nil {
+		return nil, err
+	}
+	body, bodyStr, err := parseEventBody(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+
+	return plan.NewCreateEvent(
+		sql.UnresolvedDatabase(dbName),
+		name,
+		definer,
+		sched.at,
+		sched.every,
+		sched.starts,
+		sched.ends,
+		onCompletionPreserve,
+		status,
+		comment,
+		body,
+		bodyStr,
+		ifNotExists,
+		s.query,
+	), nil
+}
+
+func parseAlterEvent(ctx *sql.Context, s *statementScanner, definer string) (sql.Node, error) {
+	dbName, name, err := s.qualifiedIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	alterEvent := plan.NewAlterEvent(sql.UnresolvedDatabase(dbName), name, definer)
+	changed := definer != ""
+
+	if s.acceptKeywords("on", "schedule") {
+		sched, err := parseEventSchedule(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		alterEvent.AlterSchedule = true
+		alterEvent.At, alterEvent.Every, alterEvent.Starts, alterEvent.Ends = sched.at, sched.every, sched.starts, sched.ends
+		changed = true
+	}
+	if s.acceptKeywords("on", "completion") {
+		alterEvent.AlterOnComp = true
+		alterEvent.OnCompPreserve = !s.acceptKeywords("not")
+		if err = s.expectKeywords("preserve"); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	if s.acceptKeywords("rename", "to") {
+		alterEvent.AlterName = true
+		alterEvent.RenameToDb, alterEvent.RenameToName, err = s.qualifiedIdentifier()
+		if err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	if status, ok := parseEventStatus(s); ok {
+		alterEvent.AlterStatus = true
+		alterEvent.Status = status
+		changed = true
+	}
+	if s.acceptKeywords("comment") {
+		alterEvent.AlterComment = true
+		if alterEvent.Comment, err = s.stringLiteral(); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	if s.acceptKeywords("do") {
+		alterEvent.AlterDefinition = true
+		alterEvent.Body, alterEvent.BodyString, err = parseEventBody(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	if !changed || !s.atEnd() {
+		return nil, s.syntaxError()
+	}
+	return alterEvent, nil
+}
+
+func parseDropEvent(ctx *sql.Context, s *statementScanner) (sql.Node, error) {
+	ifExists := s.acceptKeywords("if", "exists")
+	dbName, name, err := s.qualifiedIdentifier()
+	if err != nil {
+		return nil, err
+	}
+	if !s.atEnd() {
+		return nil, s.syntaxError()
+	}
+	return plan.NewDropEvent(sql.UnresolvedDatabase(dbName), name, ifExists), nil
+}
+
+func parseShowEvents(ctx *sql.Context, s *statementScanner) (sql.Node, error) {
+	dbName := ""
+	if s.acceptKeywords("from") || s.acceptKeywords("in") {
+		var err error
+		if dbName, err = s.identifier(); err != nil {
+			return nil, err
+		}
+	}
+
+	var filter sql.Expression
+	if s.acceptKeywords("like") {
+		pattern, err := s.stringLiteral()
+		if err != nil {
+			return nil, err
+		}
+		filter = expression.NewLike(
+			expression.NewUnresolvedColumn("Name"),
+			expression.NewLiteral(pattern, types.LongText),
+			nil,
+		)
+	} else if s.acceptKeywords("where") {
+		var err error
+		if filter, err = convertExpressionString(ctx, s.rest()); err != nil {
+			return nil, err
+		}
+	}
+	if !s.atEnd() {
+		return nil, s.syntaxError()
+	}
+
+	var node sql.Node = plan.NewShowEvents(sql.UnresolvedDatabase(dbName))
+	if filter != nil {
+		node = plan.NewFilter(filter, node)
+	}
+	return node, nil
+}
+
+// eventSchedule is the parsed ON SCHEDULE clause of an event.
+type eventSchedule struct {
+	at     sql.Expression
+	every  *expression.Interval
+	starts sql.Expression
+	ends   sql.Expression
+}
+
+// eventClauseKeywords are the keywords that may follow an expression in an event's schedule.
+var eventClauseKeywords = []string{"starts", "ends", "on", "rename", "enable", "disable", "comment", "do"}
+
+func parseEventSchedule(ctx *sql.Context, s *statementScanner) (eventSchedule, error) {
+	var sched eventSchedule
+	if s.acceptKeywords("at") {
+		at, err := parseEventExpression(ctx, s)
+		if err != nil {
+			return eventSchedule{}, err
+		}
+		sched.at = at
+		return sched, nil
+	}
+
+	if err := s.expectKeywords("every"); err != nil {
+		return eventSchedule{}, err
+	}
+	valueStr, err := s.textUntilKeywords(eventIntervalUnits...)
+	if err != nil {
+		return eventSchedule{}, err
+	}
+	value, err := convertExpressionString(ctx, valueStr)
+	if err != nil {
+		return eventSchedule{}, err
+	}
+	unit := s.next()
+	if unit.kind != tokenWord {
+		return eventSchedule{}, s.syntaxError()
+	}
+	sched.every = expression.NewInterval(value, unit.val)
+
+	if s.acceptKeywords("starts") {
+		if sched.starts, err = parseEventExpression(ctx, s); err != nil {
+			return eventSchedule{}, err
+		}
+	}
+	if s.acceptKeywords("ends") {
+		if sched.ends, err = parseEventExpression(ctx, s); err != nil {
+			return eventSchedule{}, err
+		}
+	}
+	return sched, nil
+}
+
+// parseEventExpression parses a timestamp expression of an event's schedule, such as
+// `CURRENT_TIMESTAMP + INTERVAL 1 DAY`.
+func parseEventExpression(ctx *sql.Context, s *statementScanner) (sql.Expression, error) {
+	exprStr, err := s.textUntilKeywords(eventClauseKeywords...)
+	if err != nil {
+		return nil, err
+	}
+	return convertExpressionString(ctx, exprStr)
+}
+
+func parseEventStatus(s *statementScanner) (plan.EventStatus, bool) {
+	switch {
+	case s.acceptKeywords("enable"):
+		return plan.EventStatus_Enable, true
+	case s.acceptKeywords("disable", "on", "slave"):
+		return plan.EventStatus_DisableOnSlave, true
+	case s.acceptKeywords("disable"):
+		return plan.EventStatus_Disable, true
+	default:
+		return plan.EventStatus_Enable, false
+	}
+}
+
+// parseEventBody parses the remainder of the statement as the body of an event, returning the parsed body along with
+// its original text.
+func parseEventBody(ctx *sql.Context, s *statementScanner) (sql.Node, string, error) {
+	bodyStr := s.rest()
+	if bodyStr == "" {
+		return nil, "", s.syntaxError()
+	}
+	body, err := parseEventBodyString(ctx, bodyStr)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, bodyStr, nil
+}
+
+// parseEventBodyString parses the body of an event. The body is parsed as the body of a stored procedure, since
+// BEGIN...END blocks are not valid as top-level statements.
+func parseEventBodyString(ctx *sql.Context, bodyStr string) (sql.Node, error) {
+	procQuery := "CREATE PROCEDURE " + eventBodyProcedureName + "() " + bodyStr
+	stmt, err := sqlparser.Parse(procQuery)
+	if err != nil {
+		return nil, sql.ErrSyntaxError.New(err.Error())
+	}
+	ddl, ok := stmt.(*sqlparser.DDL)
+	if !ok || ddl.ProcedureSpec == nil {
+		return nil, sql.ErrSyntaxError.New(bodyStr)
+	}
+	node, err := convertCreateProcedure(ctx, procQuery, ddl)
+	if err != nil {
+		return nil, err
+	}
+	return node.(*plan.CreateProcedure).Procedure.Body, nil
+}
+
+// EventBodyProcedure returns a stored procedure that runs the body of the given event, for the event scheduler.
+func EventBodyProcedure(ctx *sql.Context, event plan.EventDetails) (*plan.Procedure, error) {
+	body, err := parseEventBodyString(ctx, event.Body)
+	if err != nil {
+		return nil, err
+	}
+	return plan.NewProcedure(
+		event.Name,
+		event.Definer,
+		nil,
+		plan.ProcedureSecurityContext_Definer,
+		event.Comment,
+		nil,
+		event.CreateEventStatement(),
+		body,
+		event.CreatedAt,
+		event.LastAltered,
+	), nil
+}
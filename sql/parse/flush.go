@@ -0,0 +1,59 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"fmt"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+// parseFlushTablesStatement parses the FLUSH TABLES statement, which the vitess grammar does not support. Since FLUSH
+// statements are never written to a binary log, NO_WRITE_TO_BINLOG and LOCAL are accepted and ignored.
+func parseFlushTablesStatement(ctx *sql.Context, s *statementScanner) (sql.Node, bool, error) {
+	if !s.acceptKeywords("flush") {
+		return nil, false, nil
+	}
+	if !s.acceptKeywords("no_write_to_binlog") {
+		s.acceptKeywords("local")
+	}
+	if !s.acceptKeywords("tables") && !s.acceptKeywords("table") {
+		return nil, false, nil
+	}
+
+	var tables []sql.Node
+	for !s.atEnd() && !s.peekKeywords("with") && !s.peekKeywords("for") {
+		if len(tables) > 0 {
+			if err := s.expectPunct(","); err != nil {
+				return nil, true, err
+			}
+		}
+		db, name, err := s.qualifiedIdentifier()
+		if err != nil {
+			return nil, true, err
+		}
+		tables = append(tables, plan.NewUnresolvedTable(name, db))
+	}
+
+	readLock := s.acceptKeywords("with", "read", "lock")
+	if !readLock && s.acceptKeywords("for", "export") {
+		return nil, true, fmt.Errorf("FOR EXPORT not supported")
+	}
+	if !s.atEnd() {
+		return nil, true, s.syntaxError()
+	}
+	return plan.NewFlushTables(tables, readLock), true, nil
+}
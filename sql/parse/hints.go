@@ -0,0 +1,247 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"context"
+	"strings"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// queryBlockHintsKey is the context key of the optimizer hints of each query block of the statement being converted.
+type queryBlockHintsKey struct{}
+
+// queryBlock is a SELECT of a statement, along with the optimizer hints written in it and the names of the tables in
+// its FROM clause.
+type queryBlock struct {
+	sel    *sqlparser.Select
+	name   string
+	hints  sql.OptimizerHints
+	tables map[string]struct{}
+}
+
+// withQueryBlockHints returns a context that carries the optimizer hints of each query block of the statement given,
+// for convertSelect to attach to the query blocks it converts.
+func withQueryBlockHints(ctx *sql.Context, stmt sqlparser.Statement) *sql.Context {
+	hints := resolveQueryBlockHints(ctx, stmt)
+	if len(hints) == 0 {
+		return ctx
+	}
+	return ctx.WithContext(context.WithValue(ctx.Context, queryBlockHintsKey{}, hints))
+}
+
+// queryBlockHints returns the optimizer hints that apply to the query block given, along with whether the statement
+// being converted was scanned for hints. A SELECT converted outside of a statement, such as the definition of a view,
+// wasn't scanned, and its hints must be read from its own comment.
+func queryBlockHints(ctx *sql.Context, s *sqlparser.Select) (sql.OptimizerHints, bool) {
+	hints, ok := ctx.Value(queryBlockHintsKey{}).(map[*sqlparser.Select]sql.OptimizerHints)
+	if !ok {
+		return nil, false
+	}
+	h, ok := hints[s]
+	return h, ok
+}
+
+// resolveQueryBlockHints parses the optimizer hint comments of the statement given, and returns the hints that apply
+// to each of its query blocks. Like MySQL, a hint that names another query block with @name is moved to the query
+// block given that name by a QB_NAME hint. Hints that can't be used are dropped with a warning: those naming a query
+// block or a table that doesn't exist, and those conflicting with an earlier hint of the same query block. Hints that
+// apply to the whole statement, such as SET_VAR, are left for the engine to apply.
+func resolveQueryBlockHints(ctx *sql.Context, stmt sqlparser.Statement) map[*sqlparser.Select]sql.OptimizerHints {
+	var comments sqlparser.Comments
+	switch stmt := stmt.(type) {
+	case *sqlparser.Insert:
+		comments = stmt.Comments
+	case *sqlparser.Update:
+		comments = stmt.Comments
+	case *sqlparser.Delete:
+		comments = stmt.Comments
+	}
+	if comment := optimizerHintComment(comments); comment != "" {
+		// Hints of data modification statements aren't assigned to query blocks, but their syntax is still checked
+		if _, warning := sql.ParseOptimizerHints(comment); warning != nil {
+			ctx.Session.Warn(warning)
+		}
+	}
+
+	var blocks []*queryBlock
+	var visit sqlparser.Visit
+	visit = func(node sqlparser.SQLNode) (bool, error) {
+		sel, ok := node.(*sqlparser.Select)
+		if !ok {
+			return true, nil
+		}
+		qb := &queryBlock{sel: sel, tables: fromTableNames(sel.From, nil)}
+		if comment := optimizerHintComment(sel.Comments); comment != "" {
+			var warning *sql.Warning
+			qb.hints, warning = sql.ParseOptimizerHints(comment)
+			if warning != nil {
+				ctx.Session.Warn(warning)
+			}
+		}
+		blocks = append(blocks, qb)
+		// The common table expressions of a SELECT aren't part of its walkable subtree
+		if sel.With != nil {
+			for _, cte := range sel.With.Ctes {
+				if err := sqlparser.Walk(visit, cte); err != nil {
+					return false, err
+				}
+			}
+		}
+		return true, nil
+	}
+	_ = sqlparser.Walk(visit, stmt)
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	named := make(map[string]*queryBlock)
+	for _, qb := range blocks {
+		for _, h := range qb.hints.OfType(sql.HintQbName) {
+			if h.QueryBlock != "" {
+				continue
+			}
+			key := strings.ToLower(h.Name)
+			if _, ok := named[key]; ok || qb.name != "" {
+				ctx.Warn(sql.ERWarnConflictingHint, "Hint %s is ignored as conflicting/duplicated", h.String())
+				continue
+			}
+			qb.name = h.Name
+			named[key] = qb
+		}
+	}
+
+	assigned := make(map[*queryBlock]sql.OptimizerHints)
+	for _, qb := range blocks {
+		for _, h := range qb.hints {
+			if h.Type == sql.HintMaxExecutionTime && qb.sel != stmt {
+				ctx.Warn(sql.ERWarnUnsupportedMaxExecutionTime, "MAX_EXECUTION_TIME hint is supported by top-level standalone SELECT statements only")
+				continue
+			}
+			if h.IsStatementLevel() {
+				continue
+			}
+			if h.Type == sql.HintQbName && h.QueryBlock == "" {
+				if strings.EqualFold(h.Name, qb.name) {
+					assigned[qb] = append(assigned[qb], h)
+				}
+				continue
+			}
+
+			target := qb
+			if name := hintQueryBlock(h); name != "" {
+				target = named[strings.ToLower(name)]
+				if target == nil {
+					ctx.Warn(sql.ERWarnUnknownQbName, "Query block name %s is not found for %s hint", name, h.Type)
+					continue
+				}
+			}
+			if isIndexHint(h) {
+				if _, ok := target.tables[strings.ToLower(h.Table().Name)]; !ok {
+					ctx.Warn(sql.ERUnresolvedHintName, "Unresolved name %s for %s hint", h.Table().String(), h.Type)
+					continue
+				}
+			}
+			assigned[target] = append(assigned[target], h)
+		}
+	}
+
+	ret := make(map[*sqlparser.Select]sql.OptimizerHints, len(blocks))
+	for _, qb := range blocks {
+		ret[qb.sel] = assigned[qb].WithoutConflicts(ctx)
+	}
+	return ret
+}
+
+// hintQueryBlock returns the query block the hint given names, either with a leading @name argument or by qualifying
+// each of its tables with the same @name, or the empty string if it applies to the query block it's written in.
+func hintQueryBlock(h *sql.OptimizerHint) string {
+	if h.QueryBlock != "" || len(h.Tables) == 0 {
+		return h.QueryBlock
+	}
+	name := h.Tables[0].QueryBlock
+	for _, t := range h.Tables[1:] {
+		if !strings.EqualFold(t.QueryBlock, name) {
+			return ""
+		}
+	}
+	return name
+}
+
+// isIndexHint returns whether the hint given controls the indexes of a single table.
+func isIndexHint(h *sql.OptimizerHint) bool {
+	switch h.Type {
+	case sql.HintIndex, sql.HintNoIndex, sql.HintJoinIndex, sql.HintNoJoinIndex, sql.HintOrderIndex,
+		sql.HintNoOrderIndex, sql.HintNoRangeOptimization:
+		return true
+	default:
+		return false
+	}
+}
+
+// optimizerHintComment returns the first optimizer hint comment of those given, or the empty string if there is none.
+func optimizerHintComment(comments sqlparser.Comments) string {
+	for _, c := range comments {
+		if sql.IsOptimizerHintComment(string(c)) {
+			return string(c)
+		}
+	}
+	return ""
+}
+
+// fromTableNames adds the lower-cased names that the tables of a FROM clause are referred to by to the set given.
+func fromTableNames(exprs sqlparser.TableExprs, names map[string]struct{}) map[string]struct{} {
+	if names == nil {
+		names = make(map[string]struct{})
+	}
+	for _, e := range exprs {
+		switch e := e.(type) {
+		case *sqlparser.AliasedTableExpr:
+			if !e.As.IsEmpty() {
+				names[strings.ToLower(e.As.String())] = struct{}{}
+			} else if t, ok := e.Expr.(sqlparser.TableName); ok {
+				names[strings.ToLower(t.Name.String())] = struct{}{}
+			}
+		case *sqlparser.JoinTableExpr:
+			fromTableNames(sqlparser.TableExprs{e.LeftExpr, e.RightExpr}, names)
+		case *sqlparser.ParenTableExpr:
+			fromTableNames(e.Exprs, names)
+		case *sqlparser.JSONTableExpr:
+			names[strings.ToLower(e.Alias.String())] = struct{}{}
+		}
+	}
+	return names
+}
+
+// selectHintsComment returns the optimizer hint comment to attach to the FROM clause of the query block given.
+func selectHintsComment(ctx *sql.Context, s *sqlparser.Select) string {
+	hints, ok := queryBlockHints(ctx, s)
+	if !ok {
+		comment := optimizerHintComment(s.Comments)
+		if comment == "" {
+			return ""
+		}
+		parsed, _ := sql.ParseOptimizerHints(comment)
+		for _, h := range parsed {
+			if !h.IsStatementLevel() {
+				hints = append(hints, h)
+			}
+		}
+	}
+	return hints.String()
+}
@@ -53,7 +53,7 @@ var (
 	tableCollationOptionRegex = regexp.MustCompile(`(?i)(DEFAULT)?\s+COLLATE((\s*=?\s*)|\s+)([A-Za-z0-9_]+)`)
 )
 
-var describeSupportedFormats = []string{"tree"}
+var describeSupportedFormats = []string{"tree", "json"}
 
 // These constants aren't exported from vitess for some reason. This could be removed if we changed this.
 const (
@@ -90,12 +90,22 @@ func parse(ctx *sql.Context, query string, multi bool) (sql.Node, string, string
 	var parsed string
 	var remainder string
 
+	// The vitess grammar doesn't support most PARTITION BY clauses, so they're parsed separately
+	stmtText, partitionClause, clauseStart, clauseLen := splitCreateTablePartitioning(s)
+	stmtText, formatStart, formatLen := quoteExplainFormat(stmtText)
+
 	parsed = s
 	if !multi {
-		stmt, err = sqlparser.Parse(s)
+		stmt, err = sqlparser.Parse(stmtText)
 	} else {
 		var ri int
-		stmt, ri, err = sqlparser.ParseOne(s)
+		stmt, ri, err = sqlparser.ParseOne(stmtText)
+		if formatLen != 0 && ri > formatStart {
+			ri -= formatLen
+		}
+		if partitionClause != "" && ri > clauseStart {
+			ri += clauseLen
+		}
 		if ri != 0 && ri < len(s) {
 			parsed = s[:ri]
 			parsed = strings.TrimSpace(parsed)
@@ -111,10 +121,22 @@ func parse(ctx *sql.Context, query string, multi bool) (sql.Node, string, string
 			ctx.Warn(0, "query was empty after trimming comments, so it will be ignored")
 			return plan.Nothing, parsed, remainder, nil
 		}
+		if node, end, ok, supplementalErr := parseSupplementalStatement(ctx, s); ok && (multi || end == len(s)) {
+			parsed, remainder = s, ""
+			if end < len(s) {
+				parsed = strings.TrimSpace(s[:end-1])
+				remainder = s[end:]
+			}
+			return node, parsed, remainder, supplementalErr
+		}
 		return nil, parsed, remainder, sql.ErrSyntaxError.New(err.Error())
 	}
 
+	ctx = withQueryBlockHints(ctx, stmt)
 	node, err := convert(ctx, stmt, s)
+	if err == nil && partitionClause != "" {
+		node, err = withPartitionOptions(ctx, node, partitionClause)
+	}
 
 	return node, parsed, remainder, err
 }
@@ -422,12 +444,18 @@ func convertExplain(ctx *sql.Context, n *sqlparser.Explain) (sql.Node, error) {
 		return nil, err
 	}
 
+	if n.Analyze {
+		return plan.NewExplainAnalyze(child), nil
+	}
+
 	explainFmt := sqlparser.TreeStr
 	switch strings.ToLower(n.ExplainFormat) {
 	case "", sqlparser.TreeStr:
 	// tree format, do nothing
 	case "debug":
 		explainFmt = "debug"
+	case "json":
+		explainFmt = "json"
 	default:
 		return nil, errInvalidDescribeFormat.New(
 			n.ExplainFormat,
@@ -438,6 +466,28 @@ func convertExplain(ctx *sql.Context, n *sqlparser.Explain) (sql.Node, error) {
 	return plan.NewDescribeQuery(explainFmt, child), nil
 }
 
+// quoteExplainFormat quotes the format of an EXPLAIN FORMAT=JSON statement, since JSON is a keyword that the vitess
+// grammar doesn't accept as a format. Returns the statement with the quoted format, along with the offset and length
+// of the text that was added. The length is zero if the statement is not an EXPLAIN statement with the JSON format.
+func quoteExplainFormat(query string) (string, int, int) {
+	if !mayStartWithKeyword(query, "explain", "desc") {
+		return query, 0, 0
+	}
+	s, err := newStatementScanner(query)
+	if err != nil {
+		return query, 0, 0
+	}
+	if !s.acceptKeywords("explain") && !s.acceptKeywords("describe") && !s.acceptKeywords("desc") {
+		return query, 0, 0
+	}
+	s.acceptKeywords("analyze")
+	if !s.acceptKeywords("format") || !s.acceptPunct("=") || !s.peekKeywords("json") {
+		return query, 0, 0
+	}
+	t := s.peek()
+	return query[:t.start] + "`" + t.val + "`" + query[t.end:], t.start, 2
+}
+
 func convertPrepare(ctx *sql.Context, n *sqlparser.Prepare) (sql.Node, error) {
 	childStmt, err := sqlparser.Parse(n.Expr)
 	if err != nil {
@@ -1053,9 +1103,11 @@ func convertSelect(ctx *sql.Context, s *sqlparser.Select) (sql.Node, error) {
 		return nil, err
 	}
 
-	// If the top level node can store comments and one was provided, store it.
-	if cn, ok := node.(sql.CommentedNode); ok && len(s.Comments) > 0 {
-		node = cn.WithComment(string(s.Comments[0]))
+	// If the top level node can store comments, store the optimizer hints of this query block for the analyzer
+	if cn, ok := node.(sql.CommentedNode); ok {
+		if comment := selectHintsComment(ctx, s); comment != "" {
+			node = cn.WithComment(comment)
+		}
 	}
 
 	if s.Where != nil {
@@ -1225,6 +1277,9 @@ func convertDDL(ctx *sql.Context, query string, c *sqlparser.DDL) (sql.Node, err
 		}
 		return convertDropTable(ctx, c)
 	case sqlparser.AlterStr:
+		if c.PartitionSpec != nil {
+			return convertAlterPartition(ctx, query)
+		}
 		return convertAlterTable(ctx, c)
 	case sqlparser.RenameStr:
 		return convertRenameTable(ctx, c)
@@ -1653,9 +1708,10 @@ func convertLockTables(ctx *sql.Context, s *sqlparser.LockTables) (sql.Node, err
 		}
 
 		write := tbl.Lock == sqlparser.LockWrite || tbl.Lock == sqlparser.LockLowPriorityWrite
+		local := tbl.Lock == sqlparser.LockReadLocal
 
-		// TODO: Allow for other types of locks (LOW PRIORITY WRITE & LOCAL READ)
-		tables[i] = &plan.TableLock{Table: tableNode, Write: write}
+		// TODO: LOW_PRIORITY WRITE locks are taken as regular WRITE locks
+		tables[i] = &plan.TableLock{Table: tableNode, Write: write, Local: local}
 	}
 
 	return plan.NewLockTables(tables), nil
@@ -2212,8 +2268,13 @@ func convertInsert(ctx *sql.Context, i *sqlparser.Insert) (sql.Node, error) {
 
 	var columns = columnsToStrings(i.Columns)
 
+	table := tableNameToUnresolvedTable(i.Table)
+	if len(i.Partitions) > 0 {
+		table = table.WithPartitions(columnsToStrings(sqlparser.Columns(i.Partitions)))
+	}
+
 	var node sql.Node
-	node, err = plan.NewInsertInto(sql.UnresolvedDatabase(i.Table.Qualifier.String()), tableNameToUnresolvedTable(i.Table), src, isReplace, columns, onDupExprs, ignore), nil
+	node, err = plan.NewInsertInto(sql.UnresolvedDatabase(i.Table.Qualifier.String()), table, src, isReplace, columns, onDupExprs, ignore), nil
 	if err != nil {
 		return nil, err
 	}
@@ -2234,6 +2295,14 @@ func convertDelete(ctx *sql.Context, d *sqlparser.Delete) (sql.Node, error) {
 		return nil, err
 	}
 
+	if len(d.Partitions) > 0 {
+		table, ok := node.(*plan.UnresolvedTable)
+		if !ok {
+			return nil, sql.ErrUnsupportedSyntax.New(sqlparser.String(d))
+		}
+		node = table.WithPartitions(columnsToStrings(sqlparser.Columns(d.Partitions)))
+	}
+
 	if d.Where != nil {
 		node, err = whereToFilter(ctx, d.Where, node)
 		if err != nil {
@@ -2774,6 +2843,10 @@ func convertCreateUser(ctx *sql.Context, n *sqlparser.CreateUser) (*plan.CreateU
 			} else {
 				lockTime = &val
 			}
+		} else {
+			// A nil lock time represents PASSWORD_LOCK_TIME UNBOUNDED
+			unbounded := int64(-1)
+			lockTime = &unbounded
 		}
 		passwordOptions = &plan.PasswordOptions{
 			RequireCurrentOptional: n.PasswordOptions.RequireCurrentOptional,
@@ -2858,6 +2931,17 @@ func convertShowGrants(ctx *sql.Context, n *sqlparser.ShowGrants) (*plan.ShowGra
 }
 
 func convertFlush(ctx *sql.Context, f *sqlparser.Flush) (sql.Node, error) {
+	// FLUSH STATUS and FLUSH LOGS are never written to a binary log, so NO_WRITE_TO_BINLOG and LOCAL make no difference
+	name := strings.ToLower(f.Option.Name)
+	switch {
+	case name == "status":
+		return plan.NewFlushStatus(), nil
+	case name == "logs":
+		return plan.NewFlushLogs(""), nil
+	case strings.HasSuffix(name, " logs"):
+		return plan.NewFlushLogs(strings.TrimSuffix(name, " logs")), nil
+	}
+
 	var writesToBinlog = true
 	switch strings.ToLower(f.Type) {
 	case "no_write_to_binlog", "local":
@@ -2865,7 +2949,7 @@ func convertFlush(ctx *sql.Context, f *sqlparser.Flush) (sql.Node, error) {
 		return nil, fmt.Errorf("%s not supported", f.Type)
 	}
 
-	switch strings.ToLower(f.Option.Name) {
+	switch name {
 	case "privileges":
 		return plan.NewFlushPrivileges(writesToBinlog), nil
 	default:
@@ -2963,6 +3047,10 @@ func tableExprToTable(
 				node = tableNameToUnresolvedTable(e)
 			}
 
+			if len(t.Partitions) > 0 {
+				node = node.WithPartitions(columnsToStrings(sqlparser.Columns(t.Partitions)))
+			}
+
 			if !t.As.IsEmpty() {
 				return plan.NewTableAlias(t.As.String(), node), nil
 			}
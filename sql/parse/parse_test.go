@@ -1546,6 +1546,28 @@ CREATE TABLE t2
 					plan.NewUnresolvedTable("foo", "")),
 			),
 		},
+		{
+			input: "EXPLAIN FORMAT=JSON SELECT * FROM foo",
+			plan: plan.NewDescribeQuery("json", plan.NewProject(
+				[]sql.Expression{expression.NewStar()},
+				plan.NewUnresolvedTable("foo", "")),
+			),
+		},
+		{
+			input: "DESCRIBE FORMAT = json SELECT * FROM `json`",
+			plan: plan.NewDescribeQuery("json", plan.NewProject(
+				[]sql.Expression{expression.NewStar()},
+				plan.NewUnresolvedTable("json", "")),
+			),
+		},
+		{
+			input: "EXPLAIN ANALYZE SELECT * FROM foo",
+			plan: plan.NewExplainAnalyze(
+				plan.NewProject(
+					[]sql.Expression{expression.NewStar()},
+					plan.NewUnresolvedTable("foo", "")),
+			),
+		},
 		{
 			input: `SELECT foo, bar FROM foo;`,
 			plan: plan.NewProject(
@@ -3048,7 +3070,7 @@ CREATE TABLE t2
 				[]sql.Expression{
 					expression.NewStar(),
 				},
-				plan.NewUnresolvedTable("foo", ""),
+				plan.NewUnresolvedTable("foo", "").WithComment("/*+ JOIN_ORDER(a, b) */"),
 			),
 		},
 		{
@@ -3065,7 +3087,7 @@ CREATE TABLE t2
 							expression.NewUnresolvedColumn("c"),
 							expression.NewUnresolvedColumn("d"),
 						),
-					).WithComment("/*+ JOIN_ORDER(a,b) */"),
+					).WithComment("/*+ JOIN_ORDER(a, b) */"),
 				),
 			),
 		},
@@ -3241,7 +3263,7 @@ CREATE TABLE t2
 		{
 			input: `LOCK TABLES foo READ LOCAL`,
 			plan: plan.NewLockTables([]*plan.TableLock{
-				{Table: plan.NewUnresolvedTable("foo", "")},
+				{Table: plan.NewUnresolvedTable("foo", ""), Local: true},
 			}),
 		},
 		{
@@ -5041,8 +5063,309 @@ func TestParseCreateTrigger(t *testing.T) {
 	}
 }
 
+func TestParsePartitions(t *testing.T) {
+	tests := []parseTest{
+		{
+			input: "alter table t drop partition p0, p1",
+			plan:  plan.NewAlterPartition(plan.NewUnresolvedTable("t", ""), plan.AlterPartitionAction_Drop, []string{"p0", "p1"}, nil, 0, nil),
+		},
+		{
+			input: "ALTER TABLE mydb.t TRUNCATE PARTITION ALL",
+			plan:  plan.NewAlterPartition(plan.NewUnresolvedTable("t", "mydb"), plan.AlterPartitionAction_Truncate, nil, nil, 0, nil),
+		},
+		{
+			input: "alter table t coalesce partition 2",
+			plan:  plan.NewAlterPartition(plan.NewUnresolvedTable("t", ""), plan.AlterPartitionAction_Coalesce, nil, nil, 2, nil),
+		},
+		{
+			input: "alter table t remove partitioning",
+			plan:  plan.NewAlterPartition(plan.NewUnresolvedTable("t", ""), plan.AlterPartitionAction_Remove, nil, nil, 0, nil),
+		},
+		{
+			input: "select * from t partition (p0, p1)",
+			plan: plan.NewProject(
+				[]sql.Expression{expression.NewStar()},
+				plan.NewUnresolvedTable("t", "").WithPartitions([]string{"p0", "p1"}),
+			),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			ctx := sql.NewEmptyContext()
+			p, err := Parse(ctx, tt.input)
+			require.NoError(t, err)
+			assertNodesEqualWithDiff(t, tt.plan, p)
+		})
+	}
+}
+
+func TestMayStartWithKeyword(t *testing.T) {
+	require.True(t, mayStartWithKeyword("CREATE TABLE t (a int) PARTITION BY HASH (a)", "create"))
+	require.True(t, mayStartWithKeyword(" \n create table t (a int)", "create"))
+	require.True(t, mayStartWithKeyword("/* comment */ create table t (a int)", "create"))
+	require.True(t, mayStartWithKeyword("describe t", "explain", "desc"))
+	require.False(t, mayStartWithKeyword("select * from t partition (p0)", "create"))
+	require.False(t, mayStartWithKeyword("insert into t values (1)", "create"))
+	require.False(t, mayStartWithKeyword("cr", "create"))
+
+	stmt, clause, _, _ := splitCreateTablePartitioning("select * from t partition (p0)")
+	require.Equal(t, "select * from t partition (p0)", stmt)
+	require.Empty(t, clause)
+
+	query, _, formatLen := quoteExplainFormat("select json from explain")
+	require.Equal(t, "select json from explain", query)
+	require.Zero(t, formatLen)
+	query, _, formatLen = quoteExplainFormat("Explain format=json select 1")
+	require.Equal(t, "Explain format=`json` select 1", query)
+	require.Equal(t, 2, formatLen)
+}
+
+func TestParseEvents(t *testing.T) {
+	tests := []parseTest{
+		{
+			input: "drop event e1",
+			plan:  plan.NewDropEvent(sql.UnresolvedDatabase(""), "e1", false),
+		},
+		{
+			input: "DROP EVENT IF EXISTS mydb.E1",
+			plan:  plan.NewDropEvent(sql.UnresolvedDatabase("mydb"), "e1", true),
+		},
+		{
+			input: "show events",
+			plan:  plan.NewShowEvents(sql.UnresolvedDatabase("")),
+		},
+		{
+			input: "SHOW EVENTS FROM mydb LIKE 'e%'",
+			plan: plan.NewFilter(
+				expression.NewLike(
+					expression.NewUnresolvedColumn("Name"),
+					expression.NewLiteral("e%", types.LongText),
+					nil,
+				),
+				plan.NewShowEvents(sql.UnresolvedDatabase("mydb")),
+			),
+		},
+		{
+			input: "show create event mydb.e1",
+			plan:  plan.NewShowCreateEvent(sql.UnresolvedDatabase("mydb"), "e1"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			ctx := sql.NewEmptyContext()
+			p, err := Parse(ctx, tt.input)
+			require.NoError(t, err)
+			assertNodesEqualWithDiff(t, tt.plan, p)
+		})
+	}
+}
+
+func TestParseProfiles(t *testing.T) {
+	tests := []parseTest{
+		{
+			input: "SHOW PROFILES",
+			plan:  plan.NewShowProfiles(),
+		},
+		{
+			input: "show profile",
+			plan:  plan.NewShowProfile(),
+		},
+		{
+			input: "SHOW PROFILE CPU, BLOCK IO FOR QUERY 3",
+			plan:  &plan.ShowProfile{Types: []string{"CPU", "BLOCK IO"}, QueryId: 3, Limit: -1},
+		},
+		{
+			input: "SHOW PROFILE ALL LIMIT 2 OFFSET 1",
+			plan:  &plan.ShowProfile{Types: []string{"ALL"}, Limit: 2, Offset: 1},
+		},
+		{
+			input: "SHOW PROFILE FOR QUERY 2 LIMIT 1, 4;",
+			plan:  &plan.ShowProfile{QueryId: 2, Limit: 4, Offset: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			ctx := sql.NewEmptyContext()
+			p, err := Parse(ctx, tt.input)
+			require.NoError(t, err)
+			assertNodesEqualWithDiff(t, tt.plan, p)
+		})
+	}
+}
+
+func TestParseFlush(t *testing.T) {
+	tests := []parseTest{
+		{
+			input: "FLUSH TABLES",
+			plan:  plan.NewFlushTables(nil, false),
+		},
+		{
+			input: "FLUSH TABLES WITH READ LOCK",
+			plan:  plan.NewFlushTables(nil, true),
+		},
+		{
+			input: "flush /*!40101 LOCAL */ tables",
+			plan:  plan.NewFlushTables(nil, false),
+		},
+		{
+			input: "FLUSH NO_WRITE_TO_BINLOG TABLE t1, mydb.`t2`;",
+			plan: plan.NewFlushTables([]sql.Node{
+				plan.NewUnresolvedTable("t1", ""),
+				plan.NewUnresolvedTable("t2", "mydb"),
+			}, false),
+		},
+		{
+			input: "FLUSH TABLES t1 WITH READ LOCK",
+			plan:  plan.NewFlushTables([]sql.Node{plan.NewUnresolvedTable("t1", "")}, true),
+		},
+		{
+			input: "FLUSH STATUS",
+			plan:  plan.NewFlushStatus(),
+		},
+		{
+			input: "FLUSH LOCAL LOGS",
+			plan:  plan.NewFlushLogs(""),
+		},
+		{
+			input: "FLUSH SLOW LOGS",
+			plan:  plan.NewFlushLogs("slow"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			ctx := sql.NewEmptyContext()
+			p, err := Parse(ctx, tt.input)
+			require.NoError(t, err)
+			assertNodesEqualWithDiff(t, tt.plan, p)
+		})
+	}
+}
+
 // assertNodesEqualWithDiff asserts the two nodes given to be equal and prints any diff according to their DebugString
 // methods.
+func TestParseUsers(t *testing.T) {
+	int64Ptr := func(val int64) *int64 {
+		return &val
+	}
+	locked := true
+	tests := []parseTest{
+		{
+			input: "ALTER USER jeff IDENTIFIED BY 'pass'",
+			plan: &plan.AlterUser{
+				Users: []plan.AlteredUser{{
+					AuthenticatedUser: plan.AuthenticatedUser{
+						UserName: plan.UserName{Name: "jeff", AnyHost: true},
+						Auth1:    plan.NewDefaultAuthentication("pass"),
+					},
+				}},
+				MySQLDb: sql.UnresolvedDatabase("mysql"),
+			},
+		},
+		{
+			input: "alter user user() identified by 'pass'",
+			plan: &plan.AlterUser{
+				Users: []plan.AlteredUser{{
+					AuthenticatedUser: plan.AuthenticatedUser{Auth1: plan.NewDefaultAuthentication("pass")},
+					CurrentUser:       true,
+				}},
+				MySQLDb: sql.UnresolvedDatabase("mysql"),
+			},
+		},
+		{
+			input: "ALTER USER IF EXISTS 'jeff'@'localhost', bob WITH MAX_QUERIES_PER_HOUR 10 MAX_USER_CONNECTIONS 2 " +
+				"PASSWORD EXPIRE INTERVAL 30 DAY FAILED_LOGIN_ATTEMPTS 3 PASSWORD_LOCK_TIME UNBOUNDED ACCOUNT LOCK",
+			plan: &plan.AlterUser{
+				IfExists: true,
+				Users: []plan.AlteredUser{
+					{AuthenticatedUser: plan.AuthenticatedUser{UserName: plan.UserName{Name: "jeff", Host: "localhost"}}},
+					{AuthenticatedUser: plan.AuthenticatedUser{UserName: plan.UserName{Name: "bob", AnyHost: true}}},
+				},
+				AccountLimits: &plan.AccountLimits{
+					MaxQueriesPerHour:  int64Ptr(10),
+					MaxUserConnections: int64Ptr(2),
+				},
+				PasswordOptions: &plan.PasswordOptions{
+					ExpirationTime: int64Ptr(30),
+					FailedAttempts: int64Ptr(3),
+					LockTime:       int64Ptr(-1),
+				},
+				Locked:  &locked,
+				MySQLDb: sql.UnresolvedDatabase("mysql"),
+			},
+		},
+		{
+			input: "ALTER USER jeff PASSWORD EXPIRE",
+			plan: &plan.AlterUser{
+				Users: []plan.AlteredUser{
+					{AuthenticatedUser: plan.AuthenticatedUser{UserName: plan.UserName{Name: "jeff", AnyHost: true}}},
+				},
+				PasswordOptions: &plan.PasswordOptions{Expired: true},
+				MySQLDb:         sql.UnresolvedDatabase("mysql"),
+			},
+		},
+		{
+			input: "SET ROLE DEFAULT",
+			plan:  plan.NewSetRole(plan.SetRoleType_Default, nil),
+		},
+		{
+			input: "set role none",
+			plan:  plan.NewSetRole(plan.SetRoleType_None, nil),
+		},
+		{
+			input: "SET ROLE ALL EXCEPT r1, 'r2'@'localhost'",
+			plan: plan.NewSetRole(plan.SetRoleType_AllExcept, []plan.UserName{
+				{Name: "r1", AnyHost: true},
+				{Name: "r2", Host: "localhost"},
+			}),
+		},
+		{
+			input: "SET ROLE r1, r2",
+			plan: plan.NewSetRole(plan.SetRoleType_List, []plan.UserName{
+				{Name: "r1", AnyHost: true},
+				{Name: "r2", AnyHost: true},
+			}),
+		},
+		{
+			input: "SET DEFAULT ROLE ALL TO jeff@localhost, bob",
+			plan: plan.NewSetDefaultRole(plan.SetRoleType_All, nil, []plan.UserName{
+				{Name: "jeff", Host: "localhost"},
+				{Name: "bob", AnyHost: true},
+			}),
+		},
+		{
+			input: "SET DEFAULT ROLE r1, r2 TO jeff",
+			plan: plan.NewSetDefaultRole(plan.SetRoleType_List, []plan.UserName{
+				{Name: "r1", AnyHost: true},
+				{Name: "r2", AnyHost: true},
+			}, []plan.UserName{{Name: "jeff", AnyHost: true}}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			ctx := sql.NewEmptyContext()
+			p, err := Parse(ctx, tt.input)
+			require.NoError(t, err)
+			assertNodesEqualWithDiff(t, tt.plan, p)
+		})
+	}
+
+	t.Run("CREATE USER with PASSWORD EXPIRE", func(t *testing.T) {
+		ctx := sql.NewEmptyContext()
+		p, err := Parse(ctx, "CREATE USER jeff IDENTIFIED BY 'pass' PASSWORD EXPIRE ACCOUNT LOCK")
+		require.NoError(t, err)
+		createUser, ok := p.(*plan.CreateUser)
+		require.True(t, ok)
+		require.NotNil(t, createUser.PasswordOptions)
+		require.True(t, createUser.PasswordOptions.Expired)
+		require.True(t, createUser.Locked)
+	})
+}
+
 func assertNodesEqualWithDiff(t *testing.T, expected, actual sql.Node) bool {
 	if !assert.Equal(t, expected, actual) {
 		expectedStr := sql.DebugString(expected)
@@ -5103,6 +5426,19 @@ var fixturesErrors = map[string]*errors.Kind{
 	`DROP TABLE IF EXISTS curdb.foo, otherdb.bar`:               sql.ErrUnsupportedFeature,
 	`DROP TABLE curdb.t1, t2`:                                   sql.ErrUnsupportedFeature,
 	`CREATE TABLE test (i int fulltext key)`:                    sql.ErrUnsupportedFeature,
+	`CREATE EVENT e1 ON SCHEDULE AT '2037-01-01 00:00:00'`:      sql.ErrSyntaxError,
+	`ALTER EVENT e1`:                                            sql.ErrSyntaxError,
+	`DROP EVENT e1 e2`:                                          sql.ErrSyntaxError,
+	`ALTER USER jeff IDENTIFIED BY`:                             sql.ErrSyntaxError,
+	`ALTER USER jeff WITH MAX_QUERIES_PER_HOUR`:                 sql.ErrSyntaxError,
+	`SET ROLE`:                  sql.ErrSyntaxError,
+	`SET DEFAULT ROLE r1`:       sql.ErrSyntaxError,
+	`SHOW PROFILES FOR QUERY 1`: sql.ErrSyntaxError,
+	`SHOW PROFILE CPU,`:         sql.ErrSyntaxError,
+	`SHOW PROFILE FOR QUERY`:    sql.ErrSyntaxError,
+	`FLUSH TABLES t1 t2`:        sql.ErrSyntaxError,
+	`FLUSH TABLES WITH LOCK`:    sql.ErrSyntaxError,
+	`SHOW PROFILES; SELECT 2`:   sql.ErrSyntaxError,
 }
 
 func TestParseOne(t *testing.T) {
@@ -5144,6 +5480,26 @@ func TestParseOne(t *testing.T) {
 			"SELECT 1; SELECT 2; -- empty statement with comment\n",
 			[]string{"SELECT 1", "SELECT 2", "-- empty statement with comment"},
 		},
+		{
+			"DROP EVENT IF EXISTS e; SELECT 4",
+			[]string{"DROP EVENT IF EXISTS e", "SELECT 4"},
+		},
+		{
+			"CREATE EVENT e ON SCHEDULE AT '2038-01-01 00:00:00' DO SELECT 1; SELECT 3",
+			[]string{"CREATE EVENT e ON SCHEDULE AT '2038-01-01 00:00:00' DO SELECT 1", "SELECT 3"},
+		},
+		{
+			"CREATE EVENT e ON SCHEDULE EVERY 1 DAY DO BEGIN INSERT INTO t VALUES (1); IF 1 THEN SELECT CASE WHEN 1 THEN 2 END; END IF; END; SELECT 3",
+			[]string{"CREATE EVENT e ON SCHEDULE EVERY 1 DAY DO BEGIN INSERT INTO t VALUES (1); IF 1 THEN SELECT CASE WHEN 1 THEN 2 END; END IF; END", "SELECT 3"},
+		},
+		{
+			"SHOW PROFILES; SELECT 2",
+			[]string{"SHOW PROFILES", "SELECT 2"},
+		},
+		{
+			"FLUSH TABLES WITH READ LOCK; UNLOCK TABLES; SELECT 5",
+			[]string{"FLUSH TABLES WITH READ LOCK", "UNLOCK TABLES", "SELECT 5"},
+		},
 	}
 	for _, tc := range cases {
 		t.Run(tc.input, func(t *testing.T) {
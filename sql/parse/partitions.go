@@ -0,0 +1,475 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+// splitCreateTablePartitioning removes the PARTITION BY clause from a CREATE TABLE statement, since the vitess grammar
+// doesn't support most of it. Returns the statement without the clause, along with the clause and the offset and
+// length of the text that was removed. The clause is empty if the statement is not a CREATE TABLE statement with a
+// PARTITION BY clause. Only the first statement of the query is considered.
+func splitCreateTablePartitioning(query string) (string, string, int, int) {
+	if !mayStartWithKeyword(query, "create") {
+		return query, "", 0, 0
+	}
+	s, err := newStatementScanner(query)
+	if err != nil {
+		return query, "", 0, 0
+	}
+	if !s.acceptKeywords("create") {
+		return query, "", 0, 0
+	}
+	s.acceptKeywords("temporary")
+	if !s.acceptKeywords("table") {
+		return query, "", 0, 0
+	}
+
+	depth := 0
+	for {
+		t := s.peek()
+		if t.kind == tokenEOF || (depth == 0 && t.kind == tokenPunct && t.val == ";") {
+			return query, "", 0, 0
+		}
+		if depth == 0 && s.peekKeywords("partition", "by") {
+			break
+		}
+		if t.kind == tokenPunct {
+			switch t.val {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			}
+		}
+		s.pos++
+	}
+
+	start := s.peek().start
+	limit := len(query)
+	for _, comment := range s.versionedComments {
+		if comment[0] <= start && start < comment[1] {
+			start, limit = comment[0], comment[1]
+		}
+	}
+
+	// The clause ends with the statement, or with the query expression of CREATE TABLE ... SELECT
+	clauseStart := s.peek().start
+	end := clauseStart
+	depth = 0
+	for {
+		t := s.peek()
+		if t.kind == tokenEOF || t.start >= limit {
+			break
+		}
+		if depth == 0 {
+			if t.kind == tokenPunct && t.val == ";" {
+				break
+			}
+			if t.isKeyword("as") || t.isKeyword("select") || t.isKeyword("ignore") || t.isKeyword("replace") {
+				break
+			}
+		}
+		if t.kind == tokenPunct {
+			switch t.val {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			}
+		}
+		end = t.end
+		s.pos++
+	}
+
+	clause := query[clauseStart:end]
+	if limit < len(query) {
+		// the clause is in a MySQL-specific comment, such as the one written by SHOW CREATE TABLE, which is removed
+		// along with it
+		end = limit
+		clause = strings.TrimSpace(strings.TrimSuffix(query[clauseStart:limit], "*/"))
+	}
+	return query[:start] + query[end:], clause, start, end - start
+}
+
+// withPartitionOptions parses the PARTITION BY clause given and adds it to the CREATE TABLE statement given.
+func withPartitionOptions(ctx *sql.Context, node sql.Node, clause string) (sql.Node, error) {
+	ct, ok := node.(*plan.CreateTable)
+	if !ok || ct.Like() != nil {
+		return nil, sql.ErrUnsupportedSyntax.New(clause)
+	}
+	s, err := newStatementScanner(clause)
+	if err != nil {
+		return nil, sql.ErrSyntaxError.New(err.Error())
+	}
+	options, err := parsePartitionOptions(ctx, s)
+	if err != nil {
+		return nil, err
+	}
+	if !s.atEnd() {
+		return nil, s.syntaxError()
+	}
+	return ct.WithPartitioning(options), nil
+}
+
+// parseAlterPartitionStatement parses the ALTER TABLE statements that change the partitions of a table: ADD, DROP,
+// TRUNCATE, COALESCE and REORGANIZE PARTITION, REMOVE PARTITIONING and PARTITION BY.
+func parseAlterPartitionStatement(ctx *sql.Context, s *statementScanner) (sql.Node, bool, error) {
+	if !s.acceptKeywords("alter", "table") {
+		return nil, false, nil
+	}
+	dbName, tableName, err := s.qualifiedIdentifier()
+	if err != nil {
+		return nil, false, nil
+	}
+	table := plan.NewUnresolvedTable(tableName, dbName)
+
+	var node sql.Node
+	switch {
+	case s.acceptKeywords("add", "partition"):
+		if s.acceptKeywords("partitions") {
+			count, err := s.integer()
+			if err != nil {
+				return nil, true, err
+			}
+			node = plan.NewAlterPartition(table, plan.AlterPartitionAction_Add, nil, nil, count, nil)
+			break
+		}
+		defs, err := parsePartitionDefinitions(ctx, s)
+		if err != nil {
+			return nil, true, err
+		}
+		node = plan.NewAlterPartition(table, plan.AlterPartitionAction_Add, nil, defs, 0, nil)
+	case s.acceptKeywords("drop", "partition"):
+		names, err := parsePartitionNames(s)
+		if err != nil {
+			return nil, true, err
+		}
+		node = plan.NewAlterPartition(table, plan.AlterPartitionAction_Drop, names, nil, 0, nil)
+	case s.acceptKeywords("truncate", "partition"):
+		var names []string
+		if !s.acceptKeywords("all") {
+			names, err = parsePartitionNames(s)
+			if err != nil {
+				return nil, true, err
+			}
+		}
+		node = plan.NewAlterPartition(table, plan.AlterPartitionAction_Truncate, names, nil, 0, nil)
+	case s.acceptKeywords("coalesce", "partition"):
+		count, err := s.integer()
+		if err != nil {
+			return nil, true, err
+		}
+		node = plan.NewAlterPartition(table, plan.AlterPartitionAction_Coalesce, nil, nil, count, nil)
+	case s.acceptKeywords("reorganize", "partition"):
+		names, err := parsePartitionNames(s)
+		if err != nil {
+			return nil, true, err
+		}
+		if err = s.expectKeywords("into"); err != nil {
+			return nil, true, err
+		}
+		defs, err := parsePartitionDefinitions(ctx, s)
+		if err != nil {
+			return nil, true, err
+		}
+		node = plan.NewAlterPartition(table, plan.AlterPartitionAction_Reorganize, names, defs, 0, nil)
+	case s.acceptKeywords("remove", "partitioning"):
+		node = plan.NewAlterPartition(table, plan.AlterPartitionAction_Remove, nil, nil, 0, nil)
+	case s.peekKeywords("partition", "by"):
+		options, err := parsePartitionOptions(ctx, s)
+		if err != nil {
+			return nil, true, err
+		}
+		node = plan.NewAlterPartition(table, plan.AlterPartitionAction_Repartition, nil, nil, 0, options)
+	default:
+		return nil, false, nil
+	}
+
+	if !s.atEnd() {
+		return nil, true, s.syntaxError()
+	}
+	return node, true, nil
+}
+
+// convertAlterPartition converts an ALTER TABLE ... REORGANIZE PARTITION statement, which the vitess grammar only
+// partially supports, by parsing the query again.
+func convertAlterPartition(ctx *sql.Context, query string) (sql.Node, error) {
+	s, err := newStatementScanner(query)
+	if err != nil {
+		return nil, sql.ErrSyntaxError.New(err.Error())
+	}
+	node, ok, err := parseAlterPartitionStatement(ctx, s)
+	if !ok {
+		return nil, sql.ErrUnsupportedSyntax.New(query)
+	}
+	return node, err
+}
+
+// parsePartitionNames parses a comma-separated list of partition names.
+func parsePartitionNames(s *statementScanner) ([]string, error) {
+	var names []string
+	for {
+		name, err := s.identifier()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !s.acceptPunct(",") {
+			return names, nil
+		}
+	}
+}
+
+// parsePartitionOptions parses a PARTITION BY clause.
+func parsePartitionOptions(ctx *sql.Context, s *statementScanner) (*plan.PartitionOptions, error) {
+	if err := s.expectKeywords("partition", "by"); err != nil {
+		return nil, err
+	}
+
+	options := &plan.PartitionOptions{}
+	var err error
+	linear := s.acceptKeywords("linear")
+	switch {
+	case s.acceptKeywords("hash"):
+		options.Method = sql.PartitionMethod_Hash
+		if linear {
+			options.Method = sql.PartitionMethod_LinearHash
+		}
+		err = parsePartitionExpression(ctx, s, options)
+	case s.acceptKeywords("key"):
+		options.Method = sql.PartitionMethod_Key
+		if linear {
+			options.Method = sql.PartitionMethod_LinearKey
+		}
+		if s.acceptKeywords("algorithm") {
+			if err = s.expectPunct("="); err != nil {
+				return nil, err
+			}
+			if _, err = s.integer(); err != nil {
+				return nil, err
+			}
+		}
+		options.Columns, err = s.identifierList()
+	case !linear && s.acceptKeywords("range"):
+		options.Method = sql.PartitionMethod_Range
+		if s.acceptKeywords("columns") {
+			options.Method = sql.PartitionMethod_RangeColumns
+			options.Columns, err = parsePartitionColumns(s)
+		} else {
+			err = parsePartitionExpression(ctx, s, options)
+		}
+	case !linear && s.acceptKeywords("list"):
+		options.Method = sql.PartitionMethod_List
+		if s.acceptKeywords("columns") {
+			options.Method = sql.PartitionMethod_ListColumns
+			options.Columns, err = parsePartitionColumns(s)
+		} else {
+			err = parsePartitionExpression(ctx, s, options)
+		}
+	default:
+		return nil, s.syntaxError()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if s.acceptKeywords("partitions") {
+		options.NumPartitions, err = s.integer()
+		if err != nil {
+			return nil, err
+		}
+		if options.NumPartitions == 0 {
+			return nil, sql.ErrSyntaxError.New("number of partitions must be at least 1")
+		}
+	}
+	if s.peekKeywords("subpartition") {
+		return nil, sql.ErrUnsupportedFeature.New("subpartitioning")
+	}
+	if t := s.peek(); t.kind == tokenPunct && t.val == "(" {
+		options.Definitions, err = parsePartitionDefinitions(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return options, nil
+}
+
+// parsePartitionExpression parses the parenthesized partitioning expression of RANGE, LIST and HASH partitioning.
+func parsePartitionExpression(ctx *sql.Context, s *statementScanner, options *plan.PartitionOptions) error {
+	items, err := s.parenthesizedList()
+	if err != nil {
+		return err
+	}
+	if len(items) != 1 {
+		return sql.ErrSyntaxError.New("partitioning expression must be a single expression")
+	}
+	options.ExpressionString = items[0]
+	options.Expression, err = convertExpressionString(ctx, items[0])
+	return err
+}
+
+// parsePartitionColumns parses the column list of RANGE COLUMNS and LIST COLUMNS partitioning, which can't be empty.
+func parsePartitionColumns(s *statementScanner) ([]string, error) {
+	columns, err := s.identifierList()
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, s.syntaxError()
+	}
+	return columns, nil
+}
+
+// parsePartitionDefinitions parses a parenthesized list of partition definitions.
+func parsePartitionDefinitions(ctx *sql.Context, s *statementScanner) ([]*plan.PartitionDefinitionSpec, error) {
+	if err := s.expectPunct("("); err != nil {
+		return nil, err
+	}
+	var defs []*plan.PartitionDefinitionSpec
+	for {
+		def, err := parsePartitionDefinition(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		defs = append(defs, def)
+		if !s.acceptPunct(",") {
+			break
+		}
+	}
+	if err := s.expectPunct(")"); err != nil {
+		return nil, err
+	}
+	return defs, nil
+}
+
+// parsePartitionDefinition parses a single partition definition. Table options that have no meaning for partitions
+// of an in-memory table, such as ENGINE and DATA DIRECTORY, are accepted and ignored.
+func parsePartitionDefinition(ctx *sql.Context, s *statementScanner) (*plan.PartitionDefinitionSpec, error) {
+	if err := s.expectKeywords("partition"); err != nil {
+		return nil, err
+	}
+	name, err := s.identifier()
+	if err != nil {
+		return nil, err
+	}
+	def := &plan.PartitionDefinitionSpec{Name: name}
+
+	if s.acceptKeywords("values") {
+		switch {
+		case s.acceptKeywords("less", "than"):
+			if s.acceptKeywords("maxvalue") {
+				def.LessThan = []sql.Expression{nil}
+				break
+			}
+			items, err := s.parenthesizedList()
+			if err != nil {
+				return nil, err
+			}
+			def.LessThan, err = convertPartitionValues(ctx, items, true)
+			if err != nil {
+				return nil, err
+			}
+		case s.acceptKeywords("in"):
+			items, err := s.parenthesizedList()
+			if err != nil {
+				return nil, err
+			}
+			for _, item := range items {
+				values := []string{item}
+				if strings.HasPrefix(item, "(") {
+					// a tuple of values for LIST COLUMNS partitioning
+					tuple, err := newStatementScanner(item)
+					if err != nil {
+						return nil, sql.ErrSyntaxError.New(err.Error())
+					}
+					values, err = tuple.parenthesizedList()
+					if err != nil {
+						return nil, err
+					}
+					if !tuple.atEnd() {
+						values = []string{item}
+					}
+				}
+				exprs, err := convertPartitionValues(ctx, values, false)
+				if err != nil {
+					return nil, err
+				}
+				def.In = append(def.In, exprs)
+			}
+		default:
+			return nil, s.syntaxError()
+		}
+	}
+
+	for {
+		switch {
+		case s.acceptKeywords("storage", "engine"), s.acceptKeywords("engine"):
+			s.acceptPunct("=")
+			if _, err = s.identifier(); err != nil {
+				return nil, err
+			}
+		case s.acceptKeywords("comment"):
+			s.acceptPunct("=")
+			if def.Comment, err = s.stringLiteral(); err != nil {
+				return nil, err
+			}
+		case s.acceptKeywords("data", "directory"), s.acceptKeywords("index", "directory"):
+			s.acceptPunct("=")
+			if _, err = s.stringLiteral(); err != nil {
+				return nil, err
+			}
+		case s.acceptKeywords("max_rows"), s.acceptKeywords("min_rows"):
+			s.acceptPunct("=")
+			if _, err = s.integer(); err != nil {
+				return nil, err
+			}
+		case s.acceptKeywords("tablespace"):
+			s.acceptPunct("=")
+			if _, err = s.identifier(); err != nil {
+				return nil, err
+			}
+		default:
+			if t := s.peek(); t.kind == tokenPunct && t.val == "(" {
+				return nil, sql.ErrUnsupportedFeature.New("subpartitioning")
+			}
+			return def, nil
+		}
+	}
+}
+
+// convertPartitionValues parses the values of a partition definition. MAXVALUE is returned as a nil expression when
+// |allowMaxValue| is true.
+func convertPartitionValues(ctx *sql.Context, values []string, allowMaxValue bool) ([]sql.Expression, error) {
+	exprs := make([]sql.Expression, len(values))
+	for i, value := range values {
+		if strings.EqualFold(value, "maxvalue") {
+			if !allowMaxValue {
+				return nil, sql.ErrSyntaxError.New("MAXVALUE can only be used in VALUES LESS THAN")
+			}
+			continue
+		}
+		expr, err := convertExpressionString(ctx, value)
+		if err != nil {
+			return nil, err
+		}
+		exprs[i] = expr
+	}
+	return exprs, nil
+}
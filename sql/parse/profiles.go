@@ -0,0 +1,112 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+// profileTypes are the types of information accepted by SHOW PROFILE, each given as its sequence of keywords.
+var profileTypes = [][]string{
+	{"all"},
+	{"block", "io"},
+	{"context", "switches"},
+	{"cpu"},
+	{"ipc"},
+	{"memory"},
+	{"page", "faults"},
+	{"source"},
+	{"swaps"},
+}
+
+// parseProfileStatement parses the SHOW PROFILES and SHOW PROFILE statements, which the vitess grammar does not
+// support.
+func parseProfileStatement(ctx *sql.Context, s *statementScanner) (sql.Node, bool, error) {
+	switch {
+	case s.acceptKeywords("show", "profiles"):
+		if !s.atEnd() {
+			return nil, true, s.syntaxError()
+		}
+		return plan.NewShowProfiles(), true, nil
+	case s.acceptKeywords("show", "profile"):
+		node, err := parseShowProfile(s)
+		return node, true, err
+	default:
+		return nil, false, nil
+	}
+}
+
+func parseShowProfile(s *statementScanner) (sql.Node, error) {
+	showProfile := plan.NewShowProfile()
+	for len(showProfile.Types) == 0 || s.acceptPunct(",") {
+		profileType, ok := acceptProfileType(s)
+		if !ok {
+			if len(showProfile.Types) == 0 {
+				break
+			}
+			return nil, s.syntaxError()
+		}
+		showProfile.Types = append(showProfile.Types, profileType)
+	}
+
+	if s.acceptKeywords("for", "query") {
+		queryId, err := s.integer()
+		if err != nil {
+			return nil, err
+		}
+		showProfile.QueryId = int64(queryId)
+	}
+
+	if s.acceptKeywords("limit") {
+		limit, err := s.integer()
+		if err != nil {
+			return nil, err
+		}
+		showProfile.Limit = int64(limit)
+		if s.acceptPunct(",") {
+			// LIMIT offset, row_count
+			showProfile.Offset = showProfile.Limit
+			limit, err = s.integer()
+			if err != nil {
+				return nil, err
+			}
+			showProfile.Limit = int64(limit)
+		} else if s.acceptKeywords("offset") {
+			offset, err := s.integer()
+			if err != nil {
+				return nil, err
+			}
+			showProfile.Offset = int64(offset)
+		}
+	}
+
+	if !s.atEnd() {
+		return nil, s.syntaxError()
+	}
+	return showProfile, nil
+}
+
+// acceptProfileType consumes a type of information accepted by SHOW PROFILE, returning it in upper case.
+func acceptProfileType(s *statementScanner) (string, bool) {
+	for _, keywords := range profileTypes {
+		if s.acceptKeywords(keywords...) {
+			return strings.ToUpper(strings.Join(keywords, " ")), true
+		}
+	}
+	return "", false
+}
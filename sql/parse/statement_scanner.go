@@ -0,0 +1,570 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// parseSupplementalStatement parses the first statement of the query given if it's one that the vitess grammar does not
+// yet support, along with the offset at which the statements after it start, which is the length of the query if
+// there are none. Returns false if the statement is not one of these, in which case the original syntax error should
+// be reported.
+func parseSupplementalStatement(ctx *sql.Context, query string) (sql.Node, int, bool, error) {
+	s, err := newStatementScanner(query)
+	if err != nil {
+		return nil, 0, false, nil
+	}
+	end := len(query)
+	if semicolon, ok := s.statementEnd(); ok {
+		end = semicolon + 1
+		if s, err = newStatementScanner(query[:semicolon]); err != nil {
+			return nil, 0, false, nil
+		}
+	}
+	// Each parser is tried in order, and returns false if the statement is not one that it handles
+	parsers := []func(ctx *sql.Context, s *statementScanner) (sql.Node, bool, error){
+		parseEventStatement,
+		parseAlterPartitionStatement,
+		parseUserStatement,
+		parseProfileStatement,
+		parseFlushTablesStatement,
+	}
+	for _, parser := range parsers {
+		s.pos = 0
+		node, ok, err := parser(ctx, s)
+		if ok {
+			return node, end, true, err
+		}
+	}
+	return nil, 0, false, nil
+}
+
+// statementEnd returns the offset of the semicolon that ends the first statement of the query, if another statement
+// follows it. Semicolons that end the statements of BEGIN ... END blocks, such as those of event bodies, don't end
+// the statement they're part of, while the END of a CASE expression or statement doesn't end a block.
+func (s *statementScanner) statementEnd() (int, bool) {
+	var blocks []string
+	for i := 0; i < len(s.tokens); i++ {
+		t := s.tokens[i]
+		switch {
+		case t.isKeyword("begin"), t.isKeyword("case"):
+			blocks = append(blocks, strings.ToLower(t.val))
+		case t.isKeyword("end") && len(blocks) > 0:
+			next := s.tokens[i+1]
+			if next.isKeyword("if") || next.isKeyword("loop") || next.isKeyword("while") || next.isKeyword("repeat") {
+				i++
+				continue
+			}
+			if next.isKeyword("case") {
+				i++
+			}
+			blocks = blocks[:len(blocks)-1]
+		case t.kind == tokenPunct && t.val == ";" && len(blocks) == 0:
+			if s.tokens[i+1].kind == tokenEOF {
+				return 0, false
+			}
+			return t.start, true
+		}
+	}
+	return 0, false
+}
+
+type tokenKind byte
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenQuotedIdent
+	tokenString
+	tokenNumber
+	tokenPunct
+)
+
+// scannedToken is a single token of a statement, along with its byte offsets in the original query.
+type scannedToken struct {
+	kind  tokenKind
+	val   string
+	start int
+	end   int
+}
+
+// statementScanner is a minimal tokenizer and recursive-descent helper for the statements handled by
+// parseSupplementalStatement. Anything more complex than keywords and identifiers, such as expressions and statement
+// bodies, is sliced out of the original query and handed back to the vitess parser.
+type statementScanner struct {
+	query  string
+	tokens []scannedToken
+	pos    int
+	// versionedComments are the start and end offsets of each MySQL-specific comment, such as /*!50100 ... */, whose
+	// contents are scanned as part of the statement.
+	versionedComments [][2]int
+}
+
+// mayStartWithKeyword returns whether the query given may start with one of the keywords given, which is checked
+// before scanning queries that only some statements need scanned. Queries that start with a comment may.
+func mayStartWithKeyword(query string, keywords ...string) bool {
+	query = strings.TrimLeft(query, " \t\n\r")
+	if strings.HasPrefix(query, "/*") || strings.HasPrefix(query, "--") || strings.HasPrefix(query, "#") {
+		return true
+	}
+	for _, keyword := range keywords {
+		if len(query) >= len(keyword) && strings.EqualFold(query[:len(keyword)], keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+func newStatementScanner(query string) (*statementScanner, error) {
+	s := &statementScanner{query: query}
+	i := 0
+	commentStart := -1
+	for i < len(query) {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '/' && strings.HasPrefix(query[i:], "/*!"):
+			if commentStart >= 0 {
+				return nil, fmt.Errorf("nested comment")
+			}
+			commentStart = i
+			i += 3
+			for i < len(query) && isScannerDigit(query[i]) {
+				i++
+			}
+		case c == '*' && commentStart >= 0 && strings.HasPrefix(query[i:], "*/"):
+			i += 2
+			s.versionedComments = append(s.versionedComments, [2]int{commentStart, i})
+			commentStart = -1
+		case c == '#' || (c == '-' && strings.HasPrefix(query[i:], "-- ")):
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case c == '/' && strings.HasPrefix(query[i:], "/*"):
+			end := strings.Index(query[i+2:], "*/")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated comment")
+			}
+			i += end + 4
+		case c == '`':
+			start := i
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(query) {
+					return nil, fmt.Errorf("unterminated identifier")
+				}
+				if query[i] == '`' {
+					if i+1 < len(query) && query[i+1] == '`' {
+						sb.WriteByte('`')
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteByte(query[i])
+				i++
+			}
+			s.tokens = append(s.tokens, scannedToken{kind: tokenQuotedIdent, val: sb.String(), start: start, end: i})
+		case c == '\'' || c == '"':
+			start := i
+			var sb strings.Builder
+			i++
+			for {
+				if i >= len(query) {
+					return nil, fmt.Errorf("unterminated string")
+				}
+				if query[i] == '\\' && i+1 < len(query) {
+					sb.WriteByte(query[i+1])
+					i += 2
+					continue
+				}
+				if query[i] == c {
+					if i+1 < len(query) && query[i+1] == c {
+						sb.WriteByte(c)
+						i += 2
+						continue
+					}
+					i++
+					break
+				}
+				sb.WriteByte(query[i])
+				i++
+			}
+			s.tokens = append(s.tokens, scannedToken{kind: tokenString, val: sb.String(), start: start, end: i})
+		case isScannerDigit(c):
+			start := i
+			for i < len(query) && (isScannerDigit(query[i]) || query[i] == '.') {
+				i++
+			}
+			if i < len(query) && isScannerWordChar(query[i]) {
+				// identifiers may begin with digits
+				for i < len(query) && isScannerWordChar(query[i]) {
+					i++
+				}
+				s.tokens = append(s.tokens, scannedToken{kind: tokenWord, val: query[start:i], start: start, end: i})
+			} else {
+				s.tokens = append(s.tokens, scannedToken{kind: tokenNumber, val: query[start:i], start: start, end: i})
+			}
+		case isScannerWordChar(c):
+			start := i
+			for i < len(query) && isScannerWordChar(query[i]) {
+				i++
+			}
+			s.tokens = append(s.tokens, scannedToken{kind: tokenWord, val: query[start:i], start: start, end: i})
+		default:
+			s.tokens = append(s.tokens, scannedToken{kind: tokenPunct, val: query[i : i+1], start: i, end: i + 1})
+			i++
+		}
+	}
+	if commentStart >= 0 {
+		return nil, fmt.Errorf("unterminated comment")
+	}
+	s.tokens = append(s.tokens, scannedToken{kind: tokenEOF, start: len(query), end: len(query)})
+	return s, nil
+}
+
+func isScannerDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isScannerWordChar(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || isScannerDigit(c) || c == '_' || c == '$' || c >= 0x80
+}
+
+// peek returns the token at the current position without consuming it.
+func (s *statementScanner) peek() scannedToken {
+	return s.tokens[s.pos]
+}
+
+// next consumes and returns the token at the current position.
+func (s *statementScanner) next() scannedToken {
+	t := s.tokens[s.pos]
+	if t.kind != tokenEOF {
+		s.pos++
+	}
+	return t
+}
+
+// atEnd returns whether all tokens have been consumed, ignoring a trailing semicolon.
+func (s *statementScanner) atEnd() bool {
+	t := s.peek()
+	if t.kind == tokenPunct && t.val == ";" {
+		return s.tokens[s.pos+1].kind == tokenEOF
+	}
+	return t.kind == tokenEOF
+}
+
+// isKeyword returns whether the token is the unquoted keyword given.
+func (t scannedToken) isKeyword(keyword string) bool {
+	return t.kind == tokenWord && strings.EqualFold(t.val, keyword)
+}
+
+// peekKeywords returns whether the upcoming tokens are the sequence of keywords given.
+func (s *statementScanner) peekKeywords(keywords ...string) bool {
+	for i, keyword := range keywords {
+		if s.pos+i >= len(s.tokens) || !s.tokens[s.pos+i].isKeyword(keyword) {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptKeywords consumes the sequence of keywords given if they are next, returning whether they were consumed.
+func (s *statementScanner) acceptKeywords(keywords ...string) bool {
+	if !s.peekKeywords(keywords...) {
+		return false
+	}
+	s.pos += len(keywords)
+	return true
+}
+
+// expectKeywords consumes the sequence of keywords given, returning a syntax error if they are not next.
+func (s *statementScanner) expectKeywords(keywords ...string) error {
+	if !s.acceptKeywords(keywords...) {
+		return s.syntaxError()
+	}
+	return nil
+}
+
+// acceptPunct consumes the punctuation given if it is next, returning whether it was consumed.
+func (s *statementScanner) acceptPunct(punct string) bool {
+	t := s.peek()
+	if t.kind == tokenPunct && t.val == punct {
+		s.pos++
+		return true
+	}
+	return false
+}
+
+// expectPunct consumes the punctuation given, returning a syntax error if it is not next.
+func (s *statementScanner) expectPunct(punct string) error {
+	if !s.acceptPunct(punct) {
+		return s.syntaxError()
+	}
+	return nil
+}
+
+// identifier consumes and returns a quoted or unquoted identifier.
+func (s *statementScanner) identifier() (string, error) {
+	t := s.peek()
+	if t.kind != tokenWord && t.kind != tokenQuotedIdent {
+		return "", s.syntaxError()
+	}
+	s.pos++
+	return t.val, nil
+}
+
+// qualifiedIdentifier consumes an identifier that may be qualified with a database name.
+func (s *statementScanner) qualifiedIdentifier() (qualifier string, name string, err error) {
+	name, err = s.identifier()
+	if err != nil {
+		return "", "", err
+	}
+	if s.acceptPunct(".") {
+		qualifier = name
+		name, err = s.identifier()
+		if err != nil {
+			return "", "", err
+		}
+	}
+	return qualifier, name, nil
+}
+
+// stringLiteral consumes and returns a string literal.
+func (s *statementScanner) stringLiteral() (string, error) {
+	t := s.peek()
+	if t.kind != tokenString {
+		return "", s.syntaxError()
+	}
+	s.pos++
+	return t.val, nil
+}
+
+// accountName consumes an account name in the form user[@host], returning it with both parts quoted in backticks. The
+// CURRENT_USER keyword is returned as an empty string.
+func (s *statementScanner) accountName() (string, error) {
+	if s.acceptKeywords("current_user") {
+		if s.acceptPunct("(") {
+			if err := s.expectPunct(")"); err != nil {
+				return "", err
+			}
+		}
+		return "", nil
+	}
+	user, err := s.accountNamePart()
+	if err != nil {
+		return "", err
+	}
+	host := "%"
+	if s.acceptPunct("@") {
+		host, err = s.accountNamePart()
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("`%s`@`%s`", user, host), nil
+}
+
+func (s *statementScanner) accountNamePart() (string, error) {
+	t := s.peek()
+	switch t.kind {
+	case tokenWord, tokenQuotedIdent, tokenString:
+		s.pos++
+		// unquoted host names such as 127.0.0.1 are split into several tokens, so they're joined back together
+		val := t.val
+		for t.kind == tokenWord || t.kind == tokenNumber {
+			n := s.peek()
+			if n.start != t.end || (n.kind != tokenNumber && n.kind != tokenWord && !(n.kind == tokenPunct && n.val == ".")) {
+				break
+			}
+			val += n.val
+			t = s.next()
+		}
+		return val, nil
+	case tokenNumber:
+		s.pos++
+		return t.val, nil
+	case tokenPunct:
+		if t.val != "%" {
+			return "", s.syntaxError()
+		}
+		s.pos++
+		return t.val, nil
+	default:
+		return "", s.syntaxError()
+	}
+}
+
+// textUntilKeywords consumes tokens until one of the given keywords is found outside of any parentheses, returning
+// the original text of the consumed tokens.
+func (s *statementScanner) textUntilKeywords(keywords ...string) (string, error) {
+	start := s.peek().start
+	end := start
+	depth := 0
+	for {
+		t := s.peek()
+		if t.kind == tokenEOF || (depth == 0 && t.kind == tokenPunct && t.val == ";") {
+			break
+		}
+		if depth == 0 {
+			stop := false
+			for _, keyword := range keywords {
+				if t.isKeyword(keyword) {
+					stop = true
+					break
+				}
+			}
+			if stop {
+				break
+			}
+		}
+		if t.kind == tokenPunct {
+			switch t.val {
+			case "(":
+				depth++
+			case ")":
+				depth--
+			}
+		}
+		end = t.end
+		s.pos++
+	}
+	if end == start {
+		return "", s.syntaxError()
+	}
+	return s.query[start:end], nil
+}
+
+// parenthesizedList consumes a parenthesized, comma-separated list, returning the original text of each item. Commas
+// nested in parentheses don't separate items.
+func (s *statementScanner) parenthesizedList() ([]string, error) {
+	if err := s.expectPunct("("); err != nil {
+		return nil, err
+	}
+	var items []string
+	start := s.peek().start
+	depth := 0
+	for {
+		t := s.peek()
+		if t.kind == tokenEOF {
+			return nil, s.syntaxError()
+		}
+		if t.kind == tokenPunct {
+			switch {
+			case t.val == "(":
+				depth++
+			case t.val == ")" && depth > 0:
+				depth--
+			case depth == 0 && (t.val == "," || t.val == ")"):
+				item := strings.TrimSpace(s.query[start:t.start])
+				if item == "" {
+					return nil, s.syntaxError()
+				}
+				items = append(items, item)
+				s.pos++
+				if t.val == ")" {
+					return items, nil
+				}
+				start = s.peek().start
+				continue
+			}
+		}
+		s.pos++
+	}
+}
+
+// identifierList consumes a parenthesized, comma-separated list of identifiers, which may be empty.
+func (s *statementScanner) identifierList() ([]string, error) {
+	if err := s.expectPunct("("); err != nil {
+		return nil, err
+	}
+	var names []string
+	if s.acceptPunct(")") {
+		return names, nil
+	}
+	for {
+		name, err := s.identifier()
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, name)
+		if !s.acceptPunct(",") {
+			break
+		}
+	}
+	if err := s.expectPunct(")"); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// integer consumes and returns a non-negative integer literal.
+func (s *statementScanner) integer() (int, error) {
+	t := s.peek()
+	if t.kind != tokenNumber {
+		return 0, s.syntaxError()
+	}
+	i, err := strconv.Atoi(t.val)
+	if err != nil {
+		return 0, s.syntaxError()
+	}
+	s.pos++
+	return i, nil
+}
+
+// rest consumes all remaining tokens, returning the original text from the current position to the end of the query,
+// without any trailing semicolon.
+func (s *statementScanner) rest() string {
+	start := s.peek().start
+	s.pos = len(s.tokens) - 1
+	text := strings.TrimSpace(s.query[start:])
+	return strings.TrimSpace(strings.TrimSuffix(text, ";"))
+}
+
+// syntaxError returns a syntax error that references the current token.
+func (s *statementScanner) syntaxError() error {
+	t := s.peek()
+	if t.kind == tokenEOF {
+		return sql.ErrSyntaxError.New(fmt.Sprintf("syntax error at position %d", t.start+1))
+	}
+	return sql.ErrSyntaxError.New(fmt.Sprintf("syntax error at position %d near '%s'", t.end+1, t.val))
+}
+
+// convertExpressionString parses the given text as a single expression.
+func convertExpressionString(ctx *sql.Context, exprStr string) (sql.Expression, error) {
+	stmt, err := sqlparser.Parse("SELECT " + exprStr)
+	if err != nil {
+		return nil, sql.ErrSyntaxError.New(err.Error())
+	}
+	parserSelect, ok := stmt.(*sqlparser.Select)
+	if !ok || len(parserSelect.SelectExprs) != 1 {
+		return nil, sql.ErrSyntaxError.New(exprStr)
+	}
+	aliasedExpr, ok := parserSelect.SelectExprs[0].(*sqlparser.AliasedExpr)
+	if !ok {
+		return nil, sql.ErrSyntaxError.New(exprStr)
+	}
+	return ExprToExpression(ctx, aliasedExpr.Expr)
+}
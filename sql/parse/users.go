@@ -0,0 +1,425 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"strings"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/plan"
+)
+
+// parseUserStatement parses the ALTER USER, SET ROLE and SET DEFAULT ROLE statements, along with CREATE USER statements
+// that immediately expire the password using PASSWORD EXPIRE, which the vitess grammar does not yet support.
+func parseUserStatement(ctx *sql.Context, s *statementScanner) (sql.Node, bool, error) {
+	switch {
+	case s.acceptKeywords("alter", "user"):
+		node, err := parseAlterUser(s)
+		return node, true, err
+	case s.acceptKeywords("set", "role"):
+		node, err := parseSetRole(s)
+		return node, true, err
+	case s.acceptKeywords("set", "default", "role"):
+		node, err := parseSetDefaultRole(s)
+		return node, true, err
+	case s.acceptKeywords("create", "user"):
+		return parseCreateUserWithPasswordExpire(ctx, s)
+	default:
+		return nil, false, nil
+	}
+}
+
+func parseAlterUser(s *statementScanner) (sql.Node, error) {
+	alterUser := &plan.AlterUser{
+		IfExists: s.acceptKeywords("if", "exists"),
+		MySQLDb:  sql.UnresolvedDatabase("mysql"),
+	}
+	for {
+		user, err := parseAlteredUser(s)
+		if err != nil {
+			return nil, err
+		}
+		alterUser.Users = append(alterUser.Users, user)
+		if !s.acceptPunct(",") {
+			break
+		}
+	}
+
+	// TLS options are accepted but not enforced, which matches CREATE USER
+	if s.acceptKeywords("require") {
+		if err := parseUserTLSOptions(s); err != nil {
+			return nil, err
+		}
+	}
+	if s.acceptKeywords("with") {
+		limits, err := parseAccountLimits(s)
+		if err != nil {
+			return nil, err
+		}
+		alterUser.AccountLimits = limits
+	}
+	for {
+		if s.acceptKeywords("account", "lock") {
+			locked := true
+			alterUser.Locked = &locked
+			continue
+		}
+		if s.acceptKeywords("account", "unlock") {
+			locked := false
+			alterUser.Locked = &locked
+			continue
+		}
+		if alterUser.PasswordOptions == nil {
+			alterUser.PasswordOptions = &plan.PasswordOptions{}
+		}
+		ok, err := parsePasswordOption(s, alterUser.PasswordOptions)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			break
+		}
+	}
+	if *alterUser.PasswordOptions == (plan.PasswordOptions{}) {
+		alterUser.PasswordOptions = nil
+	}
+	// Comments and attributes are accepted but not stored, which matches CREATE USER
+	if s.acceptKeywords("comment") || s.acceptKeywords("attribute") {
+		if _, err := s.stringLiteral(); err != nil {
+			return nil, err
+		}
+	}
+	if !s.atEnd() {
+		return nil, s.syntaxError()
+	}
+	return alterUser, nil
+}
+
+// parseAlteredUser parses a single account of an ALTER USER statement, along with its authentication.
+func parseAlteredUser(s *statementScanner) (plan.AlteredUser, error) {
+	var user plan.AlteredUser
+	if s.peekKeywords("user") && s.tokens[s.pos+1].kind == tokenPunct && s.tokens[s.pos+1].val == "(" {
+		s.pos++
+		if err := s.expectPunct("("); err != nil {
+			return user, err
+		}
+		if err := s.expectPunct(")"); err != nil {
+			return user, err
+		}
+		user.CurrentUser = true
+	} else if s.acceptKeywords("current_user") {
+		if s.acceptPunct("(") {
+			if err := s.expectPunct(")"); err != nil {
+				return user, err
+			}
+		}
+		user.CurrentUser = true
+	} else {
+		name, err := s.accountNamePart()
+		if err != nil {
+			return user, err
+		}
+		user.UserName = plan.UserName{Name: name, AnyHost: true}
+		if s.acceptPunct("@") {
+			host, err := s.accountNamePart()
+			if err != nil {
+				return user, err
+			}
+			user.UserName.Host = host
+			user.UserName.AnyHost = host == "%"
+		}
+	}
+
+	if !s.acceptKeywords("identified") {
+		return user, nil
+	}
+	plugin := ""
+	if s.acceptKeywords("with") {
+		t := s.next()
+		if t.kind != tokenWord && t.kind != tokenQuotedIdent && t.kind != tokenString {
+			return user, s.syntaxError()
+		}
+		plugin = t.val
+		if !s.peekKeywords("by") {
+			user.Auth1 = plan.NewOtherAuthentication("", plugin)
+			return user, nil
+		}
+	}
+	if err := s.expectKeywords("by"); err != nil {
+		return user, err
+	}
+	password, err := s.stringLiteral()
+	if err != nil {
+		return user, err
+	}
+	if plugin == "mysql_native_password" && len(password) > 0 {
+		user.Auth1 = plan.AuthenticationMysqlNativePassword(password)
+	} else if len(plugin) > 0 {
+		user.Auth1 = plan.NewOtherAuthentication(password, plugin)
+	} else {
+		user.Auth1 = plan.NewDefaultAuthentication(password)
+	}
+	return user, nil
+}
+
+func parseSetRole(s *statementScanner) (sql.Node, error) {
+	var typ plan.SetRoleType
+	var roles []plan.UserName
+	var err error
+	switch {
+	case s.acceptKeywords("default"):
+		typ = plan.SetRoleType_Default
+	case s.acceptKeywords("none"):
+		typ = plan.SetRoleType_None
+	case s.acceptKeywords("all"):
+		typ = plan.SetRoleType_All
+		if s.acceptKeywords("except") {
+			typ = plan.SetRoleType_AllExcept
+			if roles, err = parseAccountNames(s); err != nil {
+				return nil, err
+			}
+		}
+	default:
+		typ = plan.SetRoleType_List
+		if roles, err = parseAccountNames(s); err != nil {
+			return nil, err
+		}
+	}
+	if !s.atEnd() {
+		return nil, s.syntaxError()
+	}
+	return plan.NewSetRole(typ, roles), nil
+}
+
+func parseSetDefaultRole(s *statementScanner) (sql.Node, error) {
+	var typ plan.SetRoleType
+	var roles []plan.UserName
+	var err error
+	switch {
+	case s.acceptKeywords("none"):
+		typ = plan.SetRoleType_None
+	case s.acceptKeywords("all"):
+		typ = plan.SetRoleType_All
+	default:
+		typ = plan.SetRoleType_List
+		if roles, err = parseAccountNames(s); err != nil {
+			return nil, err
+		}
+	}
+	if err = s.expectKeywords("to"); err != nil {
+		return nil, err
+	}
+	users, err := parseAccountNames(s)
+	if err != nil {
+		return nil, err
+	}
+	if !s.atEnd() {
+		return nil, s.syntaxError()
+	}
+	return plan.NewSetDefaultRole(typ, roles, users), nil
+}
+
+// parseAccountNames parses a comma-separated list of user or role names, where each host is optional.
+func parseAccountNames(s *statementScanner) ([]plan.UserName, error) {
+	var names []plan.UserName
+	for {
+		name, err := s.accountNamePart()
+		if err != nil {
+			return nil, err
+		}
+		userName := plan.UserName{Name: name, AnyHost: true}
+		if s.acceptPunct("@") {
+			host, err := s.accountNamePart()
+			if err != nil {
+				return nil, err
+			}
+			userName.Host = host
+			userName.AnyHost = host == "%"
+		}
+		names = append(names, userName)
+		if !s.acceptPunct(",") {
+			return names, nil
+		}
+	}
+}
+
+// parseUserTLSOptions parses the options that follow REQUIRE.
+func parseUserTLSOptions(s *statementScanner) error {
+	if s.acceptKeywords("none") {
+		return nil
+	}
+	for parsed := false; ; parsed = true {
+		switch {
+		case s.acceptKeywords("ssl"), s.acceptKeywords("x509"):
+		case s.acceptKeywords("cipher"), s.acceptKeywords("issuer"), s.acceptKeywords("subject"):
+			if _, err := s.stringLiteral(); err != nil {
+				return err
+			}
+		default:
+			if !parsed {
+				return s.syntaxError()
+			}
+			return nil
+		}
+		s.acceptKeywords("and")
+	}
+}
+
+// parseAccountLimits parses the resource options that follow WITH.
+func parseAccountLimits(s *statementScanner) (*plan.AccountLimits, error) {
+	limits := &plan.AccountLimits{}
+	for parsed := false; ; parsed = true {
+		var limit **int64
+		switch {
+		case s.acceptKeywords("max_queries_per_hour"):
+			limit = &limits.MaxQueriesPerHour
+		case s.acceptKeywords("max_updates_per_hour"):
+			limit = &limits.MaxUpdatesPerHour
+		case s.acceptKeywords("max_connections_per_hour"):
+			limit = &limits.MaxConnectionsPerHour
+		case s.acceptKeywords("max_user_connections"):
+			limit = &limits.MaxUserConnections
+		default:
+			if !parsed {
+				return nil, s.syntaxError()
+			}
+			return limits, nil
+		}
+		val, err := s.integer()
+		if err != nil {
+			return nil, err
+		}
+		val64 := int64(val)
+		*limit = &val64
+	}
+}
+
+// parsePasswordOption parses a single password option into the given options, returning false if the next tokens are
+// not a password option.
+func parsePasswordOption(s *statementScanner, options *plan.PasswordOptions) (bool, error) {
+	parseInt := func() (*int64, error) {
+		val, err := s.integer()
+		if err != nil {
+			return nil, err
+		}
+		val64 := int64(val)
+		return &val64, nil
+	}
+	negativeOne := func() *int64 {
+		val := int64(-1)
+		return &val
+	}
+
+	var err error
+	switch {
+	case s.acceptKeywords("password", "expire"):
+		switch {
+		case s.acceptKeywords("default"):
+			options.ExpirationTime = negativeOne()
+		case s.acceptKeywords("never"):
+			zero := int64(0)
+			options.ExpirationTime = &zero
+		case s.acceptKeywords("interval"):
+			if options.ExpirationTime, err = parseInt(); err != nil {
+				return true, err
+			}
+			if err = s.expectKeywords("day"); err != nil {
+				return true, err
+			}
+		default:
+			options.Expired = true
+		}
+	case s.acceptKeywords("password", "history"):
+		if s.acceptKeywords("default") {
+			options.History = nil
+		} else if options.History, err = parseInt(); err != nil {
+			return true, err
+		}
+	case s.acceptKeywords("password", "reuse", "interval"):
+		if s.acceptKeywords("default") {
+			options.ReuseInterval = nil
+		} else {
+			if options.ReuseInterval, err = parseInt(); err != nil {
+				return true, err
+			}
+			if err = s.expectKeywords("day"); err != nil {
+				return true, err
+			}
+		}
+	case s.acceptKeywords("password", "require", "current"):
+		if s.acceptKeywords("optional") {
+			options.RequireCurrentOptional = true
+		} else {
+			s.acceptKeywords("default")
+			options.RequireCurrentOptional = false
+		}
+	case s.acceptKeywords("failed_login_attempts"):
+		if options.FailedAttempts, err = parseInt(); err != nil {
+			return true, err
+		}
+	case s.acceptKeywords("password_lock_time"):
+		if s.acceptKeywords("unbounded") {
+			options.LockTime = negativeOne()
+		} else if options.LockTime, err = parseInt(); err != nil {
+			return true, err
+		}
+	default:
+		return false, nil
+	}
+	return true, nil
+}
+
+// parseCreateUserWithPasswordExpire parses a CREATE USER statement that contains PASSWORD EXPIRE without any of the
+// DEFAULT, NEVER or INTERVAL qualifiers. The clause is removed before the rest of the statement is handed back to the
+// vitess parser. Returns false if the statement does not contain the clause.
+func parseCreateUserWithPasswordExpire(ctx *sql.Context, s *statementScanner) (sql.Node, bool, error) {
+	var sb strings.Builder
+	last := 0
+	for i := s.pos; i+1 < len(s.tokens); i++ {
+		if !s.tokens[i].isKeyword("password") || !s.tokens[i+1].isKeyword("expire") {
+			continue
+		}
+		qualifier := s.tokens[i+2]
+		if qualifier.isKeyword("default") || qualifier.isKeyword("never") || qualifier.isKeyword("interval") {
+			continue
+		}
+		sb.WriteString(s.query[last:s.tokens[i].start])
+		last = s.tokens[i+1].end
+	}
+	if last == 0 {
+		return nil, false, nil
+	}
+	sb.WriteString(s.query[last:])
+
+	stmt, err := sqlparser.Parse(sb.String())
+	if err != nil {
+		return nil, true, sql.ErrSyntaxError.New(err.Error())
+	}
+	createUserStmt, ok := stmt.(*sqlparser.CreateUser)
+	if !ok {
+		return nil, true, s.syntaxError()
+	}
+	createUser, err := convertCreateUser(ctx, createUserStmt)
+	if err != nil {
+		return nil, true, err
+	}
+	if createUser.PasswordOptions == nil {
+		createUser.PasswordOptions = &plan.PasswordOptions{}
+	}
+	createUser.PasswordOptions.Expired = true
+	return createUser, true, nil
+}
@@ -0,0 +1,554 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// PartitionMethod is the method used to assign the rows of a table to its user-defined partitions.
+type PartitionMethod string
+
+const (
+	PartitionMethod_Range        PartitionMethod = "RANGE"
+	PartitionMethod_RangeColumns PartitionMethod = "RANGE COLUMNS"
+	PartitionMethod_List         PartitionMethod = "LIST"
+	PartitionMethod_ListColumns  PartitionMethod = "LIST COLUMNS"
+	PartitionMethod_Hash         PartitionMethod = "HASH"
+	PartitionMethod_LinearHash   PartitionMethod = "LINEAR HASH"
+	PartitionMethod_Key          PartitionMethod = "KEY"
+	PartitionMethod_LinearKey    PartitionMethod = "LINEAR KEY"
+)
+
+// IsRange returns whether rows are assigned to partitions by ranges of values.
+func (m PartitionMethod) IsRange() bool {
+	return m == PartitionMethod_Range || m == PartitionMethod_RangeColumns
+}
+
+// IsList returns whether rows are assigned to partitions by lists of values.
+func (m PartitionMethod) IsList() bool {
+	return m == PartitionMethod_List || m == PartitionMethod_ListColumns
+}
+
+// IsHash returns whether rows are assigned to partitions by hashing, which is the case for HASH and KEY partitioning.
+func (m PartitionMethod) IsHash() bool {
+	return m == PartitionMethod_Hash || m == PartitionMethod_LinearHash || m == PartitionMethod_Key || m == PartitionMethod_LinearKey
+}
+
+// UsesColumns returns whether rows are partitioned by a list of columns rather than by an expression.
+func (m PartitionMethod) UsesColumns() bool {
+	return m == PartitionMethod_RangeColumns || m == PartitionMethod_ListColumns || m == PartitionMethod_Key || m == PartitionMethod_LinearKey
+}
+
+// partitionMaxValue is the type of PartitionMaxValue.
+type partitionMaxValue struct{}
+
+func (partitionMaxValue) String() string {
+	return "MAXVALUE"
+}
+
+// PartitionMaxValue is the MAXVALUE bound of a RANGE partition, which is greater than any other value.
+var PartitionMaxValue = partitionMaxValue{}
+
+// PartitionDefinition is a single user-defined partition of a table.
+type PartitionDefinition struct {
+	// Name is the name of the partition.
+	Name string
+	// LessThan is the exclusive upper bound of a RANGE partition, with a value for each partitioning column of RANGE
+	// COLUMNS partitioning, or a single int64 value for RANGE partitioning. Any value may be PartitionMaxValue.
+	LessThan []interface{}
+	// In are the values of a LIST partition. Each entry has a value for each partitioning column of LIST COLUMNS
+	// partitioning, or a single int64 value for LIST partitioning.
+	In [][]interface{}
+	// Comment is the partition's comment, if any.
+	Comment string
+}
+
+// PartitionScheme is the user-defined partitioning of a table, declared with a PARTITION BY clause.
+type PartitionScheme struct {
+	// Method is the partitioning method.
+	Method PartitionMethod
+	// Expression is the partitioning expression of RANGE, LIST and HASH partitioning, which must evaluate to an
+	// integer. It is resolved against the table's schema. Nil when the method partitions by columns.
+	Expression Expression
+	// ExpressionString is the text of Expression, as displayed by SHOW CREATE TABLE.
+	ExpressionString string
+	// Columns are the partitioning columns of RANGE COLUMNS, LIST COLUMNS and KEY partitioning.
+	Columns []string
+	// Definitions are the partitions of the table, in order.
+	Definitions []PartitionDefinition
+}
+
+// PartitionNames returns the names of the partitions, in order.
+func (p *PartitionScheme) PartitionNames() []string {
+	names := make([]string, len(p.Definitions))
+	for i, def := range p.Definitions {
+		names[i] = def.Name
+	}
+	return names
+}
+
+// PartitionIndex returns the index of the partition with the name given, or -1 if there is no such partition.
+func (p *PartitionScheme) PartitionIndex(name string) int {
+	for i, def := range p.Definitions {
+		if strings.EqualFold(def.Name, name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Copy returns a copy of this partitioning, with a new slice of definitions.
+func (p *PartitionScheme) Copy() *PartitionScheme {
+	np := *p
+	np.Columns = append([]string(nil), p.Columns...)
+	np.Definitions = append([]PartitionDefinition(nil), p.Definitions...)
+	return &np
+}
+
+// Validate checks that the partitions are consistent with each other: names must be unique, RANGE bounds must be
+// strictly increasing, and no value may appear in more than one LIST partition. |sch| is the schema of the table.
+func (p *PartitionScheme) Validate(sch Schema) error {
+	for i, def := range p.Definitions {
+		for _, prev := range p.Definitions[:i] {
+			if strings.EqualFold(prev.Name, def.Name) {
+				return ErrDuplicatePartitionName.New(def.Name)
+			}
+		}
+	}
+	colTypes, err := p.columnTypes(sch)
+	if err != nil {
+		return err
+	}
+	switch {
+	case p.Method.IsRange():
+		for i := 1; i < len(p.Definitions); i++ {
+			cmp, err := compareRangeBounds(colTypes, p.Definitions[i-1].LessThan, p.Definitions[i].LessThan)
+			if err != nil {
+				return err
+			}
+			if cmp >= 0 {
+				if p.Method == PartitionMethod_Range && p.Definitions[i-1].LessThan[0] == PartitionMaxValue {
+					return ErrMaxValueNotLast.New()
+				}
+				return ErrRangeNotIncreasing.New()
+			}
+		}
+	case p.Method.IsList():
+		var seen [][]interface{}
+		for _, def := range p.Definitions {
+			for _, in := range def.In {
+				for _, other := range seen {
+					eq, err := partitionValuesEqual(in, colTypes, other)
+					if err != nil {
+						return err
+					}
+					if eq {
+						return ErrMultipleDefinitionInListPartition.New()
+					}
+				}
+				seen = append(seen, in)
+			}
+		}
+	}
+	return nil
+}
+
+// ValuesDescription returns the text of the values of the partition at the index given, as displayed by
+// information_schema.PARTITIONS: the bound of a RANGE partition, or the list of values of a LIST partition. Returns an
+// empty string for HASH and KEY partitions. |sch| is the schema of the table.
+func (p *PartitionScheme) ValuesDescription(ctx *Context, sch Schema, i int) (string, error) {
+	colTypes, err := p.columnTypes(sch)
+	if err != nil {
+		return "", err
+	}
+	def := p.Definitions[i]
+	switch {
+	case p.Method.IsRange():
+		return formatPartitionTuple(ctx, colTypes, def.LessThan)
+	case p.Method.IsList():
+		strs := make([]string, len(def.In))
+		for j, in := range def.In {
+			str, err := formatPartitionTuple(ctx, colTypes, in)
+			if err != nil {
+				return "", err
+			}
+			if len(in) > 1 {
+				str = "(" + str + ")"
+			}
+			strs[j] = str
+		}
+		return strings.Join(strs, ","), nil
+	default:
+		return "", nil
+	}
+}
+
+// columnTypes returns the types of the partitioning columns, or a nil slice when partitioning by an expression.
+func (p *PartitionScheme) columnTypes(sch Schema) ([]Type, error) {
+	if !p.Method.UsesColumns() {
+		return nil, nil
+	}
+	colTypes := make([]Type, len(p.Columns))
+	for i, col := range p.Columns {
+		idx := sch.IndexOfColName(col)
+		if idx < 0 {
+			return nil, ErrPartitionFieldNotFound.New()
+		}
+		colTypes[i] = sch[idx].Type
+	}
+	return colTypes, nil
+}
+
+// partitionColumnExpression is implemented by expressions that reference a column, such as GetField.
+type partitionColumnExpression interface {
+	Expression
+	Name() string
+	Table() string
+}
+
+// ColumnNames returns the names of the columns that the partitioning depends on.
+func (p *PartitionScheme) ColumnNames() []string {
+	if p.Method.UsesColumns() {
+		return p.Columns
+	}
+	var names []string
+	Inspect(p.Expression, func(e Expression) bool {
+		if col, ok := e.(partitionColumnExpression); ok {
+			names = append(names, col.Name())
+		}
+		return true
+	})
+	return names
+}
+
+// UsesColumn returns whether the partitioning depends on the column named.
+func (p *PartitionScheme) UsesColumn(name string) bool {
+	for _, col := range p.ColumnNames() {
+		if strings.EqualFold(col, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// PartitionForRow returns the index of the partition that the row given belongs to. |sch| is the schema of the table
+// that the row belongs to. Returns ErrNoPartitionForValue if no partition accepts the row.
+func (p *PartitionScheme) PartitionForRow(ctx *Context, sch Schema, row Row) (int, error) {
+	if len(p.Definitions) == 0 {
+		return -1, ErrNoPartitionForValue.New(row)
+	}
+	switch p.Method {
+	case PartitionMethod_Range:
+		val, isNull, err := p.evalInt(ctx, row)
+		if err != nil {
+			return -1, err
+		}
+		for i, def := range p.Definitions {
+			// NULL is less than any other value, so it always belongs to the first partition
+			if isNull || def.LessThan[0] == PartitionMaxValue || val < def.LessThan[0].(int64) {
+				return i, nil
+			}
+		}
+		return -1, ErrNoPartitionForValue.New(val)
+	case PartitionMethod_RangeColumns:
+		vals, colTypes, err := p.columnValues(sch, row)
+		if err != nil {
+			return -1, err
+		}
+		for i, def := range p.Definitions {
+			cmp, err := compareToRangeBound(vals, colTypes, def.LessThan)
+			if err != nil {
+				return -1, err
+			}
+			if cmp < 0 {
+				return i, nil
+			}
+		}
+		return -1, ErrNoPartitionForValue.New(formatPartitionValues(vals))
+	case PartitionMethod_List:
+		val, isNull, err := p.evalInt(ctx, row)
+		if err != nil {
+			return -1, err
+		}
+		for i, def := range p.Definitions {
+			for _, in := range def.In {
+				if (isNull && in[0] == nil) || (!isNull && in[0] != nil && in[0].(int64) == val) {
+					return i, nil
+				}
+			}
+		}
+		if isNull {
+			return -1, ErrNoPartitionForValue.New("NULL")
+		}
+		return -1, ErrNoPartitionForValue.New(val)
+	case PartitionMethod_ListColumns:
+		vals, colTypes, err := p.columnValues(sch, row)
+		if err != nil {
+			return -1, err
+		}
+		for i, def := range p.Definitions {
+			for _, in := range def.In {
+				eq, err := partitionValuesEqual(vals, colTypes, in)
+				if err != nil {
+					return -1, err
+				}
+				if eq {
+					return i, nil
+				}
+			}
+		}
+		return -1, ErrNoPartitionForValue.New(formatPartitionValues(vals))
+	case PartitionMethod_Hash, PartitionMethod_LinearHash:
+		val, _, err := p.evalInt(ctx, row)
+		if err != nil {
+			return -1, err
+		}
+		// Negative values are placed by their absolute value, which is computed on the unsigned value so that it
+		// doesn't overflow for the smallest int64
+		hash := uint64(val)
+		if val < 0 {
+			hash = -hash
+		}
+		return p.hashPartition(hash), nil
+	case PartitionMethod_Key, PartitionMethod_LinearKey:
+		vals, _, err := p.columnValues(sch, row)
+		if err != nil {
+			return -1, err
+		}
+		hash, err := HashOf(vals)
+		if err != nil {
+			return -1, err
+		}
+		return p.hashPartition(hash), nil
+	default:
+		return -1, fmt.Errorf("unknown partitioning method: %s", p.Method)
+	}
+}
+
+// hashPartition returns the index of the partition for the hash given, using the linear powers-of-two algorithm for
+// LINEAR HASH and LINEAR KEY partitioning and the modulus otherwise.
+func (p *PartitionScheme) hashPartition(hash uint64) int {
+	num := uint64(len(p.Definitions))
+	if p.Method != PartitionMethod_LinearHash && p.Method != PartitionMethod_LinearKey {
+		return int(hash % num)
+	}
+	v := uint64(1)
+	for v < num {
+		v <<= 1
+	}
+	n := hash & (v - 1)
+	for n >= num {
+		v >>= 1
+		n = n & (v - 1)
+	}
+	return int(n)
+}
+
+// evalInt evaluates the partitioning expression against the row given, returning its integer value and whether it
+// was NULL.
+func (p *PartitionScheme) evalInt(ctx *Context, row Row) (int64, bool, error) {
+	val, err := p.Expression.Eval(ctx, row)
+	if err != nil {
+		return 0, false, err
+	}
+	if val == nil {
+		return 0, true, nil
+	}
+	i, ok := partitionIntValue(val)
+	if !ok {
+		return 0, false, ErrWrongPartitionFunctionType.New()
+	}
+	return i, false, nil
+}
+
+// columnValues returns the values of the partitioning columns of the row given, along with their types.
+func (p *PartitionScheme) columnValues(sch Schema, row Row) (Row, []Type, error) {
+	vals := make(Row, len(p.Columns))
+	colTypes := make([]Type, len(p.Columns))
+	for i, col := range p.Columns {
+		idx := sch.IndexOfColName(col)
+		if idx < 0 {
+			return nil, nil, ErrColumnNotFound.New(col)
+		}
+		vals[i] = row[idx]
+		colTypes[i] = sch[idx].Type
+	}
+	return vals, colTypes, nil
+}
+
+// partitionIntValue converts the integer value of a partitioning expression to an int64.
+func partitionIntValue(val interface{}) (int64, bool) {
+	switch v := val.(type) {
+	case int:
+		return int64(v), true
+	case int8:
+		return int64(v), true
+	case int16:
+		return int64(v), true
+	case int32:
+		return int64(v), true
+	case int64:
+		return v, true
+	case uint:
+		return int64(v), true
+	case uint8:
+		return int64(v), true
+	case uint16:
+		return int64(v), true
+	case uint32:
+		return int64(v), true
+	case uint64:
+		return int64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	case decimal.Decimal:
+		// integer arithmetic such as a % 3 produces decimals
+		if !v.Equal(v.Truncate(0)) {
+			return 0, false
+		}
+		return v.IntPart(), true
+	default:
+		return 0, false
+	}
+}
+
+// compareRangeBounds compares the bounds of two RANGE or RANGE COLUMNS partitions. |colTypes| is nil for RANGE
+// partitioning, whose bounds are int64 values.
+func compareRangeBounds(colTypes []Type, a, b []interface{}) (int, error) {
+	for i := range a {
+		aMax, bMax := a[i] == PartitionMaxValue, b[i] == PartitionMaxValue
+		switch {
+		case aMax && bMax:
+			continue
+		case aMax:
+			return 1, nil
+		case bMax:
+			return -1, nil
+		}
+		var cmp int
+		if colTypes == nil {
+			switch {
+			case a[i].(int64) < b[i].(int64):
+				cmp = -1
+			case a[i].(int64) > b[i].(int64):
+				cmp = 1
+			}
+		} else {
+			var err error
+			cmp, err = colTypes[i].Compare(a[i], b[i])
+			if err != nil {
+				return 0, err
+			}
+		}
+		if cmp != 0 {
+			return cmp, nil
+		}
+	}
+	return 0, nil
+}
+
+// compareToRangeBound compares the column values given to the bound of a RANGE COLUMNS partition, in the same way as
+// comparing two tuples.
+func compareToRangeBound(vals Row, colTypes []Type, bound []interface{}) (int, error) {
+	for i, val := range vals {
+		if bound[i] == PartitionMaxValue {
+			return -1, nil
+		}
+		// NULL is less than any other value
+		if val == nil {
+			if bound[i] == nil {
+				continue
+			}
+			return -1, nil
+		} else if bound[i] == nil {
+			return 1, nil
+		}
+		cmp, err := colTypes[i].Compare(val, bound[i])
+		if err != nil {
+			return 0, err
+		}
+		if cmp != 0 {
+			return cmp, nil
+		}
+	}
+	return 0, nil
+}
+
+// partitionValuesEqual returns whether the values given are equal to the values of a LIST or LIST COLUMNS partition.
+// |colTypes| is nil for LIST partitioning, whose values are int64 values. NULL values are equal to each other.
+func partitionValuesEqual(vals Row, colTypes []Type, in []interface{}) (bool, error) {
+	for i, val := range vals {
+		if val == nil || in[i] == nil || colTypes == nil {
+			if val != in[i] {
+				return false, nil
+			}
+			continue
+		}
+		cmp, err := colTypes[i].Compare(val, in[i])
+		if err != nil {
+			return false, err
+		}
+		if cmp != 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// formatPartitionTuple formats the values of a partition definition, quoting them as SQL literals where necessary.
+// |colTypes| is nil for RANGE and LIST partitioning, whose values are int64 values.
+func formatPartitionTuple(ctx *Context, colTypes []Type, vals []interface{}) (string, error) {
+	strs := make([]string, len(vals))
+	for i, val := range vals {
+		switch {
+		case val == PartitionMaxValue:
+			strs[i] = "MAXVALUE"
+		case val == nil:
+			strs[i] = "NULL"
+		case colTypes == nil:
+			strs[i] = fmt.Sprintf("%d", val)
+		default:
+			sqlVal, err := colTypes[i].SQL(ctx, nil, val)
+			if err != nil {
+				return "", err
+			}
+			if sqlVal.IsQuoted() {
+				strs[i] = "'" + strings.ReplaceAll(sqlVal.ToString(), "'", "''") + "'"
+			} else {
+				strs[i] = sqlVal.ToString()
+			}
+		}
+	}
+	return strings.Join(strs, ","), nil
+}
+
+func formatPartitionValues(vals Row) string {
+	strs := make([]string, len(vals))
+	for i, val := range vals {
+		if val == nil {
+			strs[i] = "NULL"
+		} else {
+			strs[i] = fmt.Sprintf("%v", val)
+		}
+	}
+	return strings.Join(strs, ",")
+}
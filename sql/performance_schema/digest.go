@@ -0,0 +1,179 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package performance_schema
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/dolthub/vitess/go/vt/sqlparser"
+)
+
+// valueListText is the digest text that replaces a parenthesized list of values.
+const valueListText = "(...)"
+
+// valueRowsText is the digest text that replaces every row after the first one in a list of rows.
+const valueRowsText = "/* , ... */"
+
+// statementNames maps the second keyword of the statements that are named after their first two keywords, such as
+// CREATE TABLE, to the name that is used for the statement. Keywords that are not in the map are used as they are.
+var statementNames = map[string]string{
+	"database": "db",
+	"schema":   "db",
+}
+
+// twoKeywordStatements are the statements that are named after their first two keywords.
+var twoKeywordStatements = map[string]struct{}{
+	"alter":  {},
+	"create": {},
+	"drop":   {},
+	"show":   {},
+}
+
+// singleKeywordStatements maps the first keyword of a statement to the name of the statement, for the statements that
+// are not named after their first keyword.
+var singleKeywordStatements = map[string]string{
+	"start":    "begin",
+	"set":      "set_option",
+	"use":      "change_db",
+	"call":     "call_procedure",
+	"lock":     "lock_tables",
+	"unlock":   "unlock_tables",
+	"with":     "select",
+	"desc":     "explain",
+	"describe": "explain",
+}
+
+// Digest returns the normalized text of the given statement, along with its digest. The normalized text replaces every
+// literal with "?", collapses lists of values, uppercases keywords, quotes identifiers and removes comments, so that
+// statements that only differ in their literals share a digest. The digest is the hex-encoded SHA-256 hash of the
+// normalized text.
+func Digest(query string) (digestText string, digest string) {
+	tokens := normalizeTokens(query)
+	digestText = strings.Join(tokens, " ")
+	hash := sha256.Sum256([]byte(digestText))
+	return digestText, hex.EncodeToString(hash[:])
+}
+
+// StatementName returns the name of the statement with the given normalized text, as used in the event names
+// ("statement/sql/<name>") and in the "Com_<name>" status variables.
+func StatementName(digestText string) string {
+	fields := strings.Fields(digestText)
+	if len(fields) == 0 {
+		return "error"
+	}
+	first := strings.ToLower(fields[0])
+	if name, ok := singleKeywordStatements[first]; ok {
+		return name
+	}
+	if _, ok := twoKeywordStatements[first]; ok && len(fields) > 1 && !strings.HasPrefix(fields[1], "`") {
+		second := strings.ToLower(fields[1])
+		if name, ok := statementNames[second]; ok {
+			second = name
+		}
+		return first + "_" + second
+	}
+	if strings.HasPrefix(first, "`") || strings.HasPrefix(first, "?") || strings.HasPrefix(first, "(") {
+		return "error"
+	}
+	return first
+}
+
+// normalizeTokens returns the normalized tokens of the given statement.
+func normalizeTokens(query string) []string {
+	tokenizer := sqlparser.NewStringTokenizer(query)
+	tokenizer.SkipSpecialComments = true
+	var tokens []string
+	for {
+		typ, val := tokenizer.Scan()
+		if typ == 0 || typ == ';' {
+			break
+		}
+		switch typ {
+		case sqlparser.COMMENT:
+			continue
+		case sqlparser.LEX_ERROR:
+			return tokens
+		case sqlparser.STRING, sqlparser.INTEGRAL, sqlparser.FLOAT, sqlparser.HEXNUM, sqlparser.HEX,
+			sqlparser.BIT_LITERAL, sqlparser.VALUE_ARG:
+			tokens = append(tokens, "?")
+		case sqlparser.LIST_ARG:
+			tokens = append(tokens, valueListText)
+		case sqlparser.ID:
+			if len(val) > 0 && val[0] == '@' {
+				tokens = append(tokens, string(val))
+			} else {
+				tokens = append(tokens, "`"+strings.ReplaceAll(string(val), "`", "``")+"`")
+			}
+		default:
+			if val == nil {
+				tokens = append(tokens, tokenText(query, tokenizer))
+			} else {
+				tokens = append(tokens, strings.ToUpper(string(val)))
+			}
+		}
+		tokens = collapseValueList(tokens)
+	}
+	return tokens
+}
+
+// tokenText returns the text of the token that was last scanned by the given tokenizer.
+func tokenText(query string, tokenizer *sqlparser.Tokenizer) string {
+	start, end := tokenizer.OldPosition-1, tokenizer.Position-1
+	if start < 0 {
+		start = 0
+	}
+	if end > len(query) || end < start {
+		end = len(query)
+	}
+	return strings.TrimSpace(query[start:end])
+}
+
+// collapseValueList replaces the list of values at the end of the given tokens with a single token, when the list
+// follows IN, VALUES or another list of values. A list of values that follows another one in a list of rows is
+// dropped, and the rows that were dropped are noted once after the first row.
+func collapseValueList(tokens []string) []string {
+	n := len(tokens)
+	if n == 0 || tokens[n-1] != ")" {
+		return tokens
+	}
+	// Find the opening parenthesis, making sure that only values and commas are in between
+	open := -1
+	for i := n - 2; i >= 0; i-- {
+		if tokens[i] == "(" {
+			open = i
+			break
+		}
+		if tokens[i] != "?" && tokens[i] != "," {
+			return tokens
+		}
+	}
+	if open < 1 || open == n-2 {
+		return tokens
+	}
+	switch tokens[open-1] {
+	case "IN", "VALUES", "VALUE":
+		return append(tokens[:open], valueListText)
+	case ",":
+		if open >= 2 && (tokens[open-2] == valueListText || tokens[open-2] == valueRowsText) {
+			if tokens[open-2] == valueRowsText {
+				return tokens[:open-1]
+			}
+			return append(tokens[:open-1], valueRowsText)
+		}
+	}
+	return tokens
+}
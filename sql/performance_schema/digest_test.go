@@ -0,0 +1,114 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package performance_schema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigest(t *testing.T) {
+	tests := []struct {
+		query      string
+		digestText string
+		name       string
+	}{
+		{
+			query:      "select * from mytable where i = 1",
+			digestText: "SELECT * FROM `mytable` WHERE `i` = ?",
+			name:       "select",
+		},
+		{
+			query:      "SELECT s, CONCAT(s, 'x') FROM `my table` WHERE i <> -2.5 AND s LIKE \"%a\"",
+			digestText: "SELECT `s` , `CONCAT` ( `s` , ? ) FROM `my table` WHERE `i` <> - ? AND `s` LIKE ?",
+			name:       "select",
+		},
+		{
+			query:      "select i from t /* a comment */ where i in (1, 2, 3) -- another comment",
+			digestText: "SELECT `i` FROM `t` WHERE `i` IN (...)",
+			name:       "select",
+		},
+		{
+			query:      "insert into t values (1, 'a'), (2, 'b'), (3, 'c')",
+			digestText: "INSERT INTO `t` VALUES (...) /* , ... */",
+			name:       "insert",
+		},
+		{
+			query:      "insert into t values (1, 'a')",
+			digestText: "INSERT INTO `t` VALUES (...)",
+			name:       "insert",
+		},
+		{
+			query:      "update t set s = ? where i = x'0f'",
+			digestText: "UPDATE `t` SET `s` = ? WHERE `i` = ?",
+			name:       "update",
+		},
+		{
+			query:      "select @@autocommit, @myvar, null",
+			digestText: "SELECT @@autocommit , @myvar , NULL",
+			name:       "select",
+		},
+		{
+			query:      "create table t (i int primary key)",
+			digestText: "CREATE TABLE `t` ( `i` INT PRIMARY KEY )",
+			name:       "create_table",
+		},
+		{
+			query:      "drop database mydb",
+			digestText: "DROP DATABASE `mydb`",
+			name:       "drop_db",
+		},
+		{
+			query:      "show databases",
+			digestText: "SHOW DATABASES",
+			name:       "show_databases",
+		},
+		{
+			query:      "set autocommit = 0",
+			digestText: "SET `autocommit` = ?",
+			name:       "set_option",
+		},
+		{
+			query:      "use mydb;",
+			digestText: "USE `mydb`",
+			name:       "change_db",
+		},
+		{
+			query:      "start transaction",
+			digestText: "START TRANSACTION",
+			name:       "begin",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			digestText, digest := Digest(test.query)
+			assert.Equal(t, test.digestText, digestText)
+			assert.Len(t, digest, 64)
+			assert.Equal(t, test.name, StatementName(digestText))
+		})
+	}
+}
+
+func TestDigestIgnoresLiterals(t *testing.T) {
+	require := require.New(t)
+	_, digest1 := Digest("SELECT * FROM t WHERE i = 1 AND s = 'a'")
+	_, digest2 := Digest("select *   from t where i = 42 and s = 'bcd'")
+	_, digest3 := Digest("SELECT * FROM t WHERE i = 1 OR s = 'a'")
+	require.Equal(digest1, digest2)
+	require.NotEqual(digest1, digest3)
+}
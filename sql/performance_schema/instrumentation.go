@@ -0,0 +1,487 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package performance_schema
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+const (
+	// HistorySize is the number of completed statements that are kept for each thread in the
+	// events_statements_history table.
+	HistorySize = 10
+	// MaxDigests is the maximum number of rows in the events_statements_summary_by_digest table. Statements with a
+	// digest that has not been seen yet are no longer summarized once it is reached.
+	MaxDigests = 10000
+)
+
+// TableOp is a type of write to a table, which is counted in the table_io_waits_summary_by_table table.
+type TableOp byte
+
+const (
+	TableOpInsert TableOp = iota
+	TableOpUpdate
+	TableOpDelete
+)
+
+// Instrumentation collects the thread, statement and table events that are exposed by the performance_schema tables.
+// Connections and statements are reported by the server handler, while table reads and writes are reported by the
+// analyzer as it builds the process tracking of a query.
+type Instrumentation struct {
+	// LockSubsystem holds the user-level locks that are listed in the metadata_locks table.
+	LockSubsystem *sql.LockSubsystem
+	// MetadataLocks holds the table metadata locks that are listed in the metadata_locks table.
+	MetadataLocks *sql.MetadataLockManager
+	// QueryCache holds the query results whose statistics are listed in the global_status table.
+	QueryCache *sql.QueryCache
+
+	mu          sync.Mutex
+	startedAt   time.Time
+	threads     map[uint32]*thread
+	digests     map[digestKey]*digestSummary
+	tables      map[tableKey]*tableIO
+	connections uint64
+	questions   uint64
+	commands    map[string]uint64
+}
+
+// thread is a client connection.
+type thread struct {
+	id          uint32
+	user        string
+	host        string
+	db          string
+	lastEventID uint64
+	lastActive  time.Time
+	current     *StatementEvent
+	history     []*StatementEvent
+}
+
+// StatementEvent is a statement that was executed by a thread. Running statements have a zero EndedAt time.
+type StatementEvent struct {
+	ThreadID     uint32
+	EventID      uint64
+	EventName    string
+	SQLText      string
+	Digest       string
+	DigestText   string
+	Schema       string
+	StartedAt    time.Time
+	EndedAt      time.Time
+	Errno        uint16
+	SQLState     string
+	Message      string
+	Errors       uint64
+	Warnings     uint64
+	RowsAffected uint64
+	RowsSent     uint64
+	RowsExamined uint64
+
+	rowsExamined *uint64
+	writeTable   *tableIO
+	writeOp      TableOp
+}
+
+// StatementResult is the outcome of a statement, which is reported once the statement has finished.
+type StatementResult struct {
+	RowsSent     uint64
+	RowsAffected uint64
+	Err          error
+}
+
+type digestKey struct {
+	schema string
+	digest string
+}
+
+// digestSummary aggregates the statements that share a schema and digest.
+type digestSummary struct {
+	digestKey
+	digestText   string
+	count        uint64
+	sumWait      uint64
+	minWait      uint64
+	maxWait      uint64
+	errors       uint64
+	warnings     uint64
+	rowsAffected uint64
+	rowsSent     uint64
+	rowsExamined uint64
+	firstSeen    time.Time
+	lastSeen     time.Time
+	sampleText   string
+	sampleSeen   time.Time
+	sampleWait   uint64
+}
+
+type tableKey struct {
+	schema string
+	name   string
+}
+
+// tableIO counts the rows that were read from and written to a table. The counters are updated atomically.
+type tableIO struct {
+	tableKey
+	fetch  uint64
+	insert uint64
+	update uint64
+	delete uint64
+}
+
+// NewInstrumentation returns a new, empty Instrumentation.
+func NewInstrumentation() *Instrumentation {
+	return &Instrumentation{
+		startedAt: time.Now(),
+		threads:   make(map[uint32]*thread),
+		digests:   make(map[digestKey]*digestSummary),
+		tables:    make(map[tableKey]*tableIO),
+		commands:  make(map[string]uint64),
+	}
+}
+
+// ThreadConnected records a new client connection.
+func (i *Instrumentation) ThreadConnected(connID uint32) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.connections++
+	i.threadLocked(connID)
+}
+
+// ThreadDisconnected removes the given client connection, along with its statement history.
+func (i *Instrumentation) ThreadDisconnected(connID uint32) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	delete(i.threads, connID)
+}
+
+// threadLocked returns the thread of the given connection, creating it if needed. The lock must be held.
+func (i *Instrumentation) threadLocked(connID uint32) *thread {
+	t, ok := i.threads[connID]
+	if !ok {
+		t = &thread{id: connID, lastActive: time.Now()}
+		i.threads[connID] = t
+	}
+	return t
+}
+
+// StatementStarted records the start of the given statement in the session of the given context, and returns the
+// event that must be passed to StatementFinished once the statement is done.
+func (i *Instrumentation) StatementStarted(ctx *sql.Context, query string) *StatementEvent {
+	digestText, digest := Digest(query)
+	now := time.Now()
+	client := ctx.Session.Client()
+	event := &StatementEvent{
+		ThreadID:     ctx.Session.ID(),
+		EventName:    "statement/sql/" + StatementName(digestText),
+		SQLText:      query,
+		Digest:       digest,
+		DigestText:   digestText,
+		Schema:       ctx.GetCurrentDatabase(),
+		StartedAt:    now,
+		rowsExamined: new(uint64),
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	t := i.threadLocked(event.ThreadID)
+	t.user, t.host, t.db = client.User, client.Address, event.Schema
+	t.lastEventID++
+	t.lastActive = now
+	event.EventID = t.lastEventID
+	t.current = event
+	i.questions++
+	return event
+}
+
+// StatementFinished records the end of the given statement, which moves it to the history of its thread and adds it
+// to the summary of its digest.
+func (i *Instrumentation) StatementFinished(ctx *sql.Context, event *StatementEvent, result StatementResult) {
+	now := time.Now()
+	var warnings uint64
+	if ctx.Session != nil {
+		warnings = uint64(ctx.Session.WarningCount())
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	event.EndedAt = now
+	event.RowsSent = result.RowsSent
+	event.RowsAffected = result.RowsAffected
+	event.RowsExamined = atomic.LoadUint64(event.rowsExamined)
+	event.Warnings = warnings
+	if result.Err != nil {
+		sqlErr := sql.CastSQLError(result.Err)
+		event.Errors = 1
+		event.Errno = uint16(sqlErr.Number())
+		event.SQLState = sqlErr.SQLState()
+		event.Message = sqlErr.Message
+	} else if event.writeTable != nil {
+		switch event.writeOp {
+		case TableOpInsert:
+			atomic.AddUint64(&event.writeTable.insert, result.RowsAffected)
+		case TableOpUpdate:
+			atomic.AddUint64(&event.writeTable.update, result.RowsAffected)
+		case TableOpDelete:
+			atomic.AddUint64(&event.writeTable.delete, result.RowsAffected)
+		}
+	}
+
+	if t, ok := i.threads[event.ThreadID]; ok {
+		t.lastActive = now
+		t.db = ctx.GetCurrentDatabase()
+		t.history = append(t.history, event)
+		if len(t.history) > HistorySize {
+			t.history = t.history[len(t.history)-HistorySize:]
+		}
+	}
+	i.commands[event.EventName[len("statement/sql/"):]]++
+	i.summarizeLocked(event)
+}
+
+// summarizeLocked adds the given finished statement to the summary of its digest. The lock must be held.
+func (i *Instrumentation) summarizeLocked(event *StatementEvent) {
+	key := digestKey{schema: event.Schema, digest: event.Digest}
+	summary, ok := i.digests[key]
+	if !ok {
+		if len(i.digests) >= MaxDigests {
+			return
+		}
+		summary = &digestSummary{
+			digestKey:  key,
+			digestText: event.DigestText,
+			minWait:    ^uint64(0),
+			firstSeen:  event.StartedAt,
+		}
+		i.digests[key] = summary
+	}
+	wait := picoseconds(event.EndedAt.Sub(event.StartedAt))
+	summary.count++
+	summary.sumWait += wait
+	if wait < summary.minWait {
+		summary.minWait = wait
+	}
+	if wait > summary.maxWait {
+		summary.maxWait = wait
+	}
+	summary.errors += event.Errors
+	summary.warnings += event.Warnings
+	summary.rowsAffected += event.RowsAffected
+	summary.rowsSent += event.RowsSent
+	summary.rowsExamined += event.RowsExamined
+	summary.lastSeen = event.StartedAt
+	if summary.sampleText == "" || wait >= summary.sampleWait {
+		summary.sampleText = event.SQLText
+		summary.sampleSeen = event.StartedAt
+		summary.sampleWait = wait
+	}
+}
+
+// TableReadCounter returns a function that counts a row read from the given table, both for the table and for the
+// statement that is running in the session of the given context.
+func (i *Instrumentation) TableReadCounter(ctx *sql.Context, schema, table string) func() {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	io := i.tableLocked(schema, table)
+	var rowsExamined *uint64
+	if t, ok := i.threads[ctx.Session.ID()]; ok && t.current != nil && t.current.EndedAt.IsZero() {
+		rowsExamined = t.current.rowsExamined
+	}
+	return func() {
+		atomic.AddUint64(&io.fetch, 1)
+		if rowsExamined != nil {
+			atomic.AddUint64(rowsExamined, 1)
+		}
+	}
+}
+
+// StatementWrites records that the statement running in the session of the given context writes to the given table.
+// The rows it affects are counted for the table once the statement has finished.
+func (i *Instrumentation) StatementWrites(ctx *sql.Context, schema, table string, op TableOp) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	t, ok := i.threads[ctx.Session.ID()]
+	if !ok || t.current == nil || !t.current.EndedAt.IsZero() {
+		return
+	}
+	t.current.writeTable = i.tableLocked(schema, table)
+	t.current.writeOp = op
+}
+
+// tableLocked returns the counters of the given table, creating them if needed. The lock must be held.
+func (i *Instrumentation) tableLocked(schema, table string) *tableIO {
+	key := tableKey{schema: schema, name: table}
+	io, ok := i.tables[key]
+	if !ok {
+		io = &tableIO{tableKey: key}
+		i.tables[key] = io
+	}
+	return io
+}
+
+// threadSnapshot is a copy of a thread, taken while holding the lock.
+type threadSnapshot struct {
+	id         uint32
+	user       string
+	host       string
+	db         string
+	lastActive time.Time
+}
+
+// threadSnapshots returns a copy of every thread, sorted by id.
+func (i *Instrumentation) threadSnapshots() []threadSnapshot {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	threads := make([]threadSnapshot, 0, len(i.threads))
+	for _, t := range i.threads {
+		threads = append(threads, threadSnapshot{id: t.id, user: t.user, host: t.host, db: t.db, lastActive: t.lastActive})
+	}
+	sort.Slice(threads, func(a, b int) bool {
+		return threads[a].id < threads[b].id
+	})
+	return threads
+}
+
+// currentEvents returns a copy of the most recent statement of every thread, sorted by thread.
+func (i *Instrumentation) currentEvents() []StatementEvent {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	var events []StatementEvent
+	for _, t := range i.threads {
+		if t.current != nil {
+			events = append(events, i.eventSnapshotLocked(t.current))
+		}
+	}
+	sortEvents(events)
+	return events
+}
+
+// historyEvents returns a copy of the recent finished statements of every thread, sorted by thread and event.
+func (i *Instrumentation) historyEvents() []StatementEvent {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	var events []StatementEvent
+	for _, t := range i.threads {
+		for _, event := range t.history {
+			events = append(events, i.eventSnapshotLocked(event))
+		}
+	}
+	sortEvents(events)
+	return events
+}
+
+// eventSnapshotLocked returns a copy of the given event. The lock must be held.
+func (i *Instrumentation) eventSnapshotLocked(event *StatementEvent) StatementEvent {
+	snapshot := *event
+	if snapshot.EndedAt.IsZero() {
+		snapshot.RowsExamined = atomic.LoadUint64(event.rowsExamined)
+	}
+	return snapshot
+}
+
+// sortEvents sorts the given events by thread and event.
+func sortEvents(events []StatementEvent) {
+	sort.Slice(events, func(a, b int) bool {
+		if events[a].ThreadID == events[b].ThreadID {
+			return events[a].EventID < events[b].EventID
+		}
+		return events[a].ThreadID < events[b].ThreadID
+	})
+}
+
+// digestSummaries returns a copy of every digest summary, sorted by schema and digest.
+func (i *Instrumentation) digestSummaries() []digestSummary {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	summaries := make([]digestSummary, 0, len(i.digests))
+	for _, summary := range i.digests {
+		summaries = append(summaries, *summary)
+	}
+	sort.Slice(summaries, func(a, b int) bool {
+		if summaries[a].schema == summaries[b].schema {
+			return summaries[a].digest < summaries[b].digest
+		}
+		return summaries[a].schema < summaries[b].schema
+	})
+	return summaries
+}
+
+// tableSummaries returns a copy of the counters of every table, sorted by schema and name.
+func (i *Instrumentation) tableSummaries() []tableIO {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	tables := make([]tableIO, 0, len(i.tables))
+	for _, io := range i.tables {
+		tables = append(tables, tableIO{
+			tableKey: io.tableKey,
+			fetch:    atomic.LoadUint64(&io.fetch),
+			insert:   atomic.LoadUint64(&io.insert),
+			update:   atomic.LoadUint64(&io.update),
+			delete:   atomic.LoadUint64(&io.delete),
+		})
+	}
+	sort.Slice(tables, func(a, b int) bool {
+		if tables[a].schema == tables[b].schema {
+			return tables[a].name < tables[b].name
+		}
+		return tables[a].schema < tables[b].schema
+	})
+	return tables
+}
+
+// statusSnapshot is a copy of the server status counters, taken while holding the lock.
+type statusSnapshot struct {
+	uptime           time.Duration
+	connections      uint64
+	threadsConnected uint64
+	questions        uint64
+	commands         map[string]uint64
+}
+
+// status returns a copy of the server status counters.
+func (i *Instrumentation) status() statusSnapshot {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	commands := make(map[string]uint64, len(i.commands))
+	for name, count := range i.commands {
+		commands[name] = count
+	}
+	return statusSnapshot{
+		uptime:           time.Since(i.startedAt),
+		connections:      i.connections,
+		threadsConnected: uint64(len(i.threads)),
+		questions:        i.questions,
+		commands:         commands,
+	}
+}
+
+// timerStart returns the given time in picoseconds since the instrumentation was started, which is how the
+// performance_schema tables report the start and end of events.
+func (i *Instrumentation) timerStart(t time.Time) uint64 {
+	return picoseconds(t.Sub(i.startedAt))
+}
+
+// picoseconds returns the given duration in picoseconds, which is the unit of the performance_schema timers.
+func picoseconds(d time.Duration) uint64 {
+	if d < 0 {
+		return 0
+	}
+	return uint64(d.Nanoseconds()) * 1000
+}
@@ -0,0 +1,188 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package performance_schema
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+const (
+	// ThreadsTableName is the name of the THREADS table.
+	ThreadsTableName = "threads"
+	// SessionVariablesTableName is the name of the SESSION_VARIABLES table.
+	SessionVariablesTableName = "session_variables"
+	// GlobalVariablesTableName is the name of the GLOBAL_VARIABLES table.
+	GlobalVariablesTableName = "global_variables"
+	// GlobalStatusTableName is the name of the GLOBAL_STATUS table.
+	GlobalStatusTableName = "global_status"
+	// EventsStatementsCurrentTableName is the name of the EVENTS_STATEMENTS_CURRENT table.
+	EventsStatementsCurrentTableName = "events_statements_current"
+	// EventsStatementsHistoryTableName is the name of the EVENTS_STATEMENTS_HISTORY table.
+	EventsStatementsHistoryTableName = "events_statements_history"
+	// EventsStatementsSummaryByDigestTableName is the name of the EVENTS_STATEMENTS_SUMMARY_BY_DIGEST table.
+	EventsStatementsSummaryByDigestTableName = "events_statements_summary_by_digest"
+	// TableIOWaitsSummaryByTableTableName is the name of the TABLE_IO_WAITS_SUMMARY_BY_TABLE table.
+	TableIOWaitsSummaryByTableTableName = "table_io_waits_summary_by_table"
+	// MetadataLocksTableName is the name of the METADATA_LOCKS table.
+	MetadataLocksTableName = "metadata_locks"
+)
+
+// performanceSchemaDatabase is the PERFORMANCE_SCHEMA database, whose tables are read from an Instrumentation.
+type performanceSchemaDatabase struct {
+	name   string
+	tables map[string]sql.Table
+}
+
+// performanceSchemaTable is a read-only table of the PERFORMANCE_SCHEMA database.
+type performanceSchemaTable struct {
+	name            string
+	schema          sql.Schema
+	instrumentation *Instrumentation
+	reader          func(*sql.Context, *Instrumentation) (sql.RowIter, error)
+}
+
+type performanceSchemaPartition struct {
+	key []byte
+}
+
+type performanceSchemaPartitionIter struct {
+	performanceSchemaPartition
+	pos int
+}
+
+var (
+	_ sql.Database      = (*performanceSchemaDatabase)(nil)
+	_ sql.Table         = (*performanceSchemaTable)(nil)
+	_ sql.Partition     = (*performanceSchemaPartition)(nil)
+	_ sql.PartitionIter = (*performanceSchemaPartitionIter)(nil)
+)
+
+// NewPerformanceSchemaDatabase creates a new PERFORMANCE_SCHEMA Database, which exposes the events collected by the
+// given Instrumentation.
+func NewPerformanceSchemaDatabase(instrumentation *Instrumentation) sql.Database {
+	db := &performanceSchemaDatabase{
+		name:   sql.PerformanceSchemaDatabaseName,
+		tables: make(map[string]sql.Table),
+	}
+	for _, table := range []*performanceSchemaTable{
+		{name: ThreadsTableName, schema: threadsSchema, reader: threadsRowIter},
+		{name: SessionVariablesTableName, schema: variablesSchema(SessionVariablesTableName), reader: sessionVariablesRowIter},
+		{name: GlobalVariablesTableName, schema: variablesSchema(GlobalVariablesTableName), reader: globalVariablesRowIter},
+		{name: GlobalStatusTableName, schema: variablesSchema(GlobalStatusTableName), reader: globalStatusRowIter},
+		{name: EventsStatementsCurrentTableName, schema: statementEventsSchema(EventsStatementsCurrentTableName), reader: eventsStatementsCurrentRowIter},
+		{name: EventsStatementsHistoryTableName, schema: statementEventsSchema(EventsStatementsHistoryTableName), reader: eventsStatementsHistoryRowIter},
+		{name: EventsStatementsSummaryByDigestTableName, schema: eventsStatementsSummaryByDigestSchema, reader: eventsStatementsSummaryByDigestRowIter},
+		{name: TableIOWaitsSummaryByTableTableName, schema: tableIOWaitsSummaryByTableSchema, reader: tableIOWaitsSummaryByTableRowIter},
+		{name: MetadataLocksTableName, schema: metadataLocksSchema, reader: metadataLocksRowIter},
+	} {
+		table.instrumentation = instrumentation
+		db.tables[table.name] = table
+	}
+	return db
+}
+
+// Name implements the sql.Database interface.
+func (db *performanceSchemaDatabase) Name() string { return db.name }
+
+// GetTableInsensitive implements the sql.Database interface.
+func (db *performanceSchemaDatabase) GetTableInsensitive(ctx *sql.Context, tblName string) (sql.Table, bool, error) {
+	tbl, ok := sql.GetTableInsensitive(tblName, db.tables)
+	return tbl, ok, nil
+}
+
+// GetTableNames implements the sql.Database interface.
+func (db *performanceSchemaDatabase) GetTableNames(ctx *sql.Context) ([]string, error) {
+	tblNames := make([]string, 0, len(db.tables))
+	for k := range db.tables {
+		tblNames = append(tblNames, k)
+	}
+	sort.Strings(tblNames)
+	return tblNames, nil
+}
+
+// Name implements the sql.Table interface.
+func (t *performanceSchemaTable) Name() string {
+	return t.name
+}
+
+// Schema implements the sql.Table interface.
+func (t *performanceSchemaTable) Schema() sql.Schema {
+	return t.schema
+}
+
+// Collation implements the sql.Table interface.
+func (t *performanceSchemaTable) Collation() sql.CollationID {
+	return sql.Collation_Information_Schema_Default
+}
+
+// Partitions implements the sql.Table interface.
+func (t *performanceSchemaTable) Partitions(ctx *sql.Context) (sql.PartitionIter, error) {
+	return &performanceSchemaPartitionIter{performanceSchemaPartition: performanceSchemaPartition{partitionKey(t.Name())}}, nil
+}
+
+// PartitionRows implements the sql.PartitionRows interface.
+func (t *performanceSchemaTable) PartitionRows(ctx *sql.Context, partition sql.Partition) (sql.RowIter, error) {
+	if !bytes.Equal(partition.Key(), partitionKey(t.Name())) {
+		return nil, sql.ErrPartitionNotFound.New(partition.Key())
+	}
+	if t.instrumentation == nil {
+		return nil, fmt.Errorf("nil instrumentation for performance schema table %s", t.name)
+	}
+	return t.reader(ctx, t.instrumentation)
+}
+
+// String implements the fmt.Stringer interface.
+func (t *performanceSchemaTable) String() string {
+	p := sql.NewTreePrinter()
+	_ = p.WriteNode("Table(%s)", t.name)
+	var schema = make([]string, len(t.schema))
+	for i, col := range t.schema {
+		schema[i] = fmt.Sprintf(
+			"Column(%s, %s, nullable=%v)",
+			col.Name,
+			col.Type.String(),
+			col.Nullable,
+		)
+	}
+	_ = p.WriteChildren(schema...)
+	return p.String()
+}
+
+// Key implements the sql.Partition interface.
+func (p *performanceSchemaPartition) Key() []byte { return p.key }
+
+// Next implements the sql.PartitionIter interface.
+func (pit *performanceSchemaPartitionIter) Next(ctx *sql.Context) (sql.Partition, error) {
+	if pit.pos == 0 {
+		pit.pos++
+		return pit, nil
+	}
+	return nil, io.EOF
+}
+
+// Close implements the sql.PartitionIter interface.
+func (pit *performanceSchemaPartitionIter) Close(_ *sql.Context) error {
+	pit.pos = 0
+	return nil
+}
+
+func partitionKey(tableName string) []byte {
+	return []byte(sql.PerformanceSchemaDatabaseName + "." + tableName)
+}
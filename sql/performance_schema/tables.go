@@ -0,0 +1,451 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package performance_schema
+
+import (
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dolthub/vitess/go/sqltypes"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// statusCommands are the statements whose "Com_<name>" status variable is always listed in the global_status table,
+// even when no such statement has been run yet.
+var statusCommands = []string{"begin", "commit", "delete", "insert", "replace", "rollback", "select", "update"}
+
+func varchar(length int64) sql.Type {
+	return types.MustCreateString(sqltypes.VarChar, length, sql.Collation_Information_Schema_Default)
+}
+
+var threadsSchema = sql.Schema{
+	{Name: "THREAD_ID", Type: types.Uint64, Default: nil, Nullable: false, Source: ThreadsTableName},
+	{Name: "NAME", Type: varchar(128), Default: nil, Nullable: false, Source: ThreadsTableName},
+	{Name: "TYPE", Type: varchar(10), Default: nil, Nullable: false, Source: ThreadsTableName},
+	{Name: "PROCESSLIST_ID", Type: types.Uint64, Default: nil, Nullable: true, Source: ThreadsTableName},
+	{Name: "PROCESSLIST_USER", Type: varchar(32), Default: nil, Nullable: true, Source: ThreadsTableName},
+	{Name: "PROCESSLIST_HOST", Type: varchar(255), Default: nil, Nullable: true, Source: ThreadsTableName},
+	{Name: "PROCESSLIST_DB", Type: varchar(64), Default: nil, Nullable: true, Source: ThreadsTableName},
+	{Name: "PROCESSLIST_COMMAND", Type: varchar(16), Default: nil, Nullable: true, Source: ThreadsTableName},
+	{Name: "PROCESSLIST_TIME", Type: types.Int64, Default: nil, Nullable: true, Source: ThreadsTableName},
+	{Name: "PROCESSLIST_STATE", Type: varchar(64), Default: nil, Nullable: true, Source: ThreadsTableName},
+	{Name: "PROCESSLIST_INFO", Type: types.LongText, Default: nil, Nullable: true, Source: ThreadsTableName},
+	{Name: "PARENT_THREAD_ID", Type: types.Uint64, Default: nil, Nullable: true, Source: ThreadsTableName},
+	{Name: "ROLE", Type: varchar(64), Default: nil, Nullable: true, Source: ThreadsTableName},
+	{Name: "INSTRUMENTED", Type: varchar(3), Default: nil, Nullable: false, Source: ThreadsTableName},
+	{Name: "HISTORY", Type: varchar(3), Default: nil, Nullable: false, Source: ThreadsTableName},
+	{Name: "CONNECTION_TYPE", Type: varchar(16), Default: nil, Nullable: true, Source: ThreadsTableName},
+	{Name: "THREAD_OS_ID", Type: types.Uint64, Default: nil, Nullable: true, Source: ThreadsTableName},
+	{Name: "RESOURCE_GROUP", Type: varchar(64), Default: nil, Nullable: true, Source: ThreadsTableName},
+}
+
+// variablesSchema returns the schema of the tables that list variables, which all share the same columns.
+func variablesSchema(tableName string) sql.Schema {
+	return sql.Schema{
+		{Name: "VARIABLE_NAME", Type: varchar(64), Default: nil, Nullable: false, Source: tableName},
+		{Name: "VARIABLE_VALUE", Type: varchar(1024), Default: nil, Nullable: true, Source: tableName},
+	}
+}
+
+// statementEventsSchema returns the schema of the tables that list statement events, which all share the same
+// columns.
+func statementEventsSchema(tableName string) sql.Schema {
+	return sql.Schema{
+		{Name: "THREAD_ID", Type: types.Uint64, Default: nil, Nullable: false, Source: tableName},
+		{Name: "EVENT_ID", Type: types.Uint64, Default: nil, Nullable: false, Source: tableName},
+		{Name: "END_EVENT_ID", Type: types.Uint64, Default: nil, Nullable: true, Source: tableName},
+		{Name: "EVENT_NAME", Type: varchar(128), Default: nil, Nullable: false, Source: tableName},
+		{Name: "TIMER_START", Type: types.Uint64, Default: nil, Nullable: true, Source: tableName},
+		{Name: "TIMER_END", Type: types.Uint64, Default: nil, Nullable: true, Source: tableName},
+		{Name: "TIMER_WAIT", Type: types.Uint64, Default: nil, Nullable: true, Source: tableName},
+		{Name: "LOCK_TIME", Type: types.Uint64, Default: nil, Nullable: false, Source: tableName},
+		{Name: "SQL_TEXT", Type: types.LongText, Default: nil, Nullable: true, Source: tableName},
+		{Name: "DIGEST", Type: varchar(64), Default: nil, Nullable: true, Source: tableName},
+		{Name: "DIGEST_TEXT", Type: types.LongText, Default: nil, Nullable: true, Source: tableName},
+		{Name: "CURRENT_SCHEMA", Type: varchar(64), Default: nil, Nullable: true, Source: tableName},
+		{Name: "MYSQL_ERRNO", Type: types.Int32, Default: nil, Nullable: true, Source: tableName},
+		{Name: "RETURNED_SQLSTATE", Type: varchar(5), Default: nil, Nullable: true, Source: tableName},
+		{Name: "MESSAGE_TEXT", Type: varchar(128), Default: nil, Nullable: true, Source: tableName},
+		{Name: "ERRORS", Type: types.Uint64, Default: nil, Nullable: false, Source: tableName},
+		{Name: "WARNINGS", Type: types.Uint64, Default: nil, Nullable: false, Source: tableName},
+		{Name: "ROWS_AFFECTED", Type: types.Uint64, Default: nil, Nullable: false, Source: tableName},
+		{Name: "ROWS_SENT", Type: types.Uint64, Default: nil, Nullable: false, Source: tableName},
+		{Name: "ROWS_EXAMINED", Type: types.Uint64, Default: nil, Nullable: false, Source: tableName},
+	}
+}
+
+var eventsStatementsSummaryByDigestSchema = sql.Schema{
+	{Name: "SCHEMA_NAME", Type: varchar(64), Default: nil, Nullable: true, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "DIGEST", Type: varchar(64), Default: nil, Nullable: true, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "DIGEST_TEXT", Type: types.LongText, Default: nil, Nullable: true, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "COUNT_STAR", Type: types.Uint64, Default: nil, Nullable: false, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "SUM_TIMER_WAIT", Type: types.Uint64, Default: nil, Nullable: false, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "MIN_TIMER_WAIT", Type: types.Uint64, Default: nil, Nullable: false, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "AVG_TIMER_WAIT", Type: types.Uint64, Default: nil, Nullable: false, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "MAX_TIMER_WAIT", Type: types.Uint64, Default: nil, Nullable: false, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "SUM_LOCK_TIME", Type: types.Uint64, Default: nil, Nullable: false, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "SUM_ERRORS", Type: types.Uint64, Default: nil, Nullable: false, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "SUM_WARNINGS", Type: types.Uint64, Default: nil, Nullable: false, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "SUM_ROWS_AFFECTED", Type: types.Uint64, Default: nil, Nullable: false, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "SUM_ROWS_SENT", Type: types.Uint64, Default: nil, Nullable: false, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "SUM_ROWS_EXAMINED", Type: types.Uint64, Default: nil, Nullable: false, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "FIRST_SEEN", Type: types.Timestamp, Default: nil, Nullable: false, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "LAST_SEEN", Type: types.Timestamp, Default: nil, Nullable: false, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "QUERY_SAMPLE_TEXT", Type: types.LongText, Default: nil, Nullable: true, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "QUERY_SAMPLE_SEEN", Type: types.Timestamp, Default: nil, Nullable: false, Source: EventsStatementsSummaryByDigestTableName},
+	{Name: "QUERY_SAMPLE_TIMER_WAIT", Type: types.Uint64, Default: nil, Nullable: false, Source: EventsStatementsSummaryByDigestTableName},
+}
+
+// tableIOWaitsSummaryByTableSchema has a count and four timer columns for every kind of table access. Table accesses
+// are not timed, so the timer columns are always zero.
+var tableIOWaitsSummaryByTableSchema = func() sql.Schema {
+	schema := sql.Schema{
+		{Name: "OBJECT_TYPE", Type: varchar(64), Default: nil, Nullable: true, Source: TableIOWaitsSummaryByTableTableName},
+		{Name: "OBJECT_SCHEMA", Type: varchar(64), Default: nil, Nullable: true, Source: TableIOWaitsSummaryByTableTableName},
+		{Name: "OBJECT_NAME", Type: varchar(64), Default: nil, Nullable: true, Source: TableIOWaitsSummaryByTableTableName},
+	}
+	for _, suffix := range []string{"STAR", "READ", "WRITE", "FETCH", "INSERT", "UPDATE", "DELETE"} {
+		for _, prefix := range []string{"COUNT_", "SUM_TIMER_", "MIN_TIMER_", "AVG_TIMER_", "MAX_TIMER_"} {
+			name := prefix + suffix
+			if suffix == "STAR" && prefix != "COUNT_" {
+				name = prefix + "WAIT"
+			}
+			schema = append(schema, &sql.Column{Name: name, Type: types.Uint64, Default: nil, Nullable: false, Source: TableIOWaitsSummaryByTableTableName})
+		}
+	}
+	return schema
+}()
+
+var metadataLocksSchema = sql.Schema{
+	{Name: "OBJECT_TYPE", Type: varchar(64), Default: nil, Nullable: false, Source: MetadataLocksTableName},
+	{Name: "OBJECT_SCHEMA", Type: varchar(64), Default: nil, Nullable: true, Source: MetadataLocksTableName},
+	{Name: "OBJECT_NAME", Type: varchar(64), Default: nil, Nullable: true, Source: MetadataLocksTableName},
+	{Name: "COLUMN_NAME", Type: varchar(64), Default: nil, Nullable: true, Source: MetadataLocksTableName},
+	{Name: "OBJECT_INSTANCE_BEGIN", Type: types.Uint64, Default: nil, Nullable: false, Source: MetadataLocksTableName},
+	{Name: "LOCK_TYPE", Type: varchar(32), Default: nil, Nullable: false, Source: MetadataLocksTableName},
+	{Name: "LOCK_DURATION", Type: varchar(32), Default: nil, Nullable: false, Source: MetadataLocksTableName},
+	{Name: "LOCK_STATUS", Type: varchar(32), Default: nil, Nullable: false, Source: MetadataLocksTableName},
+	{Name: "SOURCE", Type: varchar(64), Default: nil, Nullable: true, Source: MetadataLocksTableName},
+	{Name: "OWNER_THREAD_ID", Type: types.Uint64, Default: nil, Nullable: true, Source: MetadataLocksTableName},
+	{Name: "OWNER_EVENT_ID", Type: types.Uint64, Default: nil, Nullable: true, Source: MetadataLocksTableName},
+}
+
+// threadsRowIter implements the sql.RowIter for the performance_schema.THREADS table. Every connection is listed,
+// along with the query it is running, if any, according to the process list.
+func threadsRowIter(ctx *sql.Context, instr *Instrumentation) (sql.RowIter, error) {
+	processes := make(map[uint32]sql.Process)
+	if ctx.ProcessList != nil {
+		for _, proc := range ctx.ProcessList.Processes() {
+			processes[proc.Connection] = proc
+		}
+	}
+
+	var rows []sql.Row
+	for _, t := range instr.threadSnapshots() {
+		command, seconds, state, info := "Sleep", int64(time.Since(t.lastActive)/time.Second), interface{}(nil), interface{}(nil)
+		if proc, ok := processes[t.id]; ok {
+			command, seconds, state, info = "Query", int64(proc.Seconds()), "executing", proc.Query
+			delete(processes, t.id)
+		}
+		rows = append(rows, threadRow(t.id, t.user, hostName(t.host), t.db, command, seconds, state, info))
+	}
+	// Sessions that are not served by the server handler only show up in the process list while they run a query
+	for _, proc := range processes {
+		rows = append(rows, threadRow(proc.Connection, proc.User, hostName(proc.Host), "", "Query", int64(proc.Seconds()), "executing", proc.Query))
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i][0].(uint64) < rows[j][0].(uint64)
+	})
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// threadRow returns a row of the THREADS table for a client connection.
+func threadRow(id uint32, user, host, db, command string, seconds int64, state, info interface{}) sql.Row {
+	return sql.Row{
+		uint64(id),                  // thread_id
+		"thread/sql/one_connection", // name
+		"FOREGROUND",                // type
+		uint64(id),                  // processlist_id
+		nullIfEmpty(user),           // processlist_user
+		nullIfEmpty(host),           // processlist_host
+		nullIfEmpty(db),             // processlist_db
+		command,                     // processlist_command
+		seconds,                     // processlist_time
+		state,                       // processlist_state
+		info,                        // processlist_info
+		nil,                         // parent_thread_id
+		nil,                         // role
+		"YES",                       // instrumented
+		"YES",                       // history
+		"TCP/IP",                    // connection_type
+		nil,                         // thread_os_id
+		nil,                         // resource_group
+	}
+}
+
+// sessionVariablesRowIter implements the sql.RowIter for the performance_schema.SESSION_VARIABLES table.
+func sessionVariablesRowIter(ctx *sql.Context, instr *Instrumentation) (sql.RowIter, error) {
+	return variableRows(ctx.GetAllSessionVariables()), nil
+}
+
+// globalVariablesRowIter implements the sql.RowIter for the performance_schema.GLOBAL_VARIABLES table.
+func globalVariablesRowIter(ctx *sql.Context, instr *Instrumentation) (sql.RowIter, error) {
+	return variableRows(sql.SystemVariables.GetAllGlobalVariables()), nil
+}
+
+// variableRows returns the rows for the given system variables, sorted by name.
+func variableRows(vars map[string]interface{}) sql.RowIter {
+	rows := make([]sql.Row, 0, len(vars))
+	for name, val := range vars {
+		rows = append(rows, sql.Row{name, variableValueString(name, val)})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i][0].(string) < rows[j][0].(string)
+	})
+	return sql.RowsToRowIter(rows...)
+}
+
+// variableValueString returns the given value of a system variable as it is displayed, which shows booleans as ON or
+// OFF.
+func variableValueString(name string, val interface{}) interface{} {
+	if val == nil {
+		return nil
+	}
+	if sysVar, _, ok := sql.SystemVariables.GetGlobal(name); ok {
+		if _, ok := sysVar.Type.(types.SystemBoolType_); ok {
+			if b, err := sysVar.Type.Convert(val); err == nil {
+				if b.(int8) != 0 {
+					return "ON"
+				}
+				return "OFF"
+			}
+		}
+	}
+	return fmt.Sprint(val)
+}
+
+// globalStatusRowIter implements the sql.RowIter for the performance_schema.GLOBAL_STATUS table.
+func globalStatusRowIter(ctx *sql.Context, instr *Instrumentation) (sql.RowIter, error) {
+	status := instr.status()
+	var threadsRunning int
+	if ctx.ProcessList != nil {
+		threadsRunning = len(ctx.ProcessList.Processes())
+	}
+
+	vars := map[string]interface{}{
+		"Connections":       status.connections,
+		"Queries":           status.questions,
+		"Questions":         status.questions,
+		"Threads_connected": status.threadsConnected,
+		"Threads_running":   threadsRunning,
+		"Uptime":            uint64(status.uptime / time.Second),
+	}
+	for _, command := range statusCommands {
+		vars["Com_"+command] = uint64(0)
+	}
+	for command, count := range status.commands {
+		vars["Com_"+command] = count
+	}
+	if instr.QueryCache != nil {
+		for name, val := range instr.QueryCache.StatusVariables() {
+			vars[name] = val
+		}
+	}
+
+	rows := make([]sql.Row, 0, len(vars))
+	for name, val := range vars {
+		rows = append(rows, sql.Row{name, fmt.Sprint(val)})
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		return rows[i][0].(string) < rows[j][0].(string)
+	})
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// eventsStatementsCurrentRowIter implements the sql.RowIter for the performance_schema.EVENTS_STATEMENTS_CURRENT
+// table, which has the most recent statement of every connection.
+func eventsStatementsCurrentRowIter(ctx *sql.Context, instr *Instrumentation) (sql.RowIter, error) {
+	return statementEventRows(instr, instr.currentEvents()), nil
+}
+
+// eventsStatementsHistoryRowIter implements the sql.RowIter for the performance_schema.EVENTS_STATEMENTS_HISTORY
+// table, which has the most recent finished statements of every connection.
+func eventsStatementsHistoryRowIter(ctx *sql.Context, instr *Instrumentation) (sql.RowIter, error) {
+	return statementEventRows(instr, instr.historyEvents()), nil
+}
+
+// statementEventRows returns the rows for the given statement events.
+func statementEventRows(instr *Instrumentation, events []StatementEvent) sql.RowIter {
+	rows := make([]sql.Row, len(events))
+	for i, event := range events {
+		var endEventID, timerEnd, errno, sqlState, message interface{}
+		timerWait := picoseconds(time.Since(event.StartedAt))
+		if !event.EndedAt.IsZero() {
+			endEventID = event.EventID
+			timerEnd = instr.timerStart(event.EndedAt)
+			timerWait = picoseconds(event.EndedAt.Sub(event.StartedAt))
+			errno, sqlState = int32(0), "00000"
+			if event.Errors > 0 {
+				errno, sqlState, message = int32(event.Errno), event.SQLState, event.Message
+			}
+		}
+		rows[i] = sql.Row{
+			uint64(event.ThreadID),            // thread_id
+			event.EventID,                     // event_id
+			endEventID,                        // end_event_id
+			event.EventName,                   // event_name
+			instr.timerStart(event.StartedAt), // timer_start
+			timerEnd,                          // timer_end
+			timerWait,                         // timer_wait
+			uint64(0),                         // lock_time
+			event.SQLText,                     // sql_text
+			event.Digest,                      // digest
+			event.DigestText,                  // digest_text
+			nullIfEmpty(event.Schema),         // current_schema
+			errno,                             // mysql_errno
+			sqlState,                          // returned_sqlstate
+			message,                           // message_text
+			event.Errors,                      // errors
+			event.Warnings,                    // warnings
+			event.RowsAffected,                // rows_affected
+			event.RowsSent,                    // rows_sent
+			event.RowsExamined,                // rows_examined
+		}
+	}
+	return sql.RowsToRowIter(rows...)
+}
+
+// eventsStatementsSummaryByDigestRowIter implements the sql.RowIter for the
+// performance_schema.EVENTS_STATEMENTS_SUMMARY_BY_DIGEST table.
+func eventsStatementsSummaryByDigestRowIter(ctx *sql.Context, instr *Instrumentation) (sql.RowIter, error) {
+	summaries := instr.digestSummaries()
+	rows := make([]sql.Row, len(summaries))
+	for i, summary := range summaries {
+		rows[i] = sql.Row{
+			nullIfEmpty(summary.schema),     // schema_name
+			summary.digest,                  // digest
+			summary.digestText,              // digest_text
+			summary.count,                   // count_star
+			summary.sumWait,                 // sum_timer_wait
+			summary.minWait,                 // min_timer_wait
+			summary.sumWait / summary.count, // avg_timer_wait
+			summary.maxWait,                 // max_timer_wait
+			uint64(0),                       // sum_lock_time
+			summary.errors,                  // sum_errors
+			summary.warnings,                // sum_warnings
+			summary.rowsAffected,            // sum_rows_affected
+			summary.rowsSent,                // sum_rows_sent
+			summary.rowsExamined,            // sum_rows_examined
+			summary.firstSeen.UTC(),         // first_seen
+			summary.lastSeen.UTC(),          // last_seen
+			summary.sampleText,              // query_sample_text
+			summary.sampleSeen.UTC(),        // query_sample_seen
+			summary.sampleWait,              // query_sample_timer_wait
+		}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// tableIOWaitsSummaryByTableRowIter implements the sql.RowIter for the
+// performance_schema.TABLE_IO_WAITS_SUMMARY_BY_TABLE table.
+func tableIOWaitsSummaryByTableRowIter(ctx *sql.Context, instr *Instrumentation) (sql.RowIter, error) {
+	tables := instr.tableSummaries()
+	rows := make([]sql.Row, len(tables))
+	for i, io := range tables {
+		write := io.insert + io.update + io.delete
+		row := sql.Row{"TABLE", io.schema, io.name}
+		for _, count := range []uint64{io.fetch + write, io.fetch, write, io.fetch, io.insert, io.update, io.delete} {
+			row = append(row, count, uint64(0), uint64(0), uint64(0), uint64(0))
+		}
+		rows[i] = row
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// metadataLocksRowIter implements the sql.RowIter for the performance_schema.METADATA_LOCKS table.
+func metadataLocksRowIter(ctx *sql.Context, instr *Instrumentation) (sql.RowIter, error) {
+	var rows []sql.Row
+	if instr.MetadataLocks != nil {
+		for _, lock := range instr.MetadataLocks.Locks() {
+			status := "PENDING"
+			if lock.Granted {
+				status = "GRANTED"
+			}
+			rows = append(rows, sql.Row{
+				"TABLE",                // object_type
+				lock.Key.Database,      // object_schema
+				lock.Key.Table,         // object_name
+				nil,                    // column_name
+				uint64(0),              // object_instance_begin
+				lock.Type.String(),     // lock_type
+				lock.Duration.String(), // lock_duration
+				status,                 // lock_status
+				nil,                    // source
+				uint64(lock.Owner),     // owner_thread_id
+				nil,                    // owner_event_id
+			})
+		}
+	}
+	if instr.LockSubsystem == nil {
+		return sql.RowsToRowIter(rows...), nil
+	}
+	for _, lock := range instr.LockSubsystem.HeldLocks() {
+		rows = append(rows, userLockRow(lock.Name, "GRANTED", lock.Owner))
+	}
+	for _, lock := range instr.LockSubsystem.WaitingLocks() {
+		rows = append(rows, userLockRow(lock.Name, "PENDING", lock.Waiter))
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// userLockRow returns the performance_schema.METADATA_LOCKS row of a named lock taken with GET_LOCK.
+func userLockRow(name, status string, owner uint32) sql.Row {
+	return sql.Row{
+		"USER LEVEL LOCK", // object_type
+		nil,               // object_schema
+		name,              // object_name
+		nil,               // column_name
+		uint64(0),         // object_instance_begin
+		"EXCLUSIVE",       // lock_type
+		"EXPLICIT",        // lock_duration
+		status,            // lock_status
+		nil,               // source
+		uint64(owner),     // owner_thread_id
+		nil,               // owner_event_id
+	}
+}
+
+// hostName returns the host of the given client address, without its port.
+func hostName(address string) string {
+	if host, port, err := net.SplitHostPort(address); err == nil {
+		if _, err = strconv.Atoi(port); err == nil {
+			return host
+		}
+	}
+	return address
+}
+
+// nullIfEmpty returns nil for an empty string, and the string otherwise.
+func nullIfEmpty(s string) interface{} {
+	if s == "" {
+		return nil
+	}
+	return s
+}
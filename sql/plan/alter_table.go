@@ -45,6 +45,16 @@ func NewRenameTable(db sql.Database, oldNames, newNames []string) *RenameTable {
 	}
 }
 
+// OldNames returns the names of the tables being renamed.
+func (r *RenameTable) OldNames() []string {
+	return r.oldNames
+}
+
+// NewNames returns the names the tables are renamed to.
+func (r *RenameTable) NewNames() []string {
+	return r.newNames
+}
+
 func (r *RenameTable) WithDatabase(db sql.Database) (sql.Node, error) {
 	nr := *r
 	nr.db = db
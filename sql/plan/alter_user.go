@@ -0,0 +1,177 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// AlterUser represents the statement ALTER USER.
+type AlterUser struct {
+	IfExists        bool
+	Users           []AlteredUser
+	AccountLimits   *AccountLimits
+	PasswordOptions *PasswordOptions
+	// Locked is nil when the statement does not contain ACCOUNT LOCK or ACCOUNT UNLOCK.
+	Locked  *bool
+	MySQLDb sql.Database
+}
+
+// AlteredUser is an account that is changed by ALTER USER.
+type AlteredUser struct {
+	AuthenticatedUser
+	// CurrentUser states that the account is the current user's account, from USER() or CURRENT_USER(), in which case
+	// the UserName is empty.
+	CurrentUser bool
+}
+
+var _ sql.Node = (*AlterUser)(nil)
+var _ sql.Databaser = (*AlterUser)(nil)
+
+// Schema implements the interface sql.Node.
+func (n *AlterUser) Schema() sql.Schema {
+	return types.OkResultSchema
+}
+
+// String implements the interface sql.Node.
+func (n *AlterUser) String() string {
+	users := make([]string, len(n.Users))
+	for i, user := range n.Users {
+		if user.CurrentUser {
+			users[i] = "CURRENT_USER()"
+		} else {
+			users[i] = user.UserName.String("")
+		}
+	}
+	ifExists := ""
+	if n.IfExists {
+		ifExists = "IfExists: "
+	}
+	return fmt.Sprintf("AlterUser(%s%s)", ifExists, strings.Join(users, ", "))
+}
+
+// Database implements the interface sql.Databaser.
+func (n *AlterUser) Database() sql.Database {
+	return n.MySQLDb
+}
+
+// WithDatabase implements the interface sql.Databaser.
+func (n *AlterUser) WithDatabase(db sql.Database) (sql.Node, error) {
+	nn := *n
+	nn.MySQLDb = db
+	return &nn, nil
+}
+
+// Resolved implements the interface sql.Node.
+func (n *AlterUser) Resolved() bool {
+	_, ok := n.MySQLDb.(sql.UnresolvedDatabase)
+	return !ok
+}
+
+// Children implements the interface sql.Node.
+func (n *AlterUser) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the interface sql.Node.
+func (n *AlterUser) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(n, len(children), 0)
+	}
+	return n, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (n *AlterUser) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	// Users may always change their own password
+	if n.OnlyChangesOwnPassword(ctx) {
+		return true
+	}
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation("", "", "", sql.PrivilegeType_CreateUser))
+}
+
+// OnlyChangesOwnPassword returns whether this statement only changes the password of the current user. These are the
+// only statements that a user with an expired password may run.
+func (n *AlterUser) OnlyChangesOwnPassword(ctx *sql.Context) bool {
+	if n.AccountLimits != nil || n.PasswordOptions != nil || n.Locked != nil {
+		return false
+	}
+	client := ctx.Session.Client()
+	for _, user := range n.Users {
+		if user.Auth1 == nil {
+			return false
+		}
+		if !user.CurrentUser && (user.UserName.Name != client.User || user.UserName.Host != client.Address) {
+			return false
+		}
+	}
+	return true
+}
+
+// RowIter implements the interface sql.Node.
+func (n *AlterUser) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	mysqlDb, ok := n.MySQLDb.(*mysql_db.MySQLDb)
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New("mysql")
+	}
+	for _, user := range n.Users {
+		var existingUser *mysql_db.User
+		if user.CurrentUser {
+			client := ctx.Session.Client()
+			existingUser = mysqlDb.GetUser(client.User, client.Address, false)
+		} else {
+			existingUser = mysqlDb.GetUser(user.UserName.Name, user.UserName.Host, false)
+		}
+		if existingUser == nil {
+			if n.IfExists {
+				continue
+			}
+			return nil, sql.ErrUserAlterFailure.New(user.UserName.String("'"))
+		}
+
+		if user.Auth1 != nil {
+			plugin := user.Auth1.Plugin()
+			if plugin != "mysql_native_password" {
+				if err := mysqlDb.VerifyPlugin(plugin); err != nil {
+					return nil, sql.ErrUserAlterFailure.New(err)
+				}
+			}
+			existingUser.Plugin = plugin
+			existingUser.Password = user.Auth1.Password()
+			existingUser.PasswordLastChanged = time.Now().UTC()
+			existingUser.PasswordExpired = false
+		}
+		n.AccountLimits.applyTo(existingUser)
+		n.PasswordOptions.applyTo(existingUser)
+		if n.Locked != nil {
+			existingUser.Locked = *n.Locked
+		}
+		// Unlocking an account, or changing how it's locked, clears any lock from previous failed logins
+		if (n.Locked != nil && !*n.Locked) || n.PasswordOptions.changesPasswordLocking() {
+			mysqlDb.ResetFailedLogins(existingUser.User, existingUser.Host)
+		}
+	}
+	if err := mysqlDb.Persist(ctx); err != nil {
+		return nil, err
+	}
+	return sql.RowsToRowIter(sql.Row{types.NewOkResult(0)}), nil
+}
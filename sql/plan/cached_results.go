@@ -56,8 +56,9 @@ func NewCachedResults(n sql.Node) *CachedResults {
 // to RowIter return an ErrEmptyCachedResult error for short-circuiting
 // join trees.
 //
-// When the memory manager cannot accommodate expanding the cache, we
-// fall back to a passthrough iterator.
+// When the memory manager cannot accommodate expanding the cache, the
+// cached rows are written to a temporary file instead. If that fails
+// too, we fall back to a passthrough iterator.
 type CachedResults struct {
 	UnaryNode
 	id    uint64
@@ -80,8 +81,8 @@ func (n *CachedResults) RowIter(ctx *sql.Context, r sql.Row) (sql.RowIter, error
 		return nil, fmt.Errorf("%w: %T", ErrRowIterDisposed, n)
 	}
 
-	if rows := n.getCachedResults(); rows != nil {
-		return sql.RowsToRowIter(rows...), nil
+	if cache := n.getCachedResults(); cache != nil {
+		return cache.Iter()
 	} else if n.noCache {
 		return n.UnaryNode.Child.RowIter(ctx, r)
 	} else if n.finalized {
@@ -92,7 +93,7 @@ func (n *CachedResults) RowIter(ctx *sql.Context, r sql.Row) (sql.RowIter, error
 	if err != nil {
 		return nil, err
 	}
-	cache, dispose := ctx.Memory.NewRowsCache()
+	cache, dispose := ctx.Memory.NewSpillableRowsCache(sql.SpillDir(ctx))
 	return &cachedResultsIter{n, ci, cache, dispose}, nil
 }
 
@@ -129,14 +130,19 @@ func (n *CachedResults) CheckPrivileges(ctx *sql.Context, opChecker sql.Privileg
 	return n.Child.CheckPrivileges(ctx, opChecker)
 }
 
-func (n *CachedResults) getCachedResults() []sql.Row {
-	return cachedResultsGlobalCache.getCachedResultsById(n.id)
+// getCachedResults returns the cache holding the results, or nil if the results aren't cached or are empty.
+func (n *CachedResults) getCachedResults() sql.SpillableRowsCache {
+	cache := cachedResultsGlobalCache.getCachedResultsById(n.id)
+	if cache == nil || cache.Len() == 0 {
+		return nil
+	}
+	return cache
 }
 
 type cachedResultsIter struct {
 	parent  *CachedResults
 	iter    sql.RowIter
-	cache   sql.RowsCache
+	cache   sql.SpillableRowsCache
 	dispose sql.DisposeFunc
 }
 
@@ -217,7 +223,7 @@ type cachedResultsManager struct {
 
 // cacheDisposeTuple is a container for a cache and the related function to dispose it.
 type cacheDisposeTuple struct {
-	cache   sql.RowsCache
+	cache   sql.SpillableRowsCache
 	dispose sql.DisposeFunc
 }
 
@@ -231,18 +237,18 @@ func (crm *cachedResultsManager) allocateUniqueId() uint64 {
 	return atomic.AddUint64(&(crm.cachedResultsUniqueIdCounter), 1)
 }
 
-func (crm *cachedResultsManager) getCachedResultsById(id uint64) []sql.Row {
+func (crm *cachedResultsManager) getCachedResultsById(id uint64) sql.SpillableRowsCache {
 	crm.mutex.Lock()
 	defer crm.mutex.Unlock()
 
 	if results, ok := crm.cachedResultsCaches[id]; ok {
-		return results.cache.Get()
+		return results.cache
 	} else {
 		return nil
 	}
 }
 
-func (crm *cachedResultsManager) addNewCache(id uint64, cache sql.RowsCache, dispose sql.DisposeFunc) bool {
+func (crm *cachedResultsManager) addNewCache(id uint64, cache sql.SpillableRowsCache, dispose sql.DisposeFunc) bool {
 	crm.mutex.Lock()
 	defer crm.mutex.Unlock()
 
@@ -81,7 +81,7 @@ func (c *Call) WithChildren(children ...sql.Node) (sql.Node, error) {
 // CheckPrivileges implements the interface sql.Node.
 func (c *Call) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
 	return opChecker.UserHasPrivileges(ctx,
-		sql.NewPrivilegedOperation(c.Database().Name(), "", "", sql.PrivilegeType_Execute))
+		sql.NewPrivilegedOperationForRoutine(c.Database().Name(), c.Name, true, sql.PrivilegeType_Execute))
 }
 
 // Expressions implements the sql.Expressioner interface.
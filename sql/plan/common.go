@@ -158,6 +158,9 @@ func getDatabaseName(nodeToSearch sql.Node) string {
 		case sql.Databaser:
 			return n.Database().Name()
 		case *ResolvedTable:
+			if n.Database == nil {
+				return ""
+			}
 			return n.Database.Name()
 		case *UnresolvedTable:
 			return n.Database()
@@ -133,21 +133,37 @@ func (n *CreateUser) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error)
 		}
 		// TODO: attributes should probably not be nil, but setting it to &n.Attribute causes unexpected behavior
 		// TODO: validate all of the data
-		err := userTableData.Put(ctx, &mysql_db.User{
+		newUser := &mysql_db.User{
 			User:                user.UserName.Name,
 			Host:                user.UserName.Host,
 			PrivilegeSet:        mysql_db.NewPrivilegeSet(),
 			Plugin:              plugin,
 			Password:            password,
 			PasswordLastChanged: time.Now().UTC(),
-			Locked:              false,
+			Locked:              n.Locked,
 			Attributes:          nil,
 			IsRole:              false,
 			Identity:            user.Identity,
-		})
+		}
+		n.AccountLimits.applyTo(newUser)
+		n.PasswordOptions.applyTo(newUser)
+		err := userTableData.Put(ctx, newUser)
 		if err != nil {
 			return nil, err
 		}
+		if len(n.DefaultRoles) > 0 {
+			defaultRoles := make([]*mysql_db.User, len(n.DefaultRoles))
+			for i, defaultRole := range n.DefaultRoles {
+				role := mysqlDb.GetUser(defaultRole.Name, defaultRole.Host, true)
+				if role == nil {
+					return nil, sql.ErrGrantRevokeRoleDoesNotExist.New(defaultRole.String("`"))
+				}
+				defaultRoles[i] = role
+			}
+			if err = mysqlDb.SetDefaultRoles(ctx, newUser, defaultRoles); err != nil {
+				return nil, err
+			}
+		}
 	}
 	if err := mysqlDb.Persist(ctx); err != nil {
 		return nil, err
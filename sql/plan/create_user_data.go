@@ -19,6 +19,8 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
 )
 
 // UserName represents either a user or role name.
@@ -70,7 +72,8 @@ type TLSOptions struct {
 	Subject string
 }
 
-// AccountLimits represents the limits imposed upon an account.
+// AccountLimits represents the limits imposed upon an account. A nil limit is left unchanged, while a limit of 0 means
+// that the account is unlimited.
 type AccountLimits struct {
 	MaxQueriesPerHour     *int64
 	MaxUpdatesPerHour     *int64
@@ -78,15 +81,73 @@ type AccountLimits struct {
 	MaxUserConnections    *int64
 }
 
-// PasswordOptions states how to handle a user's passwords.
+// PasswordOptions states how to handle a user's passwords. A nil option is left unchanged.
 type PasswordOptions struct {
 	RequireCurrentOptional bool
+	// Expired states that the password should be expired immediately, from PASSWORD EXPIRE.
+	Expired bool
 
+	// ExpirationTime is the number of days that a password is valid for, where 0 never expires and a negative value
+	// uses the global default_password_lifetime.
 	ExpirationTime *int64
 	History        *int64
 	ReuseInterval  *int64
 	FailedAttempts *int64
-	LockTime       *int64
+	// LockTime is the number of days that an account is locked for after FailedAttempts consecutive failed logins,
+	// where a negative value locks the account until it is unlocked.
+	LockTime *int64
+}
+
+// applyTo sets the limits on the given user.
+func (l *AccountLimits) applyTo(user *mysql_db.User) {
+	if l == nil {
+		return
+	}
+	if l.MaxQueriesPerHour != nil {
+		user.MaxQuestions = uint32(*l.MaxQueriesPerHour)
+	}
+	if l.MaxUpdatesPerHour != nil {
+		user.MaxUpdates = uint32(*l.MaxUpdatesPerHour)
+	}
+	if l.MaxConnectionsPerHour != nil {
+		user.MaxConnections = uint32(*l.MaxConnectionsPerHour)
+	}
+	if l.MaxUserConnections != nil {
+		user.MaxUserConnections = uint32(*l.MaxUserConnections)
+	}
+}
+
+// applyTo sets the password options on the given user.
+func (o *PasswordOptions) applyTo(user *mysql_db.User) {
+	if o == nil {
+		return
+	}
+	if o.Expired {
+		user.PasswordExpired = true
+	}
+	if o.ExpirationTime != nil {
+		if *o.ExpirationTime < 0 {
+			user.PasswordLifetime = nil
+		} else {
+			lifetime := uint16(*o.ExpirationTime)
+			user.PasswordLifetime = &lifetime
+		}
+	}
+	if o.FailedAttempts != nil {
+		user.FailedLoginAttempts = uint32(*o.FailedAttempts)
+	}
+	if o.LockTime != nil {
+		if *o.LockTime < 0 {
+			user.PasswordLockTime = -1
+		} else {
+			user.PasswordLockTime = int32(*o.LockTime)
+		}
+	}
+}
+
+// changesPasswordLocking returns whether these options change how failed logins lock an account.
+func (o *PasswordOptions) changesPasswordLocking() bool {
+	return o != nil && (o.FailedAttempts != nil || o.LockTime != nil)
 }
 
 // AuthenticationMysqlNativePassword is an authentication type that represents "mysql_native_password".
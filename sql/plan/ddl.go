@@ -91,6 +91,8 @@ type TableSpec struct {
 	ChDefs    []*sql.CheckConstraint
 	IdxDefs   []*IndexDefinition
 	Collation sql.CollationID
+	// Partitioning is the PARTITION BY clause of the table, if any.
+	Partitioning *PartitionOptions
 }
 
 func (c *TableSpec) WithSchema(schema sql.PrimaryKeySchema) *TableSpec {
@@ -117,6 +119,12 @@ func (c *TableSpec) WithIndices(idxDefs []*IndexDefinition) *TableSpec {
 	return &nc
 }
 
+func (c *TableSpec) WithPartitioning(partitioning *PartitionOptions) *TableSpec {
+	nc := *c
+	nc.Partitioning = partitioning
+	return &nc
+}
+
 // CreateTable is a node describing the creation of some table.
 type CreateTable struct {
 	ddlNode
@@ -131,6 +139,7 @@ type CreateTable struct {
 	like         sql.Node
 	temporary    TempTableOption
 	selectNode   sql.Node
+	partitioning *PartitionOptions
 }
 
 var _ sql.Databaser = (*CreateTable)(nil)
@@ -154,6 +163,7 @@ func NewCreateTable(db sql.Database, name string, ifn IfNotExistsOption, temp Te
 		collation:    tableSpec.Collation,
 		ifNotExists:  ifn,
 		temporary:    temp,
+		partitioning: tableSpec.Partitioning,
 	}
 }
 
@@ -184,6 +194,7 @@ func NewCreateTableSelect(db sql.Database, name string, selectNode sql.Node, tab
 		selectNode:   selectNode,
 		ifNotExists:  ifn,
 		temporary:    temp,
+		partitioning: tableSpec.Partitioning,
 	}
 }
 
@@ -237,6 +248,10 @@ func (c *CreateTable) Resolved() bool {
 		}
 	}
 
+	if c.partitioning != nil && !c.partitioning.resolved() {
+		return false
+	}
+
 	return true
 }
 
@@ -264,6 +279,14 @@ func (c *CreateTable) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error
 		return sql.RowsToRowIter(), err
 	}
 
+	var partitionScheme *sql.PartitionScheme
+	if c.partitioning != nil {
+		partitionScheme, err = c.partitioning.buildScheme(ctx, c.CreateSchema)
+		if err != nil {
+			return sql.RowsToRowIter(), err
+		}
+	}
+
 	maybePrivDb := c.db
 	if privDb, ok := maybePrivDb.(mysql_db.PrivilegedDatabase); ok {
 		maybePrivDb = privDb.Unwrap()
@@ -365,6 +388,17 @@ func (c *CreateTable) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error
 		}
 	}
 
+	if partitionScheme != nil {
+		partitionable, ok := tableNode.(sql.PartitionAlterableTable)
+		if !ok {
+			return sql.RowsToRowIter(), sql.ErrPartitioningNotSupported.New(c.name)
+		}
+		err = partitionable.SetPartitionScheme(ctx, partitionScheme)
+		if err != nil {
+			return sql.RowsToRowIter(), err
+		}
+	}
+
 	return sql.RowsToRowIter(sql.NewRow(types.NewOkResult(0))), nil
 }
 
@@ -560,6 +594,9 @@ func (c *CreateTable) DebugString() string {
 	if len(c.chDefs) > 0 {
 		children = append(children, c.checkConstraintsDebugString())
 	}
+	if c.partitioning != nil {
+		children = append(children, c.partitioning.String())
+	}
 
 	p.WriteChildren(children...)
 	return p.String()
@@ -620,6 +657,9 @@ func (c *CreateTable) Expressions() []sql.Expression {
 		exprs[i] = ch.Expr
 		i++
 	}
+	if c.partitioning != nil {
+		exprs = append(exprs, c.partitioning.expressions()...)
+	}
 	return exprs
 }
 
@@ -638,6 +678,7 @@ func (c *CreateTable) TableSpec() *TableSpec {
 	ret = ret.WithForeignKeys(c.fkDefs)
 	ret = ret.WithIndices(c.idxDefs)
 	ret = ret.WithCheckConstraints(c.chDefs)
+	ret = ret.WithPartitioning(c.partitioning)
 	ret.Collation = c.collation
 
 	return ret
@@ -655,8 +696,22 @@ func (c *CreateTable) Temporary() TempTableOption {
 	return c.temporary
 }
 
+// Partitioning returns the PARTITION BY clause of the table, if any.
+func (c *CreateTable) Partitioning() *PartitionOptions {
+	return c.partitioning
+}
+
+// WithPartitioning returns a copy of this node with the PARTITION BY clause given.
+func (c CreateTable) WithPartitioning(partitioning *PartitionOptions) *CreateTable {
+	c.partitioning = partitioning
+	return &c
+}
+
 func (c CreateTable) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
 	length := len(c.CreateSchema.Schema) + len(c.chDefs)
+	if c.partitioning != nil {
+		length += len(c.partitioning.expressions())
+	}
 	if len(exprs) != length {
 		return nil, sql.ErrInvalidChildrenNumber.New(c, len(exprs), length)
 	}
@@ -676,12 +731,16 @@ func (c CreateTable) WithExpressions(exprs ...sql.Expression) (sql.Node, error)
 	}
 	nc.CreateSchema = sql.NewPrimaryKeySchema(ns, c.CreateSchema.PkOrdinals...)
 
-	ncd, err := c.chDefs.FromExpressions(exprs[i:])
+	ncd, err := c.chDefs.FromExpressions(exprs[i : i+len(c.chDefs)])
 	if err != nil {
 		return nil, err
 	}
-
 	nc.chDefs = ncd
+	i += len(c.chDefs)
+
+	if c.partitioning != nil {
+		nc.partitioning, _ = c.partitioning.withExpressions(exprs[i:])
+	}
 	return &nc, nil
 }
 
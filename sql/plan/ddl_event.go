@@ -0,0 +1,816 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// eventTimestampLayout is the layout used to write the times of an event's schedule into its stored CREATE EVENT
+// statement.
+const eventTimestampLayout = "2006-01-02 15:04:05"
+
+// EventStatus represents an event's status, which determines whether the event scheduler runs it.
+type EventStatus byte
+
+const (
+	// EventStatus_Enable means the event will be run by the event scheduler.
+	EventStatus_Enable EventStatus = iota
+	// EventStatus_Disable means the event will not be run.
+	EventStatus_Disable
+	// EventStatus_DisableOnSlave means the event was created on a source and replicated, and will not be run on the
+	// replica.
+	EventStatus_DisableOnSlave
+)
+
+// String returns the keyword used for the status in a CREATE EVENT statement.
+func (e EventStatus) String() string {
+	switch e {
+	case EventStatus_Disable:
+		return "DISABLE"
+	case EventStatus_DisableOnSlave:
+		return "DISABLE ON SLAVE"
+	default:
+		return "ENABLE"
+	}
+}
+
+// InformationSchemaString returns the status as it is displayed in information_schema.EVENTS and SHOW EVENTS.
+func (e EventStatus) InformationSchemaString() string {
+	switch e {
+	case EventStatus_Disable:
+		return "DISABLED"
+	case EventStatus_DisableOnSlave:
+		return "SLAVESIDE_DISABLED"
+	default:
+		return "ENABLED"
+	}
+}
+
+// EventOnScheduleEveryInterval is the evaluated EVERY interval of a recurring event, such as `EVERY 1 HOUR` or
+// `EVERY '1:30' HOUR_MINUTE`.
+type EventOnScheduleEveryInterval struct {
+	Value string
+	Unit  string
+}
+
+// String returns the interval as written in a CREATE EVENT statement.
+func (e EventOnScheduleEveryInterval) String() string {
+	if _, err := strconv.ParseInt(e.Value, 10, 64); err == nil {
+		return fmt.Sprintf("%s %s", e.Value, e.Unit)
+	}
+	return fmt.Sprintf("'%s' %s", strings.ReplaceAll(e.Value, "'", "''"), e.Unit)
+}
+
+// delta returns the amount of time added by each repetition of the interval.
+func (e EventOnScheduleEveryInterval) delta(ctx *sql.Context) (*expression.TimeDelta, error) {
+	return expression.NewInterval(expression.NewLiteral(e.Value, types.LongText), e.Unit).EvalDelta(ctx, nil)
+}
+
+// EventDetails are the fully evaluated details of an event, as stored in an sql.EventDatabase. All times are in UTC.
+type EventDetails struct {
+	Name                 string
+	Definer              string
+	OnCompletionPreserve bool
+	Status               EventStatus
+	Comment              string
+	Body                 string
+
+	// HasExecuteAt is true for one-time events, which are executed once at ExecuteAt.
+	HasExecuteAt bool
+	ExecuteAt    time.Time
+	// ExecuteEvery is set for recurring events, which are executed every interval beginning at Starts.
+	ExecuteEvery *EventOnScheduleEveryInterval
+	Starts       time.Time
+	HasEnds      bool
+	Ends         time.Time
+
+	CreatedAt    time.Time
+	LastAltered  time.Time
+	LastExecuted time.Time
+}
+
+// CreateEventStatement returns the canonical CREATE EVENT statement for these details, with the schedule written as
+// literal timestamps so that the statement can be parsed again without changing its meaning.
+func (e EventDetails) CreateEventStatement() string {
+	sb := strings.Builder{}
+	sb.WriteString("CREATE")
+	if e.Definer != "" {
+		sb.WriteString(fmt.Sprintf(" DEFINER = %s", e.Definer))
+	}
+	sb.WriteString(fmt.Sprintf(" EVENT `%s` ON SCHEDULE ", strings.ReplaceAll(e.Name, "`", "``")))
+	if e.HasExecuteAt {
+		sb.WriteString(fmt.Sprintf("AT '%s'", e.ExecuteAt.Format(eventTimestampLayout)))
+	} else {
+		sb.WriteString(fmt.Sprintf("EVERY %s STARTS '%s'", e.ExecuteEvery.String(), e.Starts.Format(eventTimestampLayout)))
+		if e.HasEnds {
+			sb.WriteString(fmt.Sprintf(" ENDS '%s'", e.Ends.Format(eventTimestampLayout)))
+		}
+	}
+	if e.OnCompletionPreserve {
+		sb.WriteString(" ON COMPLETION PRESERVE")
+	} else {
+		sb.WriteString(" ON COMPLETION NOT PRESERVE")
+	}
+	sb.WriteString(" ")
+	sb.WriteString(e.Status.String())
+	if e.Comment != "" {
+		sb.WriteString(fmt.Sprintf(" COMMENT '%s'", strings.ReplaceAll(e.Comment, "'", "''")))
+	}
+	sb.WriteString(" DO ")
+	sb.WriteString(e.Body)
+	return sb.String()
+}
+
+// Definition returns the sql.EventDefinition that stores these details.
+func (e EventDetails) Definition() sql.EventDefinition {
+	return sql.EventDefinition{
+		Name:            e.Name,
+		CreateStatement: e.CreateEventStatement(),
+		CreatedAt:       e.CreatedAt,
+		LastAltered:     e.LastAltered,
+		LastExecuted:    e.LastExecuted,
+	}
+}
+
+// NextExecution returns the first time that the event is scheduled to run after it was last executed. Returns false
+// if the event will never run again, either because a one-time event has already run or because the schedule has
+// passed its ENDS time. The status of the event is not considered.
+func (e EventDetails) NextExecution(ctx *sql.Context) (time.Time, bool, error) {
+	if e.HasExecuteAt {
+		return e.ExecuteAt, e.LastExecuted.IsZero(), nil
+	}
+	delta, err := e.ExecuteEvery.delta(ctx)
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	next := e.Starts
+	if !e.LastExecuted.IsZero() && !e.LastExecuted.Before(e.Starts) {
+		if delta.Years == 0 && delta.Months == 0 {
+			period := time.Duration(delta.Days)*24*time.Hour + time.Duration(delta.Hours)*time.Hour +
+				time.Duration(delta.Minutes)*time.Minute + time.Duration(delta.Seconds)*time.Second +
+				time.Duration(delta.Microseconds)*time.Microsecond
+			if period <= 0 {
+				return time.Time{}, false, sql.ErrEventIntervalNotPositive.New()
+			}
+			next = e.Starts.Add(period * (e.LastExecuted.Sub(e.Starts)/period + 1))
+		} else {
+			// months have varying lengths, so each repetition is computed from the start to avoid drifting
+			for i := int64(1); !next.After(e.LastExecuted); i++ {
+				next = expression.TimeDelta{
+					Years:        delta.Years * i,
+					Months:       delta.Months * i,
+					Days:         delta.Days * i,
+					Hours:        delta.Hours * i,
+					Minutes:      delta.Minutes * i,
+					Seconds:      delta.Seconds * i,
+					Microseconds: delta.Microseconds * i,
+				}.Add(e.Starts)
+			}
+		}
+	}
+	if e.HasEnds && next.After(e.Ends) {
+		return time.Time{}, false, nil
+	}
+	return next, true, nil
+}
+
+// EventType returns the type of the event as displayed in information_schema.EVENTS and SHOW EVENTS.
+func (e EventDetails) EventType() string {
+	if e.HasExecuteAt {
+		return "ONE TIME"
+	}
+	return "RECURRING"
+}
+
+// LoadEventDetails returns the EventDetails of the given stored event, using |parsed| as the result of parsing its
+// CREATE EVENT statement.
+func LoadEventDetails(ctx *sql.Context, definition sql.EventDefinition, parsed sql.Node) (EventDetails, error) {
+	createEvent, ok := parsed.(*CreateEvent)
+	if !ok {
+		return EventDetails{}, sql.ErrEventCreateStatementInvalid.New(definition.CreateStatement)
+	}
+	details, err := createEvent.GetEventDetails(ctx, definition.CreatedAt)
+	if err != nil {
+		return EventDetails{}, err
+	}
+	details.CreatedAt = definition.CreatedAt
+	details.LastAltered = definition.LastAltered
+	details.LastExecuted = definition.LastExecuted
+	return details, nil
+}
+
+// evalEventTime evaluates an expression from an event's schedule, truncating the result to seconds.
+func evalEventTime(ctx *sql.Context, expr sql.Expression) (time.Time, error) {
+	val, err := expr.Eval(ctx, nil)
+	if err != nil {
+		return time.Time{}, err
+	}
+	t, err := types.Datetime.Convert(val)
+	if err != nil {
+		return time.Time{}, err
+	}
+	if t == nil {
+		return time.Time{}, sql.ErrEventEndsBeforeStarts.New()
+	}
+	return t.(time.Time).Truncate(time.Second), nil
+}
+
+// evalEventInterval evaluates the EVERY interval of an event, checking that it is positive.
+func evalEventInterval(ctx *sql.Context, interval *expression.Interval) (*EventOnScheduleEveryInterval, error) {
+	val, err := interval.Child.Eval(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	if val == nil {
+		return nil, sql.ErrEventIntervalNotPositive.New()
+	}
+	strVal, err := types.LongText.Convert(val)
+	if err != nil {
+		return nil, err
+	}
+	every := &EventOnScheduleEveryInterval{Value: strings.TrimSpace(strVal.(string)), Unit: interval.Unit}
+	delta, err := every.delta(ctx)
+	if err != nil {
+		return nil, err
+	}
+	epoch := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+	if !delta.Add(epoch).After(epoch) {
+		return nil, sql.ErrEventIntervalNotPositive.New()
+	}
+	return every, nil
+}
+
+// validateSchedule checks that the ENDS time of a recurring event is after its STARTS time.
+func (e EventDetails) validateSchedule() error {
+	if e.ExecuteEvery != nil && e.HasEnds && !e.Ends.After(e.Starts) {
+		return sql.ErrEventEndsBeforeStarts.New()
+	}
+	return nil
+}
+
+// CreateEvent is a node for the CREATE EVENT statement.
+type CreateEvent struct {
+	ddlNode
+	EventName        string
+	Definer          string
+	At               sql.Expression
+	Every            *expression.Interval
+	Starts           sql.Expression
+	Ends             sql.Expression
+	OnCompPreserve   bool
+	Status           EventStatus
+	Comment          string
+	Body             sql.Node
+	BodyString       string
+	IfNotExists      bool
+	CreateEventQuery string
+}
+
+var _ sql.Node = (*CreateEvent)(nil)
+var _ sql.Databaser = (*CreateEvent)(nil)
+var _ sql.Expressioner = (*CreateEvent)(nil)
+
+// NewCreateEvent returns a *CreateEvent node. Exactly one of |at| and |every| must be set, and |starts| and |ends| are
+// only valid when |every| is set.
+func NewCreateEvent(
+	db sql.Database,
+	name, definer string,
+	at sql.Expression,
+	every *expression.Interval,
+	starts, ends sql.Expression,
+	onCompletionPreserve bool,
+	status EventStatus,
+	comment string,
+	body sql.Node,
+	bodyString string,
+	ifNotExists bool,
+	createEventQuery string,
+) *CreateEvent {
+	return &CreateEvent{
+		ddlNode:          ddlNode{db},
+		EventName:        strings.ToLower(name),
+		Definer:          definer,
+		At:               at,
+		Every:            every,
+		Starts:           starts,
+		Ends:             ends,
+		OnCompPreserve:   onCompletionPreserve,
+		Status:           status,
+		Comment:          comment,
+		Body:             body,
+		BodyString:       bodyString,
+		IfNotExists:      ifNotExists,
+		CreateEventQuery: createEventQuery,
+	}
+}
+
+// Resolved implements the sql.Node interface. The body is not analyzed until the event runs, so it is not considered.
+func (c *CreateEvent) Resolved() bool {
+	if !c.ddlNode.Resolved() {
+		return false
+	}
+	for _, expr := range c.Expressions() {
+		if !expr.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements the sql.Node interface.
+func (c *CreateEvent) String() string {
+	ifNotExists := ""
+	if c.IfNotExists {
+		ifNotExists = "IF NOT EXISTS "
+	}
+	schedule := ""
+	if c.At != nil {
+		schedule = fmt.Sprintf("AT %s", c.At)
+	} else {
+		schedule = fmt.Sprintf("EVERY %s", c.Every)
+		if c.Starts != nil {
+			schedule += fmt.Sprintf(" STARTS %s", c.Starts)
+		}
+		if c.Ends != nil {
+			schedule += fmt.Sprintf(" ENDS %s", c.Ends)
+		}
+	}
+	return fmt.Sprintf("CREATE EVENT %s%s ON SCHEDULE %s DO %s", ifNotExists, c.EventName, schedule, c.BodyString)
+}
+
+// WithChildren implements the sql.Node interface.
+func (c *CreateEvent) WithChildren(children ...sql.Node) (sql.Node, error) {
+	return NillaryWithChildren(c, children...)
+}
+
+// WithDatabase implements the sql.Databaser interface.
+func (c *CreateEvent) WithDatabase(database sql.Database) (sql.Node, error) {
+	nc := *c
+	nc.db = database
+	return &nc, nil
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (c *CreateEvent) Expressions() []sql.Expression {
+	var exprs []sql.Expression
+	if c.At != nil {
+		exprs = append(exprs, c.At)
+	}
+	if c.Every != nil {
+		// the interval itself is only valid in date arithmetic, so only its quantity is exposed
+		exprs = append(exprs, c.Every.Child)
+	}
+	if c.Starts != nil {
+		exprs = append(exprs, c.Starts)
+	}
+	if c.Ends != nil {
+		exprs = append(exprs, c.Ends)
+	}
+	return exprs
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (c *CreateEvent) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
+	if len(exprs) != len(c.Expressions()) {
+		return nil, sql.ErrInvalidChildrenNumber.New(c, len(exprs), len(c.Expressions()))
+	}
+	nc := *c
+	i := 0
+	if nc.At != nil {
+		nc.At = exprs[i]
+		i++
+	}
+	if nc.Every != nil {
+		nc.Every = expression.NewInterval(exprs[i], nc.Every.Unit)
+		i++
+	}
+	if nc.Starts != nil {
+		nc.Starts = exprs[i]
+		i++
+	}
+	if nc.Ends != nil {
+		nc.Ends = exprs[i]
+	}
+	return &nc, nil
+}
+
+// CheckPrivileges implements the sql.Node interface.
+func (c *CreateEvent) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(c.db.Name(), "", "", sql.PrivilegeType_Event))
+}
+
+// GetEventDetails evaluates the schedule of this event, returning its details. |now| is used as the creation time and
+// as the default STARTS time of recurring events.
+func (c *CreateEvent) GetEventDetails(ctx *sql.Context, now time.Time) (EventDetails, error) {
+	details := EventDetails{
+		Name:                 c.EventName,
+		Definer:              c.Definer,
+		OnCompletionPreserve: c.OnCompPreserve,
+		Status:               c.Status,
+		Comment:              c.Comment,
+		Body:                 c.BodyString,
+		CreatedAt:            now,
+		LastAltered:          now,
+	}
+	var err error
+	if c.At != nil {
+		details.HasExecuteAt = true
+		details.ExecuteAt, err = evalEventTime(ctx, c.At)
+		if err != nil {
+			return EventDetails{}, err
+		}
+		return details, nil
+	}
+
+	details.ExecuteEvery, err = evalEventInterval(ctx, c.Every)
+	if err != nil {
+		return EventDetails{}, err
+	}
+	details.Starts = now.UTC().Truncate(time.Second)
+	if c.Starts != nil {
+		details.Starts, err = evalEventTime(ctx, c.Starts)
+		if err != nil {
+			return EventDetails{}, err
+		}
+	}
+	if c.Ends != nil {
+		details.HasEnds = true
+		details.Ends, err = evalEventTime(ctx, c.Ends)
+		if err != nil {
+			return EventDetails{}, err
+		}
+	}
+	return details, details.validateSchedule()
+}
+
+// RowIter implements the sql.Node interface.
+func (c *CreateEvent) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	eventDb, ok := c.db.(sql.EventDatabase)
+	if !ok {
+		return nil, sql.ErrEventsNotSupported.New(c.db.Name())
+	}
+
+	if _, exists, err := eventDb.GetEvent(ctx, c.EventName); err != nil {
+		return nil, err
+	} else if exists {
+		if c.IfNotExists {
+			ctx.Warn(1537, sql.ErrEventAlreadyExists.New(c.EventName).Error())
+			return sql.RowsToRowIter(sql.Row{types.NewOkResult(0)}), nil
+		}
+		return nil, sql.ErrEventAlreadyExists.New(c.EventName)
+	}
+
+	now := ctx.QueryTime().UTC()
+	details, err := c.GetEventDetails(ctx, now)
+	if err != nil {
+		return nil, err
+	}
+	if details.Definer == "" {
+		details.Definer = currentUserDefiner(ctx)
+	}
+
+	if eventScheduleHasPassed(details, now) {
+		if !details.OnCompletionPreserve {
+			ctx.Warn(1588, "Event execution time is in the past and ON COMPLETION NOT PRESERVE is set. The event was dropped immediately after creation.")
+			return sql.RowsToRowIter(sql.Row{types.NewOkResult(0)}), nil
+		}
+		ctx.Warn(1544, "Event execution time is in the past. Event has been disabled")
+		details.Status = EventStatus_Disable
+	}
+
+	if err = eventDb.SaveEvent(ctx, details.Definition()); err != nil {
+		return nil, err
+	}
+	return sql.RowsToRowIter(sql.Row{types.NewOkResult(0)}), nil
+}
+
+// eventScheduleHasPassed returns whether the given event will never execute because its schedule is entirely in the
+// past.
+func eventScheduleHasPassed(details EventDetails, now time.Time) bool {
+	if details.HasExecuteAt {
+		return details.ExecuteAt.Before(now)
+	}
+	return details.HasEnds && details.Ends.Before(now)
+}
+
+// currentUserDefiner returns the current client formatted as an event definer.
+func currentUserDefiner(ctx *sql.Context) string {
+	client := ctx.Session.Client()
+	return fmt.Sprintf("`%s`@`%s`", client.User, client.Address)
+}
+
+// AlterEvent is a node for the ALTER EVENT statement. Only the parts of the event that are set by the statement are
+// changed.
+type AlterEvent struct {
+	ddlNode
+	EventName string
+	Definer   string
+
+	AlterSchedule bool
+	At            sql.Expression
+	Every         *expression.Interval
+	Starts        sql.Expression
+	Ends          sql.Expression
+
+	AlterOnComp    bool
+	OnCompPreserve bool
+
+	AlterName    bool
+	RenameToDb   string
+	RenameToName string
+
+	AlterStatus bool
+	Status      EventStatus
+
+	AlterComment bool
+	Comment      string
+
+	AlterDefinition bool
+	Body            sql.Node
+	BodyString      string
+
+	// Event is the current definition of the event being altered, which is loaded during analysis.
+	Event EventDetails
+}
+
+var _ sql.Node = (*AlterEvent)(nil)
+var _ sql.Databaser = (*AlterEvent)(nil)
+var _ sql.Expressioner = (*AlterEvent)(nil)
+
+// NewAlterEvent returns a *AlterEvent node for the event given. The parts of the event to change are set on the
+// returned node.
+func NewAlterEvent(db sql.Database, name, definer string) *AlterEvent {
+	return &AlterEvent{
+		ddlNode:   ddlNode{db},
+		EventName: strings.ToLower(name),
+		Definer:   definer,
+	}
+}
+
+// Resolved implements the sql.Node interface.
+func (a *AlterEvent) Resolved() bool {
+	if !a.ddlNode.Resolved() {
+		return false
+	}
+	for _, expr := range a.Expressions() {
+		if !expr.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// String implements the sql.Node interface.
+func (a *AlterEvent) String() string {
+	var changes []string
+	if a.AlterSchedule {
+		if a.At != nil {
+			changes = append(changes, fmt.Sprintf("ON SCHEDULE AT %s", a.At))
+		} else {
+			changes = append(changes, fmt.Sprintf("ON SCHEDULE EVERY %s", a.Every))
+		}
+	}
+	if a.AlterOnComp {
+		changes = append(changes, fmt.Sprintf("ON COMPLETION PRESERVE %t", a.OnCompPreserve))
+	}
+	if a.AlterName {
+		changes = append(changes, fmt.Sprintf("RENAME TO %s", a.RenameToName))
+	}
+	if a.AlterStatus {
+		changes = append(changes, a.Status.String())
+	}
+	if a.AlterComment {
+		changes = append(changes, fmt.Sprintf("COMMENT '%s'", a.Comment))
+	}
+	if a.AlterDefinition {
+		changes = append(changes, fmt.Sprintf("DO %s", a.BodyString))
+	}
+	return fmt.Sprintf("ALTER EVENT %s %s", a.EventName, strings.Join(changes, " "))
+}
+
+// WithChildren implements the sql.Node interface.
+func (a *AlterEvent) WithChildren(children ...sql.Node) (sql.Node, error) {
+	return NillaryWithChildren(a, children...)
+}
+
+// WithDatabase implements the sql.Databaser interface.
+func (a *AlterEvent) WithDatabase(database sql.Database) (sql.Node, error) {
+	na := *a
+	na.db = database
+	return &na, nil
+}
+
+// WithEvent returns a copy of this node with the current definition of the event set.
+func (a *AlterEvent) WithEvent(event EventDetails) *AlterEvent {
+	na := *a
+	na.Event = event
+	return &na
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (a *AlterEvent) Expressions() []sql.Expression {
+	var exprs []sql.Expression
+	if a.At != nil {
+		exprs = append(exprs, a.At)
+	}
+	if a.Every != nil {
+		// the interval itself is only valid in date arithmetic, so only its quantity is exposed
+		exprs = append(exprs, a.Every.Child)
+	}
+	if a.Starts != nil {
+		exprs = append(exprs, a.Starts)
+	}
+	if a.Ends != nil {
+		exprs = append(exprs, a.Ends)
+	}
+	return exprs
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (a *AlterEvent) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
+	if len(exprs) != len(a.Expressions()) {
+		return nil, sql.ErrInvalidChildrenNumber.New(a, len(exprs), len(a.Expressions()))
+	}
+	na := *a
+	i := 0
+	if na.At != nil {
+		na.At = exprs[i]
+		i++
+	}
+	if na.Every != nil {
+		na.Every = expression.NewInterval(exprs[i], na.Every.Unit)
+		i++
+	}
+	if na.Starts != nil {
+		na.Starts = exprs[i]
+		i++
+	}
+	if na.Ends != nil {
+		na.Ends = exprs[i]
+	}
+	return &na, nil
+}
+
+// CheckPrivileges implements the sql.Node interface.
+func (a *AlterEvent) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	hasPriv := opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(a.db.Name(), "", "", sql.PrivilegeType_Event))
+	if a.AlterName && a.RenameToDb != "" {
+		hasPriv = hasPriv && opChecker.UserHasPrivileges(ctx,
+			sql.NewPrivilegedOperation(a.RenameToDb, "", "", sql.PrivilegeType_Event))
+	}
+	return hasPriv
+}
+
+// RowIter implements the sql.Node interface.
+func (a *AlterEvent) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	eventDb, ok := a.db.(sql.EventDatabase)
+	if !ok {
+		return nil, sql.ErrEventsNotSupported.New(a.db.Name())
+	}
+
+	now := ctx.QueryTime().UTC()
+	details := a.Event
+	if a.Definer != "" {
+		details.Definer = a.Definer
+	}
+	if a.AlterSchedule {
+		schedule, err := (&CreateEvent{At: a.At, Every: a.Every, Starts: a.Starts, Ends: a.Ends}).GetEventDetails(ctx, now)
+		if err != nil {
+			return nil, err
+		}
+		details.HasExecuteAt = schedule.HasExecuteAt
+		details.ExecuteAt = schedule.ExecuteAt
+		details.ExecuteEvery = schedule.ExecuteEvery
+		details.Starts = schedule.Starts
+		details.HasEnds = schedule.HasEnds
+		details.Ends = schedule.Ends
+		// a new schedule begins again, even if the event has been executed before
+		details.LastExecuted = time.Time{}
+	}
+	if a.AlterOnComp {
+		details.OnCompletionPreserve = a.OnCompPreserve
+	}
+	if a.AlterName {
+		if a.RenameToDb != "" && !strings.EqualFold(a.RenameToDb, a.db.Name()) {
+			return nil, sql.ErrUnsupportedFeature.New("moving events to another database")
+		}
+		details.Name = strings.ToLower(a.RenameToName)
+		if details.Name != a.EventName {
+			if _, exists, err := eventDb.GetEvent(ctx, details.Name); err != nil {
+				return nil, err
+			} else if exists {
+				return nil, sql.ErrEventAlreadyExists.New(details.Name)
+			}
+		}
+	}
+	if a.AlterStatus {
+		details.Status = a.Status
+	}
+	if a.AlterComment {
+		details.Comment = a.Comment
+	}
+	if a.AlterDefinition {
+		details.Body = a.BodyString
+	}
+	details.LastAltered = now
+
+	if (a.AlterSchedule || a.AlterOnComp) && eventScheduleHasPassed(details, now) {
+		if !details.OnCompletionPreserve {
+			return nil, sql.ErrEventCannotAlterInThePast.New()
+		}
+		ctx.Warn(1544, "Event execution time is in the past. Event has been disabled")
+		details.Status = EventStatus_Disable
+	}
+
+	if err := eventDb.UpdateEvent(ctx, a.EventName, details.Definition()); err != nil {
+		return nil, err
+	}
+	return sql.RowsToRowIter(sql.Row{types.NewOkResult(0)}), nil
+}
+
+// DropEvent is a node for the DROP EVENT statement.
+type DropEvent struct {
+	ddlNode
+	EventName string
+	IfExists  bool
+}
+
+var _ sql.Node = (*DropEvent)(nil)
+var _ sql.Databaser = (*DropEvent)(nil)
+
+// NewDropEvent returns a *DropEvent node.
+func NewDropEvent(db sql.Database, name string, ifExists bool) *DropEvent {
+	return &DropEvent{
+		ddlNode:   ddlNode{db},
+		EventName: strings.ToLower(name),
+		IfExists:  ifExists,
+	}
+}
+
+// String implements the sql.Node interface.
+func (d *DropEvent) String() string {
+	ifExists := ""
+	if d.IfExists {
+		ifExists = "IF EXISTS "
+	}
+	return fmt.Sprintf("DROP EVENT %s%s", ifExists, d.EventName)
+}
+
+// WithChildren implements the sql.Node interface.
+func (d *DropEvent) WithChildren(children ...sql.Node) (sql.Node, error) {
+	return NillaryWithChildren(d, children...)
+}
+
+// WithDatabase implements the sql.Databaser interface.
+func (d *DropEvent) WithDatabase(database sql.Database) (sql.Node, error) {
+	nd := *d
+	nd.db = database
+	return &nd, nil
+}
+
+// CheckPrivileges implements the sql.Node interface.
+func (d *DropEvent) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(d.db.Name(), "", "", sql.PrivilegeType_Event))
+}
+
+// RowIter implements the sql.Node interface.
+func (d *DropEvent) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	eventDb, ok := d.db.(sql.EventDatabase)
+	if !ok {
+		if d.IfExists {
+			return sql.RowsToRowIter(sql.Row{types.NewOkResult(0)}), nil
+		}
+		return nil, sql.ErrEventDoesNotExist.New(d.EventName)
+	}
+	err := eventDb.DropEvent(ctx, d.EventName)
+	if d.IfExists && sql.ErrEventDoesNotExist.Is(err) {
+		ctx.Warn(1305, "Event %s does not exist", d.EventName)
+		return sql.RowsToRowIter(sql.Row{types.NewOkResult(0)}), nil
+	} else if err != nil {
+		return nil, err
+	}
+	return sql.RowsToRowIter(sql.Row{types.NewOkResult(0)}), nil
+}
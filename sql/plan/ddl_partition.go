@@ -0,0 +1,653 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// PartitionOptions is the PARTITION BY clause of a CREATE TABLE or ALTER TABLE statement.
+type PartitionOptions struct {
+	// Method is the partitioning method.
+	Method sql.PartitionMethod
+	// Expression is the partitioning expression of RANGE, LIST and HASH partitioning.
+	Expression sql.Expression
+	// ExpressionString is the original text of Expression.
+	ExpressionString string
+	// Columns are the partitioning columns of RANGE COLUMNS, LIST COLUMNS and KEY partitioning. KEY partitioning
+	// without any columns uses the primary key.
+	Columns []string
+	// NumPartitions is the number of partitions given by the PARTITIONS clause, or zero if there was none.
+	NumPartitions int
+	// Definitions are the partitions declared, if any.
+	Definitions []*PartitionDefinitionSpec
+}
+
+// PartitionDefinitionSpec is a single partition of a PARTITION BY clause, or of an ALTER TABLE statement that adds or
+// reorganizes partitions.
+type PartitionDefinitionSpec struct {
+	// Name is the name of the partition.
+	Name string
+	// LessThan are the expressions of the VALUES LESS THAN clause, or nil if there was none. A nil element is MAXVALUE.
+	LessThan []sql.Expression
+	// In are the expressions of the VALUES IN clause, or nil if there was none.
+	In [][]sql.Expression
+	// Comment is the partition's comment, if any.
+	Comment string
+}
+
+// partitionDefinitionExpressions returns the expressions of the partition definitions given, skipping MAXVALUE.
+func partitionDefinitionExpressions(defs []*PartitionDefinitionSpec) []sql.Expression {
+	var exprs []sql.Expression
+	for _, def := range defs {
+		for _, expr := range def.LessThan {
+			if expr != nil {
+				exprs = append(exprs, expr)
+			}
+		}
+		for _, in := range def.In {
+			exprs = append(exprs, in...)
+		}
+	}
+	return exprs
+}
+
+// partitionDefinitionsWithExpressions returns a copy of the partition definitions given with the expressions given,
+// in the order returned by partitionDefinitionExpressions, along with the number of expressions used.
+func partitionDefinitionsWithExpressions(defs []*PartitionDefinitionSpec, exprs []sql.Expression) ([]*PartitionDefinitionSpec, int) {
+	if defs == nil {
+		return nil, 0
+	}
+	i := 0
+	newDefs := make([]*PartitionDefinitionSpec, len(defs))
+	for j, def := range defs {
+		nd := *def
+		if def.LessThan != nil {
+			nd.LessThan = make([]sql.Expression, len(def.LessThan))
+			for k, expr := range def.LessThan {
+				if expr != nil {
+					nd.LessThan[k] = exprs[i]
+					i++
+				}
+			}
+		}
+		if def.In != nil {
+			nd.In = make([][]sql.Expression, len(def.In))
+			for k, in := range def.In {
+				nd.In[k] = exprs[i : i+len(in)]
+				i += len(in)
+			}
+		}
+		newDefs[j] = &nd
+	}
+	return newDefs, i
+}
+
+// expressions returns the partitioning expression, if any, followed by the values of the partition definitions.
+func (p *PartitionOptions) expressions() []sql.Expression {
+	var exprs []sql.Expression
+	if p.Expression != nil {
+		exprs = append(exprs, p.Expression)
+	}
+	return append(exprs, partitionDefinitionExpressions(p.Definitions)...)
+}
+
+// withExpressions returns a copy of these options with the expressions given, in the order returned by expressions,
+// along with the number of expressions used.
+func (p *PartitionOptions) withExpressions(exprs []sql.Expression) (*PartitionOptions, int) {
+	np := *p
+	i := 0
+	if p.Expression != nil {
+		np.Expression = exprs[0]
+		i++
+	}
+	var n int
+	np.Definitions, n = partitionDefinitionsWithExpressions(p.Definitions, exprs[i:])
+	return &np, i + n
+}
+
+// resolved returns whether all of the expressions of these options are resolved.
+func (p *PartitionOptions) resolved() bool {
+	for _, expr := range p.expressions() {
+		if !expr.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *PartitionOptions) String() string {
+	if p.Method.UsesColumns() {
+		return fmt.Sprintf("PARTITION BY %s (%s)", p.Method, strings.Join(p.Columns, ", "))
+	}
+	return fmt.Sprintf("PARTITION BY %s (%s)", p.Method, p.ExpressionString)
+}
+
+// buildScheme evaluates and validates these options, returning the partitioning of a table with the schema given.
+func (p *PartitionOptions) buildScheme(ctx *sql.Context, sch sql.PrimaryKeySchema) (*sql.PartitionScheme, error) {
+	scheme := &sql.PartitionScheme{
+		Method:           p.Method,
+		Expression:       p.Expression,
+		ExpressionString: p.ExpressionString,
+	}
+
+	if p.Method.UsesColumns() {
+		columns := p.Columns
+		if len(columns) == 0 {
+			for _, ord := range sch.PkOrdinals {
+				columns = append(columns, sch.Schema[ord].Name)
+			}
+			if len(columns) == 0 {
+				return nil, sql.ErrPartitionFieldNotFound.New()
+			}
+		}
+		for _, col := range columns {
+			idx := sch.Schema.IndexOfColName(col)
+			if idx < 0 {
+				return nil, sql.ErrPartitionFieldNotFound.New()
+			}
+			scheme.Columns = append(scheme.Columns, sch.Schema[idx].Name)
+		}
+	} else if t := p.Expression.Type(); !types.IsInteger(t) && !types.IsDecimal(t) {
+		// integer arithmetic such as a % 3 has a decimal type, so those values are checked when they're evaluated
+		return nil, sql.ErrWrongPartitionFunctionType.New()
+	}
+
+	if p.NumPartitions > 0 && len(p.Definitions) > 0 && p.NumPartitions != len(p.Definitions) {
+		return nil, sql.ErrWrongPartitionCount.New()
+	}
+
+	if len(p.Definitions) == 0 {
+		switch {
+		case p.Method.IsRange():
+			return nil, sql.ErrPartitionsMustBeDefined.New("RANGE")
+		case p.Method.IsList():
+			return nil, sql.ErrPartitionsMustBeDefined.New("LIST")
+		}
+		num := p.NumPartitions
+		if num == 0 {
+			num = 1
+		}
+		scheme.Definitions = hashPartitionDefinitions(0, num)
+	} else {
+		defs, err := buildPartitionDefinitions(ctx, scheme, sch.Schema, p.Definitions)
+		if err != nil {
+			return nil, err
+		}
+		scheme.Definitions = defs
+	}
+
+	if len(sch.PkOrdinals) > 0 {
+		for _, col := range scheme.ColumnNames() {
+			idx := sch.Schema.IndexOfColName(col)
+			if idx < 0 || !sch.Schema[idx].PrimaryKey {
+				return nil, sql.ErrPrimaryKeyNeedsAllPartitionColumns.New()
+			}
+		}
+	}
+
+	if err := scheme.Validate(sch.Schema); err != nil {
+		return nil, err
+	}
+	return scheme, nil
+}
+
+// hashPartitionDefinitions returns |count| HASH or KEY partitions with the default names, starting with the partition
+// at the index given.
+func hashPartitionDefinitions(start, count int) []sql.PartitionDefinition {
+	defs := make([]sql.PartitionDefinition, count)
+	for i := range defs {
+		defs[i] = sql.PartitionDefinition{Name: fmt.Sprintf("p%d", start+i)}
+	}
+	return defs
+}
+
+// buildPartitionDefinitions evaluates the partition definitions given, which must match the method of the partitioning
+// given. |sch| is the schema of the table.
+func buildPartitionDefinitions(ctx *sql.Context, scheme *sql.PartitionScheme, sch sql.Schema, specs []*PartitionDefinitionSpec) ([]sql.PartitionDefinition, error) {
+	var colTypes []sql.Type
+	numValues := 1
+	if scheme.Method.UsesColumns() {
+		for _, col := range scheme.Columns {
+			colTypes = append(colTypes, sch[sch.IndexOfColName(col)].Type)
+		}
+		numValues = len(colTypes)
+	}
+
+	defs := make([]sql.PartitionDefinition, len(specs))
+	for i, spec := range specs {
+		switch {
+		case spec.LessThan != nil && !scheme.Method.IsRange():
+			return nil, sql.ErrWrongPartitionValuesType.New("RANGE", "LESS THAN")
+		case spec.In != nil && !scheme.Method.IsList():
+			return nil, sql.ErrWrongPartitionValuesType.New("LIST", "IN")
+		case spec.LessThan == nil && scheme.Method.IsRange():
+			return nil, sql.ErrPartitionRequiresValues.New("RANGE", "LESS THAN")
+		case spec.In == nil && scheme.Method.IsList():
+			return nil, sql.ErrPartitionRequiresValues.New("LIST", "IN")
+		}
+
+		def := sql.PartitionDefinition{Name: spec.Name, Comment: spec.Comment}
+		if spec.LessThan != nil {
+			vals, err := evalPartitionValues(ctx, spec.Name, colTypes, numValues, spec.LessThan, false)
+			if err != nil {
+				return nil, err
+			}
+			def.LessThan = vals
+		}
+		for _, in := range spec.In {
+			vals, err := evalPartitionValues(ctx, spec.Name, colTypes, numValues, in, true)
+			if err != nil {
+				return nil, err
+			}
+			def.In = append(def.In, vals)
+		}
+		defs[i] = def
+	}
+	return defs, nil
+}
+
+// evalPartitionValues evaluates the values of a partition definition, converting them to the types of the partitioning
+// columns, or to int64 values when partitioning by an expression. Nil expressions are MAXVALUE.
+func evalPartitionValues(ctx *sql.Context, name string, colTypes []sql.Type, numValues int, exprs []sql.Expression, allowNull bool) ([]interface{}, error) {
+	if len(exprs) != numValues {
+		return nil, sql.ErrPartitionColumnListMismatch.New()
+	}
+	vals := make([]interface{}, len(exprs))
+	for i, expr := range exprs {
+		if expr == nil {
+			vals[i] = sql.PartitionMaxValue
+			continue
+		}
+		val, err := expr.Eval(ctx, nil)
+		if err != nil {
+			return nil, err
+		}
+		if val == nil {
+			if !allowNull {
+				return nil, sql.ErrNullInValuesLessThan.New()
+			}
+			continue
+		}
+		if colTypes == nil {
+			if !types.IsInteger(expr.Type()) {
+				return nil, sql.ErrPartitionValueNotInt.New(name)
+			}
+			val, err = types.Int64.Convert(val)
+		} else {
+			val, err = colTypes[i].Convert(val)
+		}
+		if err != nil {
+			return nil, err
+		}
+		vals[i] = val
+	}
+	return vals, nil
+}
+
+// partitionSchemeString returns the PARTITION BY clause of the partitioning given, as displayed by SHOW CREATE TABLE.
+func partitionSchemeString(ctx *sql.Context, scheme *sql.PartitionScheme, sch sql.Schema) (string, error) {
+	var sb strings.Builder
+	switch scheme.Method {
+	case sql.PartitionMethod_RangeColumns, sql.PartitionMethod_ListColumns:
+		method := strings.TrimSuffix(string(scheme.Method), " COLUMNS")
+		sb.WriteString(fmt.Sprintf("/*!50500 PARTITION BY %s  COLUMNS(%s)", method, strings.Join(quoteIdentifiers(scheme.Columns), ",")))
+	case sql.PartitionMethod_Key, sql.PartitionMethod_LinearKey:
+		sb.WriteString(fmt.Sprintf("/*!50100 PARTITION BY %s (%s)", scheme.Method, strings.Join(quoteIdentifiers(scheme.Columns), ",")))
+	default:
+		sb.WriteString(fmt.Sprintf("/*!50100 PARTITION BY %s (%s)", scheme.Method, scheme.ExpressionString))
+	}
+
+	if scheme.Method.IsHash() && hasDefaultHashPartitions(scheme) {
+		sb.WriteString(fmt.Sprintf("\nPARTITIONS %d */", len(scheme.Definitions)))
+		return sb.String(), nil
+	}
+
+	sb.WriteString("\n(")
+	for i, def := range scheme.Definitions {
+		if i > 0 {
+			sb.WriteString(",\n ")
+		}
+		sb.WriteString("PARTITION ")
+		sb.WriteString(def.Name)
+		desc, err := scheme.ValuesDescription(ctx, sch, i)
+		if err != nil {
+			return "", err
+		}
+		switch {
+		case scheme.Method == sql.PartitionMethod_Range && def.LessThan[0] == sql.PartitionMaxValue:
+			sb.WriteString(" VALUES LESS THAN MAXVALUE")
+		case scheme.Method.IsRange():
+			sb.WriteString(fmt.Sprintf(" VALUES LESS THAN (%s)", desc))
+		case scheme.Method.IsList():
+			sb.WriteString(fmt.Sprintf(" VALUES IN (%s)", desc))
+		}
+		if def.Comment != "" {
+			sb.WriteString(fmt.Sprintf(" COMMENT = '%s'", strings.ReplaceAll(def.Comment, "'", "''")))
+		}
+		sb.WriteString(" ENGINE = InnoDB")
+	}
+	sb.WriteString(") */")
+	return sb.String(), nil
+}
+
+// hasDefaultHashPartitions returns whether the HASH or KEY partitions given have the default names and no comments, in
+// which case they can be declared with a PARTITIONS clause.
+func hasDefaultHashPartitions(scheme *sql.PartitionScheme) bool {
+	for i, def := range scheme.Definitions {
+		if def.Name != fmt.Sprintf("p%d", i) || def.Comment != "" {
+			return false
+		}
+	}
+	return true
+}
+
+// AlterPartitionAction is the kind of change made by an AlterPartition node.
+type AlterPartitionAction byte
+
+const (
+	// AlterPartitionAction_Add adds partitions, as in ALTER TABLE ... ADD PARTITION.
+	AlterPartitionAction_Add AlterPartitionAction = iota
+	// AlterPartitionAction_Drop drops partitions along with their rows, as in ALTER TABLE ... DROP PARTITION.
+	AlterPartitionAction_Drop
+	// AlterPartitionAction_Truncate removes the rows of partitions, as in ALTER TABLE ... TRUNCATE PARTITION.
+	AlterPartitionAction_Truncate
+	// AlterPartitionAction_Coalesce reduces the number of HASH or KEY partitions, as in ALTER TABLE ... COALESCE
+	// PARTITION.
+	AlterPartitionAction_Coalesce
+	// AlterPartitionAction_Reorganize replaces partitions with new ones, as in ALTER TABLE ... REORGANIZE PARTITION.
+	AlterPartitionAction_Reorganize
+	// AlterPartitionAction_Remove removes the partitioning of a table, as in ALTER TABLE ... REMOVE PARTITIONING.
+	AlterPartitionAction_Remove
+	// AlterPartitionAction_Repartition replaces the partitioning of a table, as in ALTER TABLE ... PARTITION BY.
+	AlterPartitionAction_Repartition
+)
+
+func (a AlterPartitionAction) String() string {
+	switch a {
+	case AlterPartitionAction_Add:
+		return "ADD PARTITION"
+	case AlterPartitionAction_Drop:
+		return "DROP PARTITION"
+	case AlterPartitionAction_Truncate:
+		return "TRUNCATE PARTITION"
+	case AlterPartitionAction_Coalesce:
+		return "COALESCE PARTITION"
+	case AlterPartitionAction_Reorganize:
+		return "REORGANIZE PARTITION"
+	case AlterPartitionAction_Remove:
+		return "REMOVE PARTITIONING"
+	case AlterPartitionAction_Repartition:
+		return "PARTITION BY"
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// AlterPartition is a node that changes the user-defined partitions of a table.
+type AlterPartition struct {
+	UnaryNode
+	Action AlterPartitionAction
+	// Names are the partitions dropped, truncated or reorganized. Nil for TRUNCATE PARTITION ALL.
+	Names []string
+	// Definitions are the partitions added, or the partitions that reorganized partitions are replaced with.
+	Definitions []*PartitionDefinitionSpec
+	// Count is the number of partitions added by ADD PARTITION PARTITIONS, or removed by COALESCE PARTITION.
+	Count int
+	// Options is the new partitioning of the table for ALTER TABLE ... PARTITION BY.
+	Options *PartitionOptions
+}
+
+var _ sql.Node = (*AlterPartition)(nil)
+var _ sql.Expressioner = (*AlterPartition)(nil)
+
+// NewAlterPartition returns a new AlterPartition node.
+func NewAlterPartition(table sql.Node, action AlterPartitionAction, names []string, defs []*PartitionDefinitionSpec, count int, options *PartitionOptions) *AlterPartition {
+	return &AlterPartition{
+		UnaryNode:   UnaryNode{Child: table},
+		Action:      action,
+		Names:       names,
+		Definitions: defs,
+		Count:       count,
+		Options:     options,
+	}
+}
+
+func getPartitionAlterable(node sql.Node) (sql.PartitionAlterableTable, error) {
+	switch node := node.(type) {
+	case *ResolvedTable:
+		return getPartitionAlterableTable(node.Table)
+	default:
+		return nil, sql.ErrPartitioningNotSupported.New(node.String())
+	}
+}
+
+func getPartitionAlterableTable(t sql.Table) (sql.PartitionAlterableTable, error) {
+	switch t := t.(type) {
+	case sql.PartitionAlterableTable:
+		return t, nil
+	case sql.TableWrapper:
+		return getPartitionAlterableTable(t.Underlying())
+	default:
+		return nil, sql.ErrPartitioningNotSupported.New(t.Name())
+	}
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (p *AlterPartition) Expressions() []sql.Expression {
+	exprs := partitionDefinitionExpressions(p.Definitions)
+	if p.Options != nil {
+		exprs = append(exprs, p.Options.expressions()...)
+	}
+	return exprs
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (p *AlterPartition) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
+	if len(exprs) != len(p.Expressions()) {
+		return nil, sql.ErrInvalidChildrenNumber.New(p, len(exprs), len(p.Expressions()))
+	}
+	np := *p
+	var i int
+	np.Definitions, i = partitionDefinitionsWithExpressions(p.Definitions, exprs)
+	if p.Options != nil {
+		np.Options, _ = p.Options.withExpressions(exprs[i:])
+	}
+	return &np, nil
+}
+
+// Resolved implements the sql.Node interface.
+func (p *AlterPartition) Resolved() bool {
+	if !p.Child.Resolved() {
+		return false
+	}
+	for _, expr := range p.Expressions() {
+		if !expr.Resolved() {
+			return false
+		}
+	}
+	return true
+}
+
+// Schema implements the sql.Node interface.
+func (p *AlterPartition) Schema() sql.Schema {
+	return types.OkResultSchema
+}
+
+// WithChildren implements the sql.Node interface.
+func (p *AlterPartition) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 1 {
+		return nil, sql.ErrInvalidChildrenNumber.New(p, len(children), 1)
+	}
+	np := *p
+	np.Child = children[0]
+	return &np, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (p *AlterPartition) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	db, table := getDatabaseName(p.Child), getTableName(p.Child)
+	if p.Action == AlterPartitionAction_Drop {
+		return opChecker.UserHasPrivileges(ctx,
+			sql.NewPrivilegedOperation(db, table, "", sql.PrivilegeType_Alter, sql.PrivilegeType_Drop))
+	}
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(db, table, "", sql.PrivilegeType_Alter))
+}
+
+// RowIter implements the sql.Node interface.
+func (p *AlterPartition) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	table, err := getPartitionAlterable(p.Child)
+	if err != nil {
+		return nil, err
+	}
+
+	var pkSch sql.PrimaryKeySchema
+	if pkTable, ok := table.(sql.PrimaryKeyTable); ok {
+		pkSch = pkTable.PrimaryKeySchema()
+	} else {
+		pkSch = sql.NewPrimaryKeySchema(table.Schema())
+	}
+
+	if p.Action == AlterPartitionAction_Repartition {
+		scheme, err := p.Options.buildScheme(ctx, pkSch)
+		if err != nil {
+			return nil, err
+		}
+		if err = table.SetPartitionScheme(ctx, scheme); err != nil {
+			return nil, err
+		}
+		return sql.RowsToRowIter(sql.NewRow(types.NewOkResult(0))), nil
+	}
+
+	current, err := table.PartitionScheme(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, sql.ErrPartitionManagementOnNonpartitioned.New()
+	}
+	for _, name := range p.Names {
+		if current.PartitionIndex(name) < 0 {
+			return nil, sql.ErrUnknownPartition.New(name, table.Name())
+		}
+	}
+
+	scheme := current.Copy()
+	switch p.Action {
+	case AlterPartitionAction_Add:
+		if len(p.Definitions) == 0 {
+			if !scheme.Method.IsHash() {
+				return nil, sql.ErrPartitionsMustBeDefined.New(strings.Fields(string(scheme.Method))[0])
+			}
+			scheme.Definitions = append(scheme.Definitions, hashPartitionDefinitions(len(scheme.Definitions), p.Count)...)
+		} else {
+			defs, err := buildPartitionDefinitions(ctx, scheme, pkSch.Schema, p.Definitions)
+			if err != nil {
+				return nil, err
+			}
+			scheme.Definitions = append(scheme.Definitions, defs...)
+		}
+	case AlterPartitionAction_Drop:
+		if scheme.Method.IsHash() {
+			return nil, sql.ErrPartitionActionOnlyOnRangeList.New("DROP")
+		}
+		scheme.Definitions = removePartitionDefinitions(scheme.Definitions, p.Names)
+		if len(scheme.Definitions) == 0 {
+			return nil, sql.ErrDropLastPartition.New()
+		}
+		if _, err = table.TruncatePartitions(ctx, p.Names); err != nil {
+			return nil, err
+		}
+	case AlterPartitionAction_Truncate:
+		names := p.Names
+		if names == nil {
+			names = current.PartitionNames()
+		}
+		if _, err = table.TruncatePartitions(ctx, names); err != nil {
+			return nil, err
+		}
+		return sql.RowsToRowIter(sql.NewRow(types.NewOkResult(0))), nil
+	case AlterPartitionAction_Coalesce:
+		if !scheme.Method.IsHash() {
+			return nil, sql.ErrCoalesceOnlyOnHashPartition.New()
+		}
+		if p.Count >= len(scheme.Definitions) {
+			return nil, sql.ErrDropLastPartition.New()
+		}
+		scheme.Definitions = scheme.Definitions[:len(scheme.Definitions)-p.Count]
+	case AlterPartitionAction_Reorganize:
+		defs, err := buildPartitionDefinitions(ctx, scheme, pkSch.Schema, p.Definitions)
+		if err != nil {
+			return nil, err
+		}
+		first := len(scheme.Definitions)
+		for _, name := range p.Names {
+			if idx := scheme.PartitionIndex(name); idx < first {
+				first = idx
+			}
+		}
+		remaining := removePartitionDefinitions(scheme.Definitions, p.Names)
+		scheme.Definitions = append(append(append([]sql.PartitionDefinition(nil), remaining[:first]...), defs...), remaining[first:]...)
+	case AlterPartitionAction_Remove:
+		scheme = nil
+	}
+
+	if scheme != nil {
+		if err = scheme.Validate(pkSch.Schema); err != nil {
+			return nil, err
+		}
+	}
+	if err = table.SetPartitionScheme(ctx, scheme); err != nil {
+		return nil, err
+	}
+	return sql.RowsToRowIter(sql.NewRow(types.NewOkResult(0))), nil
+}
+
+// removePartitionDefinitions returns the partition definitions given without the partitions named.
+func removePartitionDefinitions(defs []sql.PartitionDefinition, names []string) []sql.PartitionDefinition {
+	var remaining []sql.PartitionDefinition
+	for _, def := range defs {
+		removed := false
+		for _, name := range names {
+			if strings.EqualFold(def.Name, name) {
+				removed = true
+				break
+			}
+		}
+		if !removed {
+			remaining = append(remaining, def)
+		}
+	}
+	return remaining
+}
+
+func (p *AlterPartition) String() string {
+	pr := sql.NewTreePrinter()
+	_ = pr.WriteNode("AlterPartition(%s)", p.Action)
+	children := []string{fmt.Sprintf("Table(%s)", p.Child.String())}
+	if len(p.Names) > 0 {
+		children = append(children, fmt.Sprintf("Partitions(%s)", strings.Join(p.Names, ", ")))
+	}
+	if p.Options != nil {
+		children = append(children, p.Options.String())
+	}
+	_ = pr.WriteChildren(children...)
+	return pr.String()
+}
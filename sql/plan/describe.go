@@ -91,6 +91,11 @@ func (i *describeIter) Close(*sql.Context) error {
 type DescribeQuery struct {
 	child  sql.Node
 	Format string
+	// Analyze is set for EXPLAIN ANALYZE, which executes the query and describes the plan along with the rows
+	// produced by each node and the time spent producing them.
+	Analyze bool
+	// stats are used by the JSON format to estimate the number of rows of each table.
+	stats sql.StatsReader
 }
 
 func (d *DescribeQuery) Resolved() bool {
@@ -120,7 +125,12 @@ var DescribeSchema = sql.Schema{
 
 // NewDescribeQuery creates a new DescribeQuery node.
 func NewDescribeQuery(format string, child sql.Node) *DescribeQuery {
-	return &DescribeQuery{child, format}
+	return &DescribeQuery{child: child, Format: format}
+}
+
+// NewExplainAnalyze creates a new DescribeQuery node for EXPLAIN ANALYZE.
+func NewExplainAnalyze(child sql.Node) *DescribeQuery {
+	return &DescribeQuery{child: child, Format: "tree", Analyze: true}
 }
 
 // Schema implements the Node interface.
@@ -132,8 +142,21 @@ func (d *DescribeQuery) Schema() sql.Schema {
 func (d *DescribeQuery) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 	var rows []sql.Row
 	var formatString string
-	if d.Format == "debug" {
+	if d.Analyze {
+		var err error
+		formatString, err = explainAnalyze(ctx, d.child, row)
+		if err != nil {
+			return nil, err
+		}
+	} else if d.Format == "debug" {
 		formatString = sql.DebugString(d.child)
+	} else if d.Format == "json" {
+		// The JSON document is returned in a single row, like MySQL does
+		formatString, err := explainJSON(ctx, d.child, d.stats)
+		if err != nil {
+			return nil, err
+		}
+		return sql.RowsToRowIter(sql.NewRow(formatString)), nil
 	} else {
 		formatString = d.child.String()
 	}
@@ -148,7 +171,7 @@ func (d *DescribeQuery) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, err
 
 func (d *DescribeQuery) String() string {
 	pr := sql.NewTreePrinter()
-	_ = pr.WriteNode("DescribeQuery(format=%s)", d.Format)
+	_ = pr.WriteNode("DescribeQuery(format=%s%s)", d.Format, d.analyzeString())
 	if d.Format == "debug" {
 		_ = pr.WriteChildren(sql.DebugString(d.child))
 	} else {
@@ -159,17 +182,34 @@ func (d *DescribeQuery) String() string {
 
 func (d *DescribeQuery) DebugString() string {
 	pr := sql.NewTreePrinter()
-	_ = pr.WriteNode("DescribeQuery(format=%s)", d.Format)
+	_ = pr.WriteNode("DescribeQuery(format=%s%s)", d.Format, d.analyzeString())
 	_ = pr.WriteChildren(sql.DebugString(d.child))
 	return pr.String()
 }
 
+func (d *DescribeQuery) analyzeString() string {
+	if d.Analyze {
+		return ", analyze"
+	}
+	return ""
+}
+
 // Query returns the query node being described
 func (d *DescribeQuery) Query() sql.Node {
 	return d.child
 }
 
+// WithStats returns a copy of this node that reads the number of rows of the described tables from the statistics
+// given.
+func (d *DescribeQuery) WithStats(stats sql.StatsReader) *DescribeQuery {
+	nd := *d
+	nd.stats = stats
+	return &nd
+}
+
 // WithQuery returns a copy of this node with the query node given
 func (d *DescribeQuery) WithQuery(child sql.Node) sql.Node {
-	return NewDescribeQuery(d.Format, child)
+	nd := *d
+	nd.child = child
+	return &nd
 }
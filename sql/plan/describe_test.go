@@ -16,6 +16,7 @@ package plan
 
 import (
 	"io"
+	"regexp"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -108,3 +109,74 @@ func TestDescribeQuery(t *testing.T) {
 
 	require.Equal(expected, rows)
 }
+
+func TestExplainAnalyze(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	table := memory.NewTable("foo", sql.NewPrimaryKeySchema(sql.Schema{
+		{Source: "foo", Name: "a", Type: types.Text},
+		{Source: "foo", Name: "b", Type: types.Text},
+	}), nil)
+	for _, r := range []sql.Row{{"foo", "1"}, {"bar", "2"}, {"foo", "3"}} {
+		require.NoError(table.Insert(ctx, r))
+	}
+
+	node := NewExplainAnalyze(NewProject(
+		[]sql.Expression{
+			expression.NewGetFieldWithTable(1, types.Text, "foo", "b", false),
+		},
+		NewFilter(
+			expression.NewEquals(
+				expression.NewGetFieldWithTable(0, types.Text, "foo", "a", false),
+				expression.NewLiteral("foo", types.LongText),
+			),
+			NewResolvedTable(table, nil, nil),
+		),
+	))
+
+	iter, err := node.RowIter(ctx, nil)
+	require.NoError(err)
+
+	rows, err := sql.RowIterToRows(ctx, nil, iter)
+	require.NoError(err)
+
+	timings := regexp.MustCompile(`first_row=\S+ total=[^)\s]+`)
+	for i := range rows {
+		rows[i][0] = timings.ReplaceAllString(rows[i][0].(string), "first_row=? total=?")
+	}
+
+	expected := []sql.Row{
+		{"Project (actual rows=2 loops=1 first_row=? total=?)"},
+		{" ├─ columns: [foo.b]"},
+		{" └─ Filter (actual rows=2 loops=1 first_row=? total=?)"},
+		{"     ├─ (foo.a = 'foo')"},
+		{"     └─ Table (actual rows=3 loops=1 first_row=? total=?)"},
+		{"         └─ name: foo"},
+	}
+
+	require.Equal(expected, rows)
+}
+
+func TestExplainAnalyzeHashLookup(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	table := memory.NewTable("foo", sql.NewPrimaryKeySchema(sql.Schema{
+		{Source: "foo", Name: "a", Type: types.Text},
+	}), nil)
+	for _, r := range []sql.Row{{"foo"}, {"bar"}} {
+		require.NoError(table.Insert(ctx, r))
+	}
+
+	lookup := NewHashLookup(
+		NewCachedResults(NewResolvedTable(table, nil, nil)),
+		expression.NewGetFieldWithTable(0, types.Text, "foo", "a", false),
+		expression.NewGetFieldWithTable(0, types.Text, "foo", "a", false),
+	)
+	str, err := explainAnalyze(ctx, lookup, sql.Row{"foo"})
+	require.NoError(err)
+
+	// two rows of a single value: 16 bytes for each value, along with 3 bytes for each string
+	require.Regexp(`^HashLookup .*\(actual rows=2 loops=1 first_row=\S+ total=\S+ memory=38B\)`, str)
+}
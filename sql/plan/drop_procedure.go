@@ -91,7 +91,7 @@ func (d *DropProcedure) WithChildren(children ...sql.Node) (sql.Node, error) {
 // CheckPrivileges implements the interface sql.Node.
 func (d *DropProcedure) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
 	return opChecker.UserHasPrivileges(ctx,
-		sql.NewPrivilegedOperation(d.db.Name(), "", "", sql.PrivilegeType_AlterRoutine))
+		sql.NewPrivilegedOperationForRoutine(d.db.Name(), d.ProcedureName, true, sql.PrivilegeType_AlterRoutine))
 }
 
 // Database implements the sql.Databaser interface.
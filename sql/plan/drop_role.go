@@ -144,6 +144,9 @@ func (n *DropRole) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 		if err != nil {
 			return nil, err
 		}
+		if err = mysqlDb.RemoveDefaultRoles(ctx, existingUser); err != nil {
+			return nil, err
+		}
 	}
 	if err := mysqlDb.Persist(ctx); err != nil {
 		return nil, err
@@ -18,9 +18,11 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 	errors "gopkg.in/src-d/go-errors.v1"
 
@@ -33,14 +35,19 @@ import (
 var ErrNoPartitionable = errors.NewKind("no partitionable node found in exchange tree")
 
 // Exchange is a node that can parallelize the underlying tree iterating
-// partitions concurrently.
+// partitions concurrently. Each of its workers runs the underlying tree once,
+// over the rows of the partitions it takes.
 type Exchange struct {
 	UnaryNode
 	Parallelism int
+	// SortFields are the order of the rows of each worker, when the rows of
+	// the workers are merged in that order rather than as they arrive.
+	SortFields sql.SortFields
 }
 
 var _ sql.Node = (*Exchange)(nil)
 var _ sql.Node2 = (*Exchange)(nil)
+var _ sql.Expressioner = (*Exchange)(nil)
 
 // NewExchange creates a new Exchange node.
 func NewExchange(
@@ -53,6 +60,21 @@ func NewExchange(
 	}
 }
 
+// NewOrderedExchange creates a new Exchange node whose workers each return
+// their rows in the order of the sort fields given, usually by sorting them
+// in the underlying tree. The rows of the workers are merged in that order.
+func NewOrderedExchange(
+	parallelism int,
+	sortFields sql.SortFields,
+	child sql.Node,
+) *Exchange {
+	return &Exchange{
+		UnaryNode:   UnaryNode{Child: child},
+		Parallelism: parallelism,
+		SortFields:  sortFields,
+	}
+}
+
 // RowIter implements the sql.Node interface.
 func (e *Exchange) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 	var t sql.Table
@@ -78,10 +100,11 @@ func (e *Exchange) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 	// |e.Parallelism| instances of |iterPartitionRows|. A
 	// goroutine within the top-level errgroup |Wait|s on the
 	// dependent errgroup and closes |rowsCh| once all its
-	// goroutines are completed.
+	// goroutines are completed. An ordered exchange gives each
+	// worker a channel of its own instead, which the worker closes
+	// once it's done, and merges the rows of those channels.
 
 	partitionsCh := make(chan sql.Partition)
-	rowsCh := make(chan sql.Row, e.Parallelism*16)
 
 	eg, egCtx := ctx.NewErrgroup()
 	eg.Go(func() error {
@@ -93,14 +116,31 @@ func (e *Exchange) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 	// errgroup.
 	getRowIter := e.getRowIterFunc(row)
 	seg, segCtx := egCtx.NewErrgroup()
-	for i := 0; i < e.Parallelism; i++ {
-		seg.Go(func() error {
-			return iterPartitionRows(segCtx, getRowIter, partitionsCh, rowsCh)
-		})
+	var rowsCh chan sql.Row
+	var workerChs []chan sql.Row
+	if len(e.SortFields) == 0 {
+		rowsCh = make(chan sql.Row, e.Parallelism*16)
+		for i := 0; i < e.Parallelism; i++ {
+			seg.Go(func() error {
+				return iterPartitionRows(segCtx, getRowIter, partitionsCh, rowsCh)
+			})
+		}
+	} else {
+		workerChs = make([]chan sql.Row, e.Parallelism)
+		for i := range workerChs {
+			workerCh := make(chan sql.Row, 16)
+			workerChs[i] = workerCh
+			seg.Go(func() error {
+				defer close(workerCh)
+				return iterPartitionRows(segCtx, getRowIter, partitionsCh, workerCh)
+			})
+		}
 	}
 
 	eg.Go(func() error {
-		defer close(rowsCh)
+		if rowsCh != nil {
+			defer close(rowsCh)
+		}
 		err := seg.Wait()
 		if err != nil {
 			return err
@@ -114,6 +154,14 @@ func (e *Exchange) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 
 	waiter := func() error { return eg.Wait() }
 	shutdownHook := newShutdownHook(eg, egCtx)
+	if workerChs != nil {
+		iters := make([]sql.RowIter, len(workerChs))
+		for i, workerCh := range workerChs {
+			iters[i] = &exchangeWorkerRowIter{rows: workerCh}
+		}
+		merge := newSortMergeIter(ctx, e.SortFields, iters)
+		return &exchangeRowIter{shutdownHook: shutdownHook, waiter: waiter, merge: merge}, nil
+	}
 	return &exchangeRowIter{shutdownHook: shutdownHook, waiter: waiter, rows: rowsCh}, nil
 }
 
@@ -169,19 +217,40 @@ func (e *Exchange) RowIter2(ctx *sql.Context, f *sql.RowFrame) (sql.RowIter2, er
 
 	waiter := func() error { return eg.Wait() }
 	shutdownHook := newShutdownHook(eg, egCtx)
-	return &exchangeRowIter{shutdownHook: shutdownHook, waiter: waiter, rows2: rowsCh}, nil
+	iter := &exchangeRowIter{shutdownHook: shutdownHook, waiter: waiter, rows2: rowsCh}
+	if len(e.SortFields) > 0 {
+		// Rows of the workers arrive interleaved here, so they're sorted again rather than merged
+		return newSortIter(e.SortFields, iter), nil
+	}
+	return iter, nil
 }
 
 func (e *Exchange) String() string {
 	p := sql.NewTreePrinter()
-	_ = p.WriteNode("Exchange")
+	if len(e.SortFields) > 0 {
+		var fields = make([]string, len(e.SortFields))
+		for i, f := range e.SortFields {
+			fields[i] = fmt.Sprintf("%s %s", f.Column, f.Order)
+		}
+		_ = p.WriteNode("Exchange(order: %s)", strings.Join(fields, ", "))
+	} else {
+		_ = p.WriteNode("Exchange")
+	}
 	_ = p.WriteChildren(e.Child.String())
 	return p.String()
 }
 
 func (e *Exchange) DebugString() string {
 	p := sql.NewTreePrinter()
-	_ = p.WriteNode("Exchange(parallelism=%d)", e.Parallelism)
+	if len(e.SortFields) > 0 {
+		var fields = make([]string, len(e.SortFields))
+		for i, f := range e.SortFields {
+			fields[i] = sql.DebugString(f)
+		}
+		_ = p.WriteNode("Exchange(parallelism=%d, order: %s)", e.Parallelism, strings.Join(fields, ", "))
+	} else {
+		_ = p.WriteNode("Exchange(parallelism=%d)", e.Parallelism)
+	}
 	_ = p.WriteChildren(sql.DebugString(e.Child))
 	return p.String()
 }
@@ -192,7 +261,21 @@ func (e *Exchange) WithChildren(children ...sql.Node) (sql.Node, error) {
 		return nil, sql.ErrInvalidChildrenNumber.New(e, len(children), 1)
 	}
 
-	return NewExchange(e.Parallelism, children[0]), nil
+	return NewOrderedExchange(e.Parallelism, e.SortFields, children[0]), nil
+}
+
+// Expressions implements the sql.Expressioner interface.
+func (e *Exchange) Expressions() []sql.Expression {
+	return e.SortFields.ToExpressions()
+}
+
+// WithExpressions implements the sql.Expressioner interface.
+func (e *Exchange) WithExpressions(exprs ...sql.Expression) (sql.Node, error) {
+	if len(exprs) != len(e.SortFields) {
+		return nil, sql.ErrInvalidChildrenNumber.New(e, len(exprs), len(e.SortFields))
+	}
+
+	return NewOrderedExchange(e.Parallelism, e.SortFields.FromExpressions(exprs...), e.Child), nil
 }
 
 // CheckPrivileges implements the interface sql.Node.
@@ -200,11 +283,11 @@ func (e *Exchange) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOpe
 	return e.Child.CheckPrivileges(ctx, opChecker)
 }
 
-func (e *Exchange) getRowIterFunc(row sql.Row) func(*sql.Context, sql.Partition) (sql.RowIter, error) {
-	return func(ctx *sql.Context, partition sql.Partition) (sql.RowIter, error) {
+func (e *Exchange) getRowIterFunc(row sql.Row) rowIterPartitionFunc {
+	return func(ctx *sql.Context, partitions <-chan sql.Partition) (sql.RowIter, error) {
 		node, _, err := transform.Node(e.Child, func(n sql.Node) (sql.Node, transform.TreeIdentity, error) {
 			if t, ok := n.(sql.Table); ok {
-				return &exchangePartition{partition, t}, transform.NewTree, nil
+				return &exchangePartitions{partitions, t}, transform.NewTree, nil
 			}
 			return n, transform.SameTree, nil
 		})
@@ -215,11 +298,11 @@ func (e *Exchange) getRowIterFunc(row sql.Row) func(*sql.Context, sql.Partition)
 	}
 }
 
-func (e *Exchange) getRowIter2Func() func(*sql.Context, sql.Partition, *sql.RowFrame) (sql.RowIter2, error) {
-	return func(ctx *sql.Context, partition sql.Partition, frame *sql.RowFrame) (sql.RowIter2, error) {
+func (e *Exchange) getRowIter2Func() rowIter2PartitionFunc {
+	return func(ctx *sql.Context, partitions <-chan sql.Partition, frame *sql.RowFrame) (sql.RowIter2, error) {
 		node, _, err := transform.Node(e.Child, func(n sql.Node) (sql.Node, transform.TreeIdentity, error) {
 			if t, ok := n.(sql.Table); ok {
-				return &exchangePartition{partition, t}, transform.NewTree, nil
+				return &exchangePartitions{partitions, t}, transform.NewTree, nil
 			}
 			return n, transform.SameTree, nil
 		})
@@ -231,9 +314,10 @@ func (e *Exchange) getRowIter2Func() func(*sql.Context, sql.Partition, *sql.RowF
 }
 
 // exchangeRowIter implements sql.RowIter for an exchange
-// node. Calling |Next| reads off of |rows|, while calling |Close|
-// calls |shutdownHook| and waits for exchange node workers to
-// shutdown. If |rows| is closed, |Next| returns the error returned by
+// node. Calling |Next| reads off of |rows|, or off of |merge| for an
+// ordered exchange, while calling |Close| calls |shutdownHook| and
+// waits for exchange node workers to shutdown. If |rows| is closed,
+// or |merge| is done, |Next| returns the error returned by
 // |waiter|. |Close| returns the error returned by |waiter|, except it
 // returns |nil| if |waiter| returns |io.EOF| or |shutdownHookErr|.
 type exchangeRowIter struct {
@@ -241,12 +325,20 @@ type exchangeRowIter struct {
 	waiter       func() error
 	rows         <-chan sql.Row
 	rows2        <-chan sql.Row2
+	merge        sql.RowIter
 }
 
 var _ sql.RowIter = (*exchangeRowIter)(nil)
 var _ sql.RowIter2 = (*exchangeRowIter)(nil)
 
 func (i *exchangeRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	if i.merge != nil {
+		r, err := i.merge.Next(ctx)
+		if err == io.EOF {
+			return nil, i.waiter()
+		}
+		return r, err
+	}
 	if i.rows == nil {
 		panic("Next called for a Next2 iterator")
 	}
@@ -279,36 +371,64 @@ func (i *exchangeRowIter) Close(ctx *sql.Context) error {
 	return err
 }
 
-type exchangePartition struct {
-	sql.Partition
-	table sql.Table
+// exchangeWorkerRowIter returns the rows a worker of an ordered
+// exchange sends to |rows|, until the worker closes it. The rows are
+// merged by the exchangeRowIter, which waits for the workers itself.
+type exchangeWorkerRowIter struct {
+	rows <-chan sql.Row
 }
 
-var _ sql.Node = (*exchangePartition)(nil)
-var _ sql.Node2 = (*exchangePartition)(nil)
+var _ sql.RowIter = (*exchangeWorkerRowIter)(nil)
+
+func (i *exchangeWorkerRowIter) Next(ctx *sql.Context) (sql.Row, error) {
+	select {
+	case r, ok := <-i.rows:
+		if !ok {
+			return nil, io.EOF
+		}
+		return r, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
 
-func (p *exchangePartition) String() string {
-	return fmt.Sprintf("Partition(%s)", string(p.Key()))
+func (i *exchangeWorkerRowIter) Close(ctx *sql.Context) error {
+	return nil
 }
 
-func (exchangePartition) Children() []sql.Node { return nil }
+// exchangePartitions stands for the table of an exchange in the tree
+// a worker runs. It returns the rows of every partition the worker
+// reads off of |partitions|, one partition after the other.
+type exchangePartitions struct {
+	partitions <-chan sql.Partition
+	table      sql.Table
+}
 
-func (exchangePartition) Resolved() bool { return true }
+var _ sql.Node = (*exchangePartitions)(nil)
+var _ sql.Node2 = (*exchangePartitions)(nil)
 
-func (p *exchangePartition) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
-	return p.table.PartitionRows(ctx, p.Partition)
+func (p *exchangePartitions) String() string {
+	return fmt.Sprintf("Partitions(%s)", p.table.Name())
 }
 
-func (p *exchangePartition) RowIter2(ctx *sql.Context, f *sql.RowFrame) (sql.RowIter2, error) {
-	return p.table.(sql.Table2).PartitionRows2(ctx, p.Partition)
+func (exchangePartitions) Children() []sql.Node { return nil }
+
+func (exchangePartitions) Resolved() bool { return true }
+
+func (p *exchangePartitions) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	return &exchangePartitionsIter{partitions: p.partitions, table: p.table}, nil
+}
+
+func (p *exchangePartitions) RowIter2(ctx *sql.Context, f *sql.RowFrame) (sql.RowIter2, error) {
+	return &exchangePartitionsIter{partitions: p.partitions, table: p.table}, nil
 }
 
-func (p *exchangePartition) Schema() sql.Schema {
+func (p *exchangePartitions) Schema() sql.Schema {
 	return p.table.Schema()
 }
 
 // WithChildren implements the Node interface.
-func (p *exchangePartition) WithChildren(children ...sql.Node) (sql.Node, error) {
+func (p *exchangePartitions) WithChildren(children ...sql.Node) (sql.Node, error) {
 	if len(children) != 0 {
 		return nil, sql.ErrInvalidChildrenNumber.New(p, len(children), 0)
 	}
@@ -317,7 +437,7 @@ func (p *exchangePartition) WithChildren(children ...sql.Node) (sql.Node, error)
 }
 
 // CheckPrivileges implements the interface sql.Node.
-func (p *exchangePartition) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+func (p *exchangePartitions) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
 	if node, ok := p.table.(sql.Node); ok {
 		return node.CheckPrivileges(ctx, opChecker)
 	}
@@ -326,8 +446,114 @@ func (p *exchangePartition) CheckPrivileges(ctx *sql.Context, opChecker sql.Priv
 	return true
 }
 
-type rowIterPartitionFunc func(ctx *sql.Context, partition sql.Partition) (sql.RowIter, error)
-type rowIter2PartitionFunc func(ctx *sql.Context, partition sql.Partition, frame *sql.RowFrame) (sql.RowIter2, error)
+// exchangePartitionsIter iterates the rows of the partitions read off
+// of |partitions|. It returns |io.EOF| once |partitions| is closed.
+type exchangePartitionsIter struct {
+	partitions <-chan sql.Partition
+	table      sql.Table
+	iter       sql.RowIter
+	iter2      sql.RowIter2
+	span       trace.Span
+	count      int
+}
+
+var _ sql.RowIter = (*exchangePartitionsIter)(nil)
+var _ sql.RowIter2 = (*exchangePartitionsIter)(nil)
+
+func (i *exchangePartitionsIter) Next(ctx *sql.Context) (sql.Row, error) {
+	for {
+		if i.iter == nil {
+			p, err := i.nextPartition(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if i.iter, err = i.table.PartitionRows(ctx, p); err != nil {
+				return nil, err
+			}
+		}
+		r, err := i.iter.Next(ctx)
+		if err == io.EOF {
+			if err = i.closePartition(ctx); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		i.count++
+		return r, nil
+	}
+}
+
+func (i *exchangePartitionsIter) Next2(ctx *sql.Context, frame *sql.RowFrame) error {
+	for {
+		if i.iter2 == nil {
+			p, err := i.nextPartition(ctx)
+			if err != nil {
+				return err
+			}
+			if i.iter2, err = i.table.(sql.Table2).PartitionRows2(ctx, p); err != nil {
+				return err
+			}
+		}
+		err := i.iter2.Next2(ctx, frame)
+		if err == io.EOF {
+			if err = i.closePartition(ctx); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		i.count++
+		return nil
+	}
+}
+
+// nextPartition reads the next partition off of |partitions|, and
+// starts a span for it.
+func (i *exchangePartitionsIter) nextPartition(ctx *sql.Context) (sql.Partition, error) {
+	select {
+	case p, ok := <-i.partitions:
+		if !ok {
+			return nil, io.EOF
+		}
+		i.span, _ = ctx.Span("exchange.IterPartition")
+		i.count = 0
+		return p, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// closePartition closes the row iterator of the current partition,
+// and ends its span.
+func (i *exchangePartitionsIter) closePartition(ctx *sql.Context) error {
+	var err error
+	if i.iter != nil {
+		err = i.iter.Close(ctx)
+		i.iter = nil
+	}
+	if i.iter2 != nil {
+		err = i.iter2.Close(ctx)
+		i.iter2 = nil
+	}
+	if i.span != nil {
+		i.span.SetAttributes(attribute.Int("num_rows", i.count))
+		i.span.End()
+		i.span = nil
+	}
+	return err
+}
+
+func (i *exchangePartitionsIter) Close(ctx *sql.Context) error {
+	return i.closePartition(ctx)
+}
+
+type rowIterPartitionFunc func(ctx *sql.Context, partitions <-chan sql.Partition) (sql.RowIter, error)
+type rowIter2PartitionFunc func(ctx *sql.Context, partitions <-chan sql.Partition, frame *sql.RowFrame) (sql.RowIter2, error)
 
 func sendAllRows(ctx *sql.Context, iter sql.RowIter, rows chan<- sql.Row) (rowCount int, rerr error) {
 	defer func() {
@@ -379,39 +605,24 @@ func sendAllRows2(ctx *sql.Context, iter sql.RowIter2, rows chan<- sql.Row2, f *
 }
 
 // iterPartitionRows is the parallel worker for an Exchange node. It
-// is meant to be run as a goroutine in an errgroup.Group. It will
-// values read off of |partitions|. For each value it reads, it will
-// call |getRowIter| to get a row projectIter, and will then call |Next| on
-// that row projectIter, passing every row it gets into |rows|. If it
-// receives an error at any point, it returns it. |iterPartitionRows|
-// stops iterating and returns |nil| when |partitions| is closed.
+// is meant to be run as a goroutine in an errgroup.Group. It calls
+// |getRowIter| to get a row iterator over the rows of the partitions
+// it reads off of |partitions|, and will then call |Next| on that row
+// iterator, passing every row it gets into |rows|. If it receives an
+// error at any point, it returns it. |iterPartitionRows| stops
+// iterating and returns |nil| once |partitions| is closed.
 func iterPartitionRows(ctx *sql.Context, getRowIter rowIterPartitionFunc, partitions <-chan sql.Partition, rows chan<- sql.Row) (rerr error) {
 	defer func() {
 		if r := recover(); r != nil {
 			rerr = fmt.Errorf("panic in ExchangeIterPartitionRows: %v", r)
 		}
 	}()
-	for {
-		select {
-		case p, ok := <-partitions:
-			if !ok {
-				return nil
-			}
-			span, ctx := ctx.Span("exchange.IterPartition")
-			iter, err := getRowIter(ctx, p)
-			if err != nil {
-				return err
-			}
-			count, err := sendAllRows(ctx, iter, rows)
-			span.SetAttributes(attribute.Int("num_rows", count))
-			span.End()
-			if err != nil {
-				return err
-			}
-		case <-ctx.Done():
-			return ctx.Err()
-		}
+	iter, err := getRowIter(ctx, partitions)
+	if err != nil {
+		return err
 	}
+	_, err = sendAllRows(ctx, iter, rows)
+	return err
 }
 
 func iterPartitionRows2(ctx *sql.Context, getRowIter rowIter2PartitionFunc, partitions <-chan sql.Partition, rows chan<- sql.Row2) (rerr error) {
@@ -420,29 +631,13 @@ func iterPartitionRows2(ctx *sql.Context, getRowIter rowIter2PartitionFunc, part
 			rerr = fmt.Errorf("panic in ExchangeIterPartitionRows2: %v", r)
 		}
 	}()
-	for {
-		select {
-		case p, ok := <-partitions:
-			if !ok {
-				return nil
-			}
-			f := sql.NewRowFrame()
-
-			span, ctx := ctx.Span("exchange.IterPartition")
-			iter, err := getRowIter(ctx, p, f)
-			if err != nil {
-				return err
-			}
-			count, err := sendAllRows2(ctx, iter, rows, f)
-			span.SetAttributes(attribute.Int("num_rows", count))
-			span.End()
-			if err != nil {
-				return err
-			}
-		case <-ctx.Done():
-			return ctx.Err()
-		}
+	f := sql.NewRowFrame()
+	iter, err := getRowIter(ctx, partitions, f)
+	if err != nil {
+		return err
 	}
+	_, err = sendAllRows2(ctx, iter, rows, f)
+	return err
 }
 
 // iterPartitions will call Next() on |iter| and send every result it
@@ -76,6 +76,36 @@ func TestExchange(t *testing.T) {
 	}
 }
 
+func TestOrderedExchange(t *testing.T) {
+	sortFields := sql.SortFields{
+		{Column: expression.NewGetField(1, types.Int64, "val", false), Order: sql.Descending},
+		{Column: expression.NewGetField(0, types.Text, "partition", false), Order: sql.Ascending},
+	}
+	children := NewSort(sortFields, &partitionable{nil, 5, 4})
+
+	var expected []sql.Row
+	for val := 4; val > 0; val-- {
+		for partition := 1; partition <= 5; partition++ {
+			expected = append(expected, sql.NewRow(fmt.Sprint(partition), int64(val)))
+		}
+	}
+
+	for i := 1; i <= 4; i++ {
+		t.Run(fmt.Sprint(i), func(t *testing.T) {
+			require := require.New(t)
+
+			exchange := NewOrderedExchange(i, sortFields, children)
+			ctx := sql.NewEmptyContext()
+			iter, err := exchange.RowIter(ctx, nil)
+			require.NoError(err)
+
+			rows, err := sql.RowIterToRows(ctx, nil, iter)
+			require.NoError(err)
+			require.Equal(expected, rows)
+		})
+	}
+}
+
 func TestExchangeCancelled(t *testing.T) {
 	children := NewProject(
 		[]sql.Expression{
@@ -129,7 +159,7 @@ func TestExchangeIterPartitionRowsPanic(t *testing.T) {
 	ctx := sql.NewContext(context.Background())
 	partitions := make(chan sql.Partition, 1)
 	partitions <- Partition("test")
-	err := iterPartitionRows(ctx, func(*sql.Context, sql.Partition) (sql.RowIter, error) {
+	err := iterPartitionRows(ctx, func(*sql.Context, <-chan sql.Partition) (sql.RowIter, error) {
 		return &rowIterPanic{}, nil
 	}, partitions, nil)
 	assert.Error(t, err)
@@ -137,8 +167,7 @@ func TestExchangeIterPartitionRowsPanic(t *testing.T) {
 
 	closedCh := make(chan sql.Row)
 	close(closedCh)
-	partitions <- Partition("test")
-	err = iterPartitionRows(ctx, func(*sql.Context, sql.Partition) (sql.RowIter, error) {
+	err = iterPartitionRows(ctx, func(*sql.Context, <-chan sql.Partition) (sql.RowIter, error) {
 		return &partitionRows{Partition("test"), 10}, nil
 	}, partitions, closedCh)
 	assert.Error(t, err)
@@ -0,0 +1,247 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dolthub/go-mysql-server/sql"
+)
+
+// analyzedNode wraps a node of a plan that is explained by EXPLAIN ANALYZE, recording the rows produced by the row
+// iterators of the node and the time spent producing them. Its String method prints the node with these statistics.
+type analyzedNode struct {
+	sql.Node
+	stats *nodeStats
+}
+
+var _ sql.Node = (*analyzedNode)(nil)
+
+// nodeStats are the statistics that EXPLAIN ANALYZE records for a node. As the iterators of a node may be used
+// concurrently, the statistics are guarded by a mutex.
+type nodeStats struct {
+	mu sync.Mutex
+	// loops is the number of row iterators that were created for the node.
+	loops int64
+	// rows is the number of rows produced by all the iterators of the node.
+	rows int64
+	// firstRow is the time spent in the node until it produced its first row.
+	firstRow time.Duration
+	// total is the time spent creating the iterators of the node and producing their rows, including the time spent in
+	// the node's children.
+	total time.Duration
+	// memory is the estimated size of the rows held in memory by the node, for the nodes that cache rows.
+	memory uint64
+	// hasFirstRow is set once the first row has been produced.
+	hasFirstRow bool
+}
+
+// newAnalyzedNode wraps the node given, and its descendants, with analyzedNode. The children of nodes that expect a
+// specific type of child, as well as the children of exchanges that replace the tables of their children for each
+// partition, are not wrapped.
+func newAnalyzedNode(n sql.Node) (sql.Node, error) {
+	switch n.(type) {
+	case *HashLookup, *TableAlias, *Exchange:
+	default:
+		children := n.Children()
+		if len(children) > 0 {
+			newChildren := make([]sql.Node, len(children))
+			for i, child := range children {
+				var err error
+				newChildren[i], err = newAnalyzedNode(child)
+				if err != nil {
+					return nil, err
+				}
+			}
+			var err error
+			n, err = n.WithChildren(newChildren...)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &analyzedNode{Node: n, stats: &nodeStats{}}, nil
+}
+
+// RowIter implements the sql.Node interface.
+func (n *analyzedNode) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	start := time.Now()
+	iter, err := n.Node.RowIter(ctx, row)
+	loop := n.stats.addLoop(start)
+	if err != nil {
+		return nil, err
+	}
+	// Empty cached results are recognized by their iterator type to short-circuit joins
+	if isEmptyIter(iter) {
+		return iter, nil
+	}
+	_, cachesRows := n.Node.(*CachedResults)
+	return &analyzedIter{iter: iter, stats: n.stats, cachesRows: cachesRows && loop == 1}, nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (n *analyzedNode) WithChildren(children ...sql.Node) (sql.Node, error) {
+	node, err := n.Node.WithChildren(children...)
+	if err != nil {
+		return nil, err
+	}
+	return &analyzedNode{Node: node, stats: n.stats}, nil
+}
+
+// String implements the fmt.Stringer interface. The statistics of the node are added to the first line of the node's
+// description.
+func (n *analyzedNode) String() string {
+	if lookup, ok := n.Node.(*HashLookup); ok {
+		n.stats.setMemory(lookup.memoryUsage())
+	}
+	header, rest, hasRest := strings.Cut(n.Node.String(), "\n")
+	header = header + " " + n.stats.String()
+	if hasRest {
+		return header + "\n" + rest
+	}
+	return header
+}
+
+// addLoop records the creation of a row iterator that started at the time given, and returns the number of iterators
+// created so far.
+func (s *nodeStats) addLoop(start time.Time) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loops++
+	s.total += time.Since(start)
+	return s.loops
+}
+
+// addRow records the result of a call to Next that started at the time given.
+func (s *nodeStats) addRow(start time.Time, row sql.Row, cached bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.total += time.Since(start)
+	if row == nil {
+		return
+	}
+	s.rows++
+	if !s.hasFirstRow {
+		s.hasFirstRow = true
+		s.firstRow = s.total
+	}
+	if cached {
+		s.memory += sql.RowMemorySize(row)
+	}
+}
+
+func (s *nodeStats) setMemory(memory uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.memory = memory
+}
+
+// String returns the statistics, as printed by EXPLAIN ANALYZE.
+func (s *nodeStats) String() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	str := fmt.Sprintf("(actual rows=%d loops=%d first_row=%s total=%s", s.rows, s.loops, s.firstRow, s.total)
+	if s.memory > 0 {
+		str += fmt.Sprintf(" memory=%dB", s.memory)
+	}
+	return str + ")"
+}
+
+// analyzedIter is the row iterator of an analyzedNode.
+type analyzedIter struct {
+	iter  sql.RowIter
+	stats *nodeStats
+	// cachesRows is set for the first iterator of a node that caches its rows, whose rows are counted in the memory
+	// used by the node.
+	cachesRows bool
+}
+
+var _ sql.RowIter = (*analyzedIter)(nil)
+
+// Next implements the sql.RowIter interface.
+func (i *analyzedIter) Next(ctx *sql.Context) (sql.Row, error) {
+	start := time.Now()
+	row, err := i.iter.Next(ctx)
+	if err != nil {
+		i.stats.addRow(start, nil, false)
+		return nil, err
+	}
+	i.stats.addRow(start, row, i.cachesRows)
+	return row, nil
+}
+
+// Close implements the sql.RowIter interface.
+func (i *analyzedIter) Close(ctx *sql.Context) error {
+	return i.iter.Close(ctx)
+}
+
+// explainAnalyze executes the plan given until all of its rows were read, and returns the plan annotated with the
+// statistics of each of its nodes.
+func explainAnalyze(ctx *sql.Context, n sql.Node, row sql.Row) (string, error) {
+	analyzed, err := newAnalyzedNode(n)
+	if err != nil {
+		return "", err
+	}
+	iter, err := analyzed.RowIter(ctx, row)
+	if err != nil {
+		return "", err
+	}
+	for {
+		_, err = iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			iter.Close(ctx)
+			return "", err
+		}
+	}
+	if err = iter.Close(ctx); err != nil {
+		return "", err
+	}
+	return analyzed.String(), nil
+}
+
+// memoryUsage returns the estimated size of the rows held by the hash tables of the lookup, or by its cached results
+// before the hash tables are built. The rows spilled to disk aren't counted.
+func (n *HashLookup) memoryUsage() uint64 {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+	var memory uint64
+	if n.lookup == nil && n.partitions == nil {
+		if cache := n.Child.(*CachedResults).getCachedResults(); cache != nil {
+			for _, row := range cache.Get() {
+				memory += sql.RowMemorySize(row)
+			}
+		}
+		return memory
+	}
+	lookups := n.partitionLookups
+	if n.lookup != nil {
+		lookups = map[int]map[interface{}][]sql.Row{0: n.lookup}
+	}
+	for _, lookup := range lookups {
+		for _, rows := range lookup {
+			for _, row := range rows {
+				memory += sql.RowMemorySize(row)
+			}
+		}
+	}
+	return memory
+}
@@ -0,0 +1,487 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+)
+
+// explainQueryBlock is a query block of the JSON format of EXPLAIN, as understood by MySQL tooling. The same structure
+// describes the operations that are nested in a query block, such as the sort of an ORDER BY.
+type explainQueryBlock struct {
+	SelectId            int                   `json:"select_id,omitempty"`
+	Recursive           bool                  `json:"recursive,omitempty"`
+	Message             string                `json:"message,omitempty"`
+	UsingTemporaryTable bool                  `json:"using_temporary_table,omitempty"`
+	UsingFilesort       bool                  `json:"using_filesort,omitempty"`
+	OrderingOperation   *explainQueryBlock    `json:"ordering_operation,omitempty"`
+	GroupingOperation   *explainQueryBlock    `json:"grouping_operation,omitempty"`
+	DuplicatesRemoval   *explainQueryBlock    `json:"duplicates_removal,omitempty"`
+	UnionResult         *explainUnionResult   `json:"union_result,omitempty"`
+	NestedLoop          []*explainNestedTable `json:"nested_loop,omitempty"`
+	Table               *explainTable         `json:"table,omitempty"`
+}
+
+// explainNestedTable is an element of the nested_loop of a query block.
+type explainNestedTable struct {
+	Table *explainTable `json:"table"`
+}
+
+// explainTable describes the access to a table, or to a derived table.
+type explainTable struct {
+	TableName                string                   `json:"table_name"`
+	AccessType               string                   `json:"access_type"`
+	PossibleKeys             []string                 `json:"possible_keys,omitempty"`
+	Key                      string                   `json:"key,omitempty"`
+	UsedKeyParts             []string                 `json:"used_key_parts,omitempty"`
+	Ref                      []string                 `json:"ref,omitempty"`
+	RowsExaminedPerScan      *uint64                  `json:"rows_examined_per_scan,omitempty"`
+	JoinAlgorithm            string                   `json:"join_algorithm,omitempty"`
+	UsingJoinBuffer          string                   `json:"using_join_buffer,omitempty"`
+	AttachedCondition        string                   `json:"attached_condition,omitempty"`
+	MaterializedFromSubquery *explainMaterializedFrom `json:"materialized_from_subquery,omitempty"`
+
+	// indexes are the indexes of the table, of which the possible keys are those whose first column is compared by one
+	// of the conditions on the table, or is used to access it
+	indexes     []sql.Index
+	baseName    string
+	conditions  []sql.Expression
+	usedColumns []string
+}
+
+// explainMaterializedFrom describes the query block of a derived table.
+type explainMaterializedFrom struct {
+	UsingTemporaryTable bool               `json:"using_temporary_table"`
+	QueryBlock          *explainQueryBlock `json:"query_block"`
+}
+
+// explainUnionResult describes the query blocks of a union.
+type explainUnionResult struct {
+	UsingTemporaryTable bool                     `json:"using_temporary_table"`
+	TableName           string                   `json:"table_name"`
+	AccessType          string                   `json:"access_type"`
+	QuerySpecifications []*explainQuerySpecifier `json:"query_specifications"`
+}
+
+// explainQuerySpecifier is an element of the query_specifications of a union.
+type explainQuerySpecifier struct {
+	QueryBlock *explainQueryBlock `json:"query_block"`
+}
+
+// explainJSONBuilder converts a plan to the JSON format of EXPLAIN.
+type explainJSONBuilder struct {
+	ctx      *sql.Context
+	stats    sql.StatsReader
+	selectId int
+}
+
+// explainJSON returns the plan given in the JSON format of EXPLAIN, which is a "query_block" object describing the
+// tables that are read, how they are accessed and joined, and the operations applied to their rows. The number of
+// rows of each table is read from the statistics given, when not nil.
+func explainJSON(ctx *sql.Context, n sql.Node, stats sql.StatsReader) (string, error) {
+	b := &explainJSONBuilder{ctx: ctx, stats: stats}
+	doc := struct {
+		QueryBlock *explainQueryBlock `json:"query_block"`
+	}{b.queryBlock(n)}
+
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
+
+// queryBlock returns the query block of the node given, which is the root of a query or of a derived table.
+func (b *explainJSONBuilder) queryBlock(n sql.Node) *explainQueryBlock {
+	qb := &explainQueryBlock{}
+	switch n := unwrapExplainNode(n).(type) {
+	case *Union, *RecursiveCte:
+		b.fill(qb, n)
+	default:
+		b.selectId++
+		qb.SelectId = b.selectId
+		b.fill(qb, n)
+	}
+	return qb
+}
+
+// fill describes the node given in the query block given.
+func (b *explainJSONBuilder) fill(qb *explainQueryBlock, n sql.Node) {
+	switch n := unwrapExplainNode(n).(type) {
+	case *Sort:
+		qb.OrderingOperation = &explainQueryBlock{UsingFilesort: true}
+		b.fill(qb.OrderingOperation, n.Child)
+	case *TopN:
+		qb.OrderingOperation = &explainQueryBlock{UsingFilesort: true}
+		b.fill(qb.OrderingOperation, n.Child)
+	case *GroupBy:
+		qb.GroupingOperation = &explainQueryBlock{UsingTemporaryTable: true}
+		b.fill(qb.GroupingOperation, n.Child)
+	case *Distinct:
+		qb.DuplicatesRemoval = &explainQueryBlock{UsingTemporaryTable: true}
+		b.fill(qb.DuplicatesRemoval, n.Child)
+	case *OrderedDistinct:
+		qb.DuplicatesRemoval = &explainQueryBlock{}
+		b.fill(qb.DuplicatesRemoval, n.Child)
+	case *Union:
+		qb.UnionResult = b.unionResult(n.Distinct, n, false)
+	case *RecursiveCte:
+		qb.UnionResult = b.unionResult(n.union.Distinct, n.union, true)
+	default:
+		tables := b.tables(n)
+		for _, table := range tables {
+			table.PossibleKeys = possibleKeys(table)
+		}
+		switch len(tables) {
+		case 0:
+			qb.Message = "No tables used"
+		case 1:
+			qb.Table = tables[0]
+		default:
+			qb.NestedLoop = make([]*explainNestedTable, len(tables))
+			for i, table := range tables {
+				qb.NestedLoop[i] = &explainNestedTable{Table: table}
+			}
+		}
+	}
+}
+
+// unionResult returns the union_result of the union given. Nested unions are flattened into a single list of query
+// specifications. The query blocks of a recursive common table expression, except for the first one, are recursive.
+func (b *explainJSONBuilder) unionResult(distinct bool, u *Union, recursive bool) *explainUnionResult {
+	var specs []*explainQuerySpecifier
+	var flatten func(n sql.Node)
+	flatten = func(n sql.Node) {
+		if nested, ok := unwrapExplainNode(n).(*Union); ok && !recursive {
+			flatten(nested.Left())
+			flatten(nested.Right())
+			return
+		}
+		specs = append(specs, &explainQuerySpecifier{QueryBlock: b.queryBlock(n)})
+	}
+	flatten(u.Left())
+	flatten(u.Right())
+
+	ids := make([]string, len(specs))
+	for i, spec := range specs {
+		ids[i] = fmt.Sprintf("%d", spec.QueryBlock.SelectId)
+		if recursive && i > 0 {
+			spec.QueryBlock.Recursive = true
+		}
+	}
+	return &explainUnionResult{
+		UsingTemporaryTable: distinct,
+		TableName:           fmt.Sprintf("<union%s>", strings.Join(ids, ",")),
+		AccessType:          "ALL",
+		QuerySpecifications: specs,
+	}
+}
+
+// tables returns the tables read by the node given, in the order in which they are joined.
+func (b *explainJSONBuilder) tables(n sql.Node) []*explainTable {
+	switch n := unwrapExplainNode(n).(type) {
+	case *JoinNode:
+		left := b.tables(n.Left())
+		right := b.tables(n.Right())
+		if len(right) > 0 {
+			inner := right[0]
+			inner.JoinAlgorithm = explainJoinAlgorithm(n.Op)
+			if inner.JoinAlgorithm == "hash" {
+				inner.UsingJoinBuffer = "hash join"
+			}
+			if n.JoinCond() != nil && inner.JoinAlgorithm != "lookup" {
+				addAttachedCondition(right[len(right)-1], n.JoinCond())
+			}
+		}
+		tables := append(left, right...)
+		if n.JoinCond() != nil {
+			addCondition(tables, n.JoinCond())
+		}
+		return tables
+	case *Filter:
+		tables := b.tables(n.Child)
+		if len(tables) > 0 {
+			addAttachedCondition(tables[len(tables)-1], n.Expression)
+		}
+		addCondition(tables, n.Expression)
+		return tables
+	case *TableAlias:
+		tables := b.tables(n.Child)
+		if len(tables) == 1 {
+			tables[0].TableName = n.Name()
+		}
+		return tables
+	case *ResolvedTable:
+		if IsDualTable(n.Table) {
+			return nil
+		}
+		return []*explainTable{b.resolvedTable(n)}
+	case *IndexedTableAccess:
+		return []*explainTable{b.indexedTableAccess(n)}
+	case *SubqueryAlias:
+		return []*explainTable{{
+			TableName:  n.Name(),
+			AccessType: "ALL",
+			MaterializedFromSubquery: &explainMaterializedFrom{
+				UsingTemporaryTable: true,
+				QueryBlock:          b.queryBlock(n.Child),
+			},
+		}}
+	}
+
+	children := n.Children()
+	if len(children) == 0 {
+		name := strings.SplitN(n.String(), "\n", 2)[0]
+		if nameable, ok := n.(sql.Nameable); ok {
+			name = nameable.Name()
+		}
+		return []*explainTable{{TableName: name, AccessType: "ALL"}}
+	}
+	var tables []*explainTable
+	for _, child := range children {
+		tables = append(tables, b.tables(child)...)
+	}
+	return tables
+}
+
+// resolvedTable describes a full scan of the table given.
+func (b *explainJSONBuilder) resolvedTable(rt *ResolvedTable) *explainTable {
+	return &explainTable{
+		TableName:           rt.Name(),
+		AccessType:          "ALL",
+		RowsExaminedPerScan: b.rowCount(rt),
+		indexes:             b.indexes(rt),
+		baseName:            rt.Name(),
+	}
+}
+
+// indexedTableAccess describes the access to a table through one of its indexes. The access type is "eq_ref" or
+// "ref" for the lookups of a join, depending on whether a single row is returned for each lookup, "const" for a
+// lookup of a single row, "ref" for a lookup of the rows equal to constants, "range" for lookups of ranges of the
+// index, and "index" for full scans of the index.
+func (b *explainJSONBuilder) indexedTableAccess(ita *IndexedTableAccess) *explainTable {
+	idx := ita.Index()
+	table := &explainTable{
+		TableName:           ita.Name(),
+		Key:                 explainIndexName(idx),
+		RowsExaminedPerScan: b.rowCount(ita.ResolvedTable),
+		indexes:             b.indexes(ita.ResolvedTable),
+		baseName:            ita.ResolvedTable.Name(),
+	}
+	keyParts := explainKeyParts(idx)
+
+	if ita.IsStatic() {
+		used, points := explainStaticKeyParts(ita.lookup.Ranges)
+		switch {
+		case used == 0:
+			table.AccessType = "index"
+			table.UsedKeyParts = keyParts
+		case points == used && len(ita.lookup.Ranges) == 1:
+			table.AccessType = "ref"
+			if idx.IsUnique() && used == len(keyParts) {
+				table.AccessType = "const"
+			}
+			table.UsedKeyParts = keyParts[:used]
+			for i := 0; i < used; i++ {
+				table.Ref = append(table.Ref, "const")
+			}
+		default:
+			table.AccessType = "range"
+			table.UsedKeyParts = keyParts[:used]
+		}
+		if used > 0 {
+			table.usedColumns = table.UsedKeyParts
+		}
+		return table
+	}
+
+	used := len(ita.lb.keyExprs)
+	if used > len(keyParts) {
+		used = len(keyParts)
+	}
+	table.UsedKeyParts = keyParts[:used]
+	table.usedColumns = table.UsedKeyParts
+	table.AccessType = "ref"
+	if idx.IsUnique() && used == len(keyParts) {
+		table.AccessType = "eq_ref"
+	}
+	for _, e := range ita.lb.keyExprs {
+		table.Ref = append(table.Ref, e.String())
+	}
+	return table
+}
+
+// indexes returns the indexes of the table given, other than the ones generated by the engine.
+func (b *explainJSONBuilder) indexes(rt *ResolvedTable) []sql.Index {
+	table := rt.Table
+	for {
+		wrapper, ok := table.(sql.TableWrapper)
+		if !ok {
+			break
+		}
+		table = wrapper.Underlying()
+	}
+	addressable, ok := table.(sql.IndexAddressable)
+	if !ok {
+		return nil
+	}
+	indexes, err := addressable.GetIndexes(b.ctx)
+	if err != nil {
+		return nil
+	}
+	var result []sql.Index
+	for _, idx := range indexes {
+		if !idx.IsGenerated() {
+			result = append(result, idx)
+		}
+	}
+	return result
+}
+
+// possibleKeys returns the names of the indexes of the table given that could be used to access it: those whose first
+// column is used to access the table, or is referenced by a condition on the table.
+func possibleKeys(table *explainTable) []string {
+	columns := make(map[string]bool)
+	for _, col := range table.usedColumns {
+		columns[strings.ToLower(col)] = true
+	}
+	for _, cond := range table.conditions {
+		sql.Inspect(cond, func(e sql.Expression) bool {
+			if gf, ok := e.(*expression.GetField); ok &&
+				(strings.EqualFold(gf.Table(), table.TableName) || strings.EqualFold(gf.Table(), table.baseName)) {
+				columns[strings.ToLower(gf.Name())] = true
+			}
+			return true
+		})
+	}
+
+	var keys []string
+	for _, idx := range table.indexes {
+		if parts := explainKeyParts(idx); len(parts) > 0 && columns[strings.ToLower(parts[0])] {
+			keys = append(keys, explainIndexName(idx))
+		}
+	}
+	return keys
+}
+
+// rowCount returns the number of rows of the table given, or nil if it is unknown.
+func (b *explainJSONBuilder) rowCount(rt *ResolvedTable) *uint64 {
+	if b.stats == nil || rt.Database == nil {
+		return nil
+	}
+	cnt, ok, err := b.stats.RowCount(b.ctx, rt.Database.Name(), rt.Name())
+	if err != nil || !ok {
+		return nil
+	}
+	return &cnt
+}
+
+// unwrapExplainNode returns the node that is described in place of the node given, skipping the nodes that do not
+// appear in the JSON format of EXPLAIN.
+func unwrapExplainNode(n sql.Node) sql.Node {
+	for {
+		switch nn := n.(type) {
+		case *Project, *Limit, *Offset, *Having, *Window, *QueryProcess, *TransactionCommittingNode,
+			*Exchange, *CachedResults, *HashLookup:
+			n = nn.Children()[0]
+		default:
+			return n
+		}
+	}
+}
+
+// addAttachedCondition adds the condition given to the conditions attached to the table given.
+func addAttachedCondition(table *explainTable, cond sql.Expression) {
+	if table.AttachedCondition == "" {
+		table.AttachedCondition = cond.String()
+	} else {
+		table.AttachedCondition = fmt.Sprintf("(%s AND %s)", table.AttachedCondition, cond.String())
+	}
+}
+
+// addCondition adds the condition given to the conditions on the tables given, which determine their possible keys.
+func addCondition(tables []*explainTable, cond sql.Expression) {
+	for _, table := range tables {
+		table.conditions = append(table.conditions, cond)
+	}
+}
+
+// explainJoinAlgorithm returns the algorithm used by the join type given.
+func explainJoinAlgorithm(op JoinType) string {
+	switch op {
+	case JoinTypeHash, JoinTypeLeftOuterHash, JoinTypeSemiHash, JoinTypeAntiHash:
+		return "hash"
+	case JoinTypeMerge, JoinTypeLeftOuterMerge, JoinTypeSemiMerge, JoinTypeAntiMerge:
+		return "merge"
+	case JoinTypeLookup, JoinTypeLeftOuterLookup, JoinTypeSemiLookup, JoinTypeAntiLookup, JoinTypeRightSemiLookup:
+		return "lookup"
+	default:
+		return "nested_loop"
+	}
+}
+
+// explainIndexName returns the name of the index given, as shown by MySQL.
+func explainIndexName(idx sql.Index) string {
+	if strings.EqualFold(idx.ID(), "primary") {
+		return "PRIMARY"
+	}
+	return idx.ID()
+}
+
+// explainKeyParts returns the names of the columns of the index given, without their table.
+func explainKeyParts(idx sql.Index) []string {
+	exprs := idx.Expressions()
+	parts := make([]string, len(exprs))
+	for i, expr := range exprs {
+		parts[i] = expr[strings.LastIndex(expr, ".")+1:]
+	}
+	return parts
+}
+
+// explainStaticKeyParts returns the number of columns of an index that are used by the ranges given, along with the
+// number of those columns that are compared for equality in every range.
+func explainStaticKeyParts(ranges sql.RangeCollection) (used int, points int) {
+	points = -1
+	for _, rang := range ranges {
+		rangeUsed, rangePoints := 0, 0
+		for i, col := range rang {
+			if col.Type() != sql.RangeType_All {
+				rangeUsed = i + 1
+			}
+			if eq, err := col.RepresentsEquals(); err == nil && eq && rangePoints == i {
+				rangePoints = i + 1
+			}
+		}
+		if rangeUsed > used {
+			used = rangeUsed
+		}
+		if points == -1 || rangePoints < points {
+			points = rangePoints
+		}
+	}
+	if points == -1 {
+		points = 0
+	}
+	return used, points
+}
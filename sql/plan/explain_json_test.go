@@ -0,0 +1,275 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+func TestExplainJSON(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	foo := memory.NewTable("foo", sql.NewPrimaryKeySchema(sql.Schema{
+		{Source: "foo", Name: "a", Type: types.Int64, PrimaryKey: true},
+		{Source: "foo", Name: "b", Type: types.Text},
+	}), nil)
+	bar := memory.NewTable("bar", sql.NewPrimaryKeySchema(sql.Schema{
+		{Source: "bar", Name: "c", Type: types.Int64, PrimaryKey: true},
+		{Source: "bar", Name: "d", Type: types.Int64},
+	}), nil)
+	bar.EnablePrimaryKeyIndexes()
+
+	indexes, err := bar.GetIndexes(ctx)
+	require.NoError(err)
+	barD := expression.NewGetFieldWithTable(3, types.Int64, "bar", "d", false)
+	ita, err := NewIndexedAccessForResolvedTable(
+		NewResolvedTable(bar, nil, nil),
+		NewLookupBuilder(indexes[0], []sql.Expression{expression.NewGetFieldWithTable(0, types.Int64, "foo", "a", false)}, []bool{false}),
+	)
+	require.NoError(err)
+
+	node := NewSort(
+		[]sql.SortField{{Column: expression.NewGetFieldWithTable(1, types.Text, "foo", "b", false)}},
+		NewJoin(
+			NewFilter(
+				expression.NewEquals(
+					expression.NewGetFieldWithTable(1, types.Text, "foo", "b", false),
+					expression.NewLiteral("x", types.LongText),
+				),
+				NewResolvedTable(foo, nil, nil),
+			),
+			NewFilter(
+				expression.NewGreaterThan(barD, expression.NewLiteral(int64(1), types.Int64)),
+				NewTableAlias("b", ita),
+			),
+			JoinTypeLookup,
+			expression.NewEquals(
+				expression.NewGetFieldWithTable(0, types.Int64, "foo", "a", false),
+				expression.NewGetFieldWithTable(2, types.Int64, "bar", "c", false),
+			),
+		),
+	)
+
+	iter, err := NewDescribeQuery("json", node).RowIter(ctx, nil)
+	require.NoError(err)
+	rows, err := sql.RowIterToRows(ctx, nil, iter)
+	require.NoError(err)
+	require.Len(rows, 1)
+
+	require.Equal(`{
+  "query_block": {
+    "select_id": 1,
+    "ordering_operation": {
+      "using_filesort": true,
+      "nested_loop": [
+        {
+          "table": {
+            "table_name": "foo",
+            "access_type": "ALL",
+            "attached_condition": "(foo.b = 'x')"
+          }
+        },
+        {
+          "table": {
+            "table_name": "b",
+            "access_type": "eq_ref",
+            "possible_keys": [
+              "PRIMARY"
+            ],
+            "key": "PRIMARY",
+            "used_key_parts": [
+              "c"
+            ],
+            "ref": [
+              "foo.a"
+            ],
+            "join_algorithm": "lookup",
+            "attached_condition": "(bar.d > 1)"
+          }
+        }
+      ]
+    }
+  }
+}`, rows[0][0])
+}
+
+func TestExplainJSONUnion(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	table := memory.NewTable("foo", sql.NewPrimaryKeySchema(sql.Schema{
+		{Source: "foo", Name: "a", Type: types.Int64},
+	}), nil)
+
+	node := NewUnion(
+		NewUnion(
+			NewResolvedTable(table, nil, nil),
+			NewHashJoin(
+				NewResolvedTable(table, nil, nil),
+				NewResolvedTable(table, nil, nil),
+				expression.NewLiteral(true, types.Boolean),
+			),
+			false, nil, nil,
+		),
+		NewProject([]sql.Expression{expression.NewLiteral(int64(1), types.Int64)}, NewResolvedTable(NewDualSqlTable(), nil, nil)),
+		true, nil, nil,
+	)
+
+	str, err := explainJSON(ctx, node, nil)
+	require.NoError(err)
+	require.Equal(`{
+  "query_block": {
+    "union_result": {
+      "using_temporary_table": true,
+      "table_name": "<union1,2,3>",
+      "access_type": "ALL",
+      "query_specifications": [
+        {
+          "query_block": {
+            "select_id": 1,
+            "table": {
+              "table_name": "foo",
+              "access_type": "ALL"
+            }
+          }
+        },
+        {
+          "query_block": {
+            "select_id": 2,
+            "nested_loop": [
+              {
+                "table": {
+                  "table_name": "foo",
+                  "access_type": "ALL"
+                }
+              },
+              {
+                "table": {
+                  "table_name": "foo",
+                  "access_type": "ALL",
+                  "join_algorithm": "hash",
+                  "using_join_buffer": "hash join",
+                  "attached_condition": "true"
+                }
+              }
+            ]
+          }
+        },
+        {
+          "query_block": {
+            "select_id": 3,
+            "message": "No tables used"
+          }
+        }
+      ]
+    }
+  }
+}`, str)
+}
+
+func TestExplainJSONStaticLookup(t *testing.T) {
+	require := require.New(t)
+	ctx := sql.NewEmptyContext()
+
+	table := memory.NewTable("foo", sql.NewPrimaryKeySchema(sql.Schema{
+		{Source: "foo", Name: "a", Type: types.Int64, PrimaryKey: true},
+		{Source: "foo", Name: "b", Type: types.Int64},
+		{Source: "foo", Name: "c", Type: types.Int64},
+	}), nil)
+	table.EnablePrimaryKeyIndexes()
+	for _, col := range []string{"b", "c"} {
+		require.NoError(table.CreateIndex(ctx, sql.IndexDef{
+			Name:       col + "k",
+			Columns:    []sql.IndexColumn{{Name: col}},
+			Constraint: sql.IndexConstraint_None,
+			Storage:    sql.IndexUsing_BTree,
+		}))
+	}
+	indexes, err := table.GetIndexes(ctx)
+	require.NoError(err)
+	var bk sql.Index
+	for _, idx := range indexes {
+		if idx.ID() == "bk" {
+			bk = idx
+		}
+	}
+
+	lookup := func(ranges ...sql.Range) sql.Node {
+		ita, err := NewStaticIndexedAccessForResolvedTable(
+			NewResolvedTable(table, nil, nil),
+			sql.IndexLookup{Index: bk, Ranges: ranges},
+		)
+		require.NoError(err)
+		return ita
+	}
+
+	// The rows equal to a constant are a ref access, for which only the indexes on the compared columns are possible
+	str, err := explainJSON(ctx, lookup(sql.Range{sql.ClosedRangeColumnExpr(int64(1), int64(1), types.Int64)}), nil)
+	require.NoError(err)
+	require.Equal(`{
+  "query_block": {
+    "select_id": 1,
+    "table": {
+      "table_name": "foo",
+      "access_type": "ref",
+      "possible_keys": [
+        "bk"
+      ],
+      "key": "bk",
+      "used_key_parts": [
+        "b"
+      ],
+      "ref": [
+        "const"
+      ]
+    }
+  }
+}`, str)
+
+	str, err = explainJSON(ctx, NewFilter(
+		expression.NewEquals(expression.NewGetFieldWithTable(2, types.Int64, "foo", "c", false), expression.NewLiteral(int64(3), types.Int64)),
+		lookup(
+			sql.Range{sql.ClosedRangeColumnExpr(int64(1), int64(1), types.Int64)},
+			sql.Range{sql.ClosedRangeColumnExpr(int64(2), int64(2), types.Int64)},
+		),
+	), nil)
+	require.NoError(err)
+	require.Equal(`{
+  "query_block": {
+    "select_id": 1,
+    "table": {
+      "table_name": "foo",
+      "access_type": "range",
+      "possible_keys": [
+        "bk",
+        "ck"
+      ],
+      "key": "bk",
+      "used_key_parts": [
+        "b"
+      ],
+      "attached_condition": "(foo.c = 3)"
+    }
+  }
+}`, str)
+}
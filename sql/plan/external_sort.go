@@ -0,0 +1,272 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"container/heap"
+	"io"
+	"sort"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+)
+
+// sortSpillMinRows is the smallest number of rows that a sort writes to disk when it runs out of memory. Fewer rows
+// are kept in memory, as writing them wouldn't free much of it.
+var sortSpillMinRows = 1024
+
+// sortMergeFanIn is the largest number of sorted runs that are merged at once. When there are more runs, they are
+// merged in several passes.
+var sortMergeFanIn = 64
+
+// sortRun is a sequence of sorted rows written to a spill file, between two offsets of the file.
+type sortRun struct {
+	start, end int64
+}
+
+// externalSort sorts more rows than fit in memory. The rows are given in batches, each of which is sorted and written
+// to a temporary file as a run, and the runs are then merged. The sort is stable, like the in-memory one.
+type externalSort struct {
+	sortFields sql.SortFields
+	file       *sql.SpillFile
+	runs       []sortRun
+}
+
+func newExternalSort(sortFields sql.SortFields) *externalSort {
+	return &externalSort{sortFields: sortFields}
+}
+
+// sortRows sorts the rows given in memory.
+func sortRows(ctx *sql.Context, sortFields sql.SortFields, rows []sql.Row) error {
+	sorter := &expression.Sorter{
+		SortFields: sortFields,
+		Rows:       rows,
+		LastError:  nil,
+		Ctx:        ctx,
+	}
+	sort.Stable(sorter)
+	return sorter.LastError
+}
+
+// writeRun sorts the rows given and writes them to disk as a new run. The rows must follow the ones of the previous
+// runs in the input.
+func (s *externalSort) writeRun(ctx *sql.Context, rows []sql.Row) error {
+	if err := sortRows(ctx, s.sortFields, rows); err != nil {
+		return err
+	}
+	if s.file == nil {
+		var err error
+		if s.file, err = sql.NewSpillFile(ctx); err != nil {
+			return err
+		}
+	}
+	start := s.file.Size()
+	for _, row := range rows {
+		if err := s.file.Write(row); err != nil {
+			return err
+		}
+	}
+	s.runs = append(s.runs, sortRun{start: start, end: s.file.Size()})
+	return nil
+}
+
+// iter returns an iterator over the rows of all runs, in order. Once there are too many runs to merge them at once,
+// consecutive runs are merged into longer ones first.
+func (s *externalSort) iter(ctx *sql.Context) (sql.RowIter, error) {
+	for len(s.runs) > sortMergeFanIn {
+		file, err := sql.NewSpillFile(ctx)
+		if err != nil {
+			return nil, err
+		}
+		var runs []sortRun
+		for start := 0; start < len(s.runs); start += sortMergeFanIn {
+			end := start + sortMergeFanIn
+			if end > len(s.runs) {
+				end = len(s.runs)
+			}
+			runStart := file.Size()
+			if err := s.mergeInto(ctx, s.runs[start:end], file); err != nil {
+				_ = file.Close()
+				return nil, err
+			}
+			runs = append(runs, sortRun{start: runStart, end: file.Size()})
+		}
+		if err := s.file.Close(); err != nil {
+			_ = file.Close()
+			return nil, err
+		}
+		s.file, s.runs = file, runs
+	}
+	return s.merge(ctx, s.runs)
+}
+
+// mergeInto merges the runs given, writing the result to the file given.
+func (s *externalSort) mergeInto(ctx *sql.Context, runs []sortRun, file *sql.SpillFile) error {
+	iter, err := s.merge(ctx, runs)
+	if err != nil {
+		return err
+	}
+	for {
+		row, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			_ = iter.Close(ctx)
+			return err
+		}
+		if err := file.Write(row); err != nil {
+			_ = iter.Close(ctx)
+			return err
+		}
+	}
+	return iter.Close(ctx)
+}
+
+// merge returns an iterator merging the runs given.
+func (s *externalSort) merge(ctx *sql.Context, runs []sortRun) (sql.RowIter, error) {
+	iters := make([]sql.RowIter, len(runs))
+	for i, run := range runs {
+		var err error
+		if iters[i], err = s.file.Section(run.start, run.end); err != nil {
+			return nil, err
+		}
+	}
+	return newSortMergeIter(ctx, s.sortFields, iters), nil
+}
+
+// close removes the temporary file of the sort.
+func (s *externalSort) close() error {
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// sortMergeIter merges sorted runs of rows. It's a heap of the indexes of the runs that still have rows, ordered by
+// their next row. Ties are broken by the index of the run, so that rows keep the order of the input.
+type sortMergeIter struct {
+	sorter      *expression.Sorter
+	iters       []sql.RowIter
+	heads       []sql.Row
+	runs        []int
+	initialized bool
+}
+
+var _ sql.RowIter = (*sortMergeIter)(nil)
+var _ heap.Interface = (*sortMergeIter)(nil)
+
+// newSortMergeIter returns an iterator merging the rows of the iterators given, each of which returns its rows in the
+// order of the sort fields given.
+func newSortMergeIter(ctx *sql.Context, sortFields sql.SortFields, iters []sql.RowIter) *sortMergeIter {
+	return &sortMergeIter{
+		sorter: &expression.Sorter{
+			SortFields: sortFields,
+			Rows:       make([]sql.Row, 2),
+			Ctx:        ctx,
+		},
+		iters: iters,
+		heads: make([]sql.Row, len(iters)),
+	}
+}
+
+// Next implements the sql.RowIter interface.
+func (m *sortMergeIter) Next(ctx *sql.Context) (sql.Row, error) {
+	if !m.initialized {
+		m.initialized = true
+		for i := range m.iters {
+			if err := m.advance(ctx, i); err == io.EOF {
+				continue
+			} else if err != nil {
+				return nil, err
+			}
+			m.runs = append(m.runs, i)
+		}
+		heap.Init(m)
+		if m.sorter.LastError != nil {
+			return nil, m.sorter.LastError
+		}
+	}
+
+	if len(m.runs) == 0 {
+		return nil, io.EOF
+	}
+	run := m.runs[0]
+	row := m.heads[run]
+	if err := m.advance(ctx, run); err == io.EOF {
+		heap.Pop(m)
+	} else if err != nil {
+		return nil, err
+	} else {
+		heap.Fix(m, 0)
+	}
+	if m.sorter.LastError != nil {
+		return nil, m.sorter.LastError
+	}
+	return row, nil
+}
+
+// advance reads the next row of the run given.
+func (m *sortMergeIter) advance(ctx *sql.Context, run int) error {
+	row, err := m.iters[run].Next(ctx)
+	if err != nil {
+		return err
+	}
+	m.heads[run] = row
+	return nil
+}
+
+// Close implements the sql.RowIter interface.
+func (m *sortMergeIter) Close(ctx *sql.Context) error {
+	var err error
+	for _, iter := range m.iters {
+		if cerr := iter.Close(ctx); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Len implements the heap.Interface interface.
+func (m *sortMergeIter) Len() int {
+	return len(m.runs)
+}
+
+// Less implements the heap.Interface interface.
+func (m *sortMergeIter) Less(i, j int) bool {
+	m.sorter.Rows[0], m.sorter.Rows[1] = m.heads[m.runs[i]], m.heads[m.runs[j]]
+	if m.sorter.Less(0, 1) {
+		return true
+	}
+	return !m.sorter.Less(1, 0) && m.runs[i] < m.runs[j]
+}
+
+// Swap implements the heap.Interface interface.
+func (m *sortMergeIter) Swap(i, j int) {
+	m.runs[i], m.runs[j] = m.runs[j], m.runs[i]
+}
+
+// Push implements the heap.Interface interface.
+func (m *sortMergeIter) Push(x interface{}) {
+	m.runs = append(m.runs, x.(int))
+}
+
+// Pop implements the heap.Interface interface.
+func (m *sortMergeIter) Pop() interface{} {
+	run := m.runs[len(m.runs)-1]
+	m.runs = m.runs[:len(m.runs)-1]
+	return run
+}
@@ -15,6 +15,9 @@
 package plan
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/dolthub/go-mysql-server/sql"
 	"github.com/dolthub/go-mysql-server/sql/mysql_db"
 	"github.com/dolthub/go-mysql-server/sql/types"
@@ -95,3 +98,261 @@ func (f *FlushPrivileges) WithDatabase(db sql.Database) (sql.Node, error) {
 	fp.mysqlDb = db
 	return &fp, nil
 }
+
+// FlushTables flushes tables through the database provider. With a read lock, it takes the global read lock when no
+// tables are given, or locks the tables given for reads as LOCK TABLES does. Either lock is released with UNLOCK TABLES.
+type FlushTables struct {
+	// Tables are the tables to flush, or every table if empty.
+	Tables   []sql.Node
+	ReadLock bool
+	Catalog  sql.Catalog
+	// GlobalReadLock is the lock taken by FLUSH TABLES WITH READ LOCK.
+	GlobalReadLock *sql.GlobalReadLock
+	provider       sql.DatabaseProvider
+}
+
+var _ sql.Node = (*FlushTables)(nil)
+var _ sql.MultiDatabaser = (*FlushTables)(nil)
+
+// NewFlushTables creates a new FlushTables node.
+func NewFlushTables(tables []sql.Node, readLock bool) *FlushTables {
+	return &FlushTables{Tables: tables, ReadLock: readLock}
+}
+
+// RowIter implements the interface sql.Node.
+func (f *FlushTables) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	if f.ReadLock {
+		if err := f.lock(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if flusher, ok := f.provider.(sql.TableFlusher); ok {
+		var tables []sql.DbTable
+		for _, t := range f.Tables {
+			tables = append(tables, sql.NewDbTable(getDatabaseName(t), getTableName(t)))
+		}
+		if err := flusher.FlushTables(ctx, tables); err != nil {
+			if f.ReadLock {
+				f.unlock(ctx)
+			}
+			return nil, err
+		}
+	}
+
+	return sql.RowsToRowIter(sql.Row{types.NewOkResult(0)}), nil
+}
+
+// lock takes the global read lock, or read locks on the tables to flush.
+func (f *FlushTables) lock(ctx *sql.Context) error {
+	if len(f.Tables) == 0 {
+		return f.GlobalReadLock.Lock(ctx)
+	}
+	locks := make([]*TableLock, len(f.Tables))
+	for i, t := range f.Tables {
+		locks[i] = &TableLock{Table: t}
+	}
+	return lockTables(ctx, f.Catalog, locks)
+}
+
+// unlock releases the locks taken by lock.
+func (f *FlushTables) unlock(ctx *sql.Context) {
+	if len(f.Tables) == 0 {
+		f.GlobalReadLock.Unlock(ctx.ID())
+	} else if err := f.Catalog.UnlockTables(ctx, ctx.ID()); err != nil {
+		ctx.GetLogger().Warnf("unable to unlock tables: %s", err.Error())
+	}
+}
+
+// String implements the interface sql.Node.
+func (f *FlushTables) String() string {
+	var sb strings.Builder
+	sb.WriteString("FLUSH TABLES")
+	for i, t := range f.Tables {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		sb.WriteString(" ")
+		sb.WriteString(getTableName(t))
+	}
+	if f.ReadLock {
+		sb.WriteString(" WITH READ LOCK")
+	}
+	return sb.String()
+}
+
+// WithChildren implements the interface sql.Node.
+func (f *FlushTables) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != len(f.Tables) {
+		return nil, sql.ErrInvalidChildrenNumber.New(f, len(children), len(f.Tables))
+	}
+
+	nf := *f
+	nf.Tables = children
+	return &nf, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (f *FlushTables) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	if !opChecker.UserHasPrivileges(ctx, sql.NewPrivilegedOperation("", "", "", sql.PrivilegeType_Reload)) {
+		return false
+	}
+	if !f.ReadLock || len(f.Tables) == 0 {
+		return true
+	}
+	operations := make([]sql.PrivilegedOperation, len(f.Tables))
+	for i, t := range f.Tables {
+		operations[i] = sql.NewPrivilegedOperation(getDatabaseName(t), getTableName(t), "",
+			sql.PrivilegeType_Select, sql.PrivilegeType_LockTables)
+	}
+	return opChecker.UserHasPrivileges(ctx, operations...)
+}
+
+// Resolved implements the interface sql.Node.
+func (f *FlushTables) Resolved() bool {
+	for _, t := range f.Tables {
+		if !t.Resolved() {
+			return false
+		}
+	}
+	return f.provider != nil
+}
+
+// Children implements the sql.Node interface.
+func (f *FlushTables) Children() []sql.Node { return f.Tables }
+
+// Schema implements the sql.Node interface.
+func (*FlushTables) Schema() sql.Schema { return types.OkResultSchema }
+
+// DatabaseProvider implements the sql.MultiDatabaser interface.
+func (f *FlushTables) DatabaseProvider() sql.DatabaseProvider {
+	return f.provider
+}
+
+// WithDatabaseProvider implements the sql.MultiDatabaser interface.
+func (f *FlushTables) WithDatabaseProvider(provider sql.DatabaseProvider) (sql.Node, error) {
+	nf := *f
+	nf.provider = provider
+	return &nf, nil
+}
+
+// FlushStatus resets the status counters of the session through the database provider.
+type FlushStatus struct {
+	provider sql.DatabaseProvider
+}
+
+var _ sql.Node = (*FlushStatus)(nil)
+var _ sql.MultiDatabaser = (*FlushStatus)(nil)
+
+// NewFlushStatus creates a new FlushStatus node.
+func NewFlushStatus() *FlushStatus {
+	return &FlushStatus{}
+}
+
+// RowIter implements the interface sql.Node.
+func (f *FlushStatus) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	if flusher, ok := f.provider.(sql.StatusFlusher); ok {
+		if err := flusher.FlushStatus(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return sql.RowsToRowIter(sql.Row{types.NewOkResult(0)}), nil
+}
+
+// String implements the interface sql.Node.
+func (*FlushStatus) String() string { return "FLUSH STATUS" }
+
+// WithChildren implements the interface sql.Node.
+func (f *FlushStatus) WithChildren(children ...sql.Node) (sql.Node, error) {
+	return NillaryWithChildren(f, children...)
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (f *FlushStatus) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return opChecker.UserHasPrivileges(ctx, sql.NewPrivilegedOperation("", "", "", sql.PrivilegeType_Reload))
+}
+
+// Resolved implements the interface sql.Node.
+func (f *FlushStatus) Resolved() bool { return f.provider != nil }
+
+// Children implements the sql.Node interface.
+func (*FlushStatus) Children() []sql.Node { return nil }
+
+// Schema implements the sql.Node interface.
+func (*FlushStatus) Schema() sql.Schema { return types.OkResultSchema }
+
+// DatabaseProvider implements the sql.MultiDatabaser interface.
+func (f *FlushStatus) DatabaseProvider() sql.DatabaseProvider {
+	return f.provider
+}
+
+// WithDatabaseProvider implements the sql.MultiDatabaser interface.
+func (f *FlushStatus) WithDatabaseProvider(provider sql.DatabaseProvider) (sql.Node, error) {
+	nf := *f
+	nf.provider = provider
+	return &nf, nil
+}
+
+// FlushLogs flushes logs through the database provider.
+type FlushLogs struct {
+	// Kind is the kind of logs to flush, such as "binary" or "slow", or empty to flush every log.
+	Kind     string
+	provider sql.DatabaseProvider
+}
+
+var _ sql.Node = (*FlushLogs)(nil)
+var _ sql.MultiDatabaser = (*FlushLogs)(nil)
+
+// NewFlushLogs creates a new FlushLogs node.
+func NewFlushLogs(kind string) *FlushLogs {
+	return &FlushLogs{Kind: kind}
+}
+
+// RowIter implements the interface sql.Node.
+func (f *FlushLogs) RowIter(ctx *sql.Context, _ sql.Row) (sql.RowIter, error) {
+	if flusher, ok := f.provider.(sql.LogFlusher); ok {
+		if err := flusher.FlushLogs(ctx, f.Kind); err != nil {
+			return nil, err
+		}
+	}
+	return sql.RowsToRowIter(sql.Row{types.NewOkResult(0)}), nil
+}
+
+// String implements the interface sql.Node.
+func (f *FlushLogs) String() string {
+	if f.Kind == "" {
+		return "FLUSH LOGS"
+	}
+	return fmt.Sprintf("FLUSH %s LOGS", strings.ToUpper(f.Kind))
+}
+
+// WithChildren implements the interface sql.Node.
+func (f *FlushLogs) WithChildren(children ...sql.Node) (sql.Node, error) {
+	return NillaryWithChildren(f, children...)
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (f *FlushLogs) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return opChecker.UserHasPrivileges(ctx, sql.NewPrivilegedOperation("", "", "", sql.PrivilegeType_Reload))
+}
+
+// Resolved implements the interface sql.Node.
+func (f *FlushLogs) Resolved() bool { return f.provider != nil }
+
+// Children implements the sql.Node interface.
+func (*FlushLogs) Children() []sql.Node { return nil }
+
+// Schema implements the sql.Node interface.
+func (*FlushLogs) Schema() sql.Schema { return types.OkResultSchema }
+
+// DatabaseProvider implements the sql.MultiDatabaser interface.
+func (f *FlushLogs) DatabaseProvider() sql.DatabaseProvider {
+	return f.provider
+}
+
+// WithDatabaseProvider implements the sql.MultiDatabaser interface.
+func (f *FlushLogs) WithDatabaseProvider(provider sql.DatabaseProvider) (sql.Node, error) {
+	nf := *f
+	nf.provider = provider
+	return &nf, nil
+}
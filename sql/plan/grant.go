@@ -173,8 +173,20 @@ func (n *Grant) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperat
 		}
 		return opChecker.UserHasPrivileges(ctx, sql.NewPrivilegedOperation(database, "", "",
 			convertToSqlPrivilegeType(true, n.Privileges...)...))
+	} else if n.ObjectType == ObjectType_Procedure || n.ObjectType == ObjectType_Function {
+		isProcedure := n.ObjectType == ObjectType_Procedure
+		if n.Privileges[0].Type == PrivilegeType_All {
+			return opChecker.UserHasPrivileges(ctx,
+				sql.NewPrivilegedOperationForRoutine(n.PrivilegeLevel.Database, n.PrivilegeLevel.TableRoutine, isProcedure,
+					sql.PrivilegeType_AlterRoutine,
+					sql.PrivilegeType_Execute,
+					sql.PrivilegeType_GrantOption,
+				))
+		}
+		return opChecker.UserHasPrivileges(ctx,
+			sql.NewPrivilegedOperationForRoutine(n.PrivilegeLevel.Database, n.PrivilegeLevel.TableRoutine, isProcedure,
+				convertToSqlPrivilegeType(true, n.Privileges...)...))
 	} else {
-		//TODO: add column checks
 		if n.Privileges[0].Type == PrivilegeType_All {
 			return opChecker.UserHasPrivileges(ctx,
 				sql.NewPrivilegedOperation(n.PrivilegeLevel.Database, n.PrivilegeLevel.TableRoutine, "",
@@ -193,9 +205,7 @@ func (n *Grant) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperat
 					sql.PrivilegeType_GrantOption,
 				))
 		}
-		return opChecker.UserHasPrivileges(ctx,
-			sql.NewPrivilegedOperation(n.PrivilegeLevel.Database, n.PrivilegeLevel.TableRoutine, "",
-				convertToSqlPrivilegeType(true, n.Privileges...)...))
+		return opChecker.UserHasPrivileges(ctx, tablePrivilegedOperations(n.PrivilegeLevel, n.Privileges)...)
 	}
 }
 
@@ -258,24 +268,39 @@ func (n *Grant) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 				return nil, sql.ErrNoDatabaseSelected.New()
 			}
 		}
-		if n.ObjectType != ObjectType_Any {
-			//TODO: implement object types
-			return nil, fmt.Errorf("GRANT has not yet implemented object types")
-		}
 		if n.As != nil {
 			return nil, fmt.Errorf("GRANT has not yet implemented user assumption")
 		}
-		for _, grantUser := range n.Users {
-			user := mysqlDb.GetUser(grantUser.Name, grantUser.Host, false)
-			if user == nil {
-				return nil, sql.ErrGrantUserDoesNotExist.New()
-			}
-			if err := n.handleTablePrivileges(user, database, n.PrivilegeLevel.TableRoutine); err != nil {
-				return nil, err
+		switch n.ObjectType {
+		case ObjectType_Any, ObjectType_Table:
+			for _, grantUser := range n.Users {
+				user := mysqlDb.GetUser(grantUser.Name, grantUser.Host, false)
+				if user == nil {
+					return nil, sql.ErrGrantUserDoesNotExist.New()
+				}
+				if err := n.handleTablePrivileges(user, database, n.PrivilegeLevel.TableRoutine); err != nil {
+					return nil, err
+				}
+				if n.WithGrantOption {
+					user.PrivilegeSet.AddTable(database, n.PrivilegeLevel.TableRoutine, sql.PrivilegeType_GrantOption)
+				}
 			}
-			if n.WithGrantOption {
-				user.PrivilegeSet.AddTable(database, n.PrivilegeLevel.TableRoutine, sql.PrivilegeType_GrantOption)
+		case ObjectType_Procedure, ObjectType_Function:
+			isProcedure := n.ObjectType == ObjectType_Procedure
+			for _, grantUser := range n.Users {
+				user := mysqlDb.GetUser(grantUser.Name, grantUser.Host, false)
+				if user == nil {
+					return nil, sql.ErrGrantUserDoesNotExist.New()
+				}
+				if err := n.handleRoutinePrivileges(user, database, n.PrivilegeLevel.TableRoutine, isProcedure); err != nil {
+					return nil, err
+				}
+				if n.WithGrantOption {
+					user.PrivilegeSet.AddRoutine(database, n.PrivilegeLevel.TableRoutine, isProcedure, sql.PrivilegeType_GrantOption)
+				}
 			}
+		default:
+			return nil, sql.ErrGrantRevokeIllegalPrivilege.New()
 		}
 	}
 	if err := mysqlDb.Persist(ctx); err != nil {
@@ -526,7 +551,10 @@ func (n *Grant) handleDatabasePrivileges(user *mysql_db.User, dbName string) err
 func (n *Grant) handleTablePrivileges(user *mysql_db.User, dbName string, tblName string) error {
 	for i, priv := range n.Privileges {
 		if len(priv.Columns) > 0 {
-			return fmt.Errorf("GRANT has not yet implemented column privileges")
+			if err := n.handleColumnPrivileges(user, dbName, tblName, priv); err != nil {
+				return err
+			}
+			continue
 		}
 		switch priv.Type {
 		case PrivilegeType_All:
@@ -572,6 +600,57 @@ func (n *Grant) handleTablePrivileges(user *mysql_db.User, dbName string, tblNam
 	return nil
 }
 
+// handleColumnPrivileges handles giving a user their column privileges for a single privilege.
+func (n *Grant) handleColumnPrivileges(user *mysql_db.User, dbName string, tblName string, priv Privilege) error {
+	var sqlPriv sql.PrivilegeType
+	switch priv.Type {
+	case PrivilegeType_Insert:
+		sqlPriv = sql.PrivilegeType_Insert
+	case PrivilegeType_References:
+		sqlPriv = sql.PrivilegeType_References
+	case PrivilegeType_Select:
+		sqlPriv = sql.PrivilegeType_Select
+	case PrivilegeType_Update:
+		sqlPriv = sql.PrivilegeType_Update
+	default:
+		return sql.ErrGrantRevokeIllegalPrivilege.New()
+	}
+	for _, colName := range priv.Columns {
+		user.PrivilegeSet.AddColumn(dbName, tblName, colName, sqlPriv)
+	}
+	return nil
+}
+
+// handleRoutinePrivileges handles giving a user their routine privileges.
+func (n *Grant) handleRoutinePrivileges(user *mysql_db.User, dbName string, routineName string, isProcedure bool) error {
+	for i, priv := range n.Privileges {
+		if len(priv.Columns) > 0 {
+			return sql.ErrGrantRevokeIllegalPrivilege.New()
+		}
+		switch priv.Type {
+		case PrivilegeType_All:
+			// If ALL is present, then no other privileges may be provided.
+			// This should be enforced by the parser, so this is a backup check just in case
+			if i == 0 && len(n.Privileges) == 1 {
+				user.PrivilegeSet.AddRoutine(dbName, routineName, isProcedure, sql.PrivilegeType_AlterRoutine, sql.PrivilegeType_Execute)
+			} else {
+				return sql.ErrGrantRevokeIllegalPrivilege.New()
+			}
+		case PrivilegeType_AlterRoutine:
+			user.PrivilegeSet.AddRoutine(dbName, routineName, isProcedure, sql.PrivilegeType_AlterRoutine)
+		case PrivilegeType_Execute:
+			user.PrivilegeSet.AddRoutine(dbName, routineName, isProcedure, sql.PrivilegeType_Execute)
+		case PrivilegeType_GrantOption:
+			user.PrivilegeSet.AddRoutine(dbName, routineName, isProcedure, sql.PrivilegeType_GrantOption)
+		case PrivilegeType_Usage:
+			// Usage is equal to no privilege
+		default:
+			return sql.ErrGrantRevokeIllegalPrivilege.New()
+		}
+	}
+	return nil
+}
+
 // GrantRole represents the statement GRANT [role...] TO [user...].
 type GrantRole struct {
 	Roles           []UserName
@@ -156,6 +156,25 @@ func convertToSqlPrivilegeType(addGrant bool, privs ...Privilege) []sql.Privileg
 	return sqlPrivs
 }
 
+// tablePrivilegedOperations returns the operations that are needed to grant or revoke the given privileges on a table.
+// Privileges on columns require the privilege on each column, along with the grant privilege on the table.
+func tablePrivilegedOperations(level PrivilegeLevel, privs []Privilege) []sql.PrivilegedOperation {
+	var tablePrivs []Privilege
+	var ops []sql.PrivilegedOperation
+	for _, priv := range privs {
+		if len(priv.Columns) == 0 {
+			tablePrivs = append(tablePrivs, priv)
+			continue
+		}
+		for _, colName := range priv.Columns {
+			ops = append(ops, sql.NewPrivilegedOperation(level.Database, level.TableRoutine, colName,
+				convertToSqlPrivilegeType(false, priv)...))
+		}
+	}
+	return append(ops, sql.NewPrivilegedOperation(level.Database, level.TableRoutine, "",
+		convertToSqlPrivilegeType(true, tablePrivs...)...))
+}
+
 // ObjectType represents the object type that the GRANT or REVOKE statement will apply to.
 type ObjectType byte
 
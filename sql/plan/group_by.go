@@ -300,8 +300,25 @@ type groupByGroupingIter struct {
 	pos           int
 	child         sql.RowIter
 	dispose       sql.DisposeFunc
+	// partitions hold the rows of the groups that didn't fit in memory. They're aggregated one partition at a time,
+	// once the groups in memory have been returned.
+	partitions *sql.SpillPartitions
+	// level is the number of times that the rows were partitioned before reaching this iterator.
+	level int
+	// partition is the next partition to aggregate, and spilled is the iterator aggregating the previous one.
+	partition int
+	spilled   *groupByGroupingIter
 }
 
+// groupBySpillPartitions is the number of partitions in which a grouping distributes the rows of the groups that
+// don't fit in memory. Each level of partitioning uses the next groupBySpillPartitionBits bits of the grouping keys,
+// up to groupBySpillMaxLevel levels.
+const (
+	groupBySpillPartitionBits = 4
+	groupBySpillPartitions    = 1 << groupBySpillPartitionBits
+	groupBySpillMaxLevel      = 8
+)
+
 func newGroupByGroupingIter(
 	ctx *sql.Context,
 	selectedExprs, groupByExprs []sql.Expression,
@@ -322,16 +339,54 @@ func (i *groupByGroupingIter) Next(ctx *sql.Context) (sql.Row, error) {
 		}
 	}
 
-	if i.pos >= len(i.keys) {
+	if i.pos < len(i.keys) {
+		buffers, err := i.get(i.keys[i.pos])
+		if err != nil {
+			return nil, err
+		}
+		i.pos++
+		return evalBuffers(ctx, buffers)
+	}
+
+	return i.nextSpilled(ctx)
+}
+
+// nextSpilled returns the next group of the partitions written to disk, which are aggregated one at a time, after
+// releasing the groups kept in memory.
+func (i *groupByGroupingIter) nextSpilled(ctx *sql.Context) (sql.Row, error) {
+	if i.partitions == nil {
 		return nil, io.EOF
 	}
+	i.releaseGroups()
 
-	buffers, err := i.get(i.keys[i.pos])
-	if err != nil {
-		return nil, err
+	for {
+		if i.spilled != nil {
+			row, err := i.spilled.Next(ctx)
+			if err != io.EOF {
+				return row, err
+			}
+			if err := i.spilled.Close(ctx); err != nil {
+				return nil, err
+			}
+			i.spilled = nil
+		}
+
+		if i.partition >= i.partitions.Len() {
+			return nil, io.EOF
+		}
+		rows, err := i.partitions.Iter(i.partition)
+		if err != nil {
+			return nil, err
+		}
+		i.partition++
+		i.spilled = newGroupByGroupingIter(ctx, i.selectedExprs, i.groupByExprs, rows)
+		i.spilled.level = i.level + 1
 	}
-	i.pos++
-	return evalBuffers(ctx, buffers)
+}
+
+// spill writes the row given to the partition of its grouping key.
+func (i *groupByGroupingIter) spill(ctx *sql.Context, key uint64, row sql.Row) error {
+	return i.partitions.Write(ctx, key>>(i.level*groupBySpillPartitionBits), row)
 }
 
 func (i *groupByGroupingIter) compute(ctx *sql.Context) error {
@@ -351,6 +406,15 @@ func (i *groupByGroupingIter) compute(ctx *sql.Context) error {
 
 		b, err := i.get(key)
 		if sql.ErrKeyNotFound.Is(err) {
+			// Once a group doesn't fit in memory, the rows of all the groups that aren't in memory yet are written to
+			// disk instead, so that each group is aggregated either in memory or from its partition.
+			if i.partitions != nil {
+				if err := i.spill(ctx, key, row); err != nil {
+					return err
+				}
+				continue
+			}
+
 			b = make([]sql.AggregationBuffer, len(i.selectedExprs))
 			for j, a := range i.selectedExprs {
 				b[j], err = newAggregationBuffer(a)
@@ -360,7 +424,17 @@ func (i *groupByGroupingIter) compute(ctx *sql.Context) error {
 			}
 
 			if err := i.aggregations.Put(key, b); err != nil {
-				return err
+				if !sql.IsMemoryUnavailable(err) || i.level >= groupBySpillMaxLevel {
+					return err
+				}
+				for _, buffer := range b {
+					buffer.Dispose()
+				}
+				i.partitions = sql.NewSpillPartitions(groupBySpillPartitions)
+				if err := i.spill(ctx, key, row); err != nil {
+					return err
+				}
+				continue
 			}
 
 			i.keys = append(i.keys, key)
@@ -393,14 +467,35 @@ func (i *groupByGroupingIter) put(key uint64, val []sql.AggregationBuffer) error
 }
 
 func (i *groupByGroupingIter) Close(ctx *sql.Context) error {
-	i.Dispose()
+	i.releaseGroups()
 	i.aggregations = nil
+
+	var err error
+	if i.spilled != nil {
+		err = i.spilled.Close(ctx)
+		i.spilled = nil
+	}
+	if i.partitions != nil {
+		if perr := i.partitions.Close(); err == nil {
+			err = perr
+		}
+		i.partitions = nil
+	}
+	if cerr := i.child.Close(ctx); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// releaseGroups disposes the groups kept in memory.
+func (i *groupByGroupingIter) releaseGroups() {
+	i.Dispose()
+	i.keys = nil
+	i.pos = 0
 	if i.dispose != nil {
 		i.dispose()
 		i.dispose = nil
 	}
-
-	return i.child.Close(ctx)
 }
 
 func (i *groupByGroupingIter) Dispose() {
@@ -16,6 +16,7 @@ package plan
 
 import (
 	"fmt"
+	"io"
 	"sync"
 
 	"github.com/dolthub/go-mysql-server/sql"
@@ -30,6 +31,14 @@ import (
 // available, it fulfills the RowIter call by performing a hash lookup
 // on the projected results. If cached results are not available, it
 // simply delegates to the child.
+//
+// When the cached results didn't fit in memory, they're distributed
+// among temporary files by the hash of their keys, and the hash table
+// of each file is loaded when a key of that file is looked up. The
+// hash tables are dropped when there's no memory for more of them, and
+// a file is scanned for each lookup when its own table doesn't fit.
+// The hash tables are charged to the memory budget of the query, and
+// the rows are spilled to disk as well when they exceed it.
 func NewHashLookup(n *CachedResults, childProjection sql.Expression, lookupProjection sql.Expression) *HashLookup {
 	return &HashLookup{
 		UnaryNode: UnaryNode{n},
@@ -45,8 +54,19 @@ type HashLookup struct {
 	outer  sql.Expression
 	mutex  *sync.Mutex
 	lookup map[interface{}][]sql.Row
+	// partitions hold the cached rows when they were spilled to disk, and partitionLookups the hash tables of the
+	// partitions that are loaded in memory.
+	partitions       *sql.SpillPartitions
+	partitionLookups map[int]map[interface{}][]sql.Row
+	// charged is the memory charged to budget for the hash tables in memory.
+	charged uint64
+	budget  *sql.MemoryBudget
 }
 
+// hashLookupSpillPartitions is the number of partitions among which the cached rows are distributed when they were
+// spilled to disk.
+const hashLookupSpillPartitions = 64
+
 var _ sql.Expressioner = (*HashLookup)(nil)
 
 func (n *HashLookup) Expressions() []sql.Expression {
@@ -105,24 +125,43 @@ func (n *HashLookup) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedO
 func (n *HashLookup) RowIter(ctx *sql.Context, r sql.Row) (sql.RowIter, error) {
 	n.mutex.Lock()
 	defer n.mutex.Unlock()
-	if n.lookup == nil {
+	if n.lookup == nil && n.partitions == nil {
 		// Instead of building the mapping inline here with a special
 		// RowIter, we currently make use of CachedResults and require
 		// *CachedResults to be our direct child.
 		cr := n.UnaryNode.Child.(*CachedResults)
-		if res := cr.getCachedResults(); res != nil {
-			n.lookup = make(map[interface{}][]sql.Row)
-			for _, row := range res {
-				// TODO: Maybe do not put nil stuff in here.
-				key, err := n.getHashKey(ctx, n.inner, row)
+		if cache := cr.getCachedResults(); cache != nil {
+			n.budget = ctx.Memory.Budget()
+			var rows []sql.Row
+			if cache.Spilled() {
+				iter, err := cache.Iter()
 				if err != nil {
 					return nil, err
 				}
-				n.lookup[key] = append(n.lookup[key], row)
+				if err := n.partitionRows(ctx, iter); err != nil {
+					return nil, err
+				}
+			} else {
+				rows = cache.Get()
+				n.lookup = make(map[interface{}][]sql.Row)
+				for _, row := range rows {
+					// TODO: Maybe do not put nil stuff in here.
+					key, err := n.getHashKey(ctx, n.inner, row)
+					if err != nil {
+						return nil, err
+					}
+					n.lookup[key] = append(n.lookup[key], row)
+				}
 			}
 			// CachedResult is safe to Dispose after contents are transferred
-			// to |n.lookup|
+			// to |n.lookup| or |n.partitions|
 			cr.Dispose()
+
+			if n.lookup != nil {
+				if err := n.chargeLookup(ctx, rows); err != nil {
+					return nil, err
+				}
+			}
 		}
 	}
 	if n.lookup != nil {
@@ -132,9 +171,160 @@ func (n *HashLookup) RowIter(ctx *sql.Context, r sql.Row) (sql.RowIter, error) {
 		}
 		return sql.RowsToRowIter(n.lookup[key]...), nil
 	}
+	if n.partitions != nil {
+		key, err := n.getHashKey(ctx, n.outer, r)
+		if err != nil {
+			return nil, err
+		}
+		return n.lookupPartition(ctx, key)
+	}
 	return n.UnaryNode.Child.RowIter(ctx, r)
 }
 
+// chargeLookup charges the memory used by the rows of the hash table to the budget of the query. If they exceed it,
+// the rows are spilled to disk instead.
+func (n *HashLookup) chargeLookup(ctx *sql.Context, rows []sql.Row) error {
+	var size uint64
+	for _, row := range rows {
+		size += sql.RowMemorySize(row)
+	}
+	err := n.budget.Charge(size)
+	if err == nil {
+		n.charged = size
+		return nil
+	} else if !sql.IsMemoryUnavailable(err) {
+		return err
+	}
+	n.lookup = nil
+	return n.partitionRows(ctx, sql.RowsToRowIter(rows...))
+}
+
+// partitionRows distributes the rows given, which don't fit in memory, among partitions by the hash of their keys.
+func (n *HashLookup) partitionRows(ctx *sql.Context, iter sql.RowIter) error {
+	defer iter.Close(ctx)
+
+	partitions := sql.NewSpillPartitions(hashLookupSpillPartitions)
+	for {
+		row, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			_ = partitions.Close()
+			return err
+		}
+		hash, err := n.getPartitionHash(ctx, n.inner, row)
+		if err != nil {
+			_ = partitions.Close()
+			return err
+		}
+		if err := partitions.Write(ctx, hash, row); err != nil {
+			_ = partitions.Close()
+			return err
+		}
+	}
+	n.partitions = partitions
+	n.partitionLookups = make(map[int]map[interface{}][]sql.Row)
+	return nil
+}
+
+// lookupPartition returns the cached rows with the key given, from the hash table of its partition. The hash table is
+// loaded if needed, dropping the other ones when there's no memory for it. If it doesn't fit in memory by itself, the
+// rows of the partition are scanned instead.
+func (n *HashLookup) lookupPartition(ctx *sql.Context, key interface{}) (sql.RowIter, error) {
+	hash, err := sql.HashOf(sql.NewRow(key))
+	if err != nil {
+		return nil, err
+	}
+	partition := n.partitions.Partition(hash)
+	if lookup, ok := n.partitionLookups[partition]; ok {
+		return sql.RowsToRowIter(lookup[key]...), nil
+	}
+
+	iter, err := n.partitions.Iter(partition)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close(ctx)
+
+	lookup := make(map[interface{}][]sql.Row)
+	var charged uint64
+	for {
+		row, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			n.budget.Release(charged)
+			return nil, err
+		}
+		size := sql.RowMemorySize(row)
+		if !n.reserve(ctx, size) {
+			n.dropPartitionLookups()
+			if !n.reserve(ctx, size) {
+				n.budget.Release(charged)
+				return n.scanPartition(ctx, partition, key)
+			}
+		}
+		charged += size
+		rowKey, err := n.getHashKey(ctx, n.inner, row)
+		if err != nil {
+			n.budget.Release(charged)
+			return nil, err
+		}
+		lookup[rowKey] = append(lookup[rowKey], row)
+	}
+	n.partitionLookups[partition] = lookup
+	n.charged += charged
+	return sql.RowsToRowIter(lookup[key]...), nil
+}
+
+// reserve returns whether there's memory for a row of the size given, charging it to the budget of the query if so.
+func (n *HashLookup) reserve(ctx *sql.Context, size uint64) bool {
+	return ctx.Memory.ReleaseIfNeeded() && n.budget.Charge(size) == nil
+}
+
+// dropPartitionLookups drops the hash tables of the partitions loaded in memory.
+func (n *HashLookup) dropPartitionLookups() {
+	n.partitionLookups = make(map[int]map[interface{}][]sql.Row)
+	n.budget.Release(n.charged)
+	n.charged = 0
+}
+
+// scanPartition returns the rows of the partition given with the key given, reading all the rows of the partition.
+func (n *HashLookup) scanPartition(ctx *sql.Context, partition int, key interface{}) (sql.RowIter, error) {
+	iter, err := n.partitions.Iter(partition)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close(ctx)
+
+	var rows []sql.Row
+	for {
+		row, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		rowKey, err := n.getHashKey(ctx, n.inner, row)
+		if err != nil {
+			return nil, err
+		}
+		if rowKey == key {
+			rows = append(rows, row)
+		}
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// getPartitionHash returns the hash of the key of the row given, which determines its partition.
+func (n *HashLookup) getPartitionHash(ctx *sql.Context, e sql.Expression, row sql.Row) (uint64, error) {
+	key, err := n.getHashKey(ctx, e, row)
+	if err != nil {
+		return 0, err
+	}
+	return sql.HashOf(sql.NewRow(key))
+}
+
 // Convert a tuple expression returning []interface{} into something comparable.
 // Fast paths a few smaller slices into fixed size arrays, puts everything else
 // through string serialization and a hash for now. It is OK to hash lossy here
@@ -176,4 +366,11 @@ func (n *HashLookup) getHashKey(ctx *sql.Context, e sql.Expression, row sql.Row)
 func (n *HashLookup) Dispose() {
 	cr := n.Child.(*CachedResults)
 	cr.Dispose()
+	n.budget.Release(n.charged)
+	n.charged = 0
+	if n.partitions != nil {
+		_ = n.partitions.Close()
+		n.partitions = nil
+		n.partitionLookups = nil
+	}
 }
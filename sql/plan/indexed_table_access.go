@@ -249,6 +249,10 @@ func (i *IndexedTableAccess) String() string {
 		}
 	}
 
+	if pt, ok := i.Table.(sql.PartitionedTable); ok && pt.SelectedPartitions() != nil {
+		children = append(children, fmt.Sprintf("partitions: %v", pt.SelectedPartitions()))
+	}
+
 	if ft, ok := i.Table.(sql.FilteredTable); ok {
 		var filters []string
 		for _, f := range ft.Filters() {
@@ -295,6 +299,10 @@ func (i *IndexedTableAccess) DebugString() string {
 	}
 	children = append(children, fmt.Sprintf("columns: %v", columns))
 
+	if pt, ok := i.Table.(sql.PartitionedTable); ok && pt.SelectedPartitions() != nil {
+		children = append(children, fmt.Sprintf("partitions: %v", pt.SelectedPartitions()))
+	}
+
 	if ft, ok := i.Table.(sql.FilteredTable); ok {
 		var filters []string
 		for _, f := range ft.Filters() {
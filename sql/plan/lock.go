@@ -27,12 +27,19 @@ type TableLock struct {
 	Table sql.Node
 	// Write if it's true, read if it's false.
 	Write bool
+	// Local is whether a read lock still lets other sessions insert rows into the table.
+	Local bool
 }
 
 // LockTables will lock tables for the session in which it's executed.
 type LockTables struct {
 	Catalog sql.Catalog
-	Locks   []*TableLock
+	// GlobalReadLock is the lock taken by FLUSH TABLES WITH READ LOCK, which keeps tables from being locked for writes.
+	GlobalReadLock *sql.GlobalReadLock
+	// MetadataLocks are the metadata locks of the engine. The metadata locks of the locked tables are held until they're
+	// unlocked, so that other sessions wait for them as they would for a statement using the tables.
+	MetadataLocks *sql.MetadataLockManager
+	Locks         []*TableLock
 }
 
 // NewLockTables creates a new LockTables node.
@@ -67,7 +74,82 @@ func (t *LockTables) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error)
 	span, ctx := ctx.Span("plan.LockTables")
 	defer span.End()
 
+	// Locking tables releases the tables the session locked before
+	if err := t.Catalog.UnlockTables(ctx, ctx.ID()); err != nil {
+		return nil, err
+	}
+
+	// Tables locked for writes keep the global read lock from being taken until they're unlocked
+	if t.GlobalReadLock != nil && t.locksForWrite() {
+		if err := t.GlobalReadLock.BeginTableWrites(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := t.acquireMetadataLocks(ctx); err != nil {
+		if unlockErr := t.Catalog.UnlockTables(ctx, ctx.ID()); unlockErr != nil {
+			ctx.GetLogger().Warnf("unable to unlock tables: %s", unlockErr.Error())
+		}
+		return nil, err
+	}
+
+	if err := lockTables(ctx, t.Catalog, t.Locks); err != nil {
+		return nil, err
+	}
+
+	return sql.RowsToRowIter(), nil
+}
+
+// acquireMetadataLocks takes the metadata locks of the tables locked, in the order of their tables: a shared read
+// lock on the tables locked for reads, and a shared no-read-write lock on the tables locked for writes.
+func (t *LockTables) acquireMetadataLocks(ctx *sql.Context) error {
+	if t.MetadataLocks == nil {
+		return nil
+	}
+	requests := make(map[sql.MDLKey]sql.MDLType)
+	for _, l := range t.Locks {
+		database, table := getDatabaseName(l.Table), getTableName(l.Table)
+		if database == "" || table == "" {
+			continue
+		}
+		typ := sql.MDLSharedRead
+		if l.Write {
+			typ = sql.MDLSharedNoReadWrite
+		}
+		key := sql.NewMDLKey(database, table)
+		if held, ok := requests[key]; !ok || typ > held {
+			requests[key] = typ
+		}
+	}
+
+	keys := make([]sql.MDLKey, 0, len(requests))
+	for key := range requests {
+		keys = append(keys, key)
+	}
+	sql.SortMDLKeys(keys)
+	timeout := sql.LockWaitTimeout(ctx)
+	for _, key := range keys {
+		if err := t.MetadataLocks.Acquire(ctx, key, requests[key], sql.MDLExplicit, timeout); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// locksForWrite returns whether any table is locked for writes.
+func (t *LockTables) locksForWrite() bool {
 	for _, l := range t.Locks {
+		if l.Write {
+			return true
+		}
+	}
+	return false
+}
+
+// lockTables takes the locks given for the session of the context, and records them in the catalog given. Either
+// every table is locked, or none is.
+func lockTables(ctx *sql.Context, catalog sql.Catalog, locks []*TableLock) error {
+	for _, l := range locks {
 		lockable, err := getLockable(l.Table)
 		if err != nil {
 			// If a table is not lockable, just skip it
@@ -75,14 +157,25 @@ func (t *LockTables) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error)
 			continue
 		}
 
-		if err := lockable.Lock(ctx, l.Write); err != nil {
-			ctx.Error(0, "unable to lock table: %s", err)
-		} else {
-			t.Catalog.LockTable(ctx, lockable.Name())
+		if err := lockTable(ctx, lockable, l); err != nil {
+			if unlockErr := catalog.UnlockTables(ctx, ctx.ID()); unlockErr != nil {
+				ctx.GetLogger().Warnf("unable to unlock tables: %s", unlockErr.Error())
+			}
+			return err
 		}
+		catalog.LockTable(ctx, getDatabaseName(l.Table), lockable.Name())
 	}
+	return nil
+}
 
-	return sql.RowsToRowIter(), nil
+// lockTable takes the lock given on the table given.
+func lockTable(ctx *sql.Context, lockable sql.Lockable, l *TableLock) error {
+	if l.Local && !l.Write {
+		if local, ok := lockable.(sql.ReadLocalLockable); ok {
+			return local.LockReadLocal(ctx)
+		}
+	}
+	return lockable.Lock(ctx, l.Write)
 }
 
 func (t *LockTables) String() string {
@@ -90,6 +183,8 @@ func (t *LockTables) String() string {
 	for i, l := range t.Locks {
 		if l.Write {
 			children[i] = fmt.Sprintf("[WRITE] %s", l.Table.String())
+		} else if l.Local {
+			children[i] = fmt.Sprintf("[READ LOCAL] %s", l.Table.String())
 		} else {
 			children[i] = fmt.Sprintf("[READ] %s", l.Table.String())
 		}
@@ -112,10 +207,11 @@ func (t *LockTables) WithChildren(children ...sql.Node) (sql.Node, error) {
 		locks[i] = &TableLock{
 			Table: n,
 			Write: t.Locks[i].Write,
+			Local: t.Locks[i].Local,
 		}
 	}
 
-	return &LockTables{t.Catalog, locks}, nil
+	return &LockTables{Catalog: t.Catalog, GlobalReadLock: t.GlobalReadLock, MetadataLocks: t.MetadataLocks, Locks: locks}, nil
 }
 
 // CheckPrivileges implements the interface sql.Node.
@@ -153,9 +249,11 @@ func getLockableTable(table sql.Table) (sql.Lockable, error) {
 	}
 }
 
-// UnlockTables will release all locks for the current session.
+// UnlockTables will release all locks for the current session, including the global read lock.
 type UnlockTables struct {
 	Catalog sql.Catalog
+	// GlobalReadLock is the lock taken by FLUSH TABLES WITH READ LOCK.
+	GlobalReadLock *sql.GlobalReadLock
 }
 
 // NewUnlockTables returns a new UnlockTables node.
@@ -177,6 +275,9 @@ func (t *UnlockTables) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, erro
 	span, ctx := ctx.Span("plan.UnlockTables")
 	defer span.End()
 
+	if t.GlobalReadLock != nil {
+		t.GlobalReadLock.Unlock(ctx.ID())
+	}
 	if err := t.Catalog.UnlockTables(ctx, ctx.ID()); err != nil {
 		return nil, err
 	}
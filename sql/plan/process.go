@@ -529,6 +529,7 @@ func IsDDLNode(node sql.Node) bool {
 		*CreateForeignKey, *DropForeignKey,
 		*CreateCheck, *DropCheck,
 		*CreateTrigger, *DropTrigger, *AlterPK,
+		*CreateEvent, *AlterEvent, *DropEvent, *AlterPartition,
 		*Block: // Block as a top level node wraps a set of ALTER TABLE statements
 		return true
 	default:
@@ -540,6 +541,7 @@ func IsShowNode(node sql.Node) bool {
 	switch node.(type) {
 	case *ShowTables, *ShowCreateTable,
 		*ShowTriggers, *ShowCreateTrigger,
+		*ShowEvents, *ShowCreateEvent,
 		*ShowDatabases, *ShowCreateDatabase,
 		*ShowColumns, *ShowIndexes,
 		*ShowProcessList, *ShowTableStatus,
@@ -31,6 +31,7 @@ type process struct {
 	time    int64
 	state   string
 	info    string
+	memory  uint64
 }
 
 func (p process) toRow() sql.Row {
@@ -43,6 +44,7 @@ func (p process) toRow() sql.Row {
 		p.time,
 		p.state,
 		p.info,
+		p.memory,
 	)
 }
 
@@ -55,6 +57,7 @@ var processListSchema = sql.Schema{
 	{Name: "Time", Type: types.Int64},
 	{Name: "State", Type: types.LongText},
 	{Name: "Info", Type: types.LongText},
+	{Name: "Memory_used", Type: types.Uint64},
 }
 
 // ShowProcessList shows a list of all current running processes.
@@ -117,7 +120,9 @@ func (p *ShowProcessList) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, e
 			status = append(status, printer.String())
 		}
 
-		if len(status) == 0 {
+		if proc.State != "" {
+			status = []string{proc.State}
+		} else if len(status) == 0 {
 			status = []string{"running"}
 		}
 
@@ -127,9 +132,10 @@ func (p *ShowProcessList) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, e
 			time:    int64(proc.Seconds()),
 			state:   strings.Join(status, ""),
 			command: "Query",
-			host:    ctx.Session.Client().Address,
+			host:    proc.Host,
 			info:    proc.Query,
 			db:      p.Database,
+			memory:  proc.MemoryUsed(),
 		}.toRow()
 	}
 
@@ -28,9 +28,12 @@ type ResolvedTable struct {
 	sql.Table
 	Database sql.Database
 	AsOf     interface{}
+	// CommentStr is the optimizer hint comment of the query block this table is the only source of, if any
+	CommentStr string
 }
 
 var _ sql.Node = (*ResolvedTable)(nil)
+var _ sql.CommentedNode = (*ResolvedTable)(nil)
 var _ sql.Node2 = (*ResolvedTable)(nil)
 
 // Can't embed Table2 like we do Table1 as it's an extension not everyone implements
@@ -68,6 +71,10 @@ func (t *ResolvedTable) String() string {
 		}
 	}
 
+	if pt, ok := table.(sql.PartitionedTable); ok && pt.SelectedPartitions() != nil {
+		children = append(children, fmt.Sprintf("partitions: %v", pt.SelectedPartitions()))
+	}
+
 	if ft, ok := table.(sql.FilteredTable); ok {
 		var filters []string
 		for _, f := range ft.Filters() {
@@ -103,6 +110,10 @@ func (t *ResolvedTable) DebugString() string {
 	}
 	children = append(children, fmt.Sprintf("columns: %v", columns))
 
+	if pt, ok := table.(sql.PartitionedTable); ok && pt.SelectedPartitions() != nil {
+		children = append(children, fmt.Sprintf("partitions: %v", pt.SelectedPartitions()))
+	}
+
 	if ft, ok := table.(sql.FilteredTable); ok {
 		var filters []string
 		for _, f := range ft.Filters() {
@@ -172,6 +183,18 @@ func (t *ResolvedTable) CheckPrivileges(ctx *sql.Context, opChecker sql.Privileg
 		sql.NewPrivilegedOperation(t.Database.Name(), t.Table.Name(), "", sql.PrivilegeType_Select))
 }
 
+// Comment implements sql.CommentedNode
+func (t *ResolvedTable) Comment() string {
+	return t.CommentStr
+}
+
+// WithComment implements sql.CommentedNode
+func (t *ResolvedTable) WithComment(comment string) sql.Node {
+	nt := *t
+	nt.CommentStr = comment
+	return &nt
+}
+
 // WithTable returns this Node with the given table. The new table should have the same name as the previous table.
 func (t *ResolvedTable) WithTable(table sql.Table) (*ResolvedTable, error) {
 	if t.Name() != table.Name() {
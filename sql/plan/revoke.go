@@ -169,8 +169,20 @@ func (n *Revoke) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOpera
 		}
 		return opChecker.UserHasPrivileges(ctx, sql.NewPrivilegedOperation(database, "", "",
 			convertToSqlPrivilegeType(true, n.Privileges...)...))
+	} else if n.ObjectType == ObjectType_Procedure || n.ObjectType == ObjectType_Function {
+		isProcedure := n.ObjectType == ObjectType_Procedure
+		if n.Privileges[0].Type == PrivilegeType_All {
+			return opChecker.UserHasPrivileges(ctx,
+				sql.NewPrivilegedOperationForRoutine(n.PrivilegeLevel.Database, n.PrivilegeLevel.TableRoutine, isProcedure,
+					sql.PrivilegeType_AlterRoutine,
+					sql.PrivilegeType_Execute,
+					sql.PrivilegeType_GrantOption,
+				))
+		}
+		return opChecker.UserHasPrivileges(ctx,
+			sql.NewPrivilegedOperationForRoutine(n.PrivilegeLevel.Database, n.PrivilegeLevel.TableRoutine, isProcedure,
+				convertToSqlPrivilegeType(true, n.Privileges...)...))
 	} else {
-		//TODO: add column checks
 		if n.Privileges[0].Type == PrivilegeType_All {
 			return opChecker.UserHasPrivileges(ctx,
 				sql.NewPrivilegedOperation(n.PrivilegeLevel.Database, n.PrivilegeLevel.TableRoutine, "",
@@ -189,9 +201,7 @@ func (n *Revoke) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOpera
 					sql.PrivilegeType_GrantOption,
 				))
 		}
-		return opChecker.UserHasPrivileges(ctx,
-			sql.NewPrivilegedOperation(n.PrivilegeLevel.Database, n.PrivilegeLevel.TableRoutine, "",
-				convertToSqlPrivilegeType(true, n.Privileges...)...))
+		return opChecker.UserHasPrivileges(ctx, tablePrivilegedOperations(n.PrivilegeLevel, n.Privileges)...)
 	}
 }
 
@@ -242,18 +252,30 @@ func (n *Revoke) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 				return nil, sql.ErrNoDatabaseSelected.New()
 			}
 		}
-		if n.ObjectType != ObjectType_Any {
-			//TODO: implement object types
-			return nil, fmt.Errorf("GRANT has not yet implemented object types")
-		}
-		for _, grantUser := range n.Users {
-			user := mysqlDb.GetUser(grantUser.Name, grantUser.Host, false)
-			if user == nil {
-				return nil, sql.ErrGrantUserDoesNotExist.New()
+		switch n.ObjectType {
+		case ObjectType_Any, ObjectType_Table:
+			for _, revokeUser := range n.Users {
+				user := mysqlDb.GetUser(revokeUser.Name, revokeUser.Host, false)
+				if user == nil {
+					return nil, sql.ErrGrantUserDoesNotExist.New()
+				}
+				if err := n.handleTablePrivileges(user, database, n.PrivilegeLevel.TableRoutine); err != nil {
+					return nil, err
+				}
 			}
-			if err := n.handleTablePrivileges(user, database, n.PrivilegeLevel.TableRoutine); err != nil {
-				return nil, err
+		case ObjectType_Procedure, ObjectType_Function:
+			isProcedure := n.ObjectType == ObjectType_Procedure
+			for _, revokeUser := range n.Users {
+				user := mysqlDb.GetUser(revokeUser.Name, revokeUser.Host, false)
+				if user == nil {
+					return nil, sql.ErrGrantUserDoesNotExist.New()
+				}
+				if err := n.handleRoutinePrivileges(user, database, n.PrivilegeLevel.TableRoutine, isProcedure); err != nil {
+					return nil, err
+				}
 			}
+		default:
+			return nil, sql.ErrGrantRevokeIllegalPrivilege.New()
 		}
 	}
 	if err := mysqlDb.Persist(ctx); err != nil {
@@ -419,7 +441,16 @@ func (n *Revoke) handleDatabasePrivileges(user *mysql_db.User, dbName string) er
 func (n *Revoke) handleTablePrivileges(user *mysql_db.User, dbName string, tblName string) error {
 	for i, priv := range n.Privileges {
 		if len(priv.Columns) > 0 {
-			return fmt.Errorf("GRANT has not yet implemented column privileges")
+			if err := n.handleColumnPrivileges(user, dbName, tblName, priv); err != nil {
+				return err
+			}
+			continue
+		}
+		// Revoking a privilege from a table also revokes it from the table's columns
+		if sqlPrivs := convertToSqlPrivilegeType(false, priv); len(sqlPrivs) == 1 {
+			for _, colSet := range user.PrivilegeSet.Database(dbName).Table(tblName).GetColumns() {
+				user.PrivilegeSet.RemoveColumn(dbName, tblName, colSet.Name(), sqlPrivs[0])
+			}
 		}
 		switch priv.Type {
 		case PrivilegeType_All:
@@ -427,6 +458,9 @@ func (n *Revoke) handleTablePrivileges(user *mysql_db.User, dbName string, tblNa
 			// This should be enforced by the parser, so this is a backup check just in case
 			if i == 0 && len(n.Privileges) == 1 {
 				user.PrivilegeSet.ClearTable(dbName, tblName)
+				for _, colSet := range user.PrivilegeSet.Database(dbName).Table(tblName).GetColumns() {
+					user.PrivilegeSet.ClearColumn(dbName, tblName, colSet.Name())
+				}
 			} else {
 				return sql.ErrGrantRevokeIllegalPrivilege.New()
 			}
@@ -465,6 +499,57 @@ func (n *Revoke) handleTablePrivileges(user *mysql_db.User, dbName string, tblNa
 	return nil
 }
 
+// handleColumnPrivileges handles removing column privileges from a user for a single privilege.
+func (n *Revoke) handleColumnPrivileges(user *mysql_db.User, dbName string, tblName string, priv Privilege) error {
+	var sqlPriv sql.PrivilegeType
+	switch priv.Type {
+	case PrivilegeType_Insert:
+		sqlPriv = sql.PrivilegeType_Insert
+	case PrivilegeType_References:
+		sqlPriv = sql.PrivilegeType_References
+	case PrivilegeType_Select:
+		sqlPriv = sql.PrivilegeType_Select
+	case PrivilegeType_Update:
+		sqlPriv = sql.PrivilegeType_Update
+	default:
+		return sql.ErrGrantRevokeIllegalPrivilege.New()
+	}
+	for _, colName := range priv.Columns {
+		user.PrivilegeSet.RemoveColumn(dbName, tblName, colName, sqlPriv)
+	}
+	return nil
+}
+
+// handleRoutinePrivileges handles removing routine privileges from a user.
+func (n *Revoke) handleRoutinePrivileges(user *mysql_db.User, dbName string, routineName string, isProcedure bool) error {
+	for i, priv := range n.Privileges {
+		if len(priv.Columns) > 0 {
+			return sql.ErrGrantRevokeIllegalPrivilege.New()
+		}
+		switch priv.Type {
+		case PrivilegeType_All:
+			// If ALL is present, then no other privileges may be provided.
+			// This should be enforced by the parser, so this is a backup check just in case
+			if i == 0 && len(n.Privileges) == 1 {
+				user.PrivilegeSet.ClearRoutine(dbName, routineName, isProcedure)
+			} else {
+				return sql.ErrGrantRevokeIllegalPrivilege.New()
+			}
+		case PrivilegeType_AlterRoutine:
+			user.PrivilegeSet.RemoveRoutine(dbName, routineName, isProcedure, sql.PrivilegeType_AlterRoutine)
+		case PrivilegeType_Execute:
+			user.PrivilegeSet.RemoveRoutine(dbName, routineName, isProcedure, sql.PrivilegeType_Execute)
+		case PrivilegeType_GrantOption:
+			user.PrivilegeSet.RemoveRoutine(dbName, routineName, isProcedure, sql.PrivilegeType_GrantOption)
+		case PrivilegeType_Usage:
+			// Usage is equal to no privilege
+		default:
+			return sql.ErrGrantRevokeIllegalPrivilege.New()
+		}
+	}
+	return nil
+}
+
 // RevokeAll represents the statement REVOKE ALL PRIVILEGES.
 type RevokeAll struct {
 	Users []UserName
@@ -661,6 +746,16 @@ func (n *RevokeRole) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error)
 			if err != nil {
 				return nil, err
 			}
+			// A revoked role can no longer be one of the user's default roles
+			err = mysqlDb.DefaultRolesTable().Data().Remove(ctx, mysql_db.DefaultRolesPrimaryKey{
+				Host:            user.Host,
+				User:            user.User,
+				DefaultRoleHost: role.Host,
+				DefaultRoleUser: role.User,
+			}, nil)
+			if err != nil {
+				return nil, err
+			}
 		}
 	}
 	if err := mysqlDb.Persist(ctx); err != nil {
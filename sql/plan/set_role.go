@@ -0,0 +1,354 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/mysql_db"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// SetRoleType is the set of roles that SET ROLE or SET DEFAULT ROLE refers to.
+type SetRoleType byte
+
+const (
+	// SetRoleType_Default refers to the user's default roles. Only valid for SET ROLE.
+	SetRoleType_Default SetRoleType = iota
+	// SetRoleType_None refers to no roles.
+	SetRoleType_None
+	// SetRoleType_All refers to every role that has been granted to the user.
+	SetRoleType_All
+	// SetRoleType_AllExcept refers to every role that has been granted to the user, other than the listed roles. Only
+	// valid for SET ROLE.
+	SetRoleType_AllExcept
+	// SetRoleType_List refers to the listed roles.
+	SetRoleType_List
+)
+
+// String returns the SQL representation of the role set, without any listed roles.
+func (t SetRoleType) String() string {
+	switch t {
+	case SetRoleType_Default:
+		return "DEFAULT"
+	case SetRoleType_None:
+		return "NONE"
+	case SetRoleType_All:
+		return "ALL"
+	case SetRoleType_AllExcept:
+		return "ALL EXCEPT"
+	default:
+		return ""
+	}
+}
+
+// SetRole represents the statement SET ROLE.
+type SetRole struct {
+	Type    SetRoleType
+	Roles   []UserName
+	MySQLDb sql.Database
+}
+
+var _ sql.Node = (*SetRole)(nil)
+var _ sql.Databaser = (*SetRole)(nil)
+
+// NewSetRole returns a new SetRole node.
+func NewSetRole(typ SetRoleType, roles []UserName) *SetRole {
+	return &SetRole{
+		Type:    typ,
+		Roles:   roles,
+		MySQLDb: sql.UnresolvedDatabase("mysql"),
+	}
+}
+
+// Schema implements the interface sql.Node.
+func (n *SetRole) Schema() sql.Schema {
+	return types.OkResultSchema
+}
+
+// String implements the interface sql.Node.
+func (n *SetRole) String() string {
+	return fmt.Sprintf("SetRole(%s)", formatRoleSet(n.Type, n.Roles))
+}
+
+// Database implements the interface sql.Databaser.
+func (n *SetRole) Database() sql.Database {
+	return n.MySQLDb
+}
+
+// WithDatabase implements the interface sql.Databaser.
+func (n *SetRole) WithDatabase(db sql.Database) (sql.Node, error) {
+	nn := *n
+	nn.MySQLDb = db
+	return &nn, nil
+}
+
+// Resolved implements the interface sql.Node.
+func (n *SetRole) Resolved() bool {
+	_, ok := n.MySQLDb.(sql.UnresolvedDatabase)
+	return !ok
+}
+
+// Children implements the interface sql.Node.
+func (n *SetRole) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the interface sql.Node.
+func (n *SetRole) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(n, len(children), 0)
+	}
+	return n, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (n *SetRole) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	// Users may always activate the roles that have been granted to them, which is verified while running
+	return true
+}
+
+// RowIter implements the interface sql.Node.
+func (n *SetRole) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	mysqlDb, ok := n.MySQLDb.(*mysql_db.MySQLDb)
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New("mysql")
+	}
+	client := ctx.Session.Client()
+	user := mysqlDb.GetUser(client.User, client.Address, false)
+	var grantedRoles []*mysql_db.RoleEdge
+	if user != nil {
+		grantedRoles = mysqlDb.GrantedRoles(user)
+	}
+
+	var activeRoles []sql.ActiveRole
+	switch n.Type {
+	case SetRoleType_Default:
+		for _, roleEdge := range grantedRoles {
+			if mysqlDb.IsDefaultRole(user, roleEdge.FromUser, roleEdge.FromHost) {
+				activeRoles = append(activeRoles, sql.ActiveRole{Name: roleEdge.FromUser, Host: roleEdge.FromHost})
+			}
+		}
+	case SetRoleType_None:
+	case SetRoleType_All, SetRoleType_AllExcept:
+		excluded := resolveRoles(mysqlDb, n.Roles)
+		for _, roleEdge := range grantedRoles {
+			if _, ok := excluded[sql.ActiveRole{Name: roleEdge.FromUser, Host: roleEdge.FromHost}]; !ok {
+				activeRoles = append(activeRoles, sql.ActiveRole{Name: roleEdge.FromUser, Host: roleEdge.FromHost})
+			}
+		}
+	case SetRoleType_List:
+		for _, role := range n.Roles {
+			roleEdge := findGrantedRole(mysqlDb, grantedRoles, role)
+			if roleEdge == nil {
+				return nil, sql.ErrRoleNotGranted.New(role.String("`"), clientUserName(client))
+			}
+			activeRoles = append(activeRoles, sql.ActiveRole{Name: roleEdge.FromUser, Host: roleEdge.FromHost})
+		}
+	}
+
+	ctx.Session.SetActiveRoles(activeRoles, true)
+	// The cached privilege set no longer reflects the active roles, so it is recomputed on the next check
+	ctx.Session.SetPrivilegeSet(nil, 0)
+	return sql.RowsToRowIter(sql.Row{types.NewOkResult(0)}), nil
+}
+
+// SetDefaultRole represents the statement SET DEFAULT ROLE.
+type SetDefaultRole struct {
+	Type    SetRoleType
+	Roles   []UserName
+	Users   []UserName
+	MySQLDb sql.Database
+}
+
+var _ sql.Node = (*SetDefaultRole)(nil)
+var _ sql.Databaser = (*SetDefaultRole)(nil)
+
+// NewSetDefaultRole returns a new SetDefaultRole node.
+func NewSetDefaultRole(typ SetRoleType, roles []UserName, users []UserName) *SetDefaultRole {
+	return &SetDefaultRole{
+		Type:    typ,
+		Roles:   roles,
+		Users:   users,
+		MySQLDb: sql.UnresolvedDatabase("mysql"),
+	}
+}
+
+// Schema implements the interface sql.Node.
+func (n *SetDefaultRole) Schema() sql.Schema {
+	return types.OkResultSchema
+}
+
+// String implements the interface sql.Node.
+func (n *SetDefaultRole) String() string {
+	users := make([]string, len(n.Users))
+	for i, user := range n.Users {
+		users[i] = user.String("")
+	}
+	return fmt.Sprintf("SetDefaultRole(%s TO %s)", formatRoleSet(n.Type, n.Roles), strings.Join(users, ", "))
+}
+
+// Database implements the interface sql.Databaser.
+func (n *SetDefaultRole) Database() sql.Database {
+	return n.MySQLDb
+}
+
+// WithDatabase implements the interface sql.Databaser.
+func (n *SetDefaultRole) WithDatabase(db sql.Database) (sql.Node, error) {
+	nn := *n
+	nn.MySQLDb = db
+	return &nn, nil
+}
+
+// Resolved implements the interface sql.Node.
+func (n *SetDefaultRole) Resolved() bool {
+	_, ok := n.MySQLDb.(sql.UnresolvedDatabase)
+	return !ok
+}
+
+// Children implements the interface sql.Node.
+func (n *SetDefaultRole) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the interface sql.Node.
+func (n *SetDefaultRole) WithChildren(children ...sql.Node) (sql.Node, error) {
+	if len(children) != 0 {
+		return nil, sql.ErrInvalidChildrenNumber.New(n, len(children), 0)
+	}
+	return n, nil
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (n *SetDefaultRole) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	// Users may always set their own default roles
+	if n.onlySetsOwnDefaultRoles(ctx) {
+		return true
+	}
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation("", "", "", sql.PrivilegeType_CreateUser))
+}
+
+// onlySetsOwnDefaultRoles returns whether this statement only sets the default roles of the current user.
+func (n *SetDefaultRole) onlySetsOwnDefaultRoles(ctx *sql.Context) bool {
+	mysqlDb, ok := n.MySQLDb.(*mysql_db.MySQLDb)
+	if !ok {
+		return false
+	}
+	client := ctx.Session.Client()
+	currentUser := mysqlDb.GetUser(client.User, client.Address, false)
+	if currentUser == nil {
+		return false
+	}
+	for _, user := range n.Users {
+		if mysqlDb.GetUser(user.Name, user.Host, false) != currentUser {
+			return false
+		}
+	}
+	return true
+}
+
+// RowIter implements the interface sql.Node.
+func (n *SetDefaultRole) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	mysqlDb, ok := n.MySQLDb.(*mysql_db.MySQLDb)
+	if !ok {
+		return nil, sql.ErrDatabaseNotFound.New("mysql")
+	}
+	for _, targetUser := range n.Users {
+		user := mysqlDb.GetUser(targetUser.Name, targetUser.Host, false)
+		if user == nil {
+			return nil, sql.ErrGrantRevokeRoleDoesNotExist.New(targetUser.String("`"))
+		}
+		grantedRoles := mysqlDb.GrantedRoles(user)
+
+		var defaultRoles []*mysql_db.User
+		switch n.Type {
+		case SetRoleType_None:
+		case SetRoleType_All:
+			for _, roleEdge := range grantedRoles {
+				if role := mysqlDb.GetUser(roleEdge.FromUser, roleEdge.FromHost, true); role != nil {
+					defaultRoles = append(defaultRoles, role)
+				}
+			}
+		case SetRoleType_List:
+			for _, targetRole := range n.Roles {
+				roleEdge := findGrantedRole(mysqlDb, grantedRoles, targetRole)
+				if roleEdge == nil {
+					return nil, sql.ErrRoleNotGranted.New(targetRole.String("`"), targetUser.String("`"))
+				}
+				defaultRoles = append(defaultRoles, mysqlDb.GetUser(roleEdge.FromUser, roleEdge.FromHost, true))
+			}
+		default:
+			return nil, fmt.Errorf("invalid default role type: %s", n.Type.String())
+		}
+
+		if err := mysqlDb.SetDefaultRoles(ctx, user, defaultRoles); err != nil {
+			return nil, err
+		}
+	}
+	if err := mysqlDb.Persist(ctx); err != nil {
+		return nil, err
+	}
+	return sql.RowsToRowIter(sql.Row{types.NewOkResult(0)}), nil
+}
+
+// formatRoleSet returns the string representation of the roles referred to by SET ROLE or SET DEFAULT ROLE.
+func formatRoleSet(typ SetRoleType, roles []UserName) string {
+	names := make([]string, len(roles))
+	for i, role := range roles {
+		names[i] = role.String("")
+	}
+	switch typ {
+	case SetRoleType_List:
+		return strings.Join(names, ", ")
+	case SetRoleType_AllExcept:
+		return fmt.Sprintf("%s %s", typ.String(), strings.Join(names, ", "))
+	default:
+		return typ.String()
+	}
+}
+
+// resolveRoles returns the set of existing roles that match the given role names.
+func resolveRoles(mysqlDb *mysql_db.MySQLDb, roles []UserName) map[sql.ActiveRole]struct{} {
+	resolved := make(map[sql.ActiveRole]struct{})
+	for _, roleName := range roles {
+		if role := mysqlDb.GetUser(roleName.Name, roleName.Host, true); role != nil {
+			resolved[sql.ActiveRole{Name: role.User, Host: role.Host}] = struct{}{}
+		}
+	}
+	return resolved
+}
+
+// findGrantedRole returns the role edge from the given granted roles that matches the given role name. Returns nil if
+// the role does not exist, or has not been granted.
+func findGrantedRole(mysqlDb *mysql_db.MySQLDb, grantedRoles []*mysql_db.RoleEdge, roleName UserName) *mysql_db.RoleEdge {
+	role := mysqlDb.GetUser(roleName.Name, roleName.Host, true)
+	if role == nil {
+		return nil
+	}
+	for _, roleEdge := range grantedRoles {
+		if roleEdge.FromUser == role.User && roleEdge.FromHost == role.Host {
+			return roleEdge
+		}
+	}
+	return nil
+}
+
+// clientUserName returns the name of the client's account, quoted with backticks.
+func clientUserName(client sql.Client) string {
+	return fmt.Sprintf("`%s`@`%s`", client.User, client.Address)
+}
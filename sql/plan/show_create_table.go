@@ -382,13 +382,40 @@ func (i *showCreateTablesIter) produceCreateTableStatement(ctx *sql.Context, tab
 		}
 	}
 
-	return fmt.Sprintf(
-		"CREATE TABLE %s (\n%s\n) ENGINE=InnoDB DEFAULT CHARSET=%s COLLATE=%s",
+	underlying := table
+	if tw, ok := underlying.(sql.TableWrapper); ok {
+		underlying = tw.Underlying()
+	}
+
+	temporary := ""
+	if tt, ok := underlying.(sql.TemporaryTable); ok && tt.IsTemporary() {
+		temporary = "TEMPORARY "
+	}
+
+	stmt := fmt.Sprintf(
+		"CREATE %sTABLE %s (\n%s\n) ENGINE=InnoDB DEFAULT CHARSET=%s COLLATE=%s",
+		temporary,
 		quoteIdentifier(table.Name()),
 		strings.Join(colStmts, ",\n"),
 		table.Collation().CharacterSet().Name(),
 		table.Collation().Name(),
-	), nil
+	)
+
+	if partitioned, ok := underlying.(sql.PartitionedTable); ok {
+		scheme, err := partitioned.PartitionScheme(ctx)
+		if err != nil {
+			return "", err
+		}
+		if scheme != nil {
+			partitioning, err := partitionSchemeString(ctx, scheme, schema)
+			if err != nil {
+				return "", err
+			}
+			stmt = fmt.Sprintf("%s\n%s", stmt, partitioning)
+		}
+	}
+
+	return stmt, nil
 }
 
 // quoteIdentifier wraps the specified identifier in backticks and escapes all occurrences of backticks in the
@@ -0,0 +1,265 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"strings"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+type ShowEvents struct {
+	db     sql.Database
+	Events []EventDetails
+}
+
+var _ sql.Databaser = (*ShowEvents)(nil)
+var _ sql.Node = (*ShowEvents)(nil)
+
+var showEventsSchema = sql.Schema{
+	&sql.Column{Name: "Db", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "Name", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "Definer", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "Time zone", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "Type", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "Execute at", Type: types.Datetime, Nullable: true},
+	&sql.Column{Name: "Interval value", Type: types.LongText, Nullable: true},
+	&sql.Column{Name: "Interval field", Type: types.LongText, Nullable: true},
+	&sql.Column{Name: "Starts", Type: types.Datetime, Nullable: true},
+	&sql.Column{Name: "Ends", Type: types.Datetime, Nullable: true},
+	&sql.Column{Name: "Status", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "Originator", Type: types.Int64, Nullable: false},
+	&sql.Column{Name: "character_set_client", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "collation_connection", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "Database Collation", Type: types.LongText, Nullable: false},
+}
+
+// NewShowEvents creates a new ShowEvents node for SHOW EVENTS statements.
+func NewShowEvents(db sql.Database) *ShowEvents {
+	return &ShowEvents{
+		db: db,
+	}
+}
+
+// String implements the sql.Node interface.
+func (s *ShowEvents) String() string {
+	return "SHOW EVENTS"
+}
+
+// Resolved implements the sql.Node interface.
+func (s *ShowEvents) Resolved() bool {
+	_, ok := s.db.(sql.UnresolvedDatabase)
+	return !ok
+}
+
+// Children implements the sql.Node interface.
+func (s *ShowEvents) Children() []sql.Node {
+	return nil
+}
+
+// Schema implements the sql.Node interface.
+func (s *ShowEvents) Schema() sql.Schema {
+	return showEventsSchema
+}
+
+// RowIter implements the sql.Node interface.
+func (s *ShowEvents) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	characterSetClient, err := ctx.GetSessionVariable(ctx, "character_set_client")
+	if err != nil {
+		return nil, err
+	}
+	collationConnection, err := ctx.GetSessionVariable(ctx, "collation_connection")
+	if err != nil {
+		return nil, err
+	}
+	collationServer, err := ctx.GetSessionVariable(ctx, "collation_server")
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []sql.Row
+	for _, event := range s.Events {
+		var executeAt, intervalValue, intervalField, starts, ends interface{}
+		if event.HasExecuteAt {
+			executeAt = event.ExecuteAt
+		} else {
+			intervalValue = event.ExecuteEvery.Value
+			intervalField = event.ExecuteEvery.Unit
+			starts = event.Starts
+			if event.HasEnds {
+				ends = event.Ends
+			}
+		}
+		definer := strings.ReplaceAll(event.Definer, "`", "")
+		rows = append(rows, sql.Row{
+			s.db.Name(),                            // Db
+			event.Name,                             // Name
+			definer,                                // Definer
+			"SYSTEM",                               // Time zone
+			event.EventType(),                      // Type
+			executeAt,                              // Execute at
+			intervalValue,                          // Interval value
+			intervalField,                          // Interval field
+			starts,                                 // Starts
+			ends,                                   // Ends
+			event.Status.InformationSchemaString(), // Status
+			int64(0),                               // Originator
+			characterSetClient,                     // character_set_client
+			collationConnection,                    // collation_connection
+			collationServer,                        // Database Collation
+		})
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (s *ShowEvents) WithChildren(children ...sql.Node) (sql.Node, error) {
+	return NillaryWithChildren(s, children...)
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (s *ShowEvents) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(s.db.Name(), "", "", sql.PrivilegeType_Event))
+}
+
+// Database implements the sql.Databaser interface.
+func (s *ShowEvents) Database() sql.Database {
+	return s.db
+}
+
+// WithDatabase implements the sql.Databaser interface.
+func (s *ShowEvents) WithDatabase(db sql.Database) (sql.Node, error) {
+	ns := *s
+	ns.db = db
+	return &ns, nil
+}
+
+// WithEvents returns a copy of this node with the events of the database set.
+func (s *ShowEvents) WithEvents(events []EventDetails) *ShowEvents {
+	ns := *s
+	ns.Events = events
+	return &ns
+}
+
+type ShowCreateEvent struct {
+	db        sql.Database
+	EventName string
+}
+
+var _ sql.Databaser = (*ShowCreateEvent)(nil)
+var _ sql.Node = (*ShowCreateEvent)(nil)
+
+var showCreateEventSchema = sql.Schema{
+	&sql.Column{Name: "Event", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "sql_mode", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "time_zone", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "Create Event", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "character_set_client", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "collation_connection", Type: types.LongText, Nullable: false},
+	&sql.Column{Name: "Database Collation", Type: types.LongText, Nullable: false},
+}
+
+// NewShowCreateEvent creates a new ShowCreateEvent node for SHOW CREATE EVENT statements.
+func NewShowCreateEvent(db sql.Database, event string) *ShowCreateEvent {
+	return &ShowCreateEvent{
+		db:        db,
+		EventName: strings.ToLower(event),
+	}
+}
+
+// String implements the sql.Node interface.
+func (s *ShowCreateEvent) String() string {
+	return "SHOW CREATE EVENT " + s.EventName
+}
+
+// Resolved implements the sql.Node interface.
+func (s *ShowCreateEvent) Resolved() bool {
+	_, ok := s.db.(sql.UnresolvedDatabase)
+	return !ok
+}
+
+// Children implements the sql.Node interface.
+func (s *ShowCreateEvent) Children() []sql.Node {
+	return nil
+}
+
+// Schema implements the sql.Node interface.
+func (s *ShowCreateEvent) Schema() sql.Schema {
+	return showCreateEventSchema
+}
+
+// RowIter implements the sql.Node interface.
+func (s *ShowCreateEvent) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	eventDb, ok := s.db.(sql.EventDatabase)
+	if !ok {
+		return nil, sql.ErrEventDoesNotExist.New(s.EventName)
+	}
+	event, exists, err := eventDb.GetEvent(ctx, s.EventName)
+	if err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, sql.ErrEventDoesNotExist.New(s.EventName)
+	}
+	sqlMode, err := ctx.GetSessionVariable(ctx, "sql_mode")
+	if err != nil {
+		return nil, err
+	}
+	characterSetClient, err := ctx.GetSessionVariable(ctx, "character_set_client")
+	if err != nil {
+		return nil, err
+	}
+	collationConnection, err := ctx.GetSessionVariable(ctx, "collation_connection")
+	if err != nil {
+		return nil, err
+	}
+	collationServer, err := ctx.GetSessionVariable(ctx, "collation_server")
+	if err != nil {
+		return nil, err
+	}
+	return sql.RowsToRowIter(sql.Row{
+		event.Name,            // Event
+		sqlMode,               // sql_mode
+		"SYSTEM",              // time_zone
+		event.CreateStatement, // Create Event
+		characterSetClient,    // character_set_client
+		collationConnection,   // collation_connection
+		collationServer,       // Database Collation
+	}), nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (s *ShowCreateEvent) WithChildren(children ...sql.Node) (sql.Node, error) {
+	return NillaryWithChildren(s, children...)
+}
+
+// CheckPrivileges implements the interface sql.Node.
+func (s *ShowCreateEvent) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return opChecker.UserHasPrivileges(ctx,
+		sql.NewPrivilegedOperation(s.db.Name(), "", "", sql.PrivilegeType_Event))
+}
+
+// Database implements the sql.Databaser interface.
+func (s *ShowCreateEvent) Database() sql.Database {
+	return s.db
+}
+
+// WithDatabase implements the sql.Databaser interface.
+func (s *ShowCreateEvent) WithDatabase(db sql.Database) (sql.Node, error) {
+	ns := *s
+	ns.db = db
+	return &ns, nil
+}
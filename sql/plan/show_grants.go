@@ -144,6 +144,45 @@ func generatePrivStrings(db, tbl, user string, privs []sql.PrivilegeType) string
 	return fmt.Sprintf("GRANT %s ON %s.%s TO %s%s", privStr, db, tbl, user, withGrantOption)
 }
 
+// generateTablePrivStrings returns the GRANT statement for the given table, which includes the privileges granted on
+// the table's columns. Column privileges are written as the privilege followed by the list of columns, such as
+// "SELECT (`a`, `b`)".
+func generateTablePrivStrings(db, tbl, user string, tblSet sql.PrivilegeSetTable) string {
+	var privStrs []string
+	for _, priv := range tblSet.ToSlice() {
+		if priv != sql.PrivilegeType_GrantOption {
+			privStrs = append(privStrs, priv.String())
+		}
+	}
+	for _, priv := range []sql.PrivilegeType{
+		sql.PrivilegeType_Select,
+		sql.PrivilegeType_Insert,
+		sql.PrivilegeType_Update,
+		sql.PrivilegeType_References,
+	} {
+		if tblSet.Has(priv) {
+			continue
+		}
+		var colStrs []string
+		for _, col := range tblSet.GetColumns() {
+			if col.Has(priv) {
+				colStrs = append(colStrs, fmt.Sprintf("`%s`", col.Name()))
+			}
+		}
+		if len(colStrs) > 0 {
+			privStrs = append(privStrs, fmt.Sprintf("%s (%s)", priv.String(), strings.Join(colStrs, ", ")))
+		}
+	}
+	if len(privStrs) == 0 {
+		return ""
+	}
+	withGrantOption := ""
+	if tblSet.Has(sql.PrivilegeType_GrantOption) {
+		withGrantOption = " WITH GRANT OPTION"
+	}
+	return fmt.Sprintf("GRANT %s ON %s.%s TO %s%s", strings.Join(privStrs, ", "), db, tbl, user, withGrantOption)
+}
+
 // RowIter implements the interface sql.Node.
 func (n *ShowGrants) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 	mysqlDb, ok := n.MySQLDb.(*mysql_db.MySQLDb)
@@ -176,12 +215,22 @@ func (n *ShowGrants) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error)
 
 		for _, tbl := range db.GetTables() {
 			tblStr := fmt.Sprintf("`%s`", tbl.Name())
-			privStr = generatePrivStrings(dbStr, tblStr, userStr, tbl.ToSlice())
-			rows = append(rows, sql.Row{privStr})
+			if privStr = generateTablePrivStrings(dbStr, tblStr, userStr, tbl); len(privStr) != 0 {
+				rows = append(rows, sql.Row{privStr})
+			}
 		}
-	}
 
-	// TODO: display column privileges
+		for _, routine := range db.GetRoutines() {
+			routineType := "FUNCTION"
+			if routine.IsProcedure() {
+				routineType = "PROCEDURE"
+			}
+			routineStr := fmt.Sprintf("`%s`", routine.Name())
+			if privStr = generatePrivStrings(routineType+" "+dbStr, routineStr, userStr, routine.ToSlice()); len(privStr) != 0 {
+				rows = append(rows, sql.Row{privStr})
+			}
+		}
+	}
 
 	sb := strings.Builder{}
 	roleEdges := mysqlDb.RoleEdgesTable().Data().Get(mysql_db.RoleEdgesToKey{
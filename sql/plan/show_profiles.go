@@ -0,0 +1,204 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// ProfileDurationType is the type of the durations shown by SHOW PROFILE and information_schema.PROFILING, which are
+// expressed in seconds.
+var ProfileDurationType = types.MustCreateDecimalType(9, 6)
+
+// ProfileDuration returns the duration given as a value of ProfileDurationType.
+func ProfileDuration(d time.Duration) decimal.Decimal {
+	return decimal.New(d.Microseconds(), -6)
+}
+
+// ShowProfiles is a node that shows the statements that were profiled in the session, along with their duration.
+type ShowProfiles struct{}
+
+var _ sql.Node = (*ShowProfiles)(nil)
+
+// NewShowProfiles creates a new ShowProfiles node.
+func NewShowProfiles() *ShowProfiles {
+	return &ShowProfiles{}
+}
+
+// Resolved implements the sql.Node interface.
+func (*ShowProfiles) Resolved() bool {
+	return true
+}
+
+// Children implements the sql.Node interface.
+func (*ShowProfiles) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (s *ShowProfiles) WithChildren(children ...sql.Node) (sql.Node, error) {
+	return NillaryWithChildren(s, children...)
+}
+
+// CheckPrivileges implements the sql.Node interface. The profiles are those of the current session.
+func (*ShowProfiles) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return true
+}
+
+// String implements the fmt.Stringer interface.
+func (*ShowProfiles) String() string {
+	return "SHOW PROFILES"
+}
+
+// Schema implements the sql.Node interface.
+func (*ShowProfiles) Schema() sql.Schema {
+	return sql.Schema{
+		&sql.Column{Name: "Query_ID", Type: types.Int64, Nullable: false},
+		&sql.Column{Name: "Duration", Type: types.Float64, Nullable: false},
+		&sql.Column{Name: "Query", Type: types.LongText, Nullable: false},
+	}
+}
+
+// RowIter implements the sql.Node interface.
+func (*ShowProfiles) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	var rows []sql.Row
+	for _, profile := range ctx.Session.GetQueryProfiles() {
+		rows = append(rows, sql.NewRow(profile.QueryId, profile.Duration().Seconds(), profile.Query))
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
+
+// ShowProfile is a node that shows the stages of a statement that was profiled in the session, along with their
+// duration.
+type ShowProfile struct {
+	// Types are the types of information shown in addition to the durations, such as CPU. Only CPU adds columns, the
+	// other types are accepted but not recorded, like MEMORY is in MySQL.
+	Types []string
+	// QueryId is the statement whose profile is shown, or 0 for the most recent statement.
+	QueryId int64
+	// Limit is the largest number of stages shown, or -1 to show all of them.
+	Limit int64
+	// Offset is the number of stages skipped before the ones shown.
+	Offset int64
+}
+
+var _ sql.Node = (*ShowProfile)(nil)
+
+// NewShowProfile creates a new ShowProfile node, which shows every stage of the most recent statement.
+func NewShowProfile() *ShowProfile {
+	return &ShowProfile{Limit: -1}
+}
+
+// Resolved implements the sql.Node interface.
+func (*ShowProfile) Resolved() bool {
+	return true
+}
+
+// Children implements the sql.Node interface.
+func (*ShowProfile) Children() []sql.Node {
+	return nil
+}
+
+// WithChildren implements the sql.Node interface.
+func (s *ShowProfile) WithChildren(children ...sql.Node) (sql.Node, error) {
+	return NillaryWithChildren(s, children...)
+}
+
+// CheckPrivileges implements the sql.Node interface. The profiles are those of the current session.
+func (*ShowProfile) CheckPrivileges(ctx *sql.Context, opChecker sql.PrivilegedOperationChecker) bool {
+	return true
+}
+
+// String implements the fmt.Stringer interface.
+func (s *ShowProfile) String() string {
+	str := "SHOW PROFILE"
+	if len(s.Types) > 0 {
+		str += " " + strings.Join(s.Types, ", ")
+	}
+	if s.QueryId != 0 {
+		str += fmt.Sprintf(" FOR QUERY %d", s.QueryId)
+	}
+	if s.Limit >= 0 {
+		str += fmt.Sprintf(" LIMIT %d OFFSET %d", s.Limit, s.Offset)
+	}
+	return str
+}
+
+// showsCPU returns whether the CPU columns are shown.
+func (s *ShowProfile) showsCPU() bool {
+	for _, t := range s.Types {
+		if t == "CPU" || t == "ALL" {
+			return true
+		}
+	}
+	return false
+}
+
+// Schema implements the sql.Node interface.
+func (s *ShowProfile) Schema() sql.Schema {
+	sch := sql.Schema{
+		&sql.Column{Name: "Status", Type: types.LongText, Nullable: false},
+		&sql.Column{Name: "Duration", Type: ProfileDurationType, Nullable: false},
+	}
+	if s.showsCPU() {
+		sch = append(sch,
+			&sql.Column{Name: "CPU_user", Type: ProfileDurationType, Nullable: true},
+			&sql.Column{Name: "CPU_system", Type: ProfileDurationType, Nullable: true},
+		)
+	}
+	return sch
+}
+
+// RowIter implements the sql.Node interface.
+func (s *ShowProfile) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
+	profiles := ctx.Session.GetQueryProfiles()
+	var stages []sql.ProfileStage
+	for i, profile := range profiles {
+		if profile.QueryId == s.QueryId || (s.QueryId == 0 && i == len(profiles)-1) {
+			stages = profile.Stages
+		}
+	}
+
+	if s.Offset >= int64(len(stages)) {
+		stages = nil
+	} else {
+		stages = stages[s.Offset:]
+	}
+	if s.Limit >= 0 && s.Limit < int64(len(stages)) {
+		stages = stages[:s.Limit]
+	}
+
+	showsCPU := s.showsCPU()
+	var rows []sql.Row
+	for _, stage := range stages {
+		r := sql.NewRow(stage.State, ProfileDuration(stage.Duration))
+		if showsCPU {
+			if sql.HasProcessCPUTime {
+				r = append(r, ProfileDuration(stage.CPUUser), ProfileDuration(stage.CPUSystem))
+			} else {
+				r = append(r, nil, nil)
+			}
+		}
+		rows = append(rows, r)
+	}
+	return sql.RowsToRowIter(rows...), nil
+}
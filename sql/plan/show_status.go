@@ -29,6 +29,8 @@ import (
 // in the future.
 type ShowStatus struct {
 	modifier ShowStatusModifier
+	// QueryCache is the query result cache whose statistics are listed along with the system variables.
+	QueryCache *sql.QueryCache
 }
 
 var _ sql.Node = (*ShowStatus)(nil)
@@ -91,12 +93,21 @@ func (s *ShowStatus) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error)
 		rows = append(rows, sql.Row{name, val})
 	}
 
+	if s.QueryCache != nil {
+		for name, val := range s.QueryCache.StatusVariables() {
+			rows = append(rows, sql.Row{name, val})
+		}
+		sort.Slice(rows, func(i, j int) bool {
+			return rows[i][0].(string) < rows[j][0].(string)
+		})
+	}
+
 	return sql.RowsToRowIter(rows...), nil
 }
 
 // WithChildren implements sql.Node interface.
 func (s *ShowStatus) WithChildren(node ...sql.Node) (sql.Node, error) {
-	return NewShowStatus(s.modifier), nil
+	return NillaryWithChildren(s, node...)
 }
 
 // CheckPrivileges implements the interface sql.Node.
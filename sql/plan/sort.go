@@ -137,6 +137,11 @@ type sortIter struct {
 	sortedRows  []sql.Row
 	sortedRows2 []sql.Row2
 	idx         int
+	// spill and spilledRows are set when the rows didn't fit in memory, and were sorted on disk instead.
+	spill       *externalSort
+	spilledRows sql.RowIter
+	// charged is the memory charged to the budget of the query for the rows kept in memory.
+	charged uint64
 }
 
 var _ sql.RowIter = (*sortIter)(nil)
@@ -161,6 +166,9 @@ func (i *sortIter) Next(ctx *sql.Context) (sql.Row, error) {
 		i.idx = 0
 	}
 
+	if i.spilledRows != nil {
+		return i.spilledRows.Next(ctx)
+	}
 	if i.idx >= len(i.sortedRows) {
 		return nil, io.EOF
 	}
@@ -191,13 +199,30 @@ func (i *sortIter) Next2(ctx *sql.Context, frame *sql.RowFrame) error {
 
 func (i *sortIter) Close(ctx *sql.Context) error {
 	i.sortedRows = nil
-	return i.childIter.Close(ctx)
+	ctx.Memory.Budget().Release(i.charged)
+	i.charged = 0
+	err := i.childIter.Close(ctx)
+	if i.spilledRows != nil {
+		if cerr := i.spilledRows.Close(ctx); err == nil {
+			err = cerr
+		}
+		i.spilledRows = nil
+	}
+	if i.spill != nil {
+		if cerr := i.spill.close(); err == nil {
+			err = cerr
+		}
+		i.spill = nil
+	}
+	return err
 }
 
+// computeSortedRows reads and sorts all the rows of the child. When there is no memory available, or the rows exceed
+// the memory budget of the query, the rows read so far are sorted and written to disk, and the sort continues as an
+// external merge sort.
 func (i *sortIter) computeSortedRows(ctx *sql.Context) error {
-	cache, dispose := ctx.Memory.NewRowsCache()
-	defer dispose()
-
+	budget := ctx.Memory.Budget()
+	var rows []sql.Row
 	for {
 		row, err := i.childIter.Next(ctx)
 
@@ -208,24 +233,43 @@ func (i *sortIter) computeSortedRows(ctx *sql.Context) error {
 			return err
 		}
 
-		if err := cache.Add(row); err != nil {
-			return err
+		size := sql.RowMemorySize(row)
+		chargeErr := budget.Charge(size)
+		if len(rows) > 0 && (chargeErr != nil || (len(rows) >= sortSpillMinRows && !ctx.Memory.ReleaseIfNeeded())) {
+			if i.spill == nil {
+				i.spill = newExternalSort(i.sortFields)
+			}
+			if err := i.spill.writeRun(ctx, rows); err != nil {
+				return err
+			}
+			rows = nil
+			budget.Release(i.charged)
+			i.charged = 0
+			if chargeErr != nil {
+				chargeErr = budget.Charge(size)
+			}
+		}
+		if chargeErr != nil {
+			return chargeErr
 		}
+		i.charged += size
+		rows = append(rows, row)
 	}
 
-	rows := cache.Get()
-	sorter := &expression.Sorter{
-		SortFields: i.sortFields,
-		Rows:       rows,
-		LastError:  nil,
-		Ctx:        ctx,
+	if i.spill == nil {
+		if err := sortRows(ctx, i.sortFields, rows); err != nil {
+			return err
+		}
+		i.sortedRows = rows
+		return nil
 	}
-	sort.Stable(sorter)
-	if sorter.LastError != nil {
-		return sorter.LastError
+
+	if err := i.spill.writeRun(ctx, rows); err != nil {
+		return err
 	}
-	i.sortedRows = rows
-	return nil
+	var err error
+	i.spilledRows, err = i.spill.iter(ctx)
+	return err
 }
 
 func (i *sortIter) computeSortedRows2(ctx *sql.Context) error {
@@ -0,0 +1,221 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plan
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/dolthub/go-mysql-server/memory"
+	"github.com/dolthub/go-mysql-server/sql"
+	"github.com/dolthub/go-mysql-server/sql/expression"
+	"github.com/dolthub/go-mysql-server/sql/expression/function/aggregation"
+	"github.com/dolthub/go-mysql-server/sql/types"
+)
+
+// spillReporter reports that there's no memory available for two consecutive checks out of every period, which is
+// enough for the check following a release of memory to fail too. The nodes using it run out of memory every few rows.
+type spillReporter struct {
+	mu     sync.Mutex
+	period int
+	checks int
+}
+
+func (r *spillReporter) MaxMemory() uint64 {
+	return 1
+}
+
+func (r *spillReporter) UsedMemory() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.checks++
+	if r.checks%r.period < 2 {
+		return 1
+	}
+	return 0
+}
+
+func newSpillContext(period int) *sql.Context {
+	memory := sql.NewMemoryManager(&spillReporter{period: period})
+	return sql.NewContext(context.Background(), sql.WithMemoryManager(memory))
+}
+
+func newSpillTestTable(t *testing.T, rows []sql.Row) *memory.Table {
+	table := memory.NewTable("test", sql.NewPrimaryKeySchema(sql.Schema{
+		{Name: "k", Type: types.Int64, Source: "test"},
+		{Name: "v", Type: types.Int64, Source: "test"},
+	}), nil)
+	for _, r := range rows {
+		require.NoError(t, table.Insert(sql.NewEmptyContext(), r))
+	}
+	return table
+}
+
+func TestSortSpill(t *testing.T) {
+	defer func(minRows, fanIn int) {
+		sortSpillMinRows, sortMergeFanIn = minRows, fanIn
+	}(sortSpillMinRows, sortMergeFanIn)
+	sortSpillMinRows, sortMergeFanIn = 2, 3
+
+	var rows []sql.Row
+	for i := 0; i < 200; i++ {
+		rows = append(rows, sql.NewRow(int64((i*37)%23), int64(i)))
+	}
+	expected := append([]sql.Row(nil), rows...)
+	sort.SliceStable(expected, func(i, j int) bool {
+		return expected[i][0].(int64) > expected[j][0].(int64)
+	})
+
+	ctx := newSpillContext(5)
+	child, err := NewResolvedTable(newSpillTestTable(t, rows), nil, nil).RowIter(ctx, nil)
+	require.NoError(t, err)
+	iter := newSortIter(sql.SortFields{
+		{Column: expression.NewGetField(0, types.Int64, "k", false), Order: sql.Descending},
+	}, child)
+
+	var actual []sql.Row
+	for {
+		row, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		actual = append(actual, row)
+	}
+	require.NotNil(t, iter.spill)
+	require.Greater(t, len(iter.spill.runs), 1)
+	require.NoError(t, iter.Close(ctx))
+	require.Equal(t, expected, actual)
+}
+
+func TestGroupBySpill(t *testing.T) {
+	var rows []sql.Row
+	sums := make(map[int64]float64)
+	counts := make(map[int64]int64)
+	for i := 0; i < 1000; i++ {
+		k := int64((i * 7919) % 331)
+		rows = append(rows, sql.NewRow(k, int64(i)))
+		sums[k] += float64(i)
+		counts[k]++
+	}
+	var expected []sql.Row
+	for k, sum := range sums {
+		expected = append(expected, sql.NewRow(k, sum, counts[k]))
+	}
+
+	ctx := newSpillContext(20)
+	child, err := NewResolvedTable(newSpillTestTable(t, rows), nil, nil).RowIter(ctx, nil)
+	require.NoError(t, err)
+	k := expression.NewGetField(0, types.Int64, "k", false)
+	v := expression.NewGetField(1, types.Int64, "v", false)
+	iter := newGroupByGroupingIter(ctx,
+		[]sql.Expression{k, aggregation.NewSum(v), aggregation.NewCount(v)},
+		[]sql.Expression{k},
+		child,
+	)
+
+	var actual []sql.Row
+	for {
+		row, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		actual = append(actual, row)
+	}
+	require.NotNil(t, iter.partitions)
+	require.NoError(t, iter.Close(ctx))
+	require.ElementsMatch(t, expected, actual)
+}
+
+func TestHashLookupSpill(t *testing.T) {
+	var rows []sql.Row
+	for i := 0; i < 300; i++ {
+		rows = append(rows, sql.NewRow(int64(i%50), int64(i)))
+	}
+
+	ctx := newSpillContext(10)
+	k := expression.NewGetField(0, types.Int64, "k", false)
+	cr := NewCachedResults(NewResolvedTable(newSpillTestTable(t, rows), nil, nil))
+	lookup := NewHashLookup(cr, k, k)
+	defer lookup.Dispose()
+
+	// The first iteration goes through the cached results, which don't fit in memory
+	iter, err := lookup.RowIter(ctx, sql.NewRow(int64(0)))
+	require.NoError(t, err)
+	all, err := sql.RowIterToRows(ctx, nil, iter)
+	require.NoError(t, err)
+	require.Len(t, all, len(rows))
+	require.True(t, cr.getCachedResults().Spilled())
+
+	for key := int64(-1); key <= 50; key++ {
+		t.Run(fmt.Sprint(key), func(t *testing.T) {
+			var expected []sql.Row
+			for _, row := range rows {
+				if row[0] == key {
+					expected = append(expected, row)
+				}
+			}
+			iter, err := lookup.RowIter(ctx, sql.NewRow(key))
+			require.NoError(t, err)
+			actual, err := sql.RowIterToRows(ctx, nil, iter)
+			require.NoError(t, err)
+			require.ElementsMatch(t, expected, actual)
+		})
+	}
+	require.NotNil(t, lookup.partitions)
+}
+
+func TestSortSpillOverBudget(t *testing.T) {
+	var rows []sql.Row
+	for i := 0; i < 100; i++ {
+		rows = append(rows, sql.NewRow(int64((i*37)%23), int64(i)))
+	}
+	expected := append([]sql.Row(nil), rows...)
+	sort.SliceStable(expected, func(i, j int) bool {
+		return expected[i][0].(int64) < expected[j][0].(int64)
+	})
+
+	session := sql.NewSessionMemoryBudget()
+	budget := sql.NewQueryMemoryBudget(320, session)
+	ctx := sql.NewContext(context.Background(), sql.WithMemoryManager(sql.NewMemoryManager(nil).WithBudget(budget)))
+	child, err := NewResolvedTable(newSpillTestTable(t, rows), nil, nil).RowIter(ctx, nil)
+	require.NoError(t, err)
+	iter := newSortIter(sql.SortFields{
+		{Column: expression.NewGetField(0, types.Int64, "k", false), Order: sql.Ascending},
+	}, child)
+
+	var actual []sql.Row
+	for {
+		row, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		require.LessOrEqual(t, budget.Used(), uint64(320))
+		actual = append(actual, row)
+	}
+	require.NotNil(t, iter.spill)
+	require.NoError(t, iter.Close(ctx))
+	require.Equal(t, expected, actual)
+	require.Zero(t, budget.Used())
+	require.Zero(t, session.Used())
+}
@@ -45,6 +45,16 @@ func (tc *TableCopier) Database() sql.Database {
 	return tc.db
 }
 
+// Source returns the node whose rows are copied.
+func (tc *TableCopier) Source() sql.Node {
+	return tc.source
+}
+
+// Destination returns the node that creates the table the rows are copied to.
+func (tc *TableCopier) Destination() sql.Node {
+	return tc.destination
+}
+
 func (tc *TableCopier) RowIter(ctx *sql.Context, row sql.Row) (sql.RowIter, error) {
 	if _, ok := tc.destination.(*CreateTable); ok {
 		return tc.processCreateTable(ctx, row)
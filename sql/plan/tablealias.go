@@ -26,9 +26,12 @@ import (
 // TableAlias is a node that acts as a table with a given name.
 type TableAlias struct {
 	*UnaryNode
-	name string
+	name    string
+	comment string
 }
 
+var _ sql.CommentedNode = (*TableAlias)(nil)
+
 // NewTableAlias returns a new Table alias node.
 func NewTableAlias(name string, node sql.Node) *TableAlias {
 	return &TableAlias{UnaryNode: &UnaryNode{Child: node}, name: name}
@@ -58,7 +61,21 @@ func (t *TableAlias) WithChildren(children ...sql.Node) (sql.Node, error) {
 		return nil, sql.ErrInvalidChildrenNumber.New(t, len(children), 1)
 	}
 
-	return NewTableAlias(t.name, children[0]), nil
+	ret := *t
+	ret.UnaryNode = &UnaryNode{Child: children[0]}
+	return &ret, nil
+}
+
+// Comment implements sql.CommentedNode
+func (t *TableAlias) Comment() string {
+	return t.comment
+}
+
+// WithComment implements sql.CommentedNode
+func (t *TableAlias) WithComment(comment string) sql.Node {
+	ret := *t
+	ret.comment = comment
+	return &ret
 }
 
 // CheckPrivileges implements the interface sql.Node.
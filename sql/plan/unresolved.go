@@ -27,24 +27,27 @@ var ErrUnresolvedTable = errors.NewKind("unresolved table")
 
 // UnresolvedTable is a table that has not been resolved yet but whose name is known.
 type UnresolvedTable struct {
-	name     string
-	database string
-	asOf     sql.Expression
+	name       string
+	database   string
+	asOf       sql.Expression
+	partitions []string
+	comment    string
 }
 
 var _ sql.Node = (*UnresolvedTable)(nil)
+var _ sql.CommentedNode = (*UnresolvedTable)(nil)
 var _ sql.Expressioner = (*UnresolvedTable)(nil)
 var _ sql.UnresolvedTable = (*UnresolvedTable)(nil)
 var _ Versionable = (*UnresolvedTable)(nil)
 
 // NewUnresolvedTable creates a new Unresolved table.
 func NewUnresolvedTable(name, db string) *UnresolvedTable {
-	return &UnresolvedTable{name, db, nil}
+	return &UnresolvedTable{name: name, database: db}
 }
 
 // NewUnresolvedTableAsOf creates a new Unresolved table with an AS OF expression.
 func NewUnresolvedTableAsOf(name, db string, asOf sql.Expression) *UnresolvedTable {
-	return &UnresolvedTable{name, db, asOf}
+	return &UnresolvedTable{name: name, database: db, asOf: asOf}
 }
 
 // Name implements the Nameable interface.
@@ -108,6 +111,30 @@ func (t *UnresolvedTable) WithDatabase(database string) (*UnresolvedTable, error
 	return &t2, nil
 }
 
+// Partitions returns the partitions selected with a PARTITION clause, or nil if there was none.
+func (t *UnresolvedTable) Partitions() []string {
+	return t.partitions
+}
+
+// WithPartitions returns a copy of this unresolved table that selects the partitions named.
+func (t *UnresolvedTable) WithPartitions(partitions []string) *UnresolvedTable {
+	t2 := *t
+	t2.partitions = partitions
+	return &t2
+}
+
+// Comment implements sql.CommentedNode
+func (t *UnresolvedTable) Comment() string {
+	return t.comment
+}
+
+// WithComment implements sql.CommentedNode
+func (t *UnresolvedTable) WithComment(comment string) sql.Node {
+	t2 := *t
+	t2.comment = comment
+	return &t2
+}
+
 func (t *UnresolvedTable) Expressions() []sql.Expression {
 	if t.asOf != nil {
 		return []sql.Expression{t.asOf}
@@ -2,10 +2,13 @@ package sql
 
 // PrivilegedOperation represents an operation that requires privileges to execute.
 type PrivilegedOperation struct {
-	Database   string
-	Table      string
-	Column     string
-	Privileges []PrivilegeType
+	Database string
+	Table    string
+	Column   string
+	Routine  string
+	// IsProcedure is set for operations on a routine, and states whether the routine is a procedure or a function.
+	IsProcedure bool
+	Privileges  []PrivilegeType
 }
 
 // NewPrivilegedOperation returns a new PrivilegedOperation with the given parameters.
@@ -18,6 +21,17 @@ func NewPrivilegedOperation(dbName string, tblName string, colName string, privs
 	}
 }
 
+// NewPrivilegedOperationForRoutine returns a new PrivilegedOperation for the given routine. Routine-level privileges
+// are checked in place of table-level privileges.
+func NewPrivilegedOperationForRoutine(dbName string, routineName string, isProcedure bool, privs ...PrivilegeType) PrivilegedOperation {
+	return PrivilegedOperation{
+		Database:    dbName,
+		Routine:     routineName,
+		IsProcedure: isProcedure,
+		Privileges:  privs,
+	}
+}
+
 // PrivilegedOperationChecker contains the necessary data to check whether the operation should succeed based on the
 // privileges contained by the user. The user is retrieved from the context, along with their active roles.
 type PrivilegedOperationChecker interface {
@@ -51,8 +65,8 @@ type PrivilegeSetDatabase interface {
 	Name() string
 	// Has returns whether the given database privilege(s) exists.
 	Has(privileges ...PrivilegeType) bool
-	// HasPrivileges returns whether this database has either database-level privileges, or privileges on a table or
-	// column contained within this database.
+	// HasPrivileges returns whether this database has either database-level privileges, or privileges on a table,
+	// column, or routine contained within this database.
 	HasPrivileges() bool
 	// Count returns the number of database privileges.
 	Count() int
@@ -60,6 +74,11 @@ type PrivilegeSetDatabase interface {
 	Table(tblName string) PrivilegeSetTable
 	// GetTables returns all tables.
 	GetTables() []PrivilegeSetTable
+	// Routine returns the set of privileges for the given procedure or function. Returns an empty set if the routine
+	// does not exist.
+	Routine(routineName string, isProcedure bool) PrivilegeSetRoutine
+	// GetRoutines returns all routines.
+	GetRoutines() []PrivilegeSetRoutine
 	// Equals returns whether the given set of privileges is equivalent to the calling set.
 	Equals(otherPs PrivilegeSetDatabase) bool
 	// ToSlice returns all of the database privileges contained as a sorted slice.
@@ -101,6 +120,22 @@ type PrivilegeSetColumn interface {
 	ToSlice() []PrivilegeType
 }
 
+// PrivilegeSetRoutine is a set containing routine privileges. Integrators should not implement this interface.
+type PrivilegeSetRoutine interface {
+	// Name returns the name of the routine that this privilege set belongs to.
+	Name() string
+	// IsProcedure returns whether the routine is a procedure, rather than a function.
+	IsProcedure() bool
+	// Has returns whether the given routine privilege(s) exists.
+	Has(privileges ...PrivilegeType) bool
+	// Count returns the number of routine privileges.
+	Count() int
+	// Equals returns whether the given set of privileges is equivalent to the calling set.
+	Equals(otherPs PrivilegeSetRoutine) bool
+	// ToSlice returns all of the routine privileges contained as a sorted slice.
+	ToSlice() []PrivilegeType
+}
+
 // PrivilegeType represents a privilege.
 type PrivilegeType int
 
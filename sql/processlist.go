@@ -56,6 +56,10 @@ type ProcessList interface {
 	// RemovePartitionProgress removes an existing partition tracking progress from the
 	// process with the given pid, if it exists.
 	RemovePartitionProgress(pid uint64, tableName, partitionName string)
+
+	// UpdateState sets what the process with the given pid is waiting for, such as a lock, or clears it if the state
+	// is empty.
+	UpdateState(pid uint64, state string)
 }
 
 // Process represents a process in the SQL server.
@@ -63,15 +67,25 @@ type Process struct {
 	Pid        uint64
 	Connection uint32
 	User       string
+	Host       string
 	Query      string
 	Progress   map[string]TableProgress
 	StartedAt  time.Time
 	Kill       context.CancelFunc
+	// Memory is the memory budget of the process's query, or nil if its memory isn't accounted for.
+	Memory *MemoryBudget
+	// State is what the process is waiting for, or empty if it's running.
+	State string
 }
 
 // Done needs to be called when this process has finished.
 func (p *Process) Done() { p.Kill() }
 
+// MemoryUsed returns the number of bytes used by the caches and buffers of this process's query.
+func (p *Process) MemoryUsed() uint64 {
+	return p.Memory.Used()
+}
+
 // Seconds returns the number of seconds this process has been running.
 func (p *Process) Seconds() uint64 {
 	return uint64(time.Since(p.StartedAt) / time.Second)
@@ -145,3 +159,4 @@ func (e EmptyProcessList) AddPartitionProgress(pid uint64, tableName, partitionN
 }
 func (e EmptyProcessList) RemoveTableProgress(pid uint64, name string)                         {}
 func (e EmptyProcessList) RemovePartitionProgress(pid uint64, tableName, partitionName string) {}
+func (e EmptyProcessList) UpdateState(pid uint64, state string)                                {}
@@ -0,0 +1,229 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+const (
+	profilingSysVarName            = "profiling"
+	profilingHistorySizeSysVarName = "profiling_history_size"
+)
+
+// QueryProfile is the profile of a single statement, as shown by SHOW PROFILES and SHOW PROFILE, and in
+// information_schema.PROFILING.
+type QueryProfile struct {
+	// QueryId identifies the statement among the profiled statements of its session, starting at 1.
+	QueryId int64
+	// Query is the text of the profiled statement.
+	Query string
+	// Stages are the stages of the execution of the statement, in order.
+	Stages []ProfileStage
+}
+
+// Duration returns the time spent executing the profiled statement.
+func (p QueryProfile) Duration() time.Duration {
+	var d time.Duration
+	for _, stage := range p.Stages {
+		d += stage.Duration
+	}
+	return d
+}
+
+// ProfileStage is a stage of the execution of a profiled statement.
+type ProfileStage struct {
+	// State describes what was done during the stage, such as "parsing" or "Sending data".
+	State string
+	// Duration is the wall-clock time spent in the stage.
+	Duration time.Duration
+	// CPUUser and CPUSystem are the user and system CPU time used by the server process during the stage. They are only
+	// recorded on the platforms where HasProcessCPUTime is set.
+	CPUUser   time.Duration
+	CPUSystem time.Duration
+}
+
+// queryProfilerKey is the key of the QueryProfiler of the statement being executed in the context.
+type queryProfilerKey struct{}
+
+// QueryProfiler records the stages of the execution of a statement, when the profiling system variable is enabled.
+// The current stage is ended when the next one starts, or when the profile is finished. As the rows of a statement may
+// be produced by a different goroutine than the one that analyzed it, the profiler is guarded by a mutex.
+type QueryProfiler struct {
+	// query is the query of the context the profile was started with, which is recorded unless the context the profile
+	// is finished with has one, as the caller that closes the statement's iterator may not have set it
+	query      string
+	mu         sync.Mutex
+	stages     []ProfileStage
+	state      string
+	stageStart time.Time
+	cpuUser    time.Duration
+	cpuSystem  time.Duration
+	discarded  bool
+}
+
+// StartQueryProfile starts profiling the statement being executed, if the session enabled profiling and no profile was
+// already started for the statement. Returns the context to use to execute the statement, along with the new
+// profiler, or nil if no profile was started. The first stage of the profile has the state given.
+func StartQueryProfile(ctx *Context, state string) (*Context, *QueryProfiler) {
+	if ctx == nil || ctx.Session == nil || ctx.Context == nil || GetQueryProfiler(ctx) != nil {
+		return ctx, nil
+	}
+	if enabled, err := intSessionVariable(ctx, profilingSysVarName, 0); err != nil || enabled == 0 {
+		return ctx, nil
+	}
+	p := &QueryProfiler{query: ctx.Query()}
+	p.startStage(state, time.Now())
+	return ctx.WithContext(context.WithValue(ctx.Context, queryProfilerKey{}, p)), p
+}
+
+// GetQueryProfiler returns the QueryProfiler of the statement being executed, or nil if it is not being profiled.
+func GetQueryProfiler(ctx *Context) *QueryProfiler {
+	if ctx == nil || ctx.Context == nil {
+		return nil
+	}
+	p, _ := ctx.Value(queryProfilerKey{}).(*QueryProfiler)
+	return p
+}
+
+// SetProfileStage ends the current stage of the statement being executed and starts a new one with the state given,
+// if the statement is being profiled.
+func SetProfileStage(ctx *Context, state string) {
+	if p := GetQueryProfiler(ctx); p != nil {
+		p.SetStage(state)
+	}
+}
+
+// SetStage ends the current stage of the profile and starts a new one with the state given.
+func (p *QueryProfiler) SetStage(state string) {
+	if p == nil {
+		return
+	}
+	now := time.Now()
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.state == state {
+		return
+	}
+	p.endStage(now)
+	p.startStage(state, now)
+}
+
+// Discard marks the profile as one that is not kept once finished. This is used for the statements that show the
+// profiles, so that they don't replace the profiles that they show.
+func (p *QueryProfiler) Discard() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.discarded = true
+}
+
+// FinishQueryProfile ends the last stage of the profile given, and adds it to the profiles of the session. Only the
+// number of profiles given by the profiling_history_size system variable are kept.
+func FinishQueryProfile(ctx *Context, p *QueryProfiler) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.endStage(time.Now())
+	stages, discarded := p.stages, p.discarded
+	p.stages = nil
+	p.mu.Unlock()
+	if discarded || stages == nil {
+		return
+	}
+
+	historySize, err := intSessionVariable(ctx, profilingHistorySizeSysVarName, 15)
+	if err != nil {
+		return
+	}
+	query := ctx.Query()
+	if query == "" {
+		query = p.query
+	}
+	profiles := ctx.Session.GetQueryProfiles()
+	queryId := int64(1)
+	if len(profiles) > 0 {
+		queryId = profiles[len(profiles)-1].QueryId + 1
+	}
+	profiles = append(profiles, QueryProfile{
+		QueryId: queryId,
+		Query:   query,
+		Stages:  stages,
+	})
+	if int64(len(profiles)) > historySize {
+		profiles = profiles[int64(len(profiles))-historySize:]
+	}
+	ctx.Session.SetQueryProfiles(profiles)
+}
+
+func (p *QueryProfiler) startStage(state string, now time.Time) {
+	p.state = state
+	p.stageStart = now
+	p.cpuUser, p.cpuSystem, _ = processCPUTime()
+}
+
+func (p *QueryProfiler) endStage(now time.Time) {
+	if p.state == "" {
+		return
+	}
+	stage := ProfileStage{
+		State:    p.state,
+		Duration: now.Sub(p.stageStart),
+	}
+	if cpuUser, cpuSystem, ok := processCPUTime(); ok {
+		stage.CPUUser = cpuUser - p.cpuUser
+		stage.CPUSystem = cpuSystem - p.cpuSystem
+	}
+	p.stages = append(p.stages, stage)
+	p.state = ""
+}
+
+// NewProfilingIter returns an iterator that starts the "Sending data" stage of the profile given, and starts the
+// "cleaning up" stage once it's closed. If |finish| is set, the profile is finished once the iterator is closed.
+func NewProfilingIter(p *QueryProfiler, iter RowIter, finish bool) RowIter {
+	if p == nil {
+		return iter
+	}
+	p.SetStage("Sending data")
+	return &profilingIter{profiler: p, iter: iter, finish: finish}
+}
+
+type profilingIter struct {
+	profiler *QueryProfiler
+	iter     RowIter
+	finish   bool
+}
+
+var _ RowIter = (*profilingIter)(nil)
+
+// Next implements the RowIter interface.
+func (i *profilingIter) Next(ctx *Context) (Row, error) {
+	return i.iter.Next(ctx)
+}
+
+// Close implements the RowIter interface.
+func (i *profilingIter) Close(ctx *Context) error {
+	i.profiler.SetStage("cleaning up")
+	err := i.iter.Close(ctx)
+	if i.finish {
+		FinishQueryProfile(ctx, i.profiler)
+	}
+	return err
+}
@@ -0,0 +1,302 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"container/list"
+	"sync"
+)
+
+const (
+	// QueryCacheTypeSysVarName is the system variable that enables the query result cache for a session: OFF, ON
+	// for every cacheable SELECT but those with SQL_NO_CACHE, or DEMAND for only those with SQL_CACHE.
+	QueryCacheTypeSysVarName = "query_cache_type"
+	// QueryCacheSizeSysVarName is the system variable holding the largest number of bytes that the results kept by the
+	// query result cache may use. 0 disables the cache.
+	QueryCacheSizeSysVarName = "query_cache_size"
+)
+
+// QueryCache keeps the results of read-only queries, so that a query that is run again can be answered without
+// reading its tables, until any of these tables is written. Results are keyed by a string that the engine builds
+// from the query and everything else its result depends on. The cache holds as many results as fit in the number of
+// bytes it's given when they're added, evicting the least recently used ones first, and is emptied when the memory
+// manager it belongs to frees its caches.
+type QueryCache struct {
+	memory   Freeable
+	reporter Reporter
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// lru orders the entries from the most recently used to the least recently used.
+	lru  *list.List
+	size uint64
+	// version is incremented whenever tables are written or the cache is cleared. versions is the version at which
+	// each table was last written, and cleared the version at which the cache was last cleared. A result is only added
+	// if none of its tables was written, and the cache wasn't cleared, since the query started.
+	version  uint64
+	versions map[MDLKey]uint64
+	cleared  uint64
+	// pending are the tables written by the sessions in explicit transactions, which are invalidated again once
+	// their transactions end.
+	pending map[uint32]map[MDLKey]struct{}
+
+	hits, misses, inserts, notCached, lowmemPrunes uint64
+}
+
+// queryCacheEntry is a result kept by a QueryCache.
+type queryCacheEntry struct {
+	key    string
+	tables []MDLKey
+	rows   []Row
+	size   uint64
+}
+
+var _ Freeable = (*QueryCache)(nil)
+var _ Disposable = (*QueryCache)(nil)
+
+// queryCaches are the query caches in use, whose results InvalidateQueryCaches evicts.
+var queryCaches = struct {
+	sync.Mutex
+	caches map[*QueryCache]struct{}
+}{caches: make(map[*QueryCache]struct{})}
+
+// InvalidateQueryCaches evicts the results that read the table given from every query cache in use. The engine
+// evicts the results of the tables written by the statements it runs, but rows may be written without it, such as by
+// integrators using the editors of a table directly, so tables call this once their written rows are visible.
+func InvalidateQueryCaches(database, table string) {
+	tables := []MDLKey{NewMDLKey(database, table)}
+	queryCaches.Lock()
+	defer queryCaches.Unlock()
+	for c := range queryCaches.caches {
+		c.mu.Lock()
+		c.invalidateLocked(tables)
+		c.mu.Unlock()
+	}
+}
+
+func registerQueryCache(c *QueryCache) {
+	queryCaches.Lock()
+	defer queryCaches.Unlock()
+	queryCaches.caches[c] = struct{}{}
+}
+
+func unregisterQueryCache(c *QueryCache) {
+	queryCaches.Lock()
+	defer queryCaches.Unlock()
+	delete(queryCaches.caches, c)
+}
+
+func newQueryCache(memory Freeable, r Reporter) *QueryCache {
+	return &QueryCache{
+		memory:   memory,
+		reporter: r,
+		entries:  make(map[string]*list.Element),
+		lru:      list.New(),
+		versions: make(map[MDLKey]uint64),
+		pending:  make(map[uint32]map[MDLKey]struct{}),
+	}
+}
+
+// Get returns the result kept for the key given, and whether there is one, counting a hit or a miss.
+func (c *QueryCache) Get(key string) ([]Row, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	elem, ok := c.entries[key]
+	if !ok {
+		c.misses++
+		return nil, false
+	}
+	c.hits++
+	c.lru.MoveToFront(elem)
+	return elem.Value.(*queryCacheEntry).rows, true
+}
+
+// Version returns the current version of the cache, which must be taken before a query starts reading its tables and
+// given to Put along with its result.
+func (c *QueryCache) Version() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.version
+}
+
+// Put keeps the result of a query that read the tables given, and started at the version given, under the key given.
+// Results that don't fit in the limit given, in bytes, are not kept, nor are those of queries that raced with a write
+// to any of their tables. The least recently used results are evicted to make room for the new one. Returns whether
+// the result was kept.
+func (c *QueryCache) Put(key string, tables []MDLKey, version uint64, rows []Row, limit uint64) bool {
+	size := uint64(len(key))
+	for _, row := range rows {
+		size += RowMemorySize(row)
+	}
+	if size > limit || !releaseMemoryIfNeeded(c.reporter, c.Free, c.memory.Free) {
+		c.NotCached()
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.cleared > version {
+		c.notCached++
+		return false
+	}
+	for _, t := range tables {
+		if c.versions[t] > version {
+			c.notCached++
+			return false
+		}
+	}
+	if elem, ok := c.entries[key]; ok {
+		c.removeLocked(elem)
+	}
+	for c.size+size > limit {
+		c.removeLocked(c.lru.Back())
+		c.lowmemPrunes++
+	}
+	c.entries[key] = c.lru.PushFront(&queryCacheEntry{key: key, tables: tables, rows: rows, size: size})
+	c.size += size
+	c.inserts++
+	return true
+}
+
+// NotCached counts a query that wasn't answered from the cache, nor kept in it.
+func (c *QueryCache) NotCached() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.notCached++
+}
+
+// InvalidateTables evicts the results that read any of the tables given, which were written by the session given.
+// If the session is in an explicit transaction, they're evicted again once EndTransaction is called, as the writes
+// may only become visible to other sessions then.
+func (c *QueryCache) InvalidateTables(sessionID uint32, tables []MDLKey, inTransaction bool) {
+	if len(tables) == 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.invalidateLocked(tables)
+	if !inTransaction {
+		return
+	}
+	pending, ok := c.pending[sessionID]
+	if !ok {
+		pending = make(map[MDLKey]struct{})
+		c.pending[sessionID] = pending
+	}
+	for _, t := range tables {
+		pending[t] = struct{}{}
+	}
+}
+
+// EndTransaction evicts the results that read any of the tables written in the explicit transaction of the session
+// given, once it's committed or rolled back.
+func (c *QueryCache) EndTransaction(sessionID uint32) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	pending, ok := c.pending[sessionID]
+	if !ok {
+		return
+	}
+	delete(c.pending, sessionID)
+	tables := make([]MDLKey, 0, len(pending))
+	for t := range pending {
+		tables = append(tables, t)
+	}
+	c.invalidateLocked(tables)
+}
+
+// HasPendingWrites returns whether the session given wrote tables in an explicit transaction that hasn't ended.
+func (c *QueryCache) HasPendingWrites(sessionID uint32) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.pending[sessionID]
+	return ok
+}
+
+// Clear evicts every result, as is needed once the definition of tables or views changes.
+func (c *QueryCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clearLocked()
+}
+
+// Free implements the Freeable interface. The results evicted count as low memory prunes.
+func (c *QueryCache) Free() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.lowmemPrunes += uint64(len(c.entries))
+	c.clearLocked()
+}
+
+// Dispose implements the Disposable interface.
+func (c *QueryCache) Dispose() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.clearLocked()
+	c.memory = nil
+}
+
+// StatusVariables returns the status variables of the cache, named after those of the MySQL query cache.
+// Qcache_misses, which MySQL doesn't have, counts the cacheable queries that had no result in the cache.
+func (c *QueryCache) StatusVariables() map[string]uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return map[string]uint64{
+		"Qcache_hits":             c.hits,
+		"Qcache_inserts":          c.inserts,
+		"Qcache_lowmem_prunes":    c.lowmemPrunes,
+		"Qcache_misses":           c.misses,
+		"Qcache_not_cached":       c.notCached,
+		"Qcache_queries_in_cache": uint64(len(c.entries)),
+	}
+}
+
+// invalidateLocked evicts the results that read any of the tables given, and keeps the results of the queries that
+// are already reading them from being added. It must be called with the mutex held.
+func (c *QueryCache) invalidateLocked(tables []MDLKey) {
+	c.version++
+	written := make(map[MDLKey]struct{}, len(tables))
+	for _, t := range tables {
+		c.versions[t] = c.version
+		written[t] = struct{}{}
+	}
+	for elem := c.lru.Front(); elem != nil; {
+		next := elem.Next()
+		for _, t := range elem.Value.(*queryCacheEntry).tables {
+			if _, ok := written[t]; ok {
+				c.removeLocked(elem)
+				break
+			}
+		}
+		elem = next
+	}
+}
+
+// clearLocked evicts every result. Queries that are already running don't add theirs either. It must be called with
+// the mutex held.
+func (c *QueryCache) clearLocked() {
+	c.version++
+	c.cleared = c.version
+	c.versions = make(map[MDLKey]uint64)
+	c.entries = make(map[string]*list.Element)
+	c.lru.Init()
+	c.size = 0
+}
+
+func (c *QueryCache) removeLocked(elem *list.Element) {
+	entry := c.lru.Remove(elem).(*queryCacheEntry)
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+}
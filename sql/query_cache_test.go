@@ -0,0 +1,139 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryCache(t *testing.T) {
+	t1, t2 := NewMDLKey("mydb", "t1"), NewMDLKey("mydb", "t2")
+	rows := []Row{{int64(1), "foo"}, {int64(2), "bar"}}
+
+	t.Run("basic methods", func(t *testing.T) {
+		require := require.New(t)
+		cache := newQueryCache(mockMemory{}, fixedReporter(5, 50))
+
+		_, ok := cache.Get("q1")
+		require.False(ok)
+		require.True(cache.Put("q1", []MDLKey{t1}, cache.Version(), rows, 1024))
+		v, ok := cache.Get("q1")
+		require.True(ok)
+		require.Equal(rows, v)
+
+		require.True(cache.Put("q2", []MDLKey{t2}, cache.Version(), rows, 1024))
+		cache.InvalidateTables(1, []MDLKey{t1}, false)
+		_, ok = cache.Get("q1")
+		require.False(ok)
+		_, ok = cache.Get("q2")
+		require.True(ok)
+
+		cache.Clear()
+		_, ok = cache.Get("q2")
+		require.False(ok)
+
+		require.Equal(map[string]uint64{
+			"Qcache_hits":             2,
+			"Qcache_inserts":          2,
+			"Qcache_lowmem_prunes":    0,
+			"Qcache_misses":           3,
+			"Qcache_not_cached":       0,
+			"Qcache_queries_in_cache": 0,
+		}, cache.StatusVariables())
+	})
+
+	t.Run("results are bounded in bytes", func(t *testing.T) {
+		require := require.New(t)
+		cache := newQueryCache(mockMemory{}, fixedReporter(5, 50))
+		size := uint64(len("q1")) + RowMemorySize(rows[0]) + RowMemorySize(rows[1])
+
+		require.False(cache.Put("q1", nil, cache.Version(), rows, size-1))
+		require.True(cache.Put("q1", nil, cache.Version(), rows, size))
+		require.True(cache.Put("q2", nil, cache.Version(), rows, 2*size))
+		_, ok := cache.Get("q1")
+		require.True(ok)
+
+		// q2 is the least recently used result, so it's evicted first
+		require.True(cache.Put("q3", nil, cache.Version(), rows, 2*size))
+		_, ok = cache.Get("q2")
+		require.False(ok)
+		_, ok = cache.Get("q1")
+		require.True(ok)
+
+		stats := cache.StatusVariables()
+		require.Equal(uint64(1), stats["Qcache_lowmem_prunes"])
+		require.Equal(uint64(1), stats["Qcache_not_cached"])
+		require.Equal(uint64(2), stats["Qcache_queries_in_cache"])
+	})
+
+	t.Run("no memory available", func(t *testing.T) {
+		require := require.New(t)
+		cache := newQueryCache(mockMemory{}, fixedReporter(51, 50))
+
+		require.False(cache.Put("q1", nil, cache.Version(), rows, 1024))
+		_, ok := cache.Get("q1")
+		require.False(ok)
+	})
+
+	t.Run("results of queries that raced with writes are not kept", func(t *testing.T) {
+		require := require.New(t)
+		cache := newQueryCache(mockMemory{}, fixedReporter(5, 50))
+
+		version := cache.Version()
+		cache.InvalidateTables(1, []MDLKey{t1}, false)
+		require.False(cache.Put("q1", []MDLKey{t1}, version, rows, 1024))
+		require.True(cache.Put("q2", []MDLKey{t2}, version, rows, 1024))
+
+		version = cache.Version()
+		cache.Clear()
+		require.False(cache.Put("q2", []MDLKey{t2}, version, rows, 1024))
+	})
+
+	t.Run("writes in transactions are invalidated again once they end", func(t *testing.T) {
+		require := require.New(t)
+		cache := newQueryCache(mockMemory{}, fixedReporter(5, 50))
+
+		cache.InvalidateTables(1, []MDLKey{t1}, true)
+		require.True(cache.HasPendingWrites(1))
+		require.False(cache.HasPendingWrites(2))
+
+		require.True(cache.Put("q1", []MDLKey{t1}, cache.Version(), rows, 1024))
+		cache.EndTransaction(1)
+		require.False(cache.HasPendingWrites(1))
+		_, ok := cache.Get("q1")
+		require.False(ok)
+	})
+
+	t.Run("tables invalidate every cache in use", func(t *testing.T) {
+		require := require.New(t)
+		m := NewMemoryManager(fixedReporter(5, 50))
+		cache, dispose := m.NewQueryCache()
+
+		require.True(cache.Put("q1", []MDLKey{t1}, cache.Version(), rows, 1024))
+		require.True(cache.Put("q2", []MDLKey{t2}, cache.Version(), rows, 1024))
+		InvalidateQueryCaches("MyDB", "T1")
+		_, ok := cache.Get("q1")
+		require.False(ok)
+		_, ok = cache.Get("q2")
+		require.True(ok)
+
+		dispose()
+		queryCaches.Lock()
+		defer queryCaches.Unlock()
+		require.NotContains(queryCaches.caches, cache)
+	})
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"sync"
 	"time"
@@ -56,6 +57,12 @@ type Client struct {
 	Capabilities uint32
 }
 
+// ActiveRole is a role that is active in a session, which grants its privileges to the session's user.
+type ActiveRole struct {
+	Name string
+	Host string
+}
+
 // Session holds the session data.
 type Session interface {
 	// Address of the server.
@@ -142,6 +149,25 @@ type Session interface {
 	// value of zero will force the cache to reload. This is an internal function and is not intended to be used by
 	// integrators.
 	SetPrivilegeSet(newPs PrivilegeSet, counter uint64)
+	// GetActiveRoles returns the roles that are active in this session, along with whether they were chosen using
+	// SET ROLE. Until SET ROLE is used, every role granted to the user is active. This is an internal function and is
+	// not intended to be used by integrators.
+	GetActiveRoles() ([]ActiveRole, bool)
+	// SetActiveRoles sets the roles that are active in this session, along with whether they were chosen using
+	// SET ROLE. This is an internal function and is not intended to be used by integrators.
+	SetActiveRoles(roles []ActiveRole, explicit bool)
+	// GetOptimizerTraces returns the optimizer traces that were recorded for this session's statements, from the
+	// oldest to the most recent. This is an internal function and is not intended to be used by integrators.
+	GetOptimizerTraces() []OptimizerTrace
+	// SetOptimizerTraces sets the optimizer traces that are recorded for this session's statements. This is an
+	// internal function and is not intended to be used by integrators.
+	SetOptimizerTraces(traces []OptimizerTrace)
+	// GetQueryProfiles returns the profiles that were recorded for this session's statements, from the oldest to the
+	// most recent. This is an internal function and is not intended to be used by integrators.
+	GetQueryProfiles() []QueryProfile
+	// SetQueryProfiles sets the profiles that are recorded for this session's statements. This is an internal function
+	// and is not intended to be used by integrators.
+	SetQueryProfiles(profiles []QueryProfile)
 	// ValidateSession provides integrators a chance to do any custom validation of this session before any query is executed in it. For example, Dolt uses this hook to validate that the session's working set is valid.
 	ValidateSession(ctx *Context, dbName string) error
 	// SetTransactionDatabase is called when a transaction begins, and is set to the name of the database in scope for
@@ -151,6 +177,9 @@ type Session interface {
 	SetTransactionDatabase(dbName string)
 	// GetTransactionDatabase returns the name of the database considered in scope when the current transaction began.
 	GetTransactionDatabase() string
+	// GetMemoryBudget returns the memory budget of the session, which is charged along with the budget of each of its
+	// queries. This is an internal function and is not intended to be used by integrators.
+	GetMemoryBudget() *MemoryBudget
 }
 
 // PersistableSession supports serializing/deserializing global system variables/
@@ -346,10 +375,34 @@ func NewContext(
 	if c.initialDb != "" {
 		c.Session.SetCurrentDatabase(c.initialDb)
 	}
+	if c.Memory.Budget() == nil {
+		c.Memory = c.Memory.WithBudget(newQueryMemoryBudget(c))
+	}
 
 	return c
 }
 
+// newQueryMemoryBudget returns a memory budget for the query of the context given, limited by the query_memory_limit
+// and connection_memory_limit system variables of its session.
+func newQueryMemoryBudget(ctx *Context) *MemoryBudget {
+	session := ctx.Session.GetMemoryBudget()
+	session.SetLimit(memoryLimit(ctx, ConnectionMemoryLimitSysVarName))
+	return NewQueryMemoryBudget(memoryLimit(ctx, QueryMemoryLimitSysVarName), session)
+}
+
+// memoryLimit returns the memory limit given by the system variable given, where 0 means that there is no limit.
+func memoryLimit(ctx *Context, sysVarName string) uint64 {
+	val, err := ctx.GetSessionVariable(ctx, sysVarName)
+	if err != nil {
+		return math.MaxUint64
+	}
+	limit, ok := val.(uint64)
+	if !ok || limit == 0 {
+		return math.MaxUint64
+	}
+	return limit
+}
+
 // ApplyOpts the options given to the context. Mostly for tests, not safe for use after construction of the context.
 func (c *Context) ApplyOpts(opts ...ContextOption) {
 	for _, opt := range opts {
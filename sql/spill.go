@@ -0,0 +1,540 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"io"
+	"math"
+	"os"
+	"time"
+
+	"github.com/shopspring/decimal"
+	errors "gopkg.in/src-d/go-errors.v1"
+)
+
+// ErrSpillFailed is returned when rows cannot be written to, or read from, a temporary file.
+var ErrSpillFailed = errors.NewKind("unable to spill rows to disk: %s")
+
+// spillBufferSize is the size of the buffers used to write and read temporary files.
+const spillBufferSize = 64 * kib
+
+// SpillDir returns the directory in which the temporary files of the components that spill rows to disk are created,
+// which is given by the tmpdir system variable.
+func SpillDir(ctx *Context) string {
+	if SystemVariables != nil {
+		if _, val, ok := SystemVariables.GetGlobal("tmpdir"); ok {
+			if dir, ok := val.(string); ok && dir != "" {
+				return dir
+			}
+		}
+	}
+	return GetTmpdirSessionVar()
+}
+
+// RegisterSpillValueType registers the type of the value given, so that values of that type can be spilled to disk.
+// Most types are written directly, values of other types are only written if their type was registered, through
+// encoding/gob.
+func RegisterSpillValueType(value interface{}) {
+	gob.Register(value)
+}
+
+// SpillFile is a temporary file in which rows are written when there isn't enough memory to keep them, so that they
+// can be read back later. Rows are read from sections of the file, given by offsets returned by Size, so that a single
+// file can hold several sequences of rows.
+type SpillFile struct {
+	file   *os.File
+	writer *bufio.Writer
+	size   int64
+	rows   int
+	buf    []byte
+}
+
+// NewSpillFile creates a new temporary file in the directory given by SpillDir.
+func NewSpillFile(ctx *Context) (*SpillFile, error) {
+	file, err := os.CreateTemp(SpillDir(ctx), "gms-spill-")
+	if err != nil {
+		return nil, ErrSpillFailed.New(err)
+	}
+	return &SpillFile{
+		file:   file,
+		writer: bufio.NewWriterSize(file, spillBufferSize),
+	}, nil
+}
+
+// Write appends the row given to the file.
+func (s *SpillFile) Write(row Row) error {
+	var err error
+	s.buf, err = appendSpillRow(s.buf[:0], row)
+	if err != nil {
+		return err
+	}
+	var header [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(header[:], uint64(len(s.buf)))
+	if _, err = s.writer.Write(header[:n]); err != nil {
+		return ErrSpillFailed.New(err)
+	}
+	if _, err = s.writer.Write(s.buf); err != nil {
+		return ErrSpillFailed.New(err)
+	}
+	s.size += int64(n + len(s.buf))
+	s.rows++
+	return nil
+}
+
+// Size returns the number of bytes written to the file, which is the offset at which the next row is written.
+func (s *SpillFile) Size() int64 {
+	return s.size
+}
+
+// Len returns the number of rows written to the file.
+func (s *SpillFile) Len() int {
+	return s.rows
+}
+
+// Iter returns an iterator over all the rows of the file, in the order in which they were written.
+func (s *SpillFile) Iter() (RowIter, error) {
+	return s.Section(0, s.size)
+}
+
+// Section returns an iterator over the rows written between the offsets given. Several iterators may read the file at
+// the same time, but rows must not be written while they do.
+func (s *SpillFile) Section(start, end int64) (RowIter, error) {
+	if err := s.writer.Flush(); err != nil {
+		return nil, ErrSpillFailed.New(err)
+	}
+	return &spillFileIter{
+		reader: bufio.NewReaderSize(io.NewSectionReader(s.file, start, end-start), spillBufferSize),
+	}, nil
+}
+
+// Close closes and removes the file.
+func (s *SpillFile) Close() error {
+	err := s.file.Close()
+	if rerr := os.Remove(s.file.Name()); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+type spillFileIter struct {
+	reader *bufio.Reader
+	buf    []byte
+}
+
+var _ RowIter = (*spillFileIter)(nil)
+
+// Next implements the RowIter interface.
+func (i *spillFileIter) Next(ctx *Context) (Row, error) {
+	n, err := binary.ReadUvarint(i.reader)
+	if err == io.EOF {
+		return nil, io.EOF
+	} else if err != nil {
+		return nil, ErrSpillFailed.New(err)
+	}
+	if uint64(cap(i.buf)) < n {
+		i.buf = make([]byte, n)
+	}
+	i.buf = i.buf[:n]
+	if _, err = io.ReadFull(i.reader, i.buf); err != nil {
+		return nil, ErrSpillFailed.New(err)
+	}
+	return decodeSpillRow(i.buf)
+}
+
+// Close implements the RowIter interface.
+func (i *spillFileIter) Close(*Context) error {
+	return nil
+}
+
+// SpillableRowsCache is a cache of rows that writes its rows to a temporary file once there is no memory available, or
+// once its rows exceed the memory budget of the query, rather than failing like a RowsCache.
+type SpillableRowsCache interface {
+	// Add a new row to the cache. If there is no memory available, the rows of the cache are written to a temporary
+	// file, as are all the rows added afterwards.
+	Add(Row) error
+	// Spilled returns whether the rows were written to a temporary file.
+	Spilled() bool
+	// Get returns all rows, or nil if they were written to a temporary file.
+	Get() []Row
+	// Iter returns an iterator over all rows, in the order in which they were added.
+	Iter() (RowIter, error)
+	// Len returns the number of rows in the cache.
+	Len() int
+}
+
+// NewSpillableRowsCache returns an empty spillable rows cache, which writes its rows to a temporary file in the
+// directory given, and a function to dispose it when it's no longer needed.
+func (m *MemoryManager) NewSpillableRowsCache(dir string) (SpillableRowsCache, DisposeFunc) {
+	c := &spillableRowsCache{memory: m, dir: dir}
+	pos := m.addCache(c)
+	return c, func() {
+		c.Dispose()
+		m.removeCache(pos)
+	}
+}
+
+// ReleaseIfNeeded frees the memory of the freeable caches when there is no memory available, and reports whether
+// memory is available afterwards. It's used by the components that can write their data to disk to decide when to do
+// so.
+func (m *MemoryManager) ReleaseIfNeeded() bool {
+	return releaseMemoryIfNeeded(m.reporter, m.Free)
+}
+
+type spillableRowsCache struct {
+	memory  *MemoryManager
+	dir     string
+	rows    []Row
+	charged uint64
+	file    *SpillFile
+}
+
+func (c *spillableRowsCache) Add(row Row) error {
+	if c.file == nil {
+		size := RowMemorySize(row)
+		if c.memory.ReleaseIfNeeded() && c.memory.budget.Charge(size) == nil {
+			c.rows = append(c.rows, row)
+			c.charged += size
+			return nil
+		}
+		if err := c.spill(); err != nil {
+			return err
+		}
+	}
+	return c.file.Write(row)
+}
+
+// spill writes the rows of the cache to a new temporary file.
+func (c *spillableRowsCache) spill() error {
+	file, err := os.CreateTemp(c.dir, "gms-spill-")
+	if err != nil {
+		return ErrSpillFailed.New(err)
+	}
+	c.file = &SpillFile{file: file, writer: bufio.NewWriterSize(file, spillBufferSize)}
+	for _, row := range c.rows {
+		if err := c.file.Write(row); err != nil {
+			return err
+		}
+	}
+	c.rows = nil
+	c.release()
+	return nil
+}
+
+// release gives back the memory charged for the rows kept in memory.
+func (c *spillableRowsCache) release() {
+	c.memory.budget.Release(c.charged)
+	c.charged = 0
+}
+
+func (c *spillableRowsCache) Spilled() bool {
+	return c.file != nil
+}
+
+func (c *spillableRowsCache) Get() []Row {
+	return c.rows
+}
+
+func (c *spillableRowsCache) Iter() (RowIter, error) {
+	if c.file != nil {
+		return c.file.Iter()
+	}
+	return RowsToRowIter(c.rows...), nil
+}
+
+func (c *spillableRowsCache) Len() int {
+	if c.file != nil {
+		return c.file.Len()
+	}
+	return len(c.rows)
+}
+
+func (c *spillableRowsCache) Dispose() {
+	c.rows = nil
+	c.release()
+	if c.file != nil {
+		_ = c.file.Close()
+		c.file = nil
+	}
+}
+
+// SpillPartitions is a set of temporary files among which rows are distributed by a hash, so that the rows with the
+// same hash can be processed together once they no longer fit in memory.
+type SpillPartitions struct {
+	files []*SpillFile
+}
+
+// NewSpillPartitions creates the number of partitions given, each in a temporary file created lazily.
+func NewSpillPartitions(count int) *SpillPartitions {
+	return &SpillPartitions{files: make([]*SpillFile, count)}
+}
+
+// Len returns the number of partitions.
+func (p *SpillPartitions) Len() int {
+	return len(p.files)
+}
+
+// Partition returns the partition of the rows with the hash given.
+func (p *SpillPartitions) Partition(hash uint64) int {
+	return int(hash % uint64(len(p.files)))
+}
+
+// Write writes the row given to the partition of the hash given.
+func (p *SpillPartitions) Write(ctx *Context, hash uint64, row Row) error {
+	i := p.Partition(hash)
+	if p.files[i] == nil {
+		var err error
+		if p.files[i], err = NewSpillFile(ctx); err != nil {
+			return err
+		}
+	}
+	return p.files[i].Write(row)
+}
+
+// Iter returns an iterator over the rows of the partition given.
+func (p *SpillPartitions) Iter(partition int) (RowIter, error) {
+	if p.files[partition] == nil {
+		return RowsToRowIter(), nil
+	}
+	return p.files[partition].Iter()
+}
+
+// Close closes and removes the files of the partitions.
+func (p *SpillPartitions) Close() error {
+	var err error
+	for i, file := range p.files {
+		if file == nil {
+			continue
+		}
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+		p.files[i] = nil
+	}
+	return err
+}
+
+// The tags of the values of spilled rows.
+const (
+	spillTagNull byte = iota
+	spillTagInt
+	spillTagInt8
+	spillTagInt16
+	spillTagInt32
+	spillTagInt64
+	spillTagUint
+	spillTagUint8
+	spillTagUint16
+	spillTagUint32
+	spillTagUint64
+	spillTagFloat32
+	spillTagFloat64
+	spillTagFalse
+	spillTagTrue
+	spillTagString
+	spillTagBytes
+	spillTagTime
+	spillTagDecimal
+	spillTagGob
+)
+
+// spillGobValue holds the values written through encoding/gob, whose types must be registered with
+// RegisterSpillValueType.
+type spillGobValue struct {
+	Value interface{}
+}
+
+// appendSpillRow appends the encoding of the row given to the buffer given.
+func appendSpillRow(buf []byte, row Row) ([]byte, error) {
+	buf = binary.AppendUvarint(buf, uint64(len(row)))
+	for _, v := range row {
+		switch v := v.(type) {
+		case nil:
+			buf = append(buf, spillTagNull)
+		case int:
+			buf = binary.AppendVarint(append(buf, spillTagInt), int64(v))
+		case int8:
+			buf = binary.AppendVarint(append(buf, spillTagInt8), int64(v))
+		case int16:
+			buf = binary.AppendVarint(append(buf, spillTagInt16), int64(v))
+		case int32:
+			buf = binary.AppendVarint(append(buf, spillTagInt32), int64(v))
+		case int64:
+			buf = binary.AppendVarint(append(buf, spillTagInt64), v)
+		case uint:
+			buf = binary.AppendUvarint(append(buf, spillTagUint), uint64(v))
+		case uint8:
+			buf = binary.AppendUvarint(append(buf, spillTagUint8), uint64(v))
+		case uint16:
+			buf = binary.AppendUvarint(append(buf, spillTagUint16), uint64(v))
+		case uint32:
+			buf = binary.AppendUvarint(append(buf, spillTagUint32), uint64(v))
+		case uint64:
+			buf = binary.AppendUvarint(append(buf, spillTagUint64), v)
+		case float32:
+			buf = binary.LittleEndian.AppendUint32(append(buf, spillTagFloat32), math.Float32bits(v))
+		case float64:
+			buf = binary.LittleEndian.AppendUint64(append(buf, spillTagFloat64), math.Float64bits(v))
+		case bool:
+			if v {
+				buf = append(buf, spillTagTrue)
+			} else {
+				buf = append(buf, spillTagFalse)
+			}
+		case string:
+			buf = appendSpillBytes(append(buf, spillTagString), []byte(v))
+		case []byte:
+			buf = appendSpillBytes(append(buf, spillTagBytes), v)
+		case time.Time:
+			data, err := v.MarshalBinary()
+			if err != nil {
+				return nil, ErrSpillFailed.New(err)
+			}
+			buf = appendSpillBytes(append(buf, spillTagTime), data)
+		case decimal.Decimal:
+			data, err := v.MarshalBinary()
+			if err != nil {
+				return nil, ErrSpillFailed.New(err)
+			}
+			buf = appendSpillBytes(append(buf, spillTagDecimal), data)
+		default:
+			var data bytes.Buffer
+			if err := gob.NewEncoder(&data).Encode(&spillGobValue{Value: v}); err != nil {
+				return nil, ErrSpillFailed.New(err)
+			}
+			buf = appendSpillBytes(append(buf, spillTagGob), data.Bytes())
+		}
+	}
+	return buf, nil
+}
+
+func appendSpillBytes(buf []byte, data []byte) []byte {
+	buf = binary.AppendUvarint(buf, uint64(len(data)))
+	return append(buf, data...)
+}
+
+// decodeSpillRow decodes a row encoded by appendSpillRow.
+func decodeSpillRow(data []byte) (Row, error) {
+	d := spillDecoder{data: data}
+	row := make(Row, d.uvarint())
+	for i := range row {
+		switch tag := d.byte(); tag {
+		case spillTagNull:
+		case spillTagInt:
+			row[i] = int(d.varint())
+		case spillTagInt8:
+			row[i] = int8(d.varint())
+		case spillTagInt16:
+			row[i] = int16(d.varint())
+		case spillTagInt32:
+			row[i] = int32(d.varint())
+		case spillTagInt64:
+			row[i] = d.varint()
+		case spillTagUint:
+			row[i] = uint(d.uvarint())
+		case spillTagUint8:
+			row[i] = uint8(d.uvarint())
+		case spillTagUint16:
+			row[i] = uint16(d.uvarint())
+		case spillTagUint32:
+			row[i] = uint32(d.uvarint())
+		case spillTagUint64:
+			row[i] = d.uvarint()
+		case spillTagFloat32:
+			row[i] = math.Float32frombits(binary.LittleEndian.Uint32(d.next(4)))
+		case spillTagFloat64:
+			row[i] = math.Float64frombits(binary.LittleEndian.Uint64(d.next(8)))
+		case spillTagFalse:
+			row[i] = false
+		case spillTagTrue:
+			row[i] = true
+		case spillTagString:
+			row[i] = string(d.bytes())
+		case spillTagBytes:
+			row[i] = append([]byte{}, d.bytes()...)
+		case spillTagTime:
+			var t time.Time
+			if err := t.UnmarshalBinary(d.bytes()); err != nil {
+				return nil, ErrSpillFailed.New(err)
+			}
+			row[i] = t
+		case spillTagDecimal:
+			var dec decimal.Decimal
+			if err := dec.UnmarshalBinary(d.bytes()); err != nil {
+				return nil, ErrSpillFailed.New(err)
+			}
+			row[i] = dec
+		case spillTagGob:
+			var value spillGobValue
+			if err := gob.NewDecoder(bytes.NewReader(d.bytes())).Decode(&value); err != nil {
+				return nil, ErrSpillFailed.New(err)
+			}
+			row[i] = value.Value
+		default:
+			return nil, ErrSpillFailed.Wrap(io.ErrUnexpectedEOF)
+		}
+		if d.err != nil {
+			return nil, ErrSpillFailed.New(d.err)
+		}
+	}
+	return row, nil
+}
+
+// spillDecoder reads the values of an encoded row, recording the first error encountered.
+type spillDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *spillDecoder) next(n int) []byte {
+	if d.err != nil || len(d.data) < n {
+		d.err = io.ErrUnexpectedEOF
+		return make([]byte, n)
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+func (d *spillDecoder) byte() byte {
+	return d.next(1)[0]
+}
+
+func (d *spillDecoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *spillDecoder) varint() int64 {
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *spillDecoder) bytes() []byte {
+	return d.next(int(d.uvarint()))
+}
@@ -0,0 +1,117 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+import (
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+type spillTestValue struct {
+	A string
+	B []int
+}
+
+func init() {
+	RegisterSpillValueType(spillTestValue{})
+}
+
+func TestSpillFile(t *testing.T) {
+	require := require.New(t)
+	ctx := NewEmptyContext()
+
+	rows := []Row{
+		{nil, 1, int8(-2), int16(3), int32(-4), int64(5), uint(6), uint8(7), uint16(8), uint32(9), uint64(10)},
+		{float32(1.5), 2.25, true, false, "", "text", []byte{0, 1}, []byte{}},
+		{time.Date(2023, 3, 1, 12, 30, 0, 500, time.UTC), decimal.RequireFromString("-123.4500")},
+		{spillTestValue{A: "a", B: []int{1, 2}}},
+		{},
+	}
+
+	file, err := NewSpillFile(ctx)
+	require.NoError(err)
+	for _, row := range rows {
+		require.NoError(file.Write(row))
+	}
+	middle := file.Size()
+	require.NoError(file.Write(Row{"last"}))
+	require.Equal(len(rows)+1, file.Len())
+
+	iter, err := file.Section(0, middle)
+	require.NoError(err)
+	actual, err := RowIterToRows(ctx, nil, iter)
+	require.NoError(err)
+	require.Len(actual, len(rows))
+	for i := range rows {
+		require.Len(actual[i], len(rows[i]))
+		for j := range rows[i] {
+			if d, ok := rows[i][j].(decimal.Decimal); ok {
+				require.True(d.Equal(actual[i][j].(decimal.Decimal)))
+			} else {
+				require.Equal(rows[i][j], actual[i][j])
+			}
+		}
+	}
+
+	iter, err = file.Section(middle, file.Size())
+	require.NoError(err)
+	actual, err = RowIterToRows(ctx, nil, iter)
+	require.NoError(err)
+	require.Equal([]Row{{"last"}}, actual)
+
+	name := file.file.Name()
+	require.NoError(file.Close())
+	_, err = os.Stat(name)
+	require.True(os.IsNotExist(err))
+}
+
+func TestSpillableRowsCache(t *testing.T) {
+	require := require.New(t)
+	ctx := NewEmptyContext()
+
+	reporter := &mockReporter{max: 10, f: func() uint64 { return 5 }}
+	m := NewMemoryManager(reporter)
+	cache, dispose := m.NewSpillableRowsCache(SpillDir(ctx))
+	defer dispose()
+
+	require.NoError(cache.Add(Row{1}))
+	require.NoError(cache.Add(Row{2}))
+	require.False(cache.Spilled())
+	require.Equal([]Row{{1}, {2}}, cache.Get())
+
+	reporter.f = func() uint64 { return 20 }
+	require.NoError(cache.Add(Row{3}))
+	require.True(cache.Spilled())
+	require.Nil(cache.Get())
+	require.Equal(3, cache.Len())
+
+	iter, err := cache.Iter()
+	require.NoError(err)
+	var rows []Row
+	for {
+		row, err := iter.Next(ctx)
+		if err == io.EOF {
+			break
+		}
+		require.NoError(err)
+		rows = append(rows, row)
+	}
+	require.Equal([]Row{{1}, {2}, {3}}, rows)
+}
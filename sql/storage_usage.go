@@ -0,0 +1,73 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package sql
+
+// StorageUsageProvider is a DatabaseProvider that reports the tablespaces and files in which it stores its data, as
+// shown in information_schema.TABLESPACES and information_schema.FILES. Both tables are empty for the providers that
+// don't implement it.
+type StorageUsageProvider interface {
+	// Tablespaces returns the tablespaces in which the data is stored.
+	Tablespaces(ctx *Context) ([]Tablespace, error)
+	// DataFiles returns the files in which the data is stored.
+	DataFiles(ctx *Context) ([]DataFile, error)
+}
+
+// Tablespace is a tablespace in which data is stored. The sizes are given in bytes.
+type Tablespace struct {
+	// Name is the name of the tablespace.
+	Name string
+	// Engine is the storage engine of the tablespace, such as "InnoDB".
+	Engine string
+	// Type is the type of the tablespace, such as "General", or empty if it's not known.
+	Type string
+	// ExtentSize is the size of the extents of the tablespace.
+	ExtentSize uint64
+	// AutoextendSize is the amount by which the tablespace grows when it's full.
+	AutoextendSize uint64
+	// MaximumSize is the largest size of the tablespace, or 0 if it's unlimited.
+	MaximumSize uint64
+	// Comment is the comment of the tablespace.
+	Comment string
+}
+
+// DataFile is a file in which data is stored. The sizes are given in bytes.
+type DataFile struct {
+	// Id identifies the file among the files of the provider.
+	Id int64
+	// Name is the name of the file, usually its path.
+	Name string
+	// Type is the type of the file, such as "TABLESPACE", "TEMPORARY" or "UNDO LOG".
+	Type string
+	// TablespaceName is the name of the tablespace that the file belongs to.
+	TablespaceName string
+	// Engine is the storage engine of the file, such as "InnoDB".
+	Engine string
+	// ExtentSize is the size of the extents of the file.
+	ExtentSize int64
+	// TotalExtents is the number of extents of the file.
+	TotalExtents int64
+	// FreeExtents is the number of extents of the file that are not used.
+	FreeExtents int64
+	// InitialSize is the size of the file when it was created.
+	InitialSize int64
+	// MaximumSize is the largest size of the file, or 0 if it's unlimited.
+	MaximumSize int64
+	// AutoextendSize is the amount by which the file grows when it's full.
+	AutoextendSize int64
+	// DataFree is the amount of free space in the file.
+	DataFree int64
+	// Status is the status of the file, such as "NORMAL".
+	Status string
+}
@@ -166,6 +166,30 @@ type CheckAlterableTable interface {
 	DropCheck(ctx *Context, chName string) error
 }
 
+// PartitionedTable is a table whose rows are divided among user-defined partitions, as declared with a PARTITION BY
+// clause. These partitions are unrelated to the partitions returned by Table.Partitions, which are units of work.
+type PartitionedTable interface {
+	Table
+	// PartitionScheme returns the user-defined partitioning of this table, or nil if the table is not partitioned.
+	PartitionScheme(ctx *Context) (*PartitionScheme, error)
+	// WithSelectedPartitions returns a copy of this table that only returns rows from the partitions named, and only
+	// accepts new rows that belong to one of them. Returns ErrUnknownPartition if a partition doesn't exist.
+	WithSelectedPartitions(names []string) (Table, error)
+	// SelectedPartitions returns the partitions selected by WithSelectedPartitions, or nil if all partitions are used.
+	SelectedPartitions() []string
+}
+
+// PartitionAlterableTable is a table whose user-defined partitioning can be changed.
+type PartitionAlterableTable interface {
+	PartitionedTable
+	// SetPartitionScheme assigns every row of the table to a partition of the scheme given, which replaces the current
+	// partitioning. A nil scheme removes the partitioning. Returns ErrNoPartitionForValue if a row does not belong to
+	// any partition, in which case the table is left unchanged.
+	SetPartitionScheme(ctx *Context, scheme *PartitionScheme) error
+	// TruncatePartitions removes all rows from the partitions named, returning the number of rows that were removed.
+	TruncatePartitions(ctx *Context, names []string) (int, error)
+}
+
 // PrimaryKeyTable is a table with a primary key.
 type PrimaryKeyTable interface {
 	// PrimaryKeySchema returns this table's PrimaryKeySchema
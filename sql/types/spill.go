@@ -0,0 +1,32 @@
+// Copyright 2023 Dolthub, Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package types
+
+import "github.com/dolthub/go-mysql-server/sql"
+
+// The values of these types may be written to disk by the sorts, groupings and joins that run out of memory.
+func init() {
+	sql.RegisterSpillValueType(JSONDocument{})
+	sql.RegisterSpillValueType(map[string]interface{}{})
+	sql.RegisterSpillValueType([]interface{}{})
+	sql.RegisterSpillValueType(Point{})
+	sql.RegisterSpillValueType(LineString{})
+	sql.RegisterSpillValueType(Polygon{})
+	sql.RegisterSpillValueType(MultiPoint{})
+	sql.RegisterSpillValueType(MultiLineString{})
+	sql.RegisterSpillValueType(MultiPolygon{})
+	sql.RegisterSpillValueType(GeomColl{})
+	sql.RegisterSpillValueType(Timespan(0))
+}
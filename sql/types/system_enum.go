@@ -116,6 +116,11 @@ func (t systemEnumType) Convert(v interface{}) (interface{}, error) {
 		if idx, ok := t.valToIndex[strings.ToLower(value)]; ok {
 			return t.indexToVal[idx], nil
 		}
+	case bool:
+		// ON and OFF are parsed as booleans, which name the values of enums that have them
+		if idx, ok := t.valToIndex[map[bool]string{true: "on", false: "off"}[value]]; ok {
+			return t.indexToVal[idx], nil
+		}
 	}
 
 	return nil, sql.ErrInvalidSystemVariableValue.New(t.varName, v)
@@ -554,6 +554,14 @@ var systemVars = map[string]sql.SystemVariable{
 		Type:              types.NewSystemIntType("connect_timeout", 2, 31536000, false),
 		Default:           int64(10),
 	},
+	"connection_memory_limit": {
+		Name:              "connection_memory_limit",
+		Scope:             sql.SystemVariableScope_Both,
+		Dynamic:           true,
+		SetVarHintApplies: false,
+		Type:              types.NewSystemUintType("connection_memory_limit", 2097152, 18446744073709551615),
+		Default:           uint64(18446744073709551615),
+	},
 	"core_file": {
 		Name:              "core_file",
 		Scope:             sql.SystemVariableScope_Global,
@@ -1359,7 +1367,7 @@ var systemVars = map[string]sql.SystemVariable{
 		Scope:             sql.SystemVariableScope_Both,
 		Dynamic:           true,
 		SetVarHintApplies: true,
-		Type:              types.NewSystemIntType("max_execution_time", -9223372036854775808, 9223372036854775807, false),
+		Type:              types.NewSystemIntType("max_execution_time", 0, 4294967295, false),
 		Default:           int64(0),
 	},
 	"max_heap_table_size": {
@@ -1835,13 +1843,29 @@ var systemVars = map[string]sql.SystemVariable{
 		Type:              types.NewSystemBoolType("print_identified_with_as_hex"),
 		Default:           int8(0),
 	},
+	"profiling": {
+		Name:              "profiling",
+		Scope:             sql.SystemVariableScope_Both,
+		Dynamic:           true,
+		SetVarHintApplies: false,
+		Type:              types.NewSystemBoolType("profiling"),
+		Default:           int8(0),
+	},
+	"profiling_history_size": {
+		Name:              "profiling_history_size",
+		Scope:             sql.SystemVariableScope_Both,
+		Dynamic:           true,
+		SetVarHintApplies: false,
+		Type:              types.NewSystemIntType("profiling_history_size", 0, 100, false),
+		Default:           int64(15),
+	},
 	"protocol_compression_algorithms": {
 		Name:              "protocol_compression_algorithms",
 		Scope:             sql.SystemVariableScope_Global,
-		Dynamic:           true,
+		Dynamic:           false,
 		SetVarHintApplies: false,
 		Type:              types.NewSystemSetType("protocol_compression_algorithms", "zlib", "zstd", "uncompressed"),
-		Default:           "zlib,zstd,uncompressed",
+		Default:           "uncompressed",
 	},
 	"protocol_version": {
 		Name:              "protocol_version",
@@ -1890,7 +1914,7 @@ var systemVars = map[string]sql.SystemVariable{
 		Dynamic:           true,
 		SetVarHintApplies: false,
 		Type:              types.NewSystemUintType("query_cache_size", 0, 18446744073709551615),
-		Default:           int8(1),
+		Default:           uint64(1048576),
 	},
 	"query_cache_type": {
 		Name:              "query_cache_type",
@@ -1900,6 +1924,16 @@ var systemVars = map[string]sql.SystemVariable{
 		Type:              types.NewSystemEnumType("query_cache_type", "OFF", "ON", "DEMAND"),
 		Default:           "OFF",
 	},
+	// query_memory_limit is not a MySQL variable. It limits the memory used by the caches and buffers of a single query,
+	// in bytes, and 0 means that there is no limit.
+	"query_memory_limit": {
+		Name:              "query_memory_limit",
+		Scope:             sql.SystemVariableScope_Both,
+		Dynamic:           true,
+		SetVarHintApplies: true,
+		Type:              types.NewSystemUintType("query_memory_limit", 0, 18446744073709551615),
+		Default:           uint64(0),
+	},
 	// "query_prealloc_size": {
 	//	Name: "query_prealloc_size",
 	//	Scope: SystemVariableScope_Both,
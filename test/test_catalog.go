@@ -121,7 +121,7 @@ func (c *Catalog) Function(ctx *sql.Context, name string) (sql.Function, error)
 	return nil, sql.ErrFunctionNotFound.New(name)
 }
 
-func (c *Catalog) LockTable(ctx *sql.Context, table string) {}
+func (c *Catalog) LockTable(ctx *sql.Context, database, table string) {}
 
 func (c *Catalog) UnlockTables(ctx *sql.Context, id uint32) error {
 	return nil